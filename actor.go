@@ -0,0 +1,82 @@
+package atmos
+
+import "github.com/cumulusrpg/atmos/types"
+
+// ActorPolicy decides whether an actor may emit an event.
+// The actor is the player or session ID supplied to EmitAs ("" when unknown).
+type ActorPolicy func(engine *Engine, actor string, event Event) bool
+
+// PolicyValidator adapts an ActorPolicy into an EventValidator so that
+// permission rules run alongside (and can be explained like) any other validator
+type PolicyValidator struct {
+	Name   string // Documents the rule, e.g. "only the current player may move"
+	Policy ActorPolicy
+}
+
+// Validate implements EventValidator using the actor of the emit in progress
+func (v *PolicyValidator) Validate(engine types.Engine, event Event) bool {
+	concreteEngine := engine.(*Engine)
+	return v.Policy(concreteEngine, concreteEngine.Actor(), event)
+}
+
+func (v *PolicyValidator) validatorName() string {
+	return "policy: " + v.Name
+}
+
+// EmitAs emits an event on behalf of an actor (player or session ID).
+// The actor is visible to validators, hooks, and listeners via Actor() for
+// the duration of the emit, including events emitted by those listeners.
+func (e *Engine) EmitAs(actor string, event Event) bool {
+	return e.EmitAsWithResult(actor, event).Accepted
+}
+
+// EmitAsWithResult is EmitWithResult on behalf of an actor (see EmitAs)
+func (e *Engine) EmitAsWithResult(actor string, event Event) EmitResult {
+	previous := e.actor
+	e.actor = actor
+	defer func() { e.actor = previous }()
+
+	return e.EmitWithResult(event)
+}
+
+// ExplainAs explains an event as if it were emitted by the given actor
+func (e *Engine) ExplainAs(actor string, event Event) Explanation {
+	previous := e.actor
+	e.actor = actor
+	defer func() { e.actor = previous }()
+
+	return e.Explain(event)
+}
+
+// Actor returns the actor of the emit currently being processed,
+// or "" outside of EmitAs
+func (e *Engine) Actor() string {
+	return e.actor
+}
+
+// AllowedBy registers actor policies for this event (chainable).
+// Each policy is registered as a named validator, so it can be targeted by
+// Except() and shows up in Explain() reports.
+// Usage: When("move_made").AllowedBy("only the current player may move", IsCurrentPlayer)
+func (r *EventRegistration) AllowedBy(name string, policy ActorPolicy) *EventRegistration {
+	return r.WithValidator(&PolicyValidator{Name: name, Policy: policy})
+}
+
+// ActorIn builds a policy that only admits the listed actors
+func ActorIn(actors ...string) ActorPolicy {
+	allowed := make(map[string]bool, len(actors))
+	for _, actor := range actors {
+		allowed[actor] = true
+	}
+	return func(engine *Engine, actor string, event Event) bool {
+		return allowed[actor]
+	}
+}
+
+// ActorMatches builds a policy that admits the actor returned by selector,
+// typically read from state (e.g. the current player of a turn-based game)
+func ActorMatches(selector func(engine *Engine, event Event) string) ActorPolicy {
+	return func(engine *Engine, actor string, event Event) bool {
+		return actor != "" && actor == selector(engine, event)
+	}
+}
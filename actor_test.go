@@ -0,0 +1,70 @@
+package atmos
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+type TurnState struct {
+	CurrentPlayer string
+}
+
+type MoveEvent struct {
+	Player string
+}
+
+func (e MoveEvent) Type() string { return "move" }
+
+// TestEmitAsEnforcesActorPolicy demonstrates expressing "only the current player may move" once
+func TestEmitAsEnforcesActorPolicy(t *testing.T) {
+	engine := NewEngine()
+	engine.RegisterState("turn", TurnState{CurrentPlayer: "alice"})
+
+	isCurrentPlayer := ActorMatches(func(e *Engine, event Event) string {
+		return e.GetState("turn").(TurnState).CurrentPlayer
+	})
+
+	engine.When("move").
+		AllowedBy("only the current player may move", isCurrentPlayer).
+		Updates("turn", func(e *Engine, state interface{}, event Event) interface{} {
+			if event.(MoveEvent).Player == "alice" {
+				return TurnState{CurrentPlayer: "bob"}
+			}
+			return TurnState{CurrentPlayer: "alice"}
+		})
+
+	assert.False(t, engine.EmitAs("bob", MoveEvent{Player: "bob"}), "Bob must wait for their turn")
+	assert.False(t, engine.Emit(MoveEvent{Player: "alice"}), "Anonymous emits have no actor")
+	assert.True(t, engine.EmitAs("alice", MoveEvent{Player: "alice"}))
+	assert.True(t, engine.EmitAs("bob", MoveEvent{Player: "bob"}))
+
+	explanation := engine.ExplainAs("bob", MoveEvent{Player: "bob"})
+	assert.False(t, explanation.Accepted)
+	assert.Equal(t, "policy: only the current player may move", explanation.FirstFailure.Name)
+
+	// Actor is only set for the duration of the emit
+	assert.Equal(t, "", engine.Actor())
+}
+
+// TestActorVisibleToDerivedEmits verifies listeners see (and inherit) the actor
+func TestActorVisibleToDerivedEmits(t *testing.T) {
+	engine := NewEngine()
+
+	var seen []string
+	engine.When("order_placed").Then(NewTypedListener(
+		TypedListenerFunc[OrderPlacedEvent](func(e *Engine, event OrderPlacedEvent) {
+			seen = append(seen, e.Actor())
+			e.Emit(InvoiceGeneratedEvent{OrderID: event.OrderID})
+		}),
+	))
+	engine.When("invoice_generated").
+		AllowedBy("only staff", ActorIn("staff-1", "staff-2"))
+
+	assert.True(t, engine.EmitAs("staff-1", OrderPlacedEvent{OrderID: "ORD-1"}))
+	assert.Equal(t, []string{"staff-1"}, seen)
+	assert.Len(t, engine.GetEvents(), 2, "Derived invoice inherits the staff actor")
+
+	assert.True(t, engine.EmitAs("customer", OrderPlacedEvent{OrderID: "ORD-2"}))
+	assert.Len(t, engine.GetEvents(), 3, "Derived invoice rejected for non-staff actor")
+}
@@ -0,0 +1,150 @@
+// Package agent plugs bots and AI opponents into any atmos game. An Agent
+// sees the game through a StateView (masked for its player, see
+// atmos.Engine.GetStateFor) and proposes an event; a Driver asks whichever
+// agent's turn it is and emits the decision on its behalf:
+//
+//	driver := agent.NewDriver(engine, CurrentPlayer, agent.WithSeed(42))
+//	driver.Register("bot", agent.Func(func(view agent.StateView) (atmos.Event, bool) {
+//		moves := LegalMoves(view.State("board"))
+//		return moves[view.Rand.Intn(len(moves))], true
+//	}))
+//	driver.Run(100) // play bot turns until a human is up
+//
+// Decisions are deterministic for a given seed and log, and are recorded as
+// ordinary events, so replays never consult the agents again.
+package agent
+
+import (
+	"errors"
+	"fmt"
+	"hash/fnv"
+	"math/rand"
+
+	"github.com/cumulusrpg/atmos"
+)
+
+// ErrRejected is returned when an agent proposes an event the engine rejects
+var ErrRejected = errors.New("agent decision rejected")
+
+// Agent decides what a player does next
+type Agent interface {
+	// DecideEvent returns the event to emit, or false to pass
+	DecideEvent(view StateView) (atmos.Event, bool)
+}
+
+// Func adapts a function to the Agent interface
+type Func func(view StateView) (atmos.Event, bool)
+
+// DecideEvent implements Agent
+func (f Func) DecideEvent(view StateView) (atmos.Event, bool) {
+	return f(view)
+}
+
+// StateView is what an agent may see when deciding
+type StateView struct {
+	Player string
+	Rand   *rand.Rand // Seeded from the driver seed, player, and log length
+	engine *atmos.Engine
+}
+
+// State returns a state as the player may see it
+func (v StateView) State(name string) interface{} {
+	return v.engine.GetStateFor(v.Player, name)
+}
+
+// Events returns the event log as the player may see it
+func (v StateView) Events() []atmos.Event {
+	return v.engine.GetEventsFor(v.Player)
+}
+
+// Legal reports whether the engine would accept an event from the player,
+// so agents can filter candidate moves
+func (v StateView) Legal(event atmos.Event) bool {
+	return v.engine.ExplainAs(v.Player, event).Accepted
+}
+
+// Driver invokes registered agents when it is their turn
+type Driver struct {
+	engine *atmos.Engine
+	turn   func(engine *atmos.Engine) string
+	agents map[string]Agent
+	seed   int64
+}
+
+// Option configures driver construction
+type Option func(*Driver)
+
+// WithSeed sets the seed agents' random sources derive from (default 0)
+func WithSeed(seed int64) Option {
+	return func(d *Driver) {
+		d.seed = seed
+	}
+}
+
+// NewDriver creates a driver; turn returns the player to act next ("" when nobody is)
+func NewDriver(engine *atmos.Engine, turn func(engine *atmos.Engine) string, opts ...Option) *Driver {
+	d := &Driver{
+		engine: engine,
+		turn:   turn,
+		agents: make(map[string]Agent),
+	}
+
+	// Apply options
+	for _, opt := range opts {
+		opt(d)
+	}
+
+	return d
+}
+
+// Register makes an agent play for a player
+func (d *Driver) Register(player string, agent Agent) {
+	d.agents[player] = agent
+}
+
+// Step lets the agent whose turn it is act once. It reports whether an
+// event was emitted: false when nobody is up, the player has no agent, or
+// the agent passed. A rejected decision returns ErrRejected.
+func (d *Driver) Step() (bool, error) {
+	player := d.turn(d.engine)
+	agent, exists := d.agents[player]
+	if player == "" || !exists {
+		return false, nil
+	}
+
+	view := StateView{Player: player, Rand: d.rand(player), engine: d.engine}
+	event, ok := agent.DecideEvent(view)
+	if !ok {
+		return false, nil
+	}
+
+	result := d.engine.EmitAsWithResult(player, event)
+	switch {
+	case result.Err != nil:
+		return false, result.Err
+	case !result.Accepted:
+		return false, fmt.Errorf("%w: %s proposed %s, rejected by %s: %s",
+			ErrRejected, player, event.Type(), result.RejectedBy, result.Reason)
+	}
+	return true, nil
+}
+
+// Run steps until no agent acts or maxSteps events have been emitted,
+// returning the number emitted
+func (d *Driver) Run(maxSteps int) (int, error) {
+	for steps := 0; steps < maxSteps; steps++ {
+		emitted, err := d.Step()
+		if err != nil || !emitted {
+			return steps, err
+		}
+	}
+	return maxSteps, nil
+}
+
+// rand returns the random source for a decision, derived from the seed,
+// the player, and the log length so replaying a log reproduces decisions
+func (d *Driver) rand(player string) *rand.Rand {
+	h := fnv.New64a()
+	fmt.Fprintf(h, "%d/%s/%d", d.seed, player, len(d.engine.GetEvents()))
+	return rand.New(rand.NewSource(int64(h.Sum64())))
+}
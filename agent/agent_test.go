@@ -0,0 +1,161 @@
+package agent_test
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/cumulusrpg/atmos"
+	"github.com/cumulusrpg/atmos/agent"
+	"github.com/cumulusrpg/atmos/types"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// TakeEvent removes stones from the pile
+type TakeEvent struct {
+	Player string
+	Count  int
+}
+
+func (e TakeEvent) Type() string { return "take" }
+
+// legalTake allows taking 1-3 stones, but no more than remain
+type legalTake struct{}
+
+func (legalTake) Validate(engine types.Engine, event types.Event) bool {
+	take := event.(TakeEvent)
+	return take.Count >= 1 && take.Count <= 3 && take.Count <= engine.GetState("pile").(int)
+}
+
+// pile is a game of Nim: players alternate taking 1-3 stones from a pile
+func pile(stones int) *atmos.Engine {
+	engine := atmos.NewEngine()
+	engine.RegisterState("pile", stones)
+	engine.When("take").
+		Requires(legalTake{}).
+		Updates("pile", func(engine *atmos.Engine, state interface{}, event atmos.Event) interface{} {
+			return state.(int) - event.(TakeEvent).Count
+		})
+	return engine
+}
+
+// alternate gives the turn to alice and bob in order while stones remain
+func alternate(engine *atmos.Engine) string {
+	if engine.GetState("pile").(int) == 0 {
+		return ""
+	}
+	if len(engine.GetEvents())%2 == 0 {
+		return "alice"
+	}
+	return "bob"
+}
+
+// randomBot takes a random legal number of stones
+func randomBot(player string) agent.Agent {
+	return agent.Func(func(view agent.StateView) (atmos.Event, bool) {
+		var legal []atmos.Event
+		for count := 1; count <= 3; count++ {
+			if take := (TakeEvent{Player: player, Count: count}); view.Legal(take) {
+				legal = append(legal, take)
+			}
+		}
+		if len(legal) == 0 {
+			return nil, false
+		}
+		return legal[view.Rand.Intn(len(legal))], true
+	})
+}
+
+// play runs a bot-vs-bot game and returns its log
+func play(seed int64) []atmos.Event {
+	engine := pile(20)
+	driver := agent.NewDriver(engine, alternate, agent.WithSeed(seed))
+	driver.Register("alice", randomBot("alice"))
+	driver.Register("bob", randomBot("bob"))
+	driver.Run(100)
+	return engine.GetEvents()
+}
+
+// TestDriverPlaysAgentsToCompletion verifies agents take turns until the game ends
+func TestDriverPlaysAgentsToCompletion(t *testing.T) {
+	engine := pile(10)
+	driver := agent.NewDriver(engine, alternate)
+	driver.Register("alice", randomBot("alice"))
+	driver.Register("bob", randomBot("bob"))
+
+	steps, err := driver.Run(100)
+	require.NoError(t, err)
+	assert.Equal(t, 0, engine.GetState("pile"))
+	assert.Len(t, engine.GetEvents(), steps)
+	for i, event := range engine.GetEvents() {
+		assert.Equal(t, []string{"alice", "bob"}[i%2], event.(TakeEvent).Player)
+	}
+}
+
+// TestDecisionsAreDeterministic verifies the same seed reproduces the same game
+func TestDecisionsAreDeterministic(t *testing.T) {
+	assert.Equal(t, play(7), play(7))
+
+	differs := false
+	for seed := int64(0); seed < 10 && !differs; seed++ {
+		differs = !assert.ObjectsAreEqual(play(seed), play(seed+100))
+	}
+	assert.True(t, differs, "Different seeds lead to different games")
+}
+
+// TestDriverStopsForHumans verifies the driver waits when the player to act has no agent
+func TestDriverStopsForHumans(t *testing.T) {
+	engine := pile(10)
+	driver := agent.NewDriver(engine, alternate)
+	driver.Register("bob", randomBot("bob"))
+
+	steps, err := driver.Run(100)
+	require.NoError(t, err)
+	assert.Equal(t, 0, steps, "Alice moves first and is human")
+
+	require.True(t, engine.Emit(TakeEvent{Player: "alice", Count: 2}))
+	emitted, err := driver.Step()
+	require.NoError(t, err)
+	assert.True(t, emitted)
+	assert.Len(t, engine.GetEvents(), 2)
+
+	emitted, err = driver.Step()
+	require.NoError(t, err)
+	assert.False(t, emitted, "Back to the human")
+}
+
+// TestRejectedDecisionsReturnErrors verifies illegal decisions surface the rejection
+func TestRejectedDecisionsReturnErrors(t *testing.T) {
+	engine := pile(10)
+	driver := agent.NewDriver(engine, alternate)
+	driver.Register("alice", agent.Func(func(view agent.StateView) (atmos.Event, bool) {
+		return TakeEvent{Player: view.Player, Count: 5}, true
+	}))
+
+	steps, err := driver.Run(100)
+	assert.Equal(t, 0, steps)
+	assert.True(t, errors.Is(err, agent.ErrRejected))
+	assert.Contains(t, err.Error(), "alice proposed take")
+	assert.Empty(t, engine.GetEvents())
+}
+
+// TestViewIsMaskedForThePlayer verifies agents only see what their player may see
+func TestViewIsMaskedForThePlayer(t *testing.T) {
+	engine := pile(10)
+	engine.RegisterState("hands", map[string]string{"alice": "ace", "bob": "king"})
+	engine.RegisterStateMask("hands", func(engine *atmos.Engine, viewer string, state interface{}) interface{} {
+		return map[string]string{viewer: state.(map[string]string)[viewer]}
+	})
+
+	var seen interface{}
+	driver := agent.NewDriver(engine, alternate)
+	driver.Register("alice", agent.Func(func(view agent.StateView) (atmos.Event, bool) {
+		seen = view.State("hands")
+		return nil, false
+	}))
+
+	emitted, err := driver.Step()
+	require.NoError(t, err)
+	assert.False(t, emitted, "The agent passed")
+	assert.Equal(t, map[string]string{"alice": "ace"}, seen)
+}
@@ -0,0 +1,231 @@
+// Package analytics forwards committed events to a product analytics sink,
+// sampled and with sensitive fields stripped or hashed, so telemetry needs
+// no hand-written listener per game:
+//
+//	exporter := analytics.NewExporter(sink,
+//		analytics.SampleRate(0.1),
+//		analytics.Sample("game_finished", 1),
+//		analytics.Strip("message"),
+//		analytics.Hash(salt, "player", "opponent"),
+//	)
+//	engine.ThenAll(exporter)
+//
+// Each event is converted to its JSON properties; field names are the JSON
+// names, with dots for nested objects ("card.owner").
+package analytics
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"math/rand"
+	"time"
+
+	"github.com/cumulusrpg/atmos"
+	"github.com/cumulusrpg/atmos/types"
+)
+
+// Record is one event as sent to analytics
+type Record struct {
+	Type       string                 `json:"type"`
+	At         time.Time              `json:"at"`
+	Actor      string                 `json:"actor,omitempty"` // hashed; set with IncludeActor
+	Properties map[string]interface{} `json:"properties"`
+}
+
+// Sink receives analytics records. Track is called during the emit, so
+// sinks should buffer and send in the background.
+type Sink interface {
+	Track(record Record) error
+}
+
+// SinkFunc adapts a function to Sink
+type SinkFunc func(record Record) error
+
+// Track implements Sink
+func (f SinkFunc) Track(record Record) error {
+	return f(record)
+}
+
+// Exporter is an EventListener that forwards committed events to a sink
+type Exporter struct {
+	sink         Sink
+	defaultRate  float64
+	rates        map[string]float64 // event type -> sample rate
+	strip        map[string]bool    // field paths removed
+	hash         map[string]bool    // field paths replaced by a salted hash
+	salt         []byte
+	includeActor bool
+	random       func() float64
+	now          func() time.Time
+	onError      func(error)
+}
+
+// Option configures exporter construction
+type Option func(*Exporter)
+
+// SampleRate sets the fraction of events exported, from 0 to 1 (default 1)
+func SampleRate(rate float64) Option {
+	return func(x *Exporter) {
+		x.defaultRate = rate
+	}
+}
+
+// Sample sets the fraction of one event type exported, overriding SampleRate
+func Sample(eventType string, rate float64) Option {
+	return func(x *Exporter) {
+		x.rates[eventType] = rate
+	}
+}
+
+// Strip removes fields from every record
+func Strip(fields ...string) Option {
+	return func(x *Exporter) {
+		for _, field := range fields {
+			x.strip[field] = true
+		}
+	}
+}
+
+// Hash replaces fields with a salted SHA-256 hash, so records can be joined
+// on them without revealing their values. The salt is shared by every
+// hashed field, including the actor.
+func Hash(salt string, fields ...string) Option {
+	return func(x *Exporter) {
+		x.salt = []byte(salt)
+		for _, field := range fields {
+			x.hash[field] = true
+		}
+	}
+}
+
+// IncludeActor adds the hashed actor of each emit (see atmos.Engine.EmitAs)
+func IncludeActor() Option {
+	return func(x *Exporter) {
+		x.includeActor = true
+	}
+}
+
+// WithRandom sets the source of sampling decisions, returning values in [0, 1)
+// (default math/rand)
+func WithRandom(random func() float64) Option {
+	return func(x *Exporter) {
+		x.random = random
+	}
+}
+
+// WithClock sets the time source for record timestamps (default time.Now)
+func WithClock(now func() time.Time) Option {
+	return func(x *Exporter) {
+		x.now = now
+	}
+}
+
+// OnError is called when an event can't be converted or the sink fails
+// (by default errors are dropped: analytics never disrupt the game)
+func OnError(handle func(error)) Option {
+	return func(x *Exporter) {
+		x.onError = handle
+	}
+}
+
+// NewExporter creates an exporter sending to sink
+func NewExporter(sink Sink, opts ...Option) *Exporter {
+	x := &Exporter{
+		sink:        sink,
+		defaultRate: 1,
+		rates:       make(map[string]float64),
+		strip:       make(map[string]bool),
+		hash:        make(map[string]bool),
+		random:      rand.Float64,
+		now:         time.Now,
+		onError:     func(error) {},
+	}
+
+	// Apply options
+	for _, opt := range opts {
+		opt(x)
+	}
+
+	return x
+}
+
+// Handle implements atmos.EventListener. Nothing is exported while the
+// engine is replaying.
+func (x *Exporter) Handle(engine types.Engine, event atmos.Event) {
+	if atmos.IsReplaying(engine) || !x.sampled(event.Type()) {
+		return
+	}
+
+	record, err := x.record(event)
+	if err != nil {
+		x.onError(err)
+		return
+	}
+	if actor := engine.(*atmos.Engine).Actor(); x.includeActor && actor != "" {
+		record.Actor = x.digest(actor)
+	}
+	if err := x.sink.Track(record); err != nil {
+		x.onError(err)
+	}
+}
+
+// sampled decides whether to export an event of a type
+func (x *Exporter) sampled(eventType string) bool {
+	rate, exists := x.rates[eventType]
+	if !exists {
+		rate = x.defaultRate
+	}
+	return rate >= 1 || (rate > 0 && x.random() < rate)
+}
+
+// record converts an event to an anonymized record
+func (x *Exporter) record(event atmos.Event) (Record, error) {
+	data, err := json.Marshal(event)
+	if err != nil {
+		return Record{}, err
+	}
+	properties := map[string]interface{}{}
+	var decoded interface{}
+	if err := json.Unmarshal(data, &decoded); err != nil {
+		return Record{}, err
+	}
+	if object, ok := decoded.(map[string]interface{}); ok {
+		properties = object
+	} else if decoded != nil {
+		properties["value"] = decoded // Events that aren't JSON objects
+	}
+
+	x.anonymize(properties, "")
+	return Record{Type: event.Type(), At: x.now(), Properties: properties}, nil
+}
+
+// anonymize strips and hashes the configured fields of an object, in place
+func (x *Exporter) anonymize(object map[string]interface{}, prefix string) {
+	for name, value := range object {
+		path := prefix + name
+		switch {
+		case x.strip[path]:
+			delete(object, name)
+		case x.hash[path]:
+			text, isString := value.(string)
+			if !isString {
+				encoded, _ := json.Marshal(value)
+				text = string(encoded)
+			}
+			object[name] = x.digest(text)
+		default:
+			if nested, ok := value.(map[string]interface{}); ok {
+				x.anonymize(nested, path+".")
+			}
+		}
+	}
+}
+
+// digest returns the salted hash of a value
+func (x *Exporter) digest(value string) string {
+	mac := hmac.New(sha256.New, x.salt)
+	mac.Write([]byte(value))
+	return hex.EncodeToString(mac.Sum(nil))
+}
@@ -0,0 +1,114 @@
+package analytics_test
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/cumulusrpg/atmos"
+	"github.com/cumulusrpg/atmos/analytics"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type Card struct {
+	Name  string `json:"name"`
+	Owner string `json:"owner"`
+}
+
+type CardPlayedEvent struct {
+	Player  string `json:"player"`
+	Card    Card   `json:"card"`
+	Message string `json:"message"`
+}
+
+func (e CardPlayedEvent) Type() string { return "card_played" }
+
+type HeartbeatEvent struct{}
+
+func (e HeartbeatEvent) Type() string { return "heartbeat" }
+
+var epoch = time.Date(2024, 5, 1, 12, 0, 0, 0, time.UTC)
+
+// collector is a sink that keeps every record
+type collector struct {
+	records []analytics.Record
+}
+
+func (c *collector) Track(record analytics.Record) error {
+	c.records = append(c.records, record)
+	return nil
+}
+
+// TestExporterAnonymizes verifies stripped fields are removed and hashed ones are stable digests
+func TestExporterAnonymizes(t *testing.T) {
+	sink := &collector{}
+	engine := atmos.NewEngine()
+	engine.ThenAll(analytics.NewExporter(sink,
+		analytics.Strip("message"),
+		analytics.Hash("pepper", "player", "card.owner"),
+		analytics.IncludeActor(),
+		analytics.WithClock(func() time.Time { return epoch }),
+	))
+
+	engine.EmitAs("alice", CardPlayedEvent{Player: "alice", Card: Card{Name: "Fireball", Owner: "alice"}, Message: "gg"})
+	engine.EmitAs("bob", CardPlayedEvent{Player: "bob", Card: Card{Name: "Shield", Owner: "alice"}})
+
+	require.Len(t, sink.records, 2)
+	first, second := sink.records[0], sink.records[1]
+	assert.Equal(t, "card_played", first.Type)
+	assert.Equal(t, epoch, first.At)
+	assert.NotContains(t, first.Properties, "message")
+	assert.Len(t, first.Properties["player"], 64)
+	assert.NotEqual(t, "alice", first.Properties["player"])
+	assert.Equal(t, first.Properties["player"], first.Actor, "Actor and fields share the salt, so they can be joined")
+	assert.Equal(t, "Fireball", first.Properties["card"].(map[string]interface{})["name"])
+	assert.Equal(t, first.Properties["card"].(map[string]interface{})["owner"],
+		second.Properties["card"].(map[string]interface{})["owner"])
+	assert.NotEqual(t, first.Properties["player"], second.Properties["player"])
+}
+
+// TestExporterSamples verifies per-type rates override the default rate
+func TestExporterSamples(t *testing.T) {
+	sink := &collector{}
+	rolls := []float64{0.05, 0.5, 0.95, 0.05}
+	engine := atmos.NewEngine()
+	engine.ThenAll(analytics.NewExporter(sink,
+		analytics.SampleRate(0.1),
+		analytics.Sample("card_played", 1),
+		analytics.WithRandom(func() float64 {
+			roll := rolls[0]
+			rolls = rolls[1:]
+			return roll
+		}),
+	))
+
+	for i := 0; i < 3; i++ {
+		engine.Emit(HeartbeatEvent{})
+	}
+	engine.Emit(CardPlayedEvent{})
+
+	var exported []string
+	for _, record := range sink.records {
+		exported = append(exported, record.Type)
+	}
+	assert.Equal(t, []string{"heartbeat", "card_played"}, exported)
+	assert.Len(t, rolls, 1, "Fully sampled types don't consume rolls")
+}
+
+// TestExporterNeverDisruptsTheGame verifies sink failures are reported, not raised, and replays aren't exported
+func TestExporterNeverDisruptsTheGame(t *testing.T) {
+	var errs []error
+	calls := 0
+	engine := atmos.NewEngine()
+	engine.ThenAll(analytics.NewExporter(analytics.SinkFunc(func(analytics.Record) error {
+		calls++
+		return errors.New("collector offline")
+	}), analytics.OnError(func(err error) { errs = append(errs, err) })))
+
+	assert.True(t, engine.Emit(HeartbeatEvent{}))
+	assert.EqualError(t, errors.Join(errs...), "collector offline")
+
+	require.NoError(t, engine.Replay([]atmos.Event{HeartbeatEvent{}}))
+	assert.Equal(t, 1, calls)
+}
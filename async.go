@@ -0,0 +1,128 @@
+package atmos
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+	"sync"
+
+	"github.com/cumulusrpg/atmos/types"
+)
+
+// asyncWork tracks goroutines started with Engine.Go
+type asyncWork struct {
+	mu      sync.Mutex
+	running map[int]string // id -> name
+	next    int
+	idle    chan struct{} // closed whenever nothing is running
+}
+
+// start records a running task, returning its id
+func (w *asyncWork) start(name string) int {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	if w.running == nil {
+		w.running = make(map[int]string)
+	}
+	if len(w.running) == 0 {
+		w.idle = make(chan struct{})
+	}
+	w.next++
+	w.running[w.next] = name
+	return w.next
+}
+
+// finish records a task as done
+func (w *asyncWork) finish(id int) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	delete(w.running, id)
+	if len(w.running) == 0 {
+		close(w.idle)
+	}
+}
+
+// wait blocks until nothing is running or ctx is done, returning the names
+// of tasks still running
+func (w *asyncWork) wait(ctx context.Context) []string {
+	w.mu.Lock()
+	if len(w.running) == 0 {
+		w.mu.Unlock()
+		return nil
+	}
+	idle := w.idle
+	w.mu.Unlock()
+
+	select {
+	case <-idle:
+		return w.wait(ctx) // More work may have started meanwhile
+	case <-ctx.Done():
+	}
+
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	pending := make([]string, 0, len(w.running))
+	for _, name := range w.running {
+		pending = append(pending, name)
+	}
+	sort.Strings(pending)
+	return pending
+}
+
+// Go runs fn on a new goroutine that Shutdown and Close wait for. The name
+// identifies the work if it has to be dropped. fn must not use the engine,
+// which is not safe for concurrent use.
+func (e *Engine) Go(name string, fn func()) {
+	id := e.async.start(name)
+	go func() {
+		defer e.async.finish(id)
+		fn()
+	}()
+}
+
+// asyncListener runs a listener on its own goroutine
+type asyncListener struct {
+	listener EventListener
+}
+
+// Handle implements EventListener
+func (l *asyncListener) Handle(engine types.Engine, event Event) {
+	e := engine.(*Engine)
+	if e.Replaying() {
+		return
+	}
+	e.Go(fmt.Sprintf("%s on %s", listenerName(l.listener), event.Type()), func() {
+		l.listener.Handle(engine, event)
+	})
+}
+
+// ThenAsync registers listeners that run on their own goroutines after the
+// event commits, for slow side effects like notifications. They must not use
+// the engine they are given, which is not safe for concurrent use;
+// Shutdown waits for any still running. They are skipped in replay mode.
+func (r *EventRegistration) ThenAsync(listeners ...EventListener) *EventRegistration {
+	for _, listener := range listeners {
+		r.WithListener(&asyncListener{listener: listener})
+	}
+	return r
+}
+
+// DroppedWorkError is returned by Shutdown when async work was still running
+// at the deadline
+type DroppedWorkError struct {
+	Pending []string // names of the work still running, sorted
+	Err     error    // the context's error
+}
+
+// Error implements error
+func (e *DroppedWorkError) Error() string {
+	return fmt.Sprintf("shutdown dropped %d async tasks (%s): %v", len(e.Pending), strings.Join(e.Pending, ", "), e.Err)
+}
+
+// Unwrap returns the context's error
+func (e *DroppedWorkError) Unwrap() error {
+	return e.Err
+}
@@ -0,0 +1,81 @@
+package atmos
+
+import (
+	"context"
+	"errors"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/cumulusrpg/atmos/types"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// notifier is a slow listener that waits for release before finishing
+type notifier struct {
+	release chan struct{}
+	sent    atomic.Int32
+}
+
+func (n *notifier) Handle(engine types.Engine, event Event) {
+	<-n.release
+	n.sent.Add(1)
+}
+
+// TestShutdownDrainsAsyncListeners verifies Shutdown waits for listeners still running
+func TestShutdownDrainsAsyncListeners(t *testing.T) {
+	engine := NewEngine()
+	slow := &notifier{release: make(chan struct{})}
+	engine.When("test_event").ThenAsync(slow)
+
+	assert.True(t, engine.Emit(TestEvent{Name: "a"}), "Emit doesn't wait for async listeners")
+	assert.True(t, engine.Emit(TestEvent{Name: "b"}))
+	assert.Equal(t, int32(0), slow.sent.Load())
+
+	go func() {
+		time.Sleep(10 * time.Millisecond)
+		close(slow.release)
+	}()
+	require.NoError(t, engine.Shutdown(context.Background()))
+	assert.Equal(t, int32(2), slow.sent.Load())
+	assert.ErrorIs(t, engine.EmitWithResult(TestEvent{Name: "c"}).Err, ErrEngineClosed)
+}
+
+// TestShutdownReportsDroppedWork verifies work still running at the deadline is reported
+func TestShutdownReportsDroppedWork(t *testing.T) {
+	engine := NewEngine()
+	slow := &notifier{release: make(chan struct{})}
+	defer close(slow.release)
+	engine.When("test_event").ThenAsync(slow)
+	engine.Emit(TestEvent{Name: "a"})
+
+	hooked := false
+	engine.OnShutdown(func(e *Engine) error {
+		hooked = true
+		return nil
+	})
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+	err := engine.Shutdown(ctx)
+
+	var dropped *DroppedWorkError
+	require.True(t, errors.As(err, &dropped))
+	assert.Equal(t, []string{"*atmos.notifier on test_event"}, dropped.Pending)
+	assert.ErrorIs(t, err, context.DeadlineExceeded)
+	assert.True(t, hooked, "Shutdown hooks run even when work is dropped")
+}
+
+// TestGoTracksWork verifies work started with Go is waited for
+func TestGoTracksWork(t *testing.T) {
+	engine := NewEngine()
+	var done atomic.Bool
+	engine.Go("upload", func() {
+		time.Sleep(10 * time.Millisecond)
+		done.Store(true)
+	})
+
+	require.NoError(t, engine.Close())
+	assert.True(t, done.Load())
+}
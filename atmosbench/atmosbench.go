@@ -0,0 +1,227 @@
+// Package atmosbench measures engine performance on synthetic event logs:
+// GetState replay time, Emit throughput, and serialization cost. Use it to
+// compare repositories and projection strategies before committing to one.
+//
+//	report, err := atmosbench.Run(atmosbench.Config{
+//		Events:     100000,
+//		States:     4,
+//		Repository: func() types.EventRepository { return myrepo.New() },
+//	})
+//	fmt.Println(report)
+//
+// The Benchmark* helpers wrap the same workloads for go test -bench.
+package atmosbench
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/cumulusrpg/atmos"
+	"github.com/cumulusrpg/atmos/repository"
+	"github.com/cumulusrpg/atmos/types"
+)
+
+// EventType is the type of the synthetic events
+const EventType = "bench_event"
+
+// Event is a synthetic event with a configurable payload
+type Event struct {
+	Seq     int    `json:"seq"`
+	Actor   string `json:"actor"`
+	Payload string `json:"payload"`
+}
+
+func (e *Event) Type() string { return EventType }
+
+// Tally is the state each synthetic projection maintains
+type Tally struct {
+	Count int
+	Sum   int
+}
+
+// Config describes a synthetic workload
+type Config struct {
+	Events      int                          // Log length (default 1000)
+	PayloadSize int                          // Payload bytes per event (default 64)
+	Actors      int                          // Distinct actors cycled through the log (default 4)
+	States      int                          // Projections reducing every event (default 1)
+	Repository  func() types.EventRepository // Repository under test (default in-memory)
+}
+
+// withDefaults fills in unset fields
+func (c Config) withDefaults() Config {
+	if c.Events <= 0 {
+		c.Events = 1000
+	}
+	if c.PayloadSize < 0 {
+		c.PayloadSize = 0
+	} else if c.PayloadSize == 0 {
+		c.PayloadSize = 64
+	}
+	if c.Actors <= 0 {
+		c.Actors = 4
+	}
+	if c.States <= 0 {
+		c.States = 1
+	}
+	if c.Repository == nil {
+		c.Repository = func() types.EventRepository { return repository.NewInMemory() }
+	}
+	return c
+}
+
+// StateName returns the name of the i-th synthetic projection
+func StateName(i int) string {
+	return fmt.Sprintf("tally_%d", i)
+}
+
+// NewEngine creates an empty engine wired with the synthetic event type and projections
+func (c Config) NewEngine() *atmos.Engine {
+	c = c.withDefaults()
+	engine := atmos.NewEngine(atmos.WithRepository(c.Repository()))
+	registration := engine.When(EventType, func() atmos.Event { return &Event{} })
+	for i := 0; i < c.States; i++ {
+		engine.RegisterState(StateName(i), Tally{})
+		registration.Updates(StateName(i), reduceTally)
+	}
+	return engine
+}
+
+// Log generates the synthetic event log
+func (c Config) Log() []atmos.Event {
+	c = c.withDefaults()
+	payload := strings.Repeat("x", c.PayloadSize)
+	events := make([]atmos.Event, c.Events)
+	for i := range events {
+		events[i] = &Event{Seq: i, Actor: fmt.Sprintf("actor-%d", i%c.Actors), Payload: payload}
+	}
+	return events
+}
+
+// reduceTally counts events and sums their sequence numbers
+func reduceTally(engine *atmos.Engine, state interface{}, event atmos.Event) interface{} {
+	tally := state.(Tally)
+	tally.Count++
+	tally.Sum += event.(*Event).Seq
+	return tally
+}
+
+// Report holds the measurements of one run
+type Report struct {
+	Events        int
+	Emit          time.Duration // Time to Emit every event into an empty engine
+	EmitPerSecond float64
+	Replay        time.Duration // Time for one GetState over the full log
+	Marshal       time.Duration // Time to MarshalEvents the full log
+	Unmarshal     time.Duration // Time to UnmarshalEvents the full log
+	Bytes         int           // Size of the marshaled log
+}
+
+// String renders the report as a table
+func (r Report) String() string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "events     %d\n", r.Events)
+	fmt.Fprintf(&b, "emit       %v (%.0f events/s)\n", r.Emit, r.EmitPerSecond)
+	fmt.Fprintf(&b, "replay     %v\n", r.Replay)
+	fmt.Fprintf(&b, "marshal    %v (%d bytes)\n", r.Marshal, r.Bytes)
+	fmt.Fprintf(&b, "unmarshal  %v\n", r.Unmarshal)
+	return b.String()
+}
+
+// Run measures every workload once
+func Run(c Config) (Report, error) {
+	c = c.withDefaults()
+	log := c.Log()
+	report := Report{Events: len(log)}
+
+	engine := c.NewEngine()
+	start := time.Now()
+	for _, event := range log {
+		if !engine.Emit(event) {
+			return report, fmt.Errorf("atmosbench: event %d was rejected", event.(*Event).Seq)
+		}
+	}
+	report.Emit = time.Since(start)
+	if seconds := report.Emit.Seconds(); seconds > 0 {
+		report.EmitPerSecond = float64(len(log)) / seconds
+	}
+
+	start = time.Now()
+	engine.GetState(StateName(0))
+	report.Replay = time.Since(start)
+
+	start = time.Now()
+	data, err := engine.MarshalEvents(log)
+	report.Marshal = time.Since(start)
+	if err != nil {
+		return report, err
+	}
+	report.Bytes = len(data)
+
+	start = time.Now()
+	decoded, err := engine.UnmarshalEvents(data)
+	report.Unmarshal = time.Since(start)
+	if err != nil {
+		return report, err
+	}
+	if len(decoded) != len(log) {
+		return report, fmt.Errorf("atmosbench: decoded %d of %d events", len(decoded), len(log))
+	}
+
+	return report, nil
+}
+
+// BenchmarkReplay measures GetState over a preloaded log
+func BenchmarkReplay(b *testing.B, c Config) {
+	engine := c.NewEngine()
+	engine.SetEvents(c.Log())
+	name := StateName(0)
+
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		engine.GetState(name)
+	}
+}
+
+// BenchmarkEmit measures Emit into a fresh engine; each iteration emits one event
+func BenchmarkEmit(b *testing.B, c Config) {
+	c = c.withDefaults()
+	payload := strings.Repeat("x", c.PayloadSize)
+	engine := c.NewEngine()
+
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		engine.Emit(&Event{Seq: i, Actor: "actor-0", Payload: payload})
+	}
+}
+
+// BenchmarkMarshal measures MarshalEvents of the full log
+func BenchmarkMarshal(b *testing.B, c Config) {
+	engine := c.NewEngine()
+	log := c.Log()
+
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		if _, err := engine.MarshalEvents(log); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
+
+// BenchmarkUnmarshal measures UnmarshalEvents of the full log
+func BenchmarkUnmarshal(b *testing.B, c Config) {
+	engine := c.NewEngine()
+	data, err := engine.MarshalEvents(c.Log())
+	if err != nil {
+		b.Fatal(err)
+	}
+
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		if _, err := engine.UnmarshalEvents(data); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
@@ -0,0 +1,40 @@
+package atmosbench_test
+
+import (
+	"testing"
+
+	"github.com/cumulusrpg/atmos/atmosbench"
+	"github.com/stretchr/testify/assert"
+)
+
+// TestRunReportsEveryWorkload verifies a small run produces consistent measurements
+func TestRunReportsEveryWorkload(t *testing.T) {
+	config := atmosbench.Config{Events: 200, PayloadSize: 16, States: 3}
+	report, err := atmosbench.Run(config)
+
+	assert.NoError(t, err)
+	assert.Equal(t, 200, report.Events)
+	assert.Greater(t, report.Bytes, 200*16)
+	assert.Greater(t, report.EmitPerSecond, 0.0)
+	assert.Contains(t, report.String(), "events/s")
+
+	engine := config.NewEngine()
+	engine.SetEvents(config.Log())
+	assert.Equal(t, atmosbench.Tally{Count: 200, Sum: 199 * 200 / 2}, engine.GetState(atmosbench.StateName(2)))
+}
+
+func BenchmarkReplay1k(b *testing.B) {
+	atmosbench.BenchmarkReplay(b, atmosbench.Config{Events: 1000})
+}
+
+func BenchmarkEmit(b *testing.B) {
+	atmosbench.BenchmarkEmit(b, atmosbench.Config{})
+}
+
+func BenchmarkMarshal1k(b *testing.B) {
+	atmosbench.BenchmarkMarshal(b, atmosbench.Config{Events: 1000})
+}
+
+func BenchmarkUnmarshal1k(b *testing.B) {
+	atmosbench.BenchmarkUnmarshal(b, atmosbench.Config{Events: 1000})
+}
@@ -0,0 +1,250 @@
+// Package atmosconfig wires engines from a declarative YAML or JSON config,
+// so rule wiring can change without recompiling. Components are written in
+// Go and registered by name; the config decides which apply to which events:
+//
+//	registry := atmosconfig.NewRegistry()
+//	registry.Validator("game_in_progress", Valid(&GameInProgress{}))
+//	registry.Listener("advance_turn", Do(&AdvanceTurn{}))
+//	registry.Reducer("count_moves", CountMoves)
+//
+//	config, err := atmosconfig.LoadFile("rules.yaml")
+//	err = config.Apply(engine, registry)
+//
+// with rules.yaml:
+//
+//	events:
+//	  move_made:
+//	    requires: [game_in_progress]
+//	    then: [advance_turn]
+//	    updates: {moves: count_moves}
+//	    except:
+//	      - validator: game_in_progress
+//	        when: is_tutorial
+//	        reason: tutorial moves are always allowed
+//
+// States are registered in code (their initial values are Go values);
+// the config only names the reducers that update them.
+package atmosconfig
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"maps"
+	"os"
+	"slices"
+	"strings"
+
+	"github.com/cumulusrpg/atmos"
+	"gopkg.in/yaml.v3"
+)
+
+// Condition decides whether an exception applies to an event
+type Condition func(engine *atmos.Engine, event atmos.Event) bool
+
+// Registry holds the named components a config may refer to
+type Registry struct {
+	validators map[string]atmos.EventValidator
+	listeners  map[string]atmos.EventListener
+	reducers   map[string]atmos.StateReducer
+	conditions map[string]Condition
+	factories  map[string]func() atmos.Event
+}
+
+// NewRegistry creates an empty component registry
+func NewRegistry() *Registry {
+	return &Registry{
+		validators: make(map[string]atmos.EventValidator),
+		listeners:  make(map[string]atmos.EventListener),
+		reducers:   make(map[string]atmos.StateReducer),
+		conditions: make(map[string]Condition),
+		factories:  make(map[string]func() atmos.Event),
+	}
+}
+
+// Validator registers a validator (also usable as an advisory or policy)
+func (r *Registry) Validator(name string, validator atmos.EventValidator) {
+	r.validators[name] = validator
+}
+
+// Listener registers a listener (also usable as a before hook)
+func (r *Registry) Listener(name string, listener atmos.EventListener) {
+	r.listeners[name] = listener
+}
+
+// Reducer registers a state reducer
+func (r *Registry) Reducer(name string, reducer atmos.StateReducer) {
+	r.reducers[name] = reducer
+}
+
+// Condition registers an exception condition
+func (r *Registry) Condition(name string, condition Condition) {
+	r.conditions[name] = condition
+}
+
+// Event registers the factory for an event type, applied to configured events
+func (r *Registry) Event(eventType string, factory func() atmos.Event) {
+	r.factories[eventType] = factory
+}
+
+// Config is a declarative engine wiring
+type Config struct {
+	Policies map[string]string      `yaml:"policies" json:"policies"` // policy name -> validator name
+	Events   map[string]EventConfig `yaml:"events" json:"events"`     // event type ("*" for every event) -> wiring
+}
+
+// EventConfig wires one event type; every entry names a registry component
+type EventConfig struct {
+	Requires []string          `yaml:"requires" json:"requires"` // Validators, or "policy:name" for policies
+	Warns    []string          `yaml:"warns" json:"warns"`       // Advisory validators
+	Before   []string          `yaml:"before" json:"before"`     // Before hooks (listeners)
+	Then     []string          `yaml:"then" json:"then"`         // Listeners
+	Updates  map[string]string `yaml:"updates" json:"updates"`   // State name -> reducer
+	Except   []ExceptionConfig `yaml:"except" json:"except"`
+	Emits    []string          `yaml:"emits" json:"emits"` // Declared derived event types
+}
+
+// ExceptionConfig skips a validator when a condition holds
+type ExceptionConfig struct {
+	Validator string `yaml:"validator" json:"validator"`
+	When      string `yaml:"when" json:"when"`
+	Reason    string `yaml:"reason" json:"reason"`
+}
+
+// Load parses a YAML or JSON config. Unknown keys are errors, so a
+// misspelled "require:" doesn't silently wire nothing.
+func Load(data []byte) (*Config, error) {
+	var config Config
+	if err := decode(data, &config); err != nil {
+		return nil, fmt.Errorf("atmosconfig: %w", err)
+	}
+	return &config, nil
+}
+
+// decode strictly parses a JSON object, or otherwise YAML
+func decode(data []byte, config *Config) error {
+	if trimmed := bytes.TrimSpace(data); len(trimmed) > 0 && trimmed[0] == '{' {
+		decoder := json.NewDecoder(bytes.NewReader(data))
+		decoder.DisallowUnknownFields()
+		return decoder.Decode(config)
+	}
+
+	decoder := yaml.NewDecoder(bytes.NewReader(data))
+	decoder.KnownFields(true)
+	if err := decoder.Decode(config); err != nil && !errors.Is(err, io.EOF) {
+		return err
+	}
+	return nil
+}
+
+// LoadFile parses a YAML or JSON config file
+func LoadFile(path string) (*Config, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	return Load(data)
+}
+
+// Apply wires the config into an engine. Every name is resolved before
+// anything is registered, so a config referring to unknown components,
+// policies, or states leaves the engine untouched and reports them all.
+func (c *Config) Apply(engine *atmos.Engine, registry *Registry) error {
+	if err := c.Check(engine, registry); err != nil {
+		return err
+	}
+
+	for _, name := range slices.Sorted(maps.Keys(c.Policies)) {
+		engine.RegisterPolicy(name, registry.validators[c.Policies[name]])
+	}
+
+	for _, eventType := range slices.Sorted(maps.Keys(c.Events)) {
+		ec := c.Events[eventType]
+		reg := engine.When(eventType)
+		if factory, exists := registry.factories[eventType]; exists {
+			reg.WithEventFactory(factory)
+		}
+		for _, name := range ec.Requires {
+			reg.Requires(c.validator(registry, name))
+		}
+		for _, name := range ec.Warns {
+			reg.Warns(c.validator(registry, name))
+		}
+		for _, name := range ec.Before {
+			reg.Before(registry.listeners[name])
+		}
+		for _, name := range ec.Then {
+			reg.Then(registry.listeners[name])
+		}
+		for _, state := range slices.Sorted(maps.Keys(ec.Updates)) {
+			reg.Updates(state, registry.reducers[ec.Updates[state]])
+		}
+		for _, ex := range ec.Except {
+			reg.Except(c.validator(registry, ex.Validator), registry.conditions[ex.When], ex.Reason)
+		}
+		if len(ec.Emits) > 0 {
+			reg.Emits(ec.Emits...)
+		}
+	}
+	return nil
+}
+
+// Check reports every unresolved name in the config without wiring anything
+func (c *Config) Check(engine *atmos.Engine, registry *Registry) error {
+	var errs []error
+	missing := func(kind, name, eventType string) {
+		errs = append(errs, fmt.Errorf("atmosconfig: %s: unknown %s %q", eventType, kind, name))
+	}
+
+	for _, name := range slices.Sorted(maps.Keys(c.Policies)) {
+		if _, exists := registry.validators[c.Policies[name]]; !exists {
+			missing("validator", c.Policies[name], "policy "+name)
+		}
+	}
+
+	states := make(map[string]bool)
+	for _, name := range engine.StateNames() {
+		states[name] = true
+	}
+
+	for _, eventType := range slices.Sorted(maps.Keys(c.Events)) {
+		ec := c.Events[eventType]
+		validators := append(append([]string(nil), ec.Requires...), ec.Warns...)
+		for _, ex := range ec.Except {
+			validators = append(validators, ex.Validator)
+			if _, exists := registry.conditions[ex.When]; !exists {
+				missing("condition", ex.When, eventType)
+			}
+		}
+		for _, name := range validators {
+			if c.validator(registry, name) == nil {
+				missing("validator", name, eventType)
+			}
+		}
+		for _, name := range append(append([]string(nil), ec.Before...), ec.Then...) {
+			if _, exists := registry.listeners[name]; !exists {
+				missing("listener", name, eventType)
+			}
+		}
+		for _, state := range slices.Sorted(maps.Keys(ec.Updates)) {
+			if !states[state] {
+				missing("state", state, eventType)
+			}
+			if _, exists := registry.reducers[ec.Updates[state]]; !exists {
+				missing("reducer", ec.Updates[state], eventType)
+			}
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// validator resolves a validator name, or "policy:name" for a configured or
+// engine-registered policy (nil if unknown)
+func (c *Config) validator(registry *Registry, name string) atmos.EventValidator {
+	if policy, ok := strings.CutPrefix(name, "policy:"); ok {
+		return atmos.Policy(policy)
+	}
+	return registry.validators[name]
+}
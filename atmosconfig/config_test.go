@@ -0,0 +1,134 @@
+package atmosconfig_test
+
+import (
+	"testing"
+
+	"github.com/cumulusrpg/atmos"
+	"github.com/cumulusrpg/atmos/atmosconfig"
+	"github.com/cumulusrpg/atmos/types"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type MoveEvent struct {
+	Player   string `json:"player"`
+	Tutorial bool   `json:"tutorial"`
+}
+
+func (e MoveEvent) Type() string { return "move" }
+
+type GameOverEvent struct{}
+
+func (e GameOverEvent) Type() string { return "game_over" }
+
+// check adapts a function to atmos.EventValidator (as a pointer, so exceptions can refer to it)
+type check struct {
+	fn func(engine *atmos.Engine, event atmos.Event) bool
+}
+
+func (c *check) Validate(engine types.Engine, event atmos.Event) bool {
+	return c.fn(engine.(*atmos.Engine), event)
+}
+
+// listenerFunc adapts a function to atmos.EventListener
+type listenerFunc func(engine *atmos.Engine, event atmos.Event)
+
+func (f listenerFunc) Handle(engine types.Engine, event atmos.Event) {
+	f(engine.(*atmos.Engine), event)
+}
+
+// newRegistry returns components for a small game, recording audited event types
+func newRegistry(audit *[]string) *atmosconfig.Registry {
+	registry := atmosconfig.NewRegistry()
+	registry.Event("move", func() atmos.Event { return &MoveEvent{} })
+	registry.Validator("not_over", &check{func(e *atmos.Engine, event atmos.Event) bool {
+		return !e.GetState("over").(bool)
+	}})
+	registry.Validator("own_piece", &check{func(e *atmos.Engine, event atmos.Event) bool {
+		return e.Actor() == event.(MoveEvent).Player
+	}})
+	registry.Validator("not_stalling", atmos.Because(&check{func(e *atmos.Engine, event atmos.Event) bool {
+		return e.GetState("moves").(int) < 2
+	}}, "the game is dragging on"))
+	registry.Listener("audit", listenerFunc(func(e *atmos.Engine, event atmos.Event) {
+		*audit = append(*audit, event.Type())
+	}))
+	registry.Listener("end_game_after_three", listenerFunc(func(e *atmos.Engine, event atmos.Event) {
+		if e.GetState("moves").(int) == 3 {
+			e.Emit(GameOverEvent{})
+		}
+	}))
+	registry.Reducer("count", func(e *atmos.Engine, state interface{}, event atmos.Event) interface{} {
+		return state.(int) + 1
+	})
+	registry.Reducer("set_true", func(e *atmos.Engine, state interface{}, event atmos.Event) interface{} {
+		return true
+	})
+	registry.Condition("is_tutorial", func(e *atmos.Engine, event atmos.Event) bool {
+		return event.(MoveEvent).Tutorial
+	})
+	return registry
+}
+
+// TestApplyWiresEngine verifies a YAML config wires validators, listeners, reducers, and exceptions
+func TestApplyWiresEngine(t *testing.T) {
+	config, err := atmosconfig.LoadFile("testdata/rules.yaml")
+	require.NoError(t, err)
+
+	var audit []string
+	engine := atmos.NewEngine()
+	engine.RegisterState("moves", 0)
+	engine.RegisterState("over", false)
+	require.NoError(t, config.Apply(engine, newRegistry(&audit)))
+
+	assert.True(t, engine.EmitAs("alice", MoveEvent{Player: "alice"}))
+	assert.False(t, engine.EmitAs("bob", MoveEvent{Player: "alice"}), "own_piece applies")
+	assert.True(t, engine.EmitAs("bob", MoveEvent{Player: "alice", Tutorial: true}), "The exception applies")
+
+	explanation := engine.ExplainAs("carol", MoveEvent{Player: "carol"})
+	assert.Equal(t, "the game is dragging on", explanation.Warnings[0].Reason)
+	assert.True(t, engine.EmitAs("carol", MoveEvent{Player: "carol"}))
+
+	assert.True(t, engine.GetState("over").(bool))
+	assert.False(t, engine.EmitAs("alice", MoveEvent{Player: "alice"}), "The policy now rejects moves")
+	assert.Equal(t, []string{"move", "move", "move", "game_over"}, audit)
+	assert.Equal(t, []string{"game_over"}, engine.DeclaredEmits("move"))
+
+	event, err := engine.UnmarshalEvent([]byte(`{"type":"move","data":{"player":"dave"}}`))
+	require.NoError(t, err, "Factories are registered for configured events")
+	assert.Equal(t, "dave", event.(*MoveEvent).Player)
+}
+
+// TestApplyReportsUnknownNames verifies a bad config is rejected without wiring anything
+func TestApplyReportsUnknownNames(t *testing.T) {
+	config, err := atmosconfig.Load([]byte(`{
+		"policies": {"ready": "is_ready"},
+		"events": {"move": {"requires": ["own_piece", "typo"], "then": ["nobody"], "updates": {"score": "count"}}}
+	}`))
+	require.NoError(t, err)
+
+	var audit []string
+	engine := atmos.NewEngine()
+	err = config.Apply(engine, newRegistry(&audit))
+	assert.EqualError(t, err, `atmosconfig: policy ready: unknown validator "is_ready"
+atmosconfig: move: unknown validator "typo"
+atmosconfig: move: unknown listener "nobody"
+atmosconfig: move: unknown state "score"`)
+	assert.True(t, engine.Emit(MoveEvent{}), "Nothing was wired")
+
+	_, err = atmosconfig.Load([]byte("events: [1, 2"))
+	assert.Error(t, err)
+}
+
+// TestLoadRejectsUnknownKeys verifies misspelled keys are reported in YAML and JSON
+func TestLoadRejectsUnknownKeys(t *testing.T) {
+	_, err := atmosconfig.Load([]byte("events:\n  move:\n    require: [own_piece]\n"))
+	assert.ErrorContains(t, err, "field require not found")
+
+	_, err = atmosconfig.Load([]byte(`{"events": {"move": {"require": ["own_piece"]}}}`))
+	assert.ErrorContains(t, err, `unknown field "require"`)
+
+	config, err := atmosconfig.Load(nil)
+	require.NoError(t, err, "An empty config is fine")
+	assert.Empty(t, config.Events)
+}
@@ -0,0 +1,318 @@
+// Package atmosconsole is a line-oriented debug console for a live engine:
+// emit events, query states, explain decisions, and tail the log. It reads
+// commands from any io.Reader, so it can run on stdin during development or
+// on a connection during an incident:
+//
+//	console := atmosconsole.New(engine, atmosconsole.WithLocker(handler))
+//	console.Run(os.Stdin, os.Stdout)
+//
+// Type "help" for the command list. Event payloads are JSON and are decoded
+// with the engine's registered event factories.
+package atmosconsole
+
+import (
+	"bufio"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+	"sync"
+
+	"github.com/cumulusrpg/atmos"
+	"github.com/cumulusrpg/atmos/types"
+)
+
+const help = `commands:
+  emit <type> [json]          emit an event
+  as <actor> <type> [json]    emit an event on behalf of an actor
+  explain <type> [json]       show how validators treat an event, without emitting
+  state <name>                print a projected state
+  states                      list state names
+  log [n]                     print the last n events (default 10)
+  tail on|off                 print events as they commit
+  help                        show this list
+  quit                        leave the console
+`
+
+// Console runs debug commands against an engine
+type Console struct {
+	engine *atmos.Engine
+	locker sync.Locker
+	prompt string
+
+	outMu   sync.Mutex
+	out     io.Writer
+	tailing bool
+}
+
+// Option configures console construction
+type Option func(*Console)
+
+// WithLocker serializes engine access with other users of the engine, such
+// as an atmoshttp.Handler (default: a lock private to the console)
+func WithLocker(locker sync.Locker) Option {
+	return func(c *Console) {
+		c.locker = locker
+	}
+}
+
+// WithPrompt sets the prompt printed before each command (default "atmos> ")
+func WithPrompt(prompt string) Option {
+	return func(c *Console) {
+		c.prompt = prompt
+	}
+}
+
+// New creates a console for an engine. It registers a listener on every
+// event type to support tailing.
+func New(engine *atmos.Engine, opts ...Option) *Console {
+	c := &Console{
+		engine: engine,
+		locker: &sync.Mutex{},
+		prompt: "atmos> ",
+	}
+
+	// Apply options
+	for _, opt := range opts {
+		opt(c)
+	}
+
+	c.locker.Lock()
+	engine.ThenAll(tailListener{console: c})
+	c.locker.Unlock()
+	return c
+}
+
+// Run executes commands from in until it is exhausted or "quit" is entered
+func (c *Console) Run(in io.Reader, out io.Writer) error {
+	c.outMu.Lock()
+	c.out = out
+	c.outMu.Unlock()
+	defer func() {
+		c.outMu.Lock()
+		c.out, c.tailing = nil, false
+		c.outMu.Unlock()
+	}()
+
+	scanner := bufio.NewScanner(in)
+	scanner.Buffer(nil, 1<<20)
+	for {
+		c.printf("%s", c.prompt)
+		if !scanner.Scan() {
+			c.printf("\n")
+			return scanner.Err()
+		}
+
+		line := strings.TrimSpace(scanner.Text())
+		if line == "quit" || line == "exit" {
+			return nil
+		}
+		if line == "" {
+			continue
+		}
+		if err := c.Exec(line); err != nil {
+			c.printf("error: %v\n", err)
+		}
+	}
+}
+
+// Exec runs a single command line
+func (c *Console) Exec(line string) error {
+	command, rest := cut(line)
+	switch command {
+	case "help":
+		c.printf("%s", help)
+		return nil
+	case "emit":
+		return c.emit("", rest)
+	case "as":
+		actor, rest := cut(rest)
+		if actor == "" {
+			return errors.New("usage: as <actor> <type> [json]")
+		}
+		return c.emit(actor, rest)
+	case "explain":
+		return c.explain(rest)
+	case "state":
+		return c.state(rest)
+	case "states":
+		c.locker.Lock()
+		names := c.engine.StateNames()
+		c.locker.Unlock()
+		c.printf("%s\n", strings.Join(names, "\n"))
+		return nil
+	case "log":
+		return c.log(rest)
+	case "tail":
+		return c.tail(rest)
+	default:
+		return fmt.Errorf("unknown command %q (try help)", command)
+	}
+}
+
+// emit decodes and emits an event, reporting the outcome
+func (c *Console) emit(actor, args string) error {
+	event, err := c.decode(args)
+	if err != nil {
+		return err
+	}
+
+	c.locker.Lock()
+	result := c.engine.EmitAsWithResult(actor, event)
+	sequence := len(c.engine.GetEvents()) - 1
+	c.locker.Unlock()
+
+	switch {
+	case result.Err != nil:
+		return result.Err
+	case !result.Accepted:
+		c.printf("rejected by %s", result.RejectedBy)
+		if result.Reason != "" {
+			c.printf(": %s", result.Reason)
+		}
+		c.printf("\n")
+	default:
+		c.printf("accepted #%d\n", sequence)
+		for _, warning := range result.Warnings {
+			c.printf("  warn %s: %s\n", warning.Validator, warning.Reason)
+		}
+	}
+	return nil
+}
+
+// explain prints the explanation for an event
+func (c *Console) explain(args string) error {
+	event, err := c.decode(args)
+	if err != nil {
+		return err
+	}
+
+	c.locker.Lock()
+	explanation := c.engine.Explain(event)
+	c.locker.Unlock()
+
+	c.printf("%s", explanation)
+	return nil
+}
+
+// state prints a projected state as JSON
+func (c *Console) state(name string) error {
+	c.locker.Lock()
+	defer c.locker.Unlock()
+
+	for _, known := range c.engine.StateNames() {
+		if known == name {
+			return c.printJSON(c.engine.GetState(name))
+		}
+	}
+	return fmt.Errorf("unknown state %q", name)
+}
+
+// log prints the last n events
+func (c *Console) log(args string) error {
+	n := 10
+	if args != "" {
+		parsed, err := strconv.Atoi(args)
+		if err != nil || parsed < 0 {
+			return errors.New("usage: log [n]")
+		}
+		n = parsed
+	}
+
+	c.locker.Lock()
+	events := c.engine.GetEvents()
+	c.locker.Unlock()
+
+	start := len(events) - n
+	if start < 0 {
+		start = 0
+	}
+	for i := start; i < len(events); i++ {
+		c.printEvent(i, events[i])
+	}
+	return nil
+}
+
+// tail toggles printing of committed events
+func (c *Console) tail(args string) error {
+	if args != "on" && args != "off" {
+		return errors.New("usage: tail on|off")
+	}
+	c.outMu.Lock()
+	c.tailing = args == "on"
+	c.outMu.Unlock()
+	return nil
+}
+
+// decode builds an event from "<type> [json]"
+func (c *Console) decode(args string) (atmos.Event, error) {
+	eventType, payload := cut(args)
+	if eventType == "" {
+		return nil, errors.New("missing event type")
+	}
+	if payload == "" {
+		payload = "{}"
+	}
+	if !json.Valid([]byte(payload)) {
+		return nil, fmt.Errorf("invalid JSON payload %s", payload)
+	}
+
+	c.locker.Lock()
+	defer c.locker.Unlock()
+	return c.engine.UnmarshalEvent([]byte(`{"type":` + strconv.Quote(eventType) + `,"data":` + payload + `}`))
+}
+
+// printEvent prints one log entry
+func (c *Console) printEvent(sequence int, event atmos.Event) {
+	data, err := json.Marshal(event)
+	if err != nil {
+		data = []byte(err.Error())
+	}
+	c.printf("#%d %s %s\n", sequence, event.Type(), data)
+}
+
+// printJSON prints a value as indented JSON
+func (c *Console) printJSON(value interface{}) error {
+	data, err := json.MarshalIndent(value, "", "  ")
+	if err != nil {
+		return err
+	}
+	c.printf("%s\n", data)
+	return nil
+}
+
+// printf writes to the attached output, if any
+func (c *Console) printf(format string, args ...interface{}) {
+	c.outMu.Lock()
+	defer c.outMu.Unlock()
+	if c.out != nil {
+		fmt.Fprintf(c.out, format, args...)
+	}
+}
+
+// tailListener prints committed events while tailing is on
+type tailListener struct {
+	console *Console
+}
+
+// Handle implements atmos.EventListener
+func (l tailListener) Handle(engine types.Engine, event atmos.Event) {
+	c := l.console
+	c.outMu.Lock()
+	tailing := c.tailing
+	c.outMu.Unlock()
+	if tailing {
+		c.printEvent(len(engine.(*atmos.Engine).GetEvents())-1, event)
+	}
+}
+
+// cut splits off the first whitespace-separated word
+func cut(s string) (string, string) {
+	s = strings.TrimSpace(s)
+	if i := strings.IndexAny(s, " \t"); i >= 0 {
+		return s[:i], strings.TrimSpace(s[i+1:])
+	}
+	return s, ""
+}
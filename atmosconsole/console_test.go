@@ -0,0 +1,115 @@
+package atmosconsole_test
+
+import (
+	"bytes"
+	"io"
+	"strings"
+	"testing"
+
+	"github.com/cumulusrpg/atmos"
+	"github.com/cumulusrpg/atmos/atmosconsole"
+	"github.com/cumulusrpg/atmos/types"
+	"github.com/stretchr/testify/assert"
+)
+
+type DepositEvent struct {
+	Amount int `json:"amount"`
+}
+
+func (e *DepositEvent) Type() string { return "deposit" }
+
+// positive rejects non-positive deposits
+type positive struct{}
+
+func (positive) Validate(engine types.Engine, event atmos.Event) bool {
+	return event.(*DepositEvent).Amount > 0
+}
+
+// newBank returns an engine with a balance and a deposit rule
+func newBank() *atmos.Engine {
+	engine := atmos.NewEngine()
+	engine.RegisterEventTypes(&DepositEvent{})
+	engine.RegisterState("balance", 0)
+	engine.When("deposit").
+		Requires(atmos.Because(positive{}, "deposits must be positive")).
+		Updates("balance", func(e *atmos.Engine, state interface{}, event atmos.Event) interface{} {
+			return state.(int) + event.(*DepositEvent).Amount
+		})
+	return engine
+}
+
+// TestConsoleSession verifies a scripted session of emits, explains, and queries
+func TestConsoleSession(t *testing.T) {
+	engine := newBank()
+	console := atmosconsole.New(engine, atmosconsole.WithPrompt(""))
+
+	script := strings.Join([]string{
+		`emit deposit {"amount": 5}`,
+		`as alice deposit {"amount": -1}`,
+		`explain deposit {"amount": 0}`,
+		`state balance`,
+		`states`,
+		`log 1`,
+		`emit withdraw {}`,
+		`frobnicate`,
+		`quit`,
+		`emit deposit {"amount": 100}`,
+	}, "\n")
+
+	var out bytes.Buffer
+	assert.NoError(t, console.Run(strings.NewReader(script), &out))
+	assert.Equal(t, `accepted #0
+rejected by atmosconsole_test.positive: deposits must be positive
+deposit: rejected
+  FAIL atmosconsole_test.positive: deposits must be positive
+5
+balance
+#0 deposit {"amount":5}
+error: unknown event type: withdraw
+error: unknown command "frobnicate" (try help)
+`, out.String())
+	assert.Equal(t, 5, engine.GetState("balance"), "Commands after quit are not run")
+}
+
+// TestConsoleTail verifies events emitted elsewhere are printed while tailing
+func TestConsoleTail(t *testing.T) {
+	engine := newBank()
+	console := atmosconsole.New(engine, atmosconsole.WithPrompt(""))
+
+	var out bytes.Buffer
+	reader, writer := pipe()
+	done := make(chan error)
+	go func() { done <- console.Run(reader, &out) }()
+
+	writer <- "tail on"
+	writer <- "help" // wait for tail on to be processed
+	engine.Emit(&DepositEvent{Amount: 7})
+	writer <- "tail off"
+	writer <- "help"
+	engine.Emit(&DepositEvent{Amount: 8})
+	close(writer)
+
+	assert.NoError(t, <-done)
+	assert.Equal(t, 1, strings.Count(out.String(), "#0 deposit {\"amount\":7}\n"))
+	assert.NotContains(t, out.String(), "amount\":8")
+}
+
+// lineReader delivers lines sent on a channel, one per Read, so a test knows
+// each line was consumed before the next is sent
+type lineReader struct {
+	lines chan string
+}
+
+func (r lineReader) Read(p []byte) (int, error) {
+	line, ok := <-r.lines
+	if !ok {
+		return 0, io.EOF
+	}
+	return copy(p, line+"\n"), nil
+}
+
+// pipe returns a reader fed by the returned channel
+func pipe() (io.Reader, chan<- string) {
+	lines := make(chan string)
+	return lineReader{lines: lines}, lines
+}
@@ -0,0 +1,193 @@
+// Package atmosdevtools serves a small web UI for inspecting a running
+// engine: the event log, how validators judged each event, projected states
+// at any point in the log, and the registration graph.
+//
+//	mux.Handle("/debug/atmos/", http.StripPrefix("/debug/atmos", atmosdevtools.NewHandler(engine)))
+//
+// It is meant for development builds; it exposes every event and state.
+//
+//	GET /                          the UI
+//	GET /api/events                the event log
+//	GET /api/events/{seq}/explain  validator decisions for an event, against the state before it
+//	GET /api/states?at=N           every projected state after event N (default: now)
+//	GET /api/graph                 the registration graph (atmos.Engine.Describe)
+package atmosdevtools
+
+import (
+	_ "embed"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"strconv"
+	"sync"
+
+	"github.com/cumulusrpg/atmos"
+)
+
+//go:embed index.html
+var indexHTML []byte
+
+// Handler serves the devtools UI and its JSON API
+type Handler struct {
+	engine *atmos.Engine
+	locker sync.Locker
+	mux    *http.ServeMux
+}
+
+// Option configures handler construction
+type Option func(*Handler)
+
+// WithLocker serializes engine access with other users of the engine, such
+// as an atmoshttp.Handler (default: a lock private to the devtools)
+func WithLocker(locker sync.Locker) Option {
+	return func(h *Handler) {
+		h.locker = locker
+	}
+}
+
+// NewHandler creates a devtools handler for an engine
+func NewHandler(engine *atmos.Engine, opts ...Option) *Handler {
+	h := &Handler{
+		engine: engine,
+		locker: &sync.Mutex{},
+		mux:    http.NewServeMux(),
+	}
+
+	// Apply options
+	for _, opt := range opts {
+		opt(h)
+	}
+
+	h.mux.HandleFunc("GET /{$}", h.getIndex)
+	h.mux.HandleFunc("GET /api/events", h.getEvents)
+	h.mux.HandleFunc("GET /api/events/{seq}/explain", h.getExplain)
+	h.mux.HandleFunc("GET /api/states", h.getStates)
+	h.mux.HandleFunc("GET /api/graph", h.getGraph)
+
+	return h
+}
+
+// ServeHTTP implements http.Handler
+func (h *Handler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	h.mux.ServeHTTP(w, r)
+}
+
+// LogEntry is one event in the log
+type LogEntry struct {
+	Sequence int         `json:"sequence"`
+	Type     string      `json:"type"`
+	Data     atmos.Event `json:"data"`
+}
+
+// Decision is how one validator judged an event
+type Decision struct {
+	Validator string `json:"validator"`
+	Passed    bool   `json:"passed"`
+	Skipped   bool   `json:"skipped,omitempty"`
+	Reason    string `json:"reason,omitempty"`
+}
+
+// Decisions is the explanation of an event
+type Decisions struct {
+	Sequence   int             `json:"sequence"`
+	Type       string          `json:"type"`
+	Accepted   bool            `json:"accepted"`
+	Validators []Decision      `json:"validators"`
+	Warnings   []atmos.Warning `json:"warnings,omitempty"`
+}
+
+func (h *Handler) getIndex(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "text/html; charset=utf-8")
+	w.Write(indexHTML)
+}
+
+func (h *Handler) getEvents(w http.ResponseWriter, r *http.Request) {
+	h.locker.Lock()
+	events := h.engine.GetEvents()
+	h.locker.Unlock()
+
+	entries := make([]LogEntry, len(events))
+	for i, event := range events {
+		entries[i] = LogEntry{Sequence: i, Type: event.Type(), Data: event}
+	}
+	writeJSON(w, http.StatusOK, entries)
+}
+
+// getExplain re-runs an event's validators against the state just before it
+// was committed (the log is replayed into a fork; the engine is untouched)
+func (h *Handler) getExplain(w http.ResponseWriter, r *http.Request) {
+	h.locker.Lock()
+	defer h.locker.Unlock()
+
+	events := h.engine.GetEvents()
+	seq, err := strconv.Atoi(r.PathValue("seq"))
+	if err != nil || seq < 0 || seq >= len(events) {
+		writeError(w, http.StatusNotFound, errors.New("no event at that sequence"))
+		return
+	}
+
+	fork := h.engine.Fork()
+	fork.SetEvents(events[:seq])
+	explanation := fork.Explain(events[seq])
+
+	decisions := Decisions{
+		Sequence:   seq,
+		Type:       explanation.EventType,
+		Accepted:   explanation.Accepted,
+		Validators: make([]Decision, len(explanation.Validators)),
+		Warnings:   explanation.Warnings,
+	}
+	for i, report := range explanation.Validators {
+		decisions.Validators[i] = Decision{
+			Validator: report.Name,
+			Passed:    report.Passed,
+			Skipped:   report.Skipped,
+			Reason:    report.Reason,
+		}
+	}
+	writeJSON(w, http.StatusOK, decisions)
+}
+
+// getStates projects every state, optionally as of an earlier sequence
+func (h *Handler) getStates(w http.ResponseWriter, r *http.Request) {
+	h.locker.Lock()
+	defer h.locker.Unlock()
+
+	engine := h.engine
+	if raw := r.URL.Query().Get("at"); raw != "" {
+		events := engine.GetEvents()
+		at, err := strconv.Atoi(raw)
+		if err != nil || at < -1 || at >= len(events) {
+			writeError(w, http.StatusBadRequest, errors.New("at must be a sequence in the log"))
+			return
+		}
+		engine = engine.Fork()
+		engine.SetEvents(events[:at+1])
+	}
+
+	states := make(map[string]interface{})
+	for _, name := range engine.StateNames() {
+		states[name] = engine.GetState(name)
+	}
+	writeJSON(w, http.StatusOK, states)
+}
+
+func (h *Handler) getGraph(w http.ResponseWriter, r *http.Request) {
+	h.locker.Lock()
+	graph := h.engine.Describe()
+	h.locker.Unlock()
+
+	writeJSON(w, http.StatusOK, graph)
+}
+
+// writeJSON writes a JSON response
+func writeJSON(w http.ResponseWriter, status int, value interface{}) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	json.NewEncoder(w).Encode(value)
+}
+
+// writeError writes a JSON error response
+func writeError(w http.ResponseWriter, status int, err error) {
+	writeJSON(w, status, map[string]string{"error": err.Error()})
+}
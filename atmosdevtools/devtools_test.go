@@ -0,0 +1,108 @@
+package atmosdevtools_test
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/cumulusrpg/atmos"
+	"github.com/cumulusrpg/atmos/atmosdevtools"
+	"github.com/cumulusrpg/atmos/types"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type WithdrawEvent struct {
+	Amount int `json:"amount"`
+}
+
+func (e WithdrawEvent) Type() string { return "withdraw" }
+
+type DepositEvent struct {
+	Amount int `json:"amount"`
+}
+
+func (e DepositEvent) Type() string { return "deposit" }
+
+// sufficientFunds rejects withdrawals larger than the balance
+type sufficientFunds struct{}
+
+func (sufficientFunds) Validate(engine types.Engine, event atmos.Event) bool {
+	return engine.(*atmos.Engine).GetState("balance").(int) >= event.(WithdrawEvent).Amount
+}
+
+// newServer serves devtools for a bank with a deposit of 10 and a withdrawal of 4
+func newServer(t *testing.T) *httptest.Server {
+	engine := atmos.NewEngine()
+	engine.RegisterState("balance", 0)
+	engine.When("deposit").Updates("balance", func(e *atmos.Engine, state interface{}, event atmos.Event) interface{} {
+		return state.(int) + event.(DepositEvent).Amount
+	})
+	engine.When("withdraw").
+		Requires(atmos.Because(sufficientFunds{}, "insufficient funds")).
+		Updates("balance", func(e *atmos.Engine, state interface{}, event atmos.Event) interface{} {
+			return state.(int) - event.(WithdrawEvent).Amount
+		})
+	require.True(t, engine.Emit(DepositEvent{Amount: 10}))
+	require.True(t, engine.Emit(WithdrawEvent{Amount: 4}))
+
+	mux := http.NewServeMux()
+	mux.Handle("/debug/atmos/", http.StripPrefix("/debug/atmos", atmosdevtools.NewHandler(engine)))
+	server := httptest.NewServer(mux)
+	t.Cleanup(server.Close)
+	return server
+}
+
+// get fetches a path and decodes its JSON body
+func get(t *testing.T, server *httptest.Server, path string, value interface{}) int {
+	resp, err := http.Get(server.URL + "/debug/atmos" + path)
+	require.NoError(t, err)
+	defer resp.Body.Close()
+	if value != nil {
+		require.NoError(t, json.NewDecoder(resp.Body).Decode(value))
+	}
+	return resp.StatusCode
+}
+
+// TestDevtoolsAPI verifies the log, per-event decisions, historical states, and graph
+func TestDevtoolsAPI(t *testing.T) {
+	server := newServer(t)
+
+	var log []map[string]interface{}
+	assert.Equal(t, http.StatusOK, get(t, server, "/api/events", &log))
+	assert.Len(t, log, 2)
+	assert.Equal(t, "withdraw", log[1]["type"])
+
+	var decisions atmosdevtools.Decisions
+	get(t, server, "/api/events/1/explain", &decisions)
+	assert.True(t, decisions.Accepted, "Judged against the balance of 10 before the withdrawal")
+	assert.Equal(t, []atmosdevtools.Decision{{Validator: "atmosdevtools_test.sufficientFunds", Passed: true}}, decisions.Validators)
+
+	var states map[string]int
+	get(t, server, "/api/states?at=0", &states)
+	assert.Equal(t, map[string]int{"balance": 10}, states)
+	get(t, server, "/api/states", &states)
+	assert.Equal(t, map[string]int{"balance": 6}, states)
+	get(t, server, "/api/states?at=-1", &states)
+	assert.Equal(t, map[string]int{"balance": 0}, states)
+
+	var graph []atmos.EventDescription
+	get(t, server, "/api/graph", &graph)
+	assert.Equal(t, "withdraw", graph[1].EventType)
+	assert.Equal(t, []string{"balance"}, graph[1].States)
+
+	assert.Equal(t, http.StatusNotFound, get(t, server, "/api/events/9/explain", nil))
+	assert.Equal(t, http.StatusBadRequest, get(t, server, "/api/states?at=x", nil))
+}
+
+// TestDevtoolsServesUI verifies the page is served at the mount point
+func TestDevtoolsServesUI(t *testing.T) {
+	server := newServer(t)
+
+	resp, err := http.Get(server.URL + "/debug/atmos/")
+	require.NoError(t, err)
+	defer resp.Body.Close()
+	assert.Equal(t, http.StatusOK, resp.StatusCode)
+	assert.Contains(t, resp.Header.Get("Content-Type"), "text/html")
+}
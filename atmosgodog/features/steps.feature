@@ -0,0 +1,52 @@
+Feature: Reusable engine steps
+  As a developer writing BDD scenarios for a game
+  I want ready-made steps for events and state
+  So that I only write steps specific to my game
+
+  Scenario: Seed history and emit new events
+    Given the following events have occurred:
+      | type  | player | points |
+      | score | alice  | 10     |
+      | score | bob    | 5      |
+    When I emit a "score" event with:
+      | field  | value |
+      | player | alice |
+      | points | 7     |
+    Then the last event should be accepted
+    And 3 "score" events should have been emitted
+    And the "scores" state should have:
+      | field        | value |
+      | totals.alice | 17    |
+      | totals.bob   | 5     |
+
+  Scenario: Rejected events are reported
+    When I emit a "score" event with:
+      | field  | value |
+      | player | carol |
+      | points | -1    |
+    Then the last event should be rejected by "PositivePoints"
+    And no "score" event should have been emitted
+
+  Scenario: Emit events from a table and compare whole state
+    When I emit the following events:
+      | type  | player | points |
+      | score | dave   | 1      |
+      | reset |        |        |
+    Then a "reset" event should have been emitted
+    And the "scores" state should equal:
+      """
+      {"totals": {}}
+      """
+
+  Scenario: Seed a snapshot
+    Given a snapshot of the "scores" state with:
+      | field  | value          |
+      | totals | {"erin": 40}   |
+    When I emit a "score" event with:
+      | field  | value |
+      | player | erin  |
+      | points | 2     |
+    Then the snapshot for "scores" should exist
+    And the "scores" state should have:
+      | field       | value |
+      | totals.erin | 42    |
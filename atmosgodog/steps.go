@@ -0,0 +1,410 @@
+// Package atmosgodog is a reusable godog step library for writing Gherkin
+// scenarios against atmos engines without redefining common steps.
+//
+//	func InitializeScenario(sc *godog.ScenarioContext) {
+//		steps := atmosgodog.New(newGameEngine)
+//		steps.Register(sc)
+//		// Game-specific steps can use steps.Engine()
+//	}
+//
+// Events are built from tables through the engine's registered event types:
+// the "type" column names the event and every other column is a JSON field.
+//
+//	Given the following events have occurred:
+//	  | type  | player | points |
+//	  | score | alice  | 10     |
+//	When I emit a "score" event with:
+//	  | field  | value |
+//	  | player | bob   |
+//	Then the last event should be accepted
+//	And the "game" state should have:
+//	  | field         | value |
+//	  | scores.alice  | 10    |
+package atmosgodog
+
+import (
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/cucumber/godog"
+	"github.com/cumulusrpg/atmos"
+)
+
+// Steps holds the engine and results shared by the steps of one scenario
+type Steps struct {
+	engine      *atmos.Engine
+	explanation *atmos.Explanation // Explanation of the last emitted event
+	accepted    bool
+}
+
+// New creates the steps for one scenario around a fresh engine. Call it from
+// the godog scenario initializer so every scenario starts clean.
+func New(newEngine func() *atmos.Engine) *Steps {
+	return &Steps{engine: newEngine()}
+}
+
+// Engine returns the scenario's engine, for application-specific steps
+func (s *Steps) Engine() *atmos.Engine {
+	return s.engine
+}
+
+// Register adds the library's steps to a scenario
+func (s *Steps) Register(sc *godog.ScenarioContext) {
+	// Seeding
+	sc.Step(`^the following events have occurred:$`, s.theFollowingEventsHaveOccurred)
+	sc.Step(`^a snapshot of the "([^"]*)" state with:$`, s.aSnapshotOfTheStateWith)
+
+	// Emitting
+	sc.Step(`^I emit the following events:$`, s.iEmitTheFollowingEvents)
+	sc.Step(`^I emit a "([^"]*)" event$`, s.iEmitAnEvent)
+	sc.Step(`^I emit a "([^"]*)" event with:$`, s.iEmitAnEventWith)
+
+	// Assertions
+	sc.Step(`^the last event should be accepted$`, s.theLastEventShouldBeAccepted)
+	sc.Step(`^the last event should be rejected$`, s.theLastEventShouldBeRejected)
+	sc.Step(`^the last event should be rejected by "([^"]*)"$`, s.theLastEventShouldBeRejectedBy)
+	sc.Step(`^a "([^"]*)" event should have been emitted$`, s.anEventShouldHaveBeenEmitted)
+	sc.Step(`^no "([^"]*)" event should have been emitted$`, s.noEventShouldHaveBeenEmitted)
+	sc.Step(`^(\d+) "([^"]*)" events? should have been emitted$`, s.eventsShouldHaveBeenEmitted)
+	sc.Step(`^the "([^"]*)" state should have:$`, s.theStateShouldHave)
+	sc.Step(`^the "([^"]*)" state should equal:$`, s.theStateShouldEqual)
+	sc.Step(`^the snapshot for "([^"]*)" should exist$`, s.theSnapshotShouldExist)
+	sc.Step(`^the snapshot for "([^"]*)" should not exist$`, s.theSnapshotShouldNotExist)
+}
+
+// =============================================================================
+// Seeding
+// =============================================================================
+
+func (s *Steps) theFollowingEventsHaveOccurred(table *godog.Table) error {
+	events, err := s.eventsFromTable(table)
+	if err != nil {
+		return err
+	}
+	s.engine.SetEvents(append(s.engine.GetEvents(), events...))
+	return nil
+}
+
+func (s *Steps) aSnapshotOfTheStateWith(stateName string, table *godog.Table) error {
+	fields, err := fieldsFromTable(table)
+	if err != nil {
+		return err
+	}
+	return s.engine.SetSnapshot(stateName, fields)
+}
+
+// =============================================================================
+// Emitting
+// =============================================================================
+
+func (s *Steps) iEmitTheFollowingEvents(table *godog.Table) error {
+	events, err := s.eventsFromTable(table)
+	if err != nil {
+		return err
+	}
+	for _, event := range events {
+		s.emit(event)
+	}
+	return nil
+}
+
+func (s *Steps) iEmitAnEvent(eventType string) error {
+	event, err := s.buildEvent(eventType, map[string]interface{}{})
+	if err != nil {
+		return err
+	}
+	s.emit(event)
+	return nil
+}
+
+func (s *Steps) iEmitAnEventWith(eventType string, table *godog.Table) error {
+	fields, err := fieldsFromTable(table)
+	if err != nil {
+		return err
+	}
+	event, err := s.buildEvent(eventType, fields)
+	if err != nil {
+		return err
+	}
+	s.emit(event)
+	return nil
+}
+
+// emit explains and emits an event, recording the outcome
+func (s *Steps) emit(event atmos.Event) {
+	explanation := s.engine.Explain(event)
+	s.explanation = &explanation
+	s.accepted = s.engine.Emit(event)
+}
+
+// =============================================================================
+// Assertions
+// =============================================================================
+
+func (s *Steps) theLastEventShouldBeAccepted() error {
+	if s.explanation == nil {
+		return fmt.Errorf("no event has been emitted")
+	}
+	if !s.accepted {
+		return fmt.Errorf("expected the last event to be accepted:\n%s", s.explanation)
+	}
+	return nil
+}
+
+func (s *Steps) theLastEventShouldBeRejected() error {
+	if s.explanation == nil {
+		return fmt.Errorf("no event has been emitted")
+	}
+	if s.accepted {
+		return fmt.Errorf("expected the last event to be rejected:\n%s", s.explanation)
+	}
+	return nil
+}
+
+func (s *Steps) theLastEventShouldBeRejectedBy(name string) error {
+	if err := s.theLastEventShouldBeRejected(); err != nil {
+		return err
+	}
+	failure := s.explanation.FirstFailure
+	if failure == nil || !strings.Contains(failure.Name, name) {
+		return fmt.Errorf("expected the last event to be rejected by %q:\n%s", name, s.explanation)
+	}
+	return nil
+}
+
+func (s *Steps) anEventShouldHaveBeenEmitted(eventType string) error {
+	if s.count(eventType) == 0 {
+		return fmt.Errorf("expected a %q event to have been emitted", eventType)
+	}
+	return nil
+}
+
+func (s *Steps) noEventShouldHaveBeenEmitted(eventType string) error {
+	if n := s.count(eventType); n > 0 {
+		return fmt.Errorf("expected no %q events, got %d", eventType, n)
+	}
+	return nil
+}
+
+func (s *Steps) eventsShouldHaveBeenEmitted(want int, eventType string) error {
+	if n := s.count(eventType); n != want {
+		return fmt.Errorf("expected %d %q events, got %d", want, eventType, n)
+	}
+	return nil
+}
+
+func (s *Steps) theStateShouldHave(stateName string, table *godog.Table) error {
+	state, err := s.stateAsJSON(stateName)
+	if err != nil {
+		return err
+	}
+	expected, err := fieldsFromTable(table)
+	if err != nil {
+		return err
+	}
+
+	for field, expectedValue := range expected {
+		actualValue, exists := lookup(state, field)
+		if !exists {
+			return fmt.Errorf("field %q not found in %q state", field, stateName)
+		}
+
+		expectedJSON, _ := json.Marshal(expectedValue)
+		actualJSON, _ := json.Marshal(actualValue)
+		if string(expectedJSON) != string(actualJSON) {
+			return fmt.Errorf("field %q: expected %s, got %s", field, expectedJSON, actualJSON)
+		}
+	}
+	return nil
+}
+
+func (s *Steps) theStateShouldEqual(stateName string, doc *godog.DocString) error {
+	state, err := s.stateAsJSON(stateName)
+	if err != nil {
+		return err
+	}
+
+	var expected interface{}
+	if err := json.Unmarshal([]byte(doc.Content), &expected); err != nil {
+		return fmt.Errorf("invalid expected JSON: %w", err)
+	}
+
+	expectedJSON, _ := json.Marshal(expected)
+	actualJSON, _ := json.Marshal(state)
+	if string(expectedJSON) != string(actualJSON) {
+		return fmt.Errorf("%q state: expected %s, got %s", stateName, expectedJSON, actualJSON)
+	}
+	return nil
+}
+
+func (s *Steps) theSnapshotShouldExist(stateName string) error {
+	if !s.engine.HasSnapshot(stateName) {
+		return fmt.Errorf("expected snapshot for %q to exist", stateName)
+	}
+	return nil
+}
+
+func (s *Steps) theSnapshotShouldNotExist(stateName string) error {
+	if s.engine.HasSnapshot(stateName) {
+		return fmt.Errorf("expected snapshot for %q to not exist", stateName)
+	}
+	return nil
+}
+
+// =============================================================================
+// Helper Functions
+// =============================================================================
+
+// count returns how many events of a type are in the log
+func (s *Steps) count(eventType string) int {
+	n := 0
+	for _, event := range s.engine.GetEvents() {
+		if event.Type() == eventType {
+			n++
+		}
+	}
+	return n
+}
+
+// buildEvent creates a registered event type from JSON fields
+func (s *Steps) buildEvent(eventType string, fields map[string]interface{}) (atmos.Event, error) {
+	data, err := json.Marshal(atmos.EventWrapper{Type: eventType, Data: fields})
+	if err != nil {
+		return nil, err
+	}
+	return s.engine.UnmarshalEvent(data)
+}
+
+// eventsFromTable builds events from a table with a "type" column and one column per field
+func (s *Steps) eventsFromTable(table *godog.Table) ([]atmos.Event, error) {
+	if len(table.Rows) == 0 {
+		return nil, nil
+	}
+
+	headers := table.Rows[0].Cells
+	typeIdx := -1
+	for i, cell := range headers {
+		if cell.Value == "type" {
+			typeIdx = i
+		}
+	}
+	if typeIdx == -1 {
+		return nil, fmt.Errorf("event table needs a \"type\" column")
+	}
+
+	var events []atmos.Event
+	for _, row := range table.Rows[1:] {
+		fields := make(map[string]interface{})
+		for i, cell := range row.Cells {
+			if i != typeIdx && cell.Value != "" {
+				fields[headers[i].Value] = parseValue(cell.Value)
+			}
+		}
+
+		event, err := s.buildEvent(row.Cells[typeIdx].Value, fields)
+		if err != nil {
+			return nil, err
+		}
+		events = append(events, event)
+	}
+	return events, nil
+}
+
+// stateAsJSON returns a state round-tripped through JSON, so fields are addressed by their JSON names
+func (s *Steps) stateAsJSON(stateName string) (interface{}, error) {
+	state := s.engine.GetState(stateName)
+	if state == nil {
+		return nil, fmt.Errorf("state %q not found", stateName)
+	}
+
+	data, err := json.Marshal(state)
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal %q state: %w", stateName, err)
+	}
+	var decoded interface{}
+	if err := json.Unmarshal(data, &decoded); err != nil {
+		return nil, err
+	}
+	return decoded, nil
+}
+
+// lookup follows a dotted path (e.g. "scores.alice" or "players.0") through decoded JSON
+func lookup(value interface{}, path string) (interface{}, bool) {
+	for _, key := range strings.Split(path, ".") {
+		switch v := value.(type) {
+		case map[string]interface{}:
+			next, exists := v[key]
+			if !exists {
+				return nil, false
+			}
+			value = next
+		case []interface{}:
+			i, err := strconv.Atoi(key)
+			if err != nil || i < 0 || i >= len(v) {
+				return nil, false
+			}
+			value = v[i]
+		default:
+			return nil, false
+		}
+	}
+	return value, true
+}
+
+// fieldsFromTable reads a two-column field/value table
+func fieldsFromTable(table *godog.Table) (map[string]interface{}, error) {
+	result := make(map[string]interface{})
+	if len(table.Rows) < 2 {
+		return result, nil
+	}
+
+	headers := table.Rows[0].Cells
+	fieldIdx := -1
+	valueIdx := -1
+	for i, cell := range headers {
+		switch cell.Value {
+		case "field":
+			fieldIdx = i
+		case "value":
+			valueIdx = i
+		}
+	}
+
+	if fieldIdx == -1 || valueIdx == -1 {
+		return nil, fmt.Errorf("table needs \"field\" and \"value\" columns")
+	}
+
+	for _, row := range table.Rows[1:] {
+		result[row.Cells[fieldIdx].Value] = parseValue(row.Cells[valueIdx].Value)
+	}
+	return result, nil
+}
+
+// parseValue converts a table cell to a bool, number, JSON value, or string
+func parseValue(value string) interface{} {
+	switch value {
+	case "":
+		return ""
+	case "true":
+		return true
+	case "false":
+		return false
+	case "null":
+		return nil
+	}
+	if i, err := strconv.Atoi(value); err == nil {
+		return i
+	}
+	if f, err := strconv.ParseFloat(value, 64); err == nil {
+		return f
+	}
+	if strings.HasPrefix(value, "{") || strings.HasPrefix(value, "[") || strings.HasPrefix(value, `"`) {
+		var decoded interface{}
+		if err := json.Unmarshal([]byte(value), &decoded); err == nil {
+			return decoded
+		}
+	}
+	return value
+}
@@ -0,0 +1,74 @@
+package atmosgodog_test
+
+import (
+	"testing"
+
+	"github.com/cucumber/godog"
+	"github.com/cumulusrpg/atmos"
+	"github.com/cumulusrpg/atmos/atmosgodog"
+	"github.com/cumulusrpg/atmos/repository"
+)
+
+type Scores struct {
+	Totals map[string]int `json:"totals"`
+}
+
+type ScoreEvent struct {
+	Player string `json:"player"`
+	Points int    `json:"points"`
+}
+
+func (e *ScoreEvent) Type() string { return "score" }
+
+type ResetEvent struct{}
+
+func (e *ResetEvent) Type() string { return "reset" }
+
+// PositivePoints rejects scores that are not positive
+type PositivePoints struct{}
+
+func (v *PositivePoints) ValidateTyped(engine *atmos.Engine, event *ScoreEvent) bool {
+	return event.Points > 0
+}
+
+func newScoreEngine() *atmos.Engine {
+	engine := atmos.NewEngine(atmos.WithRepository(repository.NewInMemorySnapshot()))
+	engine.RegisterState("scores", Scores{Totals: map[string]int{}})
+	engine.When("score", func() atmos.Event { return &ScoreEvent{} }).
+		Requires(atmos.NewTypedValidator[*ScoreEvent](&PositivePoints{})).
+		Updates("scores", func(e *atmos.Engine, state interface{}, event atmos.Event) interface{} {
+			s := state.(Scores)
+			totals := make(map[string]int, len(s.Totals))
+			for player, points := range s.Totals {
+				totals[player] = points
+			}
+			score := event.(*ScoreEvent)
+			totals[score.Player] += score.Points
+			return Scores{Totals: totals}
+		})
+	engine.When("reset", func() atmos.Event { return &ResetEvent{} }).
+		Updates("scores", func(e *atmos.Engine, state interface{}, event atmos.Event) interface{} {
+			return Scores{Totals: map[string]int{}}
+		})
+	return engine
+}
+
+func InitializeScenario(sc *godog.ScenarioContext) {
+	atmosgodog.New(newScoreEngine).Register(sc)
+}
+
+func TestFeatures(t *testing.T) {
+	suite := godog.TestSuite{
+		ScenarioInitializer: InitializeScenario,
+		Options: &godog.Options{
+			Format:   "pretty",
+			Paths:    []string{"features"},
+			TestingT: t,
+			Strict:   true, // fail on undefined or pending steps
+		},
+	}
+
+	if suite.Run() != 0 {
+		t.Fatal("non-zero status returned, failed to run feature tests")
+	}
+}
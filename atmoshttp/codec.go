@@ -0,0 +1,50 @@
+package atmoshttp
+
+import (
+	"encoding/json"
+	"io"
+)
+
+// Codec encodes and decodes request and response bodies
+type Codec interface {
+	// ContentType is sent as the Content-Type of every response
+	ContentType() string
+
+	// Encode writes a value to a response body
+	Encode(w io.Writer, v interface{}) error
+
+	// Decode reads a value from a request body
+	Decode(r io.Reader, v interface{}) error
+
+	// Marshal encodes a value to bytes (used to re-decode event payloads)
+	Marshal(v interface{}) ([]byte, error)
+
+	// Unmarshal decodes bytes into a value
+	Unmarshal(data []byte, v interface{}) error
+}
+
+// JSONCodec is the default codec, using encoding/json
+type JSONCodec struct{}
+
+// ContentType returns the JSON media type
+func (JSONCodec) ContentType() string { return "application/json" }
+
+// Encode writes v as JSON
+func (JSONCodec) Encode(w io.Writer, v interface{}) error {
+	return json.NewEncoder(w).Encode(v)
+}
+
+// Decode reads JSON into v
+func (JSONCodec) Decode(r io.Reader, v interface{}) error {
+	return json.NewDecoder(r).Decode(v)
+}
+
+// Marshal encodes v as JSON
+func (JSONCodec) Marshal(v interface{}) ([]byte, error) {
+	return json.Marshal(v)
+}
+
+// Unmarshal decodes JSON into v
+func (JSONCodec) Unmarshal(data []byte, v interface{}) error {
+	return json.Unmarshal(data, v)
+}
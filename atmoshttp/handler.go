@@ -0,0 +1,268 @@
+// Package atmoshttp exposes an atmos engine over HTTP so thin clients can
+// drive a server-hosted engine:
+//
+//	POST /events          emit an event ({"type": "...", "data": {...}})
+//	GET  /state/{name}    read a projected state
+//	GET  /events?since=N  read committed events starting at sequence N
+//
+// Emits answer 200 when committed, 202 when held (see atmos.Engine.Pause),
+// 422 when refused, 503 while the engine can't take events, and 500 when
+// the repository fails. Reads apply the engine's masks for the actor (see
+// atmos.Engine.GetStateFor and EventFor).
+//
+// Mount it under a prefix with http.StripPrefix:
+//
+//	mux.Handle("/game/", http.StripPrefix("/game", atmoshttp.NewHandler(engine)))
+package atmoshttp
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"strconv"
+	"sync"
+
+	"github.com/cumulusrpg/atmos"
+)
+
+// Authenticator identifies the actor behind a request.
+// Returning an error rejects the request with 401 Unauthorized.
+type Authenticator func(r *http.Request) (actor string, err error)
+
+// Anonymous is the default authenticator: every request has no actor
+func Anonymous(r *http.Request) (string, error) {
+	return "", nil
+}
+
+// Handler serves an engine over HTTP. Requests are serialized, so the
+// engine may be shared with other code that uses Lock/Unlock.
+type Handler struct {
+	mu     sync.Mutex
+	engine *atmos.Engine
+	auth   Authenticator
+	codec  Codec
+	mux    *http.ServeMux
+}
+
+// Option configures handler construction
+type Option func(*Handler)
+
+// WithAuthenticator sets how requests are mapped to actors (see atmos.Engine.EmitAs)
+func WithAuthenticator(auth Authenticator) Option {
+	return func(h *Handler) {
+		h.auth = auth
+	}
+}
+
+// WithCodec sets the wire format for request and response bodies
+func WithCodec(codec Codec) Option {
+	return func(h *Handler) {
+		h.codec = codec
+	}
+}
+
+// NewHandler creates an HTTP handler for an engine with optional configuration
+func NewHandler(engine *atmos.Engine, opts ...Option) *Handler {
+	h := &Handler{
+		engine: engine,
+		auth:   Anonymous,
+		codec:  JSONCodec{},
+		mux:    http.NewServeMux(),
+	}
+
+	// Apply options
+	for _, opt := range opts {
+		opt(h)
+	}
+
+	h.mux.HandleFunc("POST /events", h.postEvent)
+	h.mux.HandleFunc("GET /events", h.getEvents)
+	h.mux.HandleFunc("GET /state/{name}", h.getState)
+
+	return h
+}
+
+// ServeHTTP implements http.Handler
+func (h *Handler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	h.mux.ServeHTTP(w, r)
+}
+
+// Lock acquires exclusive access to the engine (the same lock requests use)
+func (h *Handler) Lock() { h.mu.Lock() }
+
+// Unlock releases exclusive access to the engine
+func (h *Handler) Unlock() { h.mu.Unlock() }
+
+// EventEnvelope is the wire shape of a single event
+type EventEnvelope struct {
+	Type string      `json:"type"`
+	Data interface{} `json:"data"`
+}
+
+// EmitResponse reports the outcome of POST /events
+type EmitResponse struct {
+	Accepted   bool   `json:"accepted"`
+	Sequence   int    `json:"sequence"`              // position of the event in the log, or -1 when not committed
+	RejectedBy string `json:"rejected_by,omitempty"` // first failing validator when rejected
+	Reason     string `json:"reason,omitempty"`      // the failing validator's reason, if it gave one
+}
+
+// EventsResponse is the body of GET /events
+type EventsResponse struct {
+	Events []EventEnvelope `json:"events"`
+	Next   int             `json:"next"` // sequence to pass as ?since= on the next poll
+}
+
+// ErrorResponse is the body of any non-2xx response
+type ErrorResponse struct {
+	Error string `json:"error"`
+}
+
+func (h *Handler) postEvent(w http.ResponseWriter, r *http.Request) {
+	actor, err := h.auth(r)
+	if err != nil {
+		h.writeError(w, http.StatusUnauthorized, err)
+		return
+	}
+
+	var envelope EventEnvelope
+	if err := h.codec.Decode(r.Body, &envelope); err != nil {
+		h.writeError(w, http.StatusBadRequest, err)
+		return
+	}
+
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	event, err := h.decodeEvent(envelope)
+	if err != nil {
+		h.writeError(w, http.StatusBadRequest, err)
+		return
+	}
+
+	result := h.engine.EmitAsWithResult(actor, event)
+	switch {
+	case result.Accepted:
+		// The emitted event follows any its before hooks committed
+		committed := len(h.engine.GetEvents()) - len(result.Events)
+		h.write(w, http.StatusOK, EmitResponse{Accepted: true, Sequence: committed + result.Position})
+	case result.Queued:
+		h.write(w, http.StatusAccepted, EmitResponse{Sequence: -1})
+	case result.StorageFailed():
+		h.writeError(w, http.StatusInternalServerError, result.Err)
+	case errors.Is(result.Err, atmos.ErrEngineClosed), errors.Is(result.Err, atmos.ErrReadOnly), errors.Is(result.Err, atmos.ErrPaused):
+		h.writeError(w, http.StatusServiceUnavailable, result.Err)
+	case result.Err != nil:
+		h.write(w, http.StatusUnprocessableEntity, EmitResponse{Sequence: -1, Reason: result.Err.Error()})
+	default:
+		h.write(w, http.StatusUnprocessableEntity, EmitResponse{
+			Sequence:   -1,
+			RejectedBy: result.RejectedBy,
+			Reason:     result.Reason,
+		})
+	}
+}
+
+// decodeEvent builds a concrete event from an envelope using registered factories
+func (h *Handler) decodeEvent(envelope EventEnvelope) (atmos.Event, error) {
+	event, exists := h.engine.NewEvent(envelope.Type)
+	if !exists {
+		return nil, errors.New("unknown event type: " + envelope.Type)
+	}
+
+	// Re-encode the generic payload and decode it into the concrete event
+	data, err := h.codec.Marshal(envelope.Data)
+	if err != nil {
+		return nil, err
+	}
+	if err := h.codec.Unmarshal(data, event); err != nil {
+		return nil, err
+	}
+	return event, nil
+}
+
+func (h *Handler) getEvents(w http.ResponseWriter, r *http.Request) {
+	actor, err := h.auth(r)
+	if err != nil {
+		h.writeError(w, http.StatusUnauthorized, err)
+		return
+	}
+
+	since := 0
+	if raw := r.URL.Query().Get("since"); raw != "" {
+		parsed, err := strconv.Atoi(raw)
+		if err != nil || parsed < 0 {
+			h.writeError(w, http.StatusBadRequest, errors.New("since must be a non-negative integer"))
+			return
+		}
+		since = parsed
+	}
+
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	events := h.engine.GetEvents()
+
+	// Events go out as the actor may see them, serialized by the engine so
+	// encrypted fields stay encrypted
+	response := EventsResponse{Events: []EventEnvelope{}, Next: len(events)}
+	for i := since; i < len(events); i++ {
+		visible, ok := h.engine.EventFor(actor, events[i])
+		if !ok {
+			continue
+		}
+		envelope, err := h.encodeEvent(visible)
+		if err != nil {
+			h.writeError(w, http.StatusInternalServerError, err)
+			return
+		}
+		response.Events = append(response.Events, envelope)
+	}
+
+	h.write(w, http.StatusOK, response)
+}
+
+func (h *Handler) getState(w http.ResponseWriter, r *http.Request) {
+	actor, err := h.auth(r)
+	if err != nil {
+		h.writeError(w, http.StatusUnauthorized, err)
+		return
+	}
+
+	name := r.PathValue("name")
+
+	h.mu.Lock()
+	state := h.engine.GetStateFor(actor, name)
+	h.mu.Unlock()
+
+	if state == nil {
+		h.writeError(w, http.StatusNotFound, errors.New("unknown state: "+name))
+		return
+	}
+
+	h.write(w, http.StatusOK, state)
+}
+
+// encodeEvent puts an event on the wire as the engine serializes it (see
+// atmos.Engine.MarshalEvent), decoded generically so any codec can write it
+func (h *Handler) encodeEvent(event atmos.Event) (EventEnvelope, error) {
+	data, err := h.engine.MarshalEvent(event)
+	if err != nil {
+		return EventEnvelope{}, err
+	}
+	decoder := json.NewDecoder(bytes.NewReader(data))
+	decoder.UseNumber()
+	var envelope EventEnvelope
+	err = decoder.Decode(&envelope)
+	return envelope, err
+}
+
+func (h *Handler) write(w http.ResponseWriter, status int, body interface{}) {
+	w.Header().Set("Content-Type", h.codec.ContentType())
+	w.WriteHeader(status)
+	_ = h.codec.Encode(w, body)
+}
+
+func (h *Handler) writeError(w http.ResponseWriter, status int, err error) {
+	h.write(w, status, ErrorResponse{Error: err.Error()})
+}
@@ -0,0 +1,160 @@
+package atmoshttp_test
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/cumulusrpg/atmos"
+	"github.com/cumulusrpg/atmos/atmoshttp"
+	"github.com/cumulusrpg/atmos/types"
+	"github.com/stretchr/testify/assert"
+)
+
+type ScoreEvent struct {
+	Player string `json:"player"`
+	Points int    `json:"points"`
+}
+
+func (e *ScoreEvent) Type() string { return "score" }
+
+type Scores map[string]int
+
+func newScoreEngine() *atmos.Engine {
+	engine := atmos.NewEngine()
+	engine.RegisterState("scores", Scores{})
+	engine.When("score", func() atmos.Event { return &ScoreEvent{} }).
+		AllowedBy("players score for themselves", func(e *atmos.Engine, actor string, event atmos.Event) bool {
+			return actor == event.(*ScoreEvent).Player
+		}).
+		Updates("scores", func(e *atmos.Engine, state interface{}, event atmos.Event) interface{} {
+			next := Scores{}
+			for k, v := range state.(Scores) {
+				next[k] = v
+			}
+			next[event.(*ScoreEvent).Player] += event.(*ScoreEvent).Points
+			return next
+		})
+	return engine
+}
+
+// headerAuth reads the actor from a header, rejecting requests without one
+func headerAuth(r *http.Request) (string, error) {
+	actor := r.Header.Get("X-Player")
+	if actor == "" {
+		return "", errors.New("missing X-Player header")
+	}
+	return actor, nil
+}
+
+func do(handler http.Handler, method, target, player, body string) *httptest.ResponseRecorder {
+	req := httptest.NewRequest(method, target, strings.NewReader(body))
+	if player != "" {
+		req.Header.Set("X-Player", player)
+	}
+	rec := httptest.NewRecorder()
+	handler.ServeHTTP(rec, req)
+	return rec
+}
+
+// TestHandlerEmitAndRead verifies the emit/state/events round trip
+func TestHandlerEmitAndRead(t *testing.T) {
+	engine := newScoreEngine()
+	handler := atmoshttp.NewHandler(engine, atmoshttp.WithAuthenticator(headerAuth))
+
+	rec := do(handler, "POST", "/events", "alice", `{"type":"score","data":{"player":"alice","points":3}}`)
+	assert.Equal(t, http.StatusOK, rec.Code)
+	assert.JSONEq(t, `{"accepted":true,"sequence":0}`, rec.Body.String())
+
+	rec = do(handler, "POST", "/events", "bob", `{"type":"score","data":{"player":"alice","points":100}}`)
+	assert.Equal(t, http.StatusUnprocessableEntity, rec.Code)
+	assert.JSONEq(t, `{"accepted":false,"sequence":-1,"rejected_by":"policy: players score for themselves"}`, rec.Body.String())
+
+	rec = do(handler, "POST", "/events", "bob", `{"type":"score","data":{"player":"bob","points":2}}`)
+	assert.Equal(t, http.StatusOK, rec.Code)
+
+	rec = do(handler, "GET", "/state/scores", "alice", "")
+	assert.Equal(t, http.StatusOK, rec.Code)
+	assert.Equal(t, "application/json", rec.Header().Get("Content-Type"))
+	assert.JSONEq(t, `{"alice":3,"bob":2}`, rec.Body.String())
+
+	rec = do(handler, "GET", "/events?since=1", "alice", "")
+	assert.Equal(t, http.StatusOK, rec.Code)
+	var events atmoshttp.EventsResponse
+	assert.NoError(t, json.Unmarshal(rec.Body.Bytes(), &events))
+	assert.Equal(t, 2, events.Next)
+	assert.Len(t, events.Events, 1)
+	assert.Equal(t, "score", events.Events[0].Type)
+}
+
+// TestHandlerErrors verifies auth, decoding, and lookup failures
+func TestHandlerErrors(t *testing.T) {
+	handler := atmoshttp.NewHandler(newScoreEngine(), atmoshttp.WithAuthenticator(headerAuth))
+
+	assert.Equal(t, http.StatusUnauthorized, do(handler, "POST", "/events", "", `{}`).Code)
+	assert.Equal(t, http.StatusUnauthorized, do(handler, "GET", "/state/scores", "", "").Code)
+	assert.Equal(t, http.StatusBadRequest, do(handler, "POST", "/events", "alice", `not json`).Code)
+	assert.Equal(t, http.StatusBadRequest, do(handler, "POST", "/events", "alice", `{"type":"unknown"}`).Code)
+	assert.Equal(t, http.StatusBadRequest, do(handler, "GET", "/events?since=-1", "alice", "").Code)
+	assert.Equal(t, http.StatusNotFound, do(handler, "GET", "/state/missing", "alice", "").Code)
+	assert.Equal(t, http.StatusMethodNotAllowed, do(handler, "DELETE", "/events", "alice", "").Code)
+}
+
+type WhisperEvent struct {
+	From   string `json:"from"`
+	To     string `json:"to"`
+	Secret string `json:"secret" atmos:"encrypt"`
+}
+
+func (e *WhisperEvent) Type() string { return "whisper" }
+
+// TestHandlerReadsRespectMasksAndEncryption verifies events are served as the engine would serialize them for the actor
+func TestHandlerReadsRespectMasksAndEncryption(t *testing.T) {
+	keys, err := atmos.NewAESKeyService([]byte("0123456789abcdef0123456789abcdef"))
+	assert.NoError(t, err)
+	engine := atmos.NewEngine(atmos.WithFieldEncryption(keys))
+	engine.When("whisper", func() atmos.Event { return &WhisperEvent{} }).
+		Masked(atmos.OwnerOnly(func(event atmos.Event) string { return event.(*WhisperEvent).To }))
+	handler := atmoshttp.NewHandler(engine, atmoshttp.WithAuthenticator(headerAuth))
+
+	rec := do(handler, "POST", "/events", "alice", `{"type":"whisper","data":{"from":"alice","to":"bob","secret":"psst"}}`)
+	assert.Equal(t, http.StatusOK, rec.Code)
+
+	rec = do(handler, "GET", "/events", "carol", "")
+	assert.JSONEq(t, `{"events":[],"next":1}`, rec.Body.String(), "Hidden from others")
+
+	rec = do(handler, "GET", "/events", "bob", "")
+	assert.Contains(t, rec.Body.String(), `"to":"bob"`)
+	assert.NotContains(t, rec.Body.String(), "psst", "Encrypted fields go out encrypted")
+}
+
+// bonusHook commits a bonus before big scores
+type bonusHook struct{}
+
+func (bonusHook) Handle(engine types.Engine, event atmos.Event) {
+	if event.(*ScoreEvent).Points > 5 {
+		engine.Emit(&ScoreEvent{Player: event.(*ScoreEvent).Player, Points: 1})
+	}
+}
+
+// TestHandlerEmitOutcomes verifies sequences skip before-hook events and failures map to distinct statuses
+func TestHandlerEmitOutcomes(t *testing.T) {
+	engine := newScoreEngine()
+	engine.When("score").Before(bonusHook{})
+	handler := atmoshttp.NewHandler(engine, atmoshttp.WithAuthenticator(headerAuth))
+
+	rec := do(handler, "POST", "/events", "alice", `{"type":"score","data":{"player":"alice","points":9}}`)
+	assert.JSONEq(t, `{"accepted":true,"sequence":1}`, rec.Body.String(), "After the bonus committed by the before hook")
+
+	engine.Pause(atmos.RejectWhilePaused())
+	rec = do(handler, "POST", "/events", "alice", `{"type":"score","data":{"player":"alice","points":1}}`)
+	assert.Equal(t, http.StatusServiceUnavailable, rec.Code)
+	engine.Resume()
+
+	engine.Pause()
+	rec = do(handler, "POST", "/events", "alice", `{"type":"score","data":{"player":"alice","points":1}}`)
+	assert.Equal(t, http.StatusAccepted, rec.Code)
+}
@@ -0,0 +1,305 @@
+// Package atmossync keeps client engines in step with an authoritative server
+// engine. Clients apply their own events optimistically, queue them as
+// pending, and periodically sync: the server emits the pending events
+// (exactly once per ID), reports which were rejected, and returns every
+// committed event the client has not yet seen.
+//
+// The protocol is transport-agnostic: SyncRequest and SyncResponse are plain
+// JSON-serializable structs, and Transport can be backed by HTTP, WebSockets,
+// or (for tests and single-process games) LocalTransport.
+package atmossync
+
+import (
+	"encoding/json"
+	"sync"
+
+	"github.com/cumulusrpg/atmos"
+)
+
+// PendingEvent is an event proposed by a client but not yet confirmed
+type PendingEvent struct {
+	ID   string          `json:"id"`   // client-chosen unique ID, used for idempotency
+	Type string          `json:"type"` // event type
+	Data json.RawMessage `json:"data"` // JSON-encoded event payload
+}
+
+// SyncRequest is sent from client to server
+type SyncRequest struct {
+	Since   int            `json:"since"`   // number of committed events the client already has
+	Pending []PendingEvent `json:"pending"` // events awaiting a verdict
+}
+
+// Result is the server's verdict on a pending event
+type Result struct {
+	ID       string `json:"id"`
+	Accepted bool   `json:"accepted"`
+	Reason   string `json:"reason,omitempty"` // why the event was rejected
+}
+
+// SyncResponse is sent from server to client
+type SyncResponse struct {
+	Results []Result        `json:"results"`
+	Events  json.RawMessage `json:"events"` // committed events since the request's Since (see Engine.MarshalEvents)
+	Next    int             `json:"next"`   // total committed events; the client's next Since
+}
+
+// Transport delivers a sync request to the server
+type Transport interface {
+	Sync(request SyncRequest) (SyncResponse, error)
+}
+
+// =============================================================================
+// Server
+// =============================================================================
+
+// Server applies client submissions to an authoritative engine.
+// It is safe for concurrent use.
+type Server struct {
+	mu       sync.Mutex
+	engine   *atmos.Engine
+	results  map[resultKey]Result // verdicts, for idempotent retries
+	order    []resultKey          // remembered verdicts, oldest first
+	remember int
+}
+
+// resultKey identifies a pending event: IDs are chosen by clients, so
+// they are only unique per actor
+type resultKey struct {
+	actor string
+	id    string
+}
+
+// ServerOption configures server construction
+type ServerOption func(*Server)
+
+// WithRememberedResults sets how many verdicts the server keeps to answer
+// retries (default 4096). Once exceeded, the oldest are forgotten, and a
+// retry that old is emitted again.
+func WithRememberedResults(n int) ServerOption {
+	return func(s *Server) {
+		s.remember = n
+	}
+}
+
+// NewServer creates a sync server for an engine
+func NewServer(engine *atmos.Engine, opts ...ServerOption) *Server {
+	s := &Server{
+		engine:   engine,
+		results:  make(map[resultKey]Result),
+		remember: 4096,
+	}
+
+	// Apply options
+	for _, opt := range opts {
+		opt(s)
+	}
+
+	return s
+}
+
+// Handle processes a sync request on behalf of an actor (see atmos.Engine.EmitAs).
+// Pending events the actor already sent under the same ID are not emitted again.
+func (s *Server) Handle(actor string, request SyncRequest) (SyncResponse, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	response := SyncResponse{Results: []Result{}}
+	for _, pending := range request.Pending {
+		key := resultKey{actor: actor, id: pending.ID}
+		result, seen := s.results[key]
+		if !seen {
+			result = s.apply(actor, pending)
+			s.rememberResult(key, result)
+		}
+		response.Results = append(response.Results, result)
+	}
+
+	events := s.engine.GetEvents()
+	since := request.Since
+	if since < 0 || since > len(events) {
+		since = len(events)
+	}
+
+	data, err := s.engine.MarshalEvents(events[since:])
+	if err != nil {
+		return SyncResponse{}, err
+	}
+	response.Events = data
+	response.Next = len(events)
+	return response, nil
+}
+
+// rememberResult keeps a verdict, forgetting the oldest beyond the limit
+func (s *Server) rememberResult(key resultKey, result Result) {
+	s.results[key] = result
+	s.order = append(s.order, key)
+	for len(s.order) > s.remember && len(s.order) > 0 {
+		delete(s.results, s.order[0])
+		s.order = s.order[1:]
+	}
+}
+
+// apply decodes and emits a pending event
+func (s *Server) apply(actor string, pending PendingEvent) Result {
+	result := Result{ID: pending.ID}
+
+	event, exists := s.engine.NewEvent(pending.Type)
+	if !exists {
+		result.Reason = "unknown event type: " + pending.Type
+		return result
+	}
+	if err := json.Unmarshal(pending.Data, event); err != nil {
+		result.Reason = err.Error()
+		return result
+	}
+
+	emitted := s.engine.EmitAsWithResult(actor, event)
+	switch {
+	case emitted.Accepted:
+		result.Accepted = true
+	case emitted.RejectedBy != "":
+		result.Reason = emitted.RejectedBy
+	case emitted.Err != nil:
+		result.Reason = emitted.Err.Error()
+	default:
+		result.Reason = "event could not be committed"
+	}
+	return result
+}
+
+// LocalTransport connects a client directly to an in-process server
+type LocalTransport struct {
+	Server *Server
+	Actor  string
+}
+
+// Sync implements Transport
+func (t LocalTransport) Sync(request SyncRequest) (SyncResponse, error) {
+	return t.Server.Handle(t.Actor, request)
+}
+
+// =============================================================================
+// Client
+// =============================================================================
+
+// Rejection reports a client event the server refused
+type Rejection struct {
+	ID     string
+	Event  atmos.Event
+	Reason string
+}
+
+// pendingEntry is a locally applied event awaiting the server's verdict
+type pendingEntry struct {
+	id    string
+	event atmos.Event
+}
+
+// Client holds a local engine whose log is the confirmed server log followed
+// by the client's own pending events. Listeners on the local engine run again
+// whenever pending events are re-applied, so keep them free of external side effects.
+type Client struct {
+	engine    *atmos.Engine
+	transport Transport
+	confirmed []atmos.Event
+	next      int
+	pending   []pendingEntry
+	nextID    func() string
+}
+
+// NewClient creates a sync client around a local engine configured with the
+// same event factories as the server
+func NewClient(engine *atmos.Engine, transport Transport, nextID func() string) *Client {
+	return &Client{
+		engine:    engine,
+		transport: transport,
+		nextID:    nextID,
+	}
+}
+
+// Engine returns the local engine (confirmed events plus optimistic pending ones)
+func (c *Client) Engine() *atmos.Engine {
+	return c.engine
+}
+
+// Propose applies an event locally and queues it for the server.
+// Events the local rules reject are not queued.
+func (c *Client) Propose(event atmos.Event) (string, bool) {
+	if !c.engine.Emit(event) {
+		return "", false
+	}
+	id := c.nextID()
+	c.pending = append(c.pending, pendingEntry{id: id, event: event})
+	return id, true
+}
+
+// Pending returns the number of events awaiting the server's verdict
+func (c *Client) Pending() int {
+	return len(c.pending)
+}
+
+// Sequence returns the number of confirmed server events the client holds
+func (c *Client) Sequence() int {
+	return c.next
+}
+
+// Sync sends pending events to the server, adopts the authoritative log, and
+// re-applies events still awaiting a verdict. On transport failure nothing
+// changes, so the client can keep playing offline and retry later. If the
+// local log can't be replaced, the error comes with the rejections, and
+// the next Sync rebuilds the local view.
+func (c *Client) Sync() ([]Rejection, error) {
+	request := SyncRequest{Since: c.next, Pending: []PendingEvent{}}
+	for _, entry := range c.pending {
+		data, err := json.Marshal(entry.event)
+		if err != nil {
+			return nil, err
+		}
+		request.Pending = append(request.Pending, PendingEvent{
+			ID:   entry.id,
+			Type: entry.event.Type(),
+			Data: data,
+		})
+	}
+
+	response, err := c.transport.Sync(request)
+	if err != nil {
+		return nil, err
+	}
+
+	events, err := c.engine.UnmarshalEvents(response.Events)
+	if err != nil {
+		return nil, err
+	}
+
+	verdicts := make(map[string]Result, len(response.Results))
+	for _, result := range response.Results {
+		verdicts[result.ID] = result
+	}
+
+	var rejections []Rejection
+	var stillPending []pendingEntry
+	for _, entry := range c.pending {
+		verdict, decided := verdicts[entry.id]
+		switch {
+		case !decided:
+			stillPending = append(stillPending, entry)
+		case !verdict.Accepted:
+			rejections = append(rejections, Rejection{ID: entry.id, Event: entry.event, Reason: verdict.Reason})
+		}
+	}
+
+	c.confirmed = append(c.confirmed, events...)
+	c.next = response.Next
+	c.pending = stillPending
+
+	// Rebuild the local view: authoritative log, then optimistic pending events
+	if err := c.engine.SetEventsE(c.confirmed); err != nil {
+		return rejections, err
+	}
+	for _, entry := range c.pending {
+		c.engine.Emit(entry.event)
+	}
+
+	return rejections, nil
+}
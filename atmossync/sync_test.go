@@ -0,0 +1,154 @@
+package atmossync_test
+
+import (
+	"errors"
+	"fmt"
+	"testing"
+
+	"github.com/cumulusrpg/atmos"
+	"github.com/cumulusrpg/atmos/atmossync"
+	"github.com/stretchr/testify/assert"
+)
+
+type ClaimEvent struct {
+	Player string `json:"player"`
+	Cell   int    `json:"cell"`
+}
+
+func (e *ClaimEvent) Type() string { return "claim" }
+
+type Board map[int]string
+
+// newBoardEngine builds the same rules on server and clients: a cell can be claimed once
+func newBoardEngine() *atmos.Engine {
+	engine := atmos.NewEngine()
+	engine.RegisterState("board", Board{})
+	engine.When("claim", func() atmos.Event { return &ClaimEvent{} }).
+		AllowedBy("cell must be free", func(e *atmos.Engine, actor string, event atmos.Event) bool {
+			_, taken := e.GetState("board").(Board)[event.(*ClaimEvent).Cell]
+			return !taken
+		}).
+		Updates("board", func(e *atmos.Engine, state interface{}, event atmos.Event) interface{} {
+			next := Board{}
+			for k, v := range state.(Board) {
+				next[k] = v
+			}
+			claim := event.(*ClaimEvent)
+			next[claim.Cell] = claim.Player
+			return next
+		})
+	return engine
+}
+
+func idGenerator(prefix string) func() string {
+	n := 0
+	return func() string {
+		n++
+		return fmt.Sprintf("%s-%d", prefix, n)
+	}
+}
+
+// TestSyncReconcilesConflictingClients verifies optimistic play, rejection, and catch-up
+func TestSyncReconcilesConflictingClients(t *testing.T) {
+	server := atmossync.NewServer(newBoardEngine())
+
+	alice := atmossync.NewClient(newBoardEngine(), atmossync.LocalTransport{Server: server, Actor: "alice"}, idGenerator("alice"))
+	bob := atmossync.NewClient(newBoardEngine(), atmossync.LocalTransport{Server: server, Actor: "bob"}, idGenerator("bob"))
+
+	// Both claim cell 4 while offline; each sees their own claim locally
+	_, ok := alice.Propose(&ClaimEvent{Player: "alice", Cell: 4})
+	assert.True(t, ok)
+	_, ok = bob.Propose(&ClaimEvent{Player: "bob", Cell: 4})
+	assert.True(t, ok)
+	_, ok = bob.Propose(&ClaimEvent{Player: "bob", Cell: 4})
+	assert.False(t, ok, "Local rules reject the duplicate before it is queued")
+	assert.Equal(t, "bob", bob.Engine().GetState("board").(Board)[4])
+
+	// Alice syncs first and wins the cell
+	rejections, err := alice.Sync()
+	assert.NoError(t, err)
+	assert.Empty(t, rejections)
+	assert.Equal(t, 0, alice.Pending())
+	assert.Equal(t, 1, alice.Sequence())
+
+	// Bob's claim is rejected and their view converges on the server log
+	rejections, err = bob.Sync()
+	assert.NoError(t, err)
+	assert.Len(t, rejections, 1)
+	assert.Equal(t, "bob-1", rejections[0].ID)
+	assert.Equal(t, "policy: cell must be free", rejections[0].Reason)
+	assert.Equal(t, Board{4: "alice"}, bob.Engine().GetState("board"))
+	assert.Equal(t, 1, bob.Sequence())
+
+	// Alice catches up on nothing new
+	_, err = alice.Sync()
+	assert.NoError(t, err)
+	assert.Len(t, alice.Engine().GetEvents(), 1)
+}
+
+type flakyTransport struct {
+	inner   atmossync.Transport
+	failing bool
+	calls   int
+}
+
+func (t *flakyTransport) Sync(request atmossync.SyncRequest) (atmossync.SyncResponse, error) {
+	t.calls++
+	if t.failing {
+		return atmossync.SyncResponse{}, errors.New("offline")
+	}
+	return t.inner.Sync(request)
+}
+
+// TestSyncRetriesAreIdempotent verifies pending events survive failures and are applied once
+func TestSyncRetriesAreIdempotent(t *testing.T) {
+	serverEngine := newBoardEngine()
+	server := atmossync.NewServer(serverEngine)
+	transport := &flakyTransport{inner: atmossync.LocalTransport{Server: server}, failing: true}
+	client := atmossync.NewClient(newBoardEngine(), transport, idGenerator("c"))
+
+	client.Propose(&ClaimEvent{Player: "carol", Cell: 1})
+	_, err := client.Sync()
+	assert.Error(t, err)
+	assert.Equal(t, 1, client.Pending())
+
+	// The same request delivered twice (e.g. a lost response) emits only once
+	request := atmossync.SyncRequest{Pending: []atmossync.PendingEvent{{ID: "c-1", Type: "claim", Data: []byte(`{"player":"carol","cell":1}`)}}}
+	_, err = server.Handle("", request)
+	assert.NoError(t, err)
+
+	transport.failing = false
+	rejections, err := client.Sync()
+	assert.NoError(t, err)
+	assert.Empty(t, rejections)
+	assert.Equal(t, 0, client.Pending())
+	assert.Len(t, serverEngine.GetEvents(), 1)
+	assert.Equal(t, Board{1: "carol"}, client.Engine().GetState("board"))
+}
+
+// TestSyncResultsAreRememberedPerActor verifies request IDs only dedupe within an actor, and old verdicts are forgotten
+func TestSyncResultsAreRememberedPerActor(t *testing.T) {
+	serverEngine := newBoardEngine()
+	server := atmossync.NewServer(serverEngine, atmossync.WithRememberedResults(2))
+	claim := func(actor string, cell int) atmossync.SyncRequest {
+		data := []byte(fmt.Sprintf(`{"player":%q,"cell":%d}`, actor, cell))
+		return atmossync.SyncRequest{Pending: []atmossync.PendingEvent{{ID: "1", Type: "claim", Data: data}}}
+	}
+
+	_, err := server.Handle("ann", claim("ann", 1))
+	assert.NoError(t, err)
+	response, err := server.Handle("bob", claim("bob", 2))
+	assert.NoError(t, err)
+	assert.True(t, response.Results[0].Accepted, "Another actor's ID 1 is a different event")
+	assert.Len(t, serverEngine.GetEvents(), 2)
+
+	_, err = server.Handle("ann", claim("ann", 1))
+	assert.NoError(t, err)
+	assert.Len(t, serverEngine.GetEvents(), 2, "ann's retry is answered from memory")
+
+	_, err = server.Handle("cat", claim("cat", 3))
+	assert.NoError(t, err)
+	response, err = server.Handle("ann", claim("ann", 1))
+	assert.NoError(t, err)
+	assert.Equal(t, "policy: cell must be free", response.Results[0].Reason, "Forgotten, so emitted again")
+}
@@ -0,0 +1,128 @@
+package atmostest
+
+import (
+	"fmt"
+	"reflect"
+	"strings"
+
+	"github.com/cumulusrpg/atmos"
+	"github.com/stretchr/testify/assert"
+)
+
+// TestingT is the subset of *testing.T used by the assertion helpers
+type TestingT interface {
+	Errorf(format string, args ...interface{})
+}
+
+// tHelper is implemented by *testing.T so failures point at the caller
+type tHelper interface {
+	Helper()
+}
+
+// AssertEmitted asserts that the engine's log contains an event of the given type
+func AssertEmitted(t TestingT, engine *atmos.Engine, eventType string) bool {
+	if h, ok := t.(tHelper); ok {
+		h.Helper()
+	}
+	for _, event := range engine.GetEvents() {
+		if event.Type() == eventType {
+			return true
+		}
+	}
+	t.Errorf("expected a %q event to have been emitted; log has: %s", eventType, logTypes(engine))
+	return false
+}
+
+// AssertNotEmitted asserts that the engine's log has no event of the given type
+func AssertNotEmitted(t TestingT, engine *atmos.Engine, eventType string) bool {
+	if h, ok := t.(tHelper); ok {
+		h.Helper()
+	}
+	for _, event := range engine.GetEvents() {
+		if event.Type() == eventType {
+			t.Errorf("expected no %q event to have been emitted; log has: %s", eventType, logTypes(engine))
+			return false
+		}
+	}
+	return true
+}
+
+// AssertAccepted asserts that the engine would accept an event, without emitting it
+func AssertAccepted(t TestingT, engine *atmos.Engine, event atmos.Event) bool {
+	if h, ok := t.(tHelper); ok {
+		h.Helper()
+	}
+	explanation := engine.Explain(event)
+	if !explanation.Accepted {
+		t.Errorf("expected event to be accepted:\n%s", explanation)
+		return false
+	}
+	return true
+}
+
+// AssertRejected asserts that the engine would reject an event, without emitting it
+func AssertRejected(t TestingT, engine *atmos.Engine, event atmos.Event) bool {
+	if h, ok := t.(tHelper); ok {
+		h.Helper()
+	}
+	explanation := engine.Explain(event)
+	if explanation.Accepted {
+		t.Errorf("expected event to be rejected:\n%s", explanation)
+		return false
+	}
+	return true
+}
+
+// AssertRejectedBy asserts that the engine would reject an event and that the
+// first failing validator is the given one. The validator matches if it is the
+// registered instance or has the same type, so a fresh value such as
+// &ValidMove{} works for both plain and typed validators.
+func AssertRejectedBy(t TestingT, engine *atmos.Engine, event atmos.Event, validator interface{}) bool {
+	if h, ok := t.(tHelper); ok {
+		h.Helper()
+	}
+	explanation := engine.Explain(event)
+	if explanation.Accepted {
+		t.Errorf("expected event to be rejected by %T:\n%s", validator, explanation)
+		return false
+	}
+
+	failure := explanation.FirstFailure
+	if rejectedBy(*failure, validator) {
+		return true
+	}
+	t.Errorf("expected event to be rejected by %T, but it was rejected by %s:\n%s", validator, failure.Name, explanation)
+	return false
+}
+
+// AssertState asserts that a named state equals want, reporting a diff on mismatch
+func AssertState(t TestingT, engine *atmos.Engine, name string, want interface{}) bool {
+	if h, ok := t.(tHelper); ok {
+		h.Helper()
+	}
+	return assert.Equal(t, want, engine.GetState(name), "state %q", name)
+}
+
+// rejectedBy reports whether a failing validator is the given instance or has its type
+func rejectedBy(failure atmos.ValidatorReport, validator interface{}) bool {
+	return failure.Name == fmt.Sprintf("%T", validator) ||
+		(reflect.TypeOf(validator).Comparable() && failure.Validator == validator)
+}
+
+// logTypes summarizes the event types in an engine's log
+func logTypes(engine *atmos.Engine) string {
+	events := engine.GetEvents()
+	if len(events) == 0 {
+		return "(empty)"
+	}
+	return typesOf(events)
+}
+
+// typesOf lists event types
+func typesOf(events []atmos.Event) string {
+	types := make([]string, len(events))
+	for i, event := range events {
+		types[i] = event.Type()
+	}
+	return strings.Join(types, ", ")
+}
@@ -0,0 +1,83 @@
+package atmostest_test
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/cumulusrpg/atmos"
+	"github.com/cumulusrpg/atmos/atmostest"
+	"github.com/stretchr/testify/assert"
+)
+
+// recorder captures assertion failures instead of failing the test
+type recorder struct {
+	errors []string
+}
+
+func (r *recorder) Errorf(format string, args ...interface{}) {
+	r.errors = append(r.errors, fmt.Sprintf(format, args...))
+}
+
+type Board struct {
+	Moves int
+}
+
+type MoveEvent struct {
+	Player string
+}
+
+func (e MoveEvent) Type() string { return "move" }
+
+type GameEndedEvent struct{}
+
+func (e GameEndedEvent) Type() string { return "game_ended" }
+
+// ValidMove only accepts moves with a player
+type ValidMove struct{}
+
+func (v *ValidMove) ValidateTyped(engine *atmos.Engine, event MoveEvent) bool {
+	return event.Player != ""
+}
+
+func newBoardEngine() *atmos.Engine {
+	engine := atmos.NewEngine()
+	engine.RegisterState("board", Board{})
+	engine.When("move").
+		Requires(atmos.NewTypedValidator[MoveEvent](&ValidMove{})).
+		Updates("board", func(e *atmos.Engine, state interface{}, event atmos.Event) interface{} {
+			board := state.(Board)
+			board.Moves++
+			return board
+		})
+	return engine
+}
+
+// TestAssertionsPass verifies the helpers succeed on matching engines
+func TestAssertionsPass(t *testing.T) {
+	engine := newBoardEngine()
+	engine.Emit(MoveEvent{Player: "alice"})
+	engine.Emit(GameEndedEvent{})
+
+	atmostest.AssertEmitted(t, engine, "game_ended")
+	atmostest.AssertNotEmitted(t, engine, "resigned")
+	atmostest.AssertAccepted(t, engine, MoveEvent{Player: "bob"})
+	atmostest.AssertRejected(t, engine, MoveEvent{})
+	atmostest.AssertRejectedBy(t, engine, MoveEvent{}, &ValidMove{})
+	atmostest.AssertState(t, engine, "board", Board{Moves: 1})
+}
+
+// TestAssertionsReportFailures verifies the helpers explain what went wrong
+func TestAssertionsReportFailures(t *testing.T) {
+	engine := newBoardEngine()
+	engine.Emit(MoveEvent{Player: "alice"})
+	rec := &recorder{}
+
+	assert.False(t, atmostest.AssertEmitted(rec, engine, "game_ended"))
+	assert.Contains(t, rec.errors[0], "log has: move")
+
+	assert.False(t, atmostest.AssertRejectedBy(rec, engine, MoveEvent{Player: "bob"}, &ValidMove{}))
+	assert.Contains(t, rec.errors[1], "move: accepted")
+
+	assert.False(t, atmostest.AssertState(rec, engine, "board", Board{Moves: 2}))
+	assert.Contains(t, rec.errors[2], "- Moves: (int) 2")
+}
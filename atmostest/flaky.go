@@ -0,0 +1,163 @@
+// Package atmostest provides helpers for testing applications built on atmos.
+package atmostest
+
+import (
+	"errors"
+	"math/rand"
+	"sync"
+	"time"
+
+	"github.com/cumulusrpg/atmos/repository"
+	"github.com/cumulusrpg/atmos/types"
+)
+
+// ErrInjected is the default error returned by injected repository failures
+var ErrInjected = errors.New("atmostest: injected repository failure")
+
+// FlakyRepository wraps a repository and injects failures and latency into
+// writes, for testing how applications handle persistence failures
+type FlakyRepository struct {
+	mu          sync.RWMutex // guards the fields below and calls to inner
+	inner       types.EventRepository
+	failureRate float64
+	failAfter   int // fail every Add after this many successful adds (-1 disables)
+	latency     time.Duration
+	err         error
+	rng         *rand.Rand
+	down        bool
+
+	adds     int
+	failures int
+}
+
+// FlakyOption configures flaky repository construction
+type FlakyOption func(*FlakyRepository)
+
+// WithInner sets the wrapped repository (default: repository.NewInMemory())
+func WithInner(inner types.EventRepository) FlakyOption {
+	return func(r *FlakyRepository) {
+		r.inner = inner
+	}
+}
+
+// WithFailureRate makes each write fail with the given probability (0 to 1)
+func WithFailureRate(rate float64) FlakyOption {
+	return func(r *FlakyRepository) {
+		r.failureRate = rate
+	}
+}
+
+// WithFailAfter makes every Add fail once n adds have succeeded
+func WithFailAfter(n int) FlakyOption {
+	return func(r *FlakyRepository) {
+		r.failAfter = n
+	}
+}
+
+// WithLatency delays every write by d
+func WithLatency(d time.Duration) FlakyOption {
+	return func(r *FlakyRepository) {
+		r.latency = d
+	}
+}
+
+// WithError sets the error returned by injected failures (default ErrInjected)
+func WithError(err error) FlakyOption {
+	return func(r *FlakyRepository) {
+		r.err = err
+	}
+}
+
+// WithSeed seeds the random failures so runs are reproducible
+func WithSeed(seed int64) FlakyOption {
+	return func(r *FlakyRepository) {
+		r.rng = rand.New(rand.NewSource(seed))
+	}
+}
+
+// NewFlakyRepository creates a fault-injecting repository
+func NewFlakyRepository(opts ...FlakyOption) *FlakyRepository {
+	r := &FlakyRepository{
+		inner:     repository.NewInMemory(),
+		failAfter: -1,
+		err:       ErrInjected,
+		rng:       rand.New(rand.NewSource(time.Now().UnixNano())),
+	}
+
+	// Apply options
+	for _, opt := range opts {
+		opt(r)
+	}
+
+	return r
+}
+
+// Add implements types.EventRepository, possibly failing
+func (r *FlakyRepository) Add(engine types.Engine, event types.Event) error {
+	if err := r.inject(true); err != nil {
+		return err
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if err := r.inner.Add(engine, event); err != nil {
+		return err
+	}
+	r.adds++
+	return nil
+}
+
+// GetAll implements types.EventRepository; reads never fail
+func (r *FlakyRepository) GetAll(engine types.Engine) []types.Event {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	return r.inner.GetAll(engine)
+}
+
+// SetAll implements types.EventRepository, possibly failing
+func (r *FlakyRepository) SetAll(engine types.Engine, events []types.Event) error {
+	if err := r.inject(false); err != nil {
+		return err
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return r.inner.SetAll(engine, events)
+}
+
+// SetDown makes every write fail (true) or restores normal behavior (false)
+func (r *FlakyRepository) SetDown(down bool) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.down = down
+}
+
+// Adds returns the number of successful adds
+func (r *FlakyRepository) Adds() int {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	return r.adds
+}
+
+// Failures returns the number of injected failures
+func (r *FlakyRepository) Failures() int {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	return r.failures
+}
+
+// inject sleeps for the configured latency and decides whether a write fails
+func (r *FlakyRepository) inject(isAdd bool) error {
+	if r.latency > 0 {
+		time.Sleep(r.latency)
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	exhausted := isAdd && r.failAfter >= 0 && r.adds >= r.failAfter
+	if r.down || exhausted || (r.failureRate > 0 && r.rng.Float64() < r.failureRate) {
+		r.failures++
+		return r.err
+	}
+	return nil
+}
@@ -0,0 +1,76 @@
+package atmostest_test
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/cumulusrpg/atmos"
+	"github.com/cumulusrpg/atmos/atmostest"
+	"github.com/stretchr/testify/assert"
+)
+
+type PingEvent struct{ N int }
+
+func (e PingEvent) Type() string { return "ping" }
+
+// TestFlakyRepositoryFailAfter verifies adds start failing after N successes
+func TestFlakyRepositoryFailAfter(t *testing.T) {
+	repo := atmostest.NewFlakyRepository(atmostest.WithFailAfter(2))
+	engine := atmos.NewEngine(atmos.WithRepository(repo))
+
+	assert.True(t, engine.Emit(PingEvent{1}))
+	assert.True(t, engine.Emit(PingEvent{2}))
+	assert.False(t, engine.Emit(PingEvent{3}))
+	assert.Equal(t, 2, repo.Adds())
+	assert.Equal(t, 1, repo.Failures())
+	assert.Len(t, engine.GetEvents(), 2)
+}
+
+// TestFlakyRepositoryFailureRate verifies seeded random failures are reproducible
+func TestFlakyRepositoryFailureRate(t *testing.T) {
+	run := func() []bool {
+		repo := atmostest.NewFlakyRepository(atmostest.WithFailureRate(0.5), atmostest.WithSeed(42))
+		engine := atmos.NewEngine(atmos.WithRepository(repo))
+		var results []bool
+		for i := 0; i < 20; i++ {
+			results = append(results, engine.Emit(PingEvent{i}))
+		}
+		return results
+	}
+
+	first := run()
+	assert.Equal(t, first, run())
+	assert.Contains(t, first, true)
+	assert.Contains(t, first, false)
+}
+
+// TestFlakyRepositorySetDown verifies outages can be toggled and custom errors returned
+func TestFlakyRepositorySetDown(t *testing.T) {
+	outage := errors.New("disk full")
+	repo := atmostest.NewFlakyRepository(atmostest.WithError(outage))
+
+	repo.SetDown(true)
+	assert.ErrorIs(t, repo.Add(nil, PingEvent{1}), outage)
+	assert.ErrorIs(t, repo.SetAll(nil, nil), outage)
+
+	repo.SetDown(false)
+	assert.NoError(t, repo.Add(nil, PingEvent{1}))
+	assert.Len(t, repo.GetAll(nil), 1)
+}
+
+// TestFlakyRepositoryConcurrentReads verifies reads and writes can overlap (run with -race)
+func TestFlakyRepositoryConcurrentReads(t *testing.T) {
+	repo := atmostest.NewFlakyRepository()
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		for i := 0; i < 100; i++ {
+			repo.Add(nil, PingEvent{i})
+		}
+	}()
+	for i := 0; i < 100; i++ {
+		repo.GetAll(nil)
+	}
+	<-done
+	assert.Len(t, repo.GetAll(nil), 100)
+}
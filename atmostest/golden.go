@@ -0,0 +1,146 @@
+package atmostest
+
+import (
+	"encoding/json"
+	"flag"
+	"os"
+	"path/filepath"
+	"strconv"
+
+	"github.com/cumulusrpg/atmos"
+	"github.com/stretchr/testify/assert"
+)
+
+// UpdateGoldenEnv is the environment variable that, set to a true value,
+// makes AssertGolden rewrite golden files instead of comparing against them
+const UpdateGoldenEnv = "ATMOS_UPDATE_GOLDEN"
+
+// updating reports whether golden files should be rewritten: with
+// UpdateGoldenEnv set, or with -update if the test binary defines that flag
+// (atmostest doesn't define it, so it never clashes with the caller's own)
+func updating() bool {
+	if update, err := strconv.ParseBool(os.Getenv(UpdateGoldenEnv)); err == nil {
+		return update
+	}
+	f := flag.Lookup("update")
+	return f != nil && f.Value.String() == "true"
+}
+
+// golden is the on-disk format of a golden file
+type golden struct {
+	Events []json.RawMessage          `json:"events"`
+	States map[string]json.RawMessage `json:"states,omitempty"`
+}
+
+// AssertGolden compares an engine's event log and the named states with a
+// golden JSON file. Run the tests with ATMOS_UPDATE_GOLDEN=1 (or -update, if
+// the test binary defines it) to (re)write the file.
+func AssertGolden(t TestingT, engine *atmos.Engine, path string, states ...string) bool {
+	if h, ok := t.(tHelper); ok {
+		h.Helper()
+	}
+
+	actual, err := recordGolden(engine, states)
+	if err != nil {
+		t.Errorf("golden %s: %v", path, err)
+		return false
+	}
+
+	if updating() {
+		if err := writeGolden(path, actual); err != nil {
+			t.Errorf("golden %s: %v", path, err)
+			return false
+		}
+		return true
+	}
+
+	expected, err := os.ReadFile(path)
+	if err != nil {
+		t.Errorf("golden %s: %v (set ATMOS_UPDATE_GOLDEN=1 to create it)", path, err)
+		return false
+	}
+	return assert.JSONEq(t, string(expected), string(actual), "golden %s differs (set ATMOS_UPDATE_GOLDEN=1 to accept)", path)
+}
+
+// ReplayGolden loads a golden file's event log into an engine and checks that
+// the current rules still accept every event given the events before it, and
+// still project the recorded states. Use it to verify that rule changes don't
+// alter the outcome of historical games.
+func ReplayGolden(t TestingT, engine *atmos.Engine, path string) bool {
+	if h, ok := t.(tHelper); ok {
+		h.Helper()
+	}
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Errorf("golden %s: %v", path, err)
+		return false
+	}
+	var recorded golden
+	if err := json.Unmarshal(data, &recorded); err != nil {
+		t.Errorf("golden %s: %v", path, err)
+		return false
+	}
+
+	events := make([]atmos.Event, 0, len(recorded.Events))
+	for i, raw := range recorded.Events {
+		event, err := engine.UnmarshalEvent(raw)
+		if err != nil {
+			t.Errorf("golden %s: event %d: %v", path, i, err)
+			return false
+		}
+
+		engine.SetEvents(events)
+		if explanation := engine.Explain(event); !explanation.Accepted {
+			t.Errorf("golden %s: event %d is now rejected:\n%s", path, i, explanation)
+			return false
+		}
+		events = append(events, event)
+	}
+	engine.SetEvents(events)
+
+	ok := true
+	for name, expected := range recorded.States {
+		actual, err := json.Marshal(engine.GetState(name))
+		if err != nil {
+			t.Errorf("golden %s: state %q: %v", path, name, err)
+			ok = false
+			continue
+		}
+		ok = assert.JSONEq(t, string(expected), string(actual), "golden %s: state %q changed on replay", path, name) && ok
+	}
+	return ok
+}
+
+// recordGolden renders an engine's log and states in the golden format
+func recordGolden(engine *atmos.Engine, states []string) ([]byte, error) {
+	recorded := golden{Events: []json.RawMessage{}}
+	for _, event := range engine.GetEvents() {
+		data, err := engine.MarshalEvent(event)
+		if err != nil {
+			return nil, err
+		}
+		recorded.Events = append(recorded.Events, data)
+	}
+
+	if len(states) > 0 {
+		recorded.States = make(map[string]json.RawMessage, len(states))
+		for _, name := range states {
+			data, err := json.Marshal(engine.GetState(name))
+			if err != nil {
+				return nil, err
+			}
+			recorded.States[name] = data
+		}
+	}
+
+	return json.MarshalIndent(recorded, "", "  ")
+}
+
+// writeGolden writes a golden file, creating its directory
+func writeGolden(path string, data []byte) error {
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		return err
+	}
+	return os.WriteFile(path, append(data, '\n'), 0o644)
+}
@@ -0,0 +1,93 @@
+package atmostest_test
+
+import (
+	"flag"
+	"path/filepath"
+	"testing"
+
+	"github.com/cumulusrpg/atmos"
+	"github.com/cumulusrpg/atmos/atmostest"
+	"github.com/cumulusrpg/atmos/types"
+	"github.com/stretchr/testify/assert"
+)
+
+type Tally struct {
+	Total int `json:"total"`
+}
+
+type AddEvent struct {
+	Amount int `json:"amount"`
+}
+
+func (e *AddEvent) Type() string { return "add" }
+
+// maxAmount rejects additions above a limit
+type maxAmount int
+
+func (m maxAmount) Validate(engine types.Engine, event atmos.Event) bool {
+	return event.(*AddEvent).Amount <= int(m)
+}
+
+func newTallyEngine(limit int, multiplier int) *atmos.Engine {
+	engine := atmos.NewEngine()
+	engine.RegisterState("tally", Tally{})
+	engine.When("add", func() atmos.Event { return &AddEvent{} }).
+		Requires(maxAmount(limit)).
+		Updates("tally", func(e *atmos.Engine, state interface{}, event atmos.Event) interface{} {
+			return Tally{Total: state.(Tally).Total + event.(*AddEvent).Amount*multiplier}
+		})
+	return engine
+}
+
+func playTally(engine *atmos.Engine) {
+	engine.Emit(&AddEvent{Amount: 3})
+	engine.Emit(&AddEvent{Amount: 5})
+}
+
+// update is defined here as consumers' own test binaries do; atmostest must not clash with it
+var update = flag.Bool("update", false, "rewrite golden files")
+
+// TestGoldenLog verifies the recorded game matches testdata/tally.golden.json
+func TestGoldenLog(t *testing.T) {
+	engine := newTallyEngine(10, 1)
+	playTally(engine)
+
+	atmostest.AssertGolden(t, engine, filepath.Join("testdata", "tally.golden.json"), "tally")
+	atmostest.ReplayGolden(t, newTallyEngine(10, 1), filepath.Join("testdata", "tally.golden.json"))
+}
+
+// TestGoldenDetectsRuleChanges verifies replay reports changed outcomes and newly rejected events
+func TestGoldenDetectsRuleChanges(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "tally.json")
+
+	rec := &recorder{}
+	assert.False(t, atmostest.AssertGolden(rec, newTallyEngine(10, 1), path))
+	assert.Contains(t, rec.errors[0], "set ATMOS_UPDATE_GOLDEN=1 to create it")
+
+	t.Setenv(atmostest.UpdateGoldenEnv, "1")
+	engine := newTallyEngine(10, 1)
+	playTally(engine)
+	assert.True(t, atmostest.AssertGolden(t, engine, path, "tally"))
+	t.Setenv(atmostest.UpdateGoldenEnv, "0")
+
+	rec = &recorder{}
+	assert.False(t, atmostest.ReplayGolden(rec, newTallyEngine(10, 2), path), "A reducer change alters the outcome")
+	assert.Contains(t, rec.errors[0], `state "tally" changed on replay`)
+
+	rec = &recorder{}
+	assert.False(t, atmostest.ReplayGolden(rec, newTallyEngine(4, 1), path), "A validator change rejects history")
+	assert.Contains(t, rec.errors[0], "event 1 is now rejected")
+}
+
+// TestGoldenHonorsCallersUpdateFlag verifies a test binary's own -update flag rewrites golden files
+func TestGoldenHonorsCallersUpdateFlag(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "tally.json")
+	engine := newTallyEngine(10, 1)
+	playTally(engine)
+
+	assert.NoError(t, flag.Set("update", "true"))
+	defer flag.Set("update", "false")
+	assert.True(t, *update)
+	assert.True(t, atmostest.AssertGolden(t, engine, path, "tally"))
+	assert.FileExists(t, path)
+}
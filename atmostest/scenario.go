@@ -0,0 +1,152 @@
+package atmostest
+
+import (
+	"strings"
+
+	"github.com/cumulusrpg/atmos"
+)
+
+// ScenarioBuilder runs a Given/When/Then test against an engine.
+// Failures are reported to t and the chain keeps going, so one run reports
+// every broken expectation.
+type ScenarioBuilder struct {
+	t           TestingT
+	engine      *atmos.Engine
+	when        atmos.Event
+	explanation atmos.Explanation
+	accepted    bool
+	emitted     []atmos.Event // Events committed by When, including the event itself
+}
+
+// Scenario starts a Given/When/Then test:
+//
+//	atmostest.Scenario(t, engine).
+//		Given(MoveEvent{...}, MoveEvent{...}).
+//		When(MoveEvent{...}).
+//		ThenState("game", want).
+//		ThenEmitted("game_ended")
+func Scenario(t TestingT, engine *atmos.Engine) *ScenarioBuilder {
+	return &ScenarioBuilder{t: t, engine: engine}
+}
+
+// Given appends events to the log as history, without validation or listeners
+func (s *ScenarioBuilder) Given(events ...atmos.Event) *ScenarioBuilder {
+	s.engine.SetEvents(append(s.engine.GetEvents(), events...))
+	return s
+}
+
+// When emits the event under test, recording whether it was accepted and
+// which events it caused
+func (s *ScenarioBuilder) When(event atmos.Event) *ScenarioBuilder {
+	before := len(s.engine.GetEvents())
+	s.when = event
+	s.explanation = s.engine.Explain(event)
+	s.accepted = s.engine.Emit(event)
+	s.emitted = s.engine.GetEvents()[before:]
+	return s
+}
+
+// ThenAccepted expects the When event to have been accepted
+func (s *ScenarioBuilder) ThenAccepted() *ScenarioBuilder {
+	if h, ok := s.t.(tHelper); ok {
+		h.Helper()
+	}
+	if s.ran() && !s.accepted {
+		s.t.Errorf("expected %q to be accepted:\n%s", s.when.Type(), s.explanation)
+	}
+	return s
+}
+
+// ThenRejected expects the When event to have been rejected
+func (s *ScenarioBuilder) ThenRejected() *ScenarioBuilder {
+	if h, ok := s.t.(tHelper); ok {
+		h.Helper()
+	}
+	if s.ran() && s.accepted {
+		s.t.Errorf("expected %q to be rejected:\n%s", s.when.Type(), s.explanation)
+	}
+	return s
+}
+
+// ThenRejectedBy expects the When event to have been rejected by the given
+// validator (matched as in AssertRejectedBy)
+func (s *ScenarioBuilder) ThenRejectedBy(validator interface{}) *ScenarioBuilder {
+	if h, ok := s.t.(tHelper); ok {
+		h.Helper()
+	}
+	if !s.ran() {
+		return s
+	}
+	if s.accepted {
+		s.t.Errorf("expected %q to be rejected by %T:\n%s", s.when.Type(), validator, s.explanation)
+		return s
+	}
+	if failure := s.explanation.FirstFailure; failure != nil && !rejectedBy(*failure, validator) {
+		s.t.Errorf("expected %q to be rejected by %T, but it was rejected by %s:\n%s",
+			s.when.Type(), validator, failure.Name, s.explanation)
+	}
+	return s
+}
+
+// ThenState expects a named state to equal want, reporting a diff on mismatch
+func (s *ScenarioBuilder) ThenState(name string, want interface{}) *ScenarioBuilder {
+	if h, ok := s.t.(tHelper); ok {
+		h.Helper()
+	}
+	AssertState(s.t, s.engine, name, want)
+	return s
+}
+
+// ThenEmitted expects the When event to have caused events of each given
+// type, in order (other events may be interleaved)
+func (s *ScenarioBuilder) ThenEmitted(eventTypes ...string) *ScenarioBuilder {
+	if h, ok := s.t.(tHelper); ok {
+		h.Helper()
+	}
+	if !s.ran() {
+		return s
+	}
+
+	next := 0
+	for _, event := range s.emitted {
+		if next < len(eventTypes) && event.Type() == eventTypes[next] {
+			next++
+		}
+	}
+	if next < len(eventTypes) {
+		s.t.Errorf("expected %q to emit [%s] in order, missing %q; it emitted [%s]",
+			s.when.Type(), strings.Join(eventTypes, ", "), eventTypes[next], typesOf(s.emitted))
+	}
+	return s
+}
+
+// ThenNotEmitted expects the When event not to have caused events of the given type
+func (s *ScenarioBuilder) ThenNotEmitted(eventType string) *ScenarioBuilder {
+	if h, ok := s.t.(tHelper); ok {
+		h.Helper()
+	}
+	if !s.ran() {
+		return s
+	}
+	for _, event := range s.emitted {
+		if event.Type() == eventType {
+			s.t.Errorf("expected %q not to emit %q; it emitted [%s]", s.when.Type(), eventType, typesOf(s.emitted))
+			break
+		}
+	}
+	return s
+}
+
+// Emitted returns the events committed by When
+func (s *ScenarioBuilder) Emitted() []atmos.Event {
+	return s.emitted
+}
+
+// ran reports (and flags) whether When has been called
+func (s *ScenarioBuilder) ran() bool {
+	if s.when == nil {
+		s.t.Errorf("scenario has no When event")
+		return false
+	}
+	return true
+}
@@ -0,0 +1,56 @@
+package atmostest_test
+
+import (
+	"testing"
+
+	"github.com/cumulusrpg/atmos"
+	"github.com/cumulusrpg/atmos/atmostest"
+	"github.com/cumulusrpg/atmos/types"
+	"github.com/stretchr/testify/assert"
+)
+
+// EndOnThirdMove ends the game once three moves have been made
+type EndOnThirdMove struct{}
+
+func (l *EndOnThirdMove) Handle(engine types.Engine, event atmos.Event) {
+	if engine.GetState("board").(Board).Moves == 3 {
+		engine.Emit(GameEndedEvent{})
+	}
+}
+
+func newEndingEngine() *atmos.Engine {
+	engine := newBoardEngine()
+	engine.When("move").Then(&EndOnThirdMove{})
+	return engine
+}
+
+// TestScenarioPasses verifies a passing Given/When/Then chain
+func TestScenarioPasses(t *testing.T) {
+	atmostest.Scenario(t, newEndingEngine()).
+		Given(MoveEvent{Player: "alice"}, MoveEvent{Player: "bob"}).
+		When(MoveEvent{Player: "alice"}).
+		ThenAccepted().
+		ThenState("board", Board{Moves: 3}).
+		ThenEmitted("move", "game_ended")
+
+	atmostest.Scenario(t, newEndingEngine()).
+		When(MoveEvent{}).
+		ThenRejectedBy(&ValidMove{}).
+		ThenNotEmitted("move")
+}
+
+// TestScenarioReportsEveryFailure verifies each broken expectation is reported
+func TestScenarioReportsEveryFailure(t *testing.T) {
+	rec := &recorder{}
+	s := atmostest.Scenario(rec, newEndingEngine()).
+		When(MoveEvent{Player: "alice"}).
+		ThenRejected().
+		ThenState("board", Board{Moves: 2}).
+		ThenEmitted("game_ended")
+
+	assert.Len(t, rec.errors, 3)
+	assert.Contains(t, rec.errors[0], "move: accepted")
+	assert.Contains(t, rec.errors[1], "+ Moves: (int) 1")
+	assert.Contains(t, rec.errors[2], `missing "game_ended"; it emitted [move]`)
+	assert.Len(t, s.Emitted(), 1)
+}
@@ -0,0 +1,134 @@
+package atmostest
+
+import (
+	"fmt"
+	"math/rand"
+	"reflect"
+
+	"github.com/cumulusrpg/atmos"
+)
+
+// Generator proposes a random event given the engine's current state.
+// It may return nil when it has nothing sensible to propose.
+type Generator func(rng *rand.Rand, engine *atmos.Engine) atmos.Event
+
+// Simulation drives random event sequences through fresh engines looking for
+// validator gaps: after every step it verifies that rejected events left the
+// log and the watched states untouched, and that registered invariants hold.
+//
+//	result, err := atmostest.Simulation{
+//		NewEngine:  newGame,
+//		Generators: []atmostest.Generator{randomMove, randomResign},
+//		States:     []string{"game"},
+//		Runs:       1000,
+//		Steps:      50,
+//	}.Run()
+type Simulation struct {
+	NewEngine  func() *atmos.Engine
+	Generators []Generator // One is picked at random for each step
+	States     []string    // States that must not change when an event is rejected
+	Runs       int         // Number of independent games (default 100)
+	Steps      int         // Events proposed per game (default 100)
+	Seed       int64       // Run i uses Seed+i, so failures can be reproduced
+}
+
+// SimulationResult summarizes a simulation
+type SimulationResult struct {
+	Runs     int
+	Accepted int
+	Rejected int
+}
+
+// SimulationFailure describes the first problem a simulation found
+type SimulationFailure struct {
+	Run      int           // Index of the failing run
+	Seed     int64         // Seed that reproduces the run
+	Step     int           // Step at which the failure was detected
+	Proposed []atmos.Event // Every event proposed in the run, up to and including the failing one
+	Err      error
+}
+
+func (f *SimulationFailure) Error() string {
+	return fmt.Sprintf("simulation run %d (seed %d) failed at step %d: %v", f.Run, f.Seed, f.Step, f.Err)
+}
+
+func (f *SimulationFailure) Unwrap() error {
+	return f.Err
+}
+
+// Run executes the simulation, stopping at the first failure
+func (s Simulation) Run() (SimulationResult, error) {
+	runs, steps := s.Runs, s.Steps
+	if runs <= 0 {
+		runs = 100
+	}
+	if steps <= 0 {
+		steps = 100
+	}
+
+	var result SimulationResult
+	for run := 0; run < runs; run++ {
+		if err := s.runOnce(run, steps, &result); err != nil {
+			return result, err
+		}
+		result.Runs++
+	}
+	return result, nil
+}
+
+// runOnce plays one random game
+func (s Simulation) runOnce(run, steps int, result *SimulationResult) (failure *SimulationFailure) {
+	seed := s.Seed + int64(run)
+	rng := rand.New(rand.NewSource(seed))
+	engine := s.NewEngine()
+	var proposed []atmos.Event
+	step := 0
+
+	fail := func(err error) *SimulationFailure {
+		return &SimulationFailure{Run: run, Seed: seed, Step: step, Proposed: proposed, Err: err}
+	}
+	defer func() {
+		if r := recover(); r != nil {
+			failure = fail(fmt.Errorf("panic: %v", r))
+		}
+	}()
+
+	for ; step < steps; step++ {
+		event := s.Generators[rng.Intn(len(s.Generators))](rng, engine)
+		if event == nil {
+			continue
+		}
+		proposed = append(proposed, event)
+
+		logBefore := len(engine.GetEvents())
+		statesBefore := s.snapshotStates(engine)
+
+		if engine.Emit(event) {
+			result.Accepted++
+		} else {
+			result.Rejected++
+			if n := len(engine.GetEvents()); n != logBefore {
+				return fail(fmt.Errorf("rejected %s changed the log from %d to %d events", event.Type(), logBefore, n))
+			}
+			for i, name := range s.States {
+				if after := engine.GetState(name); !reflect.DeepEqual(statesBefore[i], after) {
+					return fail(fmt.Errorf("rejected %s changed state %q: %+v -> %+v", event.Type(), name, statesBefore[i], after))
+				}
+			}
+		}
+
+		if err := engine.CheckInvariants(); err != nil {
+			return fail(err)
+		}
+	}
+	return nil
+}
+
+// snapshotStates captures the watched states
+func (s Simulation) snapshotStates(engine *atmos.Engine) []interface{} {
+	states := make([]interface{}, len(s.States))
+	for i, name := range s.States {
+		states[i] = engine.GetState(name)
+	}
+	return states
+}
@@ -0,0 +1,292 @@
+// Package atmosws pushes committed events to WebSocket clients and accepts
+// event submissions from them, for real-time multiplayer UIs.
+//
+// The package does not depend on a WebSocket implementation: any connection
+// with ReadJSON/WriteJSON/Close works, including *websocket.Conn from
+// github.com/gorilla/websocket.
+//
+//	hub := atmosws.NewHub(engine)
+//	http.HandleFunc("/ws", func(w http.ResponseWriter, r *http.Request) {
+//		conn, _ := upgrader.Upgrade(w, r, nil)
+//		hub.Serve(conn, playerFromRequest(r), nil)
+//	})
+package atmosws
+
+import (
+	"encoding/json"
+	"errors"
+	"sync"
+
+	"github.com/cumulusrpg/atmos"
+	"github.com/cumulusrpg/atmos/types"
+)
+
+// Conn is the subset of a WebSocket connection the hub needs
+type Conn interface {
+	ReadJSON(v interface{}) error
+	WriteJSON(v interface{}) error
+	Close() error
+}
+
+// Filter decides whether a committed event is pushed to a connection
+type Filter func(event atmos.Event) bool
+
+// Message kinds sent to clients
+const (
+	KindEvent    = "event"    // a committed event
+	KindAccepted = "accepted" // the client's submission was committed
+	KindRejected = "rejected" // the client's submission was rejected
+	KindError    = "error"    // the client's submission could not be decoded
+)
+
+// Submission is a client-to-server message proposing an event
+type Submission struct {
+	ID   string      `json:"id,omitempty"` // echoed back in the reply so clients can correlate
+	Type string      `json:"type"`
+	Data interface{} `json:"data"`
+}
+
+// Message is a server-to-client message
+type Message struct {
+	Kind     string      `json:"kind"`
+	ID       string      `json:"id,omitempty"`
+	Type     string      `json:"type,omitempty"`
+	Data     interface{} `json:"data,omitempty"`
+	Sequence int         `json:"sequence"` // log position for KindEvent, -1 otherwise
+	Reason   string      `json:"reason,omitempty"`
+}
+
+// client is a connected WebSocket with its own outbound queue
+type client struct {
+	conn   Conn
+	actor  string
+	filter Filter
+	send   chan Message
+}
+
+// Hub broadcasts committed events to connected clients. Emits made through
+// the hub are serialized; code emitting on the same engine elsewhere should
+// use Lock/Unlock.
+type Hub struct {
+	engineMu  sync.Mutex
+	mu        sync.Mutex
+	engine    *atmos.Engine
+	clients   map[*client]struct{}
+	queueSize int
+}
+
+// Option configures hub construction
+type Option func(*Hub)
+
+// WithQueueSize sets how many messages may queue per client before it is
+// disconnected as too slow (default 64)
+func WithQueueSize(size int) Option {
+	return func(h *Hub) {
+		h.queueSize = size
+	}
+}
+
+// NewHub creates a hub and subscribes it to every event committed on the engine
+func NewHub(engine *atmos.Engine, opts ...Option) *Hub {
+	h := &Hub{
+		engine:    engine,
+		clients:   make(map[*client]struct{}),
+		queueSize: 64,
+	}
+
+	// Apply options
+	for _, opt := range opts {
+		opt(h)
+	}
+
+	engine.When(atmos.AnyEvent).Then(broadcastListener{hub: h})
+	return h
+}
+
+// Lock acquires exclusive access to the engine (the same lock submissions use)
+func (h *Hub) Lock() { h.engineMu.Lock() }
+
+// Unlock releases exclusive access to the engine
+func (h *Hub) Unlock() { h.engineMu.Unlock() }
+
+// Serve registers a connection and processes its submissions until it
+// disconnects. Submissions are emitted as actor, and committed events are
+// pushed as actor may see them (see atmos.Engine.EventFor), with encrypted
+// fields left encrypted. A nil filter receives every visible event.
+func (h *Hub) Serve(conn Conn, actor string, filter Filter) error {
+	c := &client{conn: conn, actor: actor, filter: filter, send: make(chan Message, h.queueSize)}
+
+	h.mu.Lock()
+	h.clients[c] = struct{}{}
+	h.mu.Unlock()
+
+	done := make(chan struct{})
+	go h.writeLoop(c, done)
+	defer func() {
+		h.remove(c)
+		<-done
+		conn.Close()
+	}()
+
+	for {
+		var submission Submission
+		if err := conn.ReadJSON(&submission); err != nil {
+			return err
+		}
+		h.enqueue(c, h.submit(actor, submission))
+	}
+}
+
+// Clients returns the number of connected clients
+func (h *Hub) Clients() int {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	return len(h.clients)
+}
+
+// submit decodes and emits a client submission, returning the reply
+func (h *Hub) submit(actor string, submission Submission) Message {
+	reply := Message{ID: submission.ID, Type: submission.Type, Sequence: -1}
+
+	h.engineMu.Lock()
+	defer h.engineMu.Unlock()
+
+	event, err := decodeEvent(h.engine, submission)
+	if err != nil {
+		reply.Kind = KindError
+		reply.Reason = err.Error()
+		return reply
+	}
+
+	result := h.engine.EmitAsWithResult(actor, event)
+	switch {
+	case result.Accepted:
+		reply.Kind = KindAccepted
+	case result.RejectedBy != "":
+		reply.Kind = KindRejected
+		reply.Reason = result.RejectedBy
+	case result.Queued:
+		reply.Kind = KindRejected
+		reply.Reason = "held while the engine is paused"
+	default:
+		reply.Kind = KindRejected
+		reply.Reason = result.Err.Error()
+	}
+	return reply
+}
+
+// decodeEvent builds a concrete event from a submission using registered factories
+func decodeEvent(engine *atmos.Engine, submission Submission) (atmos.Event, error) {
+	event, exists := engine.NewEvent(submission.Type)
+	if !exists {
+		return nil, errors.New("unknown event type: " + submission.Type)
+	}
+
+	data, err := json.Marshal(submission.Data)
+	if err != nil {
+		return nil, err
+	}
+	if err := json.Unmarshal(data, event); err != nil {
+		return nil, err
+	}
+	return event, nil
+}
+
+// broadcast queues a committed event for every client whose filter accepts
+// it, as the client's actor may see it
+func (h *Hub) broadcast(engine *atmos.Engine, event atmos.Event, sequence int) {
+	h.mu.Lock()
+	clients := make([]*client, 0, len(h.clients))
+	for c := range h.clients {
+		clients = append(clients, c)
+	}
+	h.mu.Unlock()
+
+	for _, c := range clients {
+		if c.filter != nil && !c.filter(event) {
+			continue
+		}
+		visible, ok := engine.EventFor(c.actor, event)
+		if !ok {
+			continue
+		}
+		data, err := encodeEvent(engine, visible)
+		if err != nil {
+			continue // Not serializable, so not deliverable either
+		}
+		h.enqueue(c, Message{Kind: KindEvent, Type: event.Type(), Data: data, Sequence: sequence})
+	}
+}
+
+// encodeEvent returns an event's payload as the engine serializes it (see
+// atmos.Engine.MarshalEvent), decoded generically for WriteJSON
+func encodeEvent(engine *atmos.Engine, event atmos.Event) (interface{}, error) {
+	encoded, err := engine.MarshalEvent(event)
+	if err != nil {
+		return nil, err
+	}
+	var wrapper struct {
+		Data json.RawMessage `json:"data"`
+	}
+	if err := json.Unmarshal(encoded, &wrapper); err != nil {
+		return nil, err
+	}
+	return wrapper.Data, nil
+}
+
+// enqueue queues a message for a client, disconnecting it if its queue is full
+func (h *Hub) enqueue(c *client, message Message) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	if _, connected := h.clients[c]; !connected {
+		return
+	}
+
+	select {
+	case c.send <- message:
+	default:
+		// Too slow: drop the client rather than block the engine
+		delete(h.clients, c)
+		close(c.send)
+		c.conn.Close()
+	}
+}
+
+// remove disconnects a client if it is still registered
+func (h *Hub) remove(c *client) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	if _, connected := h.clients[c]; connected {
+		delete(h.clients, c)
+		close(c.send)
+	}
+}
+
+// writeLoop delivers queued messages to a client's connection
+func (h *Hub) writeLoop(c *client, done chan struct{}) {
+	defer close(done)
+	for message := range c.send {
+		if err := c.conn.WriteJSON(message); err != nil {
+			c.conn.Close()
+			h.remove(c)
+			for range c.send {
+				// drain until remove closes the queue
+			}
+			return
+		}
+	}
+}
+
+// broadcastListener forwards every committed event to the hub
+type broadcastListener struct {
+	hub *Hub
+}
+
+// Handle implements atmos.EventListener
+func (l broadcastListener) Handle(engine types.Engine, event atmos.Event) {
+	if e, ok := engine.(*atmos.Engine); ok && e == l.hub.engine { // Not forks, which share listeners
+		l.hub.broadcast(e, event, len(engine.GetEvents())-1)
+	}
+}
@@ -0,0 +1,192 @@
+package atmosws_test
+
+import (
+	"encoding/json"
+	"errors"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/cumulusrpg/atmos"
+	"github.com/cumulusrpg/atmos/atmosws"
+	"github.com/stretchr/testify/assert"
+)
+
+type ChatEvent struct {
+	Room string `json:"room"`
+	Text string `json:"text"`
+}
+
+func (e *ChatEvent) Type() string { return "chat" }
+
+// fakeConn is an in-memory Conn: tests push submissions and read messages
+type fakeConn struct {
+	incoming chan interface{}
+	outgoing chan atmosws.Message
+	closed   chan struct{}
+	once     sync.Once
+}
+
+func newFakeConn() *fakeConn {
+	return &fakeConn{
+		incoming: make(chan interface{}, 8),
+		outgoing: make(chan atmosws.Message, 8),
+		closed:   make(chan struct{}),
+	}
+}
+
+func (c *fakeConn) ReadJSON(v interface{}) error {
+	select {
+	case msg := <-c.incoming:
+		data, _ := json.Marshal(msg)
+		return json.Unmarshal(data, v)
+	case <-c.closed:
+		return errors.New("closed")
+	}
+}
+
+func (c *fakeConn) WriteJSON(v interface{}) error {
+	select {
+	case c.outgoing <- v.(atmosws.Message):
+		return nil
+	case <-c.closed:
+		return errors.New("closed")
+	}
+}
+
+func (c *fakeConn) Close() error {
+	c.once.Do(func() { close(c.closed) })
+	return nil
+}
+
+func (c *fakeConn) next(t *testing.T) atmosws.Message {
+	select {
+	case msg := <-c.outgoing:
+		return msg
+	case <-time.After(time.Second):
+		t.Fatal("timed out waiting for message")
+		return atmosws.Message{}
+	}
+}
+
+func waitForClients(t *testing.T, hub *atmosws.Hub, n int) {
+	deadline := time.Now().Add(time.Second)
+	for hub.Clients() != n {
+		if time.Now().After(deadline) {
+			t.Fatalf("expected %d clients, got %d", n, hub.Clients())
+		}
+		time.Sleep(time.Millisecond)
+	}
+}
+
+// TestHubBroadcastsFilteredEvents verifies submissions are emitted and pushed to matching clients
+func TestHubBroadcastsFilteredEvents(t *testing.T) {
+	engine := atmos.NewEngine()
+	engine.When("chat", func() atmos.Event { return &ChatEvent{} }).
+		AllowedBy("no anonymous chat", func(e *atmos.Engine, actor string, event atmos.Event) bool {
+			return actor != ""
+		})
+
+	hub := atmosws.NewHub(engine)
+
+	alice := newFakeConn()
+	lobbyOnly := newFakeConn()
+	go hub.Serve(alice, "alice", nil)
+	go hub.Serve(lobbyOnly, "", func(event atmos.Event) bool {
+		return event.(*ChatEvent).Room == "lobby"
+	})
+	waitForClients(t, hub, 2)
+
+	alice.incoming <- atmosws.Submission{ID: "1", Type: "chat", Data: map[string]string{"room": "game", "text": "hi"}}
+	event := alice.next(t)
+	assert.Equal(t, atmosws.KindEvent, event.Kind)
+	assert.Equal(t, 0, event.Sequence)
+	assert.Equal(t, atmosws.Message{Kind: atmosws.KindAccepted, ID: "1", Type: "chat", Sequence: -1}, alice.next(t))
+
+	alice.incoming <- atmosws.Submission{ID: "2", Type: "chat", Data: map[string]string{"room": "lobby", "text": "gg"}}
+	assert.Equal(t, 1, alice.next(t).Sequence)
+	assert.Equal(t, atmosws.KindAccepted, alice.next(t).Kind)
+
+	// The lobby connection only sees the lobby message
+	lobbyEvent := lobbyOnly.next(t)
+	assert.Equal(t, 1, lobbyEvent.Sequence)
+	assert.Equal(t, "chat", lobbyEvent.Type)
+
+	// Anonymous submissions are rejected by the actor policy
+	lobbyOnly.incoming <- atmosws.Submission{ID: "3", Type: "chat", Data: map[string]string{"room": "lobby"}}
+	rejected := lobbyOnly.next(t)
+	assert.Equal(t, atmosws.KindRejected, rejected.Kind)
+	assert.Equal(t, "policy: no anonymous chat", rejected.Reason)
+
+	// Unknown types produce an error reply
+	lobbyOnly.incoming <- atmosws.Submission{ID: "4", Type: "nope"}
+	assert.Equal(t, atmosws.KindError, lobbyOnly.next(t).Kind)
+
+	assert.Len(t, engine.GetEvents(), 2)
+
+	alice.Close()
+	lobbyOnly.Close()
+	waitForClients(t, hub, 0)
+}
+
+// TestHubDropsSlowClients verifies a full queue disconnects the client instead of blocking
+func TestHubDropsSlowClients(t *testing.T) {
+	engine := atmos.NewEngine()
+	hub := atmosws.NewHub(engine, atmosws.WithQueueSize(1))
+
+	slow := &fakeConn{
+		incoming: make(chan interface{}),
+		outgoing: make(chan atmosws.Message), // unbuffered and never read
+		closed:   make(chan struct{}),
+	}
+	go hub.Serve(slow, "", nil)
+	waitForClients(t, hub, 1)
+
+	hub.Lock()
+	for i := 0; i < 3; i++ {
+		engine.Emit(&ChatEvent{Text: "spam"})
+	}
+	hub.Unlock()
+
+	waitForClients(t, hub, 0)
+}
+
+type SecretEvent struct {
+	Owner string `json:"owner"`
+	Note  string `json:"note" atmos:"encrypt"`
+}
+
+func (e *SecretEvent) Type() string { return "secret" }
+
+// TestHubBroadcastsMaskedEncryptedEvents verifies each client gets events as its actor may see them, encrypted
+func TestHubBroadcastsMaskedEncryptedEvents(t *testing.T) {
+	keys, err := atmos.NewAESKeyService([]byte("0123456789abcdef0123456789abcdef"))
+	assert.NoError(t, err)
+	engine := atmos.NewEngine(atmos.WithFieldEncryption(keys))
+	engine.When("secret", func() atmos.Event { return &SecretEvent{} }).
+		Masked(atmos.OwnerOnly(func(event atmos.Event) string { return event.(*SecretEvent).Owner }))
+	hub := atmosws.NewHub(engine)
+
+	alice, bob := newFakeConn(), newFakeConn()
+	go hub.Serve(alice, "alice", nil)
+	go hub.Serve(bob, "bob", nil)
+	waitForClients(t, hub, 2)
+
+	alice.incoming <- atmosws.Submission{ID: "1", Type: "secret", Data: map[string]string{"owner": "alice", "note": "the key is under the mat"}}
+	event := alice.next(t)
+	assert.Equal(t, atmosws.KindEvent, event.Kind)
+	data, _ := json.Marshal(event.Data)
+	assert.Contains(t, string(data), `"owner":"alice"`)
+	assert.NotContains(t, string(data), "under the mat")
+	assert.Equal(t, atmosws.KindAccepted, alice.next(t).Kind)
+
+	select {
+	case message := <-bob.outgoing:
+		t.Fatalf("bob received a hidden event: %+v", message)
+	case <-time.After(20 * time.Millisecond):
+	}
+
+	alice.Close()
+	bob.Close()
+	waitForClients(t, hub, 0)
+}
@@ -0,0 +1,232 @@
+// Package audit records who emitted what and when into an audit store kept
+// apart from the game's log, for moderation of competitive games:
+//
+//	trail := audit.New(audit.NewMemoryStore())
+//	engine.ThenAll(trail)
+//	engine.EmitWithMetadata(atmos.Metadata{"actor": "alice", "ip": addr}, event)
+//
+//	suspicious, err := trail.Query(audit.ByActor("alice"), audit.Between(from, to))
+//
+// Each entry has the emit's actor (see atmos.Engine.EmitAs) and metadata
+// (see atmos.Engine.EmitWithMetadata), so derived events are attributed to
+// whoever caused them. Events committed while the engine is replaying are
+// not recorded.
+//
+// The actor and metadata are those of the emit in progress, so the trail
+// must run as a synchronous listener (ThenAll, Then), not with ThenAsync or
+// ThenQueued, where the emit is over by the time it runs.
+package audit
+
+import (
+	"encoding/json"
+	"errors"
+	"sync"
+	"time"
+
+	"github.com/cumulusrpg/atmos"
+	"github.com/cumulusrpg/atmos/types"
+)
+
+// ErrUnsupportedEngine is reported (see OnError) for events committed by an
+// engine the trail can't read the actor and metadata of
+var ErrUnsupportedEngine = errors.New("audit trail needs an *atmos.Engine")
+
+// Entry is one audited event
+type Entry struct {
+	Seq      uint64            `json:"seq"` // Position in the audit store, from 1
+	Type     string            `json:"type"`
+	Actor    string            `json:"actor,omitempty"`
+	At       time.Time         `json:"at"`
+	Metadata map[string]string `json:"metadata,omitempty"`
+	Data     json.RawMessage   `json:"data"` // The event as MarshalEvent writes it (encrypted fields stay encrypted)
+}
+
+// Store keeps audit entries, e.g. in a table of their own. Implementations
+// must be safe for concurrent use.
+type Store interface {
+	// Append stores an entry, assigning its Seq
+	Append(entry Entry) error
+
+	// Scan visits entries in order until fn returns false
+	Scan(fn func(entry Entry) bool) error
+}
+
+// MemoryStore is a Store held in memory
+type MemoryStore struct {
+	mu      sync.Mutex
+	entries []Entry
+}
+
+// NewMemoryStore creates an empty in-memory store
+func NewMemoryStore() *MemoryStore {
+	return &MemoryStore{}
+}
+
+// Append implements Store
+func (s *MemoryStore) Append(entry Entry) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	entry.Seq = uint64(len(s.entries) + 1)
+	s.entries = append(s.entries, entry)
+	return nil
+}
+
+// Scan implements Store
+func (s *MemoryStore) Scan(fn func(entry Entry) bool) error {
+	s.mu.Lock()
+	entries := s.entries[:len(s.entries):len(s.entries)]
+	s.mu.Unlock()
+	for _, entry := range entries {
+		if !fn(entry) {
+			return nil
+		}
+	}
+	return nil
+}
+
+// Trail is an EventListener that records committed events in a store
+type Trail struct {
+	store   Store
+	types   map[string]bool // event types recorded; all if empty
+	now     func() time.Time
+	onError func(error)
+}
+
+// Option configures trail construction
+type Option func(*Trail)
+
+// Types limits the trail to some event types
+func Types(eventTypes ...string) Option {
+	return func(t *Trail) {
+		for _, eventType := range eventTypes {
+			t.types[eventType] = true
+		}
+	}
+}
+
+// WithClock sets the time source for entry timestamps (default time.Now)
+func WithClock(now func() time.Time) Option {
+	return func(t *Trail) {
+		t.now = now
+	}
+}
+
+// OnError is called when an event can't be encoded or the store fails
+// (by default errors are dropped: auditing never disrupts the game)
+func OnError(handle func(error)) Option {
+	return func(t *Trail) {
+		t.onError = handle
+	}
+}
+
+// New creates a trail recording into store
+func New(store Store, opts ...Option) *Trail {
+	t := &Trail{
+		store:   store,
+		types:   make(map[string]bool),
+		now:     time.Now,
+		onError: func(error) {},
+	}
+
+	// Apply options
+	for _, opt := range opts {
+		opt(t)
+	}
+
+	return t
+}
+
+// Handle implements atmos.EventListener
+func (t *Trail) Handle(engine types.Engine, event atmos.Event) {
+	if atmos.IsReplaying(engine) || (len(t.types) > 0 && !t.types[event.Type()]) {
+		return
+	}
+
+	e, ok := engine.(*atmos.Engine)
+	if !ok {
+		t.onError(ErrUnsupportedEngine)
+		return
+	}
+	data, err := e.MarshalEvent(event)
+	if err != nil {
+		t.onError(err)
+		return
+	}
+	entry := Entry{
+		Type:  event.Type(),
+		Actor: e.Actor(),
+		At:    t.now(),
+		Data:  data,
+	}
+	if metadata := e.Metadata(); len(metadata) > 0 {
+		entry.Metadata = make(map[string]string, len(metadata))
+		for key, value := range metadata {
+			entry.Metadata[key] = value
+		}
+	}
+	if err := t.store.Append(entry); err != nil {
+		t.onError(err)
+	}
+}
+
+// Filter selects entries in a query
+type Filter func(entry Entry) bool
+
+// ByActor selects the entries of an actor
+func ByActor(actor string) Filter {
+	return func(entry Entry) bool {
+		return entry.Actor == actor
+	}
+}
+
+// OfType selects entries of some event types
+func OfType(eventTypes ...string) Filter {
+	return func(entry Entry) bool {
+		for _, eventType := range eventTypes {
+			if entry.Type == eventType {
+				return true
+			}
+		}
+		return false
+	}
+}
+
+// Between selects entries recorded from one time up to (not including)
+// another; a zero time leaves that end open
+func Between(from, to time.Time) Filter {
+	return func(entry Entry) bool {
+		return (from.IsZero() || !entry.At.Before(from)) && (to.IsZero() || entry.At.Before(to))
+	}
+}
+
+// WithMetadata selects entries whose metadata has a value, e.g. an IP address
+func WithMetadata(key, value string) Filter {
+	return func(entry Entry) bool {
+		return entry.Metadata[key] == value
+	}
+}
+
+// Query returns the entries matching every filter, in order
+func (t *Trail) Query(filters ...Filter) ([]Entry, error) {
+	var matches []Entry
+	err := t.store.Scan(func(entry Entry) bool {
+		for _, filter := range filters {
+			if !filter(entry) {
+				return true
+			}
+		}
+		matches = append(matches, entry)
+		return true
+	})
+	return matches, err
+}
+
+// Actors returns how many entries each actor has, e.g. to spot spam
+func (t *Trail) Actors() (map[string]int, error) {
+	counts := make(map[string]int)
+	err := t.store.Scan(func(entry Entry) bool {
+		counts[entry.Actor]++
+		return true
+	})
+	return counts, err
+}
@@ -0,0 +1,145 @@
+package audit_test
+
+import (
+	"bytes"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/cumulusrpg/atmos"
+	"github.com/cumulusrpg/atmos/audit"
+	"github.com/cumulusrpg/atmos/types"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type ChatSentEvent struct {
+	Text string `json:"text"`
+}
+
+func (e ChatSentEvent) Type() string { return "chat_sent" }
+
+type ChatFlaggedEvent struct{}
+
+func (e ChatFlaggedEvent) Type() string { return "chat_flagged" }
+
+var epoch = time.Date(2024, 5, 1, 12, 0, 0, 0, time.UTC)
+
+// newAuditedEngine flags every chat message, recording into a trail whose clock ticks a minute per entry
+func newAuditedEngine(opts ...audit.Option) (*atmos.Engine, *audit.Trail) {
+	clock := epoch
+	trail := audit.New(audit.NewMemoryStore(), append([]audit.Option{audit.WithClock(func() time.Time {
+		clock = clock.Add(time.Minute)
+		return clock
+	})}, opts...)...)
+	engine := atmos.NewEngine()
+	engine.ThenAll(trail)
+	engine.When("chat_sent").Then(atmos.Do[ChatSentEvent](flagChat{}))
+	return engine, trail
+}
+
+// flagChat flags every chat message for review
+type flagChat struct{}
+
+func (flagChat) HandleTyped(engine *atmos.Engine, event ChatSentEvent) {
+	engine.Emit(ChatFlaggedEvent{})
+}
+
+// TestTrailRecordsWhoEmittedWhat verifies entries carry the actor, metadata, time, and payload
+func TestTrailRecordsWhoEmittedWhat(t *testing.T) {
+	engine, trail := newAuditedEngine()
+	engine.EmitWithMetadata(atmos.Metadata{"actor": "alice", "ip": "10.0.0.1"}, ChatSentEvent{Text: "gg"})
+	engine.EmitAs("bob", ChatSentEvent{Text: "ez"})
+
+	entries, err := trail.Query()
+	require.NoError(t, err)
+	require.Len(t, entries, 4)
+	assert.Equal(t, audit.Entry{
+		Seq:      1,
+		Type:     "chat_sent",
+		Actor:    "alice",
+		At:       epoch.Add(time.Minute),
+		Metadata: map[string]string{"actor": "alice", "ip": "10.0.0.1"},
+		Data:     []byte(`{"type":"chat_sent","data":{"text":"gg"}}`),
+	}, entries[0])
+	assert.Equal(t, "chat_flagged", entries[1].Type)
+	assert.Equal(t, "alice", entries[1].Actor, "Derived events are attributed to the emitter")
+	assert.Equal(t, "bob", entries[2].Actor)
+	assert.Nil(t, entries[2].Metadata)
+}
+
+// TestTrailQueries verifies filters combine
+func TestTrailQueries(t *testing.T) {
+	engine, trail := newAuditedEngine(audit.Types("chat_sent"))
+	engine.EmitWithMetadata(atmos.Metadata{"actor": "alice", "ip": "10.0.0.1"}, ChatSentEvent{Text: "1"})
+	engine.EmitAs("bob", ChatSentEvent{Text: "2"})
+	engine.EmitWithMetadata(atmos.Metadata{"actor": "alice", "ip": "10.0.0.2"}, ChatSentEvent{Text: "3"})
+
+	entries, err := trail.Query(audit.ByActor("alice"), audit.Between(epoch.Add(2*time.Minute), time.Time{}))
+	require.NoError(t, err)
+	require.Len(t, entries, 1)
+	assert.Equal(t, uint64(3), entries[0].Seq)
+
+	entries, err = trail.Query(audit.WithMetadata("ip", "10.0.0.1"), audit.OfType("chat_sent", "chat_flagged"))
+	require.NoError(t, err)
+	assert.Len(t, entries, 1)
+
+	actors, err := trail.Actors()
+	require.NoError(t, err)
+	assert.Equal(t, map[string]int{"alice": 2, "bob": 1}, actors)
+}
+
+// failingStore refuses every entry
+type failingStore struct{ audit.MemoryStore }
+
+func (*failingStore) Append(entry audit.Entry) error { return errors.New("disk full") }
+
+// TestTrailSkipsReplaysAndReportsErrors verifies replays aren't audited and store errors don't disrupt emits
+func TestTrailSkipsReplaysAndReportsErrors(t *testing.T) {
+	var errs []error
+	trail := audit.New(&failingStore{}, audit.OnError(func(err error) { errs = append(errs, err) }))
+	engine := atmos.NewEngine()
+	engine.ThenAll(trail)
+
+	require.NoError(t, engine.Replay([]atmos.Event{ChatSentEvent{Text: "old"}}))
+	assert.Empty(t, errs)
+	assert.True(t, engine.Emit(ChatSentEvent{Text: "new"}))
+	assert.EqualError(t, errors.Join(errs...), "disk full")
+}
+
+// SecretSentEvent carries a field encrypted in the log
+type SecretSentEvent struct {
+	Text string `json:"text" atmos:"encrypt"`
+}
+
+func (e SecretSentEvent) Type() string { return "secret_sent" }
+
+// TestTrailKeepsEncryptedFieldsEncrypted verifies entries are written like the log, and decode back
+func TestTrailKeepsEncryptedFieldsEncrypted(t *testing.T) {
+	keys, err := atmos.NewAESKeyService(bytes.Repeat([]byte{7}, 32))
+	require.NoError(t, err)
+	engine := atmos.NewEngine(atmos.WithFieldEncryption(keys))
+	engine.RegisterEventTypes(SecretSentEvent{})
+	trail := audit.New(audit.NewMemoryStore())
+	engine.ThenAll(trail)
+	require.True(t, engine.Emit(SecretSentEvent{Text: "launch codes"}))
+
+	entries, err := trail.Query()
+	require.NoError(t, err)
+	require.Len(t, entries, 1)
+	assert.NotContains(t, string(entries[0].Data), "launch codes")
+	event, err := engine.UnmarshalEvent(entries[0].Data)
+	require.NoError(t, err)
+	assert.Equal(t, "launch codes", atmos.Deref(event).(SecretSentEvent).Text)
+}
+
+// TestTrailReportsUnsupportedEngines verifies other engine implementations are reported instead of panicking
+func TestTrailReportsUnsupportedEngines(t *testing.T) {
+	var errs []error
+	trail := audit.New(audit.NewMemoryStore(), audit.OnError(func(err error) { errs = append(errs, err) }))
+	trail.Handle(otherEngine{}, ChatSentEvent{Text: "hi"})
+	assert.Equal(t, []error{audit.ErrUnsupportedEngine}, errs)
+}
+
+// otherEngine is an engine that isn't an *atmos.Engine
+type otherEngine struct{ types.Engine }
@@ -0,0 +1,148 @@
+package atmos
+
+import (
+	"errors"
+	"time"
+
+	"github.com/cumulusrpg/atmos/types"
+)
+
+// ErrNoAutosave is returned by Autosave when the engine has no autosave target
+var ErrNoAutosave = errors.New("autosave is not configured")
+
+// autosaver copies the engine's log and snapshots to a durable repository
+type autosaver struct {
+	target   types.EventRepository
+	every    int           // save after this many commits (0 disables)
+	interval time.Duration // save on the first commit this long after the last save (0 disables)
+	now      func() time.Time
+	onSaved  func(events int)
+	onFailed func(err error)
+
+	pending int       // commits since the last save
+	savedAt time.Time // time of the last save attempt
+}
+
+// AutosaveOption configures autosaving
+type AutosaveOption func(*autosaver)
+
+// EveryEvents saves after every n committed events
+func EveryEvents(n int) AutosaveOption {
+	return func(a *autosaver) {
+		a.every = n
+	}
+}
+
+// EveryInterval saves on the first commit at least d after the previous save
+func EveryInterval(d time.Duration) AutosaveOption {
+	return func(a *autosaver) {
+		a.interval = d
+	}
+}
+
+// OnSaved is called after each successful save with the length of the saved log
+func OnSaved(fn func(events int)) AutosaveOption {
+	return func(a *autosaver) {
+		a.onSaved = fn
+	}
+}
+
+// OnSaveFailed is called when a save fails; the failure never affects the emit
+func OnSaveFailed(fn func(err error)) AutosaveOption {
+	return func(a *autosaver) {
+		a.onFailed = fn
+	}
+}
+
+// WithAutosaveClock sets the time source for EveryInterval (for tests)
+func WithAutosaveClock(now func() time.Time) AutosaveOption {
+	return func(a *autosaver) {
+		a.now = now
+	}
+}
+
+// WithAutosave persists the log to target as the game is played, replacing
+// target's contents on each save. Snapshots are copied too when both
+// repositories support them. Saves happen after an outermost Emit completes
+// (so derived events are always saved with their cause); without EveryEvents
+// or EveryInterval, every such Emit is saved.
+//
+//	engine := NewEngine(WithAutosave(fileRepo, EveryEvents(10), EveryInterval(time.Minute)))
+func WithAutosave(target types.EventRepository, opts ...AutosaveOption) EngineOption {
+	return func(e *Engine) {
+		a := &autosaver{
+			target: target,
+			now:    time.Now,
+		}
+
+		// Apply options
+		for _, opt := range opts {
+			opt(a)
+		}
+
+		if a.every == 0 && a.interval == 0 {
+			a.every = 1
+		}
+		a.savedAt = a.now()
+		e.autosave = a
+	}
+}
+
+// Autosave saves to the autosave target now, e.g. before quitting
+func (e *Engine) Autosave() error {
+	if e.autosave == nil {
+		return ErrNoAutosave
+	}
+	return e.autosave.save(e)
+}
+
+// settled saves after an outermost emit if the policy is due
+func (a *autosaver) settled(e *Engine) {
+	if a.pending == 0 {
+		return
+	}
+	due := (a.every > 0 && a.pending >= a.every) ||
+		(a.interval > 0 && a.now().Sub(a.savedAt) >= a.interval)
+	if due {
+		_ = a.save(e) // Reported through OnSaveFailed
+	}
+}
+
+// save copies the log and snapshots to the target
+func (a *autosaver) save(e *Engine) error {
+	a.pending = 0
+	a.savedAt = a.now()
+
+	events := e.GetEvents()
+	err := a.target.SetAll(e, events)
+	if snapshots, ok := a.target.(types.SnapshotRepository); ok && err == nil {
+		err = copySnapshots(e, snapshots)
+	}
+
+	if err != nil {
+		if a.onFailed != nil {
+			a.onFailed(err)
+		}
+		return err
+	}
+	if a.onSaved != nil {
+		a.onSaved(len(events))
+	}
+	return nil
+}
+
+// copySnapshots mirrors the engine's snapshots into another repository
+func copySnapshots(e *Engine, target types.SnapshotRepository) error {
+	for _, state := range e.StateNames() {
+		var err error
+		if snapshot, exists := e.GetSnapshot(state); exists {
+			err = target.SetSnapshot(state, snapshot)
+		} else if _, exists := target.GetSnapshot(state); exists {
+			err = target.ClearSnapshot(state)
+		}
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
@@ -0,0 +1,113 @@
+package atmos
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/cumulusrpg/atmos/repository"
+	"github.com/cumulusrpg/atmos/types"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// unwritableRepository refuses every save
+type unwritableRepository struct {
+	CustomRepository
+}
+
+func (r *unwritableRepository) SetAll(engine types.Engine, events []types.Event) error {
+	return errors.New("disk full")
+}
+
+// TestAutosaveEveryEvents verifies the log is saved after every n commits
+func TestAutosaveEveryEvents(t *testing.T) {
+	target := repository.NewInMemory()
+	var saved []int
+	engine := NewEngine(WithAutosave(target, EveryEvents(2), OnSaved(func(events int) {
+		saved = append(saved, events)
+	})))
+
+	engine.Emit(TestEvent{Name: "a"})
+	assert.Empty(t, target.GetAll(engine))
+	engine.Emit(TestEvent{Name: "b"})
+	assert.Len(t, target.GetAll(engine), 2)
+	engine.Emit(TestEvent{Name: "c"})
+	engine.Emit(TestEvent{Name: "d"})
+	assert.Equal(t, engine.GetEvents(), target.GetAll(engine))
+	assert.Equal(t, []int{2, 4}, saved)
+}
+
+// TestAutosaveEveryInterval verifies commits are saved once the interval has passed
+func TestAutosaveEveryInterval(t *testing.T) {
+	now := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	target := repository.NewInMemory()
+	engine := NewEngine(WithAutosave(target, EveryInterval(time.Minute),
+		WithAutosaveClock(func() time.Time { return now })))
+
+	engine.Emit(TestEvent{Name: "a"})
+	now = now.Add(30 * time.Second)
+	engine.Emit(TestEvent{Name: "b"})
+	assert.Empty(t, target.GetAll(engine))
+
+	now = now.Add(30 * time.Second)
+	engine.Emit(TestEvent{Name: "c"})
+	assert.Len(t, target.GetAll(engine), 3)
+
+	now = now.Add(10 * time.Second)
+	engine.Emit(TestEvent{Name: "d"})
+	assert.Len(t, target.GetAll(engine), 3, "The interval restarts at each save")
+}
+
+// TestAutosaveWaitsForDerivedEvents verifies a cascade is saved as a whole
+func TestAutosaveWaitsForDerivedEvents(t *testing.T) {
+	target := repository.NewInMemory()
+	var saved []int
+	engine := NewEngine(WithAutosave(target, OnSaved(func(events int) {
+		saved = append(saved, events)
+	})))
+	engine.When("order_placed").Then(NewTypedListener(
+		TypedListenerFunc[OrderPlacedEvent](func(e *Engine, event OrderPlacedEvent) {
+			e.Emit(InvoiceGeneratedEvent{OrderID: event.OrderID})
+		}),
+	))
+
+	engine.Emit(OrderPlacedEvent{})
+	assert.Equal(t, []int{2}, saved, "One save after the derived event")
+	assert.Len(t, target.GetAll(engine), 2)
+}
+
+// TestAutosaveCopiesSnapshots verifies snapshots follow the log into the target
+func TestAutosaveCopiesSnapshots(t *testing.T) {
+	target := repository.NewInMemorySnapshot()
+	engine := NewEngine(
+		WithRepository(repository.NewInMemorySnapshot()),
+		WithAutosave(target))
+	engine.RegisterState("count", 0)
+	require.NoError(t, engine.SetSnapshot("count", 5))
+
+	engine.Emit(TestEvent{Name: "a"})
+	snapshot, exists := target.GetSnapshot("count")
+	assert.True(t, exists)
+	assert.Equal(t, "5", string(snapshot))
+
+	require.NoError(t, engine.ClearSnapshot("count"))
+	require.NoError(t, engine.Autosave())
+	_, exists = target.GetSnapshot("count")
+	assert.False(t, exists)
+}
+
+// TestAutosaveFailuresAreReported verifies failed saves don't reject emits
+func TestAutosaveFailuresAreReported(t *testing.T) {
+	var failures []error
+	engine := NewEngine(WithAutosave(&unwritableRepository{}, OnSaveFailed(func(err error) {
+		failures = append(failures, err)
+	})))
+
+	assert.True(t, engine.Emit(TestEvent{Name: "a"}))
+	require.Len(t, failures, 1)
+	assert.EqualError(t, failures[0], "disk full")
+	assert.EqualError(t, engine.Autosave(), "disk full")
+
+	assert.ErrorIs(t, NewEngine().Autosave(), ErrNoAutosave)
+}
@@ -0,0 +1,125 @@
+package atmos
+
+import (
+	"errors"
+	"reflect"
+	"strings"
+	"sync"
+	"unicode"
+)
+
+// AutoEvent is a marker that gives an event a Type() derived from its Go type
+// name, for simple games that don't want a hand-written Type() per event:
+//
+//	type MoveMadeEvent struct {
+//		atmos.AutoEvent[MoveMadeEvent]
+//		Player string
+//	}
+//
+//	MoveMadeEvent{}.Type() // "move_made" with the default SnakeCase convention
+type AutoEvent[T any] struct{}
+
+// Type returns the event type derived from T's name by the auto event naming convention
+func (AutoEvent[T]) Type() string {
+	return autoEventName(reflect.TypeOf((*T)(nil)).Elem().Name())
+}
+
+// autoEventStruct returns the struct type an AutoEvent marks
+func (AutoEvent[T]) autoEventStruct() reflect.Type {
+	return reflect.TypeOf((*T)(nil)).Elem()
+}
+
+// autoTyped is implemented by events embedding AutoEvent
+type autoTyped interface {
+	autoEventStruct() reflect.Type
+}
+
+// SnakeCase is the default auto event naming convention: it drops an "Event"
+// suffix and converts the rest to snake_case (MoveMadeEvent -> move_made,
+// HTTPSyncEvent -> http_sync)
+func SnakeCase(goName string) string {
+	runes := []rune(strings.TrimSuffix(goName, "Event"))
+	var b strings.Builder
+	for i, r := range runes {
+		if unicode.IsUpper(r) {
+			startsWord := i > 0 && (unicode.IsLower(runes[i-1]) ||
+				(i+1 < len(runes) && unicode.IsLower(runes[i+1]) && unicode.IsUpper(runes[i-1])))
+			if startsWord {
+				b.WriteByte('_')
+			}
+			r = unicode.ToLower(r)
+		}
+		b.WriteRune(r)
+	}
+	return b.String()
+}
+
+// autoNaming is the process-wide naming convention. It can't be per engine
+// because Type() is called on event values outside of any engine.
+var autoNaming = struct {
+	sync.RWMutex
+	naming func(string) string
+	custom bool
+}{naming: SnakeCase}
+
+// autoEventName applies the naming convention
+func autoEventName(goName string) string {
+	autoNaming.RLock()
+	defer autoNaming.RUnlock()
+	return autoNaming.naming(goName)
+}
+
+// ErrAutoNamingConflict is returned by emits on an engine whose auto event
+// naming convention differs from one already in use (see WithAutoEventTypes)
+var ErrAutoNamingConflict = errors.New("conflicting auto event naming conventions")
+
+// WithAutoEventTypes opts an engine into AutoEvent conventions: events
+// embedding AutoEvent are registered for deserialization the first time they
+// are emitted, so they need neither a Type() method nor a factory. (To load
+// a saved log before any event is emitted, pass prototypes to RegisterEventTypes.)
+//
+// naming sets the convention used to derive type names (nil keeps SnakeCase).
+// Because Type() is a method on event values, every engine in the process
+// must agree on it: an engine configured with a different convention than
+// the one in use fails its emits with ErrAutoNamingConflict.
+func WithAutoEventTypes(naming func(goName string) string) EngineOption {
+	return func(e *Engine) {
+		e.autoEventTypes = true
+		if naming != nil {
+			e.autoNamingErr = setAutoNaming(naming)
+		}
+	}
+}
+
+// setAutoNaming installs a custom convention, unless another one is installed
+func setAutoNaming(naming func(string) string) error {
+	autoNaming.Lock()
+	defer autoNaming.Unlock()
+
+	if autoNaming.custom && reflect.ValueOf(autoNaming.naming).Pointer() != reflect.ValueOf(naming).Pointer() {
+		return ErrAutoNamingConflict
+	}
+	autoNaming.naming = naming
+	autoNaming.custom = true
+	return nil
+}
+
+// registerAutoEvent registers a factory for an AutoEvent type the first time one is emitted
+func (e *Engine) registerAutoEvent(event Event) error {
+	auto, ok := event.(autoTyped)
+	if !ok {
+		return nil
+	}
+	if e.autoNamingErr != nil {
+		return e.autoNamingErr
+	}
+	if _, exists := e.eventFactories[event.Type()]; exists {
+		return nil
+	}
+
+	structType := auto.autoEventStruct()
+	e.RegisterEventType(event.Type(), func() Event {
+		return reflect.New(structType).Interface().(Event)
+	})
+	return nil
+}
@@ -0,0 +1,71 @@
+package atmos
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+type CardPlayedEvent struct {
+	AutoEvent[CardPlayedEvent]
+	Card string `json:"card"`
+}
+
+// TestSnakeCase verifies type names derived from Go type names
+func TestSnakeCase(t *testing.T) {
+	assert.Equal(t, "move_made", SnakeCase("MoveMadeEvent"))
+	assert.Equal(t, "http_sync", SnakeCase("HTTPSyncEvent"))
+	assert.Equal(t, "player_id_set", SnakeCase("PlayerIDSet"))
+	assert.Equal(t, "turn", SnakeCase("Turn"))
+}
+
+// TestAutoEventTypes verifies marker-embedding events need no Type() or factory
+func TestAutoEventTypes(t *testing.T) {
+	assert.Equal(t, "card_played", CardPlayedEvent{}.Type())
+	assert.Equal(t, "card_played", (&CardPlayedEvent{}).Type())
+
+	engine := NewEngine(WithAutoEventTypes(nil))
+	engine.RegisterState("played", 0)
+	engine.When("card_played").Updates("played", func(e *Engine, state interface{}, event Event) interface{} {
+		return state.(int) + 1
+	})
+
+	assert.True(t, engine.Emit(CardPlayedEvent{Card: "ace"}))
+	assert.Equal(t, 1, engine.GetState("played"))
+
+	data, err := engine.MarshalEvents(engine.GetEvents())
+	assert.NoError(t, err)
+	assert.JSONEq(t, `[{"type":"card_played","data":{"card":"ace"}}]`, string(data))
+
+	restored, err := engine.UnmarshalEvent([]byte(`{"type":"card_played","data":{"card":"king"}}`))
+	assert.NoError(t, err, "The factory was registered on first emit")
+	assert.Equal(t, &CardPlayedEvent{Card: "king"}, restored)
+
+	// Engines that haven't opted in don't auto-register
+	plain := NewEngine()
+	plain.Emit(CardPlayedEvent{Card: "ace"})
+	_, ok := plain.NewEvent("card_played")
+	assert.False(t, ok)
+}
+
+// TestAutoEventNamingConflict verifies engines disagreeing on the naming convention fail their emits instead of panicking
+func TestAutoEventNamingConflict(t *testing.T) {
+	defer func() {
+		autoNaming.naming, autoNaming.custom = SnakeCase, false
+	}()
+	upper := func(goName string) string { return strings.ToUpper(goName) }
+	lower := func(goName string) string { return strings.ToLower(goName) }
+
+	first := NewEngine(WithAutoEventTypes(upper))
+	assert.True(t, first.Emit(CardPlayedEvent{Card: "ace"}))
+	assert.Equal(t, "CARDPLAYEDEVENT", first.GetEvents()[0].Type())
+
+	second := NewEngine(WithAutoEventTypes(lower))
+	result := second.EmitWithResult(CardPlayedEvent{Card: "ace"})
+	assert.ErrorIs(t, result.Err, ErrAutoNamingConflict)
+	assert.Empty(t, second.GetEvents())
+	assert.True(t, second.Emit(TestEvent{Name: "plain"}), "Other events are unaffected")
+
+	assert.True(t, NewEngine(WithAutoEventTypes(upper)).Emit(CardPlayedEvent{Card: "king"}), "Agreeing engines are fine")
+}
@@ -0,0 +1,252 @@
+package atmos
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/cumulusrpg/atmos/types"
+)
+
+// EmitAll emits several events as one atomic batch: either every event is
+// committed or none is. Each event is validated against the state left by
+// the events before it in the batch, then before hooks run for each, the
+// batch is persisted, and finally listeners run for each in order (so events
+// they derive are emitted after the whole batch).
+//
+// Repositories implementing types.TxRepository persist the batch in one
+// transaction (for IdentifiedRepositories, one that is a
+// types.IdentifiedTx). Others get the events added one by one, with the log
+// restored if an add fails part-way.
+//
+// A rejection reports the first failing event's validator, with the event's
+// position in the batch in Reason.
+func (e *Engine) EmitAll(events ...Event) (result EmitResult) {
+	failed := events // the events a failure is reported against
+	defer func() {
+		for _, event := range failed {
+			e.metaRejected(event, result)
+		}
+	}()
+
+	for _, event := range events {
+		if err := e.precheck(event); err != nil {
+			failed = []Event{event}
+			return EmitResult{Err: err}
+		}
+	}
+	if e.paused != nil {
+		return e.hold(events, true, "")
+	}
+	if e.payloadLimit > 0 {
+		prepared := make([]Event, len(events))
+		for i, event := range events {
+			failed = []Event{event}
+			var err error
+			if prepared[i], err = e.limitPayload(event); errors.Is(err, ErrPayloadTooLarge) {
+				return EmitResult{
+					RejectedBy: PayloadSizeValidator,
+					Reason:     fmt.Sprintf("event %d (%s): %s", i, event.Type(), err),
+				}
+			} else if err != nil {
+				return EmitResult{Err: err}
+			}
+		}
+		events, failed = prepared, prepared
+	}
+	defer e.transaction(&result)()
+	batchTypes := e.batchTypes(events)
+	defer e.begin(StageEmit, "EmitAll", batchTypes)()
+
+	// Validate in a sandbox that reads the log in place and sees the batch
+	// so far, without running hooks or listeners
+	endValidation := e.begin(StageValidator, "EmitAll", batchTypes)
+	defer func() { endValidation() }() // On rejection
+	sandboxed := &sandboxLog{engine: e}
+	sandbox := e.forkOver(sandboxed)
+	perEvent := make([][]Warning, len(events))
+	var warnings []Warning
+	var remote []remoteCheck // run together once every event passes the rest
+	for i, event := range events {
+		failed = []Event{event}
+		explanation := sandbox.Explain(event)
+		if !explanation.Accepted {
+			countRejection(explanation.FirstFailure.Validator)
+			return EmitResult{
+				RejectedBy: explanation.FirstFailure.Name,
+				Reason:     fmt.Sprintf("event %d (%s): %s", i, event.Type(), explanation.FirstFailure.Reason),
+				Message:    explanation.FirstFailure.Message,
+			}
+		}
+		perEvent[i] = explanation.Warnings
+		warnings = append(warnings, explanation.Warnings...)
+		remote = append(remote, sandbox.remoteChecks(event, i)...)
+		sandboxed.batch = append(sandboxed.batch, event)
+		sandbox.revision++
+	}
+	if check, err := e.checkRemote(batchTypes, remote); check != nil {
+		failed = []Event{check.event}
+		return EmitResult{
+			RejectedBy: validatorName(check.validator),
+			Reason:     fmt.Sprintf("event %d (%s): %s", check.position, check.event.Type(), e.reason(err)),
+			Message:    messageOf(err),
+		}
+	}
+	if e.blobs != nil {
+		// Blobs are only stored once the whole batch passes validation
+		checked := make([]Event, len(events))
+		for i, event := range events {
+			failed = []Event{event}
+			var err error
+			if checked[i], err = e.commitBlobs(event); errors.Is(err, ErrPayloadTooLarge) {
+				return EmitResult{
+					RejectedBy: PayloadSizeValidator,
+					Reason:     fmt.Sprintf("event %d (%s): %s", i, event.Type(), err),
+				}
+			} else if err != nil {
+				return EmitResult{Err: err}
+			}
+		}
+		events = checked
+	}
+	failed = events
+	endValidation()
+	endValidation = untraced
+
+	previousWarnings := e.warnings
+	defer func() { e.warnings = previousWarnings }()
+
+	e.depth++
+	defer e.settle()
+
+	for i, event := range events {
+		e.within(event, perEvent[i], func() {
+			for _, hook := range e.beforeHooks[event.Type()] {
+				end := e.begin(StageHook, hook, event.Type())
+				hook.Handle(e, event)
+				end()
+			}
+		})
+	}
+
+	endCommit := e.begin(StageRepository, "commit", batchTypes)
+	err := e.commitBatch(events)
+	endCommit()
+	if err != nil {
+		return EmitResult{Err: &RepositoryError{Op: "commit", Err: err}}
+	}
+	e.committed = append(e.committed, events...)
+	position := len(e.committed) - len(events)
+	if e.autosave != nil {
+		e.autosave.pending += len(events)
+	}
+	e.uncheckpointed += len(events)
+
+	e.listen(func() {
+		for i, event := range events {
+			e.within(event, perEvent[i], func() { e.notify(event) })
+		}
+	})
+
+	return EmitResult{Accepted: true, Warnings: warnings, Position: position}
+}
+
+// errSandboxReadOnly is returned for attempts to replace a batch sandbox's log
+var errSandboxReadOnly = errors.New("the batch sandbox's log is read-only")
+
+// sandboxLog is the log EmitAll validates a batch against: the engine's
+// log, read in place, followed by the batch events validated so far
+type sandboxLog struct {
+	engine *Engine
+	batch  []Event
+}
+
+// Add appends to the batch
+func (l *sandboxLog) Add(engine types.Engine, event Event) error {
+	l.batch = append(l.batch, event)
+	return nil
+}
+
+// GetAll returns the engine's log followed by the batch
+func (l *sandboxLog) GetAll(engine types.Engine) []Event {
+	return append(l.engine.GetEvents(), l.batch...)
+}
+
+// SetAll refuses to replace the engine's log
+func (l *sandboxLog) SetAll(engine types.Engine, events []Event) error {
+	return errSandboxReadOnly
+}
+
+// Range visits the engine's log in place, then the batch
+func (l *sandboxLog) Range(engine types.Engine, fn func(event Event) bool) {
+	more := true
+	l.engine.eachEvent(func(event Event) bool {
+		more = fn(event)
+		return more
+	})
+	for _, event := range l.batch {
+		if !more {
+			return
+		}
+		more = fn(event)
+	}
+}
+
+// within runs fn with an event's warnings current and, when declared emits
+// are enforced, the event on the emitting stack
+func (e *Engine) within(event Event, warnings []Warning, fn func()) {
+	e.warnings = warnings
+	if e.enforceEmits {
+		e.emitting = append(e.emitting, event.Type())
+		defer func() { e.emitting = e.emitting[:len(e.emitting)-1] }()
+	}
+	fn()
+}
+
+// commitBatch persists events atomically, in one transaction when the
+// repository supports it (and, if it stores IDs, the transaction carries them)
+func (e *Engine) commitBatch(events []Event) error {
+	defer func() { e.revision++ }()
+	identified, hasIDs := e.repository.(types.IdentifiedRepository)
+	if txRepo, ok := e.repository.(types.TxRepository); ok {
+		tx, err := txRepo.Begin(e)
+		if err != nil {
+			return err
+		}
+		if identifiedTx, carriesIDs := tx.(types.IdentifiedTx); !hasIDs || carriesIDs {
+			for _, event := range events {
+				if hasIDs {
+					err = identifiedTx.AppendIdentified(Envelope{ID: e.nextID(), Event: event, Metadata: e.metadata})
+				} else {
+					err = tx.Append(event)
+				}
+				if err != nil {
+					_ = tx.Rollback()
+					return err
+				}
+			}
+			return tx.Commit()
+		}
+		if err := tx.Rollback(); err != nil {
+			return err
+		}
+	}
+
+	// Compensate for a partial failure by restoring the log
+	var restore func() error
+	if hasIDs {
+		before := identified.Envelopes(e)
+		restore = func() error { return identified.SetEnvelopes(e, before) }
+	} else {
+		before := e.GetEvents()
+		restore = func() error { return e.repository.SetAll(e, before) }
+	}
+	for _, event := range events {
+		if _, err := e.add(event, ""); err != nil {
+			if restoreErr := restore(); restoreErr != nil {
+				return fmt.Errorf("%w (restoring the log also failed: %v)", err, restoreErr)
+			}
+			return err
+		}
+	}
+	return nil
+}
@@ -0,0 +1,105 @@
+package atmos
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/cumulusrpg/atmos/repository"
+	"github.com/cumulusrpg/atmos/types"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// affordable rejects spending more tokens than the balance holds
+type affordable struct{}
+
+func (affordable) Validate(engine types.Engine, event types.Event) bool {
+	return event.(TokensSpentEvent).Amount <= engine.GetState("balance").(TokenBalance).Tokens
+}
+
+// newBatchEngine guards the token balance
+func newBatchEngine(opts ...EngineOption) *Engine {
+	engine := newTokenEngine(opts...)
+	engine.When("tokens_spent").Requires(affordable{})
+	return engine
+}
+
+// flakyRepository fails the nth add
+type flakyRepository struct {
+	CustomRepository
+	failAt int
+}
+
+func (r *flakyRepository) Add(engine types.Engine, event types.Event) error {
+	if r.addCalls+1 == r.failAt {
+		r.addCalls++
+		return errors.New("connection reset")
+	}
+	return r.CustomRepository.Add(engine, event)
+}
+
+// TestEmitAllCommitsTogether verifies a valid batch commits and notifies listeners after the commit
+func TestEmitAllCommitsTogether(t *testing.T) {
+	engine := newBatchEngine()
+	var seen []int
+	engine.When("tokens_spent").Then(NewTypedListener(
+		TypedListenerFunc[TokensSpentEvent](func(e *Engine, event TokensSpentEvent) {
+			seen = append(seen, len(e.GetEvents()))
+		}),
+	))
+
+	result := engine.EmitAll(TokensSpentEvent{Amount: 1}, TokensSpentEvent{Amount: 2})
+	assert.True(t, result.Accepted)
+	assert.Equal(t, TokenBalance{Tokens: 0}, engine.GetState("balance"))
+	assert.Equal(t, []int{2, 2}, seen, "Listeners run once the whole batch is committed")
+}
+
+// TestEmitAllValidatesAgainstEarlierEvents verifies a batch is rejected as a whole
+func TestEmitAllValidatesAgainstEarlierEvents(t *testing.T) {
+	engine := newBatchEngine()
+
+	result := engine.EmitAll(TokensSpentEvent{Amount: 2}, TokensSpentEvent{Amount: 2})
+	assert.False(t, result.Accepted)
+	assert.Equal(t, "atmos.affordable", result.RejectedBy)
+	assert.Contains(t, result.Reason, "event 1 (tokens_spent)")
+	assert.Empty(t, engine.GetEvents(), "Nothing is committed")
+	assert.Equal(t, TokenBalance{Tokens: 3}, engine.GetState("balance"))
+}
+
+// TestEmitAllUsesTransactions verifies transactional repositories receive the batch in one commit
+func TestEmitAllUsesTransactions(t *testing.T) {
+	repo := repository.NewInMemoryOutbox()
+	engine := newBatchEngine(WithRepository(repo))
+
+	require.True(t, engine.EmitAll(TokensSpentEvent{Amount: 1}, TokensSpentEvent{Amount: 1}).Accepted)
+	pending, err := repo.PendingOutbox(0)
+	require.NoError(t, err)
+	assert.Len(t, pending, 2, "The outbox is written with the batch")
+}
+
+// TestEmitAllRestoresLogOnPartialFailure verifies non-transactional repositories are compensated
+func TestEmitAllRestoresLogOnPartialFailure(t *testing.T) {
+	repo := &flakyRepository{failAt: 3}
+	engine := newBatchEngine(WithRepository(repo))
+	require.True(t, engine.Emit(TokensSpentEvent{Amount: 1}))
+
+	result := engine.EmitAll(TokensSpentEvent{Amount: 1}, TokensSpentEvent{Amount: 1})
+	assert.EqualError(t, result.Err, "connection reset")
+	assert.Len(t, engine.GetEvents(), 1, "The batch's first event was rolled back")
+}
+
+// TestEmitAllSandboxReadsTheLogInPlace verifies the batch is validated over the engine's log, with its settings, without loading a copy
+func TestEmitAllSandboxReadsTheLogInPlace(t *testing.T) {
+	engine := newBatchEngine(WithPayloadValidation())
+	loads := 0
+	engine.OnLoad(func(e *Engine) { loads++ })
+	require.True(t, engine.Emit(TokensSpentEvent{Amount: 1}))
+
+	result := engine.EmitAll(TokensSpentEvent{Amount: 1}, TokensSpentEvent{Amount: 1})
+	require.True(t, result.Accepted)
+	assert.Equal(t, TokenBalance{Tokens: 0}, engine.GetState("balance"))
+	assert.Zero(t, loads)
+
+	result = engine.EmitAll(MarkPlacedEvent{Player: "Z", Tags: []string{"a"}})
+	assert.Equal(t, "payload", result.RejectedBy, "The sandbox validates payloads like the engine")
+}
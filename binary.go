@@ -0,0 +1,246 @@
+package atmos
+
+import (
+	"bufio"
+	"bytes"
+	"compress/flate"
+	"encoding/binary"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"hash/crc32"
+	"io"
+)
+
+// binaryMagic starts every binary event log
+var binaryMagic = []byte("ATMB")
+
+// binaryVersion is the version of the layout written by MarshalEventsBinary
+const binaryVersion byte = 1
+
+// Header flags of a binary event log
+const (
+	binaryCompressed  byte = 1 << iota // records are DEFLATE-compressed
+	binaryChecksummed                  // a CRC-32 of everything before it ends the log
+)
+
+var (
+	// ErrInvalidBinary is returned for data that isn't a binary event log
+	ErrInvalidBinary = errors.New("not a binary event log")
+
+	// ErrChecksum is returned when a binary event log fails its checksum
+	ErrChecksum = errors.New("binary event log checksum mismatch")
+)
+
+// BinaryOption configures MarshalEventsBinary
+type BinaryOption func(*binaryFormat)
+
+// binaryFormat holds the header flags being written
+type binaryFormat struct {
+	flags byte
+}
+
+// WithCompression DEFLATE-compresses the records
+func WithCompression() BinaryOption {
+	return func(f *binaryFormat) {
+		f.flags |= binaryCompressed
+	}
+}
+
+// WithChecksum appends a CRC-32 so corrupted saves are detected on load
+func WithChecksum() BinaryOption {
+	return func(f *binaryFormat) {
+		f.flags |= binaryChecksummed
+	}
+}
+
+// MarshalEventsBinary serializes events like MarshalEvents, in a compact
+// binary layout for games where save size and load time matter:
+//
+//	"ATMB" | version (1 byte) | flags (1 byte) | record count (uvarint)
+//	records, each: type length (uvarint) | type | data length (uvarint) | JSON data
+//	CRC-32 (IEEE, big-endian) of all preceding bytes, with WithChecksum
+//
+// With WithCompression the records are DEFLATE-compressed.
+func (e *Engine) MarshalEventsBinary(events []Event, opts ...BinaryOption) ([]byte, error) {
+	return e.marshalBinary(events, nil, opts)
+}
+
+// MarshalLogBinary serializes the engine's log like MarshalEventsBinary,
+// with quarantined records written back in place, as MarshalLog does
+func (e *Engine) MarshalLogBinary(opts ...BinaryOption) ([]byte, error) {
+	return e.marshalBinary(e.GetEvents(), e.quarantined, opts)
+}
+
+// marshalBinary writes events, with quarantined records reinserted at their positions
+func (e *Engine) marshalBinary(events []Event, quarantined []QuarantinedRecord, opts []BinaryOption) ([]byte, error) {
+	format := &binaryFormat{}
+
+	// Apply options
+	for _, opt := range opts {
+		opt(format)
+	}
+
+	records, err := e.binaryRecords(events, quarantined)
+	if err != nil {
+		return nil, err
+	}
+
+	var buf bytes.Buffer
+	buf.Write(binaryMagic)
+	buf.WriteByte(binaryVersion)
+	buf.WriteByte(format.flags)
+	buf.Write(binary.AppendUvarint(nil, uint64(len(records))))
+
+	var body io.Writer = &buf
+	var compressor *flate.Writer
+	if format.flags&binaryCompressed != 0 {
+		compressor, _ = flate.NewWriter(&buf, flate.DefaultCompression) // Only fails for invalid levels
+		body = compressor
+	}
+	for _, record := range records {
+		var prefix []byte
+		prefix = binary.AppendUvarint(prefix, uint64(len(record.Type)))
+		prefix = append(prefix, record.Type...)
+		prefix = binary.AppendUvarint(prefix, uint64(len(record.Data)))
+		if _, err := body.Write(prefix); err != nil {
+			return nil, err
+		}
+		if _, err := body.Write(record.Data); err != nil {
+			return nil, err
+		}
+	}
+	if compressor != nil {
+		if err := compressor.Close(); err != nil {
+			return nil, err
+		}
+	}
+
+	if format.flags&binaryChecksummed != 0 {
+		_ = binary.Write(&buf, binary.BigEndian, crc32.ChecksumIEEE(buf.Bytes())) // Writes to a bytes.Buffer don't fail
+	}
+	return buf.Bytes(), nil
+}
+
+// UnmarshalEventsBinary deserializes MarshalEventsBinary output. Records
+// that don't decode are skipped or quarantined, as in UnmarshalEvents.
+func (e *Engine) UnmarshalEventsBinary(data []byte) ([]Event, error) {
+	header := len(binaryMagic) + 2
+	if len(data) < header || !bytes.Equal(data[:len(binaryMagic)], binaryMagic) {
+		return nil, ErrInvalidBinary
+	}
+	if version := data[len(binaryMagic)]; version != binaryVersion {
+		return nil, fmt.Errorf("%w: unsupported version %d", ErrInvalidBinary, version)
+	}
+	flags := data[len(binaryMagic)+1]
+
+	if flags&binaryChecksummed != 0 {
+		if len(data) < header+4 {
+			return nil, ErrChecksum
+		}
+		end := len(data) - 4
+		if crc32.ChecksumIEEE(data[:end]) != binary.BigEndian.Uint32(data[end:]) {
+			return nil, ErrChecksum
+		}
+		data = data[:end]
+	}
+
+	count, n := binary.Uvarint(data[header:])
+	if n <= 0 {
+		return nil, fmt.Errorf("%w: bad record count", ErrInvalidBinary)
+	}
+	var body io.Reader = bytes.NewReader(data[header+n:])
+	if flags&binaryCompressed != 0 {
+		body = flate.NewReader(body)
+	}
+	reader := bufio.NewReader(body)
+
+	var quarantined []QuarantinedRecord
+	events := make([]Event, 0, min(count, uint64(len(data))))
+	for i := uint64(0); i < count; i++ {
+		eventType, err := readBinaryField(reader)
+		if err != nil {
+			return nil, err
+		}
+		payload, err := readBinaryField(reader)
+		if err != nil {
+			return nil, err
+		}
+
+		if len(eventType) == 0 {
+			quarantined = e.quarantine(quarantined, len(events), "", payload, errors.New("unreadable record")) // Written from quarantine
+			continue
+		}
+		event, err := e.decodeRecord(string(eventType), payload)
+		if err != nil {
+			raw, _ := json.Marshal(EventWrapper{Type: string(eventType), Data: json.RawMessage(payload)})
+			quarantined = e.quarantine(quarantined, len(events), string(eventType), raw, err)
+			continue
+		}
+		events = append(events, event)
+	}
+	e.decoded(events, quarantined)
+	return events, nil
+}
+
+// binaryRecord is one event of a binary log
+type binaryRecord struct {
+	Type string
+	Data []byte
+}
+
+// binaryRecords encodes events as binary records, with quarantined records
+// reinserted at their positions
+func (e *Engine) binaryRecords(events []Event, quarantined []QuarantinedRecord) ([]binaryRecord, error) {
+	records := make([]binaryRecord, 0, len(events)+len(quarantined))
+	next := 0
+	for i := 0; i <= len(events); i++ {
+		for next < len(quarantined) && (quarantined[next].Position <= i || i == len(events)) {
+			records = append(records, quarantinedRecord(quarantined[next]))
+			next++
+		}
+		if i < len(events) {
+			payload, err := e.eventData(events[i])
+			if err != nil {
+				return nil, err
+			}
+			data, err := json.Marshal(payload)
+			if err != nil {
+				return nil, err
+			}
+			records = append(records, binaryRecord{Type: events[i].Type(), Data: data})
+		}
+	}
+	return records, nil
+}
+
+// quarantinedRecord converts a quarantined JSON record to a binary record;
+// records without a readable type keep their raw bytes under an empty type
+func quarantinedRecord(q QuarantinedRecord) binaryRecord {
+	var wrapper struct {
+		Type string          `json:"type"`
+		Data json.RawMessage `json:"data"`
+	}
+	if err := json.Unmarshal(q.Raw, &wrapper); err != nil || wrapper.Type == "" {
+		return binaryRecord{Data: q.Raw}
+	}
+	return binaryRecord{Type: wrapper.Type, Data: wrapper.Data}
+}
+
+// readBinaryField reads one length-prefixed field of a record
+func readBinaryField(reader *bufio.Reader) ([]byte, error) {
+	size, err := binary.ReadUvarint(reader)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrInvalidBinary, err)
+	}
+	// Read through a limit rather than allocating size up front, so a
+	// corrupted length can't exhaust memory
+	field, err := io.ReadAll(io.LimitReader(reader, int64(size)))
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrInvalidBinary, err)
+	}
+	if uint64(len(field)) != size {
+		return nil, fmt.Errorf("%w: truncated record", ErrInvalidBinary)
+	}
+	return field, nil
+}
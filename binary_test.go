@@ -0,0 +1,101 @@
+package atmos
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// TestBinaryRoundTrip verifies every option combination decodes to the same log
+func TestBinaryRoundTrip(t *testing.T) {
+	engine := NewEngine()
+	engine.RegisterEventTypes(CoinsFoundEvent{})
+	var events []Event
+	for i := 1; i <= 50; i++ {
+		events = append(events, &CoinsFoundEvent{Coins: i})
+	}
+
+	plain, err := engine.MarshalEventsBinary(events)
+	require.NoError(t, err)
+	assert.Equal(t, "ATMB", string(plain[:4]))
+	jsonLog, err := engine.MarshalEvents(events)
+	require.NoError(t, err)
+	assert.Less(t, len(plain), len(jsonLog))
+
+	for name, opts := range map[string][]BinaryOption{
+		"plain":      nil,
+		"compressed": {WithCompression()},
+		"checksum":   {WithChecksum()},
+		"both":       {WithCompression(), WithChecksum()},
+	} {
+		t.Run(name, func(t *testing.T) {
+			data, err := engine.MarshalEventsBinary(events, opts...)
+			require.NoError(t, err)
+			decoded, err := engine.UnmarshalEventsBinary(data)
+			require.NoError(t, err)
+			assert.Equal(t, events, decoded)
+		})
+	}
+
+	compressed, err := engine.MarshalEventsBinary(events, WithCompression())
+	require.NoError(t, err)
+	assert.Less(t, len(compressed), len(plain))
+}
+
+// TestBinaryDetectsCorruption verifies checksums, headers, and truncation are checked
+func TestBinaryDetectsCorruption(t *testing.T) {
+	engine := NewEngine()
+	engine.RegisterEventTypes(CoinsFoundEvent{})
+	events := []Event{CoinsFoundEvent{Coins: 1}, CoinsFoundEvent{Coins: 2}}
+
+	data, err := engine.MarshalEventsBinary(events, WithChecksum())
+	require.NoError(t, err)
+	flipped := append([]byte(nil), data...)
+	flipped[10] ^= 0xff
+	_, err = engine.UnmarshalEventsBinary(flipped)
+	assert.ErrorIs(t, err, ErrChecksum)
+
+	data, err = engine.MarshalEventsBinary(events)
+	require.NoError(t, err)
+	_, err = engine.UnmarshalEventsBinary(data[:len(data)-3])
+	assert.ErrorIs(t, err, ErrInvalidBinary)
+
+	_, err = engine.UnmarshalEventsBinary([]byte(`[{"type":"coins_found"}]`))
+	assert.ErrorIs(t, err, ErrInvalidBinary)
+
+	future := append([]byte(nil), data...)
+	future[4] = 2
+	_, err = engine.UnmarshalEventsBinary(future)
+	assert.ErrorContains(t, err, "unsupported version 2")
+}
+
+// TestBinaryKeepsQuarantinedRecords verifies undecodable records survive a binary round trip
+func TestBinaryKeepsQuarantinedRecords(t *testing.T) {
+	engine := NewEngine(WithQuarantine())
+	engine.RegisterEventTypes(RoundPlayedEvent{})
+	events, err := engine.UnmarshalEvents([]byte(mixedLog))
+	require.NoError(t, err)
+	engine.SetEvents(events)
+
+	data, err := engine.MarshalLogBinary(WithCompression())
+	require.NoError(t, err)
+	decoded, err := engine.UnmarshalEventsBinary(data)
+	require.NoError(t, err)
+	assert.Equal(t, events, decoded)
+	engine.SetEvents(decoded)
+	require.Len(t, engine.Quarantined(), 3)
+	assert.Equal(t, "emote", engine.Quarantined()[0].Type)
+
+	jsonLog, err := engine.MarshalLog()
+	require.NoError(t, err)
+	assert.JSONEq(t, mixedLog, string(jsonLog))
+
+	partial, err := engine.MarshalEventsBinary(events)
+	require.NoError(t, err)
+	plain := NewEngine()
+	plain.RegisterEventTypes(RoundPlayedEvent{})
+	decoded, err = plain.UnmarshalEventsBinary(partial)
+	require.NoError(t, err)
+	assert.Len(t, decoded, 2, "Only MarshalLogBinary writes quarantined records")
+}
@@ -0,0 +1,298 @@
+package atmos
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"reflect"
+	"strings"
+
+	"github.com/cumulusrpg/atmos/types"
+)
+
+// ErrBlobUnresolved is returned when reading a Blob that only holds a
+// reference, outside an engine with the store it refers to
+var ErrBlobUnresolved = errors.New("blob reference has no store to resolve it")
+
+// Blob is an event field for large binary content (map data, images).
+// With a blob store (see WithBlobStore), the engine checks the content in
+// at emit: the store keeps it and the committed event only a reference,
+// which Bytes resolves when listeners and reducers read it. Without one,
+// the content stays inline in the event.
+//
+//	type MapUploadedEvent struct {
+//		Name  string     `json:"name"`
+//		Tiles atmos.Blob `json:"tiles"`
+//	}
+//
+//	engine.Emit(MapUploadedEvent{Name: "keep", Tiles: atmos.NewBlob(data)})
+type Blob struct {
+	data  []byte
+	ref   string
+	store types.BlobStore
+}
+
+// NewBlob wraps content for an event field
+func NewBlob(data []byte) Blob {
+	return Blob{data: data}
+}
+
+// Bytes returns the content, fetching it from the store if the blob was
+// checked in (each call fetches again, so keep the result if reading
+// repeatedly)
+func (b Blob) Bytes() ([]byte, error) {
+	switch {
+	case b.ref == "":
+		return b.data, nil
+	case b.store == nil:
+		return nil, fmt.Errorf("%w: %s", ErrBlobUnresolved, b.ref)
+	}
+	return b.store.Get(b.ref)
+}
+
+// Ref returns the store's key for the content, or "" if it is inline
+func (b Blob) Ref() string {
+	return b.ref
+}
+
+// blobJSON is a Blob's serialized form: a reference or inline content
+type blobJSON struct {
+	Ref  string `json:"ref,omitempty"`
+	Data []byte `json:"data,omitempty"`
+}
+
+// MarshalJSON writes the reference, or the content if inline
+func (b Blob) MarshalJSON() ([]byte, error) {
+	if b.ref != "" {
+		return json.Marshal(blobJSON{Ref: b.ref})
+	}
+	return json.Marshal(blobJSON{Data: b.data})
+}
+
+// UnmarshalJSON reads a reference or inline content. The engine decoding
+// the event binds references to its store.
+func (b *Blob) UnmarshalJSON(data []byte) error {
+	var decoded blobJSON
+	if err := json.Unmarshal(data, &decoded); err != nil {
+		return err
+	}
+	*b = Blob{data: decoded.Data, ref: decoded.Ref}
+	return nil
+}
+
+// WithBlobStore checks the content of events' Blob fields into store once
+// the event passes validation, so the log only holds references. Blobs are
+// found in nested structs, behind pointers and in slices, arrays and maps,
+// but not behind interfaces. Rejected events store nothing, unless the
+// references their blobs get push them over WithMaxPayloadSize's limit.
+func WithBlobStore(store types.BlobStore) EngineOption {
+	return func(e *Engine) {
+		e.blobs = store
+	}
+}
+
+// blobType is the reflected type of Blob fields
+var blobType = reflect.TypeOf(Blob{})
+
+// pendingRef stands in for the reference an inline blob will get when
+// measuring an event before check-in, as long as a hex SHA-256 key (as
+// repository.InMemoryBlobs makes)
+var pendingRef = strings.Repeat("0", 64)
+
+// checkInBlobs returns a copy of an event with its inline Blob fields moved
+// to the blob store, or the event itself if it has none
+func (e *Engine) checkInBlobs(event Event) (Event, error) {
+	return e.rewriteBlobs(event, func(blob *Blob) error {
+		if blob.ref != "" || blob.data == nil {
+			return nil
+		}
+		key, err := e.blobs.Put(blob.data)
+		if err != nil {
+			return fmt.Errorf("storing blob of %s: %w", event.Type(), err)
+		}
+		*blob = Blob{ref: key, store: e.blobs}
+		return nil
+	})
+}
+
+// pendingBlobs returns a copy of an event with its inline Blob fields
+// replaced by stand-ins for their references, to measure it as committed
+// before checking it in
+func (e *Engine) pendingBlobs(event Event) Event {
+	pending, _ := e.rewriteBlobs(event, func(blob *Blob) error {
+		if blob.ref == "" && blob.data != nil {
+			*blob = Blob{ref: pendingRef}
+		}
+		return nil
+	})
+	return pending
+}
+
+// commitBlobs checks in an event's blobs once it has passed validation.
+// The payload limit was applied with stand-in references, so it is applied
+// again with the real ones.
+func (e *Engine) commitBlobs(event Event) (Event, error) {
+	if e.blobs == nil || !hasBlobs(reflect.TypeOf(event)) {
+		return event, nil
+	}
+	checked, err := e.checkInBlobs(event)
+	if err != nil || e.payloadLimit == 0 {
+		return checked, err
+	}
+	data, err := e.storedPayload(checked)
+	if err == nil && len(data) > e.payloadLimit {
+		err = e.tooLarge(len(data))
+	}
+	return checked, err
+}
+
+// rewriteBlobs returns a copy of an event with fn applied to each of its
+// Blobs, or the event itself if it has none
+func (e *Engine) rewriteBlobs(event Event, fn func(*Blob) error) (Event, error) {
+	value := reflect.ValueOf(event)
+	if e.blobs == nil || !hasBlobs(value.Type()) {
+		return event, nil
+	}
+	copied := reflect.New(value.Type()).Elem()
+	copied.Set(copyBlobs(value))
+	if err := eachBlob(copied, fn); err != nil {
+		return nil, err
+	}
+	return copied.Interface().(Event), nil
+}
+
+// bindBlobs points a decoded event's Blob references at the blob store
+func (e *Engine) bindBlobs(event Event) {
+	value := reflect.ValueOf(event)
+	if e.blobs == nil || value.Kind() != reflect.Ptr || value.IsNil() || !hasBlobs(value.Type()) {
+		return
+	}
+	_ = eachBlob(value.Elem(), func(blob *Blob) error {
+		if blob.ref != "" {
+			blob.store = e.blobs
+		}
+		return nil
+	})
+}
+
+// hasBlobs reports whether values of a type can hold Blobs: in fields
+// (including of nested structs), behind pointers, or in slices, arrays and
+// maps
+func hasBlobs(t reflect.Type) bool {
+	return holdsBlobs(t, map[reflect.Type]bool{})
+}
+
+// holdsBlobs is hasBlobs, skipping types already visited (which recursive
+// types revisit)
+func holdsBlobs(t reflect.Type, seen map[reflect.Type]bool) bool {
+	if t == blobType {
+		return true
+	}
+	if seen[t] {
+		return false
+	}
+	seen[t] = true
+	switch t.Kind() {
+	case reflect.Ptr, reflect.Slice, reflect.Array, reflect.Map:
+		return holdsBlobs(t.Elem(), seen)
+	case reflect.Struct:
+		for i := 0; i < t.NumField(); i++ {
+			if field := t.Field(i); field.IsExported() && holdsBlobs(field.Type, seen) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// copyBlobs returns a copy of a value sharing no pointers, slices or maps
+// that hold Blobs with it, so its Blobs can be rewritten without touching
+// the original
+func copyBlobs(value reflect.Value) reflect.Value {
+	t := value.Type()
+	if t == blobType || !hasBlobs(t) {
+		return value
+	}
+	copied := reflect.New(t).Elem()
+	switch t.Kind() {
+	case reflect.Ptr:
+		if value.IsNil() {
+			return value
+		}
+		copied.Set(reflect.New(t.Elem()))
+		copied.Elem().Set(copyBlobs(value.Elem()))
+	case reflect.Struct:
+		copied.Set(value)
+		for i := 0; i < t.NumField(); i++ {
+			if t.Field(i).IsExported() {
+				copied.Field(i).Set(copyBlobs(value.Field(i)))
+			}
+		}
+	case reflect.Slice:
+		if value.IsNil() {
+			return value
+		}
+		copied.Set(reflect.MakeSlice(t, value.Len(), value.Len()))
+		for i := 0; i < value.Len(); i++ {
+			copied.Index(i).Set(copyBlobs(value.Index(i)))
+		}
+	case reflect.Array:
+		for i := 0; i < value.Len(); i++ {
+			copied.Index(i).Set(copyBlobs(value.Index(i)))
+		}
+	case reflect.Map:
+		if value.IsNil() {
+			return value
+		}
+		copied.Set(reflect.MakeMapWithSize(t, value.Len()))
+		entries := value.MapRange()
+		for entries.Next() {
+			copied.SetMapIndex(entries.Key(), copyBlobs(entries.Value()))
+		}
+	}
+	return copied
+}
+
+// eachBlob calls fn for each Blob a settable value holds (see hasBlobs),
+// writing map entries back afterwards
+func eachBlob(value reflect.Value, fn func(*Blob) error) error {
+	t := value.Type()
+	if t == blobType {
+		return fn(value.Addr().Interface().(*Blob))
+	}
+	if !hasBlobs(t) {
+		return nil
+	}
+	switch t.Kind() {
+	case reflect.Ptr:
+		if !value.IsNil() {
+			return eachBlob(value.Elem(), fn)
+		}
+	case reflect.Struct:
+		for i := 0; i < value.NumField(); i++ {
+			if !t.Field(i).IsExported() {
+				continue
+			}
+			if err := eachBlob(value.Field(i), fn); err != nil {
+				return err
+			}
+		}
+	case reflect.Slice, reflect.Array:
+		for i := 0; i < value.Len(); i++ {
+			if err := eachBlob(value.Index(i), fn); err != nil {
+				return err
+			}
+		}
+	case reflect.Map:
+		entries := value.MapRange()
+		for entries.Next() {
+			entry := reflect.New(t.Elem()).Elem()
+			entry.Set(entries.Value())
+			if err := eachBlob(entry, fn); err != nil {
+				return err
+			}
+			value.SetMapIndex(entries.Key(), entry)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,199 @@
+package atmos
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/cumulusrpg/atmos/repository"
+	"github.com/cumulusrpg/atmos/types"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type MapArt struct {
+	Tiles Blob `json:"tiles"`
+}
+
+type MapUploadedEvent struct {
+	Name    string `json:"name"`
+	Art     MapArt `json:"art"`
+	Preview Blob   `json:"preview"`
+}
+
+func (e MapUploadedEvent) Type() string { return "map_uploaded" }
+
+// mapReader records the map content listeners see
+type mapReader struct {
+	tiles []byte
+	err   error
+}
+
+func (r *mapReader) Handle(engine types.Engine, event Event) {
+	r.tiles, r.err = event.(MapUploadedEvent).Art.Tiles.Bytes()
+}
+
+// TestBlobStoreChecksInBlobFields verifies blob content goes to the store, with listeners resolving the reference
+func TestBlobStoreChecksInBlobFields(t *testing.T) {
+	blobs := repository.NewInMemoryBlobs()
+	engine := NewEngine(WithBlobStore(blobs))
+	reader := &mapReader{}
+	engine.RegisterListener("map_uploaded", reader)
+
+	uploaded := MapUploadedEvent{Name: "keep", Art: MapArt{Tiles: NewBlob([]byte("#..#"))}, Preview: NewBlob([]byte("png"))}
+	require.True(t, engine.Emit(uploaded))
+	require.NoError(t, reader.err)
+	assert.Equal(t, []byte("#..#"), reader.tiles)
+	assert.Equal(t, 2, blobs.Len())
+
+	committed := engine.GetEvents()[0].(MapUploadedEvent)
+	assert.NotEmpty(t, committed.Art.Tiles.Ref())
+	assert.Empty(t, uploaded.Art.Tiles.Ref(), "The emitted event is left alone")
+
+	data, err := engine.MarshalEvents(engine.GetEvents())
+	require.NoError(t, err)
+	assert.NotContains(t, string(data), `"tiles":{"data"`)
+	assert.Contains(t, string(data), committed.Preview.Ref())
+}
+
+// TestBlobStoreResolvesDecodedReferences verifies decoded events read their blobs from the engine's store
+func TestBlobStoreResolvesDecodedReferences(t *testing.T) {
+	blobs := repository.NewInMemoryBlobs()
+	engine := NewEngine(WithBlobStore(blobs))
+	engine.RegisterEventTypes(MapUploadedEvent{})
+	require.True(t, engine.Emit(MapUploadedEvent{Name: "keep", Art: MapArt{Tiles: NewBlob([]byte("#..#"))}}))
+	data, err := engine.MarshalEvents(engine.GetEvents())
+	require.NoError(t, err)
+
+	restored := NewEngine(WithBlobStore(blobs))
+	restored.RegisterEventTypes(MapUploadedEvent{})
+	events, err := restored.UnmarshalEvents(data)
+	require.NoError(t, err)
+	tiles, err := events[0].(*MapUploadedEvent).Art.Tiles.Bytes()
+	require.NoError(t, err)
+	assert.Equal(t, []byte("#..#"), tiles)
+
+	storeless := NewEngine()
+	storeless.RegisterEventTypes(MapUploadedEvent{})
+	events, err = storeless.UnmarshalEvents(data)
+	require.NoError(t, err)
+	_, err = events[0].(*MapUploadedEvent).Art.Tiles.Bytes()
+	assert.ErrorIs(t, err, ErrBlobUnresolved)
+}
+
+// TestBlobsStayInlineWithoutStore verifies Blob fields serialize their content when no store is configured
+func TestBlobsStayInlineWithoutStore(t *testing.T) {
+	engine := NewEngine()
+	engine.RegisterEventTypes(MapUploadedEvent{})
+	require.True(t, engine.Emit(MapUploadedEvent{Art: MapArt{Tiles: NewBlob([]byte("#..#"))}}))
+
+	data, err := engine.MarshalEvents(engine.GetEvents())
+	require.NoError(t, err)
+	events, err := engine.UnmarshalEvents(data)
+	require.NoError(t, err)
+	tiles, err := events[0].(*MapUploadedEvent).Art.Tiles.Bytes()
+	require.NoError(t, err)
+	assert.Equal(t, []byte("#..#"), tiles)
+	assert.Empty(t, events[0].(*MapUploadedEvent).Art.Tiles.Ref())
+}
+
+// failingBlobs is a blob store that can't store anything
+type failingBlobs struct{}
+
+func (failingBlobs) Put(data []byte) (string, error) { return "", errors.New("disk full") }
+func (failingBlobs) Get(key string) ([]byte, error)  { return nil, errors.New("not found") }
+
+// TestBlobStoreFailureFailsTheEmit verifies events aren't committed when their blobs can't be stored
+func TestBlobStoreFailureFailsTheEmit(t *testing.T) {
+	engine := NewEngine(WithBlobStore(failingBlobs{}))
+
+	result := engine.EmitWithResult(&MapUploadedEvent{Preview: NewBlob([]byte("png"))})
+	assert.EqualError(t, result.Err, "storing blob of map_uploaded: disk full")
+	assert.Empty(t, engine.GetEvents())
+
+	result = engine.EmitAll(MapUploadedEvent{Name: "no blobs"}, MapUploadedEvent{Preview: NewBlob([]byte("png"))})
+	assert.Error(t, result.Err)
+	assert.Empty(t, engine.GetEvents())
+
+	assert.True(t, engine.Emit(MapUploadedEvent{Name: "no blobs"}), "Empty blobs need no storing")
+}
+
+// TestBlobStoreSkipsRejectedEvents verifies blobs are only stored for events that pass validation
+func TestBlobStoreSkipsRejectedEvents(t *testing.T) {
+	blobs := repository.NewInMemoryBlobs()
+	engine := NewEngine(WithBlobStore(blobs))
+	engine.RegisterValidator("map_uploaded", rejectEverything{})
+
+	assert.False(t, engine.Emit(MapUploadedEvent{Preview: NewBlob([]byte("png"))}))
+	assert.False(t, engine.EmitAll(MapUploadedEvent{Preview: NewBlob([]byte("png"))}).Accepted)
+	assert.Equal(t, 0, blobs.Len())
+}
+
+type AtlasEvent struct {
+	Pages   []Blob            `json:"pages"`
+	Layers  map[string]Blob   `json:"layers"`
+	Cover   *Blob             `json:"cover"`
+	Regions map[string]MapArt `json:"regions"`
+	Sheets  []*MapArt         `json:"sheets"`
+}
+
+func (e AtlasEvent) Type() string { return "atlas" }
+
+// TestBlobStoreChecksInNestedBlobs verifies blobs in slices, maps and behind pointers are checked in and resolved
+func TestBlobStoreChecksInNestedBlobs(t *testing.T) {
+	blobs := repository.NewInMemoryBlobs()
+	engine := NewEngine(WithBlobStore(blobs))
+	engine.RegisterEventTypes(AtlasEvent{})
+
+	cover := NewBlob([]byte("cover"))
+	atlas := AtlasEvent{
+		Pages:   []Blob{NewBlob([]byte("page"))},
+		Layers:  map[string]Blob{"fog": NewBlob([]byte("fog"))},
+		Cover:   &cover,
+		Regions: map[string]MapArt{"north": {Tiles: NewBlob([]byte("north"))}},
+		Sheets:  []*MapArt{{Tiles: NewBlob([]byte("sheet"))}},
+	}
+	require.True(t, engine.Emit(atlas))
+	assert.Equal(t, 5, blobs.Len())
+	assert.Empty(t, atlas.Pages[0].Ref(), "The emitted event is left alone")
+	assert.Empty(t, atlas.Layers["fog"].Ref())
+	assert.Empty(t, atlas.Cover.Ref())
+	assert.Empty(t, atlas.Regions["north"].Tiles.Ref())
+	assert.Empty(t, atlas.Sheets[0].Tiles.Ref())
+
+	data, err := engine.MarshalEvents(engine.GetEvents())
+	require.NoError(t, err)
+	assert.NotContains(t, string(data), `{"data"`)
+
+	events, err := engine.UnmarshalEvents(data)
+	require.NoError(t, err)
+	decoded := events[0].(*AtlasEvent)
+	for want, blob := range map[string]Blob{
+		"page":  decoded.Pages[0],
+		"fog":   decoded.Layers["fog"],
+		"cover": *decoded.Cover,
+		"north": decoded.Regions["north"].Tiles,
+		"sheet": decoded.Sheets[0].Tiles,
+	} {
+		content, err := blob.Bytes()
+		require.NoError(t, err)
+		assert.Equal(t, want, string(content))
+	}
+}
+
+// TestBlobStoreMeasuresReferences verifies the payload limit applies to blob references, not their content
+func TestBlobStoreMeasuresReferences(t *testing.T) {
+	blobs := repository.NewInMemoryBlobs()
+	engine := NewEngine(WithBlobStore(blobs), WithMaxPayloadSize(128, RejectOversized()))
+	engine.RegisterEventTypes(MapUploadedEvent{})
+
+	require.True(t, engine.Emit(MapUploadedEvent{Name: "keep", Preview: NewBlob(make([]byte, 1000))}))
+	data, err := engine.MarshalEvents(engine.GetEvents())
+	require.NoError(t, err)
+	events, err := engine.UnmarshalEvents(data)
+	require.NoError(t, err)
+	assert.Len(t, events, 1)
+
+	result := engine.EmitWithResult(MapUploadedEvent{Name: "keep", Art: MapArt{Tiles: NewBlob([]byte("#"))}, Preview: NewBlob([]byte("png"))})
+	assert.Equal(t, PayloadSizeValidator, result.RejectedBy, "Two references don't fit")
+	assert.Equal(t, 1, blobs.Len(), "Refused before storing")
+}
@@ -0,0 +1,50 @@
+package atmos
+
+// Blueprint captures engine registrations once so many engines can be stamped
+// out without re-running them. Engines created from a blueprint share its
+// registration tables until they register something of their own, at which
+// point they receive a private copy (the blueprint itself is never modified).
+//
+//	blueprint := atmos.NewBlueprint(func(engine *atmos.Engine) {
+//		engine.RegisterState("game", NewGameState())
+//		engine.When("move_made", ...).Requires(...).Updates("game", ReduceMoveMade)
+//	})
+//
+//	game1 := blueprint.NewEngine()
+//	game2 := blueprint.NewEngine(atmos.WithRepository(repo))
+//
+// Registered services are shared by every stamped engine; register stateful,
+// per-game services on each engine after stamping.
+type Blueprint struct {
+	registrations *registrations
+}
+
+// NewBlueprint runs setup once against a template engine and freezes the result
+func NewBlueprint(setup func(engine *Engine)) *Blueprint {
+	template := NewEngine()
+	setup(template)
+	return &Blueprint{registrations: template.registrations}
+}
+
+// NewEngine creates an engine that shares the blueprint's registrations
+func (b *Blueprint) NewEngine(opts ...EngineOption) *Engine {
+	engine := NewEngine()
+	engine.registrations = b.registrations
+	engine.sharedRegistrations = true
+
+	// Apply options after sharing so any registrations they make copy-on-write
+	for _, opt := range opts {
+		opt(engine)
+	}
+
+	engine.watchRepository()
+	return engine
+}
+
+// WithBlueprint makes an EngineManager stamp its engines from a blueprint.
+// The manager's setup function (if any) still runs afterwards for per-engine wiring.
+func WithBlueprint(blueprint *Blueprint) ManagerOption {
+	return func(m *EngineManager) {
+		m.blueprint = blueprint
+	}
+}
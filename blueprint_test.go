@@ -0,0 +1,97 @@
+package atmos
+
+import (
+	"testing"
+
+	"github.com/cumulusrpg/atmos/repository"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type OrderTotals struct {
+	Count int
+}
+
+func reduceOrderCount(e *Engine, state interface{}, event Event) interface{} {
+	s := state.(OrderTotals)
+	s.Count++
+	return s
+}
+
+// TestBlueprintStampsIndependentEngines verifies stamped engines share rules but not events
+func TestBlueprintStampsIndependentEngines(t *testing.T) {
+	setupRuns := 0
+	blueprint := NewBlueprint(func(engine *Engine) {
+		setupRuns++
+		engine.RegisterState("orders", OrderTotals{})
+		engine.When("order_placed").
+			Requires(NewTypedValidator(TypedValidatorFunc[OrderPlacedEvent](func(e *Engine, event OrderPlacedEvent) bool {
+				return event.Amount > 0
+			}))).
+			Updates("orders", reduceOrderCount)
+	})
+
+	game1 := blueprint.NewEngine()
+	game2 := blueprint.NewEngine()
+
+	assert.True(t, game1.Emit(OrderPlacedEvent{OrderID: "ORD-1", Amount: 10}))
+	assert.False(t, game1.Emit(OrderPlacedEvent{OrderID: "ORD-2", Amount: 0}))
+	assert.True(t, game2.Emit(OrderPlacedEvent{OrderID: "ORD-3", Amount: 5}))
+	assert.True(t, game2.Emit(OrderPlacedEvent{OrderID: "ORD-4", Amount: 5}))
+
+	assert.Equal(t, 1, setupRuns)
+	assert.Equal(t, OrderTotals{Count: 1}, game1.GetState("orders"))
+	assert.Equal(t, OrderTotals{Count: 2}, game2.GetState("orders"))
+}
+
+// TestBlueprintCopyOnWrite verifies per-engine registrations don't leak into the blueprint
+func TestBlueprintCopyOnWrite(t *testing.T) {
+	blueprint := NewBlueprint(func(engine *Engine) {
+		engine.RegisterState("orders", OrderTotals{})
+		engine.When("order_placed").Updates("orders", reduceOrderCount)
+	})
+
+	custom := blueprint.NewEngine()
+	custom.When("order_placed").Requires(NewTypedValidator(RequirePaymentValidator{}))
+	custom.When("invoice_generated").Updates("orders", reduceOrderCount)
+	custom.RegisterService("catalog", "custom")
+
+	plain := blueprint.NewEngine()
+
+	assert.False(t, custom.Emit(OrderPlacedEvent{OrderID: "ORD-1"}))
+	assert.True(t, plain.Emit(OrderPlacedEvent{OrderID: "ORD-1"}))
+	assert.True(t, plain.Emit(InvoiceGeneratedEvent{OrderID: "ORD-1"}))
+
+	assert.Equal(t, OrderTotals{Count: 1}, plain.GetState("orders"), "invoice reducer stays private to custom engine")
+	assert.Nil(t, plain.GetService("catalog"))
+	assert.Equal(t, "custom", custom.GetService("catalog"))
+}
+
+// TestEngineManagerWithBlueprint verifies managers can stamp engines from a blueprint
+func TestEngineManagerWithBlueprint(t *testing.T) {
+	blueprint := NewBlueprint(func(engine *Engine) {
+		engine.RegisterState("orders", OrderTotals{})
+		engine.When("order_placed").Updates("orders", reduceOrderCount)
+	})
+
+	manager := NewEngineManager(nil, WithBlueprint(blueprint))
+	engine, err := manager.Create("game-1")
+	assert.NoError(t, err)
+
+	engine.Emit(OrderPlacedEvent{OrderID: "ORD-1"})
+	assert.Equal(t, OrderTotals{Count: 1}, engine.GetState("orders"))
+}
+
+// TestBlueprintEnginesWatchSharedRepositories verifies stamped engines notice other writers like NewEngine's do
+func TestBlueprintEnginesWatchSharedRepositories(t *testing.T) {
+	blueprint := NewBlueprint(func(engine *Engine) {
+		engine.RegisterEventTypes(CoinsFoundEvent{})
+	})
+	shared := repository.NewShared()
+	local := blueprint.NewEngine(WithRepository(shared.Writer()))
+	remote := blueprint.NewEngine(WithRepository(shared.Writer()))
+
+	require.NotNil(t, local.ExternalChanges())
+	remote.Emit(CoinsFoundEvent{Coins: 2})
+	assert.True(t, local.Refresh())
+}
@@ -0,0 +1,87 @@
+package atmos
+
+import "context"
+
+// WithBreadthFirstEmits processes events that listeners emit breadth-first
+// instead of recursing into them. By default a listener's Emit runs the
+// derived event (and everything it derives) before the next listener of the
+// original event. In breadth-first mode it is queued instead, and Emit
+// returns a result with Queued set; once the outermost emit's listeners
+// have run, queued events are validated and committed in the order they
+// were emitted:
+//
+//	a's listeners emit b1, b2  →  log: a, b1, b2, c1, c2
+//	b1's listeners emit c1     (c1 and c2 follow every event of b's generation)
+//	b2's listeners emit c2
+//
+// Events emitted by before hooks still commit immediately, ahead of the
+// event being emitted, and EmitAll batches always commit together. The
+// outermost Emit returns once every derived event has been processed.
+func WithBreadthFirstEmits() EngineOption {
+	return func(e *Engine) {
+		e.breadthFirst = true
+	}
+}
+
+// derivedEmit is an event emitted by a listener, waiting its turn, with the
+// circumstances it was emitted in
+type derivedEmit struct {
+	event    Event
+	id       string
+	emitting []string // the emitting stack of its listener (see WithDeclaredEmits)
+	actor    string
+	metadata Metadata
+	ctx      context.Context
+}
+
+// queueDerived holds an event emitted by a listener until the events before it are processed
+func (e *Engine) queueDerived(event Event, id string) EmitResult {
+	e.derived = append(e.derived, derivedEmit{
+		event:    event,
+		id:       id,
+		emitting: append([]string(nil), e.emitting...),
+		actor:    e.actor,
+		metadata: e.metadata,
+		ctx:      e.ctx,
+	})
+	return EmitResult{Queued: true}
+}
+
+// listen runs the listeners of committed events; in breadth-first mode,
+// once the outermost listeners finish, it processes the events they derived
+func (e *Engine) listen(fn func()) {
+	func() {
+		e.listening++
+		defer func() { e.listening-- }()
+		fn()
+	}()
+
+	if e.breadthFirst && e.listening == 0 && !e.draining {
+		e.drainDerived()
+	}
+}
+
+// drainDerived processes derived events in the order they were emitted,
+// including those they derive in turn, each with the actor, metadata, and
+// context it was emitted with
+func (e *Engine) drainDerived() {
+	e.draining = true
+	defer func() { e.draining = false }()
+
+	for len(e.derived) > 0 {
+		next := e.derived[0]
+		e.derived = e.derived[1:]
+		e.derive(next)
+	}
+}
+
+// derive runs a derived event in its original circumstances
+func (e *Engine) derive(next derivedEmit) {
+	outerEmitting, outerActor, outerMetadata, outerCtx := e.emitting, e.actor, e.metadata, e.ctx
+	e.emitting, e.actor, e.metadata, e.ctx = next.emitting, next.actor, next.metadata, next.ctx
+	defer func() {
+		e.emitting, e.actor, e.metadata, e.ctx = outerEmitting, outerActor, outerMetadata, outerCtx
+	}()
+
+	e.emit(next.event, next.id)
+}
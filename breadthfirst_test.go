@@ -0,0 +1,99 @@
+package atmos
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// newFanOutEngine creates an engine where a derives b1 and b2, b1 derives
+// c1, and b2 derives c2, recording the results listeners see
+func newFanOutEngine(results *[]EmitResult, opts ...EngineOption) *Engine {
+	engine := NewEngine(opts...)
+	children := map[string][]string{"a": {"b1", "b2"}, "b1": {"c1"}, "b2": {"c2"}}
+	engine.When("test_event").Then(NewTypedListener(TypedListenerFunc[TestEvent](func(e *Engine, event TestEvent) {
+		for _, child := range children[event.Name] {
+			*results = append(*results, e.EmitWithResult(TestEvent{Name: child}))
+		}
+	})))
+	return engine
+}
+
+// loggedNames lists the names of logged test events
+func loggedNames(engine *Engine) []string {
+	var names []string
+	for _, event := range engine.GetEvents() {
+		names = append(names, event.(TestEvent).Name)
+	}
+	return names
+}
+
+// TestDerivedEmitsAreDepthFirstByDefault documents the default recursion
+func TestDerivedEmitsAreDepthFirstByDefault(t *testing.T) {
+	var results []EmitResult
+	engine := newFanOutEngine(&results)
+
+	require.True(t, engine.Emit(TestEvent{Name: "a"}))
+	assert.Equal(t, []string{"a", "b1", "c1", "b2", "c2"}, loggedNames(engine))
+	assert.True(t, results[0].Accepted)
+}
+
+// TestBreadthFirstEmits verifies derived events commit generation by generation
+func TestBreadthFirstEmits(t *testing.T) {
+	var results []EmitResult
+	engine := newFanOutEngine(&results, WithBreadthFirstEmits())
+
+	require.True(t, engine.Emit(TestEvent{Name: "a"}))
+	assert.Equal(t, []string{"a", "b1", "b2", "c1", "c2"}, loggedNames(engine))
+	require.Len(t, results, 4)
+	for _, result := range results {
+		assert.True(t, result.Queued)
+		assert.False(t, result.Accepted, "Queued events aren't validated yet")
+	}
+}
+
+// TestBreadthFirstEmitsValidateInTurn verifies a rejected derived event doesn't stop the rest
+func TestBreadthFirstEmitsValidateInTurn(t *testing.T) {
+	var results []EmitResult
+	engine := newFanOutEngine(&results, WithBreadthFirstEmits())
+	engine.When("test_event").Requires(NewTypedValidator(TypedValidatorFunc[TestEvent](func(e *Engine, event TestEvent) bool {
+		return event.Name != "b1"
+	})))
+
+	require.True(t, engine.Emit(TestEvent{Name: "a"}))
+	assert.Equal(t, []string{"a", "b2", "c2"}, loggedNames(engine), "c1 is never derived")
+}
+
+// TestBreadthFirstEmitsAfterBatch verifies a batch's listeners all run before derived events
+func TestBreadthFirstEmitsAfterBatch(t *testing.T) {
+	var results []EmitResult
+	engine := newFanOutEngine(&results, WithBreadthFirstEmits())
+
+	require.True(t, engine.EmitAll(TestEvent{Name: "b1"}, TestEvent{Name: "b2"}).Accepted)
+	assert.Equal(t, []string{"b1", "b2", "c1", "c2"}, loggedNames(engine))
+}
+
+// TestBreadthFirstEmitsKeepTheirActor verifies derived events are checked and stored under the actor and metadata they were emitted with
+func TestBreadthFirstEmitsKeepTheirActor(t *testing.T) {
+	engine := NewEngine(WithBreadthFirstEmits())
+	engine.When("test_event").AllowedBy("only the referee derives", func(e *Engine, actor string, event Event) bool {
+		return event.(TestEvent).Name == "a" || actor == "referee"
+	})
+	engine.When("test_event").Then(NewTypedListener(TypedListenerFunc[TestEvent](func(e *Engine, event TestEvent) {
+		if event.Name == "a" {
+			e.EmitWithMetadata(Metadata{MetadataActor: "referee", "source": "rules"}, TestEvent{Name: "b"})
+			e.EmitAs("alice", TestEvent{Name: "c"})
+		}
+	})))
+	var metadata []Metadata
+	engine.When("test_event").Then(NewTypedListener(TypedListenerFunc[TestEvent](func(e *Engine, event TestEvent) {
+		metadata = append(metadata, e.Metadata())
+	})))
+
+	require.True(t, engine.EmitAs("bob", TestEvent{Name: "a"}))
+	assert.Equal(t, []string{"a", "b"}, loggedNames(engine), "c is rejected for its own actor")
+	require.Len(t, metadata, 2)
+	assert.Equal(t, "rules", metadata[1]["source"])
+	assert.Equal(t, "", engine.Actor(), "The outer actor is restored")
+}
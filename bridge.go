@@ -0,0 +1,95 @@
+package atmos
+
+import "github.com/cumulusrpg/atmos/types"
+
+// ForwardBuilder provides a fluent API for bridging committed events from one
+// engine into another. This lets a lobby engine publish match events into
+// per-match engines and receive results back:
+//
+//	lobby.Forward("match_created").
+//	    Transform(func(e Event) []Event { return []Event{MatchSetupEvent{...}} }).
+//	    ToRouter(func(e Event) (*Engine, bool) { return matches.Get(e.(MatchCreatedEvent).MatchID) })
+//
+//	match.Forward("match_ended").To(lobby)
+//
+// Forwarded events go through the target's full Emit pipeline (validation,
+// hooks, listeners). Forwarding is synchronous, so both engines must be used
+// from the same goroutine (or behind the same lock). Avoid forwarding the
+// same event type back and forth, which would loop.
+type ForwardBuilder struct {
+	source     *Engine
+	eventType  string
+	condition  func(Event) bool
+	transform  func(Event) []Event
+	onRejected func(target *Engine, event Event)
+}
+
+// Forward starts bridging committed events of a type to other engines
+func (e *Engine) Forward(eventType string) *ForwardBuilder {
+	return &ForwardBuilder{source: e, eventType: eventType}
+}
+
+// If adds a condition - only events for which it returns true are forwarded
+func (fb *ForwardBuilder) If(condition func(Event) bool) *ForwardBuilder {
+	fb.condition = condition
+	return fb
+}
+
+// Transform converts each source event into the events emitted on the target.
+// Without a transform the source event is forwarded unchanged.
+func (fb *ForwardBuilder) Transform(transform func(Event) []Event) *ForwardBuilder {
+	fb.transform = transform
+	return fb
+}
+
+// OnRejected is called when the target engine rejects a forwarded event
+func (fb *ForwardBuilder) OnRejected(handler func(target *Engine, event Event)) *ForwardBuilder {
+	fb.onRejected = handler
+	return fb
+}
+
+// To forwards to a fixed target engine
+func (fb *ForwardBuilder) To(target *Engine) {
+	fb.ToRouter(func(Event) (*Engine, bool) { return target, true })
+}
+
+// ToRouter forwards to the engine chosen per event; events with no route are dropped
+func (fb *ForwardBuilder) ToRouter(route func(Event) (*Engine, bool)) {
+	fb.source.RegisterListener(fb.eventType, &forwardListener{
+		condition:  fb.condition,
+		transform:  fb.transform,
+		onRejected: fb.onRejected,
+		route:      route,
+	})
+}
+
+// forwardListener is the listener installed on the source engine
+type forwardListener struct {
+	condition  func(Event) bool
+	transform  func(Event) []Event
+	onRejected func(target *Engine, event Event)
+	route      func(Event) (*Engine, bool)
+}
+
+// Handle implements EventListener
+func (l *forwardListener) Handle(engine types.Engine, event Event) {
+	if l.condition != nil && !l.condition(event) {
+		return
+	}
+
+	target, ok := l.route(event)
+	if !ok || target == nil {
+		return
+	}
+
+	forwarded := []Event{event}
+	if l.transform != nil {
+		forwarded = l.transform(event)
+	}
+
+	for _, out := range forwarded {
+		if !target.Emit(out) && l.onRejected != nil {
+			l.onRejected(target, out)
+		}
+	}
+}
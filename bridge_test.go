@@ -0,0 +1,83 @@
+package atmos
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+type MatchCreatedEvent struct {
+	MatchID string
+	Players []string
+}
+
+func (e MatchCreatedEvent) Type() string { return "match_created" }
+
+type MatchSetupEvent struct {
+	Players []string
+}
+
+func (e MatchSetupEvent) Type() string { return "match_setup" }
+
+type MatchEndedEvent struct {
+	MatchID string
+	Winner  string
+}
+
+func (e MatchEndedEvent) Type() string { return "match_ended" }
+
+// TestForwardBetweenLobbyAndMatches demonstrates routing events down and results back up
+func TestForwardBetweenLobbyAndMatches(t *testing.T) {
+	lobby := NewEngine()
+	matches := map[string]*Engine{}
+
+	newMatch := func(id string) *Engine {
+		match := NewEngine()
+		match.Forward("match_ended").To(lobby)
+		matches[id] = match
+		return match
+	}
+	newMatch("m1")
+	newMatch("m2")
+
+	lobby.Forward("match_created").
+		If(func(e Event) bool { return len(e.(MatchCreatedEvent).Players) > 1 }).
+		Transform(func(e Event) []Event {
+			return []Event{MatchSetupEvent{Players: e.(MatchCreatedEvent).Players}}
+		}).
+		ToRouter(func(e Event) (*Engine, bool) {
+			match, ok := matches[e.(MatchCreatedEvent).MatchID]
+			return match, ok
+		})
+
+	lobby.Emit(MatchCreatedEvent{MatchID: "m1", Players: []string{"alice", "bob"}})
+	lobby.Emit(MatchCreatedEvent{MatchID: "m2", Players: []string{"carol"}})        // filtered out
+	lobby.Emit(MatchCreatedEvent{MatchID: "m3", Players: []string{"dave", "erin"}}) // no route
+
+	assert.Equal(t, []Event{MatchSetupEvent{Players: []string{"alice", "bob"}}}, matches["m1"].GetEvents())
+	assert.Empty(t, matches["m2"].GetEvents())
+
+	// Results bubble back to the lobby unchanged
+	matches["m1"].Emit(MatchEndedEvent{MatchID: "m1", Winner: "alice"})
+	lobbyEvents := lobby.GetEvents()
+	assert.Len(t, lobbyEvents, 4)
+	assert.Equal(t, MatchEndedEvent{MatchID: "m1", Winner: "alice"}, lobbyEvents[3])
+}
+
+// TestForwardReportsRejections verifies targets can refuse forwarded events
+func TestForwardReportsRejections(t *testing.T) {
+	source := NewEngine()
+	target := NewEngine()
+	target.When("order_placed").Requires(NewTypedValidator(RequirePaymentValidator{}))
+
+	var rejected []Event
+	source.Forward("order_placed").
+		OnRejected(func(e *Engine, event Event) { rejected = append(rejected, event) }).
+		To(target)
+
+	source.Emit(OrderPlacedEvent{OrderID: "ORD-1", Amount: 10})
+
+	assert.Len(t, source.GetEvents(), 1)
+	assert.Empty(t, target.GetEvents())
+	assert.Equal(t, []Event{OrderPlacedEvent{OrderID: "ORD-1", Amount: 10}}, rejected)
+}
@@ -0,0 +1,96 @@
+package atmos
+
+import (
+	"sync/atomic"
+
+	"github.com/cumulusrpg/atmos/types"
+)
+
+// externalChanges counts notifications from a types.ChangeNotifier
+// repository. Notifications may arrive on any goroutine; the engine takes
+// them in on its own, in Select and Refresh.
+type externalChanges struct {
+	notified atomic.Uint64 // notifications received
+	absorbed uint64        // notifications reflected in the engine's revision
+	handled  uint64        // notifications the change hooks have run for
+	signal   chan struct{} // holds a value while notifications are unhandled
+	stop     func()
+}
+
+// watchRepository subscribes to the repository's change notifications, if it sends them
+func (e *Engine) watchRepository() {
+	notifier, ok := e.repository.(types.ChangeNotifier)
+	if !ok {
+		return
+	}
+	external := &externalChanges{signal: make(chan struct{}, 1)}
+	external.stop = notifier.WatchChanges(func() {
+		external.notified.Add(1)
+		select {
+		case external.signal <- struct{}{}:
+		default: // Already signaled
+		}
+	})
+	e.external = external
+}
+
+// ExternalChanges returns a channel that receives a value when other
+// writers commit to the engine's shared repository, for a loop on the
+// engine's goroutine to call Refresh. It is nil (never ready) when the
+// repository doesn't implement types.ChangeNotifier.
+func (e *Engine) ExternalChanges() <-chan struct{} {
+	if e.external == nil {
+		return nil
+	}
+	return e.external.signal
+}
+
+// OnExternalChange registers a hook run by Refresh when other writers have
+// committed to the engine's shared repository, e.g. to push fresh state to
+// watching clients
+func (e *Engine) OnExternalChange(hook func(engine *Engine)) {
+	e.mutableRegistrations()
+	e.changeHooks = append(e.changeHooks, hook)
+}
+
+// Refresh takes in commits made by other writers to the engine's shared
+// repository since the last Refresh: cached results (see Select) are
+// dropped and the OnExternalChange hooks run. It reports whether there were
+// any. GetState always reads the repository, so it is never stale.
+//
+//	for {
+//		select {
+//		case <-engine.ExternalChanges():
+//			engine.Refresh()
+//		case cmd := <-commands:
+//			engine.Emit(cmd)
+//		}
+//	}
+func (e *Engine) Refresh() bool {
+	if e.external == nil {
+		return false
+	}
+	notified := e.absorbExternal()
+	if notified == e.external.handled {
+		return false
+	}
+	e.external.handled = notified
+	for _, hook := range e.changeHooks {
+		hook(e)
+	}
+	return true
+}
+
+// absorbExternal bumps the revision if other writers have committed since
+// it was last called, returning the notification count it saw
+func (e *Engine) absorbExternal() uint64 {
+	if e.external == nil {
+		return 0
+	}
+	notified := e.external.notified.Load()
+	if notified != e.external.absorbed {
+		e.external.absorbed = notified
+		e.revision++
+	}
+	return notified
+}
@@ -0,0 +1,49 @@
+package atmos
+
+import (
+	"testing"
+
+	"github.com/cumulusrpg/atmos/repository"
+	"github.com/stretchr/testify/assert"
+)
+
+// TestRefreshTakesInExternalCommits verifies engines on a shared repository notice each other's commits
+func TestRefreshTakesInExternalCommits(t *testing.T) {
+	shared := repository.NewShared()
+	local := newTreasuryEngine(shared.Writer())
+	remote := newTreasuryEngine(shared.Writer())
+
+	local.RegisterSelector("coins", []string{"treasury"}, func(values ...interface{}) interface{} {
+		return values[0].(Treasury).Coins
+	})
+	var refreshed []int
+	local.OnExternalChange(func(e *Engine) {
+		refreshed = append(refreshed, e.GetState("treasury").(Treasury).Coins)
+	})
+
+	local.Emit(CoinsFoundEvent{Coins: 1})
+	assert.Equal(t, 1, local.Select("coins"))
+	assert.False(t, local.Refresh(), "Its own commits aren't external")
+
+	remote.Emit(CoinsFoundEvent{Coins: 2})
+	select {
+	case <-local.ExternalChanges():
+	default:
+		t.Fatal("Expected a change signal")
+	}
+	assert.Equal(t, 3, local.Select("coins"), "Cached selections are dropped")
+	assert.True(t, local.Refresh())
+	assert.False(t, local.Refresh())
+	assert.Equal(t, []int{3}, refreshed)
+
+	local.Close()
+	remote.Emit(CoinsFoundEvent{Coins: 4})
+	assert.False(t, local.Refresh(), "Closed engines stop watching")
+}
+
+// TestRefreshWithoutNotifications verifies plain repositories never report external changes
+func TestRefreshWithoutNotifications(t *testing.T) {
+	engine := NewEngine()
+	assert.Nil(t, engine.ExternalChanges())
+	assert.False(t, engine.Refresh())
+}
@@ -0,0 +1,160 @@
+package atmos
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"hash/fnv"
+	"reflect"
+
+	"github.com/cumulusrpg/atmos/types"
+)
+
+// checkpointPrefix keys checkpoints in the snapshot repository, apart from
+// the snapshots managed with SetSnapshot
+const checkpointPrefix = "atmos.checkpoint/"
+
+// checkpoint is a state as of a position in the log
+type checkpoint struct {
+	Sequence      int             `json:"sequence"`       // Events applied
+	Last          uint64          `json:"last"`           // Fingerprint of the last event applied
+	SchemaVersion int             `json:"schema_version"` // See WithSchemaVersion
+	State         json.RawMessage `json:"state"`
+}
+
+// WithCheckpoints has the engine checkpoint every state after each `every`
+// commits (and at Shutdown), storing each state with its log position in
+// the repository's snapshot storage, so GetState (including after a
+// restart) replays only the events since. Checkpoints are internal: they
+// don't appear as snapshots (see SetSnapshot), are dropped when the log is
+// replaced or a snapshot changes, and are ignored if the log no longer
+// matches them or the schema version changed (see WithSchemaVersion) — bump
+// it when reducers change. States that don't survive a JSON round trip
+// (unexported fields, interfaces) are never checkpointed. Without a
+// types.SnapshotRepository, the option has no effect.
+func WithCheckpoints(every int) EngineOption {
+	return func(e *Engine) {
+		e.checkpointEvery = every
+	}
+}
+
+// Checkpoint checkpoints every state now (see WithCheckpoints)
+func (e *Engine) Checkpoint() error {
+	store, ok := e.repository.(types.SnapshotRepository)
+	if !ok {
+		return errors.New("repository does not support snapshots")
+	}
+	e.uncheckpointed = 0
+
+	var errs []error
+	for _, name := range e.StateNames() {
+		registry := e.states[name]
+		state, sequence, last := e.project(name, registry, true)
+		cp := checkpoint{Sequence: sequence, SchemaVersion: e.schemaVersion}
+		if last != nil {
+			cp.Last = fingerprint(last)
+		}
+		if previous, exists := e.checkpointOf(name); exists && previous.Sequence == cp.Sequence && previous.Last == cp.Last {
+			continue // Up to date
+		}
+
+		data, err := json.Marshal(state)
+		if err != nil || !reflect.DeepEqual(e.mergeSnapshot(registry.InitialState, data), state) {
+			continue // Not checkpointable
+		}
+		cp.State = data
+		encoded, err := json.Marshal(cp)
+		if err == nil {
+			err = store.SetSnapshot(checkpointPrefix+name, encoded)
+		}
+		if err != nil {
+			errs = append(errs, fmt.Errorf("checkpoint %s: %w", name, err))
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// project computes a state by replaying the log, from its checkpoint if
+// allowed and still valid, returning it with the number of events in the
+// log and the last of them
+func (e *Engine) project(name string, registry StateRegistry, fromCheckpoint bool) (interface{}, int, Event) {
+	state, start := e.startingState(name, registry), 0
+	cp, resuming := e.checkpointOf(name)
+	if resuming = resuming && fromCheckpoint; resuming {
+		state, start = e.mergeSnapshot(registry.InitialState, cp.State), cp.Sequence
+	}
+
+	// Revisit the checkpoint's last event to make sure the log still matches
+	sequence, verify, stale := start, resuming && start > 0, false
+	if verify {
+		sequence--
+	}
+	var last Event
+	e.eachEventFrom(sequence, func(event Event) bool {
+		sequence++
+		if verify {
+			verify, stale = false, fingerprint(event) != cp.Last
+			last = event
+			return !stale
+		}
+		if reducer, hasReducer := registry.Reducers[event.Type()]; hasReducer {
+			state = reducer(e, state, event)
+		}
+		last = event
+		return true
+	})
+
+	if resuming && (stale || sequence < start) {
+		return e.project(name, registry, false)
+	}
+	return state, sequence, last
+}
+
+// checkpointOf returns a state's checkpoint, if it has a usable one
+func (e *Engine) checkpointOf(name string) (checkpoint, bool) {
+	store, ok := e.repository.(types.SnapshotRepository)
+	if !ok {
+		return checkpoint{}, false
+	}
+	data, exists := store.GetSnapshot(checkpointPrefix + name)
+	if !exists {
+		return checkpoint{}, false
+	}
+	var cp checkpoint
+	if err := json.Unmarshal(data, &cp); err != nil || cp.SchemaVersion != e.schemaVersion {
+		return checkpoint{}, false
+	}
+	return cp, true
+}
+
+// clearCheckpoint drops a state's checkpoint
+func (e *Engine) clearCheckpoint(name string) {
+	if e.checkpointEvery == 0 {
+		return
+	}
+	if store, ok := e.repository.(types.SnapshotRepository); ok {
+		if _, exists := store.GetSnapshot(checkpointPrefix + name); exists {
+			_ = store.ClearSnapshot(checkpointPrefix + name) // Also caught by the fingerprint check
+		}
+	}
+}
+
+// clearCheckpoints drops every checkpoint, when the log is replaced
+func (e *Engine) clearCheckpoints() {
+	if e.checkpointEvery == 0 {
+		return
+	}
+	for _, name := range e.StateNames() {
+		e.clearCheckpoint(name)
+	}
+	e.uncheckpointed = 0
+}
+
+// fingerprint identifies an event's type and content
+func fingerprint(event Event) uint64 {
+	h := fnv.New64a()
+	h.Write([]byte(event.Type()))
+	data, _ := json.Marshal(event)
+	h.Write(data)
+	return h.Sum64()
+}
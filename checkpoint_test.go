@@ -0,0 +1,89 @@
+package atmos
+
+import (
+	"testing"
+
+	"github.com/cumulusrpg/atmos/repository"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// newCheckpointEngine counts the events its treasury reducer applies
+func newCheckpointEngine(repo *repository.InMemorySnapshot, applied *int, opts ...EngineOption) *Engine {
+	engine := NewEngine(append([]EngineOption{WithRepository(repo)}, opts...)...)
+	engine.RegisterState("treasury", Treasury{})
+	WhenEvent[CoinsFoundEvent](engine).Updates("treasury", func(e *Engine, state interface{}, event Event) interface{} {
+		*applied++
+		if loaded, ok := event.(*CoinsFoundEvent); ok {
+			event = *loaded
+		}
+		return Treasury{Coins: state.(Treasury).Coins + event.(CoinsFoundEvent).Coins}
+	})
+	return engine
+}
+
+// TestCheckpointsResumeAfterRestart verifies a new engine replays only the events since the last checkpoint
+func TestCheckpointsResumeAfterRestart(t *testing.T) {
+	repo := repository.NewInMemorySnapshot()
+	var applied int
+	engine := newCheckpointEngine(repo, &applied, WithCheckpoints(2))
+	for i := 1; i <= 5; i++ {
+		engine.Emit(CoinsFoundEvent{Coins: i})
+	}
+	assert.False(t, engine.HasSnapshot("treasury"), "Checkpoints aren't snapshots")
+
+	applied = 0
+	restarted := newCheckpointEngine(repo, &applied, WithCheckpoints(2))
+	assert.Equal(t, Treasury{Coins: 15}, restarted.GetState("treasury"))
+	assert.Equal(t, 1, applied, "Resumed from the checkpoint at 4 events")
+
+	restarted.Emit(CoinsFoundEvent{Coins: 6})
+	require.NoError(t, restarted.Close())
+	applied = 0
+	assert.Equal(t, Treasury{Coins: 21}, restarted.GetState("treasury"))
+	assert.Zero(t, applied, "Checkpointed at shutdown")
+}
+
+// TestCheckpointsInvalidated verifies checkpoints never serve a state that doesn't match the log
+func TestCheckpointsInvalidated(t *testing.T) {
+	repo := repository.NewInMemorySnapshot()
+	var applied int
+	engine := newCheckpointEngine(repo, &applied, WithCheckpoints(1))
+	engine.Emit(CoinsFoundEvent{Coins: 1})
+	engine.Emit(CoinsFoundEvent{Coins: 2})
+
+	engine.SetEvents([]Event{CoinsFoundEvent{Coins: 7}})
+	assert.Equal(t, Treasury{Coins: 7}, engine.GetState("treasury"), "Replaced log")
+
+	require.NoError(t, engine.Checkpoint())
+	require.NoError(t, repo.SetAll(engine, []Event{CoinsFoundEvent{Coins: 8}}))
+	assert.Equal(t, Treasury{Coins: 8}, engine.GetState("treasury"), "Log rewritten behind the engine's back")
+
+	require.NoError(t, engine.Checkpoint())
+	require.NoError(t, repo.SetAll(engine, nil))
+	assert.Equal(t, Treasury{}, engine.GetState("treasury"), "Log truncated")
+
+	engine.Emit(CoinsFoundEvent{Coins: 3})
+	require.NoError(t, engine.SetSnapshot("treasury", Treasury{Coins: 100}))
+	assert.Equal(t, Treasury{Coins: 103}, engine.GetState("treasury"), "Snapshot changed")
+
+	applied = 0
+	upgraded := newCheckpointEngine(repo, &applied, WithCheckpoints(1), WithSchemaVersion(2))
+	assert.Equal(t, Treasury{Coins: 103}, upgraded.GetState("treasury"))
+	assert.Equal(t, 1, applied, "Schema changed, so replayed in full")
+}
+
+// TestCheckpointsSkipLossyStates verifies states that don't round-trip through JSON aren't checkpointed
+func TestCheckpointsSkipLossyStates(t *testing.T) {
+	repo := repository.NewInMemorySnapshot()
+	engine := NewEngine(WithRepository(repo), WithCheckpoints(1))
+	engine.RegisterState("secret", struct{ hidden int }{})
+	engine.When("test_event").Updates("secret", func(e *Engine, state interface{}, event Event) interface{} {
+		return struct{ hidden int }{hidden: 1}
+	})
+	engine.Emit(TestEvent{Name: "a"})
+
+	_, exists := repo.GetSnapshot(checkpointPrefix + "secret")
+	assert.False(t, exists)
+	assert.Equal(t, struct{ hidden int }{hidden: 1}, engine.GetState("secret"))
+}
@@ -0,0 +1,199 @@
+package atmos
+
+import (
+	"encoding/json"
+	"fmt"
+	"sort"
+
+	"github.com/cumulusrpg/atmos/types"
+)
+
+// Children hosts one child engine per aggregate of a parent engine (a lobby
+// hosting matches). Events for a child implement types.AggregateEvent, whose
+// AggregateID names the child; summary events bubble back up to the parent:
+//
+//	matches := lobby.HostChildren(setupMatch,
+//		SpawnOn("match_created"),
+//		BubbleUp("match_ended"),
+//	)
+//	lobby.Emit(MatchCreatedEvent{MatchID: "m1"}) // spawns child "m1"
+//	matches.Emit(MoveMadeEvent{MatchID: "m1"})   // routed to "m1"
+//
+// Children are not safe for concurrent use; use the parent and its children
+// from one goroutine (or behind one lock), as with Forward.
+type Children struct {
+	parent   *Engine
+	setup    EngineSetup
+	spawnOn  []string
+	bubbleUp []string
+	engines  map[string]*Engine
+}
+
+// ChildOption configures HostChildren
+type ChildOption func(*Children)
+
+// SpawnOn creates the child named by an event's AggregateID when the parent
+// commits an event of one of these types
+func SpawnOn(eventTypes ...string) ChildOption {
+	return func(c *Children) {
+		c.spawnOn = append(c.spawnOn, eventTypes...)
+	}
+}
+
+// BubbleUp emits events of these types on the parent when a child commits them
+func BubbleUp(eventTypes ...string) ChildOption {
+	return func(c *Children) {
+		c.bubbleUp = append(c.bubbleUp, eventTypes...)
+	}
+}
+
+// HostChildren makes the engine a parent of child engines, each configured
+// by setup when it is spawned or restored
+func (e *Engine) HostChildren(setup EngineSetup, opts ...ChildOption) *Children {
+	c := &Children{
+		parent:  e,
+		setup:   setup,
+		engines: make(map[string]*Engine),
+	}
+
+	// Apply options
+	for _, opt := range opts {
+		opt(c)
+	}
+
+	for _, eventType := range c.spawnOn {
+		e.RegisterListener(eventType, &spawnListener{children: c})
+	}
+	return c
+}
+
+// Spawn creates a child engine
+func (c *Children) Spawn(id string) (*Engine, error) {
+	if _, exists := c.engines[id]; exists {
+		return nil, ErrEngineExists
+	}
+	return c.create(id), nil
+}
+
+// Child returns a child engine
+func (c *Children) Child(id string) (*Engine, bool) {
+	child, exists := c.engines[id]
+	return child, exists
+}
+
+// IDs returns the children's IDs, sorted
+func (c *Children) IDs() []string {
+	ids := make([]string, 0, len(c.engines))
+	for id := range c.engines {
+		ids = append(ids, id)
+	}
+	sort.Strings(ids)
+	return ids
+}
+
+// Emit routes an event to the child named by its AggregateID
+func (c *Children) Emit(event Event) EmitResult {
+	aggregate, ok := event.(types.AggregateEvent)
+	if !ok {
+		return EmitResult{Err: fmt.Errorf("%s does not name a child engine (no AggregateID)", event.Type())}
+	}
+	child, exists := c.engines[aggregate.AggregateID()]
+	if !exists {
+		return EmitResult{Err: fmt.Errorf("%w: %s", ErrEngineNotFound, aggregate.AggregateID())}
+	}
+	return child.EmitWithResult(event)
+}
+
+// childrenFile is the combined persisted form of a parent and its children
+type childrenFile struct {
+	Parent   json.RawMessage            `json:"parent"`
+	Children map[string]json.RawMessage `json:"children"`
+}
+
+// Marshal serializes the parent's log and every child's log together
+func (c *Children) Marshal() ([]byte, error) {
+	parent, err := c.parent.MarshalLog()
+	if err != nil {
+		return nil, err
+	}
+	file := childrenFile{Parent: parent, Children: make(map[string]json.RawMessage, len(c.engines))}
+	for id, child := range c.engines {
+		log, err := child.MarshalLog()
+		if err != nil {
+			return nil, fmt.Errorf("child %s: %w", id, err)
+		}
+		file.Children[id] = log
+	}
+	return json.Marshal(file)
+}
+
+// Unmarshal restores the parent and its children from Marshal output,
+// replacing any children already hosted. Logs are loaded with SetEventsE, so
+// nothing is spawned or bubbled up again, and if the parent's log can't be
+// stored the hosted children are kept.
+func (c *Children) Unmarshal(data []byte) error {
+	var file childrenFile
+	if err := json.Unmarshal(data, &file); err != nil {
+		return err
+	}
+
+	parentEvents, err := c.parent.UnmarshalEvents(file.Parent)
+	if err != nil {
+		return err
+	}
+	// Decode every log before touching the current children
+	engines := make(map[string]*Engine, len(file.Children))
+	children := make(map[string][]Event, len(file.Children))
+	for id, log := range file.Children {
+		child := c.build(id)
+		events, err := child.UnmarshalEvents(log)
+		if err != nil {
+			return fmt.Errorf("child %s: %w", id, err)
+		}
+		engines[id], children[id] = child, events
+	}
+
+	if err := c.parent.SetEventsE(parentEvents); err != nil {
+		return err
+	}
+	c.engines = engines
+	for id, events := range children {
+		if err := c.engines[id].SetEventsE(events); err != nil {
+			return fmt.Errorf("child %s: %w", id, err)
+		}
+	}
+	return nil
+}
+
+// create builds and registers a child engine
+func (c *Children) create(id string) *Engine {
+	child := c.build(id)
+	c.engines[id] = child
+	return child
+}
+
+// build creates and configures a child engine
+func (c *Children) build(id string) *Engine {
+	child := NewEngine()
+	if c.setup != nil {
+		c.setup(id, child)
+	}
+	for _, eventType := range c.bubbleUp {
+		child.Forward(eventType).To(c.parent)
+	}
+	return child
+}
+
+// spawnListener creates children as the parent commits spawning events
+type spawnListener struct {
+	children *Children
+}
+
+// Handle implements EventListener
+func (l *spawnListener) Handle(engine types.Engine, event Event) {
+	if aggregate, ok := event.(types.AggregateEvent); ok {
+		if _, exists := l.children.engines[aggregate.AggregateID()]; !exists {
+			l.children.create(aggregate.AggregateID())
+		}
+	}
+}
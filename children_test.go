@@ -0,0 +1,134 @@
+package atmos
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type TableOpenedEvent struct {
+	TableID string
+}
+
+func (e TableOpenedEvent) Type() string        { return "table_opened" }
+func (e TableOpenedEvent) AggregateID() string { return e.TableID }
+
+type HandPlayedEvent struct {
+	TableID string
+	Winner  string
+}
+
+func (e HandPlayedEvent) Type() string        { return "hand_played" }
+func (e HandPlayedEvent) AggregateID() string { return e.TableID }
+
+type TableClosedEvent struct {
+	TableID string
+	Winner  string
+}
+
+func (e TableClosedEvent) Type() string        { return "table_closed" }
+func (e TableClosedEvent) AggregateID() string { return e.TableID }
+
+// newCasino hosts one child per table; a table closes after its second hand
+func newCasino(opts ...EngineOption) (*Engine, *Children) {
+	casino := NewEngine(opts...)
+	casino.RegisterEventTypes(TableOpenedEvent{}, TableClosedEvent{})
+	casino.RegisterState("closed", 0)
+	casino.When("table_closed").Updates("closed", func(e *Engine, state interface{}, event Event) interface{} {
+		return state.(int) + 1
+	})
+
+	tables := casino.HostChildren(func(id string, table *Engine) {
+		table.RegisterEventTypes(HandPlayedEvent{}, TableClosedEvent{})
+		table.RegisterState("hands", 0)
+		table.When("hand_played").
+			Updates("hands", func(e *Engine, state interface{}, event Event) interface{} {
+				return state.(int) + 1
+			}).
+			Then(Do[HandPlayedEvent](closeAfterTwoHands{}))
+	}, SpawnOn("table_opened"), BubbleUp("table_closed"))
+	return casino, tables
+}
+
+// closeAfterTwoHands ends a table once two hands have been played
+type closeAfterTwoHands struct{}
+
+func (closeAfterTwoHands) HandleTyped(engine *Engine, event HandPlayedEvent) {
+	if engine.GetState("hands").(int) == 2 {
+		engine.Emit(TableClosedEvent{TableID: event.TableID, Winner: event.Winner})
+	}
+}
+
+// TestChildrenRouteAndBubble verifies spawning, routing down, and bubbling summaries up
+func TestChildrenRouteAndBubble(t *testing.T) {
+	casino, tables := newCasino()
+
+	require.True(t, casino.Emit(TableOpenedEvent{TableID: "t1"}))
+	require.True(t, casino.Emit(TableOpenedEvent{TableID: "t2"}))
+	assert.Equal(t, []string{"t1", "t2"}, tables.IDs())
+
+	assert.True(t, tables.Emit(HandPlayedEvent{TableID: "t1", Winner: "ann"}).Accepted)
+	assert.True(t, tables.Emit(HandPlayedEvent{TableID: "t2", Winner: "bo"}).Accepted)
+	assert.True(t, tables.Emit(HandPlayedEvent{TableID: "t1", Winner: "cy"}).Accepted)
+
+	t1, _ := tables.Child("t1")
+	assert.Equal(t, 2, t1.GetState("hands"))
+	assert.Equal(t, 1, casino.GetState("closed"))
+	assert.Equal(t, TableClosedEvent{TableID: "t1", Winner: "cy"}, casino.GetEvents()[2])
+
+	assert.ErrorIs(t, tables.Emit(HandPlayedEvent{TableID: "t9"}).Err, ErrEngineNotFound)
+	assert.Error(t, tables.Emit(TestEvent{}).Err)
+	_, err := tables.Spawn("t1")
+	assert.ErrorIs(t, err, ErrEngineExists)
+}
+
+// TestChildrenPersistTogether verifies both layers restore without spawning or bubbling again
+func TestChildrenPersistTogether(t *testing.T) {
+	casino, tables := newCasino()
+	casino.Emit(TableOpenedEvent{TableID: "t1"})
+	casino.Emit(TableOpenedEvent{TableID: "t2"})
+	tables.Emit(HandPlayedEvent{TableID: "t1"})
+	tables.Emit(HandPlayedEvent{TableID: "t1"})
+	tables.Emit(HandPlayedEvent{TableID: "t2"})
+	data, err := tables.Marshal()
+	require.NoError(t, err)
+
+	restoredCasino, restoredTables := newCasino()
+	require.NoError(t, restoredTables.Unmarshal(data))
+
+	assert.Equal(t, []string{"t1", "t2"}, restoredTables.IDs())
+	assert.Len(t, restoredCasino.GetEvents(), 3)
+	assert.Equal(t, 1, restoredCasino.GetState("closed"))
+	t2, _ := restoredTables.Child("t2")
+	assert.Equal(t, 1, t2.GetState("hands"))
+
+	assert.True(t, restoredTables.Emit(HandPlayedEvent{TableID: "t2"}).Accepted)
+	assert.Equal(t, 2, restoredCasino.GetState("closed"), "Restored children still bubble up")
+}
+
+// TestChildrenUnmarshalFailureKeepsChildren verifies a file that doesn't decode leaves the hosted children alone
+func TestChildrenUnmarshalFailureKeepsChildren(t *testing.T) {
+	casino, tables := newCasino()
+	casino.Emit(TableOpenedEvent{TableID: "t1"})
+	tables.Emit(HandPlayedEvent{TableID: "t1"})
+
+	err := tables.Unmarshal([]byte(`{"parent":[],"children":{"t2":[],"t3":"not a log"}}`))
+	assert.ErrorContains(t, err, "child t3")
+	assert.Equal(t, []string{"t1"}, tables.IDs())
+	t1, _ := tables.Child("t1")
+	assert.Equal(t, 1, t1.GetState("hands"))
+	assert.Len(t, casino.GetEvents(), 1)
+}
+
+// TestChildrenUnmarshalReportsRepositoryFailures verifies a parent log that can't be stored is an error, not a panic
+func TestChildrenUnmarshalReportsRepositoryFailures(t *testing.T) {
+	casino, tables := newCasino(WithRepository(&unwritableRepository{}))
+	casino.Emit(TableOpenedEvent{TableID: "t1"})
+	data, err := tables.Marshal()
+	require.NoError(t, err)
+
+	err = tables.Unmarshal(data)
+	assert.ErrorContains(t, err, "disk full")
+	assert.Equal(t, []string{"t1"}, tables.IDs(), "The hosted children are kept")
+}
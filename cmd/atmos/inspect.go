@@ -0,0 +1,184 @@
+package main
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"sort"
+	"strings"
+)
+
+// record is one serialized event; the payload is kept raw since the CLI
+// knows nothing about the game's event types
+type record struct {
+	Seq  int             `json:"-"`
+	Type string          `json:"type"`
+	Data json.RawMessage `json:"data"`
+}
+
+// loadLog reads a JSON array of events, or one event object per line
+func loadLog(path string) ([]record, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+
+	var records []record
+	trimmed := bytes.TrimSpace(data)
+	if bytes.HasPrefix(trimmed, []byte("[")) || bytes.Equal(trimmed, []byte("null")) {
+		if err := json.Unmarshal(trimmed, &records); err != nil {
+			return nil, fmt.Errorf("%s: %w", path, err)
+		}
+	} else {
+		scanner := bufio.NewScanner(bytes.NewReader(data))
+		scanner.Buffer(nil, len(data)+1)
+		for line := 1; scanner.Scan(); line++ {
+			if len(bytes.TrimSpace(scanner.Bytes())) == 0 {
+				continue
+			}
+			var r record
+			if err := json.Unmarshal(scanner.Bytes(), &r); err != nil {
+				return nil, fmt.Errorf("%s:%d: %w", path, line, err)
+			}
+			records = append(records, r)
+		}
+	}
+
+	for i := range records {
+		records[i].Seq = i
+	}
+	return records, nil
+}
+
+// parseLog parses a command's flags and loads the log named by its one argument
+func parseLog(fs *flag.FlagSet, args []string, stderr io.Writer) ([]record, error) {
+	fs.SetOutput(stderr)
+	if err := fs.Parse(args); err != nil {
+		return nil, err
+	}
+	if fs.NArg() != 1 {
+		return nil, fmt.Errorf("%s: expected one log file", fs.Name())
+	}
+	return loadLog(fs.Arg(0))
+}
+
+// eventFilter selects events to list
+type eventFilter struct {
+	types    map[string]bool
+	from, to int
+	match    string
+}
+
+func (f eventFilter) matches(r record) bool {
+	if len(f.types) > 0 && !f.types[r.Type] {
+		return false
+	}
+	if r.Seq < f.from || (f.to >= 0 && r.Seq > f.to) {
+		return false
+	}
+	return f.match == "" || strings.Contains(string(r.Data), f.match)
+}
+
+// eventsCommand lists events, one per line, or indented with -pretty
+func eventsCommand(args []string, stdout, stderr io.Writer) error {
+	fs := flag.NewFlagSet("events", flag.ContinueOnError)
+	types := fs.String("type", "", "comma-separated event types to include")
+	from := fs.Int("from", 0, "first sequence to include")
+	to := fs.Int("to", -1, "last sequence to include (-1 for the end of the log)")
+	match := fs.String("match", "", "only include events whose payload contains this text")
+	pretty := fs.Bool("pretty", false, "indent payloads")
+
+	records, err := parseLog(fs, args, stderr)
+	if err != nil {
+		return err
+	}
+
+	filter := eventFilter{from: *from, to: *to, match: *match}
+	if *types != "" {
+		filter.types = make(map[string]bool)
+		for _, t := range strings.Split(*types, ",") {
+			filter.types[strings.TrimSpace(t)] = true
+		}
+	}
+
+	for _, r := range records {
+		if !filter.matches(r) {
+			continue
+		}
+		if *pretty {
+			fmt.Fprintf(stdout, "#%d %s\n%s\n", r.Seq, r.Type, indent(r.Data))
+		} else {
+			fmt.Fprintf(stdout, "%d\t%s\t%s\n", r.Seq, r.Type, compact(r.Data))
+		}
+	}
+	return nil
+}
+
+// showCommand pretty-prints a single event
+func showCommand(args []string, stdout, stderr io.Writer) error {
+	fs := flag.NewFlagSet("show", flag.ContinueOnError)
+	seq := fs.Int("seq", -1, "sequence of the event to show")
+
+	records, err := parseLog(fs, args, stderr)
+	if err != nil {
+		return err
+	}
+	if *seq < 0 || *seq >= len(records) {
+		return fmt.Errorf("show: sequence %d is not in the log (0-%d)", *seq, len(records)-1)
+	}
+
+	r := records[*seq]
+	fmt.Fprintf(stdout, "sequence: %d\ntype:     %s\ndata:\n%s\n", r.Seq, r.Type, indent(r.Data))
+	return nil
+}
+
+// countsCommand prints the number of events of each type, most common first
+func countsCommand(args []string, stdout, stderr io.Writer) error {
+	records, err := parseLog(flag.NewFlagSet("counts", flag.ContinueOnError), args, stderr)
+	if err != nil {
+		return err
+	}
+
+	counts := make(map[string]int)
+	for _, r := range records {
+		counts[r.Type]++
+	}
+	types := make([]string, 0, len(counts))
+	for t := range counts {
+		types = append(types, t)
+	}
+	sort.Slice(types, func(i, j int) bool {
+		if counts[types[i]] != counts[types[j]] {
+			return counts[types[i]] > counts[types[j]]
+		}
+		return types[i] < types[j]
+	})
+
+	for _, t := range types {
+		fmt.Fprintf(stdout, "%6d  %s\n", counts[t], t)
+	}
+	fmt.Fprintf(stdout, "%6d  total\n", len(records))
+	return nil
+}
+
+// compact renders a payload on one line
+func compact(data json.RawMessage) string {
+	var b bytes.Buffer
+	if err := json.Compact(&b, data); err != nil {
+		return string(data)
+	}
+	return b.String()
+}
+
+// indent renders a payload indented for reading
+func indent(data json.RawMessage) string {
+	var b bytes.Buffer
+	if err := json.Indent(&b, data, "", "  "); err != nil {
+		return string(data)
+	}
+	return b.String()
+}
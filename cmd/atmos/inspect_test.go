@@ -0,0 +1,57 @@
+package main
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// runCLI runs the CLI, returning its exit code and output
+func runCLI(args ...string) (int, string, string) {
+	var stdout, stderr bytes.Buffer
+	code := run(args, &stdout, &stderr)
+	return code, stdout.String(), stderr.String()
+}
+
+// TestEventsFilters verifies listing with type, range, and text filters
+func TestEventsFilters(t *testing.T) {
+	code, out, _ := runCLI("events", "-type", "move", "-from", "2", "testdata/log.json")
+	assert.Equal(t, 0, code)
+	assert.Equal(t, "3\tmove\t{\"player\":\"bob\",\"square\":0}\n4\tmove\t{\"player\":\"alice\",\"square\":8}\n", out)
+
+	_, out, _ = runCLI("events", "-match", "bob", "-to", "2", "testdata/log.json")
+	assert.Equal(t, "0\tgame_started\t{\"players\":[\"alice\",\"bob\"]}\n2\tchat\t{\"player\":\"bob\",\"text\":\"nice\"}\n", out)
+
+	_, out, _ = runCLI("events", "-pretty", "-type", "chat", "testdata/log.jsonl")
+	assert.Equal(t, "#1 chat\n{\n  \"player\": \"bob\",\n  \"text\": \"gg\"\n}\n", out)
+}
+
+// TestShowAndCounts verifies single-event display and per-type counts
+func TestShowAndCounts(t *testing.T) {
+	code, out, _ := runCLI("show", "-seq", "1", "testdata/log.json")
+	assert.Equal(t, 0, code)
+	assert.Equal(t, "sequence: 1\ntype:     move\ndata:\n{\n  \"player\": \"alice\",\n  \"square\": 4\n}\n", out)
+
+	_, out, _ = runCLI("counts", "testdata/log.json")
+	assert.Equal(t, "     3  move\n     1  chat\n     1  game_started\n     5  total\n", out)
+}
+
+// TestErrors verifies bad invocations report errors with non-zero exit codes
+func TestErrors(t *testing.T) {
+	code, _, errOut := runCLI()
+	assert.Equal(t, 2, code)
+	assert.Contains(t, errOut, "usage: atmos")
+
+	code, _, errOut = runCLI("frobnicate")
+	assert.Equal(t, 2, code)
+	assert.Contains(t, errOut, `unknown command "frobnicate"`)
+
+	code, _, errOut = runCLI("show", "-seq", "9", "testdata/log.json")
+	assert.Equal(t, 1, code)
+	assert.Equal(t, "atmos: show: sequence 9 is not in the log (0-4)\n", errOut)
+
+	code, _, errOut = runCLI("counts", "testdata/missing.json")
+	assert.Equal(t, 1, code)
+	assert.Contains(t, errOut, "missing.json")
+}
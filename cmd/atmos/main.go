@@ -0,0 +1,63 @@
+// Command atmos inspects serialized event logs (the JSON written by
+// Engine.MarshalEvents, or one {"type", "data"} object per line):
+//
+//	atmos events [-type move,chat] [-from 10] [-to 20] [-match alice] [-pretty] log.json
+//	atmos show -seq 42 log.json
+//	atmos counts log.json
+//	atmos replay -plugin game.so [-state game] [-at 42] log.json
+//
+// Sequences are positions in the log, starting at 0. Replaying needs the
+// game's registrations, loaded from a Go plugin (go build -buildmode=plugin)
+// that exports a Blueprint *atmos.Blueprint variable or a
+// NewEngine() *atmos.Engine function.
+package main
+
+import (
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+)
+
+const usage = `usage: atmos <command> [flags] <log>
+
+commands:
+  events   list events, optionally filtered
+  show     pretty-print one event
+  counts   count events by type
+  replay   print projected states at a point in the log
+`
+
+func main() {
+	os.Exit(run(os.Args[1:], os.Stdout, os.Stderr))
+}
+
+// run executes a command, returning the process exit code
+func run(args []string, stdout, stderr io.Writer) int {
+	if len(args) == 0 {
+		fmt.Fprint(stderr, usage)
+		return 2
+	}
+
+	commands := map[string]func(args []string, stdout, stderr io.Writer) error{
+		"events": eventsCommand,
+		"show":   showCommand,
+		"counts": countsCommand,
+		"replay": replayCommand,
+	}
+	command, exists := commands[args[0]]
+	if !exists {
+		fmt.Fprintf(stderr, "atmos: unknown command %q\n\n%s", args[0], usage)
+		return 2
+	}
+
+	if err := command(args[1:], stdout, stderr); err != nil {
+		if errors.Is(err, flag.ErrHelp) {
+			return 0
+		}
+		fmt.Fprintln(stderr, "atmos:", err)
+		return 1
+	}
+	return 0
+}
@@ -0,0 +1,129 @@
+package main
+
+import (
+	"encoding/json"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"plugin"
+	"strings"
+
+	"github.com/cumulusrpg/atmos"
+)
+
+// openPlugin loads an engine factory from a Go plugin built with
+// -buildmode=plugin that exports either
+//
+//	var Blueprint *atmos.Blueprint
+//	func NewEngine() *atmos.Engine
+var openPlugin = func(path string) (func() *atmos.Engine, error) {
+	p, err := plugin.Open(path)
+	if err != nil {
+		return nil, err
+	}
+
+	if symbol, err := p.Lookup("Blueprint"); err == nil {
+		if blueprint, ok := symbol.(**atmos.Blueprint); ok && *blueprint != nil {
+			return func() *atmos.Engine { return (*blueprint).NewEngine() }, nil
+		}
+	}
+	if symbol, err := p.Lookup("NewEngine"); err == nil {
+		switch newEngine := symbol.(type) {
+		case func() *atmos.Engine:
+			return newEngine, nil
+		case *func() *atmos.Engine:
+			return *newEngine, nil
+		}
+	}
+	return nil, fmt.Errorf("%s exports neither Blueprint *atmos.Blueprint nor NewEngine func() *atmos.Engine", path)
+}
+
+// stringList is a repeatable string flag
+type stringList []string
+
+func (l *stringList) String() string { return strings.Join(*l, ",") }
+
+func (l *stringList) Set(value string) error {
+	*l = append(*l, value)
+	return nil
+}
+
+// replayCommand replays a log into an engine from a plugin and prints projected states
+func replayCommand(args []string, stdout, stderr io.Writer) error {
+	fs := flag.NewFlagSet("replay", flag.ContinueOnError)
+	pluginPath := fs.String("plugin", "", "Go plugin exporting the game's registrations (required)")
+	at := fs.Int("at", -1, "replay up to and including this sequence (-1 for the whole log)")
+	var states stringList
+	fs.Var(&states, "state", "state to print (repeatable; default all states)")
+
+	records, err := parseLog(fs, args, stderr)
+	if err != nil {
+		return err
+	}
+	if *pluginPath == "" {
+		return errors.New("replay: -plugin is required")
+	}
+	if *at >= len(records) {
+		return fmt.Errorf("replay: sequence %d is not in the log (0-%d)", *at, len(records)-1)
+	}
+	if *at >= 0 {
+		records = records[:*at+1]
+	}
+
+	newEngine, err := openPlugin(*pluginPath)
+	if err != nil {
+		return fmt.Errorf("replay: %w", err)
+	}
+	engine := newEngine()
+
+	events := make([]atmos.Event, len(records))
+	for i, r := range records {
+		data, err := json.Marshal(r)
+		if err != nil {
+			return err
+		}
+		if events[i], err = engine.UnmarshalEvent(data); err != nil {
+			return fmt.Errorf("replay: event %d: %w", r.Seq, err)
+		}
+	}
+	if err := engine.SetEventsE(events); err != nil {
+		return fmt.Errorf("replay: %w", err)
+	}
+
+	return printStates(engine, states, stdout)
+}
+
+// printStates prints one state's JSON, or an object of several states by name
+func printStates(engine *atmos.Engine, names []string, stdout io.Writer) error {
+	known := make(map[string]bool)
+	for _, name := range engine.StateNames() {
+		known[name] = true
+	}
+	for _, name := range names {
+		if !known[name] {
+			return fmt.Errorf("replay: unknown state %q (have %s)", name, strings.Join(engine.StateNames(), ", "))
+		}
+	}
+
+	var value interface{}
+	if len(names) == 1 {
+		value = engine.GetState(names[0])
+	} else {
+		if len(names) == 0 {
+			names = engine.StateNames()
+		}
+		all := make(map[string]interface{}, len(names))
+		for _, name := range names {
+			all[name] = engine.GetState(name)
+		}
+		value = all
+	}
+
+	data, err := json.MarshalIndent(value, "", "  ")
+	if err != nil {
+		return err
+	}
+	fmt.Fprintf(stdout, "%s\n", data)
+	return nil
+}
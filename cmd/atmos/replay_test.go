@@ -0,0 +1,92 @@
+package main
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/cumulusrpg/atmos"
+	"github.com/stretchr/testify/assert"
+)
+
+type moveEvent struct {
+	Player string `json:"player"`
+	Square int    `json:"square"`
+}
+
+func (e *moveEvent) Type() string { return "move" }
+
+type chatEvent struct{}
+
+func (e *chatEvent) Type() string { return "chat" }
+
+type gameStartedEvent struct {
+	Players []string `json:"players"`
+}
+
+func (e *gameStartedEvent) Type() string { return "game_started" }
+
+type board struct {
+	Squares [9]string `json:"squares"`
+}
+
+// ticTacToe stands in for a game plugin's registrations
+var ticTacToe = atmos.NewBlueprint(func(engine *atmos.Engine) {
+	engine.RegisterEventTypes(&moveEvent{}, &chatEvent{}, &gameStartedEvent{})
+	engine.RegisterState("board", board{})
+	engine.RegisterState("moves", 0)
+	engine.When("move").
+		Updates("board", func(e *atmos.Engine, state interface{}, event atmos.Event) interface{} {
+			b := state.(board)
+			b.Squares[event.(*moveEvent).Square] = event.(*moveEvent).Player
+			return b
+		}).
+		Updates("moves", func(e *atmos.Engine, state interface{}, event atmos.Event) interface{} {
+			return state.(int) + 1
+		})
+})
+
+// withPlugin replaces plugin loading for the duration of a test
+func withPlugin(t *testing.T, blueprint *atmos.Blueprint) {
+	original := openPlugin
+	openPlugin = func(path string) (func() *atmos.Engine, error) {
+		if path != "game.so" {
+			return nil, errors.New("plugin not found")
+		}
+		return func() *atmos.Engine { return blueprint.NewEngine() }, nil
+	}
+	t.Cleanup(func() { openPlugin = original })
+}
+
+// TestReplayPrintsStateAtSequence verifies states are projected up to -at
+func TestReplayPrintsStateAtSequence(t *testing.T) {
+	withPlugin(t, ticTacToe)
+
+	code, out, errOut := runCLI("replay", "-plugin", "game.so", "-state", "moves", "-at", "2", "testdata/log.json")
+	assert.Equal(t, 0, code, errOut)
+	assert.Equal(t, "1\n", out)
+
+	_, out, _ = runCLI("replay", "-plugin", "game.so", "-state", "moves", "-state", "board", "testdata/log.json")
+	assert.JSONEq(t, `{"moves": 3, "board": {"squares": ["bob", "", "", "", "alice", "", "", "", "alice"]}}`, out)
+}
+
+// TestReplayErrors verifies missing plugins, states, and sequences are reported
+func TestReplayErrors(t *testing.T) {
+	withPlugin(t, ticTacToe)
+
+	code, _, errOut := runCLI("replay", "testdata/log.json")
+	assert.Equal(t, 1, code)
+	assert.Contains(t, errOut, "-plugin is required")
+
+	_, _, errOut = runCLI("replay", "-plugin", "other.so", "testdata/log.json")
+	assert.Contains(t, errOut, "plugin not found")
+
+	_, _, errOut = runCLI("replay", "-plugin", "game.so", "-state", "score", "testdata/log.json")
+	assert.Equal(t, "atmos: replay: unknown state \"score\" (have board, moves)\n", errOut)
+
+	_, _, errOut = runCLI("replay", "-plugin", "game.so", "-at", "5", "testdata/log.json")
+	assert.Contains(t, errOut, "sequence 5 is not in the log")
+
+	withPlugin(t, atmos.NewBlueprint(func(engine *atmos.Engine) {}))
+	_, _, errOut = runCLI("replay", "-plugin", "game.so", "testdata/log.json")
+	assert.Equal(t, "atmos: replay: event 0: unknown event type: game_started\n", errOut)
+}
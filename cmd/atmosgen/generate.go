@@ -0,0 +1,225 @@
+package main
+
+import (
+	"bytes"
+	"fmt"
+	"go/ast"
+	"go/format"
+	"go/parser"
+	"go/token"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"text/template"
+
+	"github.com/cumulusrpg/atmos"
+)
+
+// directive marks an event struct: //atmos:event [type_name]
+const directive = "//atmos:event"
+
+// eventSpec is one event struct found in the package
+type eventSpec struct {
+	Name     string // Go type name
+	TypeName string // Event type string
+	HasType  bool   // The struct already declares Type()
+}
+
+// generate scans the Go package in dir and renders the wiring file
+func generate(dir, output string) ([]byte, error) {
+	fset := token.NewFileSet()
+	pkgs, err := parser.ParseDir(fset, dir, func(info os.FileInfo) bool {
+		return !strings.HasSuffix(info.Name(), "_test.go") && info.Name() != filepath.Base(output)
+	}, parser.ParseComments)
+	if err != nil {
+		return nil, err
+	}
+	if len(pkgs) != 1 {
+		return nil, fmt.Errorf("expected one package in %s, found %d", dir, len(pkgs))
+	}
+
+	var pkgName string
+	var files []*ast.File
+	for name, pkg := range pkgs {
+		pkgName = name
+		for _, file := range pkg.Files {
+			files = append(files, file)
+		}
+	}
+
+	events, err := findEvents(files)
+	if err != nil {
+		return nil, err
+	}
+	if len(events) == 0 {
+		return nil, fmt.Errorf("no %s structs found in %s", directive, dir)
+	}
+
+	var buf bytes.Buffer
+	if err := fileTemplate.Execute(&buf, struct {
+		Package string
+		Events  []eventSpec
+	}{pkgName, events}); err != nil {
+		return nil, err
+	}
+	return format.Source(buf.Bytes())
+}
+
+// findEvents collects annotated structs and any Type() methods they already have
+func findEvents(files []*ast.File) ([]eventSpec, error) {
+	typeMethods := make(map[string]string) // receiver type -> literal returned by Type() ("" if not a literal)
+	var events []eventSpec
+
+	for _, file := range files {
+		for _, decl := range file.Decls {
+			switch d := decl.(type) {
+			case *ast.FuncDecl:
+				if d.Recv != nil && d.Name.Name == "Type" && len(d.Recv.List) == 1 {
+					typeMethods[receiverName(d.Recv.List[0].Type)] = returnedLiteral(d)
+				}
+			case *ast.GenDecl:
+				if d.Tok != token.TYPE {
+					continue
+				}
+				for _, spec := range d.Specs {
+					ts := spec.(*ast.TypeSpec)
+					if _, isStruct := ts.Type.(*ast.StructType); !isStruct {
+						continue
+					}
+					doc := ts.Doc
+					if doc == nil && len(d.Specs) == 1 {
+						doc = d.Doc
+					}
+					if name, ok := eventDirective(doc); ok {
+						events = append(events, eventSpec{Name: ts.Name.Name, TypeName: name})
+					}
+				}
+			}
+		}
+	}
+
+	for i := range events {
+		literal, hasType := typeMethods[events[i].Name]
+		events[i].HasType = hasType
+		switch {
+		case events[i].TypeName != "" && hasType && literal != "" && literal != events[i].TypeName:
+			return nil, fmt.Errorf("%s: %s names type %q but Type() returns %q", events[i].Name, directive, events[i].TypeName, literal)
+		case events[i].TypeName != "":
+		case hasType && literal != "":
+			events[i].TypeName = literal
+		case hasType:
+			return nil, fmt.Errorf("%s: Type() does not return a string literal; name the type in the %s directive", events[i].Name, directive)
+		default:
+			events[i].TypeName = atmos.SnakeCase(events[i].Name)
+		}
+	}
+
+	sort.Slice(events, func(i, j int) bool { return events[i].Name < events[j].Name })
+	return events, nil
+}
+
+// eventDirective finds the directive in a doc comment, returning its optional type name
+func eventDirective(doc *ast.CommentGroup) (string, bool) {
+	if doc == nil {
+		return "", false
+	}
+	for _, comment := range doc.List {
+		if comment.Text == directive {
+			return "", true
+		}
+		if rest, ok := strings.CutPrefix(comment.Text, directive+" "); ok {
+			return strings.TrimSpace(rest), true
+		}
+	}
+	return "", false
+}
+
+// receiverName returns the type name of a method receiver (T or *T)
+func receiverName(expr ast.Expr) string {
+	if star, ok := expr.(*ast.StarExpr); ok {
+		expr = star.X
+	}
+	if ident, ok := expr.(*ast.Ident); ok {
+		return ident.Name
+	}
+	return ""
+}
+
+// returnedLiteral returns the string literal of a `return "..."` body, or ""
+func returnedLiteral(fn *ast.FuncDecl) string {
+	if fn.Body == nil || len(fn.Body.List) != 1 {
+		return ""
+	}
+	ret, ok := fn.Body.List[0].(*ast.ReturnStmt)
+	if !ok || len(ret.Results) != 1 {
+		return ""
+	}
+	lit, ok := ret.Results[0].(*ast.BasicLit)
+	if !ok || lit.Kind != token.STRING {
+		return ""
+	}
+	value, err := strconv.Unquote(lit.Value)
+	if err != nil {
+		return ""
+	}
+	return value
+}
+
+var fileTemplate = template.Must(template.New("events").Parse(`// Code generated by atmosgen. DO NOT EDIT.
+
+package {{.Package}}
+
+import (
+	"github.com/cumulusrpg/atmos"
+	"github.com/cumulusrpg/atmos/types"
+)
+
+// Event type names
+const (
+{{- range .Events}}
+	{{.Name}}Type = {{printf "%q" .TypeName}}
+{{- end}}
+)
+
+// RegisterEvents registers a factory for every event type so logs can be deserialized
+func RegisterEvents(engine *atmos.Engine) {
+{{- range .Events}}
+	engine.RegisterEventType({{.Name}}Type, func() atmos.Event { return &{{.Name}}{} })
+{{- end}}
+}
+{{range .Events}}
+{{- if not .HasType}}
+// Type returns the event type name
+func ({{.Name}}) Type() string { return {{.Name}}Type }
+{{end}}
+// {{.Name}}Validator adapts a function to an atmos.EventValidator for {{.Name}},
+// accepting the event by value or pointer
+type {{.Name}}Validator func(engine *atmos.Engine, event {{.Name}}) bool
+
+// Validate implements atmos.EventValidator
+func (f {{.Name}}Validator) Validate(engine types.Engine, event atmos.Event) bool {
+	switch e := event.(type) {
+	case {{.Name}}:
+		return f(engine.(*atmos.Engine), e)
+	case *{{.Name}}:
+		return f(engine.(*atmos.Engine), *e)
+	}
+	return false
+}
+
+// {{.Name}}Listener adapts a function to an atmos.EventListener for {{.Name}},
+// accepting the event by value or pointer
+type {{.Name}}Listener func(engine *atmos.Engine, event {{.Name}})
+
+// Handle implements atmos.EventListener
+func (f {{.Name}}Listener) Handle(engine types.Engine, event atmos.Event) {
+	switch e := event.(type) {
+	case {{.Name}}:
+		f(engine.(*atmos.Engine), e)
+	case *{{.Name}}:
+		f(engine.(*atmos.Engine), *e)
+	}
+}
+{{end}}`))
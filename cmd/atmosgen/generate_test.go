@@ -0,0 +1,26 @@
+package main
+
+import (
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// TestGenerate verifies the wiring generated for the cards fixture
+func TestGenerate(t *testing.T) {
+	source, err := generate(filepath.Join("testdata", "cards"), "atmos_events_gen.go")
+	assert.NoError(t, err)
+
+	code := string(source)
+	assert.Contains(t, code, "package cards")
+	assert.Contains(t, code, `CardDrawnEventType = "card_drawn"`)
+	assert.Contains(t, code, `ShuffleEventType   = "deck_shuffled"`)
+	assert.Contains(t, code, `HTTPSyncEventType  = "http_synced"`)
+	assert.Contains(t, code, "engine.RegisterEventType(ShuffleEventType, func() atmos.Event { return &ShuffleEvent{} })")
+	assert.Contains(t, code, "func (CardDrawnEvent) Type() string { return CardDrawnEventType }")
+	assert.NotContains(t, code, "func (HTTPSyncEvent) Type()", "Existing Type() methods are kept")
+	assert.Contains(t, code, "type ShuffleEventValidator func(engine *atmos.Engine, event ShuffleEvent) bool")
+	assert.Contains(t, code, "type CardDrawnEventListener func(engine *atmos.Engine, event CardDrawnEvent)")
+	assert.NotContains(t, code, "HandType", "Unmarked structs are ignored")
+}
@@ -0,0 +1,39 @@
+// Command atmosgen generates event wiring for a package: type name constants,
+// missing Type() methods, a RegisterEvents function with a factory per event,
+// and typed validator/listener adapters. Mark event structs with a directive
+// (the type name defaults to atmos.SnakeCase of the struct name):
+//
+//	//atmos:event
+//	type MoveMadeEvent struct { ... }
+//
+//	//atmos:event piece_captured
+//	type CaptureEvent struct { ... }
+//
+// and add to the package:
+//
+//	//go:generate go run github.com/cumulusrpg/atmos/cmd/atmosgen
+package main
+
+import (
+	"flag"
+	"fmt"
+	"os"
+	"path/filepath"
+)
+
+func main() {
+	dir := flag.String("dir", ".", "package directory to scan")
+	output := flag.String("output", "atmos_events_gen.go", "generated file name, relative to -dir")
+	flag.Parse()
+
+	source, err := generate(*dir, *output)
+	if err != nil {
+		fmt.Fprintln(os.Stderr, "atmosgen:", err)
+		os.Exit(1)
+	}
+
+	if err := os.WriteFile(filepath.Join(*dir, *output), source, 0o644); err != nil {
+		fmt.Fprintln(os.Stderr, "atmosgen:", err)
+		os.Exit(1)
+	}
+}
@@ -0,0 +1,27 @@
+package cards
+
+// CardDrawnEvent has its type derived from the struct name
+//
+//atmos:event
+type CardDrawnEvent struct {
+	Player string `json:"player"`
+}
+
+// ShuffleEvent names its type explicitly
+//
+//atmos:event deck_shuffled
+type ShuffleEvent struct {
+	Seed int64 `json:"seed"`
+}
+
+// HTTPSyncEvent keeps its handwritten Type()
+//
+//atmos:event
+type HTTPSyncEvent struct{}
+
+func (e HTTPSyncEvent) Type() string { return "http_synced" }
+
+// Hand is not an event
+type Hand struct {
+	Cards []string
+}
@@ -0,0 +1,396 @@
+// Package deck manages cards moving between a hidden draw pile, players'
+// hands, and a public discard pile, with deterministic shuffling:
+//
+//	cards := deck.New()
+//	cards.Install(engine)
+//	engine.RegisterService(deck.RNGService, rand.New(rand.NewSource(seed)))
+//
+//	engine.Emit(deck.CreatedEvent{Cards: []string{"ace", "king", "queen"}})
+//	cards.Shuffle(engine)
+//	engine.Emit(deck.DrawnEvent{Player: "alice", Count: 2})
+//
+//	engine.When("card_played").Requires(cards.InHand(func(event atmos.Event) (string, string) {
+//		played := event.(CardPlayedEvent)
+//		return played.Player, played.Card
+//	}))
+//
+// Shuffles record their seed in the log, so replays deal the same cards. With
+// the seed and the created order anyone could rebuild the draw pile, so the
+// seed is redacted for every viewer (GetEventsFor, atmoshttp, atmosws) and
+// tagged for field encryption in stored logs (see atmos.WithFieldEncryption).
+// View, which GetStateFor also serves, hides the draw pile order and other
+// players' hands from a player.
+package deck
+
+import (
+	"fmt"
+	"math/rand"
+	"time"
+
+	"github.com/cumulusrpg/atmos"
+	"github.com/cumulusrpg/atmos/types"
+)
+
+// RNGService is the service name of the random source used to pick shuffle
+// seeds; register a seeded *rand.Rand (or any RNG) for reproducible games
+const RNGService = "rng"
+
+// RNG is the random source Shuffle draws seeds from
+type RNG interface {
+	Int63() int64
+}
+
+// Event types
+const (
+	CreatedEventType   = "deck_created"
+	ShuffledEventType  = "deck_shuffled"
+	DrawnEventType     = "cards_drawn"
+	DiscardedEventType = "card_discarded"
+	RevealedEventType  = "card_revealed"
+)
+
+// CreatedEvent fills the draw pile (top card first), emptying hands and the discard pile
+type CreatedEvent struct {
+	Deck  string   `json:"deck,omitempty"` // The deck's state name ("" for the default deck)
+	Cards []string `json:"cards"`
+}
+
+// Type implements atmos.Event
+func (e CreatedEvent) Type() string { return CreatedEventType }
+
+// ShuffledEvent shuffles the draw pile using a recorded seed
+type ShuffledEvent struct {
+	Deck string `json:"deck,omitempty"`
+	Seed int64  `json:"seed" atmos:"encrypt"` // Secret: it determines the draw pile
+}
+
+// Type implements atmos.Event
+func (e ShuffledEvent) Type() string { return ShuffledEventType }
+
+// DrawnEvent moves cards from the top of the draw pile to a player's hand
+type DrawnEvent struct {
+	Deck   string `json:"deck,omitempty"`
+	Player string `json:"player"`
+	Count  int    `json:"count"`
+}
+
+// Type implements atmos.Event
+func (e DrawnEvent) Type() string { return DrawnEventType }
+
+// DiscardedEvent moves a card from a player's hand to the discard pile
+type DiscardedEvent struct {
+	Deck   string `json:"deck,omitempty"`
+	Player string `json:"player"`
+	Card   string `json:"card"`
+}
+
+// Type implements atmos.Event
+func (e DiscardedEvent) Type() string { return DiscardedEventType }
+
+// RevealedEvent shows a card in a player's hand to everyone
+type RevealedEvent struct {
+	Deck   string `json:"deck,omitempty"`
+	Player string `json:"player"`
+	Card   string `json:"card"`
+}
+
+// Type implements atmos.Event
+func (e RevealedEvent) Type() string { return RevealedEventType }
+
+// Zones is the full (unredacted) state of a deck. Every move copies the
+// zones it touches; earlier Zones values stay intact.
+type Zones struct {
+	Draw     []string                  `json:"draw"`     // Hidden, top card first
+	Hands    map[string][]string       `json:"hands"`    // Hidden from other players
+	Discard  []string                  `json:"discard"`  // Public, most recent last
+	Revealed map[string]map[string]int `json:"revealed"` // Player -> card -> copies in hand shown to everyone
+}
+
+// View is what one player may see of a deck
+type View struct {
+	DrawCount  int                 `json:"draw_count"`
+	Hand       []string            `json:"hand"`
+	HandCounts map[string]int      `json:"hand_counts"` // Every player's hand size
+	Revealed   map[string][]string `json:"revealed"`    // Other players' revealed cards
+	Discard    []string            `json:"discard"`
+}
+
+// Deck installs card management on an engine
+type Deck struct {
+	name string
+}
+
+// Option configures deck construction
+type Option func(*Deck)
+
+// WithStateName sets the name of the deck's state (default "deck").
+// Events for other decks must set their Deck field to this name.
+func WithStateName(name string) Option {
+	return func(d *Deck) {
+		d.name = name
+	}
+}
+
+// New creates a deck
+func New(opts ...Option) *Deck {
+	d := &Deck{name: "deck"}
+
+	// Apply options
+	for _, opt := range opts {
+		opt(d)
+	}
+
+	return d
+}
+
+// Install registers the deck's event types, state, and validators
+func (d *Deck) Install(engine *atmos.Engine) {
+	engine.RegisterState(d.name, Zones{})
+
+	factories := []func() atmos.Event{
+		func() atmos.Event { return &CreatedEvent{} },
+		func() atmos.Event { return &ShuffledEvent{} },
+		func() atmos.Event { return &DrawnEvent{} },
+		func() atmos.Event { return &DiscardedEvent{} },
+		func() atmos.Event { return &RevealedEvent{} },
+	}
+	for _, factory := range factories {
+		engine.When(factory().Type(), factory).
+			Requires(&zoneValidator{deck: d}).
+			Updates(d.name, d.reduce)
+	}
+
+	// Nobody sees seeds or unredacted zones
+	engine.RegisterEventMask(ShuffledEventType, atmos.RedactFields(func(atmos.Event) string { return "" }, "Seed"))
+	engine.RegisterStateMask(d.name, func(engine *atmos.Engine, viewer string, state interface{}) interface{} {
+		return viewOf(state.(Zones), viewer)
+	})
+}
+
+// Zones returns the full state of the deck
+func (d *Deck) Zones(engine *atmos.Engine) Zones {
+	return engine.GetState(d.name).(Zones)
+}
+
+// Hand returns a player's hand
+func (d *Deck) Hand(engine *atmos.Engine, player string) []string {
+	return d.Zones(engine).Hands[player]
+}
+
+// View returns the deck as a player may see it
+func (d *Deck) View(engine *atmos.Engine, player string) View {
+	return viewOf(d.Zones(engine), player)
+}
+
+// viewOf redacts zones for a player
+func viewOf(zones Zones, player string) View {
+	view := View{
+		DrawCount:  len(zones.Draw),
+		Hand:       zones.Hands[player],
+		HandCounts: make(map[string]int, len(zones.Hands)),
+		Revealed:   make(map[string][]string),
+		Discard:    zones.Discard,
+	}
+	for owner, hand := range zones.Hands {
+		view.HandCounts[owner] = len(hand)
+		if owner == player {
+			continue
+		}
+		shown := make(map[string]int, len(zones.Revealed[owner]))
+		for _, card := range hand {
+			if shown[card] < zones.Revealed[owner][card] {
+				shown[card]++
+				view.Revealed[owner] = append(view.Revealed[owner], card)
+			}
+		}
+	}
+	return view
+}
+
+// Shuffle emits a ShuffledEvent with a seed from the engine's RNG service
+// (or the clock if none is registered)
+func (d *Deck) Shuffle(engine *atmos.Engine) bool {
+	var seed int64
+	if rng, ok := engine.GetService(RNGService).(RNG); ok {
+		seed = rng.Int63()
+	} else {
+		seed = time.Now().UnixNano()
+	}
+	return engine.Emit(ShuffledEvent{Deck: d.deckField(), Seed: seed})
+}
+
+// InHand returns a validator, for any event type, requiring that the card
+// selected from the event is in the selected player's hand
+func (d *Deck) InHand(selector func(event atmos.Event) (player, card string)) atmos.EventValidator {
+	return &inHandValidator{deck: d, selector: selector}
+}
+
+// deckField is the Deck field value of events for this deck
+func (d *Deck) deckField() string {
+	if d.name == "deck" {
+		return ""
+	}
+	return d.name
+}
+
+// owns reports whether an event's Deck field refers to this deck
+func (d *Deck) owns(deck string) bool {
+	return deck == d.deckField() || deck == d.name
+}
+
+// reduce moves cards between zones
+func (d *Deck) reduce(engine *atmos.Engine, state interface{}, event atmos.Event) interface{} {
+	zones := state.(Zones)
+	switch e := atmos.Deref(event).(type) {
+	case CreatedEvent:
+		if d.owns(e.Deck) {
+			return Zones{Draw: append([]string(nil), e.Cards...)}
+		}
+	case ShuffledEvent:
+		if d.owns(e.Deck) {
+			draw := append([]string(nil), zones.Draw...)
+			rand.New(rand.NewSource(e.Seed)).Shuffle(len(draw), func(i, j int) {
+				draw[i], draw[j] = draw[j], draw[i]
+			})
+			zones.Draw = draw
+		}
+	case DrawnEvent:
+		if d.owns(e.Deck) && e.Count <= len(zones.Draw) {
+			hand := append(append([]string(nil), zones.Hands[e.Player]...), zones.Draw[:e.Count]...)
+			zones.Draw = zones.Draw[e.Count:]
+			zones.Hands = withHand(zones.Hands, e.Player, hand)
+		}
+	case DiscardedEvent:
+		if d.owns(e.Deck) {
+			hand := without(zones.Hands[e.Player], e.Card)
+			zones.Hands = withHand(zones.Hands, e.Player, hand)
+			zones.Discard = append(append([]string(nil), zones.Discard...), e.Card)
+			if revealed := zones.Revealed[e.Player][e.Card]; revealed > count(hand, e.Card) {
+				zones.Revealed = withRevealed(zones.Revealed, e.Player, e.Card, revealed-1)
+			}
+		}
+	case RevealedEvent:
+		if revealed := zones.Revealed[e.Player][e.Card]; d.owns(e.Deck) && revealed < count(zones.Hands[e.Player], e.Card) {
+			zones.Revealed = withRevealed(zones.Revealed, e.Player, e.Card, revealed+1)
+		}
+	}
+	return zones
+}
+
+// zoneValidator rejects impossible card movements
+type zoneValidator struct {
+	deck *Deck
+}
+
+// Validate implements atmos.EventValidator
+func (v *zoneValidator) Validate(engine types.Engine, event atmos.Event) bool {
+	return v.Check(engine, event) == nil
+}
+
+// Check implements atmos.ReasonedValidator
+func (v *zoneValidator) Check(engine types.Engine, event atmos.Event) error {
+	zones := v.deck.Zones(engine.(*atmos.Engine))
+	switch e := atmos.Deref(event).(type) {
+	case DrawnEvent:
+		if !v.deck.owns(e.Deck) {
+			return nil
+		}
+		if e.Count <= 0 {
+			return fmt.Errorf("must draw at least one card, got %d", e.Count)
+		}
+		if e.Count > len(zones.Draw) {
+			return fmt.Errorf("only %d cards left to draw", len(zones.Draw))
+		}
+	case DiscardedEvent:
+		if v.deck.owns(e.Deck) {
+			return requireInHand(zones, e.Player, e.Card)
+		}
+	case RevealedEvent:
+		if v.deck.owns(e.Deck) {
+			return requireInHand(zones, e.Player, e.Card)
+		}
+	}
+	return nil
+}
+
+// inHandValidator checks a card selected from any event is in a player's hand
+type inHandValidator struct {
+	deck     *Deck
+	selector func(event atmos.Event) (player, card string)
+}
+
+// Validate implements atmos.EventValidator
+func (v *inHandValidator) Validate(engine types.Engine, event atmos.Event) bool {
+	return v.Check(engine, event) == nil
+}
+
+// Check implements atmos.ReasonedValidator
+func (v *inHandValidator) Check(engine types.Engine, event atmos.Event) error {
+	player, card := v.selector(event)
+	return requireInHand(v.deck.Zones(engine.(*atmos.Engine)), player, card)
+}
+
+// requireInHand reports a card missing from a player's hand
+func requireInHand(zones Zones, player, card string) error {
+	for _, held := range zones.Hands[player] {
+		if held == card {
+			return nil
+		}
+	}
+	return fmt.Errorf("%s is not in %s's hand", card, player)
+}
+
+// withHand returns a copy of hands with one player's hand replaced
+func withHand(hands map[string][]string, player string, hand []string) map[string][]string {
+	next := make(map[string][]string, len(hands)+1)
+	for owner, cards := range hands {
+		next[owner] = cards
+	}
+	next[player] = hand
+	return next
+}
+
+// withRevealed returns a copy of revealed with the number of a player's
+// copies of a card shown set to copies
+func withRevealed(revealed map[string]map[string]int, player, card string, copies int) map[string]map[string]int {
+	next := make(map[string]map[string]int, len(revealed)+1)
+	for owner, cards := range revealed {
+		next[owner] = cards
+	}
+	cards := make(map[string]int, len(revealed[player])+1)
+	for c, n := range revealed[player] {
+		cards[c] = n
+	}
+	if copies > 0 {
+		cards[card] = copies
+	} else {
+		delete(cards, card)
+	}
+	next[player] = cards
+	return next
+}
+
+// count returns how many copies of card are in cards
+func count(cards []string, card string) int {
+	n := 0
+	for _, c := range cards {
+		if c == card {
+			n++
+		}
+	}
+	return n
+}
+
+// without returns a copy of cards with the first occurrence of card removed
+func without(cards []string, card string) []string {
+	next := make([]string, 0, len(cards))
+	removed := false
+	for _, c := range cards {
+		if c == card && !removed {
+			removed = true
+			continue
+		}
+		next = append(next, c)
+	}
+	return next
+}
@@ -0,0 +1,125 @@
+package deck_test
+
+import (
+	"math/rand"
+	"strconv"
+	"testing"
+
+	"github.com/cumulusrpg/atmos"
+	"github.com/cumulusrpg/atmos/deck"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type CardPlayedEvent struct {
+	Player string
+	Card   string
+}
+
+func (e CardPlayedEvent) Type() string { return "card_played" }
+
+// newTable installs a deck and a card_played rule on a seeded engine
+func newTable(seed int64) (*atmos.Engine, *deck.Deck) {
+	cards := deck.New()
+	engine := atmos.NewEngine()
+	engine.RegisterService(deck.RNGService, rand.New(rand.NewSource(seed)))
+	cards.Install(engine)
+	engine.When("card_played").Requires(cards.InHand(func(event atmos.Event) (string, string) {
+		played := event.(CardPlayedEvent)
+		return played.Player, played.Card
+	}))
+	return engine, cards
+}
+
+// deal creates, shuffles, and deals two cards each to alice and bob
+func deal(t *testing.T, engine *atmos.Engine, cards *deck.Deck) {
+	require.True(t, engine.Emit(deck.CreatedEvent{Cards: []string{"a", "b", "c", "d", "e", "f"}}))
+	require.True(t, cards.Shuffle(engine))
+	require.True(t, engine.Emit(deck.DrawnEvent{Player: "alice", Count: 2}))
+	require.True(t, engine.Emit(deck.DrawnEvent{Player: "bob", Count: 2}))
+}
+
+// TestDeckShufflesDeterministically verifies seeded engines and replays deal identical hands
+func TestDeckShufflesDeterministically(t *testing.T) {
+	first, cards := newTable(42)
+	deal(t, first, cards)
+	second, _ := newTable(42)
+	deal(t, second, cards)
+	assert.Equal(t, cards.Zones(first), cards.Zones(second))
+	assert.Len(t, cards.Hand(first, "alice"), 2)
+	assert.Len(t, cards.Zones(first).Draw, 2)
+
+	// Replaying the log (seeds included) reproduces the deal, whatever the RNG
+	data, err := first.MarshalEvents(first.GetEvents())
+	require.NoError(t, err)
+	events, err := first.UnmarshalEvents(data)
+	require.NoError(t, err)
+	replayed, _ := newTable(7)
+	replayed.SetEvents(events)
+	assert.Equal(t, cards.Zones(first), cards.Zones(replayed))
+
+	result := first.EmitWithResult(deck.DrawnEvent{Player: "carol", Count: 3})
+	assert.Equal(t, "only 2 cards left to draw", result.Reason)
+}
+
+// TestDeckHandsAndViews verifies hand validators, discards, reveals, and hidden zones
+func TestDeckHandsAndViews(t *testing.T) {
+	engine, cards := newTable(1)
+	deal(t, engine, cards)
+	aliceCard := cards.Hand(engine, "alice")[0]
+	bobCards := cards.Hand(engine, "bob")
+
+	assert.True(t, engine.Emit(CardPlayedEvent{Player: "alice", Card: aliceCard}))
+	played := engine.EmitWithResult(CardPlayedEvent{Player: "alice", Card: bobCards[0]})
+	assert.Equal(t, bobCards[0]+" is not in alice's hand", played.Reason)
+
+	assert.True(t, engine.Emit(deck.RevealedEvent{Player: "bob", Card: bobCards[1]}))
+	assert.True(t, engine.Emit(deck.DiscardedEvent{Player: "alice", Card: aliceCard}))
+	assert.False(t, engine.Emit(deck.DiscardedEvent{Player: "alice", Card: aliceCard}), "Already discarded")
+
+	view := cards.View(engine, "alice")
+	assert.Equal(t, 2, view.DrawCount)
+	assert.Equal(t, cards.Hand(engine, "alice"), view.Hand)
+	assert.Equal(t, map[string]int{"alice": 1, "bob": 2}, view.HandCounts)
+	assert.Equal(t, map[string][]string{"bob": {bobCards[1]}}, view.Revealed, "Only revealed cards of other hands are visible")
+	assert.Equal(t, []string{aliceCard}, view.Discard)
+}
+
+// TestDeckRevealsDuplicateCopies verifies revealing one copy of a card doesn't reveal the others or another player's
+func TestDeckRevealsDuplicateCopies(t *testing.T) {
+	engine, cards := newTable(1)
+	require.True(t, engine.Emit(deck.CreatedEvent{Cards: []string{"pawn", "pawn", "pawn", "king"}}))
+	require.True(t, engine.Emit(deck.DrawnEvent{Player: "alice", Count: 2}))
+	require.True(t, engine.Emit(deck.DrawnEvent{Player: "bob", Count: 2}))
+
+	require.True(t, engine.Emit(deck.RevealedEvent{Player: "alice", Card: "pawn"}))
+	assert.Equal(t, map[string][]string{"alice": {"pawn"}}, cards.View(engine, "bob").Revealed, "One of alice's two pawns")
+	assert.Empty(t, cards.View(engine, "alice").Revealed, "bob's pawn stays hidden")
+
+	require.True(t, engine.Emit(deck.RevealedEvent{Player: "alice", Card: "pawn"}))
+	require.True(t, engine.Emit(deck.RevealedEvent{Player: "alice", Card: "pawn"}), "Revealing again shows nothing more")
+	assert.Equal(t, map[string][]string{"alice": {"pawn", "pawn"}}, cards.View(engine, "bob").Revealed)
+
+	require.True(t, engine.Emit(deck.DiscardedEvent{Player: "alice", Card: "pawn"}))
+	assert.Equal(t, map[string][]string{"alice": {"pawn"}}, cards.View(engine, "bob").Revealed)
+}
+
+// TestDeckHidesShuffleSeeds verifies the seed, which determines the draw pile, is never served or stored in plaintext
+func TestDeckHidesShuffleSeeds(t *testing.T) {
+	keys, err := atmos.NewAESKeyService(make([]byte, 32))
+	require.NoError(t, err)
+	cards := deck.New()
+	engine := atmos.NewEngine(atmos.WithFieldEncryption(keys))
+	engine.RegisterService(deck.RNGService, rand.New(rand.NewSource(3)))
+	cards.Install(engine)
+	deal(t, engine, cards)
+
+	seed := engine.GetEvents()[1].(deck.ShuffledEvent).Seed
+	require.NotZero(t, seed)
+	assert.Equal(t, deck.ShuffledEvent{}, engine.GetEventsFor("alice")[1])
+	assert.Equal(t, cards.View(engine, "alice"), engine.GetStateFor("alice", "deck"), "The state is served as the player's view")
+
+	data, err := engine.MarshalEvents(engine.GetEvents())
+	require.NoError(t, err)
+	assert.NotContains(t, string(data), strconv.FormatInt(seed, 10))
+}
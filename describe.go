@@ -0,0 +1,78 @@
+package atmos
+
+import "sort"
+
+// EventDescription summarizes everything registered for one event type
+type EventDescription struct {
+	EventType   string   `json:"event_type"`
+	Factory     bool     `json:"factory"` // True if the type can be decoded from JSON
+	Validators  []string `json:"validators,omitempty"`
+	Exceptions  []string `json:"exceptions,omitempty"` // Exception reasons
+	Advisories  []string `json:"advisories,omitempty"`
+	BeforeHooks []string `json:"before_hooks,omitempty"`
+	Listeners   []string `json:"listeners,omitempty"`
+	States      []string `json:"states,omitempty"` // States with a reducer for the type
+	Emits       []string `json:"emits,omitempty"`  // Declared derived event types
+}
+
+// Describe returns the registration graph: one description per event type
+// with anything registered for it (including AnyEvent), sorted by type
+func (e *Engine) Describe() []EventDescription {
+	byType := make(map[string]*EventDescription)
+	describe := func(eventType string) *EventDescription {
+		d, exists := byType[eventType]
+		if !exists {
+			d = &EventDescription{EventType: eventType}
+			byType[eventType] = d
+		}
+		return d
+	}
+
+	for eventType := range e.eventFactories {
+		describe(eventType).Factory = true
+	}
+	for eventType, validators := range e.validators {
+		for _, validator := range validators {
+			describe(eventType).Validators = append(describe(eventType).Validators, validatorName(validator))
+		}
+	}
+	for eventType, exceptions := range e.exceptions {
+		for _, exception := range exceptions {
+			describe(eventType).Exceptions = append(describe(eventType).Exceptions, exception.Reason)
+		}
+	}
+	for eventType, validators := range e.advisories {
+		for _, validator := range validators {
+			describe(eventType).Advisories = append(describe(eventType).Advisories, validatorName(validator))
+		}
+	}
+	for eventType, hooks := range e.beforeHooks {
+		for _, hook := range hooks {
+			describe(eventType).BeforeHooks = append(describe(eventType).BeforeHooks, listenerName(hook))
+		}
+	}
+	for eventType, listeners := range e.listeners {
+		for _, listener := range listeners {
+			describe(eventType).Listeners = append(describe(eventType).Listeners, listenerName(listener))
+		}
+	}
+	for _, name := range e.StateNames() {
+		for eventType := range e.states[name].Reducers {
+			describe(eventType).States = append(describe(eventType).States, name)
+		}
+	}
+	for eventType, emits := range e.emits {
+		if len(emits) > 0 {
+			describe(eventType).Emits = append([]string(nil), emits...)
+		}
+	}
+
+	descriptions := make([]EventDescription, 0, len(byType))
+	for _, d := range byType {
+		descriptions = append(descriptions, *d)
+	}
+	sort.Slice(descriptions, func(i, j int) bool {
+		return descriptions[i].EventType < descriptions[j].EventType
+	})
+	return descriptions
+}
@@ -0,0 +1,41 @@
+package atmos
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// TestDescribeRegistrationGraph verifies every kind of registration is summarized per event type
+func TestDescribeRegistrationGraph(t *testing.T) {
+	audit := &typeRecorder{}
+	engine := NewEngine()
+	engine.RegisterState("over", false)
+	engine.RegisterState("moves", 0)
+	engine.ThenAll(audit)
+	engine.When("move", func() Event { return &MoveEvent{} }).
+		Requires(Policy("game_in_progress")).
+		Except(Policy("game_in_progress"), func(e *Engine, event Event) bool { return false }, "never").
+		Warns(gameInProgress{}).
+		Before(audit).
+		Updates("moves", func(e *Engine, state interface{}, event Event) interface{} { return state }).
+		Emits("game_over")
+	engine.When("game_over").
+		Updates("over", func(e *Engine, state interface{}, event Event) interface{} { return true }).
+		Updates("moves", func(e *Engine, state interface{}, event Event) interface{} { return 0 })
+
+	assert.Equal(t, []EventDescription{
+		{EventType: "*", Listeners: []string{"*atmos.typeRecorder"}},
+		{EventType: "game_over", States: []string{"moves", "over"}},
+		{
+			EventType:   "move",
+			Factory:     true,
+			Validators:  []string{"policy game_in_progress"},
+			Exceptions:  []string{"never"},
+			Advisories:  []string{"atmos.gameInProgress"},
+			BeforeHooks: []string{"*atmos.typeRecorder"},
+			States:      []string{"moves"},
+			Emits:       []string{"game_over"},
+		},
+	}, engine.Describe())
+}
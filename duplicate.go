@@ -0,0 +1,78 @@
+package atmos
+
+import (
+	"errors"
+	"fmt"
+	"reflect"
+)
+
+// ErrDuplicateRegistration is raised (as a panic) by DuplicatesError engines
+var ErrDuplicateRegistration = errors.New("duplicate registration")
+
+// DuplicatePolicy decides what happens when the same validator, hook,
+// listener, or reducer is registered twice for an event type
+type DuplicatePolicy int
+
+const (
+	DuplicatesAllow  DuplicatePolicy = iota // Register it again (the default); it runs twice
+	DuplicatesDedupe                        // Keep the first registration and ignore the repeat
+	DuplicatesError                         // Panic with ErrDuplicateRegistration while wiring
+)
+
+// Duplicate describes a repeated registration
+type Duplicate struct {
+	Kind      string // "validator", "advisory", "before hook", "listener", or "reducer"
+	EventType string
+	State     string // The state name (reducers only)
+	Name      string // Readable name of the repeated validator or listener
+}
+
+func (d Duplicate) String() string {
+	if d.Kind == "reducer" {
+		return fmt.Sprintf("reducer for %s on state %q", d.EventType, d.State)
+	}
+	return fmt.Sprintf("%s %s for %s", d.Kind, d.Name, d.EventType)
+}
+
+// WithDuplicatePolicy sets how repeated registrations are handled.
+// Validators, hooks, and listeners are duplicates when they are deeply equal
+// (the same instance, or e.g. Valid(&InHand{}) twice); validators that close
+// over functions only match themselves. A reducer is a duplicate when the
+// state already has one for the event type, since it would replace it.
+func WithDuplicatePolicy(policy DuplicatePolicy) EngineOption {
+	return func(e *Engine) {
+		e.duplicatePolicy = policy
+	}
+}
+
+// Duplicates lists the repeated registrations seen so far, whatever the policy
+func (e *Engine) Duplicates() []Duplicate {
+	return append([]Duplicate(nil), e.duplicates...)
+}
+
+// admit reports whether item should be added to an event type's registered
+// items, recording it if it repeats one of them
+func admit[T any](e *Engine, kind, eventType string, registered []T, item T, name func(T) string) bool {
+	for _, existing := range registered {
+		if reflect.DeepEqual(existing, item) {
+			return e.duplicate(Duplicate{Kind: kind, EventType: eventType, Name: name(item)})
+		}
+	}
+	return true
+}
+
+// duplicate records a repeated registration and applies the duplicate policy,
+// reporting whether the registration should go ahead
+func (e *Engine) duplicate(d Duplicate) bool {
+	if e.duplicatePolicy == DuplicatesError {
+		panic(fmt.Errorf("%w: %s", ErrDuplicateRegistration, d))
+	}
+	r := e.mutableRegistrations()
+	r.duplicates = append(r.duplicates, d)
+	return e.duplicatePolicy == DuplicatesAllow
+}
+
+// listenerName returns a readable name for a listener or hook
+func listenerName(listener EventListener) string {
+	return fmt.Sprintf("%T", listener)
+}
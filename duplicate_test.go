@@ -0,0 +1,69 @@
+package atmos
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// wireMoves registers a copy-pasted chain that repeats a validator, listener, and reducer
+func wireMoves(engine *Engine, audit *typeRecorder) {
+	engine.RegisterState("moves", 0)
+	count := func(e *Engine, state interface{}, event Event) interface{} {
+		return state.(int) + 1
+	}
+	engine.When("move").
+		Requires(Because(gameInProgress{}, "the game is over")).
+		Then(audit).
+		Updates("moves", count)
+	engine.When("move").
+		Requires(Because(gameInProgress{}, "the game is over")).
+		Then(audit).
+		Updates("moves", count)
+}
+
+// TestDuplicatesAllowedByDefault verifies repeats still register but are listed
+func TestDuplicatesAllowedByDefault(t *testing.T) {
+	audit := &typeRecorder{}
+	engine := NewEngine()
+	engine.RegisterState("over", false)
+	wireMoves(engine, audit)
+
+	assert.Len(t, engine.validators["move"], 2)
+	assert.Equal(t, []Duplicate{
+		{Kind: "validator", EventType: "move", Name: "atmos.gameInProgress"},
+		{Kind: "listener", EventType: "move", Name: "*atmos.typeRecorder"},
+		{Kind: "reducer", EventType: "move", State: "moves"},
+	}, engine.Duplicates())
+	assert.Equal(t, `reducer for move on state "moves"`, engine.Duplicates()[2].String())
+}
+
+// TestDuplicatesDedupe verifies repeats are ignored
+func TestDuplicatesDedupe(t *testing.T) {
+	audit := &typeRecorder{}
+	engine := NewEngine(WithDuplicatePolicy(DuplicatesDedupe))
+	engine.RegisterState("over", false)
+	wireMoves(engine, audit)
+
+	assert.Len(t, engine.validators["move"], 1)
+	assert.Len(t, engine.Duplicates(), 3)
+
+	assert.True(t, engine.Emit(MoveEvent{}))
+	assert.Equal(t, []string{"move"}, audit.types, "The listener ran once")
+	assert.Equal(t, 1, engine.GetState("moves"))
+}
+
+// TestDuplicatesError verifies repeats panic while wiring
+func TestDuplicatesError(t *testing.T) {
+	engine := NewEngine(WithDuplicatePolicy(DuplicatesError))
+	engine.RegisterState("over", false)
+
+	assert.PanicsWithError(t, "duplicate registration: listener *atmos.typeRecorder for move", func() {
+		audit := &typeRecorder{}
+		engine.When("move").Then(audit, audit)
+	})
+	assert.NotPanics(t, func() {
+		engine.When("pass").Then(&typeRecorder{types: []string{"other"}})
+		engine.When("pass").Then(&typeRecorder{})
+	}, "Listeners in different states are distinct")
+}
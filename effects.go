@@ -0,0 +1,126 @@
+package atmos
+
+import (
+	"context"
+	"sync"
+
+	"github.com/cumulusrpg/atmos/types"
+)
+
+// Effect is a side effect (an email, a webhook call) described as data.
+// Listeners return effects instead of performing them, so they are easy to
+// assert on in tests and never fire when effects are suppressed.
+type Effect interface {
+	Run(ctx context.Context) error
+}
+
+// EffectProducer returns the effects an event calls for
+type EffectProducer func(engine *Engine, event Event) []Effect
+
+// EffectExecutor runs the effects requested by an emit once it (and every
+// event it derived) has committed
+type EffectExecutor interface {
+	Execute(effects []Effect)
+}
+
+// EffectRunner is the default executor: it runs effects in order
+type EffectRunner struct {
+	OnError func(effect Effect, err error) // called for each failed effect, if set
+}
+
+// Execute implements EffectExecutor
+func (r EffectRunner) Execute(effects []Effect) {
+	for _, effect := range effects {
+		if err := effect.Run(context.Background()); err != nil && r.OnError != nil {
+			r.OnError(effect, err)
+		}
+	}
+}
+
+// EffectRecorder is an executor that records effects instead of running
+// them, for tests. It is safe for concurrent use.
+type EffectRecorder struct {
+	mu      sync.Mutex
+	effects []Effect
+}
+
+// Execute implements EffectExecutor
+func (r *EffectRecorder) Execute(effects []Effect) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.effects = append(r.effects, effects...)
+}
+
+// Effects returns the recorded effects in request order
+func (r *EffectRecorder) Effects() []Effect {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return append([]Effect(nil), r.effects...)
+}
+
+// WithEffectExecutor sets how requested effects are run (default EffectRunner{})
+func WithEffectExecutor(executor EffectExecutor) EngineOption {
+	return func(e *Engine) {
+		e.effectExecutor = executor
+	}
+}
+
+// Effects registers producers whose effects run after the event commits
+// (chainable). Effects are collected across the whole emit, including events
+// derived by listeners, and handed to the executor once it completes.
+// Usage: When("order_placed").Effects(sendReceipt)
+func (r *EventRegistration) Effects(producers ...EffectProducer) *EventRegistration {
+	for _, producer := range producers {
+		r.WithListener(&effectListener{producer: producer})
+	}
+	return r
+}
+
+// WithoutEffects runs fn with effects suppressed: producers still run, but
+// their effects are discarded. Effects are also suppressed in replay mode
+// (see Replay).
+func (e *Engine) WithoutEffects(fn func()) {
+	e.suppressEffects++
+	defer func() { e.suppressEffects-- }()
+	fn()
+}
+
+// effectListener queues a producer's effects
+type effectListener struct {
+	producer EffectProducer
+}
+
+// Handle implements EventListener
+func (l *effectListener) Handle(engine types.Engine, event Event) {
+	e := engine.(*Engine)
+	effects := l.producer(e, event)
+	if e.suppressEffects == 0 && !e.Replaying() {
+		e.pendingEffects = append(e.pendingEffects, effects...)
+	}
+}
+
+// settle finishes an emit; once the outermost emit completes, it autosaves
+// if due and hands queued effects to the executor
+func (e *Engine) settle() {
+	e.depth--
+	if e.depth > 0 {
+		return
+	}
+
+	if e.autosave != nil {
+		e.autosave.settled(e)
+	}
+	if e.checkpointEvery > 0 && e.uncheckpointed >= e.checkpointEvery {
+		_ = e.Checkpoint() // Retried after the next commit
+	}
+
+	if len(e.pendingEffects) > 0 {
+		effects := e.pendingEffects
+		e.pendingEffects = nil
+		executor := e.effectExecutor
+		if executor == nil {
+			executor = EffectRunner{}
+		}
+		executor.Execute(effects)
+	}
+}
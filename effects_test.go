@@ -0,0 +1,92 @@
+package atmos
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// SendReceipt is an effect that emails a receipt
+type SendReceipt struct {
+	OrderID string
+	sent    *[]string
+}
+
+func (s SendReceipt) Run(ctx context.Context) error {
+	if s.OrderID == "" {
+		return errors.New("no order")
+	}
+	*s.sent = append(*s.sent, s.OrderID)
+	return nil
+}
+
+// newEffectsEngine sends a receipt for every order and derives an invoice
+func newEffectsEngine(sent *[]string, opts ...EngineOption) *Engine {
+	engine := NewEngine(opts...)
+	engine.When("order_placed").
+		Effects(func(e *Engine, event Event) []Effect {
+			return []Effect{SendReceipt{OrderID: event.(OrderPlacedEvent).OrderID, sent: sent}}
+		}).
+		Then(NewTypedListener(TypedListenerFunc[OrderPlacedEvent](func(e *Engine, event OrderPlacedEvent) {
+			e.Emit(InvoiceGeneratedEvent{OrderID: event.OrderID})
+		})))
+	engine.When("invoice_generated").
+		Effects(func(e *Engine, event Event) []Effect {
+			// Effects run after the whole emit, so the invoice is already committed
+			return []Effect{SendReceipt{OrderID: "invoice " + event.(InvoiceGeneratedEvent).OrderID, sent: sent}}
+		})
+	return engine
+}
+
+// TestEffectsRunAfterCommit verifies effects run once the emit and its derived events commit
+func TestEffectsRunAfterCommit(t *testing.T) {
+	var sent []string
+	engine := newEffectsEngine(&sent)
+
+	require.True(t, engine.Emit(OrderPlacedEvent{OrderID: "ORD-1"}))
+	assert.Equal(t, []string{"ORD-1", "invoice ORD-1"}, sent)
+}
+
+// TestEffectsCanBeRecorded verifies tests can assert on effects without running them
+func TestEffectsCanBeRecorded(t *testing.T) {
+	var sent []string
+	recorder := &EffectRecorder{}
+	engine := newEffectsEngine(&sent, WithEffectExecutor(recorder))
+
+	engine.Emit(OrderPlacedEvent{OrderID: "ORD-1"})
+	assert.Empty(t, sent)
+	require.Len(t, recorder.Effects(), 2)
+	assert.Equal(t, "ORD-1", recorder.Effects()[0].(SendReceipt).OrderID)
+}
+
+// TestWithoutEffectsSuppresses verifies rebuilding from a log doesn't re-fire effects
+func TestWithoutEffectsSuppresses(t *testing.T) {
+	var sent []string
+	engine := newEffectsEngine(&sent)
+
+	engine.WithoutEffects(func() {
+		engine.Emit(OrderPlacedEvent{OrderID: "ORD-1"})
+	})
+	assert.Empty(t, sent)
+	assert.Len(t, engine.GetEvents(), 2, "Events still commit")
+
+	engine.Emit(OrderPlacedEvent{OrderID: "ORD-2"})
+	assert.Equal(t, []string{"ORD-2", "invoice ORD-2"}, sent)
+}
+
+// TestEffectErrorsAreReported verifies the runner reports failed effects
+func TestEffectErrorsAreReported(t *testing.T) {
+	var sent []string
+	var failed []error
+	engine := newEffectsEngine(&sent, WithEffectExecutor(EffectRunner{OnError: func(effect Effect, err error) {
+		failed = append(failed, err)
+	}}))
+
+	assert.True(t, engine.Emit(OrderPlacedEvent{}), "Effects never reject the emit")
+	assert.Equal(t, []string{"invoice "}, sent)
+	require.Len(t, failed, 1)
+	assert.EqualError(t, failed[0], "no order")
+}
@@ -0,0 +1,85 @@
+package atmos
+
+import "context"
+
+// Request-scoped values commonly carried by EmitCtx contexts. Any other
+// type can be carried too (see WithValue).
+type (
+	// Actor is the player or session emitting; EmitCtx also makes it the
+	// actor for actor policies (see EmitAs)
+	Actor string
+
+	// Locale is the caller's language, e.g. "fr-CA"
+	Locale string
+
+	// TraceID ties the emit to a distributed trace or request log
+	TraceID string
+
+	// Flags are the feature flags enabled for the caller
+	Flags map[string]bool
+)
+
+// Enabled reports whether a feature flag is on
+func (f Flags) Enabled(flag string) bool {
+	return f[flag]
+}
+
+// contextKey keys request-scoped values by their type
+type contextKey[T any] struct{}
+
+// WithValue returns a copy of ctx carrying value, keyed by its type, for
+// validators and listeners to read with Value during EmitCtx.
+// Usage: ctx = atmos.WithValue(ctx, atmos.Locale("fr"))
+func WithValue[T any](ctx context.Context, value T) context.Context {
+	return context.WithValue(ctx, contextKey[T]{}, value)
+}
+
+// Value returns the value of type T carried by the context of the emit in
+// progress (see EmitCtx), reporting whether there is one.
+// Usage: if locale, ok := atmos.Value[atmos.Locale](engine); ok { ... }
+func Value[T any](engine *Engine) (T, bool) {
+	return ContextValue[T](engine.Context())
+}
+
+// ContextValue returns the value of type T carried by ctx (see WithValue)
+func ContextValue[T any](ctx context.Context) (T, bool) {
+	value, ok := ctx.Value(contextKey[T]{}).(T)
+	return value, ok
+}
+
+// EmitCtx emits an event with a request-scoped context, so rules can vary
+// by caller (locale, feature flags, trace ID) without those transport
+// concerns in event payloads. The context is visible to validators, hooks,
+// and listeners via Context and Value for the duration of the emit,
+// including events emitted by those listeners. An Actor value in ctx
+// becomes the emit's MetadataActor, and so its actor (see
+// EmitWithMetadata). A ctx that is already done fails the emit with its
+// error before anything runs.
+func (e *Engine) EmitCtx(ctx context.Context, event Event) EmitResult {
+	if err := ctx.Err(); err != nil {
+		return EmitResult{Err: err}
+	}
+	previous := e.ctx
+	e.ctx = ctx
+	defer func() { e.ctx = previous }()
+
+	actor, ok := ContextValue[Actor](ctx)
+	if !ok {
+		return e.EmitWithResult(event)
+	}
+	metadata := make(Metadata, len(e.metadata)+1)
+	for key, value := range e.metadata {
+		metadata[key] = value
+	}
+	metadata[MetadataActor] = string(actor)
+	return e.EmitWithMetadata(metadata, event)
+}
+
+// Context returns the context of the emit in progress, or
+// context.Background() outside of EmitCtx
+func (e *Engine) Context() context.Context {
+	if e.ctx == nil {
+		return context.Background()
+	}
+	return e.ctx
+}
@@ -0,0 +1,71 @@
+package atmos
+
+import (
+	"context"
+	"testing"
+
+	"github.com/cumulusrpg/atmos/repository"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// TestEmitCtxCarriesRequestValues verifies validators and listeners read typed values from the emit's context
+func TestEmitCtxCarriesRequestValues(t *testing.T) {
+	engine := NewEngine()
+	engine.When("move").
+		Requires(NewTypedValidator(TypedValidatorFunc[MoveEvent](func(e *Engine, event MoveEvent) bool {
+			flags, _ := Value[Flags](e)
+			return flags.Enabled("diagonal_moves")
+		}))).
+		AllowedBy("players move for themselves", func(e *Engine, actor string, event Event) bool {
+			return actor == event.(MoveEvent).Player
+		})
+
+	var seen []string
+	engine.When("move").Then(NewTypedListener(TypedListenerFunc[MoveEvent](func(e *Engine, event MoveEvent) {
+		locale, _ := Value[Locale](e)
+		trace, _ := Value[TraceID](e)
+		seen = append(seen, string(locale)+" "+string(trace))
+	})))
+
+	ctx := WithValue(context.Background(), Actor("alice"))
+	ctx = WithValue(ctx, Locale("fr"))
+	ctx = WithValue(ctx, TraceID("t-1"))
+	assert.False(t, engine.EmitCtx(ctx, MoveEvent{Player: "alice"}).Accepted, "The flag is off")
+
+	ctx = WithValue(ctx, Flags{"diagonal_moves": true})
+	assert.True(t, engine.EmitCtx(ctx, MoveEvent{Player: "alice"}).Accepted)
+	assert.Equal(t, "policy: players move for themselves", engine.EmitCtx(ctx, MoveEvent{Player: "bob"}).RejectedBy)
+	assert.Equal(t, []string{"fr t-1"}, seen)
+
+	assert.Equal(t, context.Background(), engine.Context(), "The context only lasts for the emit")
+	assert.Empty(t, engine.Actor())
+	_, ok := Value[Locale](engine)
+	assert.False(t, ok)
+}
+
+// TestEmitCtxRefusesDoneContexts verifies a cancelled request emits nothing
+func TestEmitCtxRefusesDoneContexts(t *testing.T) {
+	engine := NewEngine()
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	result := engine.EmitCtx(ctx, TestEvent{Name: "late"})
+	assert.ErrorIs(t, result.Err, context.Canceled)
+	assert.Empty(t, engine.GetEvents())
+}
+
+// TestEmitCtxActorIsMetadata verifies the context's actor reaches the emit's metadata, and the envelope
+func TestEmitCtxActorIsMetadata(t *testing.T) {
+	engine := NewEngine(WithRepository(repository.NewIdentified()))
+	var seen Metadata
+	engine.When("test_event").Then(NewTypedListener(TypedListenerFunc[TestEvent](func(e *Engine, event TestEvent) {
+		seen = e.Metadata()
+	})))
+
+	ctx := WithValue(context.Background(), Actor("alice"))
+	require.True(t, engine.EmitCtx(ctx, TestEvent{Name: "a"}).Accepted)
+	assert.Equal(t, Metadata{MetadataActor: "alice"}, seen)
+	assert.Equal(t, map[string]string{MetadataActor: "alice"}, engine.Envelopes()[0].Metadata)
+	assert.Nil(t, engine.Metadata())
+}
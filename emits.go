@@ -0,0 +1,50 @@
+package atmos
+
+import (
+	"errors"
+	"fmt"
+)
+
+// ErrUndeclaredEmit is returned when a hook or listener emits an event type its
+// registration didn't declare with Emits (only on WithDeclaredEmits engines)
+var ErrUndeclaredEmit = errors.New("undeclared emit")
+
+// Emits declares the event types this event's hooks and listeners may emit,
+// documenting derived flows (chainable). On engines created with
+// WithDeclaredEmits, emitting anything else from them fails.
+// Usage: When("order_placed").Then(Do(&GenerateInvoice{})).Emits("invoice_generated")
+func (r *EventRegistration) Emits(eventTypes ...string) *EventRegistration {
+	reg := r.engine.mutableRegistrations()
+	reg.emits[r.eventType] = append(reg.emits[r.eventType], eventTypes...)
+	return r
+}
+
+// DeclaredEmits returns the event types declared with Emits for an event type
+func (e *Engine) DeclaredEmits(eventType string) []string {
+	return append([]string(nil), e.emits[eventType]...)
+}
+
+// WithDeclaredEmits enforces Emits declarations: an event emitted while the
+// hooks or listeners of another event are running is rejected with
+// ErrUndeclaredEmit unless that event (or AnyEvent) declared its type
+func WithDeclaredEmits() EngineOption {
+	return func(e *Engine) {
+		e.enforceEmits = true
+	}
+}
+
+// checkDeclared verifies the event in progress declared the event being emitted
+func (e *Engine) checkDeclared(event Event) error {
+	if len(e.emitting) == 0 {
+		return nil
+	}
+	parent := e.emitting[len(e.emitting)-1]
+	for _, declared := range [][]string{e.emits[parent], e.emits[AnyEvent]} {
+		for _, eventType := range declared {
+			if eventType == event.Type() {
+				return nil
+			}
+		}
+	}
+	return fmt.Errorf("%w: %s emitted %s", ErrUndeclaredEmit, parent, event.Type())
+}
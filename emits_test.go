@@ -0,0 +1,47 @@
+package atmos
+
+import (
+	"testing"
+
+	"github.com/cumulusrpg/atmos/types"
+	"github.com/stretchr/testify/assert"
+)
+
+// emitter emits a fixed event from a listener, recording the result
+type emitter struct {
+	event  Event
+	result EmitResult
+}
+
+func (l *emitter) Handle(engine types.Engine, event Event) {
+	l.result = engine.(*Engine).EmitWithResult(l.event)
+}
+
+// TestDeclaredEmitsEnforced verifies listeners may only emit declared event types
+func TestDeclaredEmitsEnforced(t *testing.T) {
+	declared := &emitter{event: PassEvent{Player: "bob"}}
+	undeclared := &emitter{event: GameOverEvent{}}
+
+	engine := NewEngine(WithDeclaredEmits())
+	engine.When("move").Then(declared).Emits("pass")
+	engine.When("pass").Then(undeclared)
+
+	assert.Equal(t, []string{"pass"}, engine.DeclaredEmits("move"))
+	assert.True(t, engine.Emit(MoveEvent{Player: "alice"}))
+	assert.True(t, declared.result.Accepted)
+	assert.ErrorIs(t, undeclared.result.Err, ErrUndeclaredEmit)
+	assert.EqualError(t, undeclared.result.Err, "undeclared emit: pass emitted game_over")
+	assert.Len(t, engine.GetEvents(), 2)
+
+	assert.True(t, engine.Emit(GameOverEvent{}), "Top-level emits are never restricted")
+}
+
+// TestDeclaredEmitsDocumentOnly verifies declarations aren't enforced by default
+func TestDeclaredEmitsDocumentOnly(t *testing.T) {
+	listener := &emitter{event: GameOverEvent{}}
+	engine := NewEngine()
+	engine.When("move").Then(listener).Emits("pass")
+
+	assert.True(t, engine.Emit(MoveEvent{}))
+	assert.True(t, listener.result.Accepted)
+}
@@ -0,0 +1,171 @@
+package atmos
+
+import (
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"reflect"
+	"strings"
+)
+
+// encryptedPrefix marks an encrypted field value in serialized events
+const encryptedPrefix = "enc:"
+
+// KeyService encrypts and decrypts sensitive event fields
+type KeyService interface {
+	Encrypt(plaintext []byte) ([]byte, error)
+	Decrypt(ciphertext []byte) ([]byte, error)
+}
+
+// WithFieldEncryption encrypts event fields tagged `atmos:"encrypt"` when
+// events are serialized (MarshalEvents, MarshalEvent, MarshalEnvelopes) and
+// decrypts them when they are decoded, so chat messages or emails are never
+// stored in plaintext:
+//
+//	type ChatSentEvent struct {
+//		Player  string `json:"player"`
+//		Message string `json:"message" atmos:"encrypt"`
+//	}
+//
+// Any field type can be encrypted; the field is stored as an opaque string.
+// Only fields of the event struct itself (including embedded structs) are
+// considered. Values stored before encryption was enabled still decode.
+func WithFieldEncryption(keys KeyService) EngineOption {
+	return func(e *Engine) {
+		e.keys = keys
+	}
+}
+
+// NewAESKeyService returns a KeyService using AES-GCM with a random nonce
+// per value. The key must be 16, 24 or 32 bytes long.
+func NewAESKeyService(key []byte) (KeyService, error) {
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return nil, err
+	}
+	gcm, err := cipher.NewGCM(block)
+	if err != nil {
+		return nil, err
+	}
+	return aesKeyService{gcm: gcm}, nil
+}
+
+// aesKeyService prefixes each ciphertext with its nonce
+type aesKeyService struct {
+	gcm cipher.AEAD
+}
+
+func (s aesKeyService) Encrypt(plaintext []byte) ([]byte, error) {
+	nonce := make([]byte, s.gcm.NonceSize())
+	if _, err := io.ReadFull(rand.Reader, nonce); err != nil {
+		return nil, err
+	}
+	return s.gcm.Seal(nonce, nonce, plaintext, nil), nil
+}
+
+func (s aesKeyService) Decrypt(ciphertext []byte) ([]byte, error) {
+	if len(ciphertext) < s.gcm.NonceSize() {
+		return nil, errors.New("ciphertext too short")
+	}
+	nonce, sealed := ciphertext[:s.gcm.NonceSize()], ciphertext[s.gcm.NonceSize():]
+	return s.gcm.Open(nil, nonce, sealed, nil)
+}
+
+// encryptData returns an event's payload for serialization, with tagged
+// fields encrypted when field encryption is enabled
+func (e *Engine) encryptData(event Event) (interface{}, error) {
+	fields := encryptedFields(reflect.TypeOf(event))
+	if e.keys == nil || len(fields) == 0 {
+		return event, nil
+	}
+
+	data, err := json.Marshal(event)
+	if err != nil {
+		return nil, err
+	}
+	var object map[string]json.RawMessage
+	if err := json.Unmarshal(data, &object); err != nil {
+		return nil, err
+	}
+	for _, name := range fields {
+		plaintext, present := object[name]
+		if !present {
+			continue
+		}
+		ciphertext, err := e.keys.Encrypt(plaintext)
+		if err != nil {
+			return nil, fmt.Errorf("encrypting %s.%s: %w", event.Type(), name, err)
+		}
+		object[name], _ = json.Marshal(encryptedPrefix + base64.StdEncoding.EncodeToString(ciphertext))
+	}
+	return object, nil
+}
+
+// decryptData reverses encryptData for a payload about to be decoded into event
+func (e *Engine) decryptData(event Event, data json.RawMessage) (json.RawMessage, error) {
+	fields := encryptedFields(reflect.TypeOf(event))
+	if e.keys == nil || len(fields) == 0 || len(data) == 0 {
+		return data, nil
+	}
+
+	var object map[string]json.RawMessage
+	if err := json.Unmarshal(data, &object); err != nil {
+		return data, nil // Let decoding into the event report it
+	}
+	for _, name := range fields {
+		var stored string
+		if json.Unmarshal(object[name], &stored) != nil || !strings.HasPrefix(stored, encryptedPrefix) {
+			continue // Absent, or written before encryption was enabled
+		}
+		ciphertext, err := base64.StdEncoding.DecodeString(strings.TrimPrefix(stored, encryptedPrefix))
+		if err != nil {
+			return nil, fmt.Errorf("decrypting %s.%s: %w", event.Type(), name, err)
+		}
+		plaintext, err := e.keys.Decrypt(ciphertext)
+		if err != nil {
+			return nil, fmt.Errorf("decrypting %s.%s: %w", event.Type(), name, err)
+		}
+		object[name] = plaintext
+	}
+	return json.Marshal(object)
+}
+
+// encryptedFields returns the JSON names of a struct type's fields tagged
+// `atmos:"encrypt"`
+func encryptedFields(t reflect.Type) []string {
+	for t != nil && t.Kind() == reflect.Ptr {
+		t = t.Elem()
+	}
+	if t == nil || t.Kind() != reflect.Struct {
+		return nil
+	}
+
+	var names []string
+	for i := 0; i < t.NumField(); i++ {
+		field := t.Field(i)
+		if field.Anonymous && field.Tag.Get("json") == "" {
+			names = append(names, encryptedFields(field.Type)...)
+			continue
+		}
+		if !field.IsExported() || !hasTagOption(field.Tag.Get("atmos"), "encrypt") {
+			continue
+		}
+		names = append(names, payloadFieldName(field))
+	}
+	return names
+}
+
+// hasTagOption reports whether a comma-separated tag contains an option
+func hasTagOption(tag, option string) bool {
+	for _, part := range strings.Split(tag, ",") {
+		if strings.TrimSpace(part) == option {
+			return true
+		}
+	}
+	return false
+}
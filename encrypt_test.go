@@ -0,0 +1,94 @@
+package atmos
+
+import (
+	"bytes"
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type ChatSentEvent struct {
+	Player  string   `json:"player"`
+	Message string   `json:"message" atmos:"encrypt"`
+	Emails  []string `atmos:"encrypt"`
+}
+
+func (e ChatSentEvent) Type() string { return "chat_sent" }
+
+// newEncryptingEngine encrypts chat with a fixed test key
+func newEncryptingEngine(t *testing.T) *Engine {
+	keys, err := NewAESKeyService(bytes.Repeat([]byte{7}, 32))
+	require.NoError(t, err)
+	engine := NewEngine(WithFieldEncryption(keys))
+	engine.RegisterEventTypes(ChatSentEvent{})
+	return engine
+}
+
+// TestFieldEncryptionRoundTrip verifies tagged fields are stored encrypted and decoded in plaintext
+func TestFieldEncryptionRoundTrip(t *testing.T) {
+	engine := newEncryptingEngine(t)
+	chat := ChatSentEvent{Player: "alice", Message: "meet at the docks", Emails: []string{"a@example.com"}}
+
+	data, err := engine.MarshalEvents([]Event{chat})
+	require.NoError(t, err)
+	assert.Contains(t, string(data), `"player":"alice"`, "Untagged fields stay readable")
+	assert.NotContains(t, string(data), "docks")
+	assert.NotContains(t, string(data), "example.com")
+	assert.Contains(t, string(data), `"message":"enc:`)
+
+	events, err := engine.UnmarshalEvents(data)
+	require.NoError(t, err)
+	assert.Equal(t, []Event{&chat}, events)
+
+	single, err := engine.MarshalEvent(chat)
+	require.NoError(t, err)
+	assert.NotContains(t, string(single), "docks")
+	event, err := engine.UnmarshalEvent(single)
+	require.NoError(t, err)
+	assert.Equal(t, &chat, event)
+}
+
+// TestFieldEncryptionWrongKey verifies records encrypted with another key don't decode
+func TestFieldEncryptionWrongKey(t *testing.T) {
+	data, err := newEncryptingEngine(t).MarshalEvent(ChatSentEvent{Message: "secret"})
+	require.NoError(t, err)
+
+	otherKeys, err := NewAESKeyService(bytes.Repeat([]byte{8}, 32))
+	require.NoError(t, err)
+	other := NewEngine(WithFieldEncryption(otherKeys))
+	other.RegisterEventTypes(ChatSentEvent{})
+
+	_, err = other.UnmarshalEvent(data)
+	assert.ErrorContains(t, err, "decrypting chat_sent.message")
+}
+
+// TestFieldEncryptionReadsPlaintextLogs verifies logs written before encryption was enabled still load
+func TestFieldEncryptionReadsPlaintextLogs(t *testing.T) {
+	plain := NewEngine()
+	data, err := plain.MarshalEvent(ChatSentEvent{Player: "bob", Message: "hi"})
+	require.NoError(t, err)
+
+	event, err := newEncryptingEngine(t).UnmarshalEvent(data)
+	require.NoError(t, err)
+	assert.Equal(t, &ChatSentEvent{Player: "bob", Message: "hi"}, event)
+}
+
+// failingKeys can't encrypt anything
+type failingKeys struct{}
+
+func (failingKeys) Encrypt([]byte) ([]byte, error) { return nil, errors.New("key service down") }
+func (failingKeys) Decrypt([]byte) ([]byte, error) { return nil, errors.New("key service down") }
+
+// TestFieldEncryptionFailureStopsMarshaling verifies a failing key service never leaks plaintext
+func TestFieldEncryptionFailureStopsMarshaling(t *testing.T) {
+	engine := NewEngine(WithFieldEncryption(failingKeys{}))
+
+	_, err := engine.MarshalEvents([]Event{ChatSentEvent{Message: "secret"}, TestEvent{}})
+	assert.ErrorContains(t, err, "key service down")
+
+	data, err := engine.MarshalEvents([]Event{TestEvent{Name: "x"}})
+	require.NoError(t, err, "Events without encrypted fields don't need the key service")
+	assert.JSONEq(t, `[{"type":"test_event","data":{"Name":"x"}}]`, string(data))
+}
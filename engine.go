@@ -1,14 +1,21 @@
 package atmos
 
 import (
+	"context"
 	"encoding/json"
 	"errors"
+	"fmt"
 	"reflect"
 
 	"github.com/cumulusrpg/atmos/repository"
 	"github.com/cumulusrpg/atmos/types"
 )
 
+// AnyEvent is a wildcard event type: validators registered for it check,
+// and listeners registered for it run after, every event whatever its type.
+// Usage: When(AnyEvent).Then(auditLogger)
+const AnyEvent = "*"
+
 // StateReducer represents a function that reduces an event into a state
 type StateReducer func(engine *Engine, state interface{}, event Event) interface{}
 
@@ -18,9 +25,10 @@ type StateRegistry struct {
 	Reducers     map[string]StateReducer // event type -> reducer function
 }
 
-// Engine coordinates event emission, validation, and commitment
-type Engine struct {
-	repository     types.EventRepository           // event storage abstraction
+// registrations holds everything configured on an engine via Register*/When.
+// Tables may be shared between engines stamped from a Blueprint and are
+// copied before the first write (see mutableRegistrations).
+type registrations struct {
 	validators     map[string][]EventValidator     // event type -> validators
 	exceptions     map[string][]ValidatorException // event type -> validator exceptions
 	beforeHooks    map[string][]EventListener      // event type -> pre-commit hooks
@@ -28,6 +36,158 @@ type Engine struct {
 	states         map[string]StateRegistry        // state name -> state registry
 	eventFactories map[string]func() Event         // event type -> factory function
 	services       map[string]interface{}          // service name -> service instance (service locator)
+	invariants     []namedInvariant                // properties checked after each commit
+	advisories     map[string][]EventValidator     // event type -> non-blocking validators
+	policies       map[string]EventValidator       // policy name -> shared validator
+	duplicates     []Duplicate                     // repeated registrations (see Duplicates)
+	emits          map[string][]string             // event type -> declared derived event types
+	stateMasks     map[string]StateMask            // state name -> per-viewer visibility rule
+	eventMasks     map[string][]EventMask          // event type -> per-viewer visibility rules
+	loadHooks      []func(*Engine)                 // run after SetEvents (see OnLoad)
+	changeHooks    []func(*Engine)                 // run by Refresh after external commits (see OnExternalChange)
+	startHooks     []LifecycleHook                 // run by Start
+	shutdownHooks  []LifecycleHook                 // run by Close, in reverse
+
+	reducerMiddleware map[string][]ReducerMiddleware // state name -> wrappers for its reducers, innermost first
+	selectors         map[string]selector            // selector name -> derived view (see RegisterSelector)
+}
+
+// newRegistrations creates empty registration tables
+func newRegistrations() *registrations {
+	return &registrations{
+		validators:     make(map[string][]EventValidator),
+		exceptions:     make(map[string][]ValidatorException),
+		beforeHooks:    make(map[string][]EventListener),
+		listeners:      make(map[string][]EventListener),
+		states:         make(map[string]StateRegistry),
+		eventFactories: make(map[string]func() Event),
+		services:       make(map[string]interface{}),
+		advisories:     make(map[string][]EventValidator),
+		policies:       make(map[string]EventValidator),
+		emits:          make(map[string][]string),
+		stateMasks:     make(map[string]StateMask),
+		eventMasks:     make(map[string][]EventMask),
+
+		reducerMiddleware: make(map[string][]ReducerMiddleware),
+		selectors:         make(map[string]selector),
+	}
+}
+
+// clone copies the registration tables so they can be modified independently
+func (r *registrations) clone() *registrations {
+	c := newRegistrations()
+	for k, v := range r.validators {
+		c.validators[k] = append([]EventValidator(nil), v...)
+	}
+	for k, v := range r.exceptions {
+		c.exceptions[k] = append([]ValidatorException(nil), v...)
+	}
+	for k, v := range r.beforeHooks {
+		c.beforeHooks[k] = append([]EventListener(nil), v...)
+	}
+	for k, v := range r.listeners {
+		c.listeners[k] = append([]EventListener(nil), v...)
+	}
+	for k, v := range r.states {
+		reducers := make(map[string]StateReducer, len(v.Reducers))
+		for eventType, reducer := range v.Reducers {
+			reducers[eventType] = reducer
+		}
+		c.states[k] = StateRegistry{InitialState: v.InitialState, Reducers: reducers}
+	}
+	for k, v := range r.eventFactories {
+		c.eventFactories[k] = v
+	}
+	for k, v := range r.services {
+		c.services[k] = v
+	}
+	for k, v := range r.advisories {
+		c.advisories[k] = append([]EventValidator(nil), v...)
+	}
+	for k, v := range r.stateMasks {
+		c.stateMasks[k] = v
+	}
+	for k, v := range r.eventMasks {
+		c.eventMasks[k] = append([]EventMask(nil), v...)
+	}
+	for k, v := range r.emits {
+		c.emits[k] = append([]string(nil), v...)
+	}
+	for k, v := range r.policies {
+		c.policies[k] = v
+	}
+	c.invariants = append([]namedInvariant(nil), r.invariants...)
+	c.duplicates = append([]Duplicate(nil), r.duplicates...)
+	for k, v := range r.reducerMiddleware {
+		c.reducerMiddleware[k] = append([]ReducerMiddleware(nil), v...)
+	}
+	for k, v := range r.selectors {
+		c.selectors[k] = v
+	}
+	c.loadHooks = append(([]func(*Engine))(nil), r.loadHooks...)
+	c.changeHooks = append(([]func(*Engine))(nil), r.changeHooks...)
+	c.startHooks = append([]LifecycleHook(nil), r.startHooks...)
+	c.shutdownHooks = append([]LifecycleHook(nil), r.shutdownHooks...)
+	return c
+}
+
+// Engine coordinates event emission, validation, and commitment
+type Engine struct {
+	*registrations
+	sharedRegistrations bool                  // registrations are shared with a Blueprint (copy on write)
+	repository          types.EventRepository // event storage abstraction
+	actor               string                // actor of the emit in progress (see EmitAs)
+	invariantChecks     bool                  // check invariants after each Emit (see WithInvariantChecks)
+	autoEventTypes      bool                  // register AutoEvent types on first Emit (see WithAutoEventTypes)
+	autoNamingErr       error                 // the engine's naming convention conflicts with the one in use
+	strictEvents        bool                  // reject unregistered event types (see WithStrictEvents)
+	strictPanic         bool                  // panic instead of rejecting in strict mode
+	strictRegistration  bool                  // panic on misconfigured registrations (see WithStrictRegistration)
+	duplicatePolicy     DuplicatePolicy       // handling of repeated registrations (see WithDuplicatePolicy)
+	enforceEmits        bool                  // reject undeclared derived events (see WithDeclaredEmits)
+	emitting            []string              // types of the events whose hooks and listeners are running
+	warnings            []Warning             // warnings of the event being committed (see Warnings)
+	autosave            *autosaver            // durable copy of the log (see WithAutosave)
+	depth               int                   // nesting of emits past validation (see settle)
+	effectExecutor      EffectExecutor        // runs queued effects (see WithEffectExecutor)
+	pendingEffects      []Effect              // effects requested during the emit in progress
+	suppressEffects     int                   // effects are discarded while positive (see WithoutEffects)
+	replaying           int                   // replay mode while positive (see Replaying)
+	quarantining        bool                  // keep undecodable records (see WithQuarantine)
+	rawEvents           bool                  // decode unknown types as RawEvent (see WithRawEvents)
+	started             bool                  // start hooks have run (see Start)
+	closed              bool                  // emits are refused (see Close)
+	async               asyncWork             // goroutines Shutdown waits for (see Go)
+	quarantined         []QuarantinedRecord   // undecodable records of the loaded log
+	lastDecoded         decodedLog            // the last decode's records, until its events are loaded
+	revision            uint64                // bumped by each change to the log, snapshots or registrations
+	selections          map[string]selection  // memoized selector results (see Select)
+	onViolation         func(*InvariantViolation)
+	metaListeners       []EventListener  // receive meta-events (see WithMetaEvents)
+	payloadValidation   bool             // check validate struct tags first (see WithPayloadValidation)
+	generateID          func() string    // assigns event IDs at commit (see WithEventIDs)
+	metadata            Metadata         // metadata of the emit in progress (see EmitWithMetadata)
+	keys                KeyService       // encrypts tagged fields when serializing (see WithFieldEncryption)
+	schemaVersion       int              // game schema version stamped into exports (see WithSchemaVersion)
+	readOnly            bool             // emits are refused (see WithReadOnly)
+	breadthFirst        bool             // queue events derived by listeners (see WithBreadthFirstEmits)
+	listening           int              // nesting of listener runs for committed events
+	derived             []derivedEmit    // events derived by listeners, waiting in breadth-first mode
+	draining            bool             // derived events are being processed
+	ctx                 context.Context  // context of the emit in progress (see EmitCtx)
+	external            *externalChanges // commits by other writers to a shared repository (see Refresh)
+	paused              *pause           // emits are held (see Pause)
+	messages            *Catalog         // renders Message reasons (see WithMessages)
+	committed           []Event          // events committed during the outermost emit in progress
+	transactions        int              // nesting of emits collecting committed events
+	trace               *Trace           // records pipeline timings (see WithTracing)
+	profilerLabels      bool             // label profile samples by pipeline step (see WithProfilerLabels)
+	labels              context.Context  // pprof labels of the step in progress
+	checkpointEvery     int              // commits between state checkpoints (see WithCheckpoints)
+	uncheckpointed      int              // commits since the last checkpoint
+	payloadLimit        int              // largest event payload in bytes, 0 for none (see WithMaxPayloadSize)
+	oversized           OversizedPolicy  // what happens to larger payloads
+	blobs               types.BlobStore  // where Blob fields are checked in (see WithBlobStore)
 }
 
 // EngineOption configures engine construction
@@ -43,14 +203,8 @@ func WithRepository(repo types.EventRepository) EngineOption {
 // NewEngine creates a new engine with optional configuration
 func NewEngine(opts ...EngineOption) *Engine {
 	engine := &Engine{
-		repository:     repository.NewInMemory(), // default repository
-		validators:     make(map[string][]EventValidator),
-		exceptions:     make(map[string][]ValidatorException),
-		beforeHooks:    make(map[string][]EventListener),
-		listeners:      make(map[string][]EventListener),
-		states:         make(map[string]StateRegistry),
-		eventFactories: make(map[string]func() Event),
-		services:       make(map[string]interface{}),
+		registrations: newRegistrations(),
+		repository:    repository.NewInMemory(), // default repository
 	}
 
 	// Apply options
@@ -58,38 +212,116 @@ func NewEngine(opts ...EngineOption) *Engine {
 		opt(engine)
 	}
 
+	engine.watchRepository()
 	return engine
 }
 
+// mutableRegistrations returns registration tables safe to modify,
+// copying them first if they are still shared with a Blueprint
+func (e *Engine) mutableRegistrations() *registrations {
+	e.revision++
+	if e.sharedRegistrations {
+		e.registrations = e.registrations.clone()
+		e.sharedRegistrations = false
+	}
+	return e.registrations
+}
+
 // RegisterValidator registers a validator for a specific event type
 func (e *Engine) RegisterValidator(eventType string, validator EventValidator) {
+	e.mutableRegistrations()
+	if !admit(e, "validator", eventType, e.validators[eventType], validator, validatorName) {
+		return
+	}
 	e.validators[eventType] = append(e.validators[eventType], validator)
 }
 
 // RegisterException registers an exception to skip a validator under certain conditions
 func (e *Engine) RegisterException(eventType string, exception ValidatorException) {
+	e.mutableRegistrations()
+	if e.strictRegistration && !e.hasValidator(eventType, exception.Validator) {
+		e.misconfigured("exception %q for %s skips %s, which is not registered for it",
+			exception.Reason, eventType, validatorName(exception.Validator))
+	}
 	e.exceptions[eventType] = append(e.exceptions[eventType], exception)
 }
 
 // RegisterBeforeHook registers a pre-commit hook for a specific event type
 // Before hooks run after validation but before the event is committed to the event log
 func (e *Engine) RegisterBeforeHook(eventType string, hook EventListener) {
+	e.mutableRegistrations()
+	if !admit(e, "before hook", eventType, e.beforeHooks[eventType], hook, listenerName) {
+		return
+	}
 	e.beforeHooks[eventType] = append(e.beforeHooks[eventType], hook)
 }
 
 // RegisterListener registers a listener for a specific event type
 func (e *Engine) RegisterListener(eventType string, listener EventListener) {
+	e.mutableRegistrations()
+	if !admit(e, "listener", eventType, e.listeners[eventType], listener, listenerName) {
+		return
+	}
 	e.listeners[eventType] = append(e.listeners[eventType], listener)
 }
 
+// RemoveListener unregisters a listener from an event type (AnyEvent for
+// ThenAll listeners), reporting whether it was registered. Listeners are
+// matched by identity, so pass the value that was registered.
+func (e *Engine) RemoveListener(eventType string, listener EventListener) bool {
+	r := e.mutableRegistrations()
+	listeners := r.listeners[eventType]
+	for i, registered := range listeners {
+		if reflect.TypeOf(registered) == reflect.TypeOf(listener) && reflect.TypeOf(listener).Comparable() && registered == listener {
+			r.listeners[eventType] = append(listeners[:i:i], listeners[i+1:]...)
+			return true
+		}
+	}
+	return false
+}
+
 // RegisterEventType registers a factory function for a specific event type
 func (e *Engine) RegisterEventType(eventType string, factory func() Event) {
+	e.mutableRegistrations()
+	if existing, exists := e.eventFactories[eventType]; exists && e.strictRegistration {
+		if before, after := reflect.TypeOf(existing()), reflect.TypeOf(factory()); before != after {
+			e.misconfigured("event type %s is registered as both %v and %v", eventType, before, after)
+		}
+	}
 	e.eventFactories[eventType] = factory
 }
 
+// RegisterEventTypes registers factories for several event types at once,
+// taking each type name from the prototype's Type() method. Factories always
+// return a new pointer to the prototype's struct type (so events can be
+// unmarshaled into), whether the prototype is given as a value or a pointer.
+// Usage: engine.RegisterEventTypes(&MoveMadeEvent{}, &GameStartedEvent{})
+func (e *Engine) RegisterEventTypes(prototypes ...Event) {
+	for _, prototype := range prototypes {
+		structType := reflect.TypeOf(prototype)
+		if structType.Kind() == reflect.Ptr {
+			structType = structType.Elem()
+		}
+
+		e.RegisterEventType(prototype.Type(), func() Event {
+			return reflect.New(structType).Interface().(Event)
+		})
+	}
+}
+
+// NewEvent creates an empty event instance using the registered factory for a type
+func (e *Engine) NewEvent(eventType string) (Event, bool) {
+	factory, exists := e.eventFactories[eventType]
+	if !exists {
+		return nil, false
+	}
+	return factory(), true
+}
+
 // RegisterState registers a state by name with its initial value
 // Reducers should be attached via the fluent API using Updates()
 func (e *Engine) RegisterState(name string, initialState interface{}) {
+	e.mutableRegistrations()
 	e.states[name] = StateRegistry{
 		InitialState: initialState,
 		Reducers:     make(map[string]StateReducer),
@@ -98,6 +330,7 @@ func (e *Engine) RegisterState(name string, initialState interface{}) {
 
 // RegisterService registers a service (reference data/utilities) in the service locator
 func (e *Engine) RegisterService(name string, service interface{}) {
+	e.mutableRegistrations()
 	e.services[name] = service
 }
 
@@ -115,6 +348,26 @@ func (e *Engine) GetState(name string) interface{} {
 		return nil
 	}
 
+	if e.checkpointEvery > 0 {
+		state, _, _ := e.project(name, registry, true)
+		return state
+	}
+
+	state := e.startingState(name, registry)
+
+	// Apply events
+	e.eachEventFor(registry.Reducers, func(event Event) bool {
+		if reducer, hasReducer := registry.Reducers[event.Type()]; hasReducer {
+			state = reducer(e, state, event)
+		}
+		return true
+	})
+
+	return state
+}
+
+// startingState returns a state's initial value, merged with its snapshot if the repository has one
+func (e *Engine) startingState(name string, registry StateRegistry) interface{} {
 	// Start with initial state
 	state := registry.InitialState
 
@@ -126,71 +379,286 @@ func (e *Engine) GetState(name string) interface{} {
 		}
 	}
 
-	// Apply events
-	for _, event := range e.repository.GetAll(e) {
-		reducer, hasReducer := registry.Reducers[event.Type()]
-		if hasReducer {
-			state = reducer(e, state, event)
+	return state
+}
+
+// eachEventFor visits the events a set of reducers handles. Indexed
+// repositories only return those event types; otherwise the whole log is
+// visited and the caller skips unrelated events.
+func (e *Engine) eachEventFor(reducers map[string]StateReducer, fn func(event Event) bool) {
+	indexed, ok := e.repository.(types.IndexedRepository)
+	if !ok {
+		e.eachEvent(fn)
+		return
+	}
+
+	eventTypes := make([]string, 0, len(reducers))
+	for eventType := range reducers {
+		eventTypes = append(eventTypes, eventType)
+	}
+	for _, event := range indexed.EventsOfTypes(e, eventTypes...) {
+		if !fn(event) {
+			return
 		}
 	}
+}
 
-	return state
+// replayChunkSize is how many events are requested per Stream call when
+// replaying a StreamingRepository
+const replayChunkSize = 1000
+
+// eachEvent visits the log in order: in place for EventRangers, in chunks for
+// StreamingRepositories, and from a GetAll copy otherwise. A stream error
+// falls back to GetAll from the event where the stream failed.
+func (e *Engine) eachEvent(fn func(event Event) bool) {
+	e.eachEventFrom(0, fn)
+}
+
+// eachEventFrom visits the log in order like eachEvent, starting at a
+// position (streamed from there, otherwise skipping to it)
+func (e *Engine) eachEventFrom(start int, fn func(event Event) bool) {
+	if ranger, ok := e.repository.(types.EventRanger); ok {
+		ranger.Range(e, skipTo(start, fn))
+		return
+	}
+	if streamer, ok := e.repository.(types.StreamingRepository); ok {
+		var done bool
+		if start, done = streamFrom(streamer, start, fn); done {
+			return
+		}
+	}
+	events := e.repository.GetAll(e)
+	if start > len(events) {
+		return
+	}
+	for _, event := range events[start:] {
+		if !fn(event) {
+			return
+		}
+	}
+}
+
+// streamFrom visits a streaming repository's log in chunks from start. It
+// reports whether the visit is done, or else the position where the stream
+// failed, for the caller to finish from a full read.
+func streamFrom(streamer types.StreamingRepository, start int, fn func(event Event) bool) (int, bool) {
+	for from := start; ; from += replayChunkSize {
+		it := streamer.Stream(from, from+replayChunkSize)
+		n, more := 0, true
+		for more && it.Next() {
+			n++
+			more = fn(it.Event())
+		}
+		err := it.Err()
+		it.Close()
+		switch {
+		case !more:
+			return 0, true
+		case err != nil:
+			return from + n, false
+		case n < replayChunkSize:
+			return 0, true
+		}
+	}
+}
+
+// skipTo wraps a visitor to ignore the events before a position
+func skipTo(start int, fn func(event Event) bool) func(event Event) bool {
+	if start == 0 {
+		return fn
+	}
+	position := 0
+	return func(event Event) bool {
+		position++
+		return position <= start || fn(event)
+	}
+}
+
+// EmitResult describes the outcome of an emit
+type EmitResult struct {
+	Accepted   bool      // True if the event was committed
+	RejectedBy string    // Name of the validator that rejected the event, if any
+	Reason     string    // The rejecting validator's reason (ReasonedValidators only)
+	Err        error     // Why the emit failed outright: a *RepositoryError when the event passed validation but could not be stored (see StorageFailed)
+	Warnings   []Warning // Objections from advisory validators (the event is committed regardless)
+	ID         string    // ID assigned at commit, when the repository stores IDs
+	Duplicate  bool      // EmitWithID found the ID already committed, so nothing was emitted
+	Queued     bool      // Held to be processed later, not yet validated (see WithBreadthFirstEmits and Pause)
+	Message    *Message  // The rejection as a message key and parameters, when the validator gave one
+	Events     []Event   // Every event committed by the call, in log order: those emitted by before hooks, the emitted ones, then those derived by listeners
+	Position   int       // Index in Events of the emitted event (the first of a batch), after any before-hook events, when accepted
 }
 
 // Emit attempts to emit an event through validation and commitment
 func (e *Engine) Emit(event Event) bool {
-	// Get validators for this event type
-	validators, exists := e.validators[event.Type()]
-	if exists {
-		// Get exceptions for this event type
-		exceptions := e.exceptions[event.Type()]
-
-		// All validators must approve (unless exception applies)
-		for _, validator := range validators {
-			// Check if any exception applies to skip this validator
-			shouldSkip := false
-			for _, exception := range exceptions {
-				if exception.Validator == validator && exception.Condition(e, event) {
-					shouldSkip = true
-					break
-				}
-			}
-
-			// Skip validation if exception applies
-			if shouldSkip {
-				continue
-			}
-
-			// Run validator
-			if !validator.Validate(e, event) {
-				return false // validation failed
-			}
+	return e.EmitWithResult(event).Accepted
+}
+
+// EmitWithResult emits an event like Emit, reporting why it was rejected or,
+// if accepted, every event the call committed (see EmitResult.Events)
+func (e *Engine) EmitWithResult(event Event) EmitResult {
+	return e.emit(event, "")
+}
+
+// emit validates and commits an event under an ID (generated if empty)
+func (e *Engine) emit(event Event, id string) (result EmitResult) {
+	defer func() { e.metaRejected(event, result) }()
+
+	if err := e.precheck(event); err != nil {
+		return EmitResult{Err: err}
+	}
+	if e.paused != nil {
+		return e.hold([]Event{event}, false, id)
+	}
+	if e.breadthFirst && e.listening > 0 {
+		return e.queueDerived(event, id)
+	}
+	if e.payloadLimit > 0 {
+		limited, err := e.limitPayload(event)
+		if errors.Is(err, ErrPayloadTooLarge) {
+			return EmitResult{RejectedBy: PayloadSizeValidator, Reason: err.Error()}
 		}
+		if err != nil {
+			return EmitResult{Err: err}
+		}
+		event = limited
+	}
+	defer e.transaction(&result)()
+	defer e.begin(StageEmit, event.Type(), event.Type())()
+
+	// All validators (global, then this event type's) must approve (unless
+	// exception applies), external checks last
+	for _, validator := range e.validatorsFor(event.Type()) {
+		// Skip validation if exception applies
+		if _, skip := e.applicableException(validator, event); skip {
+			continue
+		}
+		if _, remote := validator.(*externalValidator); remote {
+			continue
+		}
+
+		// Run validator
+		end := e.begin(StageValidator, validator, event.Type())
+		ok, err := e.check(validator, event)
+		end()
+		if !ok {
+			countRejection(validator)
+			return EmitResult{RejectedBy: validatorName(validator), Reason: e.reason(err), Message: messageOf(err)} // validation failed
+		}
+	}
+	if failed, err := e.checkRemote(event.Type(), e.remoteChecks(event, 0)); failed != nil {
+		return EmitResult{RejectedBy: validatorName(failed.validator), Reason: e.reason(err), Message: messageOf(err)}
+	}
+
+	// Blobs are only stored for events that pass validation
+	event, err := e.commitBlobs(event)
+	if errors.Is(err, ErrPayloadTooLarge) {
+		return EmitResult{RejectedBy: PayloadSizeValidator, Reason: err.Error()}
+	}
+	if err != nil {
+		return EmitResult{Err: err}
 	}
 
+	// Advisory validators never block, but their warnings travel with the commit
+	warnings := e.advise(event)
+	previousWarnings := e.warnings
+	e.warnings = warnings
+	defer func() { e.warnings = previousWarnings }()
+
+	// Track whose hooks and listeners are running, to check declared emits
+	if e.enforceEmits {
+		e.emitting = append(e.emitting, event.Type())
+		defer func() { e.emitting = e.emitting[:len(e.emitting)-1] }()
+	}
+
+	// Settle autosaves and effects once this emit and everything it derives have committed
+	e.depth++
+	defer e.settle()
+
 	// Call before hooks AFTER validation but BEFORE commitment
 	// This allows side effects (like fate dice) to run as part of the event's transaction
 	beforeHooks, hasBeforeHooks := e.beforeHooks[event.Type()]
 	if hasBeforeHooks {
 		for _, hook := range beforeHooks {
+			end := e.begin(StageHook, hook, event.Type())
 			hook.Handle(e, event)
+			end()
 		}
 	}
 
 	// No validators or all validators passed - commit the event to repository
-	if err := e.repository.Add(e, event); err != nil {
-		return false // persistence failure
+	end := e.begin(StageRepository, "add", event.Type())
+	id, err = e.add(event, id)
+	end()
+	if err != nil {
+		return EmitResult{Err: &RepositoryError{Op: "add", Err: err}} // persistence failure
+	}
+	e.revision++
+	e.committed = append(e.committed, event)
+	position := len(e.committed) - 1
+	if e.autosave != nil {
+		e.autosave.pending++
+	}
+	e.uncheckpointed++
+
+	e.listen(func() { e.notify(event) })
+	return EmitResult{Accepted: true, Warnings: warnings, ID: id, Position: position}
+}
+
+// precheck applies the engine-wide checks that come before validation
+func (e *Engine) precheck(event Event) error {
+	if e.closed {
+		return ErrEngineClosed
+	}
+	if e.readOnly {
+		return ErrReadOnly
+	}
+	if e.autoEventTypes {
+		if err := e.registerAutoEvent(event); err != nil {
+			return err
+		}
+	}
+	if e.strictEvents {
+		if err := e.checkRegistered(event); err != nil {
+			return err
+		}
 	}
+	if e.enforceEmits {
+		if err := e.checkDeclared(event); err != nil {
+			return err
+		}
+	}
+	return nil
+}
 
-	// Call listeners after commitment
+// notify runs listeners and invariant checks for a committed event
+func (e *Engine) notify(event Event) {
+	// Call listeners after commitment, starting with those subscribed to every event
+	// so they observe commits in log order before any derived events are emitted
+	for _, listener := range e.listeners[AnyEvent] {
+		e.handle(listener, event)
+	}
 	listeners, hasListeners := e.listeners[event.Type()]
 	if hasListeners {
 		for _, listener := range listeners {
-			listener.Handle(e, event)
+			e.handle(listener, event)
 		}
 	}
 
-	return true
+	if e.invariantChecks {
+		e.checkInvariants(event)
+	}
+}
+
+// applicableException returns the first registered exception that skips the
+// given validator for this event, if any
+func (e *Engine) applicableException(validator EventValidator, event Event) (ValidatorException, bool) {
+	for _, exception := range e.exceptions[event.Type()] {
+		if exception.Validator == validator && exception.Condition(e, event) {
+			return exception, true
+		}
+	}
+	return ValidatorException{}, false
 }
 
 // GetEvents returns all events in the system
@@ -198,14 +666,27 @@ func (e *Engine) GetEvents() []Event {
 	return e.repository.GetAll(e)
 }
 
-// SetEvents sets the events directly (for rebuilding from event log)
-// Panics if the repository fails to set events
+// SetEvents sets the events directly (for rebuilding from event log), then
+// runs load hooks (see OnLoad). With an identified repository, events the
+// old and new logs share from the start keep their IDs and the others get
+// new ones (see SetEnvelopes to choose them).
+// Panics if the events can't be set (see SetEventsE)
 func (e *Engine) SetEvents(events []Event) {
-	if err := e.repository.SetAll(e, events); err != nil {
+	if err := e.SetEventsE(events); err != nil {
 		panic("failed to set events in repository: " + err.Error())
 	}
 }
 
+// loaded runs after the log is replaced
+func (e *Engine) loaded(count int) {
+	e.revision++
+	e.clearCheckpoints()
+	e.meta(EventsLoaded{Count: count})
+	for _, hook := range e.loadHooks {
+		hook(e)
+	}
+}
+
 // EventWrapper wraps events with their type for JSON serialization
 type EventWrapper struct {
 	Type string      `json:"type"`
@@ -214,50 +695,129 @@ type EventWrapper struct {
 
 // MarshalEvents serializes events to JSON with type information
 func (e *Engine) MarshalEvents(events []Event) ([]byte, error) {
-	var wrappers []EventWrapper
-	for _, event := range events {
-		wrapper := EventWrapper{
+	var wrappers []EventWrapper // nil for an empty log, which marshals as null
+	if len(events) > 0 {
+		wrappers = make([]EventWrapper, len(events))
+	}
+	for i, event := range events {
+		data, err := e.eventData(event)
+		if err != nil {
+			return nil, err
+		}
+		wrappers[i] = EventWrapper{
 			Type: event.Type(),
-			Data: event,
+			Data: data,
 		}
-		wrappers = append(wrappers, wrapper)
 	}
 	return json.Marshal(wrappers)
 }
 
-// UnmarshalEvents deserializes JSON into events using registered event types
+// UnmarshalEvents deserializes JSON into events using registered event types.
+// Records of unknown types, or whose payload doesn't decode, are skipped
+// (or quarantined, see WithQuarantine; unknown types can also be kept as
+// RawEvent, see WithRawEvents).
 func (e *Engine) UnmarshalEvents(jsonData []byte) ([]Event, error) {
-	var wrappers []EventWrapper
-	if err := json.Unmarshal(jsonData, &wrappers); err != nil {
+	// Records stay raw until their type is known, so large integers aren't
+	// rounded through float64 and quarantined records keep their bytes
+	var records []json.RawMessage
+	if err := json.Unmarshal(jsonData, &records); err != nil {
 		return nil, err
 	}
-
-	var events []Event
-	for _, wrapper := range wrappers {
-		// Get factory for this event type
-		factory, exists := e.eventFactories[wrapper.Type]
-		if !exists {
-			continue // Skip unknown event types
+	var quarantined []QuarantinedRecord
+	events := make([]Event, 0, len(records))
+	for _, record := range records {
+		var wrapper struct {
+			Type string          `json:"type"`
+			Data json.RawMessage `json:"data"`
 		}
-
-		// Create new event instance and unmarshal into it
-		event := factory()
-		eventJSON, err := json.Marshal(wrapper.Data)
-		if err != nil {
-			continue // Skip events that can't be re-marshaled
+		if err := json.Unmarshal(record, &wrapper); err != nil {
+			return nil, err
 		}
 
-		if err := json.Unmarshal(eventJSON, event); err != nil {
-			continue // Skip events that can't be unmarshaled
+		event, err := e.decodeRecord(wrapper.Type, wrapper.Data)
+		if err != nil {
+			quarantined = e.quarantine(quarantined, len(events), wrapper.Type, record, err)
+			continue // Skip unknown event types and events that can't be unmarshaled
 		}
 
-		// If event is a pointer, dereference it before adding
 		events = append(events, event)
 	}
 
+	e.decoded(events, quarantined)
 	return events, nil
 }
 
+// decodeRecord decodes one stored event for UnmarshalEvents, reporting
+// unknown types (unless kept as RawEvent) and payloads that don't fit
+func (e *Engine) decodeRecord(eventType string, data json.RawMessage) (Event, error) {
+	factory, exists := e.eventFactories[eventType]
+	if !exists && e.rawEvents {
+		return rawEvent(eventType, data), nil
+	}
+	if !exists {
+		return nil, fmt.Errorf("%w: %s", ErrUnknownEventType, eventType)
+	}
+
+	// Create new event instance and unmarshal into it
+	event := factory()
+	if len(data) > 0 {
+		decrypted, err := e.storedData(event, data)
+		if err == nil {
+			err = json.Unmarshal(decrypted, event)
+		}
+		if err != nil {
+			return nil, errUndecodable(eventType, err)
+		}
+		e.bindBlobs(event)
+	}
+	return event, nil
+}
+
+// ErrUnknownEventType is returned when decoding an event type with no registered factory
+var ErrUnknownEventType = errors.New("unknown event type")
+
+// MarshalEvent serializes a single event to JSON with type information
+func (e *Engine) MarshalEvent(event Event) ([]byte, error) {
+	data, err := e.eventData(event)
+	if err != nil {
+		return nil, err
+	}
+	return json.Marshal(EventWrapper{Type: event.Type(), Data: data})
+}
+
+// UnmarshalEvent deserializes a single wrapped event produced by MarshalEvent.
+// Unlike UnmarshalEvents it reports unknown types (ErrUnknownEventType) and
+// malformed payloads as errors instead of skipping them (unknown types
+// decode as RawEvent with WithRawEvents).
+func (e *Engine) UnmarshalEvent(jsonData []byte) (Event, error) {
+	var wrapper struct {
+		Type string          `json:"type"`
+		Data json.RawMessage `json:"data"`
+	}
+	if err := json.Unmarshal(jsonData, &wrapper); err != nil {
+		return nil, err
+	}
+
+	event, exists := e.NewEvent(wrapper.Type)
+	if !exists && e.rawEvents {
+		return rawEvent(wrapper.Type, wrapper.Data), nil
+	}
+	if !exists {
+		return nil, fmt.Errorf("%w: %s", ErrUnknownEventType, wrapper.Type)
+	}
+	if len(wrapper.Data) > 0 {
+		data, err := e.storedData(event, wrapper.Data)
+		if err != nil {
+			return nil, err
+		}
+		if err := json.Unmarshal(data, event); err != nil {
+			return nil, err
+		}
+		e.bindBlobs(event)
+	}
+	return event, nil
+}
+
 // =============================================================================
 // Snapshot API
 // =============================================================================
@@ -277,7 +837,13 @@ func (e *Engine) SetSnapshot(stateName string, snapshot interface{}) error {
 		return err
 	}
 
-	return snapshotRepo.SetSnapshot(stateName, data)
+	e.revision++
+	e.clearCheckpoint(stateName)
+	if err := snapshotRepo.SetSnapshot(stateName, data); err != nil {
+		return err
+	}
+	e.meta(SnapshotCreated{State: stateName})
+	return nil
 }
 
 // ClearSnapshot removes the snapshot for a state.
@@ -288,7 +854,24 @@ func (e *Engine) ClearSnapshot(stateName string) error {
 		return errors.New("repository does not support snapshots")
 	}
 
-	return snapshotRepo.ClearSnapshot(stateName)
+	e.revision++
+	e.clearCheckpoint(stateName)
+	if err := snapshotRepo.ClearSnapshot(stateName); err != nil {
+		return err
+	}
+	e.meta(SnapshotCleared{State: stateName})
+	return nil
+}
+
+// GetSnapshot returns the stored snapshot JSON for a state.
+// Returns false if there is none or the repository doesn't support snapshots.
+func (e *Engine) GetSnapshot(stateName string) ([]byte, bool) {
+	snapshotRepo, ok := e.repository.(types.SnapshotRepository)
+	if !ok {
+		return nil, false
+	}
+
+	return snapshotRepo.GetSnapshot(stateName)
 }
 
 // HasSnapshot returns true if a snapshot exists for the given state.
@@ -231,6 +231,73 @@ func TestMarshalUnmarshalRoundTrip(t *testing.T) {
 	assert.Equal(t, "ORD-3", finalEvents[2].(*OrderPlacedEvent).OrderID)
 }
 
+// SeededEvent carries an integer too large for float64
+type SeededEvent struct {
+	Seed int64
+}
+
+func (e SeededEvent) Type() string { return "seeded" }
+
+// TestUnmarshalEventsKeepsLargeIntegers verifies payloads aren't rounded through float64
+func TestUnmarshalEventsKeepsLargeIntegers(t *testing.T) {
+	engine := NewEngine()
+	engine.When("seeded", func() Event { return &SeededEvent{} })
+
+	jsonData, err := engine.MarshalEvents([]Event{SeededEvent{Seed: 1<<62 + 1}})
+	assert.NoError(t, err)
+
+	events, err := engine.UnmarshalEvents(jsonData)
+	assert.NoError(t, err)
+	assert.Equal(t, []Event{&SeededEvent{Seed: 1<<62 + 1}}, events)
+}
+
+// TestRegisterEventTypes verifies factories are derived from prototypes by reflection
+func TestRegisterEventTypes(t *testing.T) {
+	engine := NewEngine()
+	engine.RegisterEventTypes(&OrderPlacedEvent{}, InvoiceGeneratedEvent{})
+
+	order, ok := engine.NewEvent("order_placed")
+	assert.True(t, ok)
+	assert.Equal(t, &OrderPlacedEvent{}, order)
+
+	invoice, ok := engine.NewEvent("invoice_generated")
+	assert.True(t, ok)
+	assert.Equal(t, &InvoiceGeneratedEvent{}, invoice, "Value prototypes still produce pointers")
+
+	// Each call creates a fresh instance
+	other, _ := engine.NewEvent("order_placed")
+	assert.NotSame(t, order, other)
+
+	restored, err := engine.UnmarshalEvent([]byte(`{"type":"order_placed","data":{"OrderID":"ORD-9","Amount":5}}`))
+	assert.NoError(t, err)
+	assert.Equal(t, &OrderPlacedEvent{OrderID: "ORD-9", Amount: 5}, restored)
+}
+
+// TestWhenEvent verifies generic registrations take the type string and factory from T
+func TestWhenEvent(t *testing.T) {
+	engine := NewEngine(WithStrictEvents())
+	var placed []string
+	WhenEvent[OrderPlacedEvent](engine).
+		Requires(NewTypedValidator(TypedValidatorFunc[OrderPlacedEvent](func(e *Engine, event OrderPlacedEvent) bool {
+			return event.Amount > 0
+		}))).
+		Then(NewTypedListener(TypedListenerFunc[OrderPlacedEvent](func(e *Engine, event OrderPlacedEvent) {
+			placed = append(placed, event.OrderID)
+		})))
+	WhenEvent[*InvoiceGeneratedEvent](engine)
+
+	assert.True(t, engine.Emit(OrderPlacedEvent{OrderID: "ORD-1", Amount: 5}))
+	assert.False(t, engine.Emit(OrderPlacedEvent{OrderID: "ORD-2"}))
+	assert.Equal(t, []string{"ORD-1"}, placed)
+
+	order, ok := engine.NewEvent("order_placed")
+	assert.True(t, ok)
+	assert.Equal(t, &OrderPlacedEvent{}, order)
+	invoice, ok := engine.NewEvent("invoice_generated")
+	assert.True(t, ok, "Pointer types register too")
+	assert.Equal(t, &InvoiceGeneratedEvent{}, invoice)
+}
+
 // TypedValidatorFunc is a helper for creating validators from functions
 type TypedValidatorFunc[T Event] func(*Engine, T) bool
 
@@ -321,3 +388,12 @@ func TestSetSnapshotWithUnmarshalableData(t *testing.T) {
 	err := engine.SetSnapshot("test", unmarshalable)
 	assert.Error(t, err)
 }
+
+// TestDeref verifies pointer events are dereferenced only when their values are events
+func TestDeref(t *testing.T) {
+	assert.Equal(t, TestEvent{Name: "a"}, Deref(&TestEvent{Name: "a"}))
+	assert.Equal(t, TestEvent{Name: "b"}, Deref(TestEvent{Name: "b"}))
+
+	pointerOnly := &PlayerRegisteredEvent{}
+	assert.Same(t, pointerOnly, Deref(pointerOnly), "Events whose values aren't events stay pointers")
+}
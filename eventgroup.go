@@ -0,0 +1,72 @@
+package atmos
+
+// EventGroup configures several event types at once, so a rule shared by
+// moves, passes and resignations is registered in one chain
+type EventGroup struct {
+	registrations []*EventRegistration
+}
+
+// WhenAny starts a registration chain covering several event types; each
+// call registers the same validator, listener or reducer for every type
+// Usage: WhenAny("move_made", "pass", "resign").Updates("game", AdvanceTurn)
+func (e *Engine) WhenAny(eventTypes ...string) *EventGroup {
+	group := &EventGroup{}
+	for _, eventType := range eventTypes {
+		group.registrations = append(group.registrations, e.Event(eventType))
+	}
+	return group
+}
+
+// Each runs fn for each event type's registration, for options the group
+// doesn't offer directly (chainable)
+func (g *EventGroup) Each(fn func(r *EventRegistration)) *EventGroup {
+	for _, r := range g.registrations {
+		fn(r)
+	}
+	return g
+}
+
+// Requires adds validators to every event type (chainable)
+func (g *EventGroup) Requires(validators ...EventValidator) *EventGroup {
+	return g.Each(func(r *EventRegistration) { r.Requires(validators...) })
+}
+
+// Warns adds advisory validators to every event type (chainable)
+func (g *EventGroup) Warns(validators ...EventValidator) *EventGroup {
+	return g.Each(func(r *EventRegistration) { r.Warns(validators...) })
+}
+
+// Before adds pre-commit hooks to every event type (chainable)
+func (g *EventGroup) Before(hooks ...EventListener) *EventGroup {
+	return g.Each(func(r *EventRegistration) { r.Before(hooks...) })
+}
+
+// Then adds listeners to every event type (chainable)
+func (g *EventGroup) Then(listeners ...EventListener) *EventGroup {
+	return g.Each(func(r *EventRegistration) { r.Then(listeners...) })
+}
+
+// ThenAsync adds background listeners to every event type (chainable)
+func (g *EventGroup) ThenAsync(listeners ...EventListener) *EventGroup {
+	return g.Each(func(r *EventRegistration) { r.ThenAsync(listeners...) })
+}
+
+// ThenQueued feeds every event type to a queue (chainable)
+func (g *EventGroup) ThenQueued(queue *Queue) *EventGroup {
+	return g.Each(func(r *EventRegistration) { r.ThenQueued(queue) })
+}
+
+// Updates adds the reducer for a state to every event type (chainable)
+func (g *EventGroup) Updates(stateName string, reducer StateReducer) *EventGroup {
+	return g.Each(func(r *EventRegistration) { r.Updates(stateName, reducer) })
+}
+
+// Effects adds effect producers to every event type (chainable)
+func (g *EventGroup) Effects(producers ...EffectProducer) *EventGroup {
+	return g.Each(func(r *EventRegistration) { r.Effects(producers...) })
+}
+
+// Except adds a validator exception to every event type (chainable)
+func (g *EventGroup) Except(validator EventValidator, condition func(*Engine, Event) bool, reason string) *EventGroup {
+	return g.Each(func(r *EventRegistration) { r.Except(validator, condition, reason) })
+}
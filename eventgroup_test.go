@@ -0,0 +1,57 @@
+package atmos
+
+import (
+	"testing"
+
+	"github.com/cumulusrpg/atmos/types"
+	"github.com/stretchr/testify/assert"
+)
+
+// turnCounter counts the events it hears
+type turnCounter struct {
+	heard []string
+}
+
+func (c *turnCounter) Handle(engine types.Engine, event Event) {
+	c.heard = append(c.heard, event.Type())
+}
+
+// TestWhenAnyRegistersForEveryType verifies one chain configures several event types
+func TestWhenAnyRegistersForEveryType(t *testing.T) {
+	engine := NewEngine()
+	engine.RegisterState("over", false)
+	engine.RegisterState("turns", 0)
+	counter := &turnCounter{}
+	engine.WhenAny("move", "pass").
+		Requires(gameInProgress{}).
+		Updates("turns", func(e *Engine, state interface{}, event Event) interface{} {
+			return state.(int) + 1
+		}).
+		Then(counter)
+	engine.When("game_over").Updates("over", func(e *Engine, state interface{}, event Event) interface{} {
+		return true
+	})
+
+	assert.True(t, engine.Emit(MoveEvent{Player: "alice"}))
+	assert.True(t, engine.Emit(PassEvent{Player: "bob"}))
+	assert.True(t, engine.Emit(GameOverEvent{}))
+	assert.False(t, engine.Emit(MoveEvent{Player: "alice"}))
+	assert.False(t, engine.Emit(PassEvent{Player: "bob"}))
+
+	assert.Equal(t, 2, engine.GetState("turns"))
+	assert.Equal(t, []string{"move", "pass"}, counter.heard)
+}
+
+// TestWhenAnyEach verifies Each reaches options the group doesn't wrap
+func TestWhenAnyEach(t *testing.T) {
+	engine := NewEngine()
+	var seen []string
+	engine.WhenAny("move", "pass").Each(func(r *EventRegistration) {
+		seen = append(seen, r.eventType)
+		r.Emits("game_over")
+	})
+
+	assert.Equal(t, []string{"move", "pass"}, seen)
+	assert.Equal(t, []string{"game_over"}, engine.emits["move"])
+	assert.Equal(t, []string{"game_over"}, engine.emits["pass"])
+}
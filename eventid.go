@@ -0,0 +1,185 @@
+package atmos
+
+import (
+	"encoding/json"
+	"errors"
+	"reflect"
+
+	"github.com/cumulusrpg/atmos/types"
+)
+
+// Envelope is a committed event with the ID assigned at commit
+type Envelope = types.Envelope
+
+var (
+	// ErrNoEventIDs is returned when the repository doesn't store event IDs
+	ErrNoEventIDs = errors.New("repository does not store event IDs")
+
+	// ErrUnknownEventID is returned for an ID no committed event has
+	ErrUnknownEventID = errors.New("unknown event ID")
+)
+
+// WithEventIDs sets how event IDs are generated (default ULIDs from
+// NewULIDGenerator). IDs are assigned at commit when the repository
+// implements types.IdentifiedRepository, such as repository.Identified.
+func WithEventIDs(generate func() string) EngineOption {
+	return func(e *Engine) {
+		e.generateID = generate
+	}
+}
+
+// EmitWithID emits an event under an ID chosen by the caller, such as a
+// ULID generated by the client, making retries idempotent: if an event with
+// the ID is already committed, nothing is emitted and the result is
+// Accepted with Duplicate set.
+func (e *Engine) EmitWithID(id string, event Event) EmitResult {
+	identified, ok := e.repository.(types.IdentifiedRepository)
+	if !ok {
+		return EmitResult{Err: ErrNoEventIDs}
+	}
+	if _, exists := identified.EnvelopeByID(e, id); exists {
+		return EmitResult{Accepted: true, ID: id, Duplicate: true}
+	}
+	return e.emit(event, id)
+}
+
+// Envelopes returns every committed event with its ID, or nil if the
+// repository doesn't store IDs
+func (e *Engine) Envelopes() []Envelope {
+	if identified, ok := e.repository.(types.IdentifiedRepository); ok {
+		return identified.Envelopes(e)
+	}
+	return nil
+}
+
+// EventByID returns the event committed with an ID
+func (e *Engine) EventByID(id string) (Event, bool) {
+	if identified, ok := e.repository.(types.IdentifiedRepository); ok {
+		if envelope, exists := identified.EnvelopeByID(e, id); exists {
+			return envelope.Event, true
+		}
+	}
+	return nil, false
+}
+
+// EnvelopesAfter returns the events committed after the one with an ID, for
+// clients syncing from a cursor. An empty cursor returns every event.
+func (e *Engine) EnvelopesAfter(cursor string) ([]Envelope, error) {
+	identified, ok := e.repository.(types.IdentifiedRepository)
+	if !ok {
+		return nil, ErrNoEventIDs
+	}
+	envelopes := identified.Envelopes(e)
+	if cursor == "" {
+		return envelopes, nil
+	}
+	for i, envelope := range envelopes {
+		if envelope.ID == cursor {
+			return envelopes[i+1:], nil
+		}
+	}
+	return nil, ErrUnknownEventID
+}
+
+// SetEnvelopes replaces the log like SetEvents, keeping each event's ID
+func (e *Engine) SetEnvelopes(envelopes []Envelope) error {
+	return e.setEnvelopes(envelopes, nil)
+}
+
+// setEnvelopes replaces the log with envelopes, holding quarantined records with it
+func (e *Engine) setEnvelopes(envelopes []Envelope, quarantined []QuarantinedRecord) error {
+	identified, ok := e.repository.(types.IdentifiedRepository)
+	if !ok {
+		return ErrNoEventIDs
+	}
+	if err := identified.SetEnvelopes(e, envelopes); err != nil {
+		return &RepositoryError{Op: "set", Err: err}
+	}
+	e.lastDecoded, e.quarantined = decodedLog{}, quarantined
+	e.loaded(len(envelopes))
+	return nil
+}
+
+// envelopeWrapper is the JSON form of an envelope
+type envelopeWrapper struct {
+	ID       string            `json:"id"`
+	Type     string            `json:"type"`
+	Data     json.RawMessage   `json:"data"`
+	Metadata map[string]string `json:"metadata,omitempty"`
+}
+
+// MarshalEnvelopes serializes events with their IDs, in the MarshalEvents
+// format plus "id" and "metadata" fields per record
+func (e *Engine) MarshalEnvelopes(envelopes []Envelope) ([]byte, error) {
+	wrappers := make([]envelopeWrapper, len(envelopes))
+	for i, envelope := range envelopes {
+		payload, err := e.eventData(envelope.Event)
+		if err != nil {
+			return nil, err
+		}
+		data, err := json.Marshal(payload)
+		if err != nil {
+			return nil, err
+		}
+		wrappers[i] = envelopeWrapper{ID: envelope.ID, Type: envelope.Event.Type(), Data: data, Metadata: envelope.Metadata}
+	}
+	return json.Marshal(wrappers)
+}
+
+// UnmarshalEnvelopes deserializes MarshalEnvelopes output
+func (e *Engine) UnmarshalEnvelopes(data []byte) ([]Envelope, error) {
+	var wrappers []envelopeWrapper
+	if err := json.Unmarshal(data, &wrappers); err != nil {
+		return nil, err
+	}
+	envelopes := make([]Envelope, len(wrappers))
+	for i, wrapper := range wrappers {
+		record, err := json.Marshal(EventWrapper{Type: wrapper.Type, Data: wrapper.Data})
+		if err != nil {
+			return nil, err
+		}
+		event, err := e.UnmarshalEvent(record)
+		if err != nil {
+			return nil, err
+		}
+		envelopes[i] = Envelope{ID: wrapper.ID, Event: event, Metadata: wrapper.Metadata}
+	}
+	return envelopes, nil
+}
+
+// add commits an event, assigning it an ID (unless given one) when the
+// repository stores IDs
+func (e *Engine) add(event Event, id string) (string, error) {
+	identified, ok := e.repository.(types.IdentifiedRepository)
+	if !ok {
+		return "", e.repository.Add(e, event)
+	}
+	if id == "" {
+		id = e.nextID()
+	}
+	return id, identified.AddIdentified(e, Envelope{ID: id, Event: event, Metadata: e.metadata})
+}
+
+// identify gives the events of a log replacing current their IDs: events
+// the logs share, from the start, keep theirs and the rest get new ones
+func (e *Engine) identify(current []Envelope, events []Event) []Envelope {
+	envelopes := make([]Envelope, len(events))
+	shared := true
+	for i, event := range events {
+		shared = shared && i < len(current) && current[i].ID != "" && reflect.DeepEqual(current[i].Event, event)
+		if shared {
+			envelopes[i] = current[i]
+		} else {
+			envelopes[i] = Envelope{ID: e.nextID(), Event: event}
+		}
+	}
+	return envelopes
+}
+
+// nextID generates an event ID
+func (e *Engine) nextID() string {
+	if e.generateID == nil {
+		e.generateID = NewULIDGenerator(nil, nil)
+	}
+	return e.generateID()
+}
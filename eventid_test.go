@@ -0,0 +1,149 @@
+package atmos
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/cumulusrpg/atmos/repository"
+	"github.com/cumulusrpg/atmos/types"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// newIdentifiedEngine numbers events e1, e2, ... at commit
+func newIdentifiedEngine() *Engine {
+	n := 0
+	engine := newBatchEngine(WithRepository(repository.NewIdentified()), WithEventIDs(func() string {
+		n++
+		return fmt.Sprintf("e%d", n)
+	}))
+	return engine
+}
+
+// TestEventIDsAssignedAtCommit verifies accepted events get IDs and rejected ones don't
+func TestEventIDsAssignedAtCommit(t *testing.T) {
+	engine := newIdentifiedEngine()
+
+	assert.Equal(t, "e1", engine.EmitWithResult(TokensSpentEvent{Amount: 1}).ID)
+	assert.Empty(t, engine.EmitWithResult(TokensSpentEvent{Amount: 9}).ID)
+	require.True(t, engine.EmitAll(TokensSpentEvent{Amount: 1}, TokensSpentEvent{Amount: 1}).Accepted)
+
+	assert.Equal(t, []Envelope{
+		{ID: "e1", Event: TokensSpentEvent{Amount: 1}},
+		{ID: "e2", Event: TokensSpentEvent{Amount: 1}},
+		{ID: "e3", Event: TokensSpentEvent{Amount: 1}},
+	}, engine.Envelopes())
+
+	event, found := engine.EventByID("e2")
+	assert.True(t, found)
+	assert.Equal(t, TokensSpentEvent{Amount: 1}, event)
+	_, found = engine.EventByID("e9")
+	assert.False(t, found)
+}
+
+// TestEmitWithIDIsIdempotent verifies a retried emit is committed once
+func TestEmitWithIDIsIdempotent(t *testing.T) {
+	engine := newIdentifiedEngine()
+
+	first := engine.EmitWithID("client-1", TokensSpentEvent{Amount: 2})
+	assert.Equal(t, EmitResult{Accepted: true, ID: "client-1", Events: []Event{TokensSpentEvent{Amount: 2}}}, first)
+	retry := engine.EmitWithID("client-1", TokensSpentEvent{Amount: 2})
+	assert.Equal(t, EmitResult{Accepted: true, ID: "client-1", Duplicate: true}, retry)
+
+	assert.Equal(t, TokenBalance{Tokens: 1}, engine.GetState("balance"))
+	assert.ErrorIs(t, NewEngine().EmitWithID("client-1", TestEvent{}).Err, ErrNoEventIDs)
+}
+
+// TestEnvelopesAfterCursor verifies clients can sync from the last ID they saw
+func TestEnvelopesAfterCursor(t *testing.T) {
+	engine := newIdentifiedEngine()
+	engine.Emit(TokensSpentEvent{Amount: 1})
+	engine.Emit(TokensSpentEvent{Amount: 2})
+
+	all, err := engine.EnvelopesAfter("")
+	require.NoError(t, err)
+	assert.Len(t, all, 2)
+
+	rest, err := engine.EnvelopesAfter("e1")
+	require.NoError(t, err)
+	assert.Equal(t, []Envelope{{ID: "e2", Event: TokensSpentEvent{Amount: 2}}}, rest)
+
+	_, err = engine.EnvelopesAfter("nope")
+	assert.ErrorIs(t, err, ErrUnknownEventID)
+	_, err = NewEngine().EnvelopesAfter("")
+	assert.ErrorIs(t, err, ErrNoEventIDs)
+}
+
+// TestEnvelopesRoundTrip verifies IDs survive serialization and reloading
+func TestEnvelopesRoundTrip(t *testing.T) {
+	engine := newIdentifiedEngine()
+	engine.Emit(TokensSpentEvent{Amount: 1})
+	engine.Emit(TokensSpentEvent{Amount: 2})
+
+	data, err := engine.MarshalEnvelopes(engine.Envelopes())
+	require.NoError(t, err)
+	assert.JSONEq(t, `[{"id":"e1","type":"tokens_spent","data":{"Amount":1}},{"id":"e2","type":"tokens_spent","data":{"Amount":2}}]`, string(data))
+
+	restored := newIdentifiedEngine()
+	restored.RegisterEventTypes(TokensSpentEvent{})
+	envelopes, err := restored.UnmarshalEnvelopes(data)
+	require.NoError(t, err)
+	require.NoError(t, restored.SetEnvelopes(envelopes))
+
+	assert.Equal(t, []Envelope{
+		{ID: "e1", Event: &TokensSpentEvent{Amount: 1}},
+		{ID: "e2", Event: &TokensSpentEvent{Amount: 2}},
+	}, restored.Envelopes())
+	assert.True(t, restored.EmitWithID("e2", TokensSpentEvent{Amount: 2}).Duplicate)
+}
+
+// TestULIDsByDefault verifies engines generate ULIDs unless told otherwise
+func TestULIDsByDefault(t *testing.T) {
+	engine := NewEngine(WithRepository(repository.NewIdentified()))
+	id := engine.EmitWithResult(TestEvent{}).ID
+	assert.Len(t, id, 26)
+}
+
+// beginCounter counts the transactions begun on an identified repository
+type beginCounter struct {
+	*repository.Identified
+	begun int
+}
+
+func (r *beginCounter) Begin(engine types.Engine) (types.Tx, error) {
+	r.begun++
+	return r.Identified.Begin(engine)
+}
+
+// TestEmitAllCommitsIdentifiedBatchesInOneTransaction verifies batches to identified repositories stay atomic and keep their IDs
+func TestEmitAllCommitsIdentifiedBatchesInOneTransaction(t *testing.T) {
+	repo := &beginCounter{Identified: repository.NewIdentified()}
+	n := 0
+	engine := newBatchEngine(WithRepository(repo), WithEventIDs(func() string {
+		n++
+		return fmt.Sprintf("e%d", n)
+	}))
+
+	require.True(t, engine.EmitAll(TokensSpentEvent{Amount: 1}, TokensSpentEvent{Amount: 2}).Accepted)
+	assert.Equal(t, 1, repo.begun)
+	assert.Equal(t, []Envelope{
+		{ID: "e1", Event: TokensSpentEvent{Amount: 1}},
+		{ID: "e2", Event: TokensSpentEvent{Amount: 2}},
+	}, engine.Envelopes())
+}
+
+// TestSetEventsKeepsEventIDs verifies replacing the log keeps the IDs of the events it shares and identifies the rest
+func TestSetEventsKeepsEventIDs(t *testing.T) {
+	engine := newIdentifiedEngine()
+	require.True(t, engine.EmitAll(TokensSpentEvent{Amount: 1}, TokensSpentEvent{Amount: 1}).Accepted)
+
+	engine.SetEvents([]Event{TokensSpentEvent{Amount: 1}, TokensSpentEvent{Amount: 2}, TokensSpentEvent{Amount: 1}})
+	assert.Equal(t, []Envelope{
+		{ID: "e1", Event: TokensSpentEvent{Amount: 1}},
+		{ID: "e3", Event: TokensSpentEvent{Amount: 2}},
+		{ID: "e4", Event: TokensSpentEvent{Amount: 1}},
+	}, engine.Envelopes())
+
+	engine.SetEvents(engine.GetEvents()[:1])
+	assert.Equal(t, []Envelope{{ID: "e1", Event: TokensSpentEvent{Amount: 1}}}, engine.Envelopes(), "Rolling back keeps cursors valid")
+}
@@ -0,0 +1,225 @@
+// Package eventstream lets atmos engines take part in larger event-driven
+// systems by publishing committed events to a message broker topic/stream
+// and by consuming such a stream back into an engine.
+//
+// The package speaks to brokers through the small Sink and Source interfaces
+// rather than importing client libraries, so it works with Kafka, NATS
+// JetStream, or anything else with a few lines of glue. For example, with
+// github.com/segmentio/kafka-go:
+//
+//	type kafkaSink struct{ w *kafka.Writer }
+//
+//	func (s kafkaSink) Send(ctx context.Context, m eventstream.Message) error {
+//		return s.w.WriteMessages(ctx, kafka.Message{Topic: m.Subject, Key: []byte(m.Key), Value: m.Data})
+//	}
+//
+// and with github.com/nats-io/nats.go/jetstream:
+//
+//	type jetStreamSink struct{ js jetstream.JetStream }
+//
+//	func (s jetStreamSink) Send(ctx context.Context, m eventstream.Message) error {
+//		_, err := s.js.Publish(ctx, m.Subject, m.Data, jetstream.WithMsgID(m.Key))
+//		return err
+//	}
+package eventstream
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/cumulusrpg/atmos"
+	"github.com/cumulusrpg/atmos/types"
+)
+
+// Message is a broker-neutral message carrying one wrapped event
+type Message struct {
+	Subject string            // Kafka topic or JetStream subject
+	Key     string            // Partition key / deduplication ID
+	Data    []byte            // The event, encoded with Engine.MarshalEvent
+	Headers map[string]string // Optional broker headers (event type is always set)
+}
+
+// HeaderEventType is the header carrying the event type of a message
+const HeaderEventType = "atmos-event-type"
+
+// Sink sends messages to a broker
+type Sink interface {
+	Send(ctx context.Context, message Message) error
+}
+
+// Source receives messages from a broker
+type Source interface {
+	// Receive blocks until a message is available or ctx is done
+	Receive(ctx context.Context) (Message, error)
+
+	// Ack confirms a message was processed so it is not redelivered
+	Ack(ctx context.Context, message Message) error
+}
+
+// =============================================================================
+// Publisher
+// =============================================================================
+
+// Publisher encodes committed events as messages and sends them to a sink.
+// Use it as a listener for best-effort publishing, or as an outbox.Publisher
+// behind an outbox relay for reliable publishing.
+type Publisher struct {
+	engine  *atmos.Engine
+	sink    Sink
+	subject func(event atmos.Event) string
+	key     func(event atmos.Event) string
+	onError func(event atmos.Event, err error)
+}
+
+// PublisherOption configures publisher construction
+type PublisherOption func(*Publisher)
+
+// WithSubject sets how the topic/subject is derived from an event
+// (default: the fixed subject passed to NewPublisher)
+func WithSubject(subject func(event atmos.Event) string) PublisherOption {
+	return func(p *Publisher) {
+		p.subject = subject
+	}
+}
+
+// WithKey sets how the partition key is derived from an event (default: none)
+func WithKey(key func(event atmos.Event) string) PublisherOption {
+	return func(p *Publisher) {
+		p.key = key
+	}
+}
+
+// WithPublishErrorHandler is called when a listener-driven publish fails
+func WithPublishErrorHandler(handler func(event atmos.Event, err error)) PublisherOption {
+	return func(p *Publisher) {
+		p.onError = handler
+	}
+}
+
+// NewPublisher creates a publisher that encodes events with the engine's registered types
+func NewPublisher(engine *atmos.Engine, sink Sink, subject string, opts ...PublisherOption) *Publisher {
+	p := &Publisher{
+		engine:  engine,
+		sink:    sink,
+		subject: func(atmos.Event) string { return subject },
+		key:     func(atmos.Event) string { return "" },
+	}
+
+	// Apply options
+	for _, opt := range opts {
+		opt(p)
+	}
+
+	return p
+}
+
+// Send encodes and sends a single event
+func (p *Publisher) Send(ctx context.Context, event atmos.Event) error {
+	data, err := p.engine.MarshalEvent(event)
+	if err != nil {
+		return err
+	}
+	return p.sink.Send(ctx, Message{
+		Subject: p.subject(event),
+		Key:     p.key(event),
+		Data:    data,
+		Headers: map[string]string{HeaderEventType: event.Type()},
+	})
+}
+
+// Publish implements outbox.Publisher
+func (p *Publisher) Publish(ctx context.Context, entry types.OutboxEntry) error {
+	return p.Send(ctx, entry.Event)
+}
+
+// Handle implements atmos.EventListener for best-effort publishing after commit.
+// Register it with engine.When(atmos.AnyEvent).Then(publisher) or per event type.
+// Nothing is published while the engine is replaying.
+func (p *Publisher) Handle(engine types.Engine, event atmos.Event) {
+	if atmos.IsReplaying(engine) {
+		return
+	}
+	if err := p.Send(context.Background(), event); err != nil && p.onError != nil {
+		p.onError(event, err)
+	}
+}
+
+// =============================================================================
+// Consumer
+// =============================================================================
+
+// Consumer feeds messages from a source into an engine
+type Consumer struct {
+	engine  *atmos.Engine
+	source  Source
+	onError func(message Message, err error)
+}
+
+// ErrRejected is reported when the engine rejects a consumed event
+var ErrRejected = errors.New("event rejected by engine")
+
+// ErrQueued is reported when the engine holds a consumed event instead of
+// committing it (see atmos.Pause)
+var ErrQueued = errors.New("event queued by engine")
+
+// NewConsumer creates a consumer. onError (optional) is told about messages
+// that could not be decoded or were not accepted. Undecodable messages are
+// still acknowledged, since redelivering them would fail the same way; the
+// others are left unacknowledged for the broker to redeliver.
+func NewConsumer(engine *atmos.Engine, source Source, onError func(message Message, err error)) *Consumer {
+	return &Consumer{engine: engine, source: source, onError: onError}
+}
+
+// ConsumeOne receives a single message and emits its event, acknowledging
+// the message only once the engine accepts the event. An event that isn't
+// accepted is returned as an error (ErrRejected, ErrQueued, or the emit's
+// own error).
+func (c *Consumer) ConsumeOne(ctx context.Context) error {
+	message, err := c.source.Receive(ctx)
+	if err != nil {
+		return err
+	}
+
+	event, err := c.engine.UnmarshalEvent(message.Data)
+	if err != nil {
+		c.report(message, err)
+		return c.source.Ack(ctx, message)
+	}
+
+	if result := c.engine.EmitWithResult(event); !result.Accepted {
+		err := notAccepted(result)
+		c.report(message, err)
+		return err
+	}
+	return c.source.Ack(ctx, message)
+}
+
+// report passes a failed message to the error handler, if any
+func (c *Consumer) report(message Message, err error) {
+	if c.onError != nil {
+		c.onError(message, err)
+	}
+}
+
+// notAccepted describes why an emit wasn't accepted
+func notAccepted(result atmos.EmitResult) error {
+	switch {
+	case result.Err != nil:
+		return result.Err
+	case result.Queued:
+		return ErrQueued
+	}
+	return fmt.Errorf("%w by %s", ErrRejected, result.RejectedBy)
+}
+
+// Run consumes messages until ctx is cancelled, the source fails, or the
+// engine doesn't accept an event (which stays unacknowledged).
+// The engine is driven from the calling goroutine only.
+func (c *Consumer) Run(ctx context.Context) error {
+	for {
+		if err := c.ConsumeOne(ctx); err != nil {
+			return err
+		}
+	}
+}
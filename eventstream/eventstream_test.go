@@ -0,0 +1,147 @@
+package eventstream_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/cumulusrpg/atmos"
+	"github.com/cumulusrpg/atmos/eventstream"
+	"github.com/cumulusrpg/atmos/outbox"
+	"github.com/cumulusrpg/atmos/repository"
+	"github.com/cumulusrpg/atmos/types"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type GoalScoredEvent struct {
+	MatchID string `json:"match_id"`
+	Player  string `json:"player"`
+}
+
+func (e *GoalScoredEvent) Type() string { return "goal_scored" }
+
+// memoryBroker is an in-memory Sink and Source
+type memoryBroker struct {
+	messages []eventstream.Message
+	acked    int
+}
+
+func (b *memoryBroker) Send(ctx context.Context, message eventstream.Message) error {
+	b.messages = append(b.messages, message)
+	return nil
+}
+
+func (b *memoryBroker) Receive(ctx context.Context) (eventstream.Message, error) {
+	if b.acked >= len(b.messages) {
+		return eventstream.Message{}, errors.New("drained")
+	}
+	return b.messages[b.acked], nil
+}
+
+func (b *memoryBroker) Ack(ctx context.Context, message eventstream.Message) error {
+	b.acked++
+	return nil
+}
+
+func newGoalEngine() *atmos.Engine {
+	engine := atmos.NewEngine()
+	engine.When("goal_scored", func() atmos.Event { return &GoalScoredEvent{} })
+	return engine
+}
+
+// TestPublishAndConsume verifies events flow from one engine through a broker into another
+func TestPublishAndConsume(t *testing.T) {
+	broker := &memoryBroker{}
+
+	producer := newGoalEngine()
+	publisher := eventstream.NewPublisher(producer, broker, "",
+		eventstream.WithSubject(func(e atmos.Event) string { return "matches." + e.Type() }),
+		eventstream.WithKey(func(e atmos.Event) string { return e.(*GoalScoredEvent).MatchID }),
+	)
+	producer.When(atmos.AnyEvent).Then(publisher)
+
+	producer.Emit(&GoalScoredEvent{MatchID: "m1", Player: "alice"})
+	producer.Emit(&GoalScoredEvent{MatchID: "m2", Player: "bob"})
+
+	assert.Len(t, broker.messages, 2)
+	assert.Equal(t, "matches.goal_scored", broker.messages[0].Subject)
+	assert.Equal(t, "m1", broker.messages[0].Key)
+	assert.Equal(t, "goal_scored", broker.messages[0].Headers[eventstream.HeaderEventType])
+
+	// Add an undecodable message
+	broker.messages = append(broker.messages, eventstream.Message{Data: []byte(`{"type":"unknown"}`)})
+
+	consumer := newGoalEngine()
+	var failures []error
+	err := eventstream.NewConsumer(consumer, broker, func(m eventstream.Message, err error) {
+		failures = append(failures, err)
+	}).Run(context.Background())
+
+	assert.EqualError(t, err, "drained")
+	assert.Equal(t, 3, broker.acked)
+	assert.Len(t, failures, 1)
+	assert.ErrorIs(t, failures[0], atmos.ErrUnknownEventType)
+	assert.Equal(t, producer.GetEvents(), consumer.GetEvents())
+}
+
+// TestPublisherAsOutboxPublisher verifies reliable publishing through an outbox relay
+func TestPublisherAsOutboxPublisher(t *testing.T) {
+	broker := &memoryBroker{}
+	repo := repository.NewInMemoryOutbox()
+	engine := atmos.NewEngine(atmos.WithRepository(repo))
+	engine.When("goal_scored", func() atmos.Event { return &GoalScoredEvent{} })
+
+	engine.Emit(&GoalScoredEvent{MatchID: "m1", Player: "carol"})
+
+	relay := outbox.NewRelay(repo, eventstream.NewPublisher(engine, broker, "goals"))
+	count, err := relay.RunOnce(context.Background())
+	assert.NoError(t, err)
+	assert.Equal(t, 1, count)
+	assert.Equal(t, "goals", broker.messages[0].Subject)
+	assert.JSONEq(t, `{"type":"goal_scored","data":{"match_id":"m1","player":"carol"}}`, string(broker.messages[0].Data))
+}
+
+// TestPublisherSkipsReplays verifies rebuilding an engine doesn't republish its log
+func TestPublisherSkipsReplays(t *testing.T) {
+	broker := &memoryBroker{}
+	engine := newGoalEngine()
+	engine.When(atmos.AnyEvent).Then(eventstream.NewPublisher(engine, broker, ""))
+
+	assert.NoError(t, engine.Replay([]atmos.Event{&GoalScoredEvent{MatchID: "m1", Player: "alice"}}))
+	assert.Len(t, engine.GetEvents(), 1)
+	assert.Empty(t, broker.messages)
+}
+
+// noOwnGoals rejects goals scored by "mallory"
+type noOwnGoals struct{}
+
+func (noOwnGoals) Validate(engine types.Engine, event types.Event) bool {
+	return event.(*GoalScoredEvent).Player != "mallory"
+}
+
+// TestConsumerOnlyAcksAcceptedEvents verifies events the engine doesn't accept stay on the broker
+func TestConsumerOnlyAcksAcceptedEvents(t *testing.T) {
+	producer := newGoalEngine()
+	broker := &memoryBroker{}
+	publisher := eventstream.NewPublisher(producer, broker, "goals")
+	require.NoError(t, publisher.Send(context.Background(), &GoalScoredEvent{MatchID: "m1", Player: "alice"}))
+	require.NoError(t, publisher.Send(context.Background(), &GoalScoredEvent{MatchID: "m1", Player: "mallory"}))
+
+	consumer := atmos.NewEngine()
+	consumer.When("goal_scored", func() atmos.Event { return &GoalScoredEvent{} }).Requires(noOwnGoals{})
+	var failures []error
+	err := eventstream.NewConsumer(consumer, broker, func(m eventstream.Message, err error) {
+		failures = append(failures, err)
+	}).Run(context.Background())
+
+	assert.ErrorIs(t, err, eventstream.ErrRejected)
+	assert.Equal(t, 1, broker.acked, "The rejected message is left for redelivery")
+	assert.Equal(t, []error{err}, failures)
+	assert.Len(t, consumer.GetEvents(), 1)
+
+	consumer.Pause()
+	err = eventstream.NewConsumer(consumer, broker, nil).ConsumeOne(context.Background())
+	assert.ErrorIs(t, err, eventstream.ErrQueued)
+	assert.Equal(t, 1, broker.acked)
+}
@@ -1,88 +1,58 @@
 package tictactoe
 
 import (
-	"fmt"
-
 	"github.com/cumulusrpg/atmos"
 )
 
 // Game represents a tic-tac-toe game using the atmos engine
 type Game struct {
-	engine *atmos.Engine
+	*atmos.Game[GameState]
 }
 
 // NewGame creates a new tic-tac-toe game
 func NewGame() *Game {
-	engine := atmos.NewEngine()
-
-	// Register game state
-	engine.RegisterState("game", NewGameState())
+	game := atmos.NewGame("game", NewGameState())
 
 	// Register event handlers using fluent API
-	engine.When("game_started", func() atmos.Event { return &GameStartedEvent{} }).
-		Requires(atmos.Valid(&GameNotStarted{})).
+	game.When("game_started", func() atmos.Event { return &GameStartedEvent{} }).
+		Requires(atmos.Because(atmos.Valid(&GameNotStarted{}), "game already started")).
 		Updates("game", ReduceGameStarted)
 
-	engine.When("move_made", func() atmos.Event { return &MoveMadeEvent{} }).
-		Requires(atmos.Valid(&ValidMove{})).
+	game.When("move_made", func() atmos.Event { return &MoveMadeEvent{} }).
+		Requires(atmos.NewTypedReasonedValidator[MoveMadeEvent](&ValidMove{})).
 		Then(atmos.Do(&CheckForWinner{})).
 		Updates("game", ReduceMoveMade)
 
-	engine.When("game_ended", func() atmos.Event { return &GameEndedEvent{} }).
+	game.When("game_ended", func() atmos.Event { return &GameEndedEvent{} }).
 		Updates("game", ReduceGameEnded)
 
-	return &Game{engine: engine}
+	return &Game{Game: game}
 }
 
 // StartGame begins a new game
 func (g *Game) StartGame(playerX, playerO string) error {
-	success := g.engine.Emit(GameStartedEvent{
+	return g.EmitErr(GameStartedEvent{
 		PlayerX: playerX,
 		PlayerO: playerO,
 	})
-
-	if !success {
-		return fmt.Errorf("failed to start game: game already started")
-	}
-
-	return nil
 }
 
 // MakeMove attempts to make a move
 func (g *Game) MakeMove(player string, position int) error {
-	success := g.engine.Emit(MoveMadeEvent{
+	return g.EmitErr(MoveMadeEvent{
 		Player:   player,
 		Position: position,
 	})
-
-	if !success {
-		state := g.GetGameState()
-		if !state.GameStarted {
-			return fmt.Errorf("game not started")
-		}
-		if state.IsGameOver() {
-			return fmt.Errorf("game is over")
-		}
-		if player != state.CurrentPlayer {
-			return fmt.Errorf("not your turn (current player: %s)", state.CurrentPlayer)
-		}
-		if !state.IsPositionEmpty(position) {
-			return fmt.Errorf("position %d is already occupied", position)
-		}
-		return fmt.Errorf("invalid move")
-	}
-
-	return nil
 }
 
 // GetGameState returns the current game state
 func (g *Game) GetGameState() GameState {
-	return g.engine.GetState("game").(GameState)
+	return g.State()
 }
 
 // GetBoard returns a string representation of the board
 func (g *Game) GetBoard() string {
-	state := g.GetGameState()
+	state := g.State()
 	board := ""
 	for i := 0; i < 9; i++ {
 		cell := state.Board[i]
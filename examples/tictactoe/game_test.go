@@ -176,7 +176,7 @@ func TestEventLog(t *testing.T) {
 	_ = game.MakeMove("X", 2) // X wins
 
 	// Get all events
-	events := game.engine.GetEvents()
+	events := game.GetEvents()
 
 	// Should have: 1 game_started, 5 move_made, 1 game_ended
 	assert.Equal(t, 7, len(events), "Should have 7 events")
@@ -191,7 +191,7 @@ func TestEventLog(t *testing.T) {
 	assert.Equal(t, "game_ended", events[6].Type())
 
 	// Verify we can rebuild state from event log
-	newEngine := game.engine
+	newEngine := game.Engine
 	newEngine.SetEvents(events)
 	rebuiltState := newEngine.GetState("game").(GameState)
 
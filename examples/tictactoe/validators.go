@@ -1,30 +1,41 @@
 package tictactoe
 
-import "github.com/cumulusrpg/atmos"
+import (
+	"errors"
+	"fmt"
 
-// ValidMove validates that a move is legal
+	"github.com/cumulusrpg/atmos"
+)
+
+// ValidMove validates that a move is legal, explaining why not
 type ValidMove struct{}
 
-func (v *ValidMove) ValidateTyped(engine *atmos.Engine, event MoveMadeEvent) bool {
-	state := engine.GetState("game").(GameState)
+func (v *ValidMove) CheckTyped(engine *atmos.Engine, event MoveMadeEvent) error {
+	state, ok := atmos.StateOf[GameState](engine, "game")
+	if !ok {
+		return errors.New("no game state")
+	}
 
 	// Game must be started
 	if !state.GameStarted {
-		return false
+		return errors.New("game not started")
 	}
 
 	// Game must not be over
 	if state.IsGameOver() {
-		return false
+		return errors.New("game is over")
 	}
 
 	// Must be the correct player's turn
 	if event.Player != state.CurrentPlayer {
-		return false
+		return fmt.Errorf("not your turn (current player: %s)", state.CurrentPlayer)
 	}
 
 	// Position must be valid and empty
-	return state.IsPositionEmpty(event.Position)
+	if !state.IsPositionEmpty(event.Position) {
+		return fmt.Errorf("position %d is already occupied", event.Position)
+	}
+	return nil
 }
 
 // GameNotStarted validates that the game hasn't started yet
@@ -0,0 +1,116 @@
+package atmos
+
+import (
+	"fmt"
+	"strings"
+)
+
+// ValidatorReport describes how a single validator treats an event
+type ValidatorReport struct {
+	Name      string         // Human-readable validator name (the wrapped type for typed validators)
+	Validator EventValidator // The registered validator
+	Skipped   bool           // True if an exception skips this validator, or it is an external check
+	Reason    string         // Why it was skipped (the exception's Reason), or the validator's reason when it failed
+	Message   *Message       // The failure as a message key and parameters, when the validator gave one
+	Passed    bool           // True if the validator approved the event (always true when skipped)
+}
+
+// Explanation reports why an event would be accepted or rejected
+type Explanation struct {
+	EventType    string
+	Validators   []ValidatorReport // Every applicable validator (global first), in registration order
+	Accepted     bool              // True if every validator passed or was skipped
+	FirstFailure *ValidatorReport  // The validator Emit would stop at, or nil if accepted
+	Warnings     []Warning         // Objections from advisory validators (never affect Accepted)
+}
+
+// Explain evaluates an event against its validators and exceptions without
+// committing it. Unlike Emit it does not stop at the first failure, so every
+// validator is reported; validators are expected to be side-effect free.
+// Before hooks, listeners, and the repository are never touched, and
+// external checks (see External) aren't called.
+func (e *Engine) Explain(event Event) Explanation {
+	explanation := Explanation{
+		EventType: event.Type(),
+		Accepted:  true,
+	}
+
+	for _, validator := range e.validatorsFor(event.Type()) {
+		report := ValidatorReport{
+			Name:      validatorName(validator),
+			Validator: validator,
+		}
+
+		if exception, skip := e.applicableException(validator, event); skip {
+			report.Skipped = true
+			report.Reason = exception.Reason
+			report.Passed = true
+		} else if _, remote := validator.(*externalValidator); remote {
+			report.Skipped = true
+			report.Reason = "external checks don't run when explaining"
+			report.Passed = true
+		} else {
+			passed, err := e.check(validator, event)
+			report.Passed, report.Reason, report.Message = passed, e.reason(err), messageOf(err)
+		}
+
+		explanation.Validators = append(explanation.Validators, report)
+	}
+
+	explanation.Warnings = e.advise(event)
+
+	for i := range explanation.Validators {
+		if !explanation.Validators[i].Passed {
+			explanation.Accepted = false
+			explanation.FirstFailure = &explanation.Validators[i]
+			break
+		}
+	}
+
+	return explanation
+}
+
+// String renders the explanation as a human-readable multi-line report
+func (x Explanation) String() string {
+	var b strings.Builder
+	verdict := "accepted"
+	if !x.Accepted {
+		verdict = "rejected"
+	}
+	fmt.Fprintf(&b, "%s: %s\n", x.EventType, verdict)
+
+	for _, report := range x.Validators {
+		switch {
+		case report.Skipped:
+			fmt.Fprintf(&b, "  skip %s (%s)\n", report.Name, report.Reason)
+		case report.Passed:
+			fmt.Fprintf(&b, "  pass %s\n", report.Name)
+		case report.Reason != "":
+			fmt.Fprintf(&b, "  FAIL %s: %s\n", report.Name, report.Reason)
+		default:
+			fmt.Fprintf(&b, "  FAIL %s\n", report.Name)
+		}
+	}
+	for _, warning := range x.Warnings {
+		if warning.Reason != "" {
+			fmt.Fprintf(&b, "  warn %s: %s\n", warning.Validator, warning.Reason)
+		} else {
+			fmt.Fprintf(&b, "  warn %s\n", warning.Validator)
+		}
+	}
+
+	return b.String()
+}
+
+// namedValidator is implemented by wrappers that can name the validator they wrap
+type namedValidator interface {
+	validatorName() string
+}
+
+// validatorName returns a readable name for a validator, unwrapping typed wrappers
+func validatorName(validator EventValidator) string {
+	if named, ok := validator.(namedValidator); ok {
+		return named.validatorName()
+	}
+	return fmt.Sprintf("%T", validator)
+}
@@ -0,0 +1,54 @@
+package atmos
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// TestExplainReportsSkippedAndFailingValidators demonstrates querying exception reasons
+func TestExplainReportsSkippedAndFailingValidators(t *testing.T) {
+	engine := NewEngine()
+
+	requirePayment := NewTypedValidator(RequirePaymentValidator{})
+	positiveAmount := NewTypedValidator(TypedValidatorFunc[OrderPlacedEvent](func(e *Engine, event OrderPlacedEvent) bool {
+		return event.Amount >= 0
+	}))
+
+	engine.When("order_placed").
+		Requires(positiveAmount, requirePayment).
+		Except(requirePayment, func(e *Engine, event Event) bool {
+			return event.(OrderPlacedEvent).Amount == 0.0
+		}, "Free orders don't require payment validation")
+
+	// Paid order: payment validator runs and fails
+	paid := engine.Explain(OrderPlacedEvent{OrderID: "ORD-1", Amount: 10})
+	assert.False(t, paid.Accepted)
+	assert.Equal(t, "order_placed", paid.EventType)
+	assert.Len(t, paid.Validators, 2)
+	assert.True(t, paid.Validators[0].Passed)
+	assert.False(t, paid.Validators[1].Passed)
+	assert.False(t, paid.Validators[1].Skipped)
+	assert.Equal(t, "atmos.RequirePaymentValidator", paid.FirstFailure.Name)
+
+	// Free order: payment validator skipped with the documented reason
+	free := engine.Explain(OrderPlacedEvent{OrderID: "ORD-2", Amount: 0})
+	assert.True(t, free.Accepted)
+	assert.Nil(t, free.FirstFailure)
+	assert.True(t, free.Validators[1].Skipped)
+	assert.Equal(t, "Free orders don't require payment validation", free.Validators[1].Reason)
+	assert.Contains(t, free.String(), "skip atmos.RequirePaymentValidator (Free orders don't require payment validation)")
+
+	// Explain never commits
+	assert.Empty(t, engine.GetEvents())
+}
+
+// TestExplainWithoutValidators verifies events with no validators are accepted
+func TestExplainWithoutValidators(t *testing.T) {
+	engine := NewEngine()
+
+	explanation := engine.Explain(TestEvent{Name: "anything"})
+	assert.True(t, explanation.Accepted)
+	assert.Empty(t, explanation.Validators)
+	assert.Equal(t, "test_event: accepted\n", explanation.String())
+}
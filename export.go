@@ -0,0 +1,270 @@
+package atmos
+
+import (
+	"bytes"
+	"compress/gzip"
+	"encoding/binary"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"reflect"
+
+	"github.com/cumulusrpg/atmos/types"
+)
+
+// exportMagic starts every Export blob
+var exportMagic = []byte("ATMX")
+
+// exportFormat is the version of the blob layout written by Export
+const exportFormat uint16 = 1
+
+var (
+	// ErrInvalidExport is returned by Import for data that isn't an Export blob
+	ErrInvalidExport = errors.New("not an engine export")
+
+	// ErrExportSchema is returned by Import for blobs written with a newer
+	// schema version than the engine's (see WithSchemaVersion)
+	ErrExportSchema = errors.New("export schema version is newer than the engine's")
+)
+
+// exportBody is the compressed JSON content of an Export blob
+type exportBody struct {
+	SchemaVersion int                        `json:"schema_version"`
+	Sequence      int                        `json:"sequence"`             // records in the log, quarantined ones included
+	Identified    bool                       `json:"identified,omitempty"` // Log holds envelopes (see MarshalEnvelopes)
+	Log           json.RawMessage            `json:"log"`
+	Snapshots     map[string]json.RawMessage `json:"snapshots,omitempty"`
+	Quarantine    []exportQuarantined        `json:"quarantine,omitempty"` // Quarantined records of an identified log
+}
+
+// exportQuarantined is a quarantined record of an identified log, which
+// envelopes can't hold
+type exportQuarantined struct {
+	Position int             `json:"position"`
+	Record   json.RawMessage `json:"record"`
+}
+
+// WithSchemaVersion sets the game schema version stamped into exports
+// (default 0). Import refuses exports from newer versions.
+func WithSchemaVersion(version int) EngineOption {
+	return func(e *Engine) {
+		e.schemaVersion = version
+	}
+}
+
+// SchemaVersion returns the game schema version (see WithSchemaVersion)
+func (e *Engine) SchemaVersion() int {
+	return e.schemaVersion
+}
+
+// Export bundles the complete game (the event log with any event IDs,
+// metadata and quarantined records, state snapshots, the log length, and the
+// schema version) into a single versioned blob: the bytes "ATMX", a big-endian uint16 format
+// version, then gzip-compressed JSON. Load it with Import.
+func (e *Engine) Export() ([]byte, error) {
+	body := exportBody{SchemaVersion: e.schemaVersion}
+
+	var err error
+	if identified, ok := e.repository.(types.IdentifiedRepository); ok {
+		envelopes := identified.Envelopes(e)
+		body.Identified = true
+		body.Sequence = len(envelopes) + len(e.quarantined)
+		body.Log, err = e.MarshalEnvelopes(envelopes)
+		for _, record := range e.quarantined {
+			body.Quarantine = append(body.Quarantine, exportQuarantined{Position: record.Position, Record: record.Raw})
+		}
+	} else {
+		body.Sequence = len(e.GetEvents()) + len(e.quarantined)
+		body.Log, err = e.MarshalLog()
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	for _, state := range e.StateNames() {
+		if snapshot, exists := e.GetSnapshot(state); exists {
+			if body.Snapshots == nil {
+				body.Snapshots = make(map[string]json.RawMessage)
+			}
+			body.Snapshots[state] = snapshot
+		}
+	}
+
+	var buf bytes.Buffer
+	buf.Write(exportMagic)
+	_ = binary.Write(&buf, binary.BigEndian, exportFormat) // Writes to a bytes.Buffer don't fail
+	zw := gzip.NewWriter(&buf)
+	if err := json.NewEncoder(zw).Encode(body); err != nil {
+		return nil, err
+	}
+	if err := zw.Close(); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+// Import replaces the engine's log and snapshots with an Export blob, then
+// runs load hooks as SetEvents does. The log and snapshots are all decoded
+// first, so nothing changes if any of them can't be, and the previous
+// snapshots are put back if storing the log fails. Logs exported with
+// event IDs need a repository that stores them; their quarantined records
+// that now decode get new IDs.
+func (e *Engine) Import(data []byte) error {
+	body, err := decodeExport(data)
+	if err != nil {
+		return err
+	}
+	if body.SchemaVersion > e.schemaVersion {
+		return fmt.Errorf("%w: %d > %d", ErrExportSchema, body.SchemaVersion, e.schemaVersion)
+	}
+
+	var envelopes []Envelope
+	var events []Event
+	var records []QuarantinedRecord
+	if body.Identified {
+		envelopes, err = e.UnmarshalEnvelopes(body.Log)
+		if _, ok := e.repository.(types.IdentifiedRepository); !ok && err == nil {
+			err = ErrNoEventIDs
+		}
+		if err == nil {
+			envelopes, records = e.unquarantine(envelopes, body.Quarantine)
+		}
+	} else {
+		events, err = e.UnmarshalEvents(body.Log)
+		records = e.lastDecoded.records
+	}
+	if err != nil {
+		return err
+	}
+	if decoded := len(envelopes) + len(events) + len(records); decoded != body.Sequence {
+		return fmt.Errorf("%w: %d of %d events decoded", ErrInvalidExport, decoded, body.Sequence)
+	}
+
+	if err := e.checkSnapshots(body.Snapshots); err != nil {
+		return err
+	}
+
+	previous := make(map[string]json.RawMessage)
+	for _, state := range e.StateNames() {
+		if snapshot, exists := e.GetSnapshot(state); exists {
+			previous[state] = snapshot
+		}
+	}
+	err = e.applySnapshots(body.Snapshots)
+	if err == nil && body.Identified {
+		err = e.setEnvelopes(envelopes, records)
+	} else if err == nil {
+		err = e.SetEventsE(events)
+	}
+	if err != nil {
+		_ = e.applySnapshots(previous) // Best effort, reporting the original failure
+	}
+	return err
+}
+
+// applySnapshots stores snapshots of the engine's states, clearing those
+// of states without one
+func (e *Engine) applySnapshots(snapshots map[string]json.RawMessage) error {
+	for _, state := range e.StateNames() {
+		var err error
+		if snapshot, exists := snapshots[state]; exists {
+			err = e.SetSnapshot(state, snapshot)
+		} else if e.HasSnapshot(state) {
+			err = e.ClearSnapshot(state)
+		}
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// unquarantine puts an identified export's quarantined records back among
+// its envelopes: those that now decode get new IDs, the rest stay
+// quarantined (if quarantining is enabled)
+func (e *Engine) unquarantine(envelopes []Envelope, quarantine []exportQuarantined) ([]Envelope, []QuarantinedRecord) {
+	if len(quarantine) == 0 {
+		return envelopes, nil
+	}
+	merged := make([]Envelope, 0, len(envelopes)+len(quarantine))
+	var records []QuarantinedRecord
+	next := 0
+	for i := 0; i <= len(envelopes); i++ {
+		for next < len(quarantine) && (quarantine[next].Position <= i || i == len(envelopes)) {
+			record := quarantine[next].Record
+			next++
+			var wrapper struct {
+				Type string          `json:"type"`
+				Data json.RawMessage `json:"data"`
+			}
+			err := json.Unmarshal(record, &wrapper)
+			var event Event
+			if err == nil {
+				event, err = e.decodeRecord(wrapper.Type, wrapper.Data)
+			}
+			if err != nil {
+				records = e.quarantine(records, len(merged), wrapper.Type, record, err)
+				continue
+			}
+			merged = append(merged, Envelope{ID: e.nextID(), Event: event})
+		}
+		if i < len(envelopes) {
+			merged = append(merged, envelopes[i])
+		}
+	}
+	return merged, records
+}
+
+// checkSnapshots checks that the repository can store an export's snapshots
+// of the engine's states, and that each decodes as its state
+func (e *Engine) checkSnapshots(snapshots map[string]json.RawMessage) error {
+	_, stored := e.repository.(types.SnapshotRepository)
+	for _, state := range e.StateNames() {
+		snapshot, exists := snapshots[state]
+		if !exists {
+			continue
+		}
+		if !stored {
+			return fmt.Errorf("snapshot of %s: repository does not support snapshots", state)
+		}
+		var decoded interface{}
+		if initial := e.states[state].InitialState; initial != nil {
+			t := reflect.TypeOf(initial)
+			if t.Kind() == reflect.Ptr {
+				t = t.Elem()
+			}
+			decoded = reflect.New(t).Interface()
+		}
+		if err := json.Unmarshal(snapshot, &decoded); err != nil {
+			return fmt.Errorf("%w: snapshot of %s: %v", ErrInvalidExport, state, err)
+		}
+	}
+	return nil
+}
+
+// decodeExport checks an Export blob's header and decodes its body
+func decodeExport(data []byte) (exportBody, error) {
+	header := len(exportMagic) + 2
+	if len(data) < header || !bytes.Equal(data[:len(exportMagic)], exportMagic) {
+		return exportBody{}, ErrInvalidExport
+	}
+	if format := binary.BigEndian.Uint16(data[len(exportMagic):header]); format != exportFormat {
+		return exportBody{}, fmt.Errorf("%w: unsupported format version %d", ErrInvalidExport, format)
+	}
+
+	zr, err := gzip.NewReader(bytes.NewReader(data[header:]))
+	if err != nil {
+		return exportBody{}, fmt.Errorf("%w: %v", ErrInvalidExport, err)
+	}
+	content, err := io.ReadAll(zr)
+	if err != nil {
+		return exportBody{}, fmt.Errorf("%w: %v", ErrInvalidExport, err)
+	}
+
+	var body exportBody
+	if err := json.Unmarshal(content, &body); err != nil {
+		return exportBody{}, fmt.Errorf("%w: %v", ErrInvalidExport, err)
+	}
+	return body, nil
+}
@@ -0,0 +1,214 @@
+package atmos
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/cumulusrpg/atmos/repository"
+	"github.com/cumulusrpg/atmos/types"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type CoinsFoundEvent struct {
+	Coins int `json:"coins"`
+}
+
+func (e CoinsFoundEvent) Type() string { return "coins_found" }
+
+type Treasury struct {
+	Coins int `json:"coins"`
+}
+
+func (t Treasury) ApplyCoinsFound(e CoinsFoundEvent) Treasury {
+	return Treasury{Coins: t.Coins + e.Coins}
+}
+
+// newTreasuryEngine creates an engine counting found coins
+func newTreasuryEngine(repo types.EventRepository, opts ...EngineOption) *Engine {
+	engine := NewEngine(append([]EngineOption{WithRepository(repo)}, opts...)...)
+	engine.RegisterEventTypes(CoinsFoundEvent{})
+	if err := engine.RegisterProjection("treasury", Treasury{}); err != nil {
+		panic(err)
+	}
+	return engine
+}
+
+// TestExportImportRoundTrip verifies the log and snapshots travel in one blob
+func TestExportImportRoundTrip(t *testing.T) {
+	engine := newTreasuryEngine(repository.NewInMemorySnapshot(), WithSchemaVersion(2))
+	require.NoError(t, engine.SetSnapshot("treasury", Treasury{Coins: 100}))
+	engine.Emit(CoinsFoundEvent{Coins: 5})
+	engine.Emit(CoinsFoundEvent{Coins: 7})
+
+	blob, err := engine.Export()
+	require.NoError(t, err)
+	assert.Equal(t, "ATMX", string(blob[:4]))
+
+	restored := newTreasuryEngine(repository.NewInMemorySnapshot(), WithSchemaVersion(3))
+	restored.Emit(CoinsFoundEvent{Coins: 1000})
+	loaded := 0
+	restored.OnLoad(func(e *Engine) { loaded++ })
+
+	require.NoError(t, restored.Import(blob))
+	assert.Equal(t, Treasury{Coins: 112}, restored.GetState("treasury"))
+	assert.Len(t, restored.GetEvents(), 2)
+	assert.Equal(t, 1, loaded)
+}
+
+// TestExportKeepsEventIDs verifies identified logs keep their IDs and metadata
+func TestExportKeepsEventIDs(t *testing.T) {
+	engine := newTreasuryEngine(repository.NewIdentified())
+	engine.EmitWithMetadata(Metadata{"source": "chest"}, CoinsFoundEvent{Coins: 3})
+	id := engine.Envelopes()[0].ID
+
+	blob, err := engine.Export()
+	require.NoError(t, err)
+
+	restored := newTreasuryEngine(repository.NewIdentified())
+	require.NoError(t, restored.Import(blob))
+	assert.Equal(t, Metadata{"source": "chest"}, Metadata(restored.Envelopes()[0].Metadata))
+	assert.Equal(t, Treasury{Coins: 3}, restored.GetState("treasury"))
+	assert.True(t, restored.EmitWithID(id, CoinsFoundEvent{Coins: 3}).Duplicate)
+
+	assert.ErrorIs(t, newTreasuryEngine(repository.NewInMemory()).Import(blob), ErrNoEventIDs)
+}
+
+// TestImportRejectsBadBlobs verifies broken or newer blobs leave the engine untouched
+func TestImportRejectsBadBlobs(t *testing.T) {
+	source := newTreasuryEngine(repository.NewInMemory(), WithSchemaVersion(5))
+	source.Emit(CoinsFoundEvent{Coins: 1})
+	blob, err := source.Export()
+	require.NoError(t, err)
+
+	engine := newTreasuryEngine(repository.NewInMemory(), WithSchemaVersion(4))
+	engine.Emit(CoinsFoundEvent{Coins: 9})
+
+	assert.ErrorIs(t, engine.Import(blob), ErrExportSchema)
+	assert.ErrorIs(t, engine.Import([]byte(`[{"type":"coins_found"}]`)), ErrInvalidExport)
+	assert.ErrorIs(t, engine.Import(blob[:len(blob)-4]), ErrInvalidExport)
+
+	newer := append([]byte(nil), blob...)
+	newer[5] = 9
+	assert.ErrorContains(t, engine.Import(newer), "unsupported format version 9")
+
+	assert.Equal(t, Treasury{Coins: 9}, engine.GetState("treasury"))
+}
+
+// EmoteEvent is the event quarantined engines in these tests don't know
+type EmoteEvent struct {
+	Face string `json:"face"`
+}
+
+func (e EmoteEvent) Type() string { return "emote" }
+
+// TestExportCountsQuarantinedRecords verifies exports carry quarantined records and import where the types are known
+func TestExportCountsQuarantinedRecords(t *testing.T) {
+	engine := NewEngine(WithQuarantine())
+	engine.RegisterEventTypes(RoundPlayedEvent{})
+	events, err := engine.UnmarshalEvents([]byte(mixedLog))
+	require.NoError(t, err)
+	engine.SetEvents(events)
+
+	data, err := engine.Export()
+	require.NoError(t, err)
+
+	same := NewEngine(WithQuarantine())
+	same.RegisterEventTypes(RoundPlayedEvent{})
+	require.NoError(t, same.Import(data))
+	assert.Len(t, same.GetEvents(), 2)
+	assert.Len(t, same.Quarantined(), 3)
+
+	newer := NewEngine(WithQuarantine())
+	newer.RegisterEventTypes(RoundPlayedEvent{}, EmoteEvent{})
+	require.NoError(t, newer.Import(data))
+	assert.Len(t, newer.GetEvents(), 4, "Both emotes decode")
+	assert.Len(t, newer.Quarantined(), 1, "The malformed round is still quarantined")
+
+	assert.ErrorIs(t, NewEngine().Import(data), ErrInvalidExport, "Records can't be dropped silently")
+}
+
+// TestImportChecksSnapshotsFirst verifies snapshots that can't be stored or decoded leave the engine untouched
+func TestImportChecksSnapshotsFirst(t *testing.T) {
+	source := newTreasuryEngine(repository.NewInMemorySnapshot())
+	require.NoError(t, source.SetSnapshot("treasury", Treasury{Coins: 100}))
+	source.Emit(CoinsFoundEvent{Coins: 1})
+	blob, err := source.Export()
+	require.NoError(t, err)
+
+	unsnapshotted := newTreasuryEngine(repository.NewInMemory())
+	unsnapshotted.Emit(CoinsFoundEvent{Coins: 9})
+	assert.ErrorContains(t, unsnapshotted.Import(blob), "repository does not support snapshots")
+	assert.Equal(t, Treasury{Coins: 9}, unsnapshotted.GetState("treasury"))
+
+	renamed := NewEngine(WithRepository(repository.NewInMemorySnapshot()))
+	renamed.RegisterEventTypes(CoinsFoundEvent{})
+	renamed.RegisterState("treasury", "")
+	require.NoError(t, renamed.SetSnapshot("treasury", "empty"))
+	renamed.Emit(CoinsFoundEvent{Coins: 9})
+	assert.ErrorIs(t, renamed.Import(blob), ErrInvalidExport)
+	assert.Len(t, renamed.GetEvents(), 1)
+	snapshot, _ := renamed.GetSnapshot("treasury")
+	assert.Equal(t, `"empty"`, string(snapshot))
+}
+
+// TestExportKeepsQuarantinedRecordsOfIdentifiedLogs verifies identified exports carry quarantined records too
+func TestExportKeepsQuarantinedRecordsOfIdentifiedLogs(t *testing.T) {
+	engine := NewEngine(WithRepository(repository.NewIdentified()), WithQuarantine())
+	engine.RegisterEventTypes(RoundPlayedEvent{})
+	events, err := engine.UnmarshalEvents([]byte(mixedLog))
+	require.NoError(t, err)
+	require.NoError(t, engine.SetEventsE(events))
+	require.Len(t, engine.Quarantined(), 3)
+
+	data, err := engine.Export()
+	require.NoError(t, err)
+
+	same := NewEngine(WithRepository(repository.NewIdentified()), WithQuarantine())
+	same.RegisterEventTypes(RoundPlayedEvent{})
+	require.NoError(t, same.Import(data))
+	assert.Len(t, same.Envelopes(), 2)
+	assert.Equal(t, engine.Quarantined()[1].Raw, same.Quarantined()[1].Raw)
+	log, err := same.MarshalLog()
+	require.NoError(t, err)
+	assert.JSONEq(t, mixedLog, string(log))
+
+	newer := NewEngine(WithRepository(repository.NewIdentified()), WithQuarantine())
+	newer.RegisterEventTypes(RoundPlayedEvent{}, EmoteEvent{})
+	require.NoError(t, newer.Import(data))
+	envelopes := newer.Envelopes()
+	require.Len(t, envelopes, 4, "Both emotes decode")
+	assert.Equal(t, &EmoteEvent{Face: ":)"}, envelopes[0].Event)
+	assert.NotEmpty(t, envelopes[0].ID)
+	assert.Len(t, newer.Quarantined(), 1)
+
+	unquarantined := NewEngine(WithRepository(repository.NewIdentified()))
+	unquarantined.RegisterEventTypes(RoundPlayedEvent{})
+	assert.ErrorIs(t, unquarantined.Import(data), ErrInvalidExport, "Records can't be dropped silently")
+}
+
+// unsavableSnapshotRepository stores snapshots but refuses to replace its log
+type unsavableSnapshotRepository struct {
+	*repository.InMemorySnapshot
+}
+
+func (r unsavableSnapshotRepository) SetAll(engine types.Engine, events []types.Event) error {
+	return errors.New("disk full")
+}
+
+// TestImportRestoresSnapshotsWhenTheLogFails verifies a failed import leaves the previous snapshots in place
+func TestImportRestoresSnapshotsWhenTheLogFails(t *testing.T) {
+	source := newTreasuryEngine(repository.NewInMemorySnapshot())
+	require.NoError(t, source.SetSnapshot("treasury", Treasury{Coins: 100}))
+	blob, err := source.Export()
+	require.NoError(t, err)
+
+	engine := newTreasuryEngine(unsavableSnapshotRepository{repository.NewInMemorySnapshot()})
+	require.NoError(t, engine.SetSnapshot("treasury", Treasury{Coins: 7}))
+	engine.Emit(CoinsFoundEvent{Coins: 1})
+
+	assert.ErrorContains(t, engine.Import(blob), "disk full")
+	snapshot, _ := engine.GetSnapshot("treasury")
+	assert.JSONEq(t, `{"coins":7}`, string(snapshot))
+	assert.Equal(t, Treasury{Coins: 8}, engine.GetState("treasury"))
+}
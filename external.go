@@ -0,0 +1,169 @@
+package atmos
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/cumulusrpg/atmos/types"
+)
+
+// ErrValidatorTimeout is the rejection reason of a fail-closed external
+// check that didn't answer in time
+var ErrValidatorTimeout = errors.New("validator timed out")
+
+// ExternalCheck asks a remote service (entitlements, anti-cheat) whether an
+// event may be emitted, returning an error to reject it. It runs on its own
+// goroutine, so it must not use the engine; request-scoped values from
+// EmitCtx are available through ctx (see ContextValue).
+type ExternalCheck func(ctx context.Context, event Event) error
+
+// TimeoutPolicy decides what happens when an external check doesn't answer in time
+type TimeoutPolicy int
+
+const (
+	FailClosed TimeoutPolicy = iota // Reject the event (the default)
+	FailOpen                        // Accept the event as if the check passed
+)
+
+// ExternalOption configures an external validator
+type ExternalOption func(*externalValidator)
+
+// Timeout sets how long an external check may take (default 5s)
+func Timeout(d time.Duration) ExternalOption {
+	return func(v *externalValidator) {
+		v.timeout = d
+	}
+}
+
+// WhenTimedOut sets the timeout policy (default FailClosed)
+func WhenTimedOut(policy TimeoutPolicy) ExternalOption {
+	return func(v *externalValidator) {
+		v.policy = policy
+	}
+}
+
+// ReportTimeouts sets a function called whenever the check times out,
+// whatever the policy, e.g. to count failures of the remote service. It may
+// be called concurrently for checks of the same emit.
+func ReportTimeouts(report func(event Event)) ExternalOption {
+	return func(v *externalValidator) {
+		v.report = report
+	}
+}
+
+// External adapts a remote check into a validator named "external: name".
+// The check gets the emit's context (see EmitCtx) with the timeout applied;
+// when it expires, or the emit's context is canceled first, the timeout
+// policy decides the outcome and the check is abandoned.
+//
+// External checks run once the engine's other validators have approved the
+// event, all at the same time (for EmitAll, those of every event in the
+// batch), so their timeouts overlap rather than add up. Explain reports
+// them as skipped without calling them.
+// Usage: When("item_bought").Requires(External("entitlements", checkEntitled, Timeout(time.Second)))
+func External(name string, check ExternalCheck, opts ...ExternalOption) EventValidator {
+	v := &externalValidator{name: name, check: check, timeout: 5 * time.Second}
+
+	// Apply options
+	for _, opt := range opts {
+		opt(v)
+	}
+
+	return v
+}
+
+// externalValidator runs an ExternalCheck with a deadline
+type externalValidator struct {
+	name    string
+	check   ExternalCheck
+	timeout time.Duration
+	policy  TimeoutPolicy
+	report  func(event Event)
+}
+
+// Validate implements EventValidator
+func (v *externalValidator) Validate(engine types.Engine, event Event) bool {
+	return v.Check(engine, event) == nil
+}
+
+// Check implements ReasonedValidator
+func (v *externalValidator) Check(engine types.Engine, event Event) error {
+	ctx, cancel := context.WithTimeout(engine.(*Engine).Context(), v.timeout)
+	defer cancel()
+
+	answer := make(chan error, 1) // Buffered so an abandoned check can still finish
+	go func() { answer <- v.check(ctx, event) }()
+
+	select {
+	case err := <-answer:
+		if err == nil || ctx.Err() == nil {
+			return err
+		}
+		// The check gave up at the deadline; the policy decides
+	case <-ctx.Done():
+	}
+
+	if v.report != nil {
+		v.report(event)
+	}
+	if v.policy == FailOpen {
+		return nil
+	}
+	return fmt.Errorf("%w: external check %s: %v", ErrValidatorTimeout, v.name, ctx.Err())
+}
+
+func (v *externalValidator) validatorName() string {
+	return "external: " + v.name
+}
+
+// remoteCheck is an external check due for an event
+type remoteCheck struct {
+	validator *externalValidator
+	event     Event
+	position  int // the event's position in its batch
+}
+
+// remoteChecks returns an event's external checks that no exception skips
+func (e *Engine) remoteChecks(event Event, position int) []remoteCheck {
+	var checks []remoteCheck
+	for _, validator := range e.validatorsFor(event.Type()) {
+		external, ok := validator.(*externalValidator)
+		if !ok {
+			continue
+		}
+		if _, skip := e.applicableException(validator, event); !skip {
+			checks = append(checks, remoteCheck{validator: external, event: event, position: position})
+		}
+	}
+	return checks
+}
+
+// checkRemote runs external checks concurrently, returning the first (in
+// order) that rejects its event with the rejection
+func (e *Engine) checkRemote(eventType string, checks []remoteCheck) (*remoteCheck, error) {
+	if len(checks) == 0 {
+		return nil, nil
+	}
+	defer e.begin(StageValidator, "external checks", eventType)()
+
+	errs := make([]error, len(checks))
+	var wg sync.WaitGroup
+	for i, check := range checks {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			errs[i] = check.validator.Check(e, check.event)
+		}()
+	}
+	wg.Wait()
+
+	for i, err := range errs {
+		if err != nil {
+			return &checks[i], err
+		}
+	}
+	return nil, nil
+}
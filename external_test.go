@@ -0,0 +1,138 @@
+package atmos
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// entitlementCheck approves moves by players holding the "premium" flag,
+// waiting for delay (or the deadline) first
+func entitlementCheck(delay time.Duration) ExternalCheck {
+	return func(ctx context.Context, event Event) error {
+		select {
+		case <-time.After(delay):
+		case <-ctx.Done():
+			return ctx.Err()
+		}
+		if flags, _ := ContextValue[Flags](ctx); !flags.Enabled("premium") {
+			return errors.New("premium required")
+		}
+		return nil
+	}
+}
+
+// TestExternalValidatorAnswers verifies remote checks see the emit's context and explain rejections
+func TestExternalValidatorAnswers(t *testing.T) {
+	engine := NewEngine()
+	engine.When("move").Requires(External("entitlements", entitlementCheck(0)))
+
+	premium := WithValue(context.Background(), Flags{"premium": true})
+	assert.True(t, engine.EmitCtx(premium, MoveEvent{Player: "alice"}).Accepted)
+
+	result := engine.EmitWithResult(MoveEvent{Player: "bob"})
+	assert.False(t, result.Accepted)
+	assert.Equal(t, "external: entitlements", result.RejectedBy)
+	assert.Equal(t, "premium required", result.Reason)
+}
+
+// TestExternalValidatorTimeouts verifies the timeout policy decides slow checks
+func TestExternalValidatorTimeouts(t *testing.T) {
+	timeouts := 0
+	report := ReportTimeouts(func(event Event) { timeouts++ })
+	premium := WithValue(context.Background(), Flags{"premium": true})
+
+	closed := NewEngine()
+	closed.When("move").Requires(External("anticheat", entitlementCheck(time.Second), Timeout(10*time.Millisecond), report))
+	result := closed.EmitCtx(premium, MoveEvent{Player: "alice"})
+	assert.False(t, result.Accepted)
+	assert.Contains(t, result.Reason, "validator timed out: external check anticheat")
+
+	open := NewEngine()
+	open.When("move").Requires(External("anticheat", entitlementCheck(time.Second), Timeout(10*time.Millisecond), WhenTimedOut(FailOpen), report))
+	assert.True(t, open.EmitCtx(premium, MoveEvent{Player: "alice"}).Accepted)
+	assert.Equal(t, 2, timeouts)
+}
+
+// TestExternalValidatorHonorsCanceledEmits verifies a canceled emit context ends the check
+func TestExternalValidatorHonorsCanceledEmits(t *testing.T) {
+	engine := NewEngine()
+	engine.When("move").Requires(External("entitlements", entitlementCheck(time.Second)))
+
+	ctx, cancel := context.WithCancel(WithValue(context.Background(), Flags{"premium": true}))
+	time.AfterFunc(10*time.Millisecond, cancel)
+	start := time.Now()
+	result := engine.EmitCtx(ctx, MoveEvent{Player: "alice"})
+	assert.False(t, result.Accepted)
+	assert.Less(t, time.Since(start), time.Second)
+}
+
+// rendezvous returns two checks that each wait for the other to start, so
+// they only pass when run at the same time
+func rendezvous() (ExternalCheck, ExternalCheck) {
+	first, second := make(chan struct{}), make(chan struct{})
+	meet := func(mine, theirs chan struct{}) ExternalCheck {
+		return func(ctx context.Context, event Event) error {
+			close(mine)
+			select {
+			case <-theirs:
+				return nil
+			case <-ctx.Done():
+				return ctx.Err()
+			}
+		}
+	}
+	return meet(first, second), meet(second, first)
+}
+
+// TestExternalChecksRunTogether verifies an emit's external checks overlap instead of running one after another
+func TestExternalChecksRunTogether(t *testing.T) {
+	engine := NewEngine()
+	entitlements, anticheat := rendezvous()
+	engine.When("move").
+		Requires(External("entitlements", entitlements, Timeout(time.Second))).
+		Requires(External("anticheat", anticheat, Timeout(time.Second)))
+
+	assert.True(t, engine.EmitWithResult(MoveEvent{Player: "alice"}).Accepted)
+}
+
+// TestExternalChecksRunAfterLocalValidators verifies remote services aren't called for events the engine rejects anyway
+func TestExternalChecksRunAfterLocalValidators(t *testing.T) {
+	calls := 0
+	engine := NewEngine()
+	engine.When("move").
+		Requires(External("entitlements", func(ctx context.Context, event Event) error {
+			calls++
+			return nil
+		})).
+		Requires(rejectEverything{})
+
+	assert.Equal(t, "atmos.rejectEverything", engine.EmitWithResult(MoveEvent{Player: "alice"}).RejectedBy)
+	assert.Zero(t, calls)
+
+	explanation := engine.Explain(MoveEvent{Player: "alice"})
+	assert.True(t, explanation.Validators[0].Skipped, "Explaining doesn't call remote services")
+	assert.Zero(t, calls)
+}
+
+// TestExternalChecksOfBatchesRunTogether verifies EmitAll runs the checks of every event at once
+func TestExternalChecksOfBatchesRunTogether(t *testing.T) {
+	engine := NewEngine()
+	first, second := rendezvous()
+	checks := map[string]ExternalCheck{"alice": first, "bob": second}
+	engine.When("move").Requires(External("anticheat", func(ctx context.Context, event Event) error {
+		return checks[event.(MoveEvent).Player](ctx, event)
+	}, Timeout(time.Second)))
+
+	assert.True(t, engine.EmitAll(MoveEvent{Player: "alice"}, MoveEvent{Player: "bob"}).Accepted)
+
+	refusing := NewEngine()
+	refusing.When("move").Requires(External("entitlements", entitlementCheck(0)))
+	result := refusing.EmitAll(MoveEvent{Player: "alice"})
+	assert.Equal(t, "external: entitlements", result.RejectedBy)
+	assert.Equal(t, "event 0 (move): premium required", result.Reason)
+	assert.Empty(t, refusing.GetEvents())
+}
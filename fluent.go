@@ -1,5 +1,7 @@
 package atmos
 
+import "reflect"
+
 // EventRegistration provides a fluent API for configuring event handlers
 type EventRegistration struct {
 	engine    *Engine
@@ -39,12 +41,22 @@ func (r *EventRegistration) WithListener(listener EventListener) *EventRegistrat
 // reducer is the function that updates that state
 func (r *EventRegistration) WithReducer(stateName string, reducer StateReducer) *EventRegistration {
 	// Get existing state registry
-	if registry, exists := r.engine.states[stateName]; exists {
-		// Add reducer to existing registry
-		registry.Reducers[r.eventType] = reducer
-		r.engine.states[stateName] = registry
+	states := r.engine.mutableRegistrations().states
+	if registry, exists := states[stateName]; exists {
+		if _, duplicate := registry.Reducers[r.eventType]; duplicate &&
+			!r.engine.duplicate(Duplicate{Kind: "reducer", EventType: r.eventType, State: stateName}) {
+			return r
+		}
+
+		// Add reducer to existing registry, inside any middleware for the state
+		registry.Reducers[r.eventType] = r.engine.wrapRegistered(stateName, reducer)
+		states[stateName] = registry
+		return r
 	}
 	// If state doesn't exist, this is a no-op (state must be registered first)
+	if r.engine.strictRegistration {
+		r.engine.misconfigured("reducer for %s updates state %q, which is not registered", r.eventType, stateName)
+	}
 	return r
 }
 
@@ -67,6 +79,17 @@ func (e *Engine) When(eventType string, factory ...func() Event) *EventRegistrat
 	return reg
 }
 
+// WhenEvent starts a registration for event type T, taking the type string
+// from T's Type() and registering its factory, so the string and the Go
+// type can't disagree. T may be a value or a pointer type; either way the
+// factory decodes into a new pointer, as with RegisterEventTypes.
+// Usage: WhenEvent[OrderPlacedEvent](engine).Requires(...).Then(...)
+func WhenEvent[T Event](engine *Engine) *EventRegistration {
+	prototype := zeroEvent(reflect.TypeOf((*T)(nil)).Elem())
+	engine.RegisterEventTypes(prototype)
+	return engine.Event(prototype.Type())
+}
+
 // Requires is an alias for WithValidator() to read like a requirement
 // Accepts multiple validators for convenience
 // Usage: When("player_registered").Requires(Valid(&MyValidator{}), Valid(&AnotherValidator{}))
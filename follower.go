@@ -0,0 +1,217 @@
+package atmos
+
+import (
+	"bytes"
+	"context"
+	"reflect"
+	"sync"
+	"time"
+
+	"github.com/cumulusrpg/atmos/types"
+)
+
+// Follower keeps a read replica of a primary engine's log, so reads for big
+// lobbies or leaderboards can be spread over many engines. The follower's
+// engine needs the primary's states, reducers, and event types; it refuses
+// emits (ErrReadOnly, see WithReadOnly), and its listeners don't run for followed events.
+//
+// Events arrive either by subscribing to a primary in the same process:
+//
+//	follower := atmos.NewFollower(readEngine)
+//	follower.Follow(primary)
+//
+// or by polling the primary's repository, e.g. a shared database:
+//
+//	go follower.Run(ctx, sharedRepo, time.Second)
+//
+// Follower methods are safe for concurrent use, so one goroutine can poll
+// while others read. Read the engine only through the follower.
+type Follower struct {
+	mu       sync.Mutex
+	engine   *Engine
+	position int   // events in the follower's log
+	last     Event // the last of them, to notice the source's log being replaced
+}
+
+// NewFollower makes an engine a read-only follower, starting from its current log
+func NewFollower(engine *Engine) *Follower {
+	engine.readOnly = true
+	f := &Follower{engine: engine}
+	f.held(engine.GetEvents())
+	return f
+}
+
+// Follow subscribes to a primary engine in the same process: the follower
+// catches up with the primary's log and snapshots now, receives each event
+// as the primary commits it, and resyncs whenever the primary's log is
+// replaced (see SetEvents). Primary and follower must not emit concurrently
+// with each other's setup, as with Forward.
+func (f *Follower) Follow(primary *Engine) error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	if err := f.resync(primary.GetEvents(), primary.repository); err != nil {
+		return err
+	}
+	// Registrations are shared with the primary's forks and Blueprint
+	// siblings, so the listener and hook ignore every engine but the primary
+	primary.RegisterListener(AnyEvent, &followListener{follower: f, primary: primary})
+	primary.OnLoad(func(engine *Engine) {
+		if engine != primary {
+			return
+		}
+		f.mu.Lock()
+		defer f.mu.Unlock()
+		_ = f.resync(engine.GetEvents(), engine.repository) // Retried by the next resync
+	})
+	return nil
+}
+
+// Poll appends the events committed to the primary's repository since the
+// last poll, returning how many arrived. Snapshots are mirrored when both
+// repositories support them. If the source's log is shorter than the
+// follower's, or holds a different event where the follower's ends, it was
+// replaced, and the follower reloads it in full.
+func (f *Follower) Poll(source types.EventRepository) (int, error) {
+	events := source.GetAll(f.engine)
+
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	if len(events) < f.position || f.position > 0 && !reflect.DeepEqual(events[f.position-1], f.last) {
+		if err := f.resync(events, source); err != nil {
+			return 0, err
+		}
+		return len(events), nil
+	}
+
+	if err := f.mirrorSnapshots(source); err != nil {
+		return 0, err
+	}
+	added := 0
+	for _, event := range events[f.position:] {
+		if err := f.append(event); err != nil {
+			return added, err
+		}
+		added++
+	}
+	return added, nil
+}
+
+// Run polls the source every interval until ctx is done, returning the
+// first polling error
+func (f *Follower) Run(ctx context.Context, source types.EventRepository, interval time.Duration) error {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		if _, err := f.Poll(source); err != nil {
+			return err
+		}
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-ticker.C:
+		}
+	}
+}
+
+// Position returns the number of events the follower holds
+func (f *Follower) Position() int {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	return f.position
+}
+
+// GetState returns a state projected from the followed log
+func (f *Follower) GetState(name string) interface{} {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	return f.engine.GetState(name)
+}
+
+// Read runs fn with exclusive access to the follower's engine, for reads
+// beyond GetState (GetStateFor, Select, GetEvents)
+func (f *Follower) Read(fn func(engine *Engine)) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	fn(f.engine)
+}
+
+// append adds one followed event to the log (with f.mu held)
+func (f *Follower) append(event Event) error {
+	if err := f.engine.repository.Add(f.engine, event); err != nil {
+		return err
+	}
+	f.engine.revision++
+	f.position++
+	f.last = event
+	return nil
+}
+
+// resync replaces the follower's log and snapshots with the primary's
+// (with f.mu held)
+func (f *Follower) resync(events []Event, source types.EventRepository) error {
+	if err := f.mirrorSnapshots(source); err != nil {
+		return err
+	}
+	if err := f.engine.repository.SetAll(f.engine, events); err != nil {
+		return err
+	}
+	f.held(events)
+	f.engine.loaded(len(events))
+	return nil
+}
+
+// held records the log the follower holds
+func (f *Follower) held(events []Event) {
+	f.position, f.last = len(events), nil
+	if len(events) > 0 {
+		f.last = events[len(events)-1]
+	}
+}
+
+// mirrorSnapshots copies the source's snapshots, if both repositories keep
+// them (with f.mu held)
+func (f *Follower) mirrorSnapshots(source types.EventRepository) error {
+	from, ok := source.(types.SnapshotRepository)
+	if !ok {
+		return nil
+	}
+	to, ok := f.engine.repository.(types.SnapshotRepository)
+	if !ok {
+		return nil
+	}
+	for _, state := range f.engine.StateNames() {
+		snapshot, exists := from.GetSnapshot(state)
+		current, held := to.GetSnapshot(state)
+		var err error
+		switch {
+		case exists && (!held || !bytes.Equal(snapshot, current)):
+			err = to.SetSnapshot(state, snapshot)
+		case !exists && held:
+			err = to.ClearSnapshot(state)
+		default:
+			continue
+		}
+		if err != nil {
+			return err
+		}
+		f.engine.revision++
+	}
+	return nil
+}
+
+// followListener feeds a primary's commits to a follower
+type followListener struct {
+	follower *Follower
+	primary  *Engine
+}
+
+// Handle implements EventListener
+func (l *followListener) Handle(engine types.Engine, event Event) {
+	if engine != types.Engine(l.primary) {
+		return
+	}
+	f := l.follower
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	_ = f.append(event) // A follower that falls behind catches up on the next resync
+}
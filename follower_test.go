@@ -0,0 +1,128 @@
+package atmos
+
+import (
+	"context"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/cumulusrpg/atmos/repository"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// TestFollowerSubscribesToPrimary verifies an in-process follower tracks commits and reloads
+func TestFollowerSubscribesToPrimary(t *testing.T) {
+	primary := newTreasuryEngine(repository.NewInMemory())
+	primary.Emit(CoinsFoundEvent{Coins: 2})
+
+	follower := NewFollower(newTreasuryEngine(repository.NewInMemory()))
+	require.NoError(t, follower.Follow(primary))
+	assert.Equal(t, Treasury{Coins: 2}, follower.GetState("treasury"), "Caught up on follow")
+
+	primary.Emit(CoinsFoundEvent{Coins: 3})
+	assert.Equal(t, Treasury{Coins: 5}, follower.GetState("treasury"))
+	assert.Equal(t, 2, follower.Position())
+
+	primary.SetEvents([]Event{CoinsFoundEvent{Coins: 40}})
+	assert.Equal(t, Treasury{Coins: 40}, follower.GetState("treasury"))
+	assert.Equal(t, 1, follower.Position())
+}
+
+// TestFollowerRefusesEmits verifies followers are read-only
+func TestFollowerRefusesEmits(t *testing.T) {
+	engine := newTreasuryEngine(repository.NewInMemory())
+	follower := NewFollower(engine)
+
+	assert.ErrorIs(t, engine.EmitWithResult(CoinsFoundEvent{Coins: 1}).Err, ErrReadOnly)
+	follower.Read(func(engine *Engine) {
+		assert.Empty(t, engine.GetEvents())
+	})
+}
+
+// TestFollowerPollsRepository verifies polling appends new events, mirrors snapshots, and reloads replaced logs
+func TestFollowerPollsRepository(t *testing.T) {
+	shared := repository.NewInMemorySnapshot()
+	primary := newTreasuryEngine(shared)
+	follower := NewFollower(newTreasuryEngine(repository.NewInMemorySnapshot()))
+
+	primary.Emit(CoinsFoundEvent{Coins: 1})
+	primary.Emit(CoinsFoundEvent{Coins: 2})
+	added, err := follower.Poll(shared)
+	require.NoError(t, err)
+	assert.Equal(t, 2, added)
+
+	require.NoError(t, primary.SetSnapshot("treasury", Treasury{Coins: 10}))
+	primary.Emit(CoinsFoundEvent{Coins: 4})
+	added, err = follower.Poll(shared)
+	require.NoError(t, err)
+	assert.Equal(t, 1, added)
+	assert.Equal(t, Treasury{Coins: 17}, follower.GetState("treasury"))
+
+	added, err = follower.Poll(shared)
+	require.NoError(t, err)
+	assert.Zero(t, added)
+
+	primary.SetEvents([]Event{CoinsFoundEvent{Coins: 5}})
+	_, err = follower.Poll(shared)
+	require.NoError(t, err)
+	assert.Equal(t, Treasury{Coins: 15}, follower.GetState("treasury"))
+}
+
+// TestFollowerIgnoresForksOfThePrimary verifies forks, which share the primary's registrations, don't feed the follower
+func TestFollowerIgnoresForksOfThePrimary(t *testing.T) {
+	primary := newTreasuryEngine(repository.NewInMemory())
+	primary.Emit(CoinsFoundEvent{Coins: 2})
+	follower := NewFollower(newTreasuryEngine(repository.NewInMemory()))
+	require.NoError(t, follower.Follow(primary))
+
+	fork := primary.Fork()
+	fork.Emit(CoinsFoundEvent{Coins: 100})
+	fork.SetEvents(nil)
+	assert.Equal(t, Treasury{Coins: 2}, follower.GetState("treasury"))
+	assert.Equal(t, 1, follower.Position())
+}
+
+// TestFollowerNoticesReplacedLogsOfTheSameLength verifies a replaced log is reloaded even if it didn't shrink
+func TestFollowerNoticesReplacedLogsOfTheSameLength(t *testing.T) {
+	shared := repository.NewInMemory()
+	primary := newTreasuryEngine(shared)
+	follower := NewFollower(newTreasuryEngine(repository.NewInMemory()))
+	primary.Emit(CoinsFoundEvent{Coins: 1})
+	_, err := follower.Poll(shared)
+	require.NoError(t, err)
+
+	primary.SetEvents([]Event{CoinsFoundEvent{Coins: 5}, CoinsFoundEvent{Coins: 6}})
+	added, err := follower.Poll(shared)
+	require.NoError(t, err)
+	assert.Equal(t, 2, added)
+	assert.Equal(t, Treasury{Coins: 11}, follower.GetState("treasury"))
+}
+
+// TestFollowerRunServesConcurrentReads verifies reads are safe while a poller runs
+func TestFollowerRunServesConcurrentReads(t *testing.T) {
+	shared := repository.NewInMemory()
+	primary := newTreasuryEngine(shared)
+	for i := 0; i < 10; i++ {
+		primary.Emit(CoinsFoundEvent{Coins: 1})
+	}
+	follower := NewFollower(newTreasuryEngine(repository.NewInMemory()))
+
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan error)
+	go func() { done <- follower.Run(ctx, shared, time.Millisecond) }()
+
+	var wg sync.WaitGroup
+	for i := 0; i < 4; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for follower.GetState("treasury").(Treasury).Coins < 10 {
+				time.Sleep(time.Millisecond)
+			}
+		}()
+	}
+	wg.Wait()
+	cancel()
+	assert.ErrorIs(t, <-done, context.Canceled)
+}
@@ -0,0 +1,128 @@
+package atmos
+
+import (
+	"context"
+	"errors"
+	"fmt"
+)
+
+var (
+	// ErrRejected is wrapped by Game.EmitErr errors for events a validator
+	// rejected without a mapped error (see Game.MapRejection)
+	ErrRejected = errors.New("event rejected")
+
+	// ErrQueued is returned by Game.EmitErr for events held to be processed
+	// later, which may still be rejected (see EmitResult.Queued)
+	ErrQueued = errors.New("event queued, not yet validated")
+)
+
+// Game is a base for game types: an engine with one main state of type
+// TState, plus the wrapper code every game otherwise writes itself. Embed
+// it in the game's type and add a method per command:
+//
+//	type Chess struct{ *atmos.Game[Board] }
+//
+//	func (c *Chess) Move(from, to string) error {
+//		return c.EmitErr(MoveEvent{From: from, To: to})
+//	}
+type Game[TState any] struct {
+	*Engine
+	state      string
+	rejections map[string]error // validator name -> error returned for its rejections
+}
+
+// NewGame creates an engine with the main state registered under name
+func NewGame[TState any](name string, initial TState, opts ...EngineOption) *Game[TState] {
+	engine := NewEngine(opts...)
+	engine.RegisterState(name, initial)
+	return &Game[TState]{Engine: engine, state: name, rejections: make(map[string]error)}
+}
+
+// StateOf returns a state, typed, or false if there is no such state or it
+// isn't a T.
+// Usage: score, ok := atmos.StateOf[Score](engine, "score")
+func StateOf[T any](engine *Engine, name string) (T, bool) {
+	state, ok := engine.GetState(name).(T)
+	return state, ok
+}
+
+// State returns the main state
+func (g *Game[TState]) State() TState {
+	state, _ := StateOf[TState](g.Engine, g.state) // Registered by NewGame
+	return state
+}
+
+// MapRejection makes EmitErr return err (wrapped with the rejection
+// reason, if any) when validator rejects an event, so callers can test for
+// it with errors.Is (chainable)
+func (g *Game[TState]) MapRejection(validator EventValidator, err error) *Game[TState] {
+	g.rejections[validatorName(validator)] = err
+	return g
+}
+
+// EmitErr emits an event, returning an error unless it was accepted: the
+// emit's Err, ErrQueued, the error mapped to the rejecting validator, or
+// ErrRejected wrapped with the reason
+func (g *Game[TState]) EmitErr(event Event) error {
+	return g.rejection(g.EmitWithResult(event))
+}
+
+// EmitAsErr emits an event as an actor like EmitErr (see EmitAs)
+func (g *Game[TState]) EmitAsErr(actor string, event Event) error {
+	return g.rejection(g.EmitAsWithResult(actor, event))
+}
+
+// rejection maps an emit result to EmitErr's error
+func (g *Game[TState]) rejection(result EmitResult) error {
+	switch {
+	case result.Accepted:
+		return nil
+	case result.Err != nil:
+		return result.Err
+	case result.Queued:
+		return ErrQueued
+	}
+	if err, mapped := g.rejections[result.RejectedBy]; mapped {
+		if result.Reason == "" {
+			return err
+		}
+		return fmt.Errorf("%w: %s", err, result.Reason)
+	}
+	if result.Reason == "" {
+		return fmt.Errorf("%w by %s", ErrRejected, result.RejectedBy)
+	}
+	return fmt.Errorf("%w: %s", ErrRejected, result.Reason)
+}
+
+// Save returns the whole game read from the engine's repository, for Load
+// (see Export)
+func (g *Game[TState]) Save() ([]byte, error) {
+	return g.Export()
+}
+
+// Load replaces the game with a Save (see Import)
+func (g *Game[TState]) Load(save []byte) error {
+	return g.Import(save)
+}
+
+// Reset starts the game over, clearing the log and any snapshots
+func (g *Game[TState]) Reset() error {
+	for _, state := range g.StateNames() {
+		if _, exists := g.GetSnapshot(state); exists {
+			if err := g.ClearSnapshot(state); err != nil {
+				return err
+			}
+		}
+	}
+	return g.SetEventsE(nil)
+}
+
+// Run starts the game (see Start), then when ctx is done shuts it down,
+// waiting for async work (see Close)
+func (g *Game[TState]) Run(ctx context.Context) error {
+	if err := g.Start(); err != nil {
+		return err
+	}
+	<-ctx.Done()
+	return g.Close()
+}
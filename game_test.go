@@ -0,0 +1,88 @@
+package atmos
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+var errBroke = errors.New("not enough tokens")
+
+// newTokenGame guards the token balance of a game
+func newTokenGame() *Game[TokenBalance] {
+	game := NewGame("balance", TokenBalance{Tokens: 3})
+	WhenEvent[TokensSpentEvent](game.Engine).
+		Requires(affordable{}).
+		Updates("balance", func(e *Engine, state interface{}, event Event) interface{} {
+			if loaded, ok := event.(*TokensSpentEvent); ok {
+				event = *loaded // Decoded from a save
+			}
+			return TokenBalance{Tokens: state.(TokenBalance).Tokens - event.(TokensSpentEvent).Amount}
+		})
+	return game
+}
+
+// TestGameEmitErrMapsRejections verifies rejections become errors callers can test for
+func TestGameEmitErrMapsRejections(t *testing.T) {
+	game := newTokenGame()
+	require.NoError(t, game.EmitErr(TokensSpentEvent{Amount: 2}))
+	assert.Equal(t, TokenBalance{Tokens: 1}, game.State())
+	balance, ok := StateOf[TokenBalance](game.Engine, "balance")
+	assert.True(t, ok)
+	assert.Equal(t, TokenBalance{Tokens: 1}, balance)
+	_, ok = StateOf[TokenBalance](game.Engine, "missing")
+	assert.False(t, ok)
+	_, ok = StateOf[int](game.Engine, "balance")
+	assert.False(t, ok, "Not an int")
+
+	err := game.EmitErr(TokensSpentEvent{Amount: 2})
+	assert.ErrorIs(t, err, ErrRejected)
+	assert.EqualError(t, err, "event rejected by atmos.affordable")
+
+	game.MapRejection(affordable{}, errBroke)
+	assert.ErrorIs(t, game.EmitAsErr("alice", TokensSpentEvent{Amount: 2}), errBroke)
+
+	require.NoError(t, game.Close())
+	assert.ErrorIs(t, game.EmitErr(TokensSpentEvent{Amount: 1}), ErrEngineClosed)
+}
+
+// TestGameEmitErrReportsQueuedEvents verifies held events aren't reported as accepted
+func TestGameEmitErrReportsQueuedEvents(t *testing.T) {
+	game := newTokenGame()
+	game.Pause()
+	assert.ErrorIs(t, game.EmitErr(TokensSpentEvent{Amount: 1}), ErrQueued)
+	assert.True(t, game.Resume()[0].Accepted)
+}
+
+// TestGameSaveLoadReset verifies a game can be saved, started over, and restored
+func TestGameSaveLoadReset(t *testing.T) {
+	game := newTokenGame()
+	require.NoError(t, game.EmitErr(TokensSpentEvent{Amount: 1}))
+	save, err := game.Save()
+	require.NoError(t, err)
+
+	require.NoError(t, game.Reset())
+	assert.Equal(t, TokenBalance{Tokens: 3}, game.State())
+	assert.Empty(t, game.GetEvents())
+
+	require.NoError(t, game.Load(save))
+	assert.Equal(t, TokenBalance{Tokens: 2}, game.State())
+}
+
+// TestGameRun verifies Run starts the game and shuts it down with the context
+func TestGameRun(t *testing.T) {
+	game := newTokenGame()
+	var started, stopped bool
+	game.OnStart(func(engine *Engine) error { started = true; return nil })
+	game.OnShutdown(func(engine *Engine) error { stopped = true; return nil })
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	require.NoError(t, game.Run(ctx))
+	assert.True(t, started)
+	assert.True(t, stopped)
+	assert.True(t, game.Closed())
+}
@@ -0,0 +1,33 @@
+package atmos
+
+// RequiresAll registers validators that every event must pass, whatever its
+// type, for cross-cutting rules such as rate limits or schema validation.
+// They run before the event type's own validators.
+// Usage: engine.RequiresAll(Policy("game_in_progress"))
+func (e *Engine) RequiresAll(validators ...EventValidator) {
+	for _, validator := range validators {
+		e.RegisterValidator(AnyEvent, validator)
+	}
+}
+
+// ThenAll registers listeners that run after every commit, whatever the
+// event's type (equivalent to When(AnyEvent).Then)
+// Usage: engine.ThenAll(auditLogger)
+func (e *Engine) ThenAll(listeners ...EventListener) {
+	for _, listener := range listeners {
+		e.RegisterListener(AnyEvent, listener)
+	}
+}
+
+// validatorsFor returns the validators an event type must pass: the payload
+// check if enabled, then global validators, then the type's own
+func (e *Engine) validatorsFor(eventType string) []EventValidator {
+	global := e.validators[AnyEvent]
+	if e.payloadValidation {
+		global = append([]EventValidator{payloadValidator{}}, global...)
+	}
+	if len(global) == 0 || eventType == AnyEvent {
+		return e.validators[eventType]
+	}
+	return append(append([]EventValidator(nil), global...), e.validators[eventType]...)
+}
@@ -0,0 +1,49 @@
+package atmos
+
+import (
+	"testing"
+
+	"github.com/cumulusrpg/atmos/types"
+	"github.com/stretchr/testify/assert"
+)
+
+// typeRecorder records the type of every event it sees
+type typeRecorder struct {
+	types []string
+}
+
+func (l *typeRecorder) Handle(engine types.Engine, event Event) {
+	l.types = append(l.types, event.Type())
+}
+
+// TestRequiresAllAndThenAll verifies global validators and listeners apply to every event type
+func TestRequiresAllAndThenAll(t *testing.T) {
+	audit := &typeRecorder{}
+	engine := NewEngine()
+	engine.RegisterState("over", false)
+	engine.RequiresAll(Because(gameInProgress{}, "the game is over"))
+	engine.ThenAll(audit)
+	engine.When("move").Requires(Because(&PolicyValidator{Name: "own piece", Policy: ActorMatches(func(e *Engine, event Event) string {
+		return event.(MoveEvent).Player
+	})}, "not your piece"))
+	engine.When("game_over").Updates("over", func(e *Engine, state interface{}, event Event) interface{} {
+		return true
+	})
+
+	assert.True(t, engine.EmitAs("alice", MoveEvent{Player: "alice"}))
+	assert.True(t, engine.Emit(PassEvent{Player: "bob"}), "Types with no validators of their own still pass global ones")
+
+	explanation := engine.ExplainAs("alice", MoveEvent{Player: "bob"})
+	assert.Len(t, explanation.Validators, 2)
+	assert.Equal(t, "not your piece", explanation.FirstFailure.Reason)
+
+	assert.True(t, engine.Emit(GameOverEvent{}))
+	result := engine.EmitWithResult(PassEvent{Player: "bob"})
+	assert.False(t, result.Accepted)
+	assert.Equal(t, "the game is over", result.Reason)
+
+	explanation = engine.ExplainAs("alice", MoveEvent{Player: "alice"})
+	assert.Equal(t, "the game is over", explanation.FirstFailure.Reason, "Global validators run first")
+
+	assert.Equal(t, []string{"move", "pass", "game_over"}, audit.types)
+}
@@ -0,0 +1,81 @@
+package atmos
+
+import "fmt"
+
+// Invariant checks a property that must hold after every commit, returning
+// an error describing the violation
+type Invariant func(engine *Engine) error
+
+// namedInvariant is a registered invariant
+type namedInvariant struct {
+	name  string
+	check Invariant
+}
+
+// InvariantViolation reports an invariant that failed after an event committed
+type InvariantViolation struct {
+	Name  string // The invariant's registered name
+	Event Event  // The event whose commit broke it (nil for CheckInvariants)
+	Err   error  // The invariant's error
+}
+
+func (v *InvariantViolation) Error() string {
+	if v.Event == nil {
+		return fmt.Sprintf("invariant %q violated: %v", v.Name, v.Err)
+	}
+	return fmt.Sprintf("invariant %q violated after %s: %v", v.Name, v.Event.Type(), v.Err)
+}
+
+func (v *InvariantViolation) Unwrap() error {
+	return v.Err
+}
+
+// RegisterInvariant registers a property that must hold after every commit.
+// Invariants are only checked automatically on engines created with
+// WithInvariantChecks (intended for tests, fuzzing, and debug builds);
+// CheckInvariants runs them on demand.
+func (e *Engine) RegisterInvariant(name string, check Invariant) {
+	r := e.mutableRegistrations()
+	r.invariants = append(r.invariants, namedInvariant{name: name, check: check})
+}
+
+// WithInvariantChecks runs every registered invariant after each Emit, once
+// the event's listeners have run. Violations are passed to onViolation, or
+// panic if it is nil so tests fail at the offending event.
+func WithInvariantChecks(onViolation func(*InvariantViolation)) EngineOption {
+	return func(e *Engine) {
+		e.invariantChecks = true
+		e.onViolation = onViolation
+	}
+}
+
+// CheckInvariants runs every registered invariant and returns the first violation, if any
+func (e *Engine) CheckInvariants() error {
+	if v := e.firstViolation(nil); v != nil {
+		return v
+	}
+	return nil
+}
+
+// checkInvariants reports the first violation after an event commits
+func (e *Engine) checkInvariants(event Event) {
+	v := e.firstViolation(event)
+	if v == nil {
+		return
+	}
+	e.meta(InvariantBroken{Invariant: v.Name, EventType: event.Type(), Error: v.Err.Error()})
+	if e.onViolation == nil {
+		panic(v)
+	}
+	e.onViolation(v)
+}
+
+// firstViolation runs invariants in registration order
+func (e *Engine) firstViolation(event Event) *InvariantViolation {
+	for _, invariant := range e.invariants {
+		if err := invariant.check(e); err != nil {
+			return &InvariantViolation{Name: invariant.name, Event: event, Err: err}
+		}
+	}
+	return nil
+}
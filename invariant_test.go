@@ -0,0 +1,68 @@
+package atmos
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+type TokenBalance struct {
+	Tokens int
+}
+
+type TokensSpentEvent struct {
+	Amount int
+}
+
+func (e TokensSpentEvent) Type() string { return "tokens_spent" }
+
+// newTokenEngine has no validator guarding the balance, so spending can go negative
+func newTokenEngine(opts ...EngineOption) *Engine {
+	engine := NewEngine(opts...)
+	engine.RegisterState("balance", TokenBalance{Tokens: 3})
+	engine.When("tokens_spent").Updates("balance", func(e *Engine, state interface{}, event Event) interface{} {
+		return TokenBalance{Tokens: state.(TokenBalance).Tokens - event.(TokensSpentEvent).Amount}
+	})
+	engine.RegisterInvariant("tokens never go negative", func(e *Engine) error {
+		if e.GetState("balance").(TokenBalance).Tokens < 0 {
+			return errors.New("negative balance")
+		}
+		return nil
+	})
+	return engine
+}
+
+// TestInvariantViolationReported verifies violations identify the invariant and event
+func TestInvariantViolationReported(t *testing.T) {
+	var violations []*InvariantViolation
+	engine := newTokenEngine(WithInvariantChecks(func(v *InvariantViolation) {
+		violations = append(violations, v)
+	}))
+
+	assert.True(t, engine.Emit(TokensSpentEvent{Amount: 2}))
+	assert.Empty(t, violations)
+
+	assert.True(t, engine.Emit(TokensSpentEvent{Amount: 2}), "Invariants observe commits, they don't reject them")
+	assert.Len(t, violations, 1)
+	assert.Equal(t, "tokens never go negative", violations[0].Name)
+	assert.Equal(t, TokensSpentEvent{Amount: 2}, violations[0].Event)
+	assert.EqualError(t, violations[0], `invariant "tokens never go negative" violated after tokens_spent: negative balance`)
+}
+
+// TestInvariantChecksPanicByDefault verifies a nil handler panics at the offending emit
+func TestInvariantChecksPanicByDefault(t *testing.T) {
+	engine := newTokenEngine(WithInvariantChecks(nil))
+	assert.Panics(t, func() { engine.Emit(TokensSpentEvent{Amount: 5}) })
+}
+
+// TestInvariantsOffByDefault verifies invariants only run on demand without WithInvariantChecks
+func TestInvariantsOffByDefault(t *testing.T) {
+	engine := newTokenEngine()
+	assert.NotPanics(t, func() { engine.Emit(TokensSpentEvent{Amount: 5}) })
+
+	err := engine.CheckInvariants()
+	var violation *InvariantViolation
+	assert.ErrorAs(t, err, &violation)
+	assert.Nil(t, violation.Event)
+}
@@ -0,0 +1,248 @@
+// Package ledger tracks per-player resource balances (gold, wood, victory
+// points) so economy rules don't get re-implemented per game:
+//
+//	bank := ledger.New()
+//	bank.Install(engine)
+//
+//	engine.Emit(ledger.GrantedEvent{Player: "alice", Resource: "gold", Amount: 5})
+//	engine.Emit(ledger.TransferredEvent{From: "alice", To: "bob", Resource: "gold", Amount: 2})
+//	engine.Emit(ledger.TransactionEvent{Entries: []ledger.Entry{
+//		{Player: "alice", Resource: "gold", Delta: -3},
+//		{Player: "alice", Resource: "wood", Delta: -1},
+//		{Player: "alice", Resource: "houses", Delta: 1},
+//	}})
+//
+// Installing registers the balances as state and a validator that rejects
+// non-positive amounts and anything that would overdraw a balance. A
+// transaction applies all of its entries or (when any would overdraw) none.
+package ledger
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/cumulusrpg/atmos"
+	"github.com/cumulusrpg/atmos/types"
+)
+
+// Event types
+const (
+	GrantedEventType     = "resources_granted"
+	SpentEventType       = "resources_spent"
+	TransferredEventType = "resources_transferred"
+	TransactionEventType = "ledger_transaction"
+)
+
+// GrantedEvent adds resources to a player's balance
+type GrantedEvent struct {
+	Ledger   string `json:"ledger,omitempty"` // The ledger's state name ("" for the default ledger)
+	Player   string `json:"player"`
+	Resource string `json:"resource"`
+	Amount   int    `json:"amount"`
+}
+
+// Type implements atmos.Event
+func (e GrantedEvent) Type() string { return GrantedEventType }
+
+// SpentEvent removes resources from a player's balance
+type SpentEvent struct {
+	Ledger   string `json:"ledger,omitempty"`
+	Player   string `json:"player"`
+	Resource string `json:"resource"`
+	Amount   int    `json:"amount"`
+}
+
+// Type implements atmos.Event
+func (e SpentEvent) Type() string { return SpentEventType }
+
+// TransferredEvent moves resources between players
+type TransferredEvent struct {
+	Ledger   string `json:"ledger,omitempty"`
+	From     string `json:"from"`
+	To       string `json:"to"`
+	Resource string `json:"resource"`
+	Amount   int    `json:"amount"`
+}
+
+// Type implements atmos.Event
+func (e TransferredEvent) Type() string { return TransferredEventType }
+
+// Entry changes one player's balance of one resource
+type Entry struct {
+	Player   string `json:"player"`
+	Resource string `json:"resource"`
+	Delta    int    `json:"delta"`
+}
+
+// TransactionEvent applies several entries atomically
+type TransactionEvent struct {
+	Ledger  string  `json:"ledger,omitempty"`
+	Entries []Entry `json:"entries"`
+}
+
+// Type implements atmos.Event
+func (e TransactionEvent) Type() string { return TransactionEventType }
+
+// Balances holds each player's resources: player -> resource -> amount.
+// Grants, spends and transfers produce a new Balances (copying only the
+// players involved), so older values remain safe to read.
+type Balances map[string]map[string]int
+
+// Get returns a player's balance of a resource
+func (b Balances) Get(player, resource string) int {
+	return b[player][resource]
+}
+
+// Ledger installs resource tracking on an engine
+type Ledger struct {
+	name string
+}
+
+// Option configures ledger construction
+type Option func(*Ledger)
+
+// WithStateName sets the name of the balances state (default "ledger").
+// Events for other ledgers must set their Ledger field to this name.
+func WithStateName(name string) Option {
+	return func(l *Ledger) {
+		l.name = name
+	}
+}
+
+// New creates a ledger
+func New(opts ...Option) *Ledger {
+	l := &Ledger{name: "ledger"}
+
+	// Apply options
+	for _, opt := range opts {
+		opt(l)
+	}
+
+	return l
+}
+
+// Install registers the ledger's event types, balances state, and validator
+func (l *Ledger) Install(engine *atmos.Engine) {
+	engine.RegisterState(l.name, Balances{})
+
+	factories := map[string]func() atmos.Event{
+		GrantedEventType:     func() atmos.Event { return &GrantedEvent{} },
+		SpentEventType:       func() atmos.Event { return &SpentEvent{} },
+		TransferredEventType: func() atmos.Event { return &TransferredEvent{} },
+		TransactionEventType: func() atmos.Event { return &TransactionEvent{} },
+	}
+	for _, eventType := range []string{GrantedEventType, SpentEventType, TransferredEventType, TransactionEventType} {
+		engine.When(eventType, factories[eventType]).
+			Requires(&balanceValidator{ledger: l}).
+			Updates(l.name, l.reduce)
+	}
+}
+
+// Balances returns every balance
+func (l *Ledger) Balances(engine *atmos.Engine) Balances {
+	return engine.GetState(l.name).(Balances)
+}
+
+// Balance returns a player's balance of a resource
+func (l *Ledger) Balance(engine *atmos.Engine, player, resource string) int {
+	return l.Balances(engine).Get(player, resource)
+}
+
+// Entries returns the balance changes an event makes to this ledger
+// (nil for events of other types or ledgers)
+func (l *Ledger) Entries(event atmos.Event) ([]Entry, error) {
+	var ledger string
+	var entries []Entry
+	amount := 1
+
+	switch e := atmos.Deref(event).(type) {
+	case GrantedEvent:
+		ledger, amount = e.Ledger, e.Amount
+		entries = []Entry{{Player: e.Player, Resource: e.Resource, Delta: e.Amount}}
+	case SpentEvent:
+		ledger, amount = e.Ledger, e.Amount
+		entries = []Entry{{Player: e.Player, Resource: e.Resource, Delta: -e.Amount}}
+	case TransferredEvent:
+		ledger, amount = e.Ledger, e.Amount
+		entries = []Entry{
+			{Player: e.From, Resource: e.Resource, Delta: -e.Amount},
+			{Player: e.To, Resource: e.Resource, Delta: e.Amount},
+		}
+	case TransactionEvent:
+		ledger, entries = e.Ledger, e.Entries
+	default:
+		return nil, nil
+	}
+
+	if !l.owns(ledger) {
+		return nil, nil
+	}
+	if len(entries) == 0 {
+		return nil, errors.New("a transaction needs at least one entry")
+	}
+	if amount <= 0 {
+		return nil, fmt.Errorf("amount must be positive, got %d", amount)
+	}
+	return entries, nil
+}
+
+// owns reports whether an event's Ledger field refers to this ledger
+func (l *Ledger) owns(ledger string) bool {
+	return ledger == l.name || (ledger == "" && l.name == "ledger")
+}
+
+// apply returns balances with entries applied, leaving the original untouched
+func apply(balances Balances, entries []Entry) Balances {
+	next := make(Balances, len(balances))
+	for player, resources := range balances {
+		next[player] = resources
+	}
+	copied := make(map[string]bool)
+	for _, entry := range entries {
+		if !copied[entry.Player] {
+			resources := make(map[string]int, len(next[entry.Player])+1)
+			for resource, amount := range next[entry.Player] {
+				resources[resource] = amount
+			}
+			next[entry.Player] = resources
+			copied[entry.Player] = true
+		}
+		next[entry.Player][entry.Resource] += entry.Delta
+	}
+	return next
+}
+
+// reduce applies an event's entries to the balances
+func (l *Ledger) reduce(engine *atmos.Engine, state interface{}, event atmos.Event) interface{} {
+	entries, err := l.Entries(event)
+	if err != nil || entries == nil {
+		return state
+	}
+	return apply(state.(Balances), entries)
+}
+
+// balanceValidator rejects invalid amounts and overdrafts
+type balanceValidator struct {
+	ledger *Ledger
+}
+
+// Validate implements atmos.EventValidator
+func (v *balanceValidator) Validate(engine types.Engine, event atmos.Event) bool {
+	return v.Check(engine, event) == nil
+}
+
+// Check implements atmos.ReasonedValidator
+func (v *balanceValidator) Check(engine types.Engine, event atmos.Event) error {
+	entries, err := v.ledger.Entries(event)
+	if err != nil || entries == nil {
+		return err
+	}
+
+	after := apply(v.ledger.Balances(engine.(*atmos.Engine)), entries)
+	for _, entry := range entries {
+		if balance := after.Get(entry.Player, entry.Resource); balance < 0 {
+			return fmt.Errorf("%s has insufficient %s (short by %d)", entry.Player, entry.Resource, -balance)
+		}
+	}
+	return nil
+}
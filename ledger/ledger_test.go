@@ -0,0 +1,92 @@
+package ledger_test
+
+import (
+	"testing"
+
+	"github.com/cumulusrpg/atmos"
+	"github.com/cumulusrpg/atmos/ledger"
+	"github.com/stretchr/testify/assert"
+)
+
+// TestLedgerGrantSpendTransfer verifies balances and overdraft protection
+func TestLedgerGrantSpendTransfer(t *testing.T) {
+	bank := ledger.New()
+	engine := atmos.NewEngine()
+	bank.Install(engine)
+
+	assert.True(t, engine.Emit(ledger.GrantedEvent{Player: "alice", Resource: "gold", Amount: 5}))
+	assert.True(t, engine.Emit(ledger.TransferredEvent{From: "alice", To: "bob", Resource: "gold", Amount: 2}))
+	assert.True(t, engine.Emit(ledger.SpentEvent{Player: "bob", Resource: "gold", Amount: 1}))
+
+	overdraft := engine.EmitWithResult(ledger.SpentEvent{Player: "bob", Resource: "gold", Amount: 2})
+	assert.False(t, overdraft.Accepted)
+	assert.Equal(t, "bob has insufficient gold (short by 1)", overdraft.Reason)
+
+	negative := engine.EmitWithResult(ledger.GrantedEvent{Player: "bob", Resource: "gold", Amount: -5})
+	assert.Equal(t, "amount must be positive, got -5", negative.Reason)
+
+	assert.Equal(t, 3, bank.Balance(engine, "alice", "gold"))
+	assert.Equal(t, 1, bank.Balance(engine, "bob", "gold"))
+	assert.Equal(t, 0, bank.Balance(engine, "carol", "wood"))
+}
+
+// TestLedgerTransactionsAreAtomic verifies a transaction applies all entries or none
+func TestLedgerTransactionsAreAtomic(t *testing.T) {
+	bank := ledger.New()
+	engine := atmos.NewEngine()
+	bank.Install(engine)
+	engine.Emit(ledger.GrantedEvent{Player: "alice", Resource: "gold", Amount: 3})
+	engine.Emit(ledger.GrantedEvent{Player: "alice", Resource: "wood", Amount: 1})
+
+	buildHouse := ledger.TransactionEvent{Entries: []ledger.Entry{
+		{Player: "alice", Resource: "gold", Delta: -3},
+		{Player: "alice", Resource: "wood", Delta: -1},
+		{Player: "alice", Resource: "houses", Delta: 1},
+	}}
+	before := bank.Balances(engine)
+	assert.True(t, engine.Emit(buildHouse))
+	assert.Equal(t, ledger.Balances{"alice": {"gold": 0, "wood": 0, "houses": 1}}, bank.Balances(engine))
+	assert.Equal(t, 3, before.Get("alice", "gold"), "Earlier balances are never modified")
+
+	result := engine.EmitWithResult(buildHouse)
+	assert.False(t, result.Accepted)
+	assert.Equal(t, "alice has insufficient gold (short by 3)", result.Reason)
+	assert.Equal(t, 1, bank.Balance(engine, "alice", "houses"), "Nothing was applied")
+
+	assert.False(t, engine.Emit(ledger.TransactionEvent{}), "Empty transactions are rejected")
+}
+
+// TestLedgerSeparateLedgers verifies two ledgers on one engine only see their own events
+func TestLedgerSeparateLedgers(t *testing.T) {
+	gold := ledger.New()
+	points := ledger.New(ledger.WithStateName("points"))
+	engine := atmos.NewEngine()
+	gold.Install(engine)
+	points.Install(engine)
+
+	assert.True(t, engine.Emit(ledger.GrantedEvent{Player: "alice", Resource: "gold", Amount: 2}))
+	assert.True(t, engine.Emit(ledger.GrantedEvent{Ledger: "points", Player: "alice", Resource: "vp", Amount: 7}))
+	assert.False(t, engine.Emit(ledger.SpentEvent{Ledger: "points", Player: "alice", Resource: "gold", Amount: 1}))
+
+	assert.Equal(t, 2, gold.Balance(engine, "alice", "gold"))
+	assert.Equal(t, 0, gold.Balance(engine, "alice", "vp"))
+	assert.Equal(t, 7, points.Balance(engine, "alice", "vp"))
+
+	empty := ledger.TransactionEvent{Ledger: "points"}
+	entries, err := gold.Entries(empty)
+	assert.NoError(t, err, "Other ledgers' transactions are ignored, even empty ones")
+	assert.Nil(t, entries)
+	_, err = points.Entries(empty)
+	assert.Error(t, err)
+
+	// Decoded (pointer) events replay the same way
+	data, err := engine.MarshalEvents(engine.GetEvents())
+	assert.NoError(t, err)
+	events, err := engine.UnmarshalEvents(data)
+	assert.NoError(t, err)
+	replayed := atmos.NewEngine()
+	gold.Install(replayed)
+	points.Install(replayed)
+	replayed.SetEvents(events)
+	assert.Equal(t, 7, points.Balance(replayed, "alice", "vp"))
+}
@@ -0,0 +1,92 @@
+package atmos
+
+import (
+	"context"
+	"errors"
+)
+
+// ErrEngineClosed is returned by emits after Close or Shutdown
+var ErrEngineClosed = errors.New("engine is closed")
+
+// LifecycleHook runs when an engine starts or shuts down
+type LifecycleHook func(engine *Engine) error
+
+// OnLoad registers a hook run after the log is replaced by SetEvents (a saved
+// game loading, a replay seeking), e.g. to warm caches derived from the log
+func (e *Engine) OnLoad(hook func(engine *Engine)) {
+	e.mutableRegistrations()
+	e.loadHooks = append(e.loadHooks, hook)
+}
+
+// OnStart registers a hook run by Start, e.g. to launch a scheduler
+func (e *Engine) OnStart(hook LifecycleHook) {
+	e.mutableRegistrations()
+	e.startHooks = append(e.startHooks, hook)
+}
+
+// OnShutdown registers a hook run by Close and Shutdown, e.g. to flush buffered work.
+// Shutdown hooks run in reverse registration order, so modules stop before
+// the modules they were installed on top of.
+func (e *Engine) OnShutdown(hook LifecycleHook) {
+	e.mutableRegistrations()
+	e.shutdownHooks = append(e.shutdownHooks, hook)
+}
+
+// Start runs the start hooks in registration order, stopping at the first
+// error. Starting an engine twice is a no-op.
+func (e *Engine) Start() error {
+	if e.started {
+		return nil
+	}
+	for _, hook := range e.startHooks {
+		if err := hook(e); err != nil {
+			return err
+		}
+	}
+	e.started = true
+	return nil
+}
+
+// Close shuts the engine down like Shutdown, waiting as long as async work takes
+func (e *Engine) Close() error {
+	return e.Shutdown(context.Background())
+}
+
+// Shutdown stops the engine accepting emits (they fail with
+// ErrEngineClosed), waits for async work (see ThenAsync and Go) until ctx is
+// done, then runs every shutdown hook, makes a final autosave if one is
+// configured, checkpoints states (see WithCheckpoints), and stops change
+// notifications (see ExternalChanges). Work
+// still running at the deadline is reported in a *DroppedWorkError, joined
+// with any hook or save errors. Shutting down twice is a no-op.
+func (e *Engine) Shutdown(ctx context.Context) error {
+	if e.closed {
+		return nil
+	}
+	e.closed = true
+
+	var errs []error
+	if pending := e.async.wait(ctx); len(pending) > 0 {
+		errs = append(errs, &DroppedWorkError{Pending: pending, Err: ctx.Err()})
+	}
+	for i := len(e.shutdownHooks) - 1; i >= 0; i-- {
+		if err := e.shutdownHooks[i](e); err != nil {
+			errs = append(errs, err)
+		}
+	}
+	if e.autosave != nil && e.autosave.pending > 0 {
+		errs = append(errs, e.autosave.save(e))
+	}
+	if e.checkpointEvery > 0 && e.uncheckpointed > 0 {
+		errs = append(errs, e.Checkpoint())
+	}
+	if e.external != nil {
+		e.external.stop()
+	}
+	return errors.Join(errs...)
+}
+
+// Closed reports whether the engine has been shut down
+func (e *Engine) Closed() bool {
+	return e.closed
+}
@@ -0,0 +1,104 @@
+package atmos
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/cumulusrpg/atmos/repository"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// TestLifecycleHooksRunInOrder verifies start hooks run in order and shutdown hooks in reverse
+func TestLifecycleHooksRunInOrder(t *testing.T) {
+	engine := NewEngine()
+	var calls []string
+	for _, name := range []string{"scheduler", "network"} {
+		name := name
+		engine.OnStart(func(e *Engine) error {
+			calls = append(calls, "start "+name)
+			return nil
+		})
+		engine.OnShutdown(func(e *Engine) error {
+			calls = append(calls, "stop "+name)
+			return nil
+		})
+	}
+
+	require.NoError(t, engine.Start())
+	require.NoError(t, engine.Start())
+	require.NoError(t, engine.Close())
+	require.NoError(t, engine.Close())
+	assert.Equal(t, []string{"start scheduler", "start network", "stop network", "stop scheduler"}, calls)
+}
+
+// TestStartStopsAtFirstError verifies a failing start hook aborts the start
+func TestStartStopsAtFirstError(t *testing.T) {
+	engine := NewEngine()
+	ran := false
+	engine.OnStart(func(e *Engine) error { return errors.New("port in use") })
+	engine.OnStart(func(e *Engine) error {
+		ran = true
+		return nil
+	})
+
+	assert.EqualError(t, engine.Start(), "port in use")
+	assert.False(t, ran)
+}
+
+// TestCloseRefusesEmitsAndJoinsErrors verifies a closed engine rejects emits and reports every failure
+func TestCloseRefusesEmitsAndJoinsErrors(t *testing.T) {
+	engine := NewEngine()
+	engine.OnShutdown(func(e *Engine) error { return errors.New("flush failed") })
+	engine.OnShutdown(func(e *Engine) error { return errors.New("socket stuck") })
+
+	err := engine.Close()
+	assert.ErrorContains(t, err, "flush failed")
+	assert.ErrorContains(t, err, "socket stuck")
+	assert.True(t, engine.Closed())
+
+	result := engine.EmitWithResult(TestEvent{Name: "late"})
+	assert.False(t, result.Accepted)
+	assert.ErrorIs(t, result.Err, ErrEngineClosed)
+	assert.Empty(t, engine.GetEvents())
+}
+
+// TestCloseFlushesAutosave verifies commits not yet autosaved are saved on close
+func TestCloseFlushesAutosave(t *testing.T) {
+	target := repository.NewInMemory()
+	engine := NewEngine(WithAutosave(target, EveryEvents(10)))
+
+	engine.Emit(TestEvent{Name: "a"})
+	assert.Empty(t, target.GetAll(engine))
+	require.NoError(t, engine.Close())
+	assert.Len(t, target.GetAll(engine), 1)
+}
+
+// TestLoadHooksRunOnSetEvents verifies load hooks see the loaded log
+func TestLoadHooksRunOnSetEvents(t *testing.T) {
+	engine := NewEngine()
+	var loaded []int
+	engine.OnLoad(func(e *Engine) {
+		loaded = append(loaded, len(e.GetEvents()))
+	})
+
+	engine.Emit(TestEvent{Name: "a"})
+	assert.Empty(t, loaded, "Emits aren't loads")
+	engine.SetEvents([]Event{TestEvent{Name: "a"}, TestEvent{Name: "b"}})
+	assert.Equal(t, []int{2}, loaded)
+}
+
+// TestForksDontRunLoadHooks verifies a fork starts with the log without loading it
+func TestForksDontRunLoadHooks(t *testing.T) {
+	engine := NewEngine()
+	loads := 0
+	engine.OnLoad(func(e *Engine) { loads++ })
+	engine.Emit(TestEvent{Name: "a"})
+
+	fork := engine.Fork()
+	assert.Len(t, fork.GetEvents(), 1)
+	assert.Zero(t, loads)
+
+	fork.SetEvents(nil)
+	assert.Equal(t, 1, loads, "Loading into a fork still runs them")
+}
@@ -0,0 +1,301 @@
+package atmos
+
+import (
+	"errors"
+	"sort"
+	"sync"
+	"time"
+)
+
+// ErrEngineExists is returned when creating an engine under an ID already in use
+var ErrEngineExists = errors.New("engine already exists")
+
+// ErrEngineNotFound is returned when no engine is loaded or stored under an ID
+var ErrEngineNotFound = errors.New("engine not found")
+
+// EngineStore persists serialized event logs for managed engines
+type EngineStore interface {
+	// Save stores the serialized event log for an engine
+	Save(id string, data []byte) error
+
+	// Load returns the serialized event log for an engine, or false if none exists
+	Load(id string) ([]byte, bool, error)
+
+	// Delete removes the stored event log for an engine
+	Delete(id string) error
+}
+
+// EngineSetup registers states, events, and services on a freshly created engine
+type EngineSetup func(id string, engine *Engine)
+
+// managedEngine tracks a live engine and serializes access to it
+type managedEngine struct {
+	mu       sync.Mutex
+	engine   *Engine
+	lastUsed time.Time
+	uses     uint64 // acquisitions so far, so evictions can tell it was used while saving
+}
+
+// EngineManager creates, tracks, evicts, and persists many engines keyed by ID
+// (typically one engine per game session). It is safe for concurrent use;
+// use Do to serialize work on an individual engine.
+type EngineManager struct {
+	mu                sync.Mutex
+	engines           map[string]*managedEngine
+	setup             EngineSetup
+	blueprint         *Blueprint
+	repositoryFactory func(id string) EventRepository
+	store             EngineStore
+	now               func() time.Time
+}
+
+// ManagerOption configures engine manager construction
+type ManagerOption func(*EngineManager)
+
+// WithRepositoryFactory derives a repository for each managed engine from its ID
+func WithRepositoryFactory(factory func(id string) EventRepository) ManagerOption {
+	return func(m *EngineManager) {
+		m.repositoryFactory = factory
+	}
+}
+
+// WithEngineStore persists engines on eviction and reloads them on demand
+func WithEngineStore(store EngineStore) ManagerOption {
+	return func(m *EngineManager) {
+		m.store = store
+	}
+}
+
+// NewEngineManager creates a manager that runs setup on every engine it creates or loads
+func NewEngineManager(setup EngineSetup, opts ...ManagerOption) *EngineManager {
+	manager := &EngineManager{
+		engines: make(map[string]*managedEngine),
+		setup:   setup,
+		now:     time.Now,
+	}
+
+	// Apply options
+	for _, opt := range opts {
+		opt(manager)
+	}
+
+	return manager
+}
+
+// newEngine builds and configures an engine for the given ID
+func (m *EngineManager) newEngine(id string) *Engine {
+	var opts []EngineOption
+	if m.repositoryFactory != nil {
+		opts = append(opts, WithRepository(m.repositoryFactory(id)))
+	}
+
+	var engine *Engine
+	if m.blueprint != nil {
+		engine = m.blueprint.NewEngine(opts...)
+	} else {
+		engine = NewEngine(opts...)
+	}
+	if m.setup != nil {
+		m.setup(id, engine)
+	}
+	return engine
+}
+
+// Create starts a new engine under the given ID
+func (m *EngineManager) Create(id string) (*Engine, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	if _, exists := m.engines[id]; exists {
+		return nil, ErrEngineExists
+	}
+	if m.store != nil {
+		if _, stored, err := m.store.Load(id); err != nil {
+			return nil, err
+		} else if stored {
+			return nil, ErrEngineExists
+		}
+	}
+
+	engine := m.newEngine(id)
+	m.engines[id] = &managedEngine{engine: engine, lastUsed: m.now()}
+	return engine, nil
+}
+
+// Get returns the engine for an ID, loading it from the store if it was evicted
+func (m *EngineManager) Get(id string) (*Engine, error) {
+	managed, err := m.acquire(id)
+	if err != nil {
+		return nil, err
+	}
+	return managed.engine, nil
+}
+
+// Do runs fn with exclusive access to the engine for an ID
+func (m *EngineManager) Do(id string, fn func(engine *Engine) error) error {
+	managed, err := m.acquire(id)
+	if err != nil {
+		return err
+	}
+
+	managed.mu.Lock()
+	defer managed.mu.Unlock()
+	return fn(managed.engine)
+}
+
+// acquire returns the managed engine for an ID, loading it if necessary
+func (m *EngineManager) acquire(id string) (*managedEngine, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	if managed, exists := m.engines[id]; exists {
+		managed.lastUsed = m.now()
+		managed.uses++
+		return managed, nil
+	}
+
+	if m.store == nil {
+		return nil, ErrEngineNotFound
+	}
+
+	data, stored, err := m.store.Load(id)
+	if err != nil {
+		return nil, err
+	}
+	if !stored {
+		return nil, ErrEngineNotFound
+	}
+
+	engine := m.newEngine(id)
+	events, err := engine.UnmarshalEvents(data)
+	if err != nil {
+		return nil, err
+	}
+	if err := engine.SetEventsE(events); err != nil {
+		return nil, err
+	}
+
+	managed := &managedEngine{engine: engine, lastUsed: m.now()}
+	m.engines[id] = managed
+	return managed, nil
+}
+
+// Persist saves the engine's event log to the store without evicting it
+func (m *EngineManager) Persist(id string) error {
+	m.mu.Lock()
+	managed, exists := m.engines[id]
+	m.mu.Unlock()
+	if !exists {
+		return ErrEngineNotFound
+	}
+	return m.persist(id, managed)
+}
+
+// persist saves a loaded engine. The caller must not hold m.mu, so other
+// engines stay usable while the store works.
+func (m *EngineManager) persist(id string, managed *managedEngine) error {
+	if m.store == nil {
+		return nil
+	}
+
+	managed.mu.Lock()
+	data, err := managed.engine.MarshalLog()
+	managed.mu.Unlock()
+	if err != nil {
+		return err
+	}
+	return m.store.Save(id, data)
+}
+
+// Evict persists the engine (if a store is configured) and drops it from
+// memory. An engine used while it was being saved stays loaded, since the
+// save may have missed its latest events.
+func (m *EngineManager) Evict(id string) error {
+	m.mu.Lock()
+	managed, exists := m.engines[id]
+	var uses uint64
+	if exists {
+		uses = managed.uses
+	}
+	m.mu.Unlock()
+	if !exists {
+		return ErrEngineNotFound
+	}
+
+	if err := m.persist(id, managed); err != nil {
+		return err
+	}
+	m.drop(id, managed, uses)
+	return nil
+}
+
+// drop removes a saved engine from memory unless it was used since
+func (m *EngineManager) drop(id string, managed *managedEngine, uses uint64) bool {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if m.engines[id] != managed || managed.uses != uses {
+		return false
+	}
+	delete(m.engines, id)
+	return true
+}
+
+// EvictIdle evicts every engine unused for longer than maxIdle and returns their IDs.
+// Engines that fail to persist stay loaded; the first such error is returned.
+func (m *EngineManager) EvictIdle(maxIdle time.Duration) ([]string, error) {
+	type candidate struct {
+		managed *managedEngine
+		uses    uint64
+	}
+	m.mu.Lock()
+	cutoff := m.now().Add(-maxIdle)
+	idle := make(map[string]candidate)
+	for id, managed := range m.engines {
+		if !managed.lastUsed.After(cutoff) {
+			idle[id] = candidate{managed, managed.uses}
+		}
+	}
+	m.mu.Unlock()
+
+	var evicted []string
+	var firstErr error
+	for id, c := range idle {
+		if err := m.persist(id, c.managed); err != nil {
+			if firstErr == nil {
+				firstErr = err
+			}
+			continue
+		}
+		if m.drop(id, c.managed, c.uses) {
+			evicted = append(evicted, id)
+		}
+	}
+
+	sort.Strings(evicted)
+	return evicted, firstErr
+}
+
+// Remove drops an engine from memory and deletes it from the store
+func (m *EngineManager) Remove(id string) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	delete(m.engines, id)
+	if m.store != nil {
+		return m.store.Delete(id)
+	}
+	return nil
+}
+
+// IDs returns the IDs of all engines currently loaded in memory, sorted
+func (m *EngineManager) IDs() []string {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	ids := make([]string, 0, len(m.engines))
+	for id := range m.engines {
+		ids = append(ids, id)
+	}
+	sort.Strings(ids)
+	return ids
+}
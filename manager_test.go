@@ -0,0 +1,156 @@
+package atmos
+
+import (
+	"testing"
+	"time"
+
+	"github.com/cumulusrpg/atmos/repository"
+	"github.com/stretchr/testify/assert"
+)
+
+// memoryEngineStore is a minimal EngineStore for tests
+type memoryEngineStore struct {
+	data map[string][]byte
+}
+
+func (s *memoryEngineStore) Save(id string, data []byte) error {
+	s.data[id] = data
+	return nil
+}
+
+func (s *memoryEngineStore) Load(id string) ([]byte, bool, error) {
+	data, exists := s.data[id]
+	return data, exists, nil
+}
+
+func (s *memoryEngineStore) Delete(id string) error {
+	delete(s.data, id)
+	return nil
+}
+
+func setupOrders(id string, engine *Engine) {
+	engine.When("order_placed", func() Event { return &OrderPlacedEvent{} })
+}
+
+// TestEngineManagerCreateAndGet verifies engines are tracked per ID
+func TestEngineManagerCreateAndGet(t *testing.T) {
+	var repoIDs []string
+	manager := NewEngineManager(setupOrders, WithRepositoryFactory(func(id string) EventRepository {
+		repoIDs = append(repoIDs, id)
+		return repository.NewInMemory()
+	}))
+
+	game1, err := manager.Create("game-1")
+	assert.NoError(t, err)
+	_, err = manager.Create("game-2")
+	assert.NoError(t, err)
+
+	_, err = manager.Create("game-1")
+	assert.ErrorIs(t, err, ErrEngineExists)
+
+	game1.Emit(&OrderPlacedEvent{OrderID: "ORD-1"})
+
+	got, err := manager.Get("game-1")
+	assert.NoError(t, err)
+	assert.Same(t, game1, got)
+	assert.Len(t, got.GetEvents(), 1)
+
+	_, err = manager.Get("missing")
+	assert.ErrorIs(t, err, ErrEngineNotFound)
+
+	assert.Equal(t, []string{"game-1", "game-2"}, manager.IDs())
+	assert.Equal(t, []string{"game-1", "game-2"}, repoIDs)
+}
+
+// TestEngineManagerEvictAndReload verifies evicted engines are persisted and reloaded
+func TestEngineManagerEvictAndReload(t *testing.T) {
+	store := &memoryEngineStore{data: make(map[string][]byte)}
+	manager := NewEngineManager(setupOrders, WithEngineStore(store))
+
+	clock := time.Date(2024, 1, 1, 12, 0, 0, 0, time.UTC)
+	manager.now = func() time.Time { return clock }
+
+	_, err := manager.Create("idle")
+	assert.NoError(t, err)
+	_, err = manager.Create("busy")
+	assert.NoError(t, err)
+
+	err = manager.Do("idle", func(engine *Engine) error {
+		engine.Emit(&OrderPlacedEvent{OrderID: "ORD-1", Amount: 5})
+		return nil
+	})
+	assert.NoError(t, err)
+
+	clock = clock.Add(time.Hour)
+	_, err = manager.Get("busy")
+	assert.NoError(t, err)
+
+	evicted, err := manager.EvictIdle(30 * time.Minute)
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"idle"}, evicted)
+	assert.Equal(t, []string{"busy"}, manager.IDs())
+	assert.Contains(t, store.data, "idle")
+
+	// Reload on demand from the store
+	reloaded, err := manager.Get("idle")
+	assert.NoError(t, err)
+	events := reloaded.GetEvents()
+	assert.Len(t, events, 1)
+	assert.Equal(t, "ORD-1", events[0].(*OrderPlacedEvent).OrderID)
+
+	// Stored engines cannot be re-created
+	assert.NoError(t, manager.Evict("idle"))
+	_, err = manager.Create("idle")
+	assert.ErrorIs(t, err, ErrEngineExists)
+
+	// Remove deletes from memory and store
+	assert.NoError(t, manager.Remove("idle"))
+	_, err = manager.Get("idle")
+	assert.ErrorIs(t, err, ErrEngineNotFound)
+}
+
+// blockingEngineStore holds every Save until released
+type blockingEngineStore struct {
+	memoryEngineStore
+	saving  chan string
+	release chan struct{}
+}
+
+func (s *blockingEngineStore) Save(id string, data []byte) error {
+	s.saving <- id
+	<-s.release
+	return s.memoryEngineStore.Save(id, data)
+}
+
+// TestEngineManagerPersistDoesNotBlockOtherEngines verifies the manager stays usable while the store saves
+func TestEngineManagerPersistDoesNotBlockOtherEngines(t *testing.T) {
+	store := &blockingEngineStore{
+		memoryEngineStore: memoryEngineStore{data: make(map[string][]byte)},
+		saving:            make(chan string, 2),
+		release:           make(chan struct{}),
+	}
+	manager := NewEngineManager(setupOrders, WithEngineStore(store))
+	_, err := manager.Create("slow")
+	assert.NoError(t, err)
+	_, err = manager.Create("other")
+	assert.NoError(t, err)
+
+	evicted := make(chan error)
+	go func() { evicted <- manager.Evict("slow") }()
+	assert.Equal(t, "slow", <-store.saving)
+
+	// While the save is blocked, other engines (and this one) remain reachable
+	assert.NoError(t, manager.Do("other", func(engine *Engine) error {
+		engine.Emit(&OrderPlacedEvent{OrderID: "ORD-1"})
+		return nil
+	}))
+	_, err = manager.Get("slow")
+	assert.NoError(t, err)
+
+	close(store.release)
+	assert.NoError(t, <-evicted)
+	assert.Equal(t, []string{"other", "slow"}, manager.IDs(), "Used during the save, so kept loaded")
+
+	assert.NoError(t, manager.Evict("slow"))
+	assert.Equal(t, []string{"other"}, manager.IDs())
+}
@@ -0,0 +1,204 @@
+package atmos
+
+import (
+	"errors"
+	"maps"
+	"reflect"
+
+	"github.com/cumulusrpg/atmos/repository"
+	"github.com/cumulusrpg/atmos/types"
+)
+
+// ErrNotForkedFromBase is returned by Merge when a branch does not start with the base log
+var ErrNotForkedFromBase = errors.New("branch does not start with the base log")
+
+// Fork creates an independent engine with the same registrations and a copy of
+// the current event log in memory. The fork shares registration tables
+// copy-on-write (like a Blueprint) and shares registered services. The fork
+// starts with the log rather than loading it, so load hooks don't run.
+func (e *Engine) Fork() *Engine {
+	return e.forkWith(e.GetEvents())
+}
+
+// forkWith forks the engine with events as the fork's log
+func (e *Engine) forkWith(events []Event) *Engine {
+	fork := e.forkOver(repository.NewInMemory())
+	_ = fork.repository.SetAll(fork, events) // in memory, can't fail
+	return fork
+}
+
+// forkOver forks the engine over a repository holding the fork's log
+func (e *Engine) forkOver(repo types.EventRepository) *Engine {
+	fork := &Engine{
+		registrations:       e.registrations,
+		sharedRegistrations: true,
+		repository:          repo,
+	}
+	e.sharedRegistrations = true // both sides must now copy before writing
+	e.copySettings(fork)
+	return fork
+}
+
+// copySettings gives a fork the engine's settings that decide what it
+// accepts and how it serializes, and the context of the emit in progress.
+// Storage, autosave, effects, meta-events and tracing stay with the engine.
+func (e *Engine) copySettings(fork *Engine) {
+	fork.actor = e.actor
+	fork.metadata = maps.Clone(e.metadata)
+	fork.ctx = e.ctx
+	fork.autoEventTypes = e.autoEventTypes
+	fork.autoNamingErr = e.autoNamingErr
+	fork.strictEvents = e.strictEvents
+	fork.strictPanic = e.strictPanic
+	fork.strictRegistration = e.strictRegistration
+	fork.duplicatePolicy = e.duplicatePolicy
+	fork.enforceEmits = e.enforceEmits
+	fork.breadthFirst = e.breadthFirst
+	fork.quarantining = e.quarantining
+	fork.rawEvents = e.rawEvents
+	fork.payloadValidation = e.payloadValidation
+	fork.generateID = e.generateID
+	fork.keys = e.keys
+	fork.schemaVersion = e.schemaVersion
+	fork.messages = e.messages
+	fork.payloadLimit = e.payloadLimit
+	fork.oversized = e.oversized
+	fork.blobs = e.blobs
+}
+
+// Conflict describes a branch event that is no longer valid after merging
+type Conflict struct {
+	Event       Event       // The branch event that failed validation
+	Explanation Explanation // Why it failed against the merged state
+	Attempt     int         // 1 on first failure, 2 when a reordered event fails again
+}
+
+// resolutionAction enumerates how a conflict is resolved
+type resolutionAction int
+
+const (
+	resolveReject resolutionAction = iota
+	resolveReorder
+	resolveTransform
+)
+
+// Resolution tells Merge what to do with a conflicting event
+type Resolution struct {
+	action resolutionAction
+	events []Event
+}
+
+// Reject drops the conflicting event
+func Reject() Resolution {
+	return Resolution{action: resolveReject}
+}
+
+// Reorder retries the conflicting event after the rest of the branch has been applied.
+// An event that conflicts again after reordering is rejected if reordered a second time.
+func Reorder() Resolution {
+	return Resolution{action: resolveReorder}
+}
+
+// Transform replaces the conflicting event with other events (none to drop it silently).
+// Replacements are validated too; any that fail are rejected.
+func Transform(events ...Event) Resolution {
+	return Resolution{action: resolveTransform, events: events}
+}
+
+// ConflictResolver decides how to resolve a conflicting branch event.
+// The engine passed in reflects the merge so far.
+type ConflictResolver func(merged *Engine, conflict Conflict) Resolution
+
+// RejectConflicts is a ConflictResolver that rejects every conflicting event
+func RejectConflicts(merged *Engine, conflict Conflict) Resolution {
+	return Reject()
+}
+
+// MergeResult is the outcome of merging two branches
+type MergeResult struct {
+	Events   []Event    // The merged log: base, then ours, then the surviving events of theirs
+	Rejected []Conflict // Branch events dropped during the merge
+}
+
+// Merge reconciles two logs that diverged from a common base. "ours" is
+// authoritative (typically the server log) and is kept verbatim; the events
+// "theirs" added after base are re-validated on top of it in order, with
+// conflicts handed to resolve (nil rejects every conflict).
+//
+// Replayed events are validated and appended without running before hooks or
+// listeners, since any events those produced are already part of the branch.
+// The engine itself is not modified; apply the result with SetEvents.
+func (e *Engine) Merge(base, ours, theirs []Event, resolve ConflictResolver) (MergeResult, error) {
+	if !hasPrefix(ours, base) || !hasPrefix(theirs, base) {
+		return MergeResult{}, ErrNotForkedFromBase
+	}
+	if resolve == nil {
+		resolve = RejectConflicts
+	}
+
+	merged := e.Fork()
+	merged.SetEvents(ours)
+
+	var result MergeResult
+	var deferred []Event
+
+	// apply validates and appends one event, resolving conflicts
+	var apply func(event Event, attempt int) error
+	apply = func(event Event, attempt int) error {
+		explanation := merged.Explain(event)
+		if explanation.Accepted {
+			return merged.repository.Add(merged, event)
+		}
+
+		conflict := Conflict{Event: event, Explanation: explanation, Attempt: attempt}
+		resolution := resolve(merged, conflict)
+		switch resolution.action {
+		case resolveReorder:
+			if attempt == 1 {
+				deferred = append(deferred, event)
+				return nil
+			}
+		case resolveTransform:
+			for _, replacement := range resolution.events {
+				if explained := merged.Explain(replacement); explained.Accepted {
+					if err := merged.repository.Add(merged, replacement); err != nil {
+						return err
+					}
+				} else {
+					result.Rejected = append(result.Rejected, Conflict{Event: replacement, Explanation: explained, Attempt: attempt})
+				}
+			}
+			return nil
+		}
+
+		result.Rejected = append(result.Rejected, conflict)
+		return nil
+	}
+
+	for _, event := range theirs[len(base):] {
+		if err := apply(event, 1); err != nil {
+			return MergeResult{}, err
+		}
+	}
+	for _, event := range deferred {
+		if err := apply(event, 2); err != nil {
+			return MergeResult{}, err
+		}
+	}
+
+	result.Events = merged.GetEvents()
+	return result, nil
+}
+
+// hasPrefix reports whether log starts with prefix
+func hasPrefix(log, prefix []Event) bool {
+	if len(log) < len(prefix) {
+		return false
+	}
+	for i := range prefix {
+		if !reflect.DeepEqual(log[i], prefix[i]) {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,156 @@
+package atmos
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type SeatState struct {
+	Taken map[int]string
+}
+
+type SeatClaimedEvent struct {
+	Seat   int
+	Player string
+}
+
+func (e SeatClaimedEvent) Type() string { return "seat_claimed" }
+
+func newSeatEngine() *Engine {
+	engine := NewEngine()
+	engine.RegisterState("seats", SeatState{Taken: map[int]string{}})
+	engine.When("seat_claimed").
+		Requires(NewTypedValidator(TypedValidatorFunc[SeatClaimedEvent](func(e *Engine, event SeatClaimedEvent) bool {
+			_, taken := e.GetState("seats").(SeatState).Taken[event.Seat]
+			return !taken
+		}))).
+		Updates("seats", func(e *Engine, state interface{}, event Event) interface{} {
+			taken := map[int]string{}
+			for k, v := range state.(SeatState).Taken {
+				taken[k] = v
+			}
+			claim := event.(SeatClaimedEvent)
+			taken[claim.Seat] = claim.Player
+			return SeatState{Taken: taken}
+		})
+	return engine
+}
+
+// TestForkIsIndependent verifies forks diverge without affecting the original
+func TestForkIsIndependent(t *testing.T) {
+	engine := newSeatEngine()
+	engine.Emit(SeatClaimedEvent{Seat: 1, Player: "alice"})
+
+	fork := engine.Fork()
+	fork.Emit(SeatClaimedEvent{Seat: 2, Player: "bob"})
+	fork.RegisterService("fork-only", true)
+
+	assert.Len(t, engine.GetEvents(), 1)
+	assert.Len(t, fork.GetEvents(), 2)
+	assert.Nil(t, engine.GetService("fork-only"))
+}
+
+// TestForkKeepsSettings verifies forks accept and serialize events like the original
+func TestForkKeepsSettings(t *testing.T) {
+	engine := NewEngine(WithMaxPayloadSize(64, RejectOversized()), WithStrictEvents())
+	engine.RegisterEventTypes(PostedEvent{})
+	var actor string
+	engine.RegisterListener("posted", NewTypedListener(
+		TypedListenerFunc[PostedEvent](func(e *Engine, event PostedEvent) {
+			actor = e.Fork().Actor()
+		}),
+	))
+	require.True(t, engine.EmitAs("ann", PostedEvent{Author: "ann"}))
+	assert.Equal(t, "ann", actor, "Forked mid-emit, with the emit's actor")
+
+	fork := engine.Fork()
+	result := fork.EmitWithResult(PostedEvent{Body: strings.Repeat("x", 100)})
+	assert.Equal(t, PayloadSizeValidator, result.RejectedBy)
+	assert.False(t, fork.Emit(TestEvent{Name: "unregistered"}))
+}
+
+// TestMergeResolvesConflicts demonstrates reject, reorder, and transform resolutions
+func TestMergeResolvesConflicts(t *testing.T) {
+	server := newSeatEngine()
+	server.Emit(SeatClaimedEvent{Seat: 1, Player: "alice"})
+	base := server.GetEvents()
+
+	client := server.Fork()
+
+	// Server moves on: dave takes seat 2
+	server.Emit(SeatClaimedEvent{Seat: 2, Player: "dave"})
+
+	// Offline client: bob wants seat 2, carol wants seat 1 (already taken in base), erin seat 4
+	client.Emit(SeatClaimedEvent{Seat: 2, Player: "bob"})
+	client.SetEvents(append(client.GetEvents(), SeatClaimedEvent{Seat: 1, Player: "carol"}))
+	client.Emit(SeatClaimedEvent{Seat: 4, Player: "erin"})
+
+	resolver := func(merged *Engine, conflict Conflict) Resolution {
+		claim := conflict.Event.(SeatClaimedEvent)
+		if claim.Player == "bob" {
+			// Bump bob to the next free seat
+			return Transform(SeatClaimedEvent{Seat: 5, Player: "bob"})
+		}
+		return Reject()
+	}
+
+	result, err := server.Merge(base, server.GetEvents(), client.GetEvents(), resolver)
+	assert.NoError(t, err)
+	assert.Equal(t, []Event{
+		SeatClaimedEvent{Seat: 1, Player: "alice"},
+		SeatClaimedEvent{Seat: 2, Player: "dave"},
+		SeatClaimedEvent{Seat: 5, Player: "bob"},
+		SeatClaimedEvent{Seat: 4, Player: "erin"},
+	}, result.Events)
+	assert.Len(t, result.Rejected, 1)
+	assert.Equal(t, "carol", result.Rejected[0].Event.(SeatClaimedEvent).Player)
+	assert.False(t, result.Rejected[0].Explanation.Accepted)
+
+	// Merge never modifies the engine
+	assert.Len(t, server.GetEvents(), 2)
+}
+
+// TestMergeReorder verifies reordered events are retried after the rest of the branch
+func TestMergeReorder(t *testing.T) {
+	engine := NewEngine()
+	engine.RegisterState("seats", SeatState{Taken: map[int]string{}})
+
+	// Seat 9 may only be claimed once seat 8 is taken
+	engine.When("seat_claimed").
+		Requires(NewTypedValidator(TypedValidatorFunc[SeatClaimedEvent](func(e *Engine, event SeatClaimedEvent) bool {
+			if event.Seat != 9 {
+				return true
+			}
+			_, ready := e.GetState("seats").(SeatState).Taken[8]
+			return ready
+		}))).
+		Updates("seats", func(e *Engine, state interface{}, event Event) interface{} {
+			taken := map[int]string{}
+			for k, v := range state.(SeatState).Taken {
+				taken[k] = v
+			}
+			taken[event.(SeatClaimedEvent).Seat] = event.(SeatClaimedEvent).Player
+			return SeatState{Taken: taken}
+		})
+
+	theirs := []Event{SeatClaimedEvent{Seat: 9, Player: "x"}, SeatClaimedEvent{Seat: 8, Player: "y"}}
+	result, err := engine.Merge(nil, nil, theirs, func(merged *Engine, conflict Conflict) Resolution {
+		return Reorder()
+	})
+	assert.NoError(t, err)
+	assert.Equal(t, []Event{theirs[1], theirs[0]}, result.Events)
+	assert.Empty(t, result.Rejected)
+}
+
+// TestMergeRequiresCommonBase verifies branches must start with the base log
+func TestMergeRequiresCommonBase(t *testing.T) {
+	engine := newSeatEngine()
+	base := []Event{SeatClaimedEvent{Seat: 1, Player: "alice"}}
+	other := []Event{SeatClaimedEvent{Seat: 1, Player: "mallory"}}
+
+	_, err := engine.Merge(base, base, other, nil)
+	assert.ErrorIs(t, err, ErrNotForkedFromBase)
+}
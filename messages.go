@@ -0,0 +1,136 @@
+package atmos
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+)
+
+// Params are the values substituted into a message's {name} placeholders
+type Params map[string]interface{}
+
+// Message is a localizable rejection reason: a catalog key plus parameters.
+// Reasoned validators return one as their error instead of hardcoded text;
+// the engine renders it in the emit's Locale (see WithMessages, EmitCtx)
+// and reports it in EmitResult.Message for clients that render their own.
+type Message struct {
+	Key    string `json:"key"`
+	Params Params `json:"params,omitempty"`
+}
+
+// Msg creates a message.
+// Usage: return atmos.Msg("move.square_taken", atmos.Params{"square": event.Square})
+func Msg(key string, params Params) *Message {
+	return &Message{Key: key, Params: params}
+}
+
+// Error implements error, returning the key (render it with a Catalog for text)
+func (m *Message) Error() string {
+	return m.Key
+}
+
+// Catalog holds message templates per locale. Templates name parameters in
+// braces: "Square {square} is taken". Add messages before the catalog is
+// used; rendering is safe for concurrent use.
+type Catalog struct {
+	fallback Locale
+	messages map[Locale]map[string]string
+}
+
+// NewCatalog creates a catalog that falls back to the given locale for
+// messages missing from the requested one
+func NewCatalog(fallback Locale) *Catalog {
+	return &Catalog{fallback: fallback, messages: make(map[Locale]map[string]string)}
+}
+
+// Add adds templates for a locale, keyed by message key (chainable)
+func (c *Catalog) Add(locale Locale, templates map[string]string) *Catalog {
+	if c.messages[locale] == nil {
+		c.messages[locale] = make(map[string]string)
+	}
+	for key, template := range templates {
+		c.messages[locale][key] = template
+	}
+	return c
+}
+
+// Render renders a message in a locale, trying the locale ("fr-CA"), its
+// language ("fr"), and then the fallback locale. A message without a
+// template renders as its key.
+func (c *Catalog) Render(locale Locale, message *Message) string {
+	template, ok := c.template(locale, message.Key)
+	if !ok {
+		return message.Key
+	}
+	var b strings.Builder
+	for {
+		open := strings.IndexByte(template, '{')
+		end := strings.IndexByte(template[open+1:], '}')
+		if open < 0 || end < 0 {
+			b.WriteString(template)
+			return b.String()
+		}
+		b.WriteString(template[:open])
+		name := template[open+1 : open+1+end]
+		if value, exists := message.Params[name]; exists {
+			fmt.Fprint(&b, value)
+		} else {
+			b.WriteString(template[open : open+end+2]) // Leave unknown placeholders as written
+		}
+		template = template[open+end+2:]
+	}
+}
+
+// template finds a message's template for a locale
+func (c *Catalog) template(locale Locale, key string) (string, bool) {
+	candidates := []Locale{locale}
+	if language, _, found := strings.Cut(string(locale), "-"); found {
+		candidates = append(candidates, Locale(language))
+	}
+	candidates = append(candidates, c.fallback)
+	for _, candidate := range candidates {
+		if template, ok := c.messages[candidate][key]; ok {
+			return template, true
+		}
+	}
+	return "", false
+}
+
+// WithMessages renders Message rejection reasons in EmitResult.Reason,
+// warnings, and explanations using the catalog, in the Locale carried by
+// the emit's context (see EmitCtx), or the catalog's fallback locale
+func WithMessages(catalog *Catalog) EngineOption {
+	return func(e *Engine) {
+		e.messages = catalog
+	}
+}
+
+// Localize renders a message in a locale with the engine's catalog, e.g.
+// to show another player a rejection; without a catalog it returns the key
+func (e *Engine) Localize(locale Locale, message *Message) string {
+	if e.messages == nil {
+		return message.Key
+	}
+	return e.messages.Render(locale, message)
+}
+
+// reason renders a validator's rejection error as text
+func (e *Engine) reason(err error) string {
+	if err == nil {
+		return ""
+	}
+	if message := messageOf(err); message != nil && e.messages != nil {
+		locale, _ := Value[Locale](e)
+		return e.messages.Render(locale, message)
+	}
+	return err.Error()
+}
+
+// messageOf returns the message in a rejection error, if there is one
+func messageOf(err error) *Message {
+	var message *Message
+	if errors.As(err, &message) {
+		return message
+	}
+	return nil
+}
@@ -0,0 +1,80 @@
+package atmos
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// tokensLeft rejects overspending with a localizable message
+type tokensLeft struct{}
+
+func (tokensLeft) CheckTyped(engine *Engine, event TokensSpentEvent) error {
+	if left := engine.GetState("balance").(TokenBalance).Tokens; event.Amount > left {
+		return Msg("tokens.not_enough", Params{"left": left, "wanted": event.Amount})
+	}
+	return nil
+}
+
+func newMessagesCatalog() *Catalog {
+	return NewCatalog("en").
+		Add("en", map[string]string{
+			"tokens.not_enough": "You have {left} tokens, not {wanted}",
+			"tokens.big_spend":  "That's a big spend",
+		}).
+		Add("fr", map[string]string{
+			"tokens.not_enough": "Il vous reste {left} jetons, pas {wanted}",
+		})
+}
+
+// TestMessagesRenderedPerLocale verifies rejections render in the caller's locale and carry the key
+func TestMessagesRenderedPerLocale(t *testing.T) {
+	engine := newTokenEngine(WithMessages(newMessagesCatalog()))
+	engine.When("tokens_spent").
+		Requires(NewTypedReasonedValidator[TokensSpentEvent](tokensLeft{})).
+		Warns(BecauseMessage(Valid(TypedValidatorFunc[TokensSpentEvent](func(e *Engine, event TokensSpentEvent) bool {
+			return event.Amount < 2
+		})), Msg("tokens.big_spend", nil)))
+
+	result := engine.EmitCtx(WithValue(context.Background(), Locale("fr-CA")), TokensSpentEvent{Amount: 5})
+	assert.Equal(t, "Il vous reste 3 jetons, pas 5", result.Reason)
+	assert.Equal(t, Msg("tokens.not_enough", Params{"left": 3, "wanted": 5}), result.Message)
+	assert.Equal(t, "You have 3 tokens, not 5", engine.Localize("de", result.Message), "Falls back")
+	assert.Equal(t, "You have 3 tokens, not 5", engine.EmitWithResult(TokensSpentEvent{Amount: 5}).Reason)
+
+	result = engine.EmitWithResult(TokensSpentEvent{Amount: 2})
+	assert.True(t, result.Accepted)
+	assert.Equal(t, []Warning{{Validator: "atmos.TypedValidatorFunc[github.com/cumulusrpg/atmos.TokensSpentEvent]", Reason: "That's a big spend", Message: Msg("tokens.big_spend", nil)}}, result.Warnings)
+}
+
+// TestMessagesWithoutCatalog verifies messages fall back to their keys
+func TestMessagesWithoutCatalog(t *testing.T) {
+	engine := newTokenEngine()
+	engine.RegisterPolicy("affordable", NewTypedReasonedValidator[TokensSpentEvent](tokensLeft{}))
+	engine.When("tokens_spent").Requires(Policy("affordable"))
+
+	result := engine.EmitWithResult(TokensSpentEvent{Amount: 5})
+	assert.Equal(t, "tokens.not_enough", result.Reason)
+	assert.Equal(t, "tokens.not_enough", result.Message.Key, "Policies pass messages through")
+}
+
+// TestCatalogRender verifies placeholder substitution
+func TestCatalogRender(t *testing.T) {
+	catalog := NewCatalog("en").Add("en", map[string]string{"greet": "Hi {name}, {missing} {"})
+	assert.Equal(t, "Hi Ada, {missing} {", catalog.Render("en", Msg("greet", Params{"name": "Ada"})))
+	assert.Equal(t, "unknown", catalog.Render("en", Msg("unknown", nil)))
+}
+
+// TestMessagesOfBatchRejections verifies batch rejections and explanations carry the failing validator's message
+func TestMessagesOfBatchRejections(t *testing.T) {
+	engine := newTokenEngine(WithMessages(newMessagesCatalog()))
+	engine.When("tokens_spent").Requires(NewTypedReasonedValidator[TokensSpentEvent](tokensLeft{}))
+
+	result := engine.EmitAll(TokensSpentEvent{Amount: 1}, TokensSpentEvent{Amount: 5})
+	assert.Equal(t, "event 1 (tokens_spent): You have 2 tokens, not 5", result.Reason)
+	assert.Equal(t, Msg("tokens.not_enough", Params{"left": 2, "wanted": 5}), result.Message)
+
+	explanation := engine.Explain(TokensSpentEvent{Amount: 5})
+	assert.Equal(t, Msg("tokens.not_enough", Params{"left": 3, "wanted": 5}), explanation.FirstFailure.Message)
+}
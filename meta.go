@@ -0,0 +1,99 @@
+package atmos
+
+import "github.com/cumulusrpg/atmos/types"
+
+// Meta-events describe what the engine itself did. They are never added to
+// the game's log; engines created WithMetaEvents hand them to separate
+// listeners, so monitoring and audit code can observe the engine without
+// wrapping it.
+
+// EventRejected is reported when an emit is refused by a validator or fails
+// to commit
+type EventRejected struct {
+	EventType  string `json:"event_type"`
+	RejectedBy string `json:"rejected_by,omitempty"` // Name of the rejecting validator
+	Reason     string `json:"reason,omitempty"`
+	Error      string `json:"error,omitempty"` // Set when the emit failed rather than being rejected
+	Event      Event  `json:"-"`               // The refused event
+}
+
+// Type implements Event
+func (e EventRejected) Type() string { return "event_rejected" }
+
+// SnapshotCreated is reported when a state snapshot is stored
+type SnapshotCreated struct {
+	State string `json:"state"`
+}
+
+// Type implements Event
+func (e SnapshotCreated) Type() string { return "snapshot_created" }
+
+// SnapshotCleared is reported when a state snapshot is removed
+type SnapshotCleared struct {
+	State string `json:"state"`
+}
+
+// Type implements Event
+func (e SnapshotCleared) Type() string { return "snapshot_cleared" }
+
+// EventsLoaded is reported when the log is replaced by SetEvents
+type EventsLoaded struct {
+	Count int `json:"count"`
+}
+
+// Type implements Event
+func (e EventsLoaded) Type() string { return "events_loaded" }
+
+// InvariantBroken is reported when an invariant check fails after a commit
+type InvariantBroken struct {
+	Invariant string `json:"invariant"`
+	EventType string `json:"event_type,omitempty"`
+	Error     string `json:"error"`
+}
+
+// Type implements Event
+func (e InvariantBroken) Type() string { return "invariant_violated" }
+
+// WithMetaEvents sends the engine's meta-events to listeners, in order,
+// as they happen
+func WithMetaEvents(listeners ...EventListener) EngineOption {
+	return func(e *Engine) {
+		e.metaListeners = append(e.metaListeners, listeners...)
+	}
+}
+
+// MetaLog is a meta-event listener that appends each meta-event to a
+// separate repository, for an audit trail kept apart from the game's log.
+// Repository errors are ignored.
+// Usage: NewEngine(WithMetaEvents(MetaLog(auditRepo)))
+func MetaLog(repo types.EventRepository) EventListener {
+	return metaLog{repo: repo}
+}
+
+type metaLog struct {
+	repo types.EventRepository
+}
+
+// Handle implements EventListener
+func (l metaLog) Handle(engine types.Engine, event Event) {
+	_ = l.repo.Add(engine, event)
+}
+
+// meta reports a meta-event to the meta listeners
+func (e *Engine) meta(event Event) {
+	for _, listener := range e.metaListeners {
+		listener.Handle(e, event)
+	}
+}
+
+// metaRejected reports a refused emit, if it was refused
+func (e *Engine) metaRejected(event Event, result EmitResult) {
+	if result.Accepted || result.Queued || len(e.metaListeners) == 0 {
+		return
+	}
+	rejected := EventRejected{EventType: event.Type(), RejectedBy: result.RejectedBy, Reason: result.Reason, Event: event}
+	if result.Err != nil {
+		rejected.Error = result.Err.Error()
+	}
+	e.meta(rejected)
+}
@@ -0,0 +1,51 @@
+package atmos
+
+import (
+	"testing"
+
+	"github.com/cumulusrpg/atmos/repository"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// TestMetaEventsReportRejections verifies rejected and failed emits are reported, accepted ones aren't
+func TestMetaEventsReportRejections(t *testing.T) {
+	audit := repository.NewInMemory()
+	engine := newBatchEngine(WithMetaEvents(MetaLog(audit)), WithStrictEvents())
+
+	assert.True(t, engine.Emit(TokensSpentEvent{Amount: 1}))
+	assert.False(t, engine.Emit(TokensSpentEvent{Amount: 5}))
+	assert.False(t, engine.EmitAll(TokensSpentEvent{Amount: 1}, TokensSpentEvent{Amount: 5}).Accepted)
+	assert.False(t, engine.Emit(TypoEvent{}))
+
+	meta := audit.GetAll(engine)
+	require.Len(t, meta, 3)
+	assert.Equal(t, EventRejected{EventType: "tokens_spent", RejectedBy: "atmos.affordable", Event: TokensSpentEvent{Amount: 5}}, meta[0])
+	assert.Equal(t, "event 1 (tokens_spent): ", meta[1].(EventRejected).Reason)
+	assert.Equal(t, TokensSpentEvent{Amount: 5}, meta[1].(EventRejected).Event)
+	assert.Equal(t, "mvoe", meta[2].(EventRejected).EventType)
+	assert.NotEmpty(t, meta[2].(EventRejected).Error)
+	assert.Len(t, engine.GetEvents(), 1, "Meta-events never enter the game log")
+}
+
+// TestMetaEventsReportEngineChanges verifies snapshots, loads and invariant violations are reported
+func TestMetaEventsReportEngineChanges(t *testing.T) {
+	audit := repository.NewInMemory()
+	engine := newTokenEngine(
+		WithRepository(repository.NewInMemorySnapshot()),
+		WithMetaEvents(MetaLog(audit)),
+		WithInvariantChecks(func(*InvariantViolation) {}),
+	)
+
+	require.NoError(t, engine.SetSnapshot("balance", TokenBalance{Tokens: 1}))
+	require.NoError(t, engine.ClearSnapshot("balance"))
+	engine.SetEvents([]Event{TokensSpentEvent{Amount: 1}})
+	engine.Emit(TokensSpentEvent{Amount: 3})
+
+	assert.Equal(t, []Event{
+		SnapshotCreated{State: "balance"},
+		SnapshotCleared{State: "balance"},
+		EventsLoaded{Count: 1},
+		InvariantBroken{Invariant: "tokens never go negative", EventType: "tokens_spent", Error: "negative balance"},
+	}, audit.GetAll(engine))
+}
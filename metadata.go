@@ -0,0 +1,38 @@
+package atmos
+
+// Metadata describes the circumstances of an emit (who, from where, with
+// which client) rather than what happened, so event structs don't need
+// those fields
+type Metadata map[string]string
+
+// MetadataActor is the metadata key EmitWithMetadata also uses as the actor
+// for actor policies (see EmitAs)
+const MetadataActor = "actor"
+
+// EmitWithMetadata emits an event with metadata such as the actor, client
+// version or origin IP. The metadata is visible to validators, hooks and
+// listeners via Metadata() for the duration of the emit, including events
+// emitted by those listeners, and is stored with the event when the
+// repository stores envelopes (see Envelopes).
+// Usage: EmitWithMetadata(Metadata{"actor": "alice", "ip": remoteAddr}, event)
+func (e *Engine) EmitWithMetadata(metadata Metadata, event Event) EmitResult {
+	previous, previousActor := e.metadata, e.actor
+	e.metadata = metadata
+	if actor, ok := metadata[MetadataActor]; ok {
+		e.actor = actor
+	}
+	defer func() { e.metadata, e.actor = previous, previousActor }()
+
+	return e.EmitWithResult(event)
+}
+
+// Metadata returns the metadata of the emit currently being processed, or
+// nil outside of EmitWithMetadata. It must not be modified.
+func (e *Engine) Metadata() Metadata {
+	return e.metadata
+}
+
+// MetadataValue returns one metadata value of the emit in progress ("" if unset)
+func (e *Engine) MetadataValue(key string) string {
+	return e.metadata[key]
+}
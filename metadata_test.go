@@ -0,0 +1,69 @@
+package atmos
+
+import (
+	"testing"
+
+	"github.com/cumulusrpg/atmos/repository"
+	"github.com/cumulusrpg/atmos/types"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// clientVersion rejects emits from outdated clients
+type clientVersion struct{}
+
+func (clientVersion) Validate(engine types.Engine, event Event) bool {
+	return engine.(*Engine).MetadataValue("client") != "1.0"
+}
+
+// metadataRecorder records the metadata each listener call sees
+type metadataRecorder struct {
+	seen []Metadata
+}
+
+func (r *metadataRecorder) Handle(engine types.Engine, event Event) {
+	r.seen = append(r.seen, engine.(*Engine).Metadata())
+}
+
+// TestEmitWithMetadataVisibleDuringEmit verifies validators and listeners read the emit's metadata
+func TestEmitWithMetadataVisibleDuringEmit(t *testing.T) {
+	engine := NewEngine()
+	recorder := &metadataRecorder{}
+	engine.When("test_event").Requires(clientVersion{}).Then(recorder)
+
+	result := engine.EmitWithMetadata(Metadata{"client": "1.0"}, TestEvent{})
+	assert.Equal(t, "atmos.clientVersion", result.RejectedBy)
+
+	assert.True(t, engine.EmitWithMetadata(Metadata{"client": "2.1", "ip": "10.0.0.7"}, TestEvent{}).Accepted)
+	engine.Emit(TestEvent{})
+
+	assert.Equal(t, []Metadata{{"client": "2.1", "ip": "10.0.0.7"}, nil}, recorder.seen)
+	assert.Nil(t, engine.Metadata(), "Metadata is cleared after the emit")
+}
+
+// TestEmitWithMetadataActor verifies the actor key drives actor policies
+func TestEmitWithMetadataActor(t *testing.T) {
+	engine := NewEngine()
+	engine.When("test_event").AllowedBy("only alice", ActorIn("alice"))
+
+	assert.False(t, engine.EmitWithMetadata(Metadata{"ip": "10.0.0.7"}, TestEvent{}).Accepted)
+	assert.True(t, engine.EmitWithMetadata(Metadata{MetadataActor: "alice"}, TestEvent{}).Accepted)
+	assert.Equal(t, "", engine.Actor())
+}
+
+// TestMetadataStoredInEnvelopes verifies metadata travels with the committed event
+func TestMetadataStoredInEnvelopes(t *testing.T) {
+	engine := NewEngine(WithRepository(repository.NewIdentified()), WithEventIDs(func() string { return "e1" }))
+	engine.RegisterEventTypes(TestEvent{})
+	engine.EmitWithMetadata(Metadata{"actor": "alice"}, TestEvent{Name: "t"})
+
+	envelopes := engine.Envelopes()
+	require.Len(t, envelopes, 1)
+	assert.Equal(t, map[string]string{"actor": "alice"}, envelopes[0].Metadata)
+
+	data, err := engine.MarshalEnvelopes(envelopes)
+	require.NoError(t, err)
+	restored, err := engine.UnmarshalEnvelopes(data)
+	require.NoError(t, err)
+	assert.Equal(t, map[string]string{"actor": "alice"}, restored[0].Metadata)
+}
@@ -0,0 +1,99 @@
+package atmos
+
+import "strings"
+
+// NamespaceSeparator joins a namespace and a name in qualified event types
+// and state names ("chess.move_made")
+const NamespaceSeparator = "."
+
+// Namespace scopes event types and state names under a prefix, so modules
+// installed into the same engine can't collide on generic names:
+//
+//	chess := engine.Namespace("chess")
+//	chess.RegisterState("board", NewBoard())
+//	chess.When("move_made").Updates(chess.State("board"), ApplyMove)
+//
+//	func (e MoveMadeEvent) Type() string { return atmos.Qualify("chess", "move_made") }
+//
+// Events must report their qualified type themselves. State names passed to
+// Updates are not qualified automatically; use State.
+type Namespace struct {
+	engine *Engine
+	name   string
+}
+
+// Qualify returns a name qualified by a namespace
+func Qualify(namespace, name string) string {
+	return namespace + NamespaceSeparator + name
+}
+
+// SplitQualified splits a qualified name at its last separator ("" namespace
+// if unqualified)
+func SplitQualified(qualified string) (namespace, name string) {
+	if i := strings.LastIndex(qualified, NamespaceSeparator); i >= 0 {
+		return qualified[:i], qualified[i+len(NamespaceSeparator):]
+	}
+	return "", qualified
+}
+
+// Namespace returns a view of the engine scoped to a namespace
+func (e *Engine) Namespace(name string) *Namespace {
+	return &Namespace{engine: e, name: name}
+}
+
+// Namespace returns a nested namespace ("chess.clock")
+func (n *Namespace) Namespace(name string) *Namespace {
+	return &Namespace{engine: n.engine, name: Qualify(n.name, name)}
+}
+
+// Name returns the namespace's full name
+func (n *Namespace) Name() string {
+	return n.name
+}
+
+// Type returns an event type qualified by the namespace
+func (n *Namespace) Type(eventType string) string {
+	return Qualify(n.name, eventType)
+}
+
+// State returns a state name qualified by the namespace
+func (n *Namespace) State(name string) string {
+	return Qualify(n.name, name)
+}
+
+// When starts a registration chain for an event type in the namespace
+func (n *Namespace) When(eventType string, factory ...func() Event) *EventRegistration {
+	return n.engine.When(n.Type(eventType), factory...)
+}
+
+// WhenAny starts a registration chain for several event types in the namespace
+func (n *Namespace) WhenAny(eventTypes ...string) *EventGroup {
+	qualified := make([]string, len(eventTypes))
+	for i, eventType := range eventTypes {
+		qualified[i] = n.Type(eventType)
+	}
+	return n.engine.WhenAny(qualified...)
+}
+
+// RegisterState registers a state in the namespace
+func (n *Namespace) RegisterState(name string, initialState interface{}) {
+	n.engine.RegisterState(n.State(name), initialState)
+}
+
+// GetState projects a state in the namespace
+func (n *Namespace) GetState(name string) interface{} {
+	return n.engine.GetState(n.State(name))
+}
+
+// EventTypes returns the event types in the namespace (including nested
+// namespaces) with anything registered for them, sorted
+func (n *Namespace) EventTypes() []string {
+	var types []string
+	prefix := n.name + NamespaceSeparator
+	for _, description := range n.engine.Describe() {
+		if strings.HasPrefix(description.EventType, prefix) {
+			types = append(types, description.EventType)
+		}
+	}
+	return types
+}
@@ -0,0 +1,64 @@
+package atmos
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+type ChessMoveEvent struct{}
+
+func (e ChessMoveEvent) Type() string { return Qualify("chess", "move_made") }
+
+type CheckersMoveEvent struct{}
+
+func (e CheckersMoveEvent) Type() string { return Qualify("checkers", "move_made") }
+
+// TestNamespacesKeepModulesApart verifies two modules can use the same names in one engine
+func TestNamespacesKeepModulesApart(t *testing.T) {
+	engine := NewEngine()
+	for _, module := range []string{"chess", "checkers"} {
+		ns := engine.Namespace(module)
+		ns.RegisterState("moves", 0)
+		ns.When("move_made").Updates(ns.State("moves"), func(e *Engine, state interface{}, event Event) interface{} {
+			return state.(int) + 1
+		})
+	}
+
+	engine.Emit(ChessMoveEvent{})
+	engine.Emit(ChessMoveEvent{})
+	engine.Emit(CheckersMoveEvent{})
+
+	assert.Equal(t, 2, engine.Namespace("chess").GetState("moves"))
+	assert.Equal(t, 1, engine.GetState("checkers.moves"))
+	assert.Equal(t, []string{"chess.move_made"}, engine.Namespace("chess").EventTypes())
+}
+
+// TestNestedNamespaces verifies nested names and splitting
+func TestNestedNamespaces(t *testing.T) {
+	clock := NewEngine().Namespace("chess").Namespace("clock")
+	assert.Equal(t, "chess.clock", clock.Name())
+	assert.Equal(t, "chess.clock.flag_fell", clock.Type("flag_fell"))
+
+	namespace, name := SplitQualified("chess.clock.flag_fell")
+	assert.Equal(t, "chess.clock", namespace)
+	assert.Equal(t, "flag_fell", name)
+	namespace, name = SplitQualified("move_made")
+	assert.Equal(t, "", namespace)
+	assert.Equal(t, "move_made", name)
+}
+
+// TestNamespaceWhenAny verifies group registrations are qualified too
+func TestNamespaceWhenAny(t *testing.T) {
+	engine := NewEngine()
+	chess := engine.Namespace("chess")
+	chess.RegisterState("turns", 0)
+	chess.WhenAny("move_made", "resigned").Updates(chess.State("turns"), func(e *Engine, state interface{}, event Event) interface{} {
+		return state.(int) + 1
+	})
+
+	engine.Emit(ChessMoveEvent{})
+	engine.Emit(CheckersMoveEvent{})
+	assert.Equal(t, 1, chess.GetState("turns"))
+	assert.Equal(t, []string{"chess.move_made", "chess.resigned"}, chess.EventTypes())
+}
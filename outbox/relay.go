@@ -0,0 +1,110 @@
+// Package outbox relays committed events from an OutboxRepository to an
+// external system (Kafka, NATS, webhooks, ...). Because the repository writes
+// outbox entries atomically with the events themselves, nothing committed is
+// lost if the publisher is down: entries stay pending and are retried.
+package outbox
+
+import (
+	"context"
+	"time"
+
+	"github.com/cumulusrpg/atmos/types"
+)
+
+// Publisher delivers one outbox entry to an external system
+type Publisher interface {
+	Publish(ctx context.Context, entry types.OutboxEntry) error
+}
+
+// PublisherFunc adapts a function into a Publisher
+type PublisherFunc func(ctx context.Context, entry types.OutboxEntry) error
+
+// Publish implements Publisher
+func (f PublisherFunc) Publish(ctx context.Context, entry types.OutboxEntry) error {
+	return f(ctx, entry)
+}
+
+// Relay drains an outbox into a publisher, preserving commit order:
+// a failed entry blocks later ones until it is published.
+type Relay struct {
+	repository types.OutboxRepository
+	publisher  Publisher
+	batchSize  int
+}
+
+// Option configures relay construction
+type Option func(*Relay)
+
+// WithBatchSize sets how many entries are fetched per pass (default 100)
+func WithBatchSize(size int) Option {
+	return func(r *Relay) {
+		r.batchSize = size
+	}
+}
+
+// NewRelay creates a relay from an outbox repository to a publisher
+func NewRelay(repository types.OutboxRepository, publisher Publisher, opts ...Option) *Relay {
+	relay := &Relay{
+		repository: repository,
+		publisher:  publisher,
+		batchSize:  100,
+	}
+
+	// Apply options
+	for _, opt := range opts {
+		opt(relay)
+	}
+
+	return relay
+}
+
+// RunOnce publishes pending entries until the outbox is empty or a publish
+// fails. It returns how many entries were published and the publish error,
+// if any (the failed entry has its attempt count incremented).
+func (r *Relay) RunOnce(ctx context.Context) (int, error) {
+	published := 0
+	for {
+		entries, err := r.repository.PendingOutbox(r.batchSize)
+		if err != nil {
+			return published, err
+		}
+		if len(entries) == 0 {
+			return published, nil
+		}
+
+		for _, entry := range entries {
+			if err := ctx.Err(); err != nil {
+				return published, err
+			}
+			if err := r.publisher.Publish(ctx, entry); err != nil {
+				if markErr := r.repository.MarkFailed(entry.ID, err); markErr != nil {
+					return published, markErr
+				}
+				return published, err
+			}
+			if err := r.repository.MarkPublished(entry.ID); err != nil {
+				return published, err
+			}
+			published++
+		}
+	}
+}
+
+// Run calls RunOnce every interval until ctx is cancelled. Publish failures
+// are reported to onError (if non-nil) and retried on the next tick.
+func (r *Relay) Run(ctx context.Context, interval time.Duration, onError func(error)) error {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		if _, err := r.RunOnce(ctx); err != nil && ctx.Err() == nil && onError != nil {
+			onError(err)
+		}
+
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-ticker.C:
+		}
+	}
+}
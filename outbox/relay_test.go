@@ -0,0 +1,93 @@
+package outbox_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/cumulusrpg/atmos"
+	"github.com/cumulusrpg/atmos/outbox"
+	"github.com/cumulusrpg/atmos/repository"
+	"github.com/cumulusrpg/atmos/types"
+	"github.com/stretchr/testify/assert"
+)
+
+type PingEvent struct {
+	N int
+}
+
+func (e PingEvent) Type() string { return "ping" }
+
+// TestRelayPublishesInOrderAndRetries verifies failed entries block and are retried
+func TestRelayPublishesInOrderAndRetries(t *testing.T) {
+	repo := repository.NewInMemoryOutbox()
+	engine := atmos.NewEngine(atmos.WithRepository(repo))
+
+	for i := 1; i <= 3; i++ {
+		engine.Emit(PingEvent{N: i})
+	}
+
+	var published []int
+	failOn := 2
+	relay := outbox.NewRelay(repo, outbox.PublisherFunc(func(ctx context.Context, entry types.OutboxEntry) error {
+		n := entry.Event.(PingEvent).N
+		if n == failOn {
+			return errors.New("broker unavailable")
+		}
+		published = append(published, n)
+		return nil
+	}), outbox.WithBatchSize(2))
+
+	count, err := relay.RunOnce(context.Background())
+	assert.EqualError(t, err, "broker unavailable")
+	assert.Equal(t, 1, count)
+	assert.Equal(t, []int{1}, published)
+
+	pending, _ := repo.PendingOutbox(0)
+	assert.Len(t, pending, 2)
+	assert.Equal(t, 1, pending[0].Attempts)
+	assert.Equal(t, "broker unavailable", pending[0].LastErr)
+
+	// Broker recovers: remaining entries go out in commit order
+	failOn = 0
+	count, err = relay.RunOnce(context.Background())
+	assert.NoError(t, err)
+	assert.Equal(t, 2, count)
+	assert.Equal(t, []int{1, 2, 3}, published)
+
+	pending, _ = repo.PendingOutbox(0)
+	assert.Empty(t, pending)
+}
+
+// TestRelayRunUntilCancelled verifies the background loop drains new commits
+func TestRelayRunUntilCancelled(t *testing.T) {
+	repo := repository.NewInMemoryOutbox()
+	engine := atmos.NewEngine(atmos.WithRepository(repo))
+
+	published := make(chan int, 10)
+	relay := outbox.NewRelay(repo, outbox.PublisherFunc(func(ctx context.Context, entry types.OutboxEntry) error {
+		published <- entry.Event.(PingEvent).N
+		return nil
+	}))
+
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan error)
+	go func() { done <- relay.Run(ctx, time.Millisecond, nil) }()
+
+	engine.Emit(PingEvent{N: 7})
+	select {
+	case n := <-published:
+		assert.Equal(t, 7, n)
+	case <-time.After(time.Second):
+		t.Fatal("event was not relayed")
+	}
+
+	cancel()
+	assert.ErrorIs(t, <-done, context.Canceled)
+
+	// Restored history is not re-published
+	engine.SetEvents([]atmos.Event{PingEvent{N: 1}})
+	pending, _ := repo.PendingOutbox(0)
+	assert.Empty(t, pending)
+}
@@ -0,0 +1,124 @@
+package atmos
+
+import (
+	"context"
+	"errors"
+)
+
+// ErrPaused is returned by emits refused while the engine is paused; the
+// emit can be retried once it resumes
+var ErrPaused = errors.New("engine is paused")
+
+// PauseOption configures a pause
+type PauseOption func(*pause)
+
+// RejectWhilePaused refuses emits with ErrPaused instead of buffering them
+func RejectWhilePaused() PauseOption {
+	return func(p *pause) {
+		p.reject = true
+	}
+}
+
+// MaxBuffered caps how many emits are buffered; further emits fail with ErrPaused
+func MaxBuffered(n int) PauseOption {
+	return func(p *pause) {
+		p.limit = n
+	}
+}
+
+// pause is an engine's paused emission
+type pause struct {
+	reject   bool
+	limit    int // 0 means unlimited
+	buffered []pausedEmit
+}
+
+// pausedEmit is an emit waiting for Resume, with the circumstances it was made in
+type pausedEmit struct {
+	events   []Event // more than one for EmitAll
+	batch    bool
+	id       string
+	actor    string
+	metadata Metadata
+	ctx      context.Context
+	replay   bool // made in replay mode (see Replaying)
+}
+
+// Pause stops emission for maintenance such as migrating the repository.
+// By default emits are buffered, returning a result with Queued set, and
+// processed in order by Resume; with RejectWhilePaused they fail with
+// ErrPaused. Reads are unaffected. Pausing a paused engine is a no-op.
+func (e *Engine) Pause(opts ...PauseOption) {
+	if e.paused != nil {
+		return
+	}
+	p := &pause{}
+
+	// Apply options
+	for _, opt := range opts {
+		opt(p)
+	}
+
+	e.paused = p
+}
+
+// Paused reports whether emission is paused
+func (e *Engine) Paused() bool {
+	return e.paused != nil
+}
+
+// Resume restarts emission, first emitting the buffered emits in the order
+// they were made, each with the actor, metadata, context, and replay mode it
+// was made with. Emits with an ID are checked for duplicates again, so
+// retries buffered twice commit once. It returns their results in that
+// order.
+func (e *Engine) Resume() []EmitResult {
+	if e.paused == nil {
+		return nil
+	}
+	buffered := e.paused.buffered
+	e.paused = nil
+
+	results := make([]EmitResult, len(buffered))
+	for i, emit := range buffered {
+		results[i] = e.resumed(emit)
+	}
+	return results
+}
+
+// resumed runs a buffered emit in its original circumstances
+func (e *Engine) resumed(emit pausedEmit) EmitResult {
+	previousActor, previousMetadata, previousCtx := e.actor, e.metadata, e.ctx
+	e.actor, e.metadata, e.ctx = emit.actor, emit.metadata, emit.ctx
+	defer func() { e.actor, e.metadata, e.ctx = previousActor, previousMetadata, previousCtx }()
+	if emit.replay {
+		e.replaying++
+		defer func() { e.replaying-- }()
+	}
+
+	switch {
+	case emit.batch:
+		return e.EmitAll(emit.events...)
+	case emit.id != "":
+		return e.EmitWithID(emit.id, emit.events[0])
+	}
+	return e.emit(emit.events[0], "")
+}
+
+// hold buffers an emit made while paused, or refuses it
+func (e *Engine) hold(events []Event, batch bool, id string) EmitResult {
+	p := e.paused
+	if p.reject || (p.limit > 0 && len(p.buffered) >= p.limit) {
+		return EmitResult{Err: ErrPaused}
+	}
+	p.buffered = append(p.buffered, pausedEmit{
+		events:   events,
+		batch:    batch,
+		id:       id,
+		actor:    e.actor,
+		metadata: e.metadata,
+		ctx:      e.ctx,
+		replay:   e.replaying > 0,
+	})
+	return EmitResult{Queued: true}
+}
@@ -0,0 +1,91 @@
+package atmos
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// TestPauseBuffersEmitsUntilResume verifies buffered emits run in order, in their original circumstances
+func TestPauseBuffersEmitsUntilResume(t *testing.T) {
+	engine := newBatchEngine()
+	var actors []string
+	engine.When("tokens_spent").Then(NewTypedListener(TypedListenerFunc[TokensSpentEvent](func(e *Engine, event TokensSpentEvent) {
+		locale, _ := Value[Locale](e)
+		actors = append(actors, e.Actor()+"/"+string(locale))
+	})))
+
+	engine.Pause()
+	assert.True(t, engine.Paused())
+	assert.True(t, engine.EmitAsWithResult("alice", TokensSpentEvent{Amount: 1}).Queued)
+	assert.True(t, engine.EmitAll(TokensSpentEvent{Amount: 1}, TokensSpentEvent{Amount: 1}).Queued)
+	assert.True(t, engine.EmitCtx(WithValue(context.Background(), Locale("fr")), TokensSpentEvent{Amount: 1}).Queued)
+	assert.Empty(t, engine.GetEvents(), "Nothing commits while paused")
+	assert.Equal(t, TokenBalance{Tokens: 3}, engine.GetState("balance"), "Reads still work")
+
+	results := engine.Resume()
+	require.Len(t, results, 3)
+	assert.True(t, results[0].Accepted)
+	assert.True(t, results[1].Accepted)
+	assert.False(t, results[2].Accepted, "Validated on resume, when the balance has run out")
+	assert.Equal(t, []string{"alice/", "/", "/"}, actors)
+	assert.False(t, engine.Paused())
+	assert.Nil(t, engine.Resume())
+}
+
+// TestPauseRejecting verifies emits can be refused with a retryable error instead
+func TestPauseRejecting(t *testing.T) {
+	engine := newBatchEngine()
+	engine.Pause(RejectWhilePaused())
+	assert.ErrorIs(t, engine.EmitWithResult(TokensSpentEvent{Amount: 1}).Err, ErrPaused)
+	assert.Empty(t, engine.Resume())
+	assert.True(t, engine.Emit(TokensSpentEvent{Amount: 1}), "Retrying after resume works")
+}
+
+// TestPauseBufferLimit verifies emits beyond the buffer limit are refused
+func TestPauseBufferLimit(t *testing.T) {
+	engine := newBatchEngine()
+	engine.Pause(MaxBuffered(1))
+	assert.True(t, engine.EmitWithResult(TokensSpentEvent{Amount: 1}).Queued)
+	assert.ErrorIs(t, engine.EmitWithResult(TokensSpentEvent{Amount: 1}).Err, ErrPaused)
+	assert.Len(t, engine.Resume(), 1)
+	assert.Len(t, engine.GetEvents(), 1)
+}
+
+// TestPauseChecksBufferedIDsOnResume verifies retries buffered with the same ID commit once
+func TestPauseChecksBufferedIDsOnResume(t *testing.T) {
+	engine := newIdentifiedEngine()
+
+	engine.Pause()
+	assert.True(t, engine.EmitWithID("spend-1", TokensSpentEvent{Amount: 1}).Queued)
+	assert.True(t, engine.EmitWithID("spend-1", TokensSpentEvent{Amount: 1}).Queued)
+
+	results := engine.Resume()
+	require.Len(t, results, 2)
+	assert.True(t, results[0].Accepted)
+	assert.False(t, results[0].Duplicate)
+	assert.True(t, results[1].Accepted)
+	assert.True(t, results[1].Duplicate, "The retry finds the first commit")
+	assert.Len(t, engine.Envelopes(), 1)
+}
+
+// TestPauseKeepsReplayMode verifies emits buffered in replay mode are resumed in it
+func TestPauseKeepsReplayMode(t *testing.T) {
+	engine := NewEngine()
+	var modes []bool
+	engine.When("test_event").Then(NewTypedListener(TypedListenerFunc[TestEvent](func(e *Engine, event TestEvent) {
+		modes = append(modes, IsReplaying(e))
+	})))
+
+	engine.Pause()
+	assert.ErrorIs(t, engine.Replay([]Event{TestEvent{Name: "a"}}), ErrPaused)
+	engine.WhileReplaying(func() { engine.Emit(TestEvent{Name: "b"}) })
+	engine.Emit(TestEvent{Name: "c"})
+	assert.Empty(t, engine.GetEvents(), "Replay buffers nothing")
+
+	engine.Resume()
+	assert.Equal(t, []bool{true, false}, modes)
+	assert.False(t, engine.Replaying())
+}
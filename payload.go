@@ -0,0 +1,191 @@
+package atmos
+
+import (
+	"fmt"
+	"reflect"
+	"strconv"
+	"strings"
+
+	"github.com/cumulusrpg/atmos/types"
+)
+
+// PayloadError describes an event field that breaks its validate tag
+type PayloadError struct {
+	Field string // JSON name of the field, dotted for nested structs
+	Rule  string // the failing rule, e.g. "max"
+	Param string // the rule's parameter, e.g. "8"
+}
+
+// Error implements error
+func (e PayloadError) Error() string {
+	switch e.Rule {
+	case "required":
+		return e.Field + " is required"
+	case "min":
+		return fmt.Sprintf("%s must be at least %s", e.Field, e.Param)
+	case "max":
+		return fmt.Sprintf("%s must be at most %s", e.Field, e.Param)
+	case "len":
+		return fmt.Sprintf("%s must have length %s", e.Field, e.Param)
+	case "oneof":
+		return fmt.Sprintf("%s must be one of %s", e.Field, strings.Join(strings.Fields(e.Param), ", "))
+	}
+	return fmt.Sprintf("%s has unknown validation rule %q", e.Field, e.Rule)
+}
+
+// PayloadErrors lists every field of an event that breaks its tag
+type PayloadErrors []PayloadError
+
+// Error implements error
+func (e PayloadErrors) Error() string {
+	messages := make([]string, len(e))
+	for i, err := range e {
+		messages[i] = err.Error()
+	}
+	return strings.Join(messages, "; ")
+}
+
+// WithPayloadValidation checks every emitted event's validate struct tags
+// (see ValidatePayload) before any other validator runs. Events failing
+// the check are rejected by "payload", with the broken rules as the reason.
+func WithPayloadValidation() EngineOption {
+	return func(e *Engine) {
+		e.payloadValidation = true
+	}
+}
+
+// ValidatePayload checks an event's fields against their validate tags,
+// returning PayloadErrors for every broken rule:
+//
+//	type MoveEvent struct {
+//		Position int    `json:"position" validate:"min=0,max=8"`
+//		Player   string `json:"player" validate:"required,oneof=X O"`
+//	}
+//
+// Rules are comma-separated: required (not the zero value), min and max
+// (bounds for numbers, or for the length of strings, slices and maps),
+// len (exact length), and oneof (space-separated allowed values). Nested
+// structs are checked too.
+func ValidatePayload(event Event) error {
+	value := reflect.ValueOf(event)
+	for value.Kind() == reflect.Ptr && !value.IsNil() {
+		value = value.Elem()
+	}
+	if value.Kind() != reflect.Struct {
+		return nil
+	}
+
+	var errs PayloadErrors
+	checkStruct(value, "", &errs)
+	if len(errs) > 0 {
+		return errs
+	}
+	return nil
+}
+
+// checkStruct checks each field of a struct value, prefixing field names with path
+func checkStruct(value reflect.Value, path string, errs *PayloadErrors) {
+	structType := value.Type()
+	for i := 0; i < structType.NumField(); i++ {
+		field := structType.Field(i)
+		if !field.IsExported() {
+			continue
+		}
+		name := path + payloadFieldName(field)
+		fieldValue := value.Field(i)
+
+		if tag, ok := field.Tag.Lookup("validate"); ok {
+			for _, rule := range strings.Split(tag, ",") {
+				rule, param, _ := strings.Cut(strings.TrimSpace(rule), "=")
+				if rule != "" && !checkRule(fieldValue, rule, param) {
+					*errs = append(*errs, PayloadError{Field: name, Rule: rule, Param: param})
+				}
+			}
+		}
+
+		nested := fieldValue
+		if nested.Kind() == reflect.Ptr && !nested.IsNil() {
+			nested = nested.Elem()
+		}
+		if nested.Kind() == reflect.Struct {
+			prefix := name + "."
+			if field.Anonymous && field.Tag.Get("json") == "" {
+				prefix = path // Embedded fields are promoted, as in JSON
+			}
+			checkStruct(nested, prefix, errs)
+		}
+	}
+}
+
+// payloadFieldName returns the name a client uses for a field
+func payloadFieldName(field reflect.StructField) string {
+	if name, _, _ := strings.Cut(field.Tag.Get("json"), ","); name != "" && name != "-" {
+		return name
+	}
+	return field.Name
+}
+
+// checkRule reports whether a value satisfies one rule
+func checkRule(value reflect.Value, rule, param string) bool {
+	switch rule {
+	case "required":
+		return !value.IsZero()
+	case "min", "max", "len":
+		limit, err := strconv.ParseFloat(param, 64)
+		if err != nil {
+			return false
+		}
+		measure, ok := measureOf(value)
+		if !ok {
+			return false
+		}
+		switch rule {
+		case "min":
+			return measure >= limit
+		case "max":
+			return measure <= limit
+		}
+		return measure == limit
+	case "oneof":
+		actual := fmt.Sprint(value.Interface())
+		for _, allowed := range strings.Fields(param) {
+			if actual == allowed {
+				return true
+			}
+		}
+		return false
+	}
+	return false // Unknown rules always fail, so typos don't pass silently
+}
+
+// measureOf returns a number's value or a collection's length
+func measureOf(value reflect.Value) (float64, bool) {
+	switch value.Kind() {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return float64(value.Int()), true
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return float64(value.Uint()), true
+	case reflect.Float32, reflect.Float64:
+		return value.Float(), true
+	case reflect.String:
+		return float64(len([]rune(value.String()))), true
+	case reflect.Slice, reflect.Map, reflect.Array:
+		return float64(value.Len()), true
+	}
+	return 0, false
+}
+
+// payloadValidator rejects events whose fields break their validate tags
+type payloadValidator struct{}
+
+func (payloadValidator) Validate(engine types.Engine, event Event) bool {
+	return ValidatePayload(event) == nil
+}
+
+func (payloadValidator) Check(engine types.Engine, event Event) error {
+	return ValidatePayload(event)
+}
+
+func (payloadValidator) validatorName() string {
+	return "payload"
+}
@@ -0,0 +1,76 @@
+package atmos
+
+import (
+	"testing"
+
+	"github.com/cumulusrpg/atmos/types"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type Square struct {
+	Row int `json:"row" validate:"min=0,max=2"`
+	Col int `json:"col" validate:"min=0,max=2"`
+}
+
+type MarkPlacedEvent struct {
+	Player string   `json:"player" validate:"required,oneof=X O"`
+	Square Square   `json:"square"`
+	Note   string   `validate:"max=5"`
+	Tags   []string `json:"tags" validate:"len=1"`
+}
+
+func (e MarkPlacedEvent) Type() string { return "mark_placed" }
+
+// rejectEverything stands in for domain rules
+type rejectEverything struct{}
+
+func (rejectEverything) Validate(engine types.Engine, event Event) bool { return false }
+
+// TestValidatePayloadReportsEveryBrokenRule verifies each field's rules are checked, including nested structs
+func TestValidatePayloadReportsEveryBrokenRule(t *testing.T) {
+	assert.NoError(t, ValidatePayload(MarkPlacedEvent{Player: "X", Square: Square{Row: 2}, Note: "héllo", Tags: []string{"a"}}))
+
+	err := ValidatePayload(&MarkPlacedEvent{Player: "Z", Square: Square{Row: -1, Col: 3}, Note: "too long"})
+	var errs PayloadErrors
+	require.ErrorAs(t, err, &errs)
+	assert.Equal(t, PayloadErrors{
+		{Field: "player", Rule: "oneof", Param: "X O"},
+		{Field: "square.row", Rule: "min", Param: "0"},
+		{Field: "square.col", Rule: "max", Param: "2"},
+		{Field: "Note", Rule: "max", Param: "5"},
+		{Field: "tags", Rule: "len", Param: "1"},
+	}, errs)
+	assert.EqualError(t, err, "player must be one of X, O; square.row must be at least 0; square.col must be at most 2; "+
+		"Note must be at most 5; tags must have length 1")
+
+	assert.EqualError(t, ValidatePayload(MarkPlacedEvent{Square: Square{}, Tags: []string{"a"}}), "player is required; player must be one of X, O")
+	assert.NoError(t, ValidatePayload(TestEvent{}), "Untagged events always pass")
+}
+
+// TestValidatePayloadUnknownRule verifies a misspelled rule fails rather than passing silently
+func TestValidatePayloadUnknownRule(t *testing.T) {
+	type typoEvent struct {
+		MoveEvent
+		Square
+		Count int `validate:"mx=3"`
+	}
+	assert.EqualError(t, ValidatePayload(typoEvent{Square: Square{Row: 5}}),
+		`row must be at most 2; Count has unknown validation rule "mx"`)
+}
+
+// TestWithPayloadValidationRunsFirst verifies malformed payloads are rejected before domain validators see them
+func TestWithPayloadValidationRunsFirst(t *testing.T) {
+	engine := NewEngine(WithPayloadValidation())
+	engine.RequiresAll(Because(rejectEverything{}, "domain validator ran"))
+
+	result := engine.EmitWithResult(MarkPlacedEvent{Player: "X", Square: Square{Row: 3}, Tags: []string{"a"}})
+	assert.False(t, result.Accepted)
+	assert.Equal(t, "payload", result.RejectedBy)
+	assert.Equal(t, "square.row must be at most 2", result.Reason)
+
+	result = engine.EmitWithResult(MarkPlacedEvent{Player: "X", Tags: []string{"a"}})
+	assert.Equal(t, "domain validator ran", result.Reason)
+
+	assert.Equal(t, "payload", engine.Explain(MarkPlacedEvent{}).FirstFailure.Name)
+}
@@ -0,0 +1,208 @@
+// Package phase restricts which events are legal in each phase of a game
+// (setup, main, scoring), replacing ad hoc "game started" / "game over"
+// checks with a declared state machine:
+//
+//	machine := phase.New("setup",
+//		phase.Allow("setup", "player_joined", "game_started"),
+//		phase.Allow("main", "move_made", "game_ended"),
+//		phase.Allow("scoring", "points_awarded"),
+//		phase.Anywhere("chat"),
+//		phase.On("game_started", "main"),
+//		phase.On("game_ended", "scoring"),
+//	)
+//	machine.Install(engine)
+//
+// Installing registers the phase as state, a validator on every event that
+// rejects types not allowed in the current phase, and listeners that emit a
+// PhaseChangedEvent when a triggering event commits, so transitions are part
+// of the log. Event types the machine never mentions are not restricted.
+package phase
+
+import (
+	"fmt"
+	"maps"
+	"slices"
+
+	"github.com/cumulusrpg/atmos"
+	"github.com/cumulusrpg/atmos/types"
+)
+
+// ChangedEventType is the type of PhaseChangedEvent
+const ChangedEventType = "phase_changed"
+
+// PhaseChangedEvent records a transition of a machine between phases
+type PhaseChangedEvent struct {
+	Machine string `json:"machine"` // The machine's state name
+	From    string `json:"from"`
+	To      string `json:"to"`
+}
+
+// Type implements atmos.Event
+func (e PhaseChangedEvent) Type() string { return ChangedEventType }
+
+// Machine declares a game's phases and the events legal in each
+type Machine struct {
+	name     string
+	initial  string
+	phases   map[string]map[string]bool // phase -> allowed event types
+	anywhere map[string]bool            // event types allowed in every phase
+	triggers map[string]string          // event type -> phase it moves to
+	managed  map[string]bool            // every event type the machine mentions
+}
+
+// Option configures machine construction
+type Option func(*Machine)
+
+// Allow declares a phase and the event types legal in it (repeatable)
+func Allow(phase string, eventTypes ...string) Option {
+	return func(m *Machine) {
+		if m.phases[phase] == nil {
+			m.phases[phase] = make(map[string]bool)
+		}
+		for _, eventType := range eventTypes {
+			m.phases[phase][eventType] = true
+			m.managed[eventType] = true
+		}
+	}
+}
+
+// Anywhere declares event types that are legal in every phase
+func Anywhere(eventTypes ...string) Option {
+	return func(m *Machine) {
+		for _, eventType := range eventTypes {
+			m.anywhere[eventType] = true
+			m.managed[eventType] = true
+		}
+	}
+}
+
+// On moves the machine to a phase when an event of the given type commits
+func On(eventType, to string) Option {
+	return func(m *Machine) {
+		m.triggers[eventType] = to
+		m.managed[eventType] = true
+		if m.phases[to] == nil {
+			m.phases[to] = make(map[string]bool)
+		}
+	}
+}
+
+// WithStateName sets the name of the state holding the current phase
+// (default "phase"); use distinct names for several machines on one engine
+func WithStateName(name string) Option {
+	return func(m *Machine) {
+		m.name = name
+	}
+}
+
+// New creates a machine that starts in the initial phase
+func New(initial string, opts ...Option) *Machine {
+	m := &Machine{
+		name:     "phase",
+		initial:  initial,
+		phases:   map[string]map[string]bool{initial: {}},
+		anywhere: make(map[string]bool),
+		triggers: make(map[string]string),
+		managed:  make(map[string]bool),
+	}
+
+	// Apply options
+	for _, opt := range opts {
+		opt(m)
+	}
+
+	return m
+}
+
+// Install registers the machine's state, validators, and transition listeners
+func (m *Machine) Install(engine *atmos.Engine) {
+	engine.RegisterState(m.name, m.initial)
+	engine.RequiresAll(&phaseValidator{machine: m})
+	engine.When(ChangedEventType, func() atmos.Event { return &PhaseChangedEvent{} }).
+		Updates(m.name, m.reduce)
+
+	for _, eventType := range slices.Sorted(maps.Keys(m.triggers)) {
+		engine.When(eventType).
+			Then(&transitionListener{machine: m, to: m.triggers[eventType]}).
+			Emits(ChangedEventType)
+	}
+}
+
+// Current returns the engine's current phase
+func (m *Machine) Current(engine *atmos.Engine) string {
+	return engine.GetState(m.name).(string)
+}
+
+// Phases returns the declared phases, sorted
+func (m *Machine) Phases() []string {
+	return slices.Sorted(maps.Keys(m.phases))
+}
+
+// Allowed reports whether an event type is legal in a phase
+func (m *Machine) Allowed(phase, eventType string) bool {
+	return !m.managed[eventType] || m.anywhere[eventType] || m.phases[phase][eventType]
+}
+
+// Enter emits a PhaseChangedEvent moving the engine directly to a phase, for
+// transitions that aren't triggered by another event
+func (m *Machine) Enter(engine *atmos.Engine, to string) bool {
+	return engine.Emit(PhaseChangedEvent{Machine: m.name, From: m.Current(engine), To: to})
+}
+
+// reduce applies this machine's transitions to its phase
+func (m *Machine) reduce(engine *atmos.Engine, state interface{}, event atmos.Event) interface{} {
+	changed := atmos.Deref(event).(PhaseChangedEvent)
+	if changed.Machine != m.name {
+		return state
+	}
+	return changed.To
+}
+
+// phaseValidator rejects events that aren't legal in the current phase, and
+// transitions that don't start from it or go to an undeclared phase
+type phaseValidator struct {
+	machine *Machine
+}
+
+// Validate implements atmos.EventValidator
+func (v *phaseValidator) Validate(engine types.Engine, event atmos.Event) bool {
+	return v.Check(engine, event) == nil
+}
+
+// Check implements atmos.ReasonedValidator
+func (v *phaseValidator) Check(engine types.Engine, event atmos.Event) error {
+	m := v.machine
+	current := m.Current(engine.(*atmos.Engine))
+
+	if event.Type() == ChangedEventType {
+		changed := atmos.Deref(event).(PhaseChangedEvent)
+		switch {
+		case changed.Machine != m.name:
+			return nil
+		case changed.From != current:
+			return fmt.Errorf("%s is %q, not %q", m.name, current, changed.From)
+		case m.phases[changed.To] == nil:
+			return fmt.Errorf("unknown %s %q", m.name, changed.To)
+		}
+		return nil
+	}
+
+	if !m.Allowed(current, event.Type()) {
+		return fmt.Errorf("%s is not allowed during %s", event.Type(), current)
+	}
+	return nil
+}
+
+// transitionListener moves the machine on once a triggering event commits
+type transitionListener struct {
+	machine *Machine
+	to      string
+}
+
+// Handle implements atmos.EventListener
+func (l *transitionListener) Handle(engine types.Engine, event atmos.Event) {
+	e := engine.(*atmos.Engine)
+	if from := l.machine.Current(e); from != l.to {
+		e.Emit(PhaseChangedEvent{Machine: l.machine.name, From: from, To: l.to})
+	}
+}
@@ -0,0 +1,70 @@
+package phase_test
+
+import (
+	"testing"
+
+	"github.com/cumulusrpg/atmos"
+	"github.com/cumulusrpg/atmos/phase"
+	"github.com/stretchr/testify/assert"
+)
+
+type named string
+
+func (e named) Type() string { return string(e) }
+
+// newGame installs a three-phase machine on a fresh engine
+func newGame() (*atmos.Engine, *phase.Machine) {
+	machine := phase.New("setup",
+		phase.Allow("setup", "player_joined", "game_started"),
+		phase.Allow("main", "move_made", "game_ended"),
+		phase.Allow("scoring"),
+		phase.Anywhere("chat"),
+		phase.On("game_started", "main"),
+		phase.On("game_ended", "scoring"),
+	)
+	engine := atmos.NewEngine()
+	machine.Install(engine)
+	return engine, machine
+}
+
+// TestPhasesRestrictEvents verifies events are only legal in their phases and triggers advance the machine
+func TestPhasesRestrictEvents(t *testing.T) {
+	engine, machine := newGame()
+
+	assert.True(t, engine.Emit(named("player_joined")))
+	result := engine.EmitWithResult(named("move_made"))
+	assert.False(t, result.Accepted)
+	assert.Equal(t, "move_made is not allowed during setup", result.Reason)
+	assert.True(t, engine.Emit(named("chat")))
+	assert.True(t, engine.Emit(named("unrelated")), "Unmanaged event types are not restricted")
+
+	assert.True(t, engine.Emit(named("game_started")))
+	assert.Equal(t, "main", machine.Current(engine))
+	assert.Equal(t, phase.PhaseChangedEvent{Machine: "phase", From: "setup", To: "main"}, engine.GetEvents()[4],
+		"Transitions are recorded in the log right after their trigger")
+
+	assert.False(t, engine.Emit(named("player_joined")))
+	assert.True(t, engine.Emit(named("move_made")))
+	assert.True(t, engine.Emit(named("game_ended")))
+	assert.Equal(t, "scoring", machine.Current(engine))
+	assert.False(t, engine.Emit(named("move_made")))
+	assert.True(t, engine.Emit(named("chat")))
+	assert.Equal(t, []string{"main", "scoring", "setup"}, machine.Phases())
+}
+
+// TestEnterValidatesTransitions verifies direct transitions must start from the current phase
+func TestEnterValidatesTransitions(t *testing.T) {
+	engine, machine := newGame()
+
+	assert.False(t, machine.Enter(engine, "overtime"), "Undeclared phases are rejected")
+	assert.True(t, machine.Enter(engine, "scoring"))
+
+	stale := engine.EmitWithResult(phase.PhaseChangedEvent{Machine: "phase", From: "setup", To: "main"})
+	assert.Equal(t, `phase is "scoring", not "setup"`, stale.Reason)
+	assert.Equal(t, "scoring", machine.Current(engine))
+
+	// Replays reproduce the phase from the log alone
+	replayed, replayMachine := newGame()
+	replayed.SetEvents(engine.GetEvents())
+	assert.Equal(t, "scoring", replayMachine.Current(replayed))
+}
@@ -0,0 +1,50 @@
+package atmos
+
+import (
+	"fmt"
+
+	"github.com/cumulusrpg/atmos/types"
+)
+
+// RegisterPolicy registers a named validator that any event type can require
+// with Policy(name), so common checks (game started, not over, correct player)
+// are written once. Registering a name again replaces the policy.
+func (e *Engine) RegisterPolicy(name string, validator EventValidator) {
+	e.mutableRegistrations().policies[name] = validator
+}
+
+// Policy refers to a validator registered with RegisterPolicy. The policy is
+// looked up when the event is validated, so it may be registered after the
+// events that require it; events requiring an unknown policy are rejected.
+// Usage: When("move_made").Requires(Policy("game_in_progress"), Valid(&ValidMove{}))
+func Policy(name string) EventValidator {
+	return policyValidator{name: name}
+}
+
+// policyValidator delegates to a named policy on the validating engine
+type policyValidator struct {
+	name string
+}
+
+func (p policyValidator) Validate(engine types.Engine, event Event) bool {
+	return p.Check(engine, event) == nil
+}
+
+func (p policyValidator) Check(engine types.Engine, event Event) error {
+	e := engine.(*Engine)
+	validator, exists := e.policies[p.name]
+	if !exists {
+		return fmt.Errorf("unknown policy %q", p.name)
+	}
+	if ok, err := e.check(validator, event); !ok {
+		if err == nil {
+			err = fmt.Errorf("policy %q not satisfied", p.name)
+		}
+		return err
+	}
+	return nil
+}
+
+func (p policyValidator) validatorName() string {
+	return "policy " + p.name
+}
@@ -0,0 +1,63 @@
+package atmos
+
+import (
+	"testing"
+
+	"github.com/cumulusrpg/atmos/types"
+	"github.com/stretchr/testify/assert"
+)
+
+type PassEvent struct {
+	Player string
+}
+
+func (e PassEvent) Type() string { return "pass" }
+
+type GameOverEvent struct{}
+
+func (e GameOverEvent) Type() string { return "game_over" }
+
+// gameInProgress rejects any event once the game is over
+type gameInProgress struct{}
+
+func (v gameInProgress) Validate(engine types.Engine, event Event) bool {
+	return !engine.(*Engine).GetState("over").(bool)
+}
+
+// TestPolicySharedAcrossEventTypes verifies one registered policy guards several event types
+func TestPolicySharedAcrossEventTypes(t *testing.T) {
+	engine := NewEngine()
+	engine.RegisterState("over", false)
+	engine.When("move").Requires(Policy("game_in_progress"))
+	engine.When("pass").Requires(Policy("game_in_progress"))
+	engine.When("game_over").Updates("over", func(e *Engine, state interface{}, event Event) interface{} {
+		return true
+	})
+
+	// Policies are resolved at validation time, so they may be registered late
+	engine.RegisterPolicy("game_in_progress", Because(gameInProgress{}, "the game is over"))
+
+	assert.True(t, engine.Emit(MoveEvent{Player: "alice"}))
+	assert.True(t, engine.Emit(PassEvent{Player: "bob"}))
+	assert.True(t, engine.Emit(GameOverEvent{}))
+
+	result := engine.EmitWithResult(MoveEvent{Player: "alice"})
+	assert.False(t, result.Accepted)
+	assert.Equal(t, "policy game_in_progress", result.RejectedBy)
+	assert.Equal(t, "the game is over", result.Reason)
+	assert.False(t, engine.Emit(PassEvent{Player: "bob"}))
+}
+
+// TestPolicyUnknownRejects verifies requiring an unregistered policy fails closed
+func TestPolicyUnknownRejects(t *testing.T) {
+	engine := NewEngine()
+	engine.When("move").Requires(Policy("missing"))
+
+	result := engine.EmitWithResult(MoveEvent{Player: "alice"})
+	assert.False(t, result.Accepted)
+	assert.Equal(t, `unknown policy "missing"`, result.Reason)
+
+	engine.RegisterPolicy("missing", gameInProgress{})
+	engine.RegisterState("over", false)
+	assert.True(t, engine.Emit(MoveEvent{Player: "alice"}))
+}
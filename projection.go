@@ -0,0 +1,112 @@
+package atmos
+
+import (
+	"sort"
+	"sync"
+
+	"github.com/cumulusrpg/atmos/types"
+)
+
+// GetStates projects several states in a single traversal of the event log,
+// instead of one full replay per GetState call. Unknown names are omitted
+// from the result.
+func (e *Engine) GetStates(names ...string) map[string]interface{} {
+	registries, states := e.startProjections(names)
+
+	// Only the event types some projection reduces are needed
+	handled := make(map[string]StateReducer)
+	for _, registry := range registries {
+		for eventType, reducer := range registry.Reducers {
+			handled[eventType] = reducer
+		}
+	}
+
+	e.eachEventFor(handled, func(event Event) bool {
+		for name, registry := range registries {
+			if reducer, hasReducer := registry.Reducers[event.Type()]; hasReducer {
+				states[name] = reducer(e, states[name], event)
+			}
+		}
+		return true
+	})
+
+	return states
+}
+
+// ProjectAll projects every registered state in a single traversal of the event log
+func (e *Engine) ProjectAll() map[string]interface{} {
+	return e.GetStates(e.StateNames()...)
+}
+
+// StateNames returns the names of all registered states, sorted
+func (e *Engine) StateNames() []string {
+	names := make([]string, 0, len(e.states))
+	for name := range e.states {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// GetStatesConcurrently projects several states with one goroutine per state
+// over a single read of the event log. Reducers must be safe to run
+// concurrently (pure functions of state and event, or read-only engine access).
+func (e *Engine) GetStatesConcurrently(names ...string) map[string]interface{} {
+	registries, initial := e.startProjections(names)
+	events := e.repository.GetAll(e)
+
+	var mu sync.Mutex
+	var wg sync.WaitGroup
+	states := make(map[string]interface{}, len(registries))
+	for name, registry := range registries {
+		wg.Add(1)
+		go func(name string, registry StateRegistry) {
+			defer wg.Done()
+			state := initial[name]
+			for _, event := range events {
+				if reducer, hasReducer := registry.Reducers[event.Type()]; hasReducer {
+					state = reducer(e, state, event)
+				}
+			}
+			mu.Lock()
+			states[name] = state
+			mu.Unlock()
+		}(name, registry)
+	}
+	wg.Wait()
+
+	return states
+}
+
+// startProjections looks up registries and starting states (initial state
+// merged with any snapshot) for the named states
+func (e *Engine) startProjections(names []string) (map[string]StateRegistry, map[string]interface{}) {
+	registries := make(map[string]StateRegistry, len(names))
+	states := make(map[string]interface{}, len(names))
+	for _, name := range names {
+		registry, exists := e.states[name]
+		if !exists {
+			continue
+		}
+		registries[name] = registry
+		states[name] = e.startingState(name, registry)
+	}
+	return registries, states
+}
+
+// EventsForAggregate returns the AggregateEvents with the given ID in commit
+// order, from the repository's index when it is an IndexedRepository
+func (e *Engine) EventsForAggregate(aggregateID string) []Event {
+	if indexed, ok := e.repository.(types.IndexedRepository); ok {
+		return indexed.EventsForAggregate(e, aggregateID)
+	}
+
+	var events []Event
+	e.eachEvent(func(event Event) bool {
+		if aggregate, ok := event.(types.AggregateEvent); ok && aggregate.AggregateID() == aggregateID {
+			events = append(events, event)
+		}
+		return true
+	})
+	return events
+}
@@ -0,0 +1,66 @@
+package atmos
+
+import (
+	"testing"
+
+	"github.com/cumulusrpg/atmos/types"
+	"github.com/stretchr/testify/assert"
+)
+
+// countingRepository counts full log reads
+type countingRepository struct {
+	CustomRepository
+	reads int
+}
+
+func (r *countingRepository) GetAll(engine types.Engine) []Event {
+	r.reads++
+	return r.CustomRepository.GetAll(engine)
+}
+
+func newProjectionEngine(repo EventRepository) *Engine {
+	engine := NewEngine(WithRepository(repo))
+	engine.RegisterState("count", 0)
+	engine.RegisterState("names", "")
+	engine.When("test_event").
+		Updates("count", func(e *Engine, state interface{}, event Event) interface{} {
+			return state.(int) + 1
+		}).
+		Updates("names", func(e *Engine, state interface{}, event Event) interface{} {
+			return state.(string) + event.(TestEvent).Name
+		})
+	for _, name := range []string{"a", "b", "c"} {
+		engine.Emit(TestEvent{Name: name})
+	}
+	return engine
+}
+
+// TestGetStatesSinglePass verifies several states are projected from one log read
+func TestGetStatesSinglePass(t *testing.T) {
+	repo := &countingRepository{}
+	engine := newProjectionEngine(repo)
+
+	states := engine.GetStates("count", "names", "missing")
+	assert.Equal(t, map[string]interface{}{"count": 3, "names": "abc"}, states)
+	assert.Equal(t, 1, repo.reads)
+	assert.Equal(t, engine.GetState("names"), states["names"])
+}
+
+// TestGetStatesConcurrently verifies the concurrent projection matches the serial one
+func TestGetStatesConcurrently(t *testing.T) {
+	repo := &countingRepository{}
+	engine := newProjectionEngine(repo)
+
+	assert.Equal(t, engine.GetStates("count", "names"), engine.GetStatesConcurrently("count", "names"))
+	assert.Equal(t, 2, repo.reads)
+}
+
+// TestProjectAll verifies every registered state is projected from one log read
+func TestProjectAll(t *testing.T) {
+	repo := &countingRepository{}
+	engine := newProjectionEngine(repo)
+
+	assert.Equal(t, []string{"count", "names"}, engine.StateNames())
+	assert.Equal(t, map[string]interface{}{"count": 3, "names": "abc"}, engine.ProjectAll())
+	assert.Equal(t, 1, repo.reads)
+}
@@ -0,0 +1,115 @@
+package atmos
+
+import (
+	"encoding/json"
+	"fmt"
+	"reflect"
+)
+
+// QuarantinedRecord is a logged event this engine couldn't decode, kept as
+// stored so it survives a round trip through the engine
+type QuarantinedRecord struct {
+	Position int             // number of decoded events that preceded it in the log
+	Type     string          // its event type, if the record was readable
+	Raw      json.RawMessage // the full record, exactly as stored
+	Err      error           // why it couldn't be decoded
+}
+
+// WithQuarantine keeps events that UnmarshalEvents (or RecoverEvents,
+// UnmarshalEventsBinary) can't decode — unknown types, payloads that don't
+// fit — instead of dropping them. When the decoded events are loaded with
+// SetEvents, the records are held with the engine's log, and MarshalLog
+// writes them back in place. A log round-tripped through an engine that
+// lacks some factories (an older client, a tool) is then lossless.
+//
+// MarshalEvents never writes quarantined records, since the events it is
+// given need not be the log they came from.
+func WithQuarantine() EngineOption {
+	return func(e *Engine) {
+		e.quarantining = true
+	}
+}
+
+// Quarantined returns the records held with the engine's log, in log order
+func (e *Engine) Quarantined() []QuarantinedRecord {
+	return append([]QuarantinedRecord(nil), e.quarantined...)
+}
+
+// ClearQuarantine discards quarantined records, so MarshalLog no longer writes them
+func (e *Engine) ClearQuarantine() {
+	e.quarantined = nil
+}
+
+// MarshalLog serializes the engine's log like MarshalEvents, with the
+// records quarantined when it was loaded written back in place
+func (e *Engine) MarshalLog() ([]byte, error) {
+	if len(e.quarantined) == 0 {
+		return e.MarshalEvents(e.GetEvents())
+	}
+	return e.marshalWithQuarantine(e.GetEvents())
+}
+
+// decodedLog pairs the events a decode returned with the records it quarantined
+type decodedLog struct {
+	events  []Event
+	records []QuarantinedRecord
+}
+
+// decoded remembers a decode's quarantined records until its events are loaded
+func (e *Engine) decoded(events []Event, records []QuarantinedRecord) {
+	if e.quarantining {
+		e.lastDecoded = decodedLog{events: events, records: records}
+	}
+}
+
+// adoptQuarantine holds the last decode's records with the log when the
+// log being loaded is the events that decode returned, and otherwise
+// clears them
+func (e *Engine) adoptQuarantine(events []Event) {
+	last := e.lastDecoded
+	e.lastDecoded, e.quarantined = decodedLog{}, nil
+	if len(last.records) > 0 && len(last.events) == len(events) &&
+		reflect.ValueOf(last.events).Pointer() == reflect.ValueOf(events).Pointer() {
+		e.quarantined = last.records
+	}
+}
+
+// quarantine adds an undecodable record to a decode's records, if
+// quarantining is enabled
+func (e *Engine) quarantine(records []QuarantinedRecord, position int, eventType string, raw []byte, err error) []QuarantinedRecord {
+	if !e.quarantining {
+		return records
+	}
+	return append(records, QuarantinedRecord{
+		Position: position,
+		Type:     eventType,
+		Raw:      append(json.RawMessage(nil), raw...),
+		Err:      err,
+	})
+}
+
+// marshalWithQuarantine serializes events with quarantined records
+// reinserted at their positions
+func (e *Engine) marshalWithQuarantine(events []Event) ([]byte, error) {
+	records := make([]interface{}, 0, len(events)+len(e.quarantined))
+	next := 0
+	for i := 0; i <= len(events); i++ {
+		for next < len(e.quarantined) && (e.quarantined[next].Position <= i || i == len(events)) {
+			records = append(records, e.quarantined[next].Raw)
+			next++
+		}
+		if i < len(events) {
+			data, err := e.eventData(events[i])
+			if err != nil {
+				return nil, err
+			}
+			records = append(records, EventWrapper{Type: events[i].Type(), Data: data})
+		}
+	}
+	return json.Marshal(records)
+}
+
+// errUndecodable describes a record whose payload doesn't fit its event type
+func errUndecodable(eventType string, err error) error {
+	return fmt.Errorf("decoding %s: %w", eventType, err)
+}
@@ -0,0 +1,132 @@
+package atmos
+
+import (
+	"encoding/json"
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// mixedLog has events this engine knows around ones it doesn't
+const mixedLog = `[{"type":"emote","data":{"face":":)"}},` +
+	`{"type":"round_played","data":{"winner":"alice"}},` +
+	`{"type":"round_played","data":{"winner":42}},` +
+	`{"type":"round_played","data":{"winner":"bob"}},` +
+	`{"type":"emote","data":{"face":":("}}]`
+
+// TestQuarantineRoundTripsUnknownEvents verifies undecodable records are written back in place
+func TestQuarantineRoundTripsUnknownEvents(t *testing.T) {
+	engine := NewEngine(WithQuarantine())
+	engine.RegisterEventTypes(RoundPlayedEvent{})
+
+	events, err := engine.UnmarshalEvents([]byte(mixedLog))
+	require.NoError(t, err)
+	assert.Len(t, events, 2)
+	assert.Empty(t, engine.Quarantined(), "Held for the log once it is loaded")
+	engine.SetEvents(events)
+
+	quarantined := engine.Quarantined()
+	require.Len(t, quarantined, 3)
+	assert.Equal(t, 0, quarantined[0].Position)
+	assert.Equal(t, "emote", quarantined[0].Type)
+	assert.True(t, errors.Is(quarantined[0].Err, ErrUnknownEventType))
+	assert.Equal(t, 1, quarantined[1].Position)
+	assert.Contains(t, quarantined[1].Err.Error(), "decoding round_played")
+	assert.Equal(t, 2, quarantined[2].Position)
+
+	data, err := engine.MarshalLog()
+	require.NoError(t, err)
+	assert.JSONEq(t, mixedLog, string(data))
+	assert.Contains(t, string(data), `{"type":"round_played","data":{"winner":42}}`, "Records keep their bytes")
+
+	data, err = engine.MarshalEvents(events)
+	require.NoError(t, err)
+	assert.NotContains(t, string(data), "emote", "MarshalEvents writes only the events it is given")
+
+	engine.ClearQuarantine()
+	data, err = engine.MarshalLog()
+	require.NoError(t, err)
+	assert.NotContains(t, string(data), "emote")
+}
+
+// TestQuarantineKeepsNewEventsAfterOldRecords verifies events appended after loading follow quarantined records
+func TestQuarantineKeepsNewEventsAfterOldRecords(t *testing.T) {
+	engine := NewEngine(WithQuarantine())
+	engine.RegisterEventTypes(RoundPlayedEvent{})
+
+	events, err := engine.UnmarshalEvents([]byte(mixedLog))
+	require.NoError(t, err)
+	engine.SetEvents(events)
+	engine.Emit(RoundPlayedEvent{Winner: "carol"})
+
+	data, err := engine.MarshalLog()
+	require.NoError(t, err)
+	assert.Equal(t, []string{"emote", "round_played", "round_played", "round_played", "emote", "round_played"},
+		decodeTypes(t, data))
+}
+
+// TestQuarantineFromRecovery verifies RecoverEvents quarantines records it skips
+func TestQuarantineFromRecovery(t *testing.T) {
+	engine := NewEngine(WithQuarantine())
+	engine.RegisterEventTypes(RoundPlayedEvent{})
+
+	events, report := engine.RecoverEvents([]byte(mixedLog), QuarantineBadRecords())
+	assert.Len(t, events, 2)
+	assert.Len(t, report.Failures, 3)
+	engine.SetEvents(events)
+	assert.Len(t, engine.Quarantined(), 3)
+
+	data, err := engine.MarshalLog()
+	require.NoError(t, err)
+	assert.JSONEq(t, mixedLog, string(data))
+}
+
+// TestWithoutQuarantineDropsRecords verifies the default behavior is unchanged
+func TestWithoutQuarantineDropsRecords(t *testing.T) {
+	engine := NewEngine()
+	engine.RegisterEventTypes(RoundPlayedEvent{})
+
+	events, err := engine.UnmarshalEvents([]byte(mixedLog))
+	require.NoError(t, err)
+	assert.Len(t, events, 2)
+	assert.Empty(t, engine.Quarantined())
+}
+
+// TestQuarantineBelongsToTheLoadedLog verifies other decodes and loads don't mix records into the wrong log
+func TestQuarantineBelongsToTheLoadedLog(t *testing.T) {
+	engine := NewEngine(WithQuarantine())
+	engine.RegisterEventTypes(RoundPlayedEvent{})
+	events, err := engine.UnmarshalEvents([]byte(mixedLog))
+	require.NoError(t, err)
+	engine.SetEvents(events)
+
+	// Decoding a delta doesn't disturb the loaded log's records
+	delta, err := engine.UnmarshalEvents([]byte(`[{"type":"emote","data":{}},{"type":"round_played","data":{"winner":"dan"}}]`))
+	require.NoError(t, err)
+	assert.Len(t, delta, 1)
+	assert.Len(t, engine.Quarantined(), 3)
+	data, err := engine.MarshalEvents(delta)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"round_played"}, decodeTypes(t, data))
+
+	// Loading a log that didn't come from the decode drops them
+	engine.SetEvents([]Event{RoundPlayedEvent{Winner: "erin"}})
+	assert.Empty(t, engine.Quarantined())
+	engine.SetEvents(delta)
+	assert.Empty(t, engine.Quarantined(), "A decode's records are given up once another log is loaded")
+}
+
+// decodeTypes returns the type of each record in a marshaled log
+func decodeTypes(t *testing.T, data []byte) []string {
+	var records []struct {
+		Type string `json:"type"`
+	}
+	require.NoError(t, json.Unmarshal(data, &records))
+	types := make([]string, len(records))
+	for i, record := range records {
+		types[i] = record.Type
+	}
+	return types
+}
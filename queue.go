@@ -0,0 +1,215 @@
+package atmos
+
+import (
+	"errors"
+	"fmt"
+	"sync"
+
+	"github.com/cumulusrpg/atmos/types"
+)
+
+var (
+	// ErrQueueFull is the rejection reason of emits refused by a RejectWhenFull queue
+	ErrQueueFull = errors.New("queue is full")
+
+	// ErrInvalidCapacity is returned by NewQueue for queues that couldn't hold an event
+	ErrInvalidCapacity = errors.New("queue capacity must be positive")
+)
+
+// OverflowPolicy decides what happens when an event arrives at a full queue
+type OverflowPolicy int
+
+const (
+	BlockWhenFull  OverflowPolicy = iota // The emit waits for the consumer to make room
+	DropOldest                           // The oldest queued event is discarded
+	RejectWhenFull                       // The emit is rejected before it commits
+)
+
+// QueueStats are a queue's metrics
+type QueueStats struct {
+	Name      string
+	Capacity  int
+	Depth     int // events waiting now
+	MaxDepth  int // most events ever waiting at once
+	Enqueued  int // events accepted into the queue
+	Processed int // events handed to the listeners
+	Dropped   int // events discarded by DropOldest (or arriving while full after validation)
+	Rejected  int // emits refused by RejectWhenFull
+	Blocked   int // emits that had to wait by BlockWhenFull
+}
+
+// Queue is a bounded buffer feeding slow listeners on a background
+// goroutine, in commit order, so a slow consumer can't balloon memory:
+//
+//	mail, err := atmos.NewQueue("mail", 100, atmos.DropOldest, sendMail)
+//	if err != nil { ... }
+//	engine.When("player_invited").ThenQueued(mail)
+//
+// Like ThenAsync listeners, queued listeners must not use the engine they
+// are given, are skipped in replay mode, and are waited for by Shutdown.
+type Queue struct {
+	name      string
+	capacity  int
+	policy    OverflowPolicy
+	listeners []EventListener
+
+	mu      sync.Mutex
+	room    *sync.Cond // signaled as the consumer takes events
+	pending []queuedEvent
+	running bool // a consumer goroutine is draining the queue
+	stats   QueueStats
+}
+
+// queuedEvent is an event waiting for the listeners
+type queuedEvent struct {
+	engine *Engine
+	event  Event
+}
+
+// NewQueue creates a queue holding up to capacity events for listeners, or
+// returns ErrInvalidCapacity if capacity isn't positive
+func NewQueue(name string, capacity int, policy OverflowPolicy, listeners ...EventListener) (*Queue, error) {
+	if capacity <= 0 {
+		return nil, fmt.Errorf("%w: %s holds %d", ErrInvalidCapacity, name, capacity)
+	}
+	q := &Queue{
+		name:      name,
+		capacity:  capacity,
+		policy:    policy,
+		listeners: listeners,
+		stats:     QueueStats{Name: name, Capacity: capacity},
+	}
+	q.room = sync.NewCond(&q.mu)
+	return q, nil
+}
+
+// ThenQueued feeds committed events to a queue (chainable). With
+// RejectWhenFull the queue also validates the event, so emits fail with
+// ErrQueueFull while it is full.
+func (r *EventRegistration) ThenQueued(queue *Queue) *EventRegistration {
+	if queue.policy == RejectWhenFull {
+		r.WithValidator(&queueValidator{queue: queue})
+	}
+	return r.WithListener(&queueListener{queue: queue})
+}
+
+// Stats returns the queue's metrics
+func (q *Queue) Stats() QueueStats {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	stats := q.stats
+	stats.Depth = len(q.pending)
+	return stats
+}
+
+// full reports whether the queue has no room (with q.mu held)
+func (q *Queue) full() bool {
+	return len(q.pending) >= q.capacity
+}
+
+// push adds an event, applying the overflow policy, and starts a consumer if none is running
+func (q *Queue) push(e *Engine, event Event) {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+
+	if q.full() {
+		switch q.policy {
+		case BlockWhenFull:
+			q.stats.Blocked++
+			for q.full() {
+				q.room.Wait()
+			}
+		case DropOldest:
+			q.pending = q.pending[1:]
+			q.stats.Dropped++
+		default:
+			q.stats.Dropped++ // Filled up by derived events since validation
+			return
+		}
+	}
+
+	q.pending = append(q.pending, queuedEvent{engine: e, event: event})
+	q.stats.Enqueued++
+	if len(q.pending) > q.stats.MaxDepth {
+		q.stats.MaxDepth = len(q.pending)
+	}
+	if !q.running {
+		q.running = true
+		e.Go("queue "+q.name, q.drain)
+	}
+}
+
+// drain hands queued events to the listeners until the queue is empty
+func (q *Queue) drain() {
+	for {
+		q.mu.Lock()
+		if len(q.pending) == 0 {
+			q.running = false
+			q.mu.Unlock()
+			return
+		}
+		next := q.pending[0]
+		q.pending = q.pending[1:]
+		q.room.Broadcast()
+		q.mu.Unlock()
+
+		for _, listener := range q.listeners {
+			listener.Handle(next.engine, next.event)
+		}
+
+		q.mu.Lock()
+		q.stats.Processed++
+		q.mu.Unlock()
+	}
+}
+
+// queueListener enqueues committed events
+type queueListener struct {
+	queue *Queue
+}
+
+// Handle implements EventListener
+func (l *queueListener) Handle(engine types.Engine, event Event) {
+	e := engine.(*Engine)
+	if e.Replaying() {
+		return
+	}
+	l.queue.push(e, event)
+}
+
+// queueValidator rejects emits while a RejectWhenFull queue is full
+type queueValidator struct {
+	queue *Queue
+}
+
+// Validate implements EventValidator
+func (v *queueValidator) Validate(engine types.Engine, event Event) bool {
+	return v.Check(engine, event) == nil
+}
+
+// Check implements ReasonedValidator
+func (v *queueValidator) Check(engine types.Engine, event Event) error {
+	if IsReplaying(engine) {
+		return nil
+	}
+	v.queue.mu.Lock()
+	defer v.queue.mu.Unlock()
+	if v.queue.full() {
+		return fmt.Errorf("%w: %s", ErrQueueFull, v.queue.name)
+	}
+	return nil
+}
+
+// countRejection counts an emit refused by a queue. Emits call it once they
+// are rejected, since Explain and batch prechecks run validators too.
+func countRejection(validator EventValidator) {
+	if v, ok := validator.(*queueValidator); ok {
+		v.queue.mu.Lock()
+		v.queue.stats.Rejected++
+		v.queue.mu.Unlock()
+	}
+}
+
+func (v *queueValidator) validatorName() string {
+	return "queue:" + v.queue.name
+}
@@ -0,0 +1,133 @@
+package atmos
+
+import (
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/cumulusrpg/atmos/types"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// gatedListener records events, holding each until released
+type gatedListener struct {
+	started chan string
+	release chan struct{}
+
+	mu      sync.Mutex
+	handled []string
+}
+
+func newGatedListener() *gatedListener {
+	return &gatedListener{started: make(chan string, 10), release: make(chan struct{})}
+}
+
+func (l *gatedListener) Handle(engine types.Engine, event Event) {
+	name := event.(TestEvent).Name
+	l.started <- name
+	<-l.release
+
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	l.handled = append(l.handled, name)
+}
+
+// TestQueueDropOldest verifies a full queue discards its oldest events
+func TestQueueDropOldest(t *testing.T) {
+	engine := NewEngine()
+	slow := newGatedListener()
+	queue, err := NewQueue("mail", 2, DropOldest, slow)
+	require.NoError(t, err)
+	engine.When("test_event").ThenQueued(queue)
+
+	require.True(t, engine.Emit(TestEvent{Name: "a"}))
+	assert.Equal(t, "a", <-slow.started, "The consumer is busy with a")
+	for _, name := range []string{"b", "c", "d"} {
+		assert.True(t, engine.Emit(TestEvent{Name: name}))
+	}
+	assert.Equal(t, 2, queue.Stats().Depth)
+
+	close(slow.release)
+	require.NoError(t, engine.Close())
+	assert.Equal(t, []string{"a", "c", "d"}, slow.handled)
+	assert.Equal(t, QueueStats{Name: "mail", Capacity: 2, MaxDepth: 2, Enqueued: 4, Processed: 3, Dropped: 1}, queue.Stats())
+}
+
+// TestQueueRejectWhenFull verifies emits fail while the queue is full
+func TestQueueRejectWhenFull(t *testing.T) {
+	engine := NewEngine()
+	slow := newGatedListener()
+	queue, err := NewQueue("mail", 1, RejectWhenFull, slow)
+	require.NoError(t, err)
+	engine.When("test_event").ThenQueued(queue)
+
+	require.True(t, engine.Emit(TestEvent{Name: "a"}))
+	<-slow.started
+	require.True(t, engine.Emit(TestEvent{Name: "b"}))
+
+	result := engine.EmitWithResult(TestEvent{Name: "c"})
+	assert.False(t, result.Accepted)
+	assert.Equal(t, "queue:mail", result.RejectedBy)
+	assert.Equal(t, "queue is full: mail", result.Reason)
+	assert.Len(t, engine.GetEvents(), 2, "The rejected event never committed")
+	assert.False(t, engine.Explain(TestEvent{Name: "c"}).Accepted)
+	assert.Equal(t, 1, queue.Stats().Rejected, "Explaining isn't emitting")
+	assert.Equal(t, "queue:mail", engine.EmitAll(TestEvent{Name: "c"}, TestEvent{Name: "d"}).RejectedBy)
+
+	close(slow.release)
+	require.NoError(t, engine.Close())
+	assert.Equal(t, []string{"a", "b"}, slow.handled)
+	assert.Equal(t, 2, queue.Stats().Rejected, "One emit and one batch")
+}
+
+// TestQueueBlockWhenFull verifies emits wait for room
+func TestQueueBlockWhenFull(t *testing.T) {
+	engine := NewEngine()
+	slow := newGatedListener()
+	queue, err := NewQueue("mail", 1, BlockWhenFull, slow)
+	require.NoError(t, err)
+	engine.When("test_event").ThenQueued(queue)
+
+	require.True(t, engine.Emit(TestEvent{Name: "a"}))
+	<-slow.started
+	require.True(t, engine.Emit(TestEvent{Name: "b"}))
+
+	done := make(chan bool)
+	go func() { done <- engine.Emit(TestEvent{Name: "c"}) }()
+	select {
+	case <-done:
+		t.Fatal("Emit should wait while the queue is full")
+	case <-time.After(20 * time.Millisecond):
+	}
+
+	close(slow.release)
+	assert.True(t, <-done)
+	require.NoError(t, engine.Close())
+	assert.Equal(t, []string{"a", "b", "c"}, slow.handled)
+	assert.Equal(t, 1, queue.Stats().Blocked)
+}
+
+// TestQueueSkipsReplays verifies replayed events aren't queued or rejected
+func TestQueueSkipsReplays(t *testing.T) {
+	engine := NewEngine()
+	slow := newGatedListener()
+	close(slow.release)
+	queue, err := NewQueue("mail", 1, RejectWhenFull, slow)
+	require.NoError(t, err)
+	engine.When("test_event").ThenQueued(queue)
+
+	require.NoError(t, engine.Replay([]Event{TestEvent{Name: "a"}, TestEvent{Name: "b"}}), "More than the queue holds")
+	require.NoError(t, engine.Close())
+	assert.Empty(t, slow.handled)
+	assert.Zero(t, queue.Stats().Enqueued)
+}
+
+// TestQueueCapacityMustBePositive verifies queues that couldn't hold an event are refused
+func TestQueueCapacityMustBePositive(t *testing.T) {
+	_, err := NewQueue("mail", 0, DropOldest)
+	assert.ErrorIs(t, err, ErrInvalidCapacity)
+	assert.EqualError(t, err, "queue capacity must be positive: mail holds 0")
+	_, err = NewQueue("mail", -1, BlockWhenFull)
+	assert.ErrorIs(t, err, ErrInvalidCapacity)
+}
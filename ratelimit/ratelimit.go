@@ -0,0 +1,237 @@
+// Package ratelimit protects engines from spammy clients with token-bucket
+// limits per event type, per actor, or per actor and event type.
+//
+// Limits are enforced by a validator (so over-limit emits are rejected and
+// show up in Explain) and tokens are only spent by a listener once an event
+// commits, so Explain and events rejected by other validators cost nothing:
+//
+//	limiter := ratelimit.New(
+//		ratelimit.PerActor(ratelimit.Rule{Rate: 5, Burst: 10}),
+//		ratelimit.PerEventType("chat", ratelimit.Rule{Rate: 1, Burst: 3}),
+//	)
+//	limiter.Install(engine, "chat", "move_made")
+//
+// Callers that prefer to queue rather than be rejected can Wait before emitting.
+package ratelimit
+
+import (
+	"context"
+	"math"
+	"sync"
+	"time"
+
+	"github.com/cumulusrpg/atmos"
+	"github.com/cumulusrpg/atmos/types"
+)
+
+// Rule is a token bucket: Burst tokens at most, refilled at Rate tokens per second
+type Rule struct {
+	Rate  float64
+	Burst int
+}
+
+// bucket is the live state of one token bucket
+type bucket struct {
+	tokens float64
+	last   time.Time
+}
+
+// scope identifies which events a rule applies to
+type scope int
+
+const (
+	scopeType scope = iota
+	scopeActor
+	scopeActorType
+)
+
+// limit is a configured rule
+type limit struct {
+	scope     scope
+	eventType string // "" matches every event type
+	rule      Rule
+}
+
+// bucketKey identifies a bucket
+type bucketKey struct {
+	limit     int
+	actor     string
+	eventType string
+}
+
+// Limiter tracks token buckets. It is safe for concurrent use.
+type Limiter struct {
+	mu      sync.Mutex
+	limits  []limit
+	buckets map[bucketKey]*bucket
+	now     func() time.Time
+}
+
+// Option configures limiter construction
+type Option func(*Limiter)
+
+// PerEventType limits an event type across all actors
+func PerEventType(eventType string, rule Rule) Option {
+	return func(l *Limiter) {
+		l.limits = append(l.limits, limit{scope: scopeType, eventType: eventType, rule: rule})
+	}
+}
+
+// PerActor limits each actor across all installed event types
+func PerActor(rule Rule) Option {
+	return func(l *Limiter) {
+		l.limits = append(l.limits, limit{scope: scopeActor, rule: rule})
+	}
+}
+
+// PerActorAndType limits each actor for one event type
+func PerActorAndType(eventType string, rule Rule) Option {
+	return func(l *Limiter) {
+		l.limits = append(l.limits, limit{scope: scopeActorType, eventType: eventType, rule: rule})
+	}
+}
+
+// WithClock sets the time source (for tests)
+func WithClock(now func() time.Time) Option {
+	return func(l *Limiter) {
+		l.now = now
+	}
+}
+
+// New creates a limiter from rules
+func New(opts ...Option) *Limiter {
+	l := &Limiter{
+		buckets: make(map[bucketKey]*bucket),
+		now:     time.Now,
+	}
+
+	// Apply options
+	for _, opt := range opts {
+		opt(l)
+	}
+
+	return l
+}
+
+// Install registers the limiter's validator and token-spending listener on
+// the given event types
+func (l *Limiter) Install(engine *atmos.Engine, eventTypes ...string) {
+	for _, eventType := range eventTypes {
+		engine.When(eventType).
+			Requires(&limitValidator{limiter: l}).
+			Then(&spendListener{limiter: l})
+	}
+}
+
+// Allowed reports whether an actor may emit an event type now, without spending tokens
+func (l *Limiter) Allowed(actor, eventType string) bool {
+	return l.Delay(actor, eventType) == 0
+}
+
+// Delay returns how long until an actor may emit an event type (0 if allowed now)
+func (l *Limiter) Delay(actor, eventType string) time.Duration {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	now := l.now()
+	var wait time.Duration
+	for _, key := range l.keys(actor, eventType) {
+		b := l.refill(key, now)
+		if b.tokens >= 1 {
+			continue
+		}
+		rule := l.limits[key.limit].rule
+		if rule.Rate <= 0 {
+			return time.Duration(math.MaxInt64)
+		}
+		d := time.Duration((1 - b.tokens) / rule.Rate * float64(time.Second))
+		if d > wait {
+			wait = d
+		}
+	}
+	return wait
+}
+
+// Spend takes one token from every bucket applying to an actor and event type
+func (l *Limiter) Spend(actor, eventType string) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	now := l.now()
+	for _, key := range l.keys(actor, eventType) {
+		l.refill(key, now).tokens--
+	}
+}
+
+// Wait blocks until an actor may emit an event type or ctx is done
+func (l *Limiter) Wait(ctx context.Context, actor, eventType string) error {
+	for {
+		delay := l.Delay(actor, eventType)
+		if delay == 0 {
+			return nil
+		}
+
+		timer := time.NewTimer(delay)
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return ctx.Err()
+		case <-timer.C:
+		}
+	}
+}
+
+// keys returns the buckets that apply to an actor and event type
+func (l *Limiter) keys(actor, eventType string) []bucketKey {
+	var keys []bucketKey
+	for i, lim := range l.limits {
+		if lim.eventType != "" && lim.eventType != eventType {
+			continue
+		}
+		switch lim.scope {
+		case scopeType:
+			keys = append(keys, bucketKey{limit: i, eventType: eventType})
+		case scopeActor:
+			keys = append(keys, bucketKey{limit: i, actor: actor})
+		case scopeActorType:
+			keys = append(keys, bucketKey{limit: i, actor: actor, eventType: eventType})
+		}
+	}
+	return keys
+}
+
+// refill returns a bucket topped up for the time elapsed; the caller must hold l.mu
+func (l *Limiter) refill(key bucketKey, now time.Time) *bucket {
+	rule := l.limits[key.limit].rule
+	b, exists := l.buckets[key]
+	if !exists {
+		b = &bucket{tokens: float64(rule.Burst), last: now}
+		l.buckets[key] = b
+		return b
+	}
+
+	elapsed := now.Sub(b.last).Seconds()
+	b.tokens = math.Min(float64(rule.Burst), b.tokens+elapsed*rule.Rate)
+	b.last = now
+	return b
+}
+
+// limitValidator rejects events whose actor or type is over its limit
+type limitValidator struct {
+	limiter *Limiter
+}
+
+// Validate implements atmos.EventValidator
+func (v *limitValidator) Validate(engine types.Engine, event atmos.Event) bool {
+	return v.limiter.Allowed(engine.(*atmos.Engine).Actor(), event.Type())
+}
+
+// spendListener spends tokens once an event commits
+type spendListener struct {
+	limiter *Limiter
+}
+
+// Handle implements atmos.EventListener
+func (s *spendListener) Handle(engine types.Engine, event atmos.Event) {
+	s.limiter.Spend(engine.(*atmos.Engine).Actor(), event.Type())
+}
@@ -0,0 +1,78 @@
+package ratelimit_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/cumulusrpg/atmos"
+	"github.com/cumulusrpg/atmos/ratelimit"
+	"github.com/stretchr/testify/assert"
+)
+
+type ChatEvent struct{}
+
+func (e ChatEvent) Type() string { return "chat" }
+
+type MoveEvent struct{}
+
+func (e MoveEvent) Type() string { return "move" }
+
+// TestLimiterRejectsPerActorAndType verifies buckets are tracked per actor and type
+func TestLimiterRejectsPerActorAndType(t *testing.T) {
+	clock := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	limiter := ratelimit.New(
+		ratelimit.PerActorAndType("chat", ratelimit.Rule{Rate: 1, Burst: 2}),
+		ratelimit.WithClock(func() time.Time { return clock }),
+	)
+
+	engine := atmos.NewEngine()
+	limiter.Install(engine, "chat", "move")
+
+	assert.True(t, engine.EmitAs("alice", ChatEvent{}))
+	assert.True(t, engine.EmitAs("alice", ChatEvent{}))
+	assert.False(t, engine.EmitAs("alice", ChatEvent{}), "Alice exhausted the burst")
+	assert.True(t, engine.EmitAs("bob", ChatEvent{}), "Bob has a separate bucket")
+	assert.True(t, engine.EmitAs("alice", MoveEvent{}), "Moves are not limited")
+
+	explanation := engine.ExplainAs("alice", ChatEvent{})
+	assert.False(t, explanation.Accepted)
+
+	// Tokens refill over time
+	clock = clock.Add(time.Second)
+	assert.True(t, engine.EmitAs("alice", ChatEvent{}))
+	assert.False(t, engine.EmitAs("alice", ChatEvent{}))
+}
+
+// TestLimiterSpendsOnlyOnCommit verifies rejected events don't consume tokens
+func TestLimiterSpendsOnlyOnCommit(t *testing.T) {
+	limiter := ratelimit.New(ratelimit.PerEventType("chat", ratelimit.Rule{Rate: 0, Burst: 1}))
+
+	engine := atmos.NewEngine()
+	limiter.Install(engine, "chat")
+	engine.When("chat").AllowedBy("no anonymous chat", func(e *atmos.Engine, actor string, event atmos.Event) bool {
+		return actor != ""
+	})
+
+	assert.False(t, engine.Emit(ChatEvent{}))
+	assert.False(t, engine.Emit(ChatEvent{}))
+	assert.True(t, engine.EmitAs("carol", ChatEvent{}), "Earlier rejections did not spend the token")
+	assert.False(t, engine.EmitAs("dave", ChatEvent{}), "The per-type bucket is shared by all actors")
+}
+
+// TestLimiterWaitQueuesCallers verifies Wait blocks until a token is available
+func TestLimiterWaitQueuesCallers(t *testing.T) {
+	limiter := ratelimit.New(ratelimit.PerActor(ratelimit.Rule{Rate: 100, Burst: 1}))
+	engine := atmos.NewEngine()
+	limiter.Install(engine, "move")
+
+	assert.True(t, engine.EmitAs("erin", MoveEvent{}))
+	assert.Greater(t, limiter.Delay("erin", "move"), time.Duration(0))
+
+	assert.NoError(t, limiter.Wait(context.Background(), "erin", "move"))
+	assert.True(t, engine.EmitAs("erin", MoveEvent{}))
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	assert.ErrorIs(t, limiter.Wait(ctx, "erin", "move"), context.Canceled)
+}
@@ -0,0 +1,38 @@
+package atmos
+
+import "encoding/json"
+
+// RawEvent is an event of a type this engine has no factory for, kept as its
+// original type string and payload. Raw events can be stored, replayed past
+// (no reducers are registered for them), and marshaled back unchanged, so
+// logs move between client versions without losing newer events.
+type RawEvent struct {
+	EventType string
+	Data      json.RawMessage
+}
+
+// Type implements Event
+func (e RawEvent) Type() string {
+	return e.EventType
+}
+
+// MarshalJSON writes the original payload
+func (e RawEvent) MarshalJSON() ([]byte, error) {
+	if len(e.Data) == 0 {
+		return []byte("null"), nil
+	}
+	return e.Data, nil
+}
+
+// WithRawEvents decodes events of unknown types as RawEvent in
+// UnmarshalEvents and UnmarshalEvent, instead of skipping or rejecting them
+func WithRawEvents() EngineOption {
+	return func(e *Engine) {
+		e.rawEvents = true
+	}
+}
+
+// rawEvent wraps an unknown record's payload, copying it away from the input buffer
+func rawEvent(eventType string, data json.RawMessage) RawEvent {
+	return RawEvent{EventType: eventType, Data: append(json.RawMessage(nil), data...)}
+}
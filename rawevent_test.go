@@ -0,0 +1,51 @@
+package atmos
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// TestRawEventsRoundTrip verifies unknown events survive decoding and re-encoding unchanged
+func TestRawEventsRoundTrip(t *testing.T) {
+	engine := NewEngine(WithRawEvents())
+	engine.RegisterEventTypes(RoundPlayedEvent{})
+
+	log := `[{"type":"emote","data":{"face":":)","big":12345678901234567890}},` +
+		`{"type":"round_played","data":{"winner":"alice"}}]`
+	events, err := engine.UnmarshalEvents([]byte(log))
+	require.NoError(t, err)
+	require.Len(t, events, 2)
+	assert.Equal(t, RawEvent{EventType: "emote", Data: json.RawMessage(`{"face":":)","big":12345678901234567890}`)}, events[0])
+	assert.Equal(t, "emote", events[0].Type())
+
+	data, err := engine.MarshalEvents(events)
+	require.NoError(t, err)
+	assert.Equal(t, log, string(data))
+}
+
+// TestRawEventsReplayPast verifies raw events can be stored and folded over by reducers
+func TestRawEventsReplayPast(t *testing.T) {
+	engine := newRoundEngine()
+	restored := NewEngine(WithRawEvents())
+	restored.RegisterEventTypes(RoundPlayedEvent{})
+
+	event, err := restored.UnmarshalEvent([]byte(`{"type":"emote","data":{"face":":("}}`))
+	require.NoError(t, err)
+	assert.True(t, engine.Emit(event), "Raw events can be committed")
+	assert.True(t, engine.Emit(RoundPlayedEvent{Winner: "bob"}))
+	assert.Equal(t, map[string]int{"bob": 1}, engine.GetState("wins"))
+
+	data, err := engine.MarshalEvent(event)
+	require.NoError(t, err)
+	assert.Equal(t, `{"type":"emote","data":{"face":":("}}`, string(data))
+}
+
+// TestRawEventsDisabledByDefault verifies unknown types are still skipped or rejected
+func TestRawEventsDisabledByDefault(t *testing.T) {
+	engine := NewEngine()
+	_, err := engine.UnmarshalEvent([]byte(`{"type":"emote","data":{}}`))
+	assert.ErrorIs(t, err, ErrUnknownEventType)
+}
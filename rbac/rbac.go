@@ -0,0 +1,224 @@
+// Package rbac enforces role-based access control: roles are granted and
+// revoked by events, and a policy table lists the roles each event type
+// requires, so admin-only events are enforced by the engine:
+//
+//	access := rbac.New(
+//		rbac.Assign("host", "admin"),
+//		rbac.Require("player_kicked", "admin", "moderator"),
+//		rbac.Require("game_force_ended", "admin"),
+//	)
+//	access.Install(engine)
+//
+//	engine.EmitAs("host", rbac.RoleGrantedEvent{Actor: "bea", Role: "moderator"})
+//	engine.EmitAs("bea", PlayerKickedEvent{Player: "cal"}) // accepted
+//
+// The actor is the one given to EmitAs (or EmitWithMetadata). Granting and
+// revoking roles requires one of the manager roles (default "admin").
+// Event types the table doesn't mention are not restricted.
+package rbac
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/cumulusrpg/atmos"
+	"github.com/cumulusrpg/atmos/types"
+)
+
+const (
+	// GrantedEventType is the type of RoleGrantedEvent
+	GrantedEventType = "role_granted"
+
+	// RevokedEventType is the type of RoleRevokedEvent
+	RevokedEventType = "role_revoked"
+)
+
+// RoleGrantedEvent gives an actor a role
+type RoleGrantedEvent struct {
+	Actor string `json:"actor"`
+	Role  string `json:"role"`
+}
+
+// Type implements atmos.Event
+func (e RoleGrantedEvent) Type() string { return GrantedEventType }
+
+// RoleRevokedEvent takes a role away from an actor
+type RoleRevokedEvent struct {
+	Actor string `json:"actor"`
+	Role  string `json:"role"`
+}
+
+// Type implements atmos.Event
+func (e RoleRevokedEvent) Type() string { return RevokedEventType }
+
+// Roles maps each actor to the roles they hold
+type Roles map[string]map[string]bool
+
+// Table maps event types to the roles allowed to emit them
+type Table struct {
+	name     string
+	required map[string][]string // event type -> roles, any of which suffices
+	initial  Roles
+}
+
+// Option configures table construction
+type Option func(*Table)
+
+// Require restricts an event type to actors holding any of the roles (repeatable)
+func Require(eventType string, roles ...string) Option {
+	return func(t *Table) {
+		t.required[eventType] = append(t.required[eventType], roles...)
+	}
+}
+
+// ManagedBy sets the roles allowed to grant and revoke roles (default "admin")
+func ManagedBy(roles ...string) Option {
+	return func(t *Table) {
+		t.required[GrantedEventType] = roles
+		t.required[RevokedEventType] = roles
+	}
+}
+
+// Assign gives an actor roles from the start, before any event, so someone
+// can grant the rest
+func Assign(actor string, roles ...string) Option {
+	return func(t *Table) {
+		if t.initial[actor] == nil {
+			t.initial[actor] = make(map[string]bool)
+		}
+		for _, role := range roles {
+			t.initial[actor][role] = true
+		}
+	}
+}
+
+// WithStateName sets the name of the state holding role assignments (default "roles")
+func WithStateName(name string) Option {
+	return func(t *Table) {
+		t.name = name
+	}
+}
+
+// New creates a policy table
+func New(opts ...Option) *Table {
+	t := &Table{
+		name: "roles",
+		required: map[string][]string{
+			GrantedEventType: {"admin"},
+			RevokedEventType: {"admin"},
+		},
+		initial: make(Roles),
+	}
+
+	// Apply options
+	for _, opt := range opts {
+		opt(t)
+	}
+
+	return t
+}
+
+// Install registers the role state, its reducers, and the access validator
+func (t *Table) Install(engine *atmos.Engine) {
+	engine.RegisterState(t.name, t.initial)
+	engine.RequiresAll(&accessValidator{table: t})
+	engine.When(GrantedEventType, func() atmos.Event { return &RoleGrantedEvent{} }).
+		Updates(t.name, t.grant)
+	engine.When(RevokedEventType, func() atmos.Event { return &RoleRevokedEvent{} }).
+		Updates(t.name, t.revoke)
+}
+
+// Roles returns an actor's roles, sorted
+func (t *Table) Roles(engine *atmos.Engine, actor string) []string {
+	var roles []string
+	for role := range t.assignments(engine)[actor] {
+		roles = append(roles, role)
+	}
+	sort.Strings(roles)
+	return roles
+}
+
+// Has reports whether an actor holds a role
+func (t *Table) Has(engine *atmos.Engine, actor, role string) bool {
+	return t.assignments(engine)[actor][role]
+}
+
+// Required returns the roles allowed to emit an event type (nil if unrestricted)
+func (t *Table) Required(eventType string) []string {
+	return t.required[eventType]
+}
+
+// Allowed reports whether an actor may emit an event type
+func (t *Table) Allowed(engine *atmos.Engine, actor, eventType string) bool {
+	required, restricted := t.required[eventType]
+	if !restricted {
+		return true
+	}
+	held := t.assignments(engine)[actor]
+	for _, role := range required {
+		if held[role] {
+			return true
+		}
+	}
+	return false
+}
+
+// assignments returns the current role state
+func (t *Table) assignments(engine *atmos.Engine) Roles {
+	return engine.GetState(t.name).(Roles)
+}
+
+// grant adds a role to an actor, copying the state
+func (t *Table) grant(engine *atmos.Engine, state interface{}, event atmos.Event) interface{} {
+	granted, _ := atmos.Deref(event).(RoleGrantedEvent)
+	return update(state.(Roles), granted.Actor, granted.Role, true)
+}
+
+// revoke removes a role from an actor, copying the state
+func (t *Table) revoke(engine *atmos.Engine, state interface{}, event atmos.Event) interface{} {
+	revoked, _ := atmos.Deref(event).(RoleRevokedEvent)
+	return update(state.(Roles), revoked.Actor, revoked.Role, false)
+}
+
+// update returns roles with one assignment changed, leaving the original untouched
+func update(roles Roles, actor, role string, held bool) Roles {
+	updated := make(Roles, len(roles)+1)
+	for a, r := range roles {
+		updated[a] = r
+	}
+	actorRoles := make(map[string]bool, len(roles[actor])+1)
+	for r := range roles[actor] {
+		actorRoles[r] = true
+	}
+	if held {
+		actorRoles[role] = true
+	} else {
+		delete(actorRoles, role)
+	}
+	updated[actor] = actorRoles
+	return updated
+}
+
+// accessValidator rejects events whose actor lacks the required roles
+type accessValidator struct {
+	table *Table
+}
+
+// Validate implements atmos.EventValidator
+func (v *accessValidator) Validate(engine types.Engine, event atmos.Event) bool {
+	return v.Check(engine, event) == nil
+}
+
+// Check implements atmos.ReasonedValidator
+func (v *accessValidator) Check(engine types.Engine, event atmos.Event) error {
+	e := engine.(*atmos.Engine)
+	if v.table.Allowed(e, e.Actor(), event.Type()) {
+		return nil
+	}
+	required := v.table.required[event.Type()]
+	if len(required) == 1 {
+		return fmt.Errorf("%s requires the %s role", event.Type(), required[0])
+	}
+	return fmt.Errorf("%s requires one of the roles %s", event.Type(), strings.Join(required, ", "))
+}
@@ -0,0 +1,82 @@
+package rbac_test
+
+import (
+	"testing"
+
+	"github.com/cumulusrpg/atmos"
+	"github.com/cumulusrpg/atmos/rbac"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type named string
+
+func (e named) Type() string { return string(e) }
+
+// newLobby lets the host kick players and force the game to end
+func newLobby(opts ...rbac.Option) (*atmos.Engine, *rbac.Table) {
+	access := rbac.New(append([]rbac.Option{
+		rbac.Assign("host", "admin"),
+		rbac.Require("player_kicked", "admin", "moderator"),
+		rbac.Require("game_force_ended", "admin"),
+	}, opts...)...)
+	engine := atmos.NewEngine()
+	access.Install(engine)
+	return engine, access
+}
+
+// TestRequiredRolesEnforced verifies restricted events need a role and others don't
+func TestRequiredRolesEnforced(t *testing.T) {
+	engine, _ := newLobby()
+
+	result := engine.EmitAsWithResult("bea", named("player_kicked"))
+	assert.False(t, result.Accepted)
+	assert.Equal(t, "player_kicked requires one of the roles admin, moderator", result.Reason)
+	assert.Equal(t, "game_force_ended requires the admin role", engine.EmitAsWithResult("bea", named("game_force_ended")).Reason)
+	assert.False(t, engine.Emit(named("game_force_ended")), "Emits without an actor hold no roles")
+
+	assert.True(t, engine.EmitAs("host", named("player_kicked")))
+	assert.True(t, engine.EmitAs("host", named("game_force_ended")))
+	assert.True(t, engine.EmitAs("bea", named("move_made")), "Unlisted event types are unrestricted")
+}
+
+// TestRolesGrantedByEvents verifies role changes are logged events only managers may emit
+func TestRolesGrantedByEvents(t *testing.T) {
+	engine, access := newLobby()
+
+	assert.False(t, engine.EmitAs("bea", rbac.RoleGrantedEvent{Actor: "bea", Role: "admin"}), "Actors can't promote themselves")
+	require.True(t, engine.EmitAs("host", rbac.RoleGrantedEvent{Actor: "bea", Role: "moderator"}))
+	assert.Equal(t, []string{"moderator"}, access.Roles(engine, "bea"))
+	assert.True(t, engine.EmitAs("bea", named("player_kicked")))
+	assert.False(t, engine.EmitAs("bea", named("game_force_ended")))
+
+	require.True(t, engine.EmitAs("host", rbac.RoleRevokedEvent{Actor: "bea", Role: "moderator"}))
+	assert.False(t, access.Has(engine, "bea", "moderator"))
+	assert.False(t, engine.EmitAs("bea", named("player_kicked")))
+	assert.True(t, access.Has(engine, "host", "admin"))
+}
+
+// TestRolesSurviveReload verifies role assignments are rebuilt from the log
+func TestRolesSurviveReload(t *testing.T) {
+	engine, _ := newLobby()
+	require.True(t, engine.EmitAs("host", rbac.RoleGrantedEvent{Actor: "bea", Role: "moderator"}))
+	data, err := engine.MarshalEvents(engine.GetEvents())
+	require.NoError(t, err)
+
+	restored, access := newLobby()
+	events, err := restored.UnmarshalEvents(data)
+	require.NoError(t, err)
+	restored.SetEvents(events)
+
+	assert.Equal(t, []string{"moderator"}, access.Roles(restored, "bea"))
+}
+
+// TestManagedBy verifies which roles may change assignments
+func TestManagedBy(t *testing.T) {
+	engine, access := newLobby(rbac.Assign("ops", "support"), rbac.ManagedBy("support"))
+
+	assert.False(t, engine.EmitAs("host", rbac.RoleGrantedEvent{Actor: "bea", Role: "admin"}))
+	assert.True(t, engine.EmitAs("ops", rbac.RoleGrantedEvent{Actor: "bea", Role: "admin"}))
+	assert.Equal(t, []string{"support"}, access.Required(rbac.GrantedEventType))
+	assert.Nil(t, access.Required("move_made"))
+}
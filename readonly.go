@@ -0,0 +1,21 @@
+package atmos
+
+import "errors"
+
+// ErrReadOnly is returned by every emit on a read-only engine
+var ErrReadOnly = errors.New("engine is read-only")
+
+// WithReadOnly makes an engine refuse every emit with ErrReadOnly while
+// still serving GetState and GetEvents, for replay viewers, analytics
+// processes, and follower replicas (see NewFollower) that must never write.
+// Logs can still be loaded with SetEvents or Import.
+func WithReadOnly() EngineOption {
+	return func(e *Engine) {
+		e.readOnly = true
+	}
+}
+
+// ReadOnly reports whether the engine refuses emits (see WithReadOnly)
+func (e *Engine) ReadOnly() bool {
+	return e.readOnly
+}
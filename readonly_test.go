@@ -0,0 +1,27 @@
+package atmos
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// TestReadOnlyRefusesEmits verifies every emit path fails while reads still work
+func TestReadOnlyRefusesEmits(t *testing.T) {
+	source := newTokenEngine()
+	source.Emit(TokensSpentEvent{Amount: 1})
+
+	engine := newTokenEngine(WithReadOnly())
+	assert.True(t, engine.ReadOnly())
+	engine.SetEvents(source.GetEvents())
+
+	assert.ErrorIs(t, engine.EmitWithResult(TokensSpentEvent{Amount: 1}).Err, ErrReadOnly)
+	assert.ErrorIs(t, engine.EmitAll(TokensSpentEvent{Amount: 1}).Err, ErrReadOnly)
+	assert.ErrorIs(t, engine.EmitAsWithResult("alice", TokensSpentEvent{Amount: 1}).Err, ErrReadOnly)
+	assert.False(t, engine.Emit(TokensSpentEvent{Amount: 1}))
+
+	require.Len(t, engine.GetEvents(), 1)
+	assert.Equal(t, TokenBalance{Tokens: 2}, engine.GetState("balance"))
+	assert.False(t, NewEngine().ReadOnly())
+}
@@ -0,0 +1,82 @@
+package atmos
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/cumulusrpg/atmos/types"
+)
+
+// TypedReasonedValidator validates a specific event type, explaining rejections
+type TypedReasonedValidator[T Event] interface {
+	CheckTyped(engine *Engine, event T) error
+}
+
+// ReasonedValidatorWrapper wraps a typed reasoned validator to implement the base interfaces
+type ReasonedValidatorWrapper[T Event] struct {
+	validator TypedReasonedValidator[T]
+}
+
+// Validate implements EventValidator
+func (w ReasonedValidatorWrapper[T]) Validate(engine types.Engine, event Event) bool {
+	return w.Check(engine, event) == nil
+}
+
+// Check implements ReasonedValidator
+func (w ReasonedValidatorWrapper[T]) Check(engine types.Engine, event Event) error {
+	return w.validator.CheckTyped(engine.(*Engine), event.(T))
+}
+
+func (w ReasonedValidatorWrapper[T]) validatorName() string {
+	return fmt.Sprintf("%T", w.validator)
+}
+
+// NewTypedReasonedValidator creates a wrapper for a typed reasoned validator
+func NewTypedReasonedValidator[T Event](validator TypedReasonedValidator[T]) EventValidator {
+	return ReasonedValidatorWrapper[T]{validator: validator}
+}
+
+// Because gives a plain validator a fixed rejection reason.
+// Usage: Requires(Because(Valid(&ValidMove{}), "that square is taken"))
+func Because(validator EventValidator, reason string) EventValidator {
+	return &becauseValidator{validator: validator, reason: errors.New(reason)}
+}
+
+// BecauseMessage gives a plain validator a localizable rejection reason (see Catalog).
+// Usage: Requires(BecauseMessage(Valid(&ValidMove{}), Msg("move.square_taken", nil)))
+func BecauseMessage(validator EventValidator, message *Message) EventValidator {
+	return &becauseValidator{validator: validator, reason: message}
+}
+
+// becauseValidator attaches a reason to a plain validator
+type becauseValidator struct {
+	validator EventValidator
+	reason    error
+}
+
+func (v *becauseValidator) Validate(engine types.Engine, event Event) bool {
+	return v.validator.Validate(engine, event)
+}
+
+func (v *becauseValidator) Check(engine types.Engine, event Event) error {
+	if v.validator.Validate(engine, event) {
+		return nil
+	}
+	return v.reason
+}
+
+func (v *becauseValidator) validatorName() string {
+	return validatorName(v.validator)
+}
+
+// check runs a validator, returning its error when it rejects the event
+// (nil for validators without reasons)
+func (e *Engine) check(validator EventValidator, event Event) (bool, error) {
+	if reasoned, ok := validator.(types.ReasonedValidator); ok {
+		if err := reasoned.Check(e, event); err != nil {
+			return false, err
+		}
+		return true, nil
+	}
+	return validator.Validate(e, event), nil
+}
@@ -0,0 +1,58 @@
+package atmos
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// MinimumOrder rejects small orders with a reason
+type MinimumOrder struct {
+	Minimum float64
+}
+
+func (v *MinimumOrder) CheckTyped(engine *Engine, event OrderPlacedEvent) error {
+	if event.Amount < v.Minimum {
+		return errors.New("orders must be at least 10")
+	}
+	return nil
+}
+
+// TestReasonedValidators verifies reasons flow into EmitWithResult and Explain
+func TestReasonedValidators(t *testing.T) {
+	engine := NewEngine()
+	engine.When("order_placed").Requires(
+		NewTypedReasonedValidator[OrderPlacedEvent](&MinimumOrder{Minimum: 10}),
+		Because(Valid(TypedValidatorFunc[OrderPlacedEvent](func(e *Engine, event OrderPlacedEvent) bool {
+			return event.OrderID != ""
+		})), "an order ID is required"),
+	)
+
+	result := engine.EmitWithResult(OrderPlacedEvent{OrderID: "ORD-1", Amount: 5})
+	assert.False(t, result.Accepted)
+	assert.Equal(t, "*atmos.MinimumOrder", result.RejectedBy)
+	assert.Equal(t, "orders must be at least 10", result.Reason)
+
+	result = engine.EmitWithResult(OrderPlacedEvent{Amount: 50})
+	assert.Equal(t, "an order ID is required", result.Reason)
+	assert.Equal(t, "atmos.TypedValidatorFunc[github.com/cumulusrpg/atmos.OrderPlacedEvent]", result.RejectedBy)
+
+	explanation := engine.Explain(OrderPlacedEvent{Amount: 5})
+	assert.Equal(t, "orders must be at least 10", explanation.Validators[0].Reason)
+	assert.Equal(t, "an order ID is required", explanation.Validators[1].Reason)
+	assert.Contains(t, explanation.String(), "FAIL *atmos.MinimumOrder: orders must be at least 10")
+
+	order := OrderPlacedEvent{OrderID: "ORD-2", Amount: 20}
+	assert.Equal(t, EmitResult{Accepted: true, Events: []Event{order}}, engine.EmitWithResult(order))
+}
+
+// TestEmitResultReportsPersistenceErrors verifies repository failures are surfaced
+func TestEmitResultReportsPersistenceErrors(t *testing.T) {
+	engine := NewEngine(WithRepository(&CustomRepository{shouldFail: true}))
+
+	result := engine.EmitWithResult(TestEvent{Name: "lost"})
+	assert.False(t, result.Accepted)
+	assert.Empty(t, result.RejectedBy)
+	assert.EqualError(t, result.Err, "simulated repository failure")
+}
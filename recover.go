@@ -0,0 +1,162 @@
+package atmos
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+)
+
+// RecoveryFailure describes a logged record that could not be loaded
+type RecoveryFailure struct {
+	Index  int    // position of the record in the log
+	Offset int64  // byte offset of the record in the input
+	Raw    []byte // the record as stored, when it could be delimited
+	Err    error
+}
+
+// Error implements error
+func (f RecoveryFailure) Error() string {
+	return fmt.Sprintf("record %d (byte %d): %v", f.Index, f.Offset, f.Err)
+}
+
+// Unwrap returns the underlying decoding error
+func (f RecoveryFailure) Unwrap() error {
+	return f.Err
+}
+
+// RecoveryReport describes what RecoverEvents salvaged from a damaged log
+type RecoveryReport struct {
+	Recovered int               // events loaded
+	Failures  []RecoveryFailure // records that could not be loaded, in log order
+	Truncated bool              // loading stopped before the end of the log
+}
+
+// Clean reports whether the whole log loaded without failures
+func (r RecoveryReport) Clean() bool {
+	return len(r.Failures) == 0 && !r.Truncated
+}
+
+// Err returns the failures joined into one error, or nil for a clean load
+func (r RecoveryReport) Err() error {
+	errs := make([]error, len(r.Failures))
+	for i, failure := range r.Failures {
+		errs[i] = failure
+	}
+	return errors.Join(errs...)
+}
+
+// RecoverOption configures RecoverEvents
+type RecoverOption func(*recoverConfig)
+
+type recoverConfig struct {
+	quarantine bool
+}
+
+// QuarantineBadRecords skips records that can't be decoded (keeping them in
+// the report) instead of stopping at the first one
+func QuarantineBadRecords() RecoverOption {
+	return func(c *recoverConfig) {
+		c.quarantine = true
+	}
+}
+
+// RecoverEvents loads a partially corrupted log, in MarshalEvents form (a JSON
+// array) or as JSON lines, one MarshalEvent record per line. Unlike
+// UnmarshalEvents it never fails outright: it returns every event up to the
+// first record that can't be decoded (unknown type, bad payload, or broken
+// JSON) along with a report of what went wrong.
+//
+// With QuarantineBadRecords, bad records are skipped and loading continues;
+// engines created WithQuarantine also keep them for MarshalLog.
+// A syntax error inside a JSON array still ends loading, since the records
+// after it can't be delimited; JSON lines recover from each bad line.
+func (e *Engine) RecoverEvents(data []byte, opts ...RecoverOption) ([]Event, RecoveryReport) {
+	config := &recoverConfig{}
+
+	// Apply options
+	for _, opt := range opts {
+		opt(config)
+	}
+
+	var events []Event
+	var report RecoveryReport
+	var quarantined []QuarantinedRecord
+	load := func(index int, offset int64, raw json.RawMessage) bool {
+		event, err := e.UnmarshalEvent(raw)
+		if err != nil {
+			report.Failures = append(report.Failures, RecoveryFailure{Index: index, Offset: offset, Raw: raw, Err: err})
+			if config.quarantine && json.Valid(raw) { // Broken JSON can't be written back
+				var wrapper struct {
+					Type string `json:"type"`
+				}
+				_ = json.Unmarshal(raw, &wrapper)
+				quarantined = e.quarantine(quarantined, len(events), wrapper.Type, raw, err)
+			}
+			return config.quarantine
+		}
+		events = append(events, event)
+		return true
+	}
+
+	trimmed := bytes.TrimSpace(data)
+	switch {
+	case len(trimmed) == 0 || bytes.Equal(trimmed, []byte("null")):
+	case trimmed[0] == '[':
+		report.Truncated = !recoverArray(data, load, &report)
+	default:
+		report.Truncated = !recoverLines(data, load)
+	}
+
+	report.Recovered = len(events)
+	e.decoded(events, quarantined)
+	return events, report
+}
+
+// recoverArray feeds each element of a JSON array to load, reporting
+// whether the whole array was read
+func recoverArray(data []byte, load func(int, int64, json.RawMessage) bool, report *RecoveryReport) bool {
+	decoder := json.NewDecoder(bytes.NewReader(data))
+	if _, err := decoder.Token(); err != nil { // opening bracket
+		report.Failures = append(report.Failures, RecoveryFailure{Err: err})
+		return false
+	}
+
+	for index := 0; decoder.More(); index++ {
+		offset := decoder.InputOffset()
+		var raw json.RawMessage
+		if err := decoder.Decode(&raw); err != nil {
+			report.Failures = append(report.Failures, RecoveryFailure{Index: index, Offset: offset, Err: err})
+			return false
+		}
+		if !load(index, offset, raw) {
+			return false
+		}
+	}
+
+	if _, err := decoder.Token(); err != nil { // closing bracket
+		report.Failures = append(report.Failures, RecoveryFailure{Offset: decoder.InputOffset(), Err: err})
+		return false
+	}
+	return true
+}
+
+// recoverLines feeds each non-blank line to load, reporting whether every
+// line was read
+func recoverLines(data []byte, load func(int, int64, json.RawMessage) bool) bool {
+	var offset int64
+	index := 0
+	for _, line := range bytes.SplitAfter(data, []byte("\n")) {
+		start := offset
+		offset += int64(len(line))
+		line = bytes.TrimSpace(line)
+		if len(line) == 0 {
+			continue
+		}
+		if !load(index, start, json.RawMessage(line)) {
+			return false
+		}
+		index++
+	}
+	return true
+}
@@ -0,0 +1,102 @@
+package atmos
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// newRecoveryEngine decodes round_played events
+func newRecoveryEngine() *Engine {
+	engine := NewEngine()
+	engine.RegisterEventTypes(RoundPlayedEvent{})
+	return engine
+}
+
+// TestRecoverCleanLog verifies an intact log loads with a clean report
+func TestRecoverCleanLog(t *testing.T) {
+	engine := newRecoveryEngine()
+	data, err := engine.MarshalEvents([]Event{RoundPlayedEvent{Winner: "alice"}, RoundPlayedEvent{Winner: "bob"}})
+	require.NoError(t, err)
+
+	events, report := engine.RecoverEvents(data)
+	assert.Equal(t, []Event{&RoundPlayedEvent{Winner: "alice"}, &RoundPlayedEvent{Winner: "bob"}}, events)
+	assert.True(t, report.Clean())
+	assert.Equal(t, 2, report.Recovered)
+	assert.NoError(t, report.Err())
+
+	events, report = engine.RecoverEvents([]byte("null"))
+	assert.Empty(t, events)
+	assert.True(t, report.Clean())
+}
+
+// TestRecoverStopsAtFirstFailure verifies loading keeps the events before a bad record
+func TestRecoverStopsAtFirstFailure(t *testing.T) {
+	engine := newRecoveryEngine()
+	data := []byte(`[
+		{"type":"round_played","data":{"winner":"alice"}},
+		{"type":"round_played","data":{"winner":7}},
+		{"type":"round_played","data":{"winner":"bob"}}
+	]`)
+
+	events, report := engine.RecoverEvents(data)
+	assert.Equal(t, []Event{&RoundPlayedEvent{Winner: "alice"}}, events)
+	assert.True(t, report.Truncated)
+	require.Len(t, report.Failures, 1)
+	assert.Equal(t, 1, report.Failures[0].Index)
+	assert.Equal(t, `{"type":"round_played","data":{"winner":7}}`, string(report.Failures[0].Raw))
+	assert.Contains(t, report.Err().Error(), "record 1")
+}
+
+// TestRecoverQuarantinesBadRecords verifies bad records can be skipped instead
+func TestRecoverQuarantinesBadRecords(t *testing.T) {
+	engine := newRecoveryEngine()
+	data := []byte(`[
+		{"type":"round_played","data":{"winner":"alice"}},
+		{"type":"dice_rolled","data":{"faces":6}},
+		{"type":"round_played","data":{"winner":"bob"}}
+	]`)
+
+	events, report := engine.RecoverEvents(data, QuarantineBadRecords())
+	assert.Len(t, events, 2)
+	assert.False(t, report.Truncated)
+	assert.False(t, report.Clean())
+	require.Len(t, report.Failures, 1)
+	assert.True(t, errors.Is(report.Failures[0], ErrUnknownEventType))
+}
+
+// TestRecoverTruncatedArray verifies a log cut off mid-write keeps its complete records
+func TestRecoverTruncatedArray(t *testing.T) {
+	engine := newRecoveryEngine()
+	data := []byte(`[{"type":"round_played","data":{"winner":"alice"}},{"type":"round_pl`)
+
+	events, report := engine.RecoverEvents(data, QuarantineBadRecords())
+	assert.Len(t, events, 1)
+	assert.True(t, report.Truncated, "Records after a syntax error can't be delimited")
+	require.Len(t, report.Failures, 1)
+	assert.Equal(t, 1, report.Failures[0].Index)
+	assert.Greater(t, report.Failures[0].Offset, int64(0))
+}
+
+// TestRecoverJSONLines verifies line-delimited logs recover past bad lines
+func TestRecoverJSONLines(t *testing.T) {
+	engine := newRecoveryEngine()
+	data := []byte(`{"type":"round_played","data":{"winner":"alice"}}
+{"type":"round_pl
+{"type":"round_played","data":{"winner":"bob"}}
+`)
+
+	events, report := engine.RecoverEvents(data)
+	assert.Len(t, events, 1)
+	assert.True(t, report.Truncated)
+
+	events, report = engine.RecoverEvents(data, QuarantineBadRecords())
+	assert.Equal(t, []Event{&RoundPlayedEvent{Winner: "alice"}, &RoundPlayedEvent{Winner: "bob"}}, events)
+	assert.False(t, report.Truncated)
+	require.Len(t, report.Failures, 1)
+	assert.Equal(t, 1, report.Failures[0].Index)
+	assert.Equal(t, int64(50), report.Failures[0].Offset)
+	assert.Equal(t, `{"type":"round_pl`, string(report.Failures[0].Raw))
+}
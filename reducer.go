@@ -0,0 +1,90 @@
+package atmos
+
+// ReducerMiddleware wraps a reducer with a cross-cutting concern
+type ReducerMiddleware func(next StateReducer) StateReducer
+
+// ChainReducers returns a reducer that applies several reducers in order,
+// each receiving the state the previous one returned
+func ChainReducers(reducers ...StateReducer) StateReducer {
+	return func(engine *Engine, state interface{}, event Event) interface{} {
+		for _, reducer := range reducers {
+			state = reducer(engine, state, event)
+		}
+		return state
+	}
+}
+
+// WrapReducer applies middleware to a reducer; the first middleware is the outermost
+func WrapReducer(reducer StateReducer, middleware ...ReducerMiddleware) StateReducer {
+	for i := len(middleware) - 1; i >= 0; i-- {
+		reducer = middleware[i](reducer)
+	}
+	return reducer
+}
+
+// OnlyIf runs the reducer only when condition holds, leaving the state
+// unchanged otherwise
+// Usage: OnlyIf(func(e *Engine, state interface{}, event Event) bool { return state.(Game).Started })
+func OnlyIf(condition func(engine *Engine, state interface{}, event Event) bool) ReducerMiddleware {
+	return func(next StateReducer) StateReducer {
+		return func(engine *Engine, state interface{}, event Event) interface{} {
+			if !condition(engine, state, event) {
+				return state
+			}
+			return next(engine, state, event)
+		}
+	}
+}
+
+// LogReducer reports each reduction with the event and the state before and after
+func LogReducer(logf func(format string, args ...interface{})) ReducerMiddleware {
+	return func(next StateReducer) StateReducer {
+		return func(engine *Engine, state interface{}, event Event) interface{} {
+			after := next(engine, state, event)
+			logf("%s: %v -> %v", event.Type(), state, after)
+			return after
+		}
+	}
+}
+
+// CheckState validates the state a reducer produces; when check fails, the
+// event is ignored (the previous state is kept) and report, if set, is told why
+func CheckState(check func(state interface{}) error, report func(event Event, err error)) ReducerMiddleware {
+	return func(next StateReducer) StateReducer {
+		return func(engine *Engine, state interface{}, event Event) interface{} {
+			after := next(engine, state, event)
+			if err := check(after); err != nil {
+				if report != nil {
+					report(event, err)
+				}
+				return state
+			}
+			return after
+		}
+	}
+}
+
+// UseReducerMiddleware wraps every reducer of a state in middleware, whether
+// it was registered before or after this call. Middleware added by later
+// calls wraps around earlier middleware.
+func (e *Engine) UseReducerMiddleware(stateName string, middleware ...ReducerMiddleware) {
+	wrap := func(next StateReducer) StateReducer {
+		return WrapReducer(next, middleware...)
+	}
+	registrations := e.mutableRegistrations()
+	registrations.reducerMiddleware[stateName] = append(registrations.reducerMiddleware[stateName], wrap)
+
+	if registry, exists := registrations.states[stateName]; exists {
+		for eventType, reducer := range registry.Reducers {
+			registry.Reducers[eventType] = wrap(reducer)
+		}
+	}
+}
+
+// wrapRegistered applies a state's middleware to a newly registered reducer
+func (e *Engine) wrapRegistered(stateName string, reducer StateReducer) StateReducer {
+	for _, wrap := range e.reducerMiddleware[stateName] {
+		reducer = wrap(reducer)
+	}
+	return reducer
+}
@@ -0,0 +1,139 @@
+package atmos
+
+import (
+	"errors"
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+type GameStartedEvent struct{}
+
+func (e GameStartedEvent) Type() string { return "game_started" }
+
+// Table tracks a game that ignores moves made before it starts
+type Table struct {
+	Started bool
+	Spent   int
+	Passes  int
+}
+
+func started(engine *Engine, state interface{}, event Event) bool {
+	return state.(Table).Started || event.Type() == "game_started"
+}
+
+// newTableEngine registers reducers on both sides of the middleware call
+func newTableEngine(middleware ...ReducerMiddleware) *Engine {
+	engine := NewEngine()
+	engine.RegisterState("table", Table{})
+	engine.When("game_started").Updates("table", func(e *Engine, state interface{}, event Event) interface{} {
+		table := state.(Table)
+		table.Started = true
+		return table
+	})
+	engine.When("tokens_spent").Updates("table", func(e *Engine, state interface{}, event Event) interface{} {
+		table := state.(Table)
+		table.Spent += event.(TokensSpentEvent).Amount
+		return table
+	})
+	engine.UseReducerMiddleware("table", middleware...)
+	engine.When("pass").Updates("table", func(e *Engine, state interface{}, event Event) interface{} {
+		table := state.(Table)
+		table.Passes++
+		return table
+	})
+	return engine
+}
+
+// TestUseReducerMiddlewareGuardsEveryReducer verifies one guard covers reducers registered before and after it
+func TestUseReducerMiddlewareGuardsEveryReducer(t *testing.T) {
+	engine := newTableEngine(OnlyIf(started))
+
+	engine.Emit(TokensSpentEvent{Amount: 2})
+	engine.Emit(PassEvent{Player: "alice"})
+	engine.Emit(GameStartedEvent{})
+	engine.Emit(TokensSpentEvent{Amount: 3})
+	engine.Emit(PassEvent{Player: "bob"})
+
+	assert.Equal(t, Table{Started: true, Spent: 3, Passes: 1}, engine.GetState("table"))
+}
+
+// TestUseReducerMiddlewareOrder verifies later calls wrap earlier ones and the first middleware is outermost
+func TestUseReducerMiddlewareOrder(t *testing.T) {
+	var calls []string
+	trace := func(name string) ReducerMiddleware {
+		return func(next StateReducer) StateReducer {
+			return func(engine *Engine, state interface{}, event Event) interface{} {
+				calls = append(calls, name)
+				return next(engine, state, event)
+			}
+		}
+	}
+	engine := newTableEngine(trace("a"), trace("b"))
+	engine.UseReducerMiddleware("table", trace("c"))
+
+	engine.Emit(TokensSpentEvent{Amount: 1})
+	engine.GetState("table")
+	assert.Equal(t, []string{"c", "a", "b"}, calls)
+
+	calls = nil
+	engine.Emit(PassEvent{Player: "alice"})
+	engine.GetState("table")
+	assert.Equal(t, []string{"c", "a", "b", "c", "a", "b"}, calls, "Reducers registered after the first call are wrapped the same way")
+}
+
+// TestUseReducerMiddlewareLeavesBlueprintAlone verifies engines built from a blueprint don't share middleware
+func TestUseReducerMiddlewareLeavesBlueprintAlone(t *testing.T) {
+	template := newTableEngine()
+	engine := template.Fork()
+	engine.UseReducerMiddleware("table", OnlyIf(started))
+
+	template.Emit(TokensSpentEvent{Amount: 2})
+	engine.Emit(TokensSpentEvent{Amount: 2})
+
+	assert.Equal(t, Table{Spent: 2}, template.GetState("table"))
+	assert.Equal(t, Table{}, engine.GetState("table"))
+}
+
+// TestChainReducers verifies each reducer sees the previous one's result
+func TestChainReducers(t *testing.T) {
+	double := func(e *Engine, state interface{}, event Event) interface{} { return state.(int) * 2 }
+	increment := func(e *Engine, state interface{}, event Event) interface{} { return state.(int) + 1 }
+
+	assert.Equal(t, 7, ChainReducers(double, increment)(nil, 3, PassEvent{}))
+	assert.Equal(t, 8, ChainReducers(increment, double)(nil, 3, PassEvent{}))
+	assert.Equal(t, 3, ChainReducers()(nil, 3, PassEvent{}))
+}
+
+// TestLogReducer verifies each reduction is reported
+func TestLogReducer(t *testing.T) {
+	var lines []string
+	logf := func(format string, args ...interface{}) { lines = append(lines, fmt.Sprintf(format, args...)) }
+	engine := newTableEngine(LogReducer(logf))
+
+	engine.Emit(GameStartedEvent{})
+	engine.GetState("table")
+
+	assert.Equal(t, []string{"game_started: {false 0 0} -> {true 0 0}"}, lines)
+}
+
+// TestCheckState verifies an event producing an invalid state is ignored and reported
+func TestCheckState(t *testing.T) {
+	var reported []string
+	engine := newTableEngine(CheckState(func(state interface{}) error {
+		if state.(Table).Spent > 5 {
+			return errors.New("overspent")
+		}
+		return nil
+	}, func(event Event, err error) {
+		reported = append(reported, event.Type()+": "+err.Error())
+	}))
+
+	engine.Emit(TokensSpentEvent{Amount: 4})
+	engine.Emit(TokensSpentEvent{Amount: 4})
+	engine.Emit(TokensSpentEvent{Amount: 1})
+
+	assert.Equal(t, Table{Spent: 5}, engine.GetState("table"))
+	assert.Equal(t, []string{"tokens_spent: overspent"}, reported)
+}
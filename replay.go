@@ -0,0 +1,252 @@
+package atmos
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+)
+
+// ReplayFormat is the version of the replay file format written by Record
+const ReplayFormat = 1
+
+var (
+	// ErrReplayFormat is returned when loading a replay written in an unsupported format
+	ErrReplayFormat = errors.New("unsupported replay format")
+
+	// ErrReplaySchema is returned when a replay was recorded against different event definitions
+	ErrReplaySchema = errors.New("replay schema mismatch")
+)
+
+// ReplayMetadata describes a recorded game
+type ReplayMetadata struct {
+	Title      string            `json:"title,omitempty"`
+	Version    string            `json:"version,omitempty"` // game/rules version, set by the game
+	Players    []string          `json:"players,omitempty"`
+	Result     string            `json:"result,omitempty"`
+	RecordedAt time.Time         `json:"recorded_at"`
+	Extra      map[string]string `json:"extra,omitempty"`
+}
+
+// Replay is a shareable recording of a game: its event log plus enough
+// information to check it still matches the engine playing it back
+type Replay struct {
+	Format   int             `json:"format"`
+	Schema   string          `json:"schema"` // fingerprint of the recording engine's event schemas
+	Metadata ReplayMetadata  `json:"metadata"`
+	Events   json.RawMessage `json:"events"` // log in MarshalEvents form
+}
+
+// Record captures the engine's event log as a replay. RecordedAt defaults to now.
+func (e *Engine) Record(metadata ReplayMetadata) (*Replay, error) {
+	events, err := e.MarshalEvents(e.GetEvents())
+	if err != nil {
+		return nil, err
+	}
+	if metadata.RecordedAt.IsZero() {
+		metadata.RecordedAt = time.Now().UTC()
+	}
+	return &Replay{
+		Format:   ReplayFormat,
+		Schema:   e.schemaFingerprint(),
+		Metadata: metadata,
+		Events:   events,
+	}, nil
+}
+
+// LoadReplay decodes a replay file
+func LoadReplay(data []byte) (*Replay, error) {
+	var replay Replay
+	if err := json.Unmarshal(data, &replay); err != nil {
+		return nil, err
+	}
+	if replay.Format < 1 || replay.Format > ReplayFormat {
+		return nil, fmt.Errorf("%w: %d", ErrReplayFormat, replay.Format)
+	}
+	return &replay, nil
+}
+
+// Marshal encodes the replay for sharing
+func (r *Replay) Marshal() ([]byte, error) {
+	return json.Marshal(r)
+}
+
+// schemaFingerprint hashes the event schemas so replays recorded against
+// different event definitions are detected
+func (e *Engine) schemaFingerprint() string {
+	data, err := json.Marshal(e.ExportEventSchemas()) // map keys marshal sorted
+	if err != nil {
+		return ""
+	}
+	sum := sha256.Sum256(data)
+	return hex.EncodeToString(sum[:8])
+}
+
+// Playback steps through a replay on a private copy of an engine's
+// registrations. It is safe for concurrent use, so one goroutine may Play
+// while others read state or Pause.
+//
+//	playback, err := engine.Playback(replay)
+//	go playback.Play(ctx, 500*time.Millisecond)
+//	board := playback.State("board")
+type Playback struct {
+	mu       sync.Mutex
+	engine   *Engine
+	events   []Event
+	position int
+	paused   bool
+}
+
+// PlaybackOption configures playback
+type PlaybackOption func(*playbackConfig)
+
+type playbackConfig struct {
+	ignoreSchema bool
+}
+
+// IgnoreSchema plays back replays recorded against different event schemas
+func IgnoreSchema() PlaybackOption {
+	return func(c *playbackConfig) {
+		c.ignoreSchema = true
+	}
+}
+
+// Playback prepares a replay for playback, positioned before the first event
+func (e *Engine) Playback(replay *Replay, opts ...PlaybackOption) (*Playback, error) {
+	config := &playbackConfig{}
+
+	// Apply options
+	for _, opt := range opts {
+		opt(config)
+	}
+
+	if replay.Format < 1 || replay.Format > ReplayFormat {
+		return nil, fmt.Errorf("%w: %d", ErrReplayFormat, replay.Format)
+	}
+	if schema := e.schemaFingerprint(); !config.ignoreSchema && replay.Schema != schema {
+		return nil, fmt.Errorf("%w: recorded with %s, playing with %s", ErrReplaySchema, replay.Schema, schema)
+	}
+
+	events, err := e.UnmarshalEvents(replay.Events)
+	if err != nil {
+		return nil, err
+	}
+
+	engine := e.forkWith(nil)
+	return &Playback{engine: engine, events: events}, nil
+}
+
+// Len returns the number of events in the replay
+func (p *Playback) Len() int {
+	return len(p.events)
+}
+
+// Position returns how many events have been played
+func (p *Playback) Position() int {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	return p.position
+}
+
+// Seek moves to a position between 0 and Len
+func (p *Playback) Seek(position int) error {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	if position < 0 || position > len(p.events) {
+		return fmt.Errorf("position %d out of range [0, %d]", position, len(p.events))
+	}
+	p.seek(position)
+	return nil
+}
+
+// Step plays the next event, reporting false at the end of the replay
+func (p *Playback) Step() bool {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	if p.position >= len(p.events) {
+		return false
+	}
+	p.seek(p.position + 1)
+	return true
+}
+
+// StepBack rewinds one event, reporting false at the start of the replay
+func (p *Playback) StepBack() bool {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	if p.position == 0 {
+		return false
+	}
+	p.seek(p.position - 1)
+	return true
+}
+
+// seek sets the position; the caller must hold p.mu
+func (p *Playback) seek(position int) {
+	p.position = position
+	p.engine.SetEvents(p.events[:position])
+}
+
+// Play steps once per interval until the end of the replay, Pause, or ctx
+// is done. It returns ctx.Err() when cancelled.
+func (p *Playback) Play(ctx context.Context, interval time.Duration) error {
+	p.mu.Lock()
+	p.paused = false
+	p.mu.Unlock()
+
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-ticker.C:
+			if p.Paused() || !p.Step() {
+				return nil
+			}
+		}
+	}
+}
+
+// Pause stops a running Play after its current step
+func (p *Playback) Pause() {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.paused = true
+}
+
+// Paused reports whether Pause was called since Play last started
+func (p *Playback) Paused() bool {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	return p.paused
+}
+
+// Events returns the events played so far
+func (p *Playback) Events() []Event {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	return append([]Event(nil), p.events[:p.position]...)
+}
+
+// State returns a state as of the current position
+func (p *Playback) State(name string) interface{} {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	return p.engine.GetState(name)
+}
+
+// StateFor returns a state as of the current position, masked for a viewer
+func (p *Playback) StateFor(viewer, name string) interface{} {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	return p.engine.GetStateFor(viewer, name)
+}
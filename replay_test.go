@@ -0,0 +1,133 @@
+package atmos
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// RoundPlayedEvent records the winner of a round
+type RoundPlayedEvent struct {
+	Winner string `json:"winner"`
+}
+
+func (e RoundPlayedEvent) Type() string { return "round_played" }
+
+// newRoundEngine tallies round wins per player
+func newRoundEngine() *Engine {
+	engine := NewEngine()
+	engine.RegisterEventTypes(RoundPlayedEvent{})
+	engine.RegisterState("wins", map[string]int{})
+	engine.When("round_played").
+		Updates("wins", func(e *Engine, state interface{}, event Event) interface{} {
+			wins := map[string]int{}
+			for player, n := range state.(map[string]int) {
+				wins[player] = n
+			}
+			switch round := event.(type) {
+			case RoundPlayedEvent:
+				wins[round.Winner]++
+			case *RoundPlayedEvent:
+				wins[round.Winner]++
+			}
+			return wins
+		})
+	return engine
+}
+
+// recordRounds plays a short game and returns its shared replay file
+func recordRounds(t *testing.T) []byte {
+	engine := newRoundEngine()
+	for _, winner := range []string{"alice", "bob", "alice"} {
+		require.True(t, engine.Emit(RoundPlayedEvent{Winner: winner}))
+	}
+	replay, err := engine.Record(ReplayMetadata{Players: []string{"alice", "bob"}, Result: "alice wins"})
+	require.NoError(t, err)
+	data, err := replay.Marshal()
+	require.NoError(t, err)
+	return data
+}
+
+// TestReplayRoundTrips verifies a recorded game loads with its metadata
+func TestReplayRoundTrips(t *testing.T) {
+	replay, err := LoadReplay(recordRounds(t))
+	require.NoError(t, err)
+
+	assert.Equal(t, ReplayFormat, replay.Format)
+	assert.Equal(t, []string{"alice", "bob"}, replay.Metadata.Players)
+	assert.Equal(t, "alice wins", replay.Metadata.Result)
+	assert.False(t, replay.Metadata.RecordedAt.IsZero())
+
+	_, err = LoadReplay([]byte(`{"format": 99}`))
+	assert.True(t, errors.Is(err, ErrReplayFormat))
+}
+
+// TestPlaybackSeeksAndSteps verifies playback state follows the position
+func TestPlaybackSeeksAndSteps(t *testing.T) {
+	replay, err := LoadReplay(recordRounds(t))
+	require.NoError(t, err)
+	playback, err := newRoundEngine().Playback(replay)
+	require.NoError(t, err)
+
+	assert.Equal(t, 3, playback.Len())
+	assert.Equal(t, 0, playback.Position())
+	assert.Equal(t, map[string]int{}, playback.State("wins"))
+
+	assert.True(t, playback.Step())
+	assert.Equal(t, map[string]int{"alice": 1}, playback.State("wins"))
+
+	require.NoError(t, playback.Seek(3))
+	assert.Equal(t, map[string]int{"alice": 2, "bob": 1}, playback.State("wins"))
+	assert.False(t, playback.Step(), "At the end")
+	assert.Len(t, playback.Events(), 3)
+
+	assert.True(t, playback.StepBack())
+	assert.Equal(t, map[string]int{"alice": 1, "bob": 1}, playback.State("wins"))
+	assert.Error(t, playback.Seek(4))
+	require.NoError(t, playback.Seek(0))
+	assert.False(t, playback.StepBack(), "At the start")
+}
+
+// TestPlaybackPlaysAndPauses verifies Play advances on a timer until paused or finished
+func TestPlaybackPlaysAndPauses(t *testing.T) {
+	replay, err := LoadReplay(recordRounds(t))
+	require.NoError(t, err)
+	playback, err := newRoundEngine().Playback(replay)
+	require.NoError(t, err)
+
+	require.NoError(t, playback.Play(context.Background(), time.Millisecond))
+	assert.Equal(t, 3, playback.Position(), "Play runs to the end")
+
+	require.NoError(t, playback.Seek(0))
+	done := make(chan error)
+	go func() { done <- playback.Play(context.Background(), 20*time.Millisecond) }()
+	require.Eventually(t, func() bool { return playback.Position() == 1 }, time.Second, time.Millisecond)
+	playback.Pause()
+	require.NoError(t, <-done)
+	assert.True(t, playback.Paused())
+	assert.Equal(t, 1, playback.Position(), "Pausing stops before the next step")
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	assert.Equal(t, context.Canceled, playback.Play(ctx, time.Hour))
+	assert.False(t, playback.Paused(), "Play resumes a paused playback")
+}
+
+// TestPlaybackChecksSchema verifies replays recorded against other event definitions are refused
+func TestPlaybackChecksSchema(t *testing.T) {
+	replay, err := LoadReplay(recordRounds(t))
+	require.NoError(t, err)
+
+	changed := newRoundEngine()
+	changed.RegisterEventTypes(CardDrawnEvent{})
+	_, err = changed.Playback(replay)
+	assert.True(t, errors.Is(err, ErrReplaySchema))
+
+	playback, err := changed.Playback(replay, IgnoreSchema())
+	require.NoError(t, err)
+	assert.Equal(t, 3, playback.Len())
+}
@@ -0,0 +1,60 @@
+package atmos
+
+import (
+	"fmt"
+
+	"github.com/cumulusrpg/atmos/types"
+)
+
+// Replaying reports whether the engine is re-emitting a log (see Replay and
+// WhileReplaying). Listeners with non-idempotent side effects should do
+// nothing while it is true; effects, async listeners, and the webhook and
+// event stream adapters already skip.
+func (e *Engine) Replaying() bool {
+	return e.replaying > 0
+}
+
+// IsReplaying reports whether an engine handed to a listener is replaying
+func IsReplaying(engine types.Engine) bool {
+	replayer, ok := engine.(interface{ Replaying() bool })
+	return ok && replayer.Replaying()
+}
+
+// WhileReplaying runs fn in replay mode
+func (e *Engine) WhileReplaying(fn func()) {
+	e.replaying++
+	defer func() { e.replaying-- }()
+	fn()
+}
+
+// Replay re-emits events in replay mode, for rebuilding an engine whose
+// listeners maintain state outside the log. It stops at the first event
+// that isn't accepted, and refuses to run on a paused engine (ErrPaused).
+// Replayed logs should hold only the events emitted directly, since
+// listeners derive the rest again.
+func (e *Engine) Replay(events []Event) error {
+	if e.paused != nil {
+		return fmt.Errorf("replaying: %w", ErrPaused)
+	}
+	var err error
+	e.WhileReplaying(func() {
+		for i, event := range events {
+			result := e.EmitWithResult(event)
+			switch {
+			case result.Err != nil:
+				err = fmt.Errorf("replaying event %d (%s): %w", i, event.Type(), result.Err)
+				return
+			case result.Queued:
+				err = fmt.Errorf("replaying event %d (%s): %w", i, event.Type(), ErrPaused)
+				return
+			case !result.Accepted:
+				err = fmt.Errorf("replaying event %d (%s): rejected by %s", i, event.Type(), result.RejectedBy)
+				if result.Reason != "" {
+					err = fmt.Errorf("%w: %s", err, result.Reason)
+				}
+				return
+			}
+		}
+	})
+	return err
+}
@@ -0,0 +1,53 @@
+package atmos
+
+import (
+	"testing"
+
+	"github.com/cumulusrpg/atmos/types"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// TestReplayModeVisibleToListeners verifies listeners can tell replays from live emits
+func TestReplayModeVisibleToListeners(t *testing.T) {
+	engine := NewEngine()
+	var modes []bool
+	engine.When("test_event").Then(NewTypedListener(TypedListenerFunc[TestEvent](func(e *Engine, event TestEvent) {
+		modes = append(modes, IsReplaying(e))
+	})))
+
+	require.NoError(t, engine.Replay([]Event{TestEvent{Name: "a"}, TestEvent{Name: "b"}}))
+	assert.False(t, engine.Replaying())
+	engine.Emit(TestEvent{Name: "c"})
+	assert.Equal(t, []bool{true, true, false}, modes)
+}
+
+// TestReplaySuppressesEffectsAndAsyncListeners verifies built-in side effects don't re-fire
+func TestReplaySuppressesEffectsAndAsyncListeners(t *testing.T) {
+	var sent []string
+	engine := newEffectsEngine(&sent)
+	slow := &notifier{release: make(chan struct{})}
+	close(slow.release)
+	engine.When("order_placed").ThenAsync(slow)
+
+	require.NoError(t, engine.Replay([]Event{OrderPlacedEvent{OrderID: "ORD-1"}}))
+	require.NoError(t, engine.Close())
+	assert.Empty(t, sent)
+	assert.Equal(t, int32(0), slow.sent.Load())
+	assert.Len(t, engine.GetEvents(), 2, "Listeners still derive events")
+}
+
+// TestReplayStopsAtRejection verifies a rejected event ends the replay with its position
+func TestReplayStopsAtRejection(t *testing.T) {
+	engine := newBatchEngine()
+
+	err := engine.Replay([]Event{TokensSpentEvent{Amount: 2}, TokensSpentEvent{Amount: 2}, TokensSpentEvent{Amount: 1}})
+	assert.EqualError(t, err, "replaying event 1 (tokens_spent): rejected by atmos.affordable")
+	assert.Len(t, engine.GetEvents(), 1)
+}
+
+// TestIsReplayingOnOtherEngines verifies foreign engine implementations are never replaying
+func TestIsReplayingOnOtherEngines(t *testing.T) {
+	var engine types.Engine
+	assert.False(t, IsReplaying(engine))
+}
@@ -0,0 +1,49 @@
+package repository
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"sync"
+)
+
+// InMemoryBlobs is a types.BlobStore that keeps blobs in memory, keyed by
+// their SHA-256, so storing the same payload twice stores it once
+type InMemoryBlobs struct {
+	mu    sync.RWMutex
+	blobs map[string][]byte
+}
+
+// NewInMemoryBlobs creates an empty in-memory blob store
+func NewInMemoryBlobs() *InMemoryBlobs {
+	return &InMemoryBlobs{blobs: make(map[string][]byte)}
+}
+
+// Put stores a blob under its content hash
+func (s *InMemoryBlobs) Put(data []byte) (string, error) {
+	sum := sha256.Sum256(data)
+	key := hex.EncodeToString(sum[:])
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.blobs[key] = append([]byte(nil), data...)
+	return key, nil
+}
+
+// Get returns a stored blob
+func (s *InMemoryBlobs) Get(key string) ([]byte, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	data, exists := s.blobs[key]
+	if !exists {
+		return nil, fmt.Errorf("blob %q not found", key)
+	}
+	return append([]byte(nil), data...), nil
+}
+
+// Len returns the number of stored blobs
+func (s *InMemoryBlobs) Len() int {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return len(s.blobs)
+}
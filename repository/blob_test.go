@@ -0,0 +1,27 @@
+package repository_test
+
+import (
+	"testing"
+
+	"github.com/cumulusrpg/atmos/repository"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestInMemoryBlobs_PutGet(t *testing.T) {
+	blobs := repository.NewInMemoryBlobs()
+
+	key, err := blobs.Put([]byte("payload"))
+	require.NoError(t, err)
+	again, err := blobs.Put([]byte("payload"))
+	require.NoError(t, err)
+	assert.Equal(t, key, again, "same content, same key")
+	assert.Equal(t, 1, blobs.Len())
+
+	data, err := blobs.Get(key)
+	require.NoError(t, err)
+	assert.Equal(t, []byte("payload"), data)
+
+	_, err = blobs.Get("missing")
+	assert.Error(t, err)
+}
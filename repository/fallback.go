@@ -0,0 +1,221 @@
+package repository
+
+import (
+	"sync"
+	"time"
+
+	"github.com/cumulusrpg/atmos/types"
+)
+
+// BreakerState is the state of a circuit breaker
+type BreakerState int
+
+const (
+	BreakerClosed   BreakerState = iota // Calls flow normally
+	BreakerOpen                         // Calls are short-circuited until the cooldown passes
+	BreakerHalfOpen                     // One trial call is allowed to probe for recovery
+)
+
+// String returns the state name
+func (s BreakerState) String() string {
+	switch s {
+	case BreakerOpen:
+		return "open"
+	case BreakerHalfOpen:
+		return "half-open"
+	default:
+		return "closed"
+	}
+}
+
+// CircuitBreaker stops calling a failing dependency after a run of
+// consecutive failures, then probes it again after a cooldown. It is safe
+// for concurrent use.
+type CircuitBreaker struct {
+	mu        sync.Mutex
+	threshold int
+	cooldown  time.Duration
+	failures  int
+	openedAt  time.Time
+	state     BreakerState
+	probing   bool // a half-open probe is awaiting its outcome
+	now       func() time.Time
+}
+
+// NewCircuitBreaker opens after threshold consecutive failures and half-opens after cooldown
+func NewCircuitBreaker(threshold int, cooldown time.Duration) *CircuitBreaker {
+	return &CircuitBreaker{
+		threshold: threshold,
+		cooldown:  cooldown,
+		now:       time.Now,
+	}
+}
+
+// Allow reports whether a call should be attempted. While half-open only
+// one caller is allowed, as the probe, until it reports Success or Failure.
+func (b *CircuitBreaker) Allow() bool {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	if b.state == BreakerOpen && b.now().Sub(b.openedAt) >= b.cooldown {
+		b.state = BreakerHalfOpen
+	}
+	switch {
+	case b.state == BreakerOpen:
+		return false
+	case b.state == BreakerHalfOpen:
+		if b.probing {
+			return false
+		}
+		b.probing = true
+	}
+	return true
+}
+
+// Success records a successful call, closing the breaker
+func (b *CircuitBreaker) Success() {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	b.failures = 0
+	b.state = BreakerClosed
+	b.probing = false
+}
+
+// Failure records a failed call, opening the breaker at the threshold
+// (or immediately when a half-open probe fails)
+func (b *CircuitBreaker) Failure() {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	b.failures++
+	b.probing = false
+	if b.state == BreakerHalfOpen || b.failures >= b.threshold {
+		b.state = BreakerOpen
+		b.openedAt = b.now()
+	}
+}
+
+// State returns the current breaker state
+func (b *CircuitBreaker) State() BreakerState {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return b.state
+}
+
+// Fallback is a repository that writes to a primary store and fails over to a
+// secondary store (e.g. a local file) when the primary starts failing.
+// Events written to the secondary are replayed into the primary, in order,
+// once it recovers; the secondary only ever holds events awaiting replay.
+// Reads combine the primary log with any events awaiting replay.
+type Fallback struct {
+	mu        sync.Mutex
+	primary   types.EventRepository
+	secondary types.EventRepository
+	breaker   *CircuitBreaker
+	buffered  []types.Event
+}
+
+// FallbackOption configures fallback construction
+type FallbackOption func(*Fallback)
+
+// WithBreaker sets the circuit breaker guarding the primary
+// (default: open after 3 failures, retry after 30 seconds)
+func WithBreaker(breaker *CircuitBreaker) FallbackOption {
+	return func(f *Fallback) {
+		f.breaker = breaker
+	}
+}
+
+// NewFallback creates a fallback repository. Events left in the secondary by
+// a previous run are picked up for replay.
+func NewFallback(primary, secondary types.EventRepository, opts ...FallbackOption) *Fallback {
+	f := &Fallback{
+		primary:   primary,
+		secondary: secondary,
+		breaker:   NewCircuitBreaker(3, 30*time.Second),
+	}
+
+	// Apply options
+	for _, opt := range opts {
+		opt(f)
+	}
+
+	f.buffered = secondary.GetAll(nil)
+	return f
+}
+
+// Add commits an event to the primary, or to the secondary if the primary is unavailable
+func (f *Fallback) Add(engine types.Engine, event types.Event) error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	if f.breaker.Allow() && f.flush(engine) == nil {
+		if err := f.primary.Add(engine, event); err == nil {
+			f.breaker.Success()
+			return nil
+		}
+		f.breaker.Failure()
+	}
+
+	if err := f.secondary.Add(engine, event); err != nil {
+		return err
+	}
+	f.buffered = append(f.buffered, event)
+	return nil
+}
+
+// GetAll returns the primary log followed by events awaiting replay
+func (f *Fallback) GetAll(engine types.Engine) []types.Event {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	return append(f.primary.GetAll(engine), f.buffered...)
+}
+
+// SetAll replaces the primary log and discards events awaiting replay
+func (f *Fallback) SetAll(engine types.Engine, events []types.Event) error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	if err := f.primary.SetAll(engine, events); err != nil {
+		return err
+	}
+	f.buffered = nil
+	return f.secondary.SetAll(engine, nil)
+}
+
+// Flush replays events awaiting replay into the primary now
+func (f *Fallback) Flush(engine types.Engine) error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	return f.flush(engine)
+}
+
+// flush replays buffered events; the caller must hold f.mu
+func (f *Fallback) flush(engine types.Engine) error {
+	for len(f.buffered) > 0 {
+		if err := f.primary.Add(engine, f.buffered[0]); err != nil {
+			f.breaker.Failure()
+			return err
+		}
+		f.breaker.Success()
+		f.buffered = f.buffered[1:]
+		if err := f.secondary.SetAll(engine, f.buffered); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// Pending returns the number of events awaiting replay into the primary
+func (f *Fallback) Pending() int {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	return len(f.buffered)
+}
+
+// Breaker returns the circuit breaker guarding the primary
+func (f *Fallback) Breaker() *CircuitBreaker {
+	return f.breaker
+}
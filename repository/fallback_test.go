@@ -0,0 +1,107 @@
+package repository_test
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/cumulusrpg/atmos"
+	"github.com/cumulusrpg/atmos/repository"
+	"github.com/cumulusrpg/atmos/types"
+	"github.com/stretchr/testify/assert"
+)
+
+// downRepository wraps an in-memory repository and fails writes while down
+type downRepository struct {
+	*repository.InMemory
+	down  bool
+	calls int
+}
+
+func (r *downRepository) Add(engine types.Engine, event types.Event) error {
+	r.calls++
+	if r.down {
+		return errors.New("primary unavailable")
+	}
+	return r.InMemory.Add(engine, event)
+}
+
+// TestFallbackFailsOverAndReplays verifies events survive a primary outage and are replayed in order
+func TestFallbackFailsOverAndReplays(t *testing.T) {
+	primary := &downRepository{InMemory: repository.NewInMemory()}
+	secondary := repository.NewInMemory()
+	repo := repository.NewFallback(primary, secondary,
+		repository.WithBreaker(repository.NewCircuitBreaker(1, 0)))
+	engine := atmos.NewEngine(atmos.WithRepository(repo))
+
+	assert.True(t, engine.Emit(SimpleEvent{Value: 1}))
+
+	primary.down = true
+	assert.True(t, engine.Emit(SimpleEvent{Value: 2}), "Emit succeeds while the primary is down")
+	assert.True(t, engine.Emit(SimpleEvent{Value: 3}))
+	assert.Equal(t, 2, repo.Pending())
+	assert.Len(t, secondary.GetAll(engine), 2)
+	assert.Len(t, engine.GetEvents(), 3, "Reads include events awaiting replay")
+
+	primary.down = false
+	assert.True(t, engine.Emit(SimpleEvent{Value: 4}))
+	assert.Equal(t, 0, repo.Pending())
+	assert.Empty(t, secondary.GetAll(engine))
+	assert.Equal(t, repository.BreakerClosed, repo.Breaker().State())
+	assert.Equal(t, []types.Event{
+		SimpleEvent{Value: 1}, SimpleEvent{Value: 2}, SimpleEvent{Value: 3}, SimpleEvent{Value: 4},
+	}, primary.InMemory.GetAll(engine))
+}
+
+// TestFallbackBreakerSkipsPrimaryWhileOpen verifies an open breaker stops calls to the primary
+func TestFallbackBreakerSkipsPrimaryWhileOpen(t *testing.T) {
+	primary := &downRepository{InMemory: repository.NewInMemory(), down: true}
+	repo := repository.NewFallback(primary, repository.NewInMemory(),
+		repository.WithBreaker(repository.NewCircuitBreaker(2, time.Hour)))
+	engine := atmos.NewEngine(atmos.WithRepository(repo))
+
+	for i := 0; i < 5; i++ {
+		assert.True(t, engine.Emit(SimpleEvent{Value: i}))
+	}
+
+	assert.Equal(t, 2, primary.calls, "The breaker opened after two failures")
+	assert.Equal(t, repository.BreakerOpen, repo.Breaker().State())
+	assert.Equal(t, 5, repo.Pending())
+
+	primary.down = false
+	assert.NoError(t, repo.Flush(engine))
+	assert.Equal(t, 0, repo.Pending())
+	assert.Len(t, primary.InMemory.GetAll(engine), 5)
+}
+
+// TestFallbackResumesFromSecondary verifies events left in the secondary by a previous run are replayed
+func TestFallbackResumesFromSecondary(t *testing.T) {
+	secondary := repository.NewInMemory()
+	secondary.Add(nil, SimpleEvent{Value: 1})
+
+	primary := repository.NewInMemory()
+	repo := repository.NewFallback(primary, secondary)
+	assert.Equal(t, 1, repo.Pending())
+
+	engine := atmos.NewEngine(atmos.WithRepository(repo))
+	assert.True(t, engine.Emit(SimpleEvent{Value: 2}))
+	assert.Equal(t, []types.Event{SimpleEvent{Value: 1}, SimpleEvent{Value: 2}}, primary.GetAll(engine))
+}
+
+// TestCircuitBreakerAllowsOneProbe verifies only one caller probes a half-open breaker
+func TestCircuitBreakerAllowsOneProbe(t *testing.T) {
+	breaker := repository.NewCircuitBreaker(1, 0)
+	breaker.Failure()
+
+	assert.True(t, breaker.Allow(), "The first caller probes")
+	assert.Equal(t, repository.BreakerHalfOpen, breaker.State())
+	assert.False(t, breaker.Allow(), "Others wait for the probe's outcome")
+
+	breaker.Failure()
+	assert.True(t, breaker.Allow(), "A failed probe reopens, and after the cooldown another may probe")
+	assert.False(t, breaker.Allow())
+
+	breaker.Success()
+	assert.True(t, breaker.Allow())
+	assert.True(t, breaker.Allow(), "A closed breaker allows everyone")
+}
@@ -0,0 +1,82 @@
+package repository
+
+import "github.com/cumulusrpg/atmos/types"
+
+// Identified is an in-memory repository that keeps the ID the engine assigns
+// to each event at commit, with an index for lookups by ID
+type Identified struct {
+	envelopes []types.Envelope
+	byID      map[string]int // ID -> log position
+}
+
+// NewIdentified creates an empty identified repository
+func NewIdentified() *Identified {
+	return &Identified{byID: make(map[string]int)}
+}
+
+// Add commits an event without an ID
+func (r *Identified) Add(engine types.Engine, event types.Event) error {
+	return r.AddIdentified(engine, types.Envelope{Event: event})
+}
+
+// AddIdentified commits an event with its ID
+func (r *Identified) AddIdentified(engine types.Engine, envelope types.Envelope) error {
+	if envelope.ID != "" {
+		r.byID[envelope.ID] = len(r.envelopes)
+	}
+	r.envelopes = append(r.envelopes, envelope)
+	return nil
+}
+
+// GetAll returns all events in commit order
+func (r *Identified) GetAll(engine types.Engine) []types.Event {
+	events := make([]types.Event, len(r.envelopes))
+	for i, envelope := range r.envelopes {
+		events[i] = envelope.Event
+	}
+	return events
+}
+
+// Range visits events in place, without the copy GetAll makes
+func (r *Identified) Range(engine types.Engine, fn func(event types.Event) bool) {
+	for _, envelope := range r.envelopes {
+		if !fn(envelope.Event) {
+			return
+		}
+	}
+}
+
+// Envelopes returns every event with its ID in commit order
+func (r *Identified) Envelopes(engine types.Engine) []types.Envelope {
+	return append([]types.Envelope{}, r.envelopes...)
+}
+
+// EnvelopeByID returns the event committed with an ID
+func (r *Identified) EnvelopeByID(engine types.Engine, id string) (types.Envelope, bool) {
+	pos, exists := r.byID[id]
+	if !exists {
+		return types.Envelope{}, false
+	}
+	return r.envelopes[pos], true
+}
+
+// SetAll replaces all events, leaving them without IDs
+func (r *Identified) SetAll(engine types.Engine, events []types.Event) error {
+	envelopes := make([]types.Envelope, len(events))
+	for i, event := range events {
+		envelopes[i] = types.Envelope{Event: event}
+	}
+	return r.SetEnvelopes(engine, envelopes)
+}
+
+// SetEnvelopes replaces all events, keeping their IDs
+func (r *Identified) SetEnvelopes(engine types.Engine, envelopes []types.Envelope) error {
+	r.envelopes = append([]types.Envelope{}, envelopes...)
+	r.byID = make(map[string]int, len(envelopes))
+	for pos, envelope := range r.envelopes {
+		if envelope.ID != "" {
+			r.byID[envelope.ID] = pos
+		}
+	}
+	return nil
+}
@@ -0,0 +1,37 @@
+package repository_test
+
+import (
+	"testing"
+
+	"github.com/cumulusrpg/atmos"
+	"github.com/cumulusrpg/atmos/repository"
+	"github.com/cumulusrpg/atmos/types"
+	"github.com/stretchr/testify/assert"
+)
+
+// TestIdentified_LooksUpByID verifies IDs are indexed and survive SetEnvelopes but not SetAll
+func TestIdentified_LooksUpByID(t *testing.T) {
+	repo := repository.NewIdentified()
+	engine := atmos.NewEngine(atmos.WithRepository(repo))
+
+	_ = repo.AddIdentified(engine, types.Envelope{ID: "a", Event: SimpleEvent{Value: 1}})
+	_ = repo.Add(engine, SimpleEvent{Value: 2})
+
+	envelope, found := repo.EnvelopeByID(engine, "a")
+	assert.True(t, found)
+	assert.Equal(t, SimpleEvent{Value: 1}, envelope.Event)
+	assert.Equal(t, []types.Event{SimpleEvent{Value: 1}, SimpleEvent{Value: 2}}, repo.GetAll(engine))
+	assert.Equal(t, "", repo.Envelopes(engine)[1].ID, "Plain adds have no ID")
+
+	_ = repo.SetEnvelopes(engine, []types.Envelope{{ID: "b", Event: SimpleEvent{Value: 3}}})
+	_, found = repo.EnvelopeByID(engine, "a")
+	assert.False(t, found)
+	envelope, found = repo.EnvelopeByID(engine, "b")
+	assert.True(t, found)
+	assert.Equal(t, SimpleEvent{Value: 3}, envelope.Event)
+
+	_ = repo.SetAll(engine, []types.Event{SimpleEvent{Value: 4}})
+	_, found = repo.EnvelopeByID(engine, "b")
+	assert.False(t, found)
+	assert.Equal(t, []types.Envelope{{Event: SimpleEvent{Value: 4}}}, repo.Envelopes(engine))
+}
@@ -0,0 +1,91 @@
+package repository
+
+import (
+	"sort"
+
+	"github.com/cumulusrpg/atmos/types"
+)
+
+// Indexed is an in-memory repository that indexes events by type and by
+// aggregate as they are added, for engines with long logs whose states each
+// care about a few event types.
+type Indexed struct {
+	events      []types.Event
+	byType      map[string][]int // event type -> log positions
+	byAggregate map[string][]int // aggregate ID -> log positions
+}
+
+// NewIndexed creates an empty indexed repository
+func NewIndexed() *Indexed {
+	return &Indexed{
+		events:      make([]types.Event, 0),
+		byType:      make(map[string][]int),
+		byAggregate: make(map[string][]int),
+	}
+}
+
+// Add commits a new event and indexes it
+func (r *Indexed) Add(engine types.Engine, event types.Event) error {
+	r.index(len(r.events), event)
+	r.events = append(r.events, event)
+	return nil
+}
+
+// GetAll returns all events
+func (r *Indexed) GetAll(engine types.Engine) []types.Event {
+	return append([]types.Event{}, r.events...)
+}
+
+// Range visits events in place, without the copy GetAll makes
+func (r *Indexed) Range(engine types.Engine, fn func(event types.Event) bool) {
+	for _, event := range r.events {
+		if !fn(event) {
+			return
+		}
+	}
+}
+
+// SetAll atomically replaces all events and rebuilds the indexes
+func (r *Indexed) SetAll(engine types.Engine, events []types.Event) error {
+	r.events = append([]types.Event{}, events...)
+	r.byType = make(map[string][]int)
+	r.byAggregate = make(map[string][]int)
+	for i, event := range r.events {
+		r.index(i, event)
+	}
+	return nil
+}
+
+// EventsOfTypes returns the events of the given types in commit order
+func (r *Indexed) EventsOfTypes(engine types.Engine, eventTypes ...string) []types.Event {
+	var positions []int
+	for _, eventType := range eventTypes {
+		positions = append(positions, r.byType[eventType]...)
+	}
+	if len(eventTypes) > 1 {
+		sort.Ints(positions)
+	}
+	return r.at(positions)
+}
+
+// EventsForAggregate returns the events of an aggregate in commit order
+func (r *Indexed) EventsForAggregate(engine types.Engine, aggregateID string) []types.Event {
+	return r.at(r.byAggregate[aggregateID])
+}
+
+// index records an event's position
+func (r *Indexed) index(pos int, event types.Event) {
+	r.byType[event.Type()] = append(r.byType[event.Type()], pos)
+	if aggregate, ok := event.(types.AggregateEvent); ok {
+		r.byAggregate[aggregate.AggregateID()] = append(r.byAggregate[aggregate.AggregateID()], pos)
+	}
+}
+
+// at returns the events at the given positions
+func (r *Indexed) at(positions []int) []types.Event {
+	events := make([]types.Event, len(positions))
+	for i, pos := range positions {
+		events[i] = r.events[pos]
+	}
+	return events
+}
@@ -0,0 +1,73 @@
+package repository_test
+
+import (
+	"testing"
+
+	"github.com/cumulusrpg/atmos"
+	"github.com/cumulusrpg/atmos/repository"
+	"github.com/cumulusrpg/atmos/types"
+	"github.com/stretchr/testify/assert"
+)
+
+type BidEvent struct {
+	Lot    string
+	Amount int
+}
+
+func (e BidEvent) Type() string        { return "bid" }
+func (e BidEvent) AggregateID() string { return e.Lot }
+
+type ChatEvent struct {
+	Text string
+}
+
+func (e ChatEvent) Type() string { return "chat" }
+
+// TestIndexedRepositoryQueries verifies type and aggregate lookups keep commit order
+func TestIndexedRepositoryQueries(t *testing.T) {
+	repo := repository.NewIndexed()
+	repo.Add(nil, BidEvent{Lot: "vase", Amount: 10})
+	repo.Add(nil, ChatEvent{Text: "hi"})
+	repo.Add(nil, BidEvent{Lot: "lamp", Amount: 5})
+	repo.Add(nil, SimpleEvent{Value: 1})
+	repo.Add(nil, BidEvent{Lot: "vase", Amount: 12})
+
+	assert.Equal(t, []types.Event{
+		BidEvent{Lot: "vase", Amount: 10}, ChatEvent{Text: "hi"}, BidEvent{Lot: "lamp", Amount: 5}, BidEvent{Lot: "vase", Amount: 12},
+	}, repo.EventsOfTypes(nil, "chat", "bid"))
+	assert.Equal(t, []types.Event{
+		BidEvent{Lot: "vase", Amount: 10}, BidEvent{Lot: "vase", Amount: 12},
+	}, repo.EventsForAggregate(nil, "vase"))
+
+	// SetAll rebuilds the indexes
+	repo.SetAll(nil, []types.Event{ChatEvent{Text: "reset"}})
+	assert.Empty(t, repo.EventsOfTypes(nil, "bid"))
+	assert.Empty(t, repo.EventsForAggregate(nil, "vase"))
+}
+
+// TestIndexedRepositoryReplaysOnlyHandledTypes verifies reducers only see the types they handle
+func TestIndexedRepositoryReplaysOnlyHandledTypes(t *testing.T) {
+	engine := atmos.NewEngine(atmos.WithRepository(repository.NewIndexed()))
+	engine.RegisterState("high_bid", 0)
+	engine.When("bid").Updates("high_bid", func(e *atmos.Engine, state interface{}, event atmos.Event) interface{} {
+		if amount := event.(BidEvent).Amount; amount > state.(int) {
+			return amount
+		}
+		return state
+	})
+
+	for i := 0; i < 100; i++ {
+		engine.Emit(ChatEvent{Text: "noise"})
+	}
+	engine.Emit(BidEvent{Lot: "vase", Amount: 30})
+	engine.Emit(BidEvent{Lot: "lamp", Amount: 20})
+
+	assert.Equal(t, 30, engine.GetState("high_bid"))
+	assert.Equal(t, map[string]interface{}{"high_bid": 30}, engine.ProjectAll())
+	assert.Equal(t, []atmos.Event{BidEvent{Lot: "lamp", Amount: 20}}, engine.EventsForAggregate("lamp"))
+
+	// Engines without an index fall back to scanning
+	plain := atmos.NewEngine()
+	plain.SetEvents(engine.GetEvents())
+	assert.Equal(t, engine.EventsForAggregate("vase"), plain.EventsForAggregate("vase"))
+}
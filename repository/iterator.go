@@ -0,0 +1,53 @@
+package repository
+
+import "github.com/cumulusrpg/atmos/types"
+
+// SliceIterator iterates over an in-memory slice of events
+type SliceIterator struct {
+	events []types.Event
+	pos    int
+}
+
+// NewSliceIterator creates an iterator over events
+func NewSliceIterator(events []types.Event) *SliceIterator {
+	return &SliceIterator{events: events, pos: -1}
+}
+
+// Next advances to the next event
+func (it *SliceIterator) Next() bool {
+	if it.pos+1 >= len(it.events) {
+		it.pos = len(it.events)
+		return false
+	}
+	it.pos++
+	return true
+}
+
+// Event returns the current event
+func (it *SliceIterator) Event() types.Event {
+	return it.events[it.pos]
+}
+
+// Err always returns nil
+func (it *SliceIterator) Err() error {
+	return nil
+}
+
+// Close is a no-op
+func (it *SliceIterator) Close() error {
+	return nil
+}
+
+// window clamps [from, to) to a log of length n (to < 0 means n)
+func window(from, to, n int) (int, int) {
+	if to < 0 || to > n {
+		to = n
+	}
+	if from < 0 {
+		from = 0
+	}
+	if from > to {
+		from = to
+	}
+	return from, to
+}
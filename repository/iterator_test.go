@@ -0,0 +1,31 @@
+package repository_test
+
+import (
+	"testing"
+
+	"github.com/cumulusrpg/atmos/repository"
+	"github.com/cumulusrpg/atmos/types"
+	"github.com/stretchr/testify/assert"
+)
+
+// collect drains an iterator
+func collect(it types.EventIterator) []types.Event {
+	defer it.Close()
+	var events []types.Event
+	for it.Next() {
+		events = append(events, it.Event())
+	}
+	return events
+}
+
+// TestInMemoryStream verifies ranges are clamped to the log
+func TestInMemoryStream(t *testing.T) {
+	repo := repository.NewInMemory()
+	for i := 0; i < 5; i++ {
+		repo.Add(nil, SimpleEvent{Value: i})
+	}
+
+	assert.Equal(t, []types.Event{SimpleEvent{Value: 1}, SimpleEvent{Value: 2}}, collect(repo.Stream(1, 3)))
+	assert.Len(t, collect(repo.Stream(3, -1)), 2)
+	assert.Empty(t, collect(repo.Stream(10, 20)))
+}
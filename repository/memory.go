@@ -26,6 +26,21 @@ func (r *InMemory) GetAll(engine types.Engine) []types.Event {
 	return append([]types.Event{}, r.events...)
 }
 
+// Range visits events in place, without the copy GetAll makes
+func (r *InMemory) Range(engine types.Engine, fn func(event types.Event) bool) {
+	for _, event := range r.events {
+		if !fn(event) {
+			return
+		}
+	}
+}
+
+// Stream iterates events in [from, to) without copying them
+func (r *InMemory) Stream(from, to int) types.EventIterator {
+	from, to = window(from, to, len(r.events))
+	return NewSliceIterator(r.events[from:to])
+}
+
 // SetAll atomically replaces all events in the in-memory store
 func (r *InMemory) SetAll(engine types.Engine, events []types.Event) error {
 	r.events = append([]types.Event{}, events...)
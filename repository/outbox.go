@@ -0,0 +1,116 @@
+package repository
+
+import (
+	"sync"
+
+	"github.com/cumulusrpg/atmos/types"
+)
+
+// InMemoryOutbox implements both EventRepository and OutboxRepository.
+// Every Add enqueues an outbox entry alongside the event. It is safe for
+// concurrent use so a relay can drain the outbox in the background.
+type InMemoryOutbox struct {
+	mu     sync.Mutex
+	events []types.Event
+	outbox []types.OutboxEntry
+	nextID uint64
+}
+
+// NewInMemoryOutbox creates a new in-memory repository with an outbox
+func NewInMemoryOutbox() *InMemoryOutbox {
+	return &InMemoryOutbox{
+		events: make([]types.Event, 0),
+		nextID: 1,
+	}
+}
+
+// =============================================================================
+// EventRepository implementation
+// =============================================================================
+
+// Add commits a new event and its outbox entry to the in-memory store
+func (r *InMemoryOutbox) Add(engine types.Engine, event types.Event) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.events = append(r.events, event)
+	r.outbox = append(r.outbox, types.OutboxEntry{ID: r.nextID, Event: event})
+	r.nextID++
+	return nil
+}
+
+// GetAll returns all events from the in-memory store
+func (r *InMemoryOutbox) GetAll(engine types.Engine) []types.Event {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	return append([]types.Event{}, r.events...)
+}
+
+// Range visits events without copying them. The lock is only held to read the
+// slice header (appends never move committed events), so fn may call back into
+// the repository.
+func (r *InMemoryOutbox) Range(engine types.Engine, fn func(event types.Event) bool) {
+	r.mu.Lock()
+	events := r.events
+	r.mu.Unlock()
+
+	for _, event := range events {
+		if !fn(event) {
+			return
+		}
+	}
+}
+
+// SetAll atomically replaces all events in the in-memory store without enqueuing them
+func (r *InMemoryOutbox) SetAll(engine types.Engine, events []types.Event) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.events = append([]types.Event{}, events...)
+	return nil
+}
+
+// =============================================================================
+// OutboxRepository implementation
+// =============================================================================
+
+// PendingOutbox returns up to limit unpublished entries in commit order
+func (r *InMemoryOutbox) PendingOutbox(limit int) ([]types.OutboxEntry, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if limit <= 0 || limit > len(r.outbox) {
+		limit = len(r.outbox)
+	}
+	return append([]types.OutboxEntry{}, r.outbox[:limit]...), nil
+}
+
+// MarkPublished removes an entry from the outbox
+func (r *InMemoryOutbox) MarkPublished(id uint64) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	for i, entry := range r.outbox {
+		if entry.ID == id {
+			r.outbox = append(r.outbox[:i], r.outbox[i+1:]...)
+			return nil
+		}
+	}
+	return nil
+}
+
+// MarkFailed records a failed publication attempt for an entry
+func (r *InMemoryOutbox) MarkFailed(id uint64, err error) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	for i := range r.outbox {
+		if r.outbox[i].ID == id {
+			r.outbox[i].Attempts++
+			r.outbox[i].LastErr = err.Error()
+			return nil
+		}
+	}
+	return nil
+}
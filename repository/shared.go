@@ -0,0 +1,95 @@
+package repository
+
+import (
+	"sync"
+
+	"github.com/cumulusrpg/atmos/types"
+)
+
+// Shared is an in-memory log shared by several engines, each writing
+// through its own Writer. Writers notify each other of commits (see
+// types.ChangeNotifier), as a database with change notifications would, so
+// it suits tests of multi-writer setups. It is safe for concurrent use.
+type Shared struct {
+	mu       sync.RWMutex
+	events   []types.Event
+	watchers map[int]sharedWatcher // watch ID -> watcher
+	nextID   int
+}
+
+// sharedWatcher is a change subscription of one writer
+type sharedWatcher struct {
+	writer   *SharedWriter
+	onChange func()
+}
+
+// NewShared creates an empty shared log
+func NewShared() *Shared {
+	return &Shared{watchers: make(map[int]sharedWatcher)}
+}
+
+// Writer returns a new handle on the log for one engine
+func (s *Shared) Writer() *SharedWriter {
+	return &SharedWriter{shared: s}
+}
+
+// notify tells the watchers of every other writer about a commit
+func (s *Shared) notify(from *SharedWriter) {
+	s.mu.RLock()
+	var calls []func()
+	for _, watcher := range s.watchers {
+		if watcher.writer != from {
+			calls = append(calls, watcher.onChange)
+		}
+	}
+	s.mu.RUnlock()
+
+	for _, call := range calls {
+		call()
+	}
+}
+
+// SharedWriter is one engine's handle on a Shared log
+type SharedWriter struct {
+	shared *Shared
+}
+
+// Add commits an event and notifies the other writers
+func (w *SharedWriter) Add(engine types.Engine, event types.Event) error {
+	w.shared.mu.Lock()
+	w.shared.events = append(w.shared.events, event)
+	w.shared.mu.Unlock()
+	w.shared.notify(w)
+	return nil
+}
+
+// GetAll returns all events, whichever writer committed them
+func (w *SharedWriter) GetAll(engine types.Engine) []types.Event {
+	w.shared.mu.RLock()
+	defer w.shared.mu.RUnlock()
+	return append([]types.Event{}, w.shared.events...)
+}
+
+// SetAll replaces the log and notifies the other writers
+func (w *SharedWriter) SetAll(engine types.Engine, events []types.Event) error {
+	w.shared.mu.Lock()
+	w.shared.events = append([]types.Event{}, events...)
+	w.shared.mu.Unlock()
+	w.shared.notify(w)
+	return nil
+}
+
+// WatchChanges implements types.ChangeNotifier for commits by other writers
+func (w *SharedWriter) WatchChanges(onChange func()) (stop func()) {
+	w.shared.mu.Lock()
+	defer w.shared.mu.Unlock()
+	id := w.shared.nextID
+	w.shared.nextID++
+	w.shared.watchers[id] = sharedWatcher{writer: w, onChange: onChange}
+
+	return func() {
+		w.shared.mu.Lock()
+		defer w.shared.mu.Unlock()
+		delete(w.shared.watchers, id)
+	}
+}
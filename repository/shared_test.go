@@ -0,0 +1,28 @@
+package repository_test
+
+import (
+	"testing"
+
+	"github.com/cumulusrpg/atmos/repository"
+	"github.com/stretchr/testify/assert"
+)
+
+// TestSharedNotifiesOtherWriters verifies writers see each other's commits and aren't told about their own
+func TestSharedNotifiesOtherWriters(t *testing.T) {
+	shared := repository.NewShared()
+	alice, bob := shared.Writer(), shared.Writer()
+	aliceNotified, bobNotified := 0, 0
+	alice.WatchChanges(func() { aliceNotified++ })
+	stop := bob.WatchChanges(func() { bobNotified++ })
+
+	assert.NoError(t, alice.Add(nil, SimpleEvent{Value: 1}))
+	assert.NoError(t, bob.Add(nil, SimpleEvent{Value: 2}))
+	assert.Equal(t, 1, aliceNotified)
+	assert.Equal(t, 1, bobNotified)
+	assert.Len(t, bob.GetAll(nil), 2)
+
+	stop()
+	assert.NoError(t, alice.SetAll(nil, nil))
+	assert.Equal(t, 1, bobNotified)
+	assert.Empty(t, bob.GetAll(nil))
+}
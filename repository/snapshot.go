@@ -33,6 +33,15 @@ func (r *InMemorySnapshot) GetAll(engine types.Engine) []types.Event {
 	return append([]types.Event{}, r.events...)
 }
 
+// Range visits events in place, without the copy GetAll makes
+func (r *InMemorySnapshot) Range(engine types.Engine, fn func(event types.Event) bool) {
+	for _, event := range r.events {
+		if !fn(event) {
+			return
+		}
+	}
+}
+
 // SetAll atomically replaces all events in the in-memory store
 func (r *InMemorySnapshot) SetAll(engine types.Engine, events []types.Event) error {
 	r.events = append([]types.Event{}, events...)
@@ -0,0 +1,123 @@
+package repository
+
+import (
+	"errors"
+
+	"github.com/cumulusrpg/atmos/types"
+)
+
+// ErrTxDone is returned when using a transaction that was already committed or rolled back
+var ErrTxDone = errors.New("transaction already committed or rolled back")
+
+// memoryTx buffers appends and hands them to commit in one call
+type memoryTx struct {
+	pending []types.Event
+	commit  func(events []types.Event)
+	done    bool
+}
+
+// Append implements types.Tx
+func (t *memoryTx) Append(event types.Event) error {
+	if t.done {
+		return ErrTxDone
+	}
+	t.pending = append(t.pending, event)
+	return nil
+}
+
+// Commit implements types.Tx
+func (t *memoryTx) Commit() error {
+	if t.done {
+		return ErrTxDone
+	}
+	t.done = true
+	t.commit(t.pending)
+	return nil
+}
+
+// Rollback implements types.Tx
+func (t *memoryTx) Rollback() error {
+	if t.done {
+		return ErrTxDone
+	}
+	t.done = true
+	t.pending = nil
+	return nil
+}
+
+// identifiedTx buffers appends with their IDs and hands them to commit in one call
+type identifiedTx struct {
+	pending []types.Envelope
+	commit  func(envelopes []types.Envelope)
+	done    bool
+}
+
+// Append implements types.Tx, for an event without an ID
+func (t *identifiedTx) Append(event types.Event) error {
+	return t.AppendIdentified(types.Envelope{Event: event})
+}
+
+// AppendIdentified implements types.IdentifiedTx
+func (t *identifiedTx) AppendIdentified(envelope types.Envelope) error {
+	if t.done {
+		return ErrTxDone
+	}
+	t.pending = append(t.pending, envelope)
+	return nil
+}
+
+// Commit implements types.Tx
+func (t *identifiedTx) Commit() error {
+	if t.done {
+		return ErrTxDone
+	}
+	t.done = true
+	t.commit(t.pending)
+	return nil
+}
+
+// Rollback implements types.Tx
+func (t *identifiedTx) Rollback() error {
+	if t.done {
+		return ErrTxDone
+	}
+	t.done = true
+	t.pending = nil
+	return nil
+}
+
+// Begin starts a batch of appends
+func (r *InMemory) Begin(engine types.Engine) (types.Tx, error) {
+	return &memoryTx{commit: func(events []types.Event) {
+		r.events = append(r.events, events...)
+	}}, nil
+}
+
+// Begin starts a batch of appends
+func (r *InMemorySnapshot) Begin(engine types.Engine) (types.Tx, error) {
+	return &memoryTx{commit: func(events []types.Event) {
+		r.events = append(r.events, events...)
+	}}, nil
+}
+
+// Begin starts a batch of appends; their outbox entries are enqueued with them
+func (r *InMemoryOutbox) Begin(engine types.Engine) (types.Tx, error) {
+	return &memoryTx{commit: func(events []types.Event) {
+		r.mu.Lock()
+		defer r.mu.Unlock()
+		for _, event := range events {
+			r.events = append(r.events, event)
+			r.outbox = append(r.outbox, types.OutboxEntry{ID: r.nextID, Event: event})
+			r.nextID++
+		}
+	}}, nil
+}
+
+// Begin starts a batch of appends that keep their IDs
+func (r *Identified) Begin(engine types.Engine) (types.Tx, error) {
+	return &identifiedTx{commit: func(envelopes []types.Envelope) {
+		for _, envelope := range envelopes {
+			_ = r.AddIdentified(engine, envelope) // in memory, can't fail
+		}
+	}}, nil
+}
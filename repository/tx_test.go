@@ -0,0 +1,75 @@
+package repository_test
+
+import (
+	"testing"
+
+	"github.com/cumulusrpg/atmos/repository"
+	"github.com/cumulusrpg/atmos/types"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// TestTxCommitsAtomically verifies appended events only appear on commit
+func TestTxCommitsAtomically(t *testing.T) {
+	repo := repository.NewInMemory()
+	tx, err := repo.Begin(nil)
+	require.NoError(t, err)
+
+	require.NoError(t, tx.Append(SimpleEvent{Value: 1}))
+	require.NoError(t, tx.Append(SimpleEvent{Value: 2}))
+	assert.Empty(t, repo.GetAll(nil), "Nothing is visible before commit")
+
+	require.NoError(t, tx.Commit())
+	assert.Equal(t, []types.Event{SimpleEvent{Value: 1}, SimpleEvent{Value: 2}}, repo.GetAll(nil))
+	assert.ErrorIs(t, tx.Append(SimpleEvent{Value: 3}), repository.ErrTxDone)
+	assert.ErrorIs(t, tx.Rollback(), repository.ErrTxDone)
+}
+
+// TestTxRollbackDiscards verifies a rolled back batch leaves the log untouched
+func TestTxRollbackDiscards(t *testing.T) {
+	repo := repository.NewInMemorySnapshot()
+	tx, err := repo.Begin(nil)
+	require.NoError(t, err)
+
+	require.NoError(t, tx.Append(SimpleEvent{Value: 1}))
+	require.NoError(t, tx.Rollback())
+	assert.ErrorIs(t, tx.Commit(), repository.ErrTxDone)
+	assert.Empty(t, repo.GetAll(nil))
+}
+
+// TestOutboxTxEnqueuesEntries verifies committed batches reach the outbox in order
+func TestOutboxTxEnqueuesEntries(t *testing.T) {
+	repo := repository.NewInMemoryOutbox()
+	tx, err := repo.Begin(nil)
+	require.NoError(t, err)
+	require.NoError(t, tx.Append(SimpleEvent{Value: 1}))
+	require.NoError(t, tx.Append(SimpleEvent{Value: 2}))
+	require.NoError(t, tx.Commit())
+
+	pending, err := repo.PendingOutbox(0)
+	require.NoError(t, err)
+	require.Len(t, pending, 2)
+	assert.Equal(t, uint64(1), pending[0].ID)
+	assert.Equal(t, SimpleEvent{Value: 2}, pending[1].Event)
+}
+
+// TestIdentifiedTxKeepsIDs verifies identified batches commit with their IDs
+func TestIdentifiedTxKeepsIDs(t *testing.T) {
+	repo := repository.NewIdentified()
+	tx, err := repo.Begin(nil)
+	require.NoError(t, err)
+
+	identified := tx.(types.IdentifiedTx)
+	require.NoError(t, identified.AppendIdentified(types.Envelope{ID: "a", Event: SimpleEvent{Value: 1}}))
+	require.NoError(t, tx.Append(SimpleEvent{Value: 2}))
+	assert.Empty(t, repo.Envelopes(nil), "Nothing is visible before commit")
+
+	require.NoError(t, tx.Commit())
+	assert.Equal(t, []types.Envelope{
+		{ID: "a", Event: SimpleEvent{Value: 1}},
+		{Event: SimpleEvent{Value: 2}},
+	}, repo.Envelopes(nil))
+	envelope, found := repo.EnvelopeByID(nil, "a")
+	assert.True(t, found)
+	assert.Equal(t, SimpleEvent{Value: 1}, envelope.Event)
+}
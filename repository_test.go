@@ -4,6 +4,7 @@ import (
 	"errors"
 	"testing"
 
+	"github.com/cumulusrpg/atmos/repository"
 	"github.com/cumulusrpg/atmos/types"
 )
 
@@ -168,3 +169,115 @@ func TestInMemoryRepositoryDefault(t *testing.T) {
 		t.Errorf("Expected 1 event, got %d", len(events))
 	}
 }
+
+// TestReplayDoesNotCopyRangeableLogs verifies GetState iterates in place when
+// the repository implements EventRanger, so allocations don't grow with the log
+func TestReplayDoesNotCopyRangeableLogs(t *testing.T) {
+	allocsFor := func(events int) float64 {
+		engine := NewEngine()
+		engine.RegisterState("unrelated", 0)
+		engine.When("other_event").Updates("unrelated", func(e *Engine, state interface{}, event Event) interface{} {
+			return state
+		})
+		for i := 0; i < events; i++ {
+			engine.Emit(TestEvent{Name: "event"})
+		}
+		return testing.AllocsPerRun(20, func() { engine.GetState("unrelated") })
+	}
+
+	if small, large := allocsFor(10), allocsFor(10000); large > small {
+		t.Errorf("Expected replay allocations independent of log size, got %v for 10 events and %v for 10000", small, large)
+	}
+}
+
+// pagedRepository only serves replays through Stream, like a database cursor
+type pagedRepository struct {
+	CustomRepository
+	getAllCalls int
+	streams     [][2]int
+}
+
+func (r *pagedRepository) GetAll(engine types.Engine) []types.Event {
+	r.getAllCalls++
+	return r.CustomRepository.GetAll(engine)
+}
+
+func (r *pagedRepository) Stream(from, to int) types.EventIterator {
+	r.streams = append(r.streams, [2]int{from, to})
+	if to > len(r.events) {
+		to = len(r.events)
+	}
+	if from > to {
+		from = to
+	}
+	return repository.NewSliceIterator(r.events[from:to])
+}
+
+// TestReplayStreamsInChunks verifies GetState pages through streaming repositories
+func TestReplayStreamsInChunks(t *testing.T) {
+	repo := &pagedRepository{}
+	engine := NewEngine(WithRepository(repo))
+	engine.RegisterState("count", 0)
+	engine.When("test_event").Updates("count", func(e *Engine, state interface{}, event Event) interface{} {
+		return state.(int) + 1
+	})
+
+	events := make([]Event, 2500)
+	for i := range events {
+		events[i] = TestEvent{Name: "event"}
+	}
+	engine.SetEvents(events)
+
+	if count := engine.GetState("count"); count != 2500 {
+		t.Errorf("Expected count 2500, got %v", count)
+	}
+	if repo.getAllCalls != 0 {
+		t.Errorf("Expected no GetAll calls during replay, got %d", repo.getAllCalls)
+	}
+	if len(repo.streams) != 3 || repo.streams[2] != [2]int{2000, 3000} {
+		t.Errorf("Expected three 1000-event chunks, got %v", repo.streams)
+	}
+}
+
+// failingIterator serves events, then fails
+type failingIterator struct {
+	types.EventIterator
+}
+
+func (it failingIterator) Err() error {
+	return errors.New("cursor lost")
+}
+
+// brokenStreamRepository's streams fail after the first chunk's first few events
+type brokenStreamRepository struct {
+	pagedRepository
+}
+
+func (r *brokenStreamRepository) Stream(from, to int) types.EventIterator {
+	r.streams = append(r.streams, [2]int{from, to})
+	return failingIterator{repository.NewSliceIterator(r.events[from : from+3])}
+}
+
+// TestReplayFallsBackWhenStreamFails verifies a stream error finishes the replay from GetAll instead of cutting it short
+func TestReplayFallsBackWhenStreamFails(t *testing.T) {
+	repo := &brokenStreamRepository{}
+	engine := NewEngine(WithRepository(repo))
+	engine.RegisterState("count", 0)
+	engine.When("test_event").Updates("count", func(e *Engine, state interface{}, event Event) interface{} {
+		return state.(int) + 1
+	})
+
+	events := make([]Event, 10)
+	for i := range events {
+		events[i] = TestEvent{Name: "event"}
+	}
+	engine.SetEvents(events)
+	repo.streams, repo.getAllCalls = nil, 0
+
+	if count := engine.GetState("count"); count != 10 {
+		t.Errorf("Expected count 10, got %v", count)
+	}
+	if len(repo.streams) != 1 || repo.getAllCalls != 1 {
+		t.Errorf("Expected one failed stream then a full read, got streams %v and %d GetAll calls", repo.streams, repo.getAllCalls)
+	}
+}
@@ -0,0 +1,181 @@
+package atmos
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"maps"
+	"slices"
+	"strings"
+)
+
+// RulebookOption configures a generated rulebook
+type RulebookOption func(*rulebook)
+
+// RulebookTitle sets the rulebook's heading (default "Rulebook")
+func RulebookTitle(title string) RulebookOption {
+	return func(r *rulebook) {
+		r.title = title
+	}
+}
+
+// RulebookIntro sets a paragraph written under the heading
+func RulebookIntro(intro string) RulebookOption {
+	return func(r *rulebook) {
+		r.intro = intro
+	}
+}
+
+// rulebook holds the settings of a generated rulebook
+type rulebook struct {
+	title string
+	intro string
+}
+
+// WriteRulebook writes a Markdown rulebook generated from the registrations
+// (see Describe): per event type, its preconditions with their exceptions,
+// warnings, the states it changes, the events it leads to, and what reacts
+// to it. Policies (see Policy) and exception reasons read best; other
+// validators and listeners appear by their Go type.
+func (e *Engine) WriteRulebook(w io.Writer, opts ...RulebookOption) error {
+	r := &rulebook{title: "Rulebook"}
+
+	// Apply options
+	for _, opt := range opts {
+		opt(r)
+	}
+
+	descriptions := e.Describe()
+	emittedBy := make(map[string][]string)
+	for _, d := range descriptions {
+		for _, eventType := range d.Emits {
+			emittedBy[eventType] = append(emittedBy[eventType], d.EventType)
+		}
+	}
+
+	var b bytes.Buffer
+	fmt.Fprintf(&b, "# %s\n", r.title)
+	if r.intro != "" {
+		fmt.Fprintf(&b, "\n%s\n", r.intro)
+	}
+	fmt.Fprintln(&b)
+	for _, d := range descriptions {
+		fmt.Fprintf(&b, "- %s\n", eventLink(d.EventType))
+	}
+
+	for _, d := range descriptions {
+		e.writeRules(&b, d, emittedBy[d.EventType])
+	}
+
+	_, err := w.Write(b.Bytes())
+	return err
+}
+
+// Rulebook returns the Markdown rulebook (see WriteRulebook)
+func (e *Engine) Rulebook(opts ...RulebookOption) string {
+	var b strings.Builder
+	e.WriteRulebook(&b, opts...)
+	return b.String()
+}
+
+// writeRules writes the rulebook section for one event type
+func (e *Engine) writeRules(b *bytes.Buffer, d EventDescription, emittedBy []string) {
+	fmt.Fprintf(b, "\n## %s\n", eventHeading(d.EventType))
+	if d.EventType == AnyEvent {
+		fmt.Fprintln(b, "\nThese rules apply to every event.")
+	}
+	if len(emittedBy) > 0 {
+		fmt.Fprintf(b, "\nFollows from %s.\n", eventLinks(emittedBy))
+	}
+
+	if len(d.Validators) > 0 || len(d.Exceptions) > 0 {
+		fmt.Fprintln(b, "\n### Preconditions")
+		fmt.Fprintln(b)
+		excepted := make(map[string][]string)
+		for _, exception := range e.exceptions[d.EventType] {
+			name := validatorName(exception.Validator)
+			excepted[name] = append(excepted[name], exception.Reason)
+		}
+		for _, name := range d.Validators {
+			fmt.Fprintf(b, "- %s\n", name)
+			for _, reason := range excepted[name] {
+				fmt.Fprintf(b, "  - Except: %s\n", reason)
+			}
+			delete(excepted, name)
+		}
+		// Exceptions to validators registered elsewhere, e.g. for every event
+		for _, name := range slices.Sorted(maps.Keys(excepted)) {
+			for _, reason := range excepted[name] {
+				fmt.Fprintf(b, "- Except from %s: %s\n", name, reason)
+			}
+		}
+	}
+	writeRuleList(b, "Warnings", d.Advisories, plain)
+
+	var effects []string
+	for _, name := range d.States {
+		effects = append(effects, "Updates `"+name+"`")
+	}
+	writeRuleList(b, "Effects", effects, plain)
+	writeRuleList(b, "Leads to", d.Emits, eventLink)
+
+	var reactions []string
+	for _, name := range d.BeforeHooks {
+		reactions = append(reactions, name+" (before)")
+	}
+	reactions = append(reactions, d.Listeners...)
+	writeRuleList(b, "Reactions", reactions, plain)
+}
+
+// writeRuleList writes a rulebook subsection, if it has any items
+func writeRuleList(b *bytes.Buffer, heading string, items []string, format func(string) string) {
+	if len(items) == 0 {
+		return
+	}
+	fmt.Fprintf(b, "\n### %s\n\n", heading)
+	for _, item := range items {
+		fmt.Fprintf(b, "- %s\n", format(item))
+	}
+}
+
+// plain formats a rulebook item as is
+func plain(s string) string {
+	return s
+}
+
+// eventHeading is the heading text of an event type's section
+func eventHeading(eventType string) string {
+	if eventType == AnyEvent {
+		return "Every event"
+	}
+	return "`" + eventType + "`"
+}
+
+// eventLink links to an event type's section
+func eventLink(eventType string) string {
+	return "[" + eventHeading(eventType) + "](#" + anchor(eventHeading(eventType)) + ")"
+}
+
+// eventLinks links to several sections, as prose
+func eventLinks(eventTypes []string) string {
+	links := make([]string, len(eventTypes))
+	for i, eventType := range eventTypes {
+		links[i] = eventLink(eventType)
+	}
+	return strings.Join(links, ", ")
+}
+
+// anchor derives a heading's anchor the way Markdown renderers do:
+// lowercased, spaces to hyphens, punctuation other than - and _ dropped
+func anchor(heading string) string {
+	var b strings.Builder
+	for _, r := range strings.ToLower(heading) {
+		switch {
+		case r == ' ':
+			b.WriteRune('-')
+		case r == '-' || r == '_' || r >= 'a' && r <= 'z' || r >= '0' && r <= '9' || r > 127:
+			b.WriteRune(r)
+		}
+	}
+	return b.String()
+}
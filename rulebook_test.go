@@ -0,0 +1,77 @@
+package atmos
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// TestRulebookDocumentsWiring verifies the rulebook covers preconditions, exceptions, effects, and derived events
+func TestRulebookDocumentsWiring(t *testing.T) {
+	engine := NewEngine()
+	engine.RegisterState("over", false)
+	engine.RegisterState("moves", 0)
+	engine.ThenAll(&typeRecorder{})
+	engine.When("move", func() Event { return &MoveEvent{} }).
+		Requires(Policy("game_in_progress")).
+		Except(Policy("game_in_progress"), func(e *Engine, event Event) bool { return false }, "the first move starts the game").
+		Warns(gameInProgress{}).
+		Updates("moves", func(e *Engine, state interface{}, event Event) interface{} { return state }).
+		Emits("game_over")
+	engine.When("game_over").
+		Updates("over", func(e *Engine, state interface{}, event Event) interface{} { return true })
+
+	expected := strings.Join([]string{
+		"# Tic-tac-toe",
+		"",
+		"The rules, straight from the wiring.",
+		"",
+		"- [Every event](#every-event)",
+		"- [`game_over`](#game_over)",
+		"- [`move`](#move)",
+		"",
+		"## Every event",
+		"",
+		"These rules apply to every event.",
+		"",
+		"### Reactions",
+		"",
+		"- *atmos.typeRecorder",
+		"",
+		"## `game_over`",
+		"",
+		"Follows from [`move`](#move).",
+		"",
+		"### Effects",
+		"",
+		"- Updates `over`",
+		"",
+		"## `move`",
+		"",
+		"### Preconditions",
+		"",
+		"- policy game_in_progress",
+		"  - Except: the first move starts the game",
+		"",
+		"### Warnings",
+		"",
+		"- atmos.gameInProgress",
+		"",
+		"### Effects",
+		"",
+		"- Updates `moves`",
+		"",
+		"### Leads to",
+		"",
+		"- [`game_over`](#game_over)",
+		"",
+	}, "\n")
+	assert.Equal(t, expected, engine.Rulebook(RulebookTitle("Tic-tac-toe"), RulebookIntro("The rules, straight from the wiring.")))
+}
+
+// TestRulebookAnchors verifies links match the anchors Markdown renderers generate
+func TestRulebookAnchors(t *testing.T) {
+	assert.Equal(t, "every-event", anchor("Every event"))
+	assert.Equal(t, "cardplayed_v2", anchor("`card.played_v2`"))
+}
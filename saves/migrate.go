@@ -0,0 +1,93 @@
+package saves
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+)
+
+var (
+	// ErrNewerVersion is returned when loading a save written by a newer game version
+	ErrNewerVersion = errors.New("save was written by a newer version")
+
+	// ErrNoMigration is returned when no migration upgrades a save's version
+	ErrNoMigration = errors.New("no migration for save version")
+)
+
+// Record is one logged event in its stored form, before it is decoded
+type Record struct {
+	Type string          `json:"type"`
+	Data json.RawMessage `json:"data"`
+}
+
+// Migration upgrades a saved log by one version
+type Migration func(records []Record) ([]Record, error)
+
+// Upcast returns a migration that rewrites the payload of every event of a type
+func Upcast(eventType string, fn func(data json.RawMessage) (json.RawMessage, error)) Migration {
+	return func(records []Record) ([]Record, error) {
+		for i, record := range records {
+			if record.Type != eventType {
+				continue
+			}
+			data, err := fn(record.Data)
+			if err != nil {
+				return nil, fmt.Errorf("upcasting %s at %d: %w", eventType, i, err)
+			}
+			records[i].Data = data
+		}
+		return records, nil
+	}
+}
+
+// RenameEvent returns a migration that renames an event type
+func RenameEvent(from, to string) Migration {
+	return func(records []Record) ([]Record, error) {
+		for i := range records {
+			if records[i].Type == from {
+				records[i].Type = to
+			}
+		}
+		return records, nil
+	}
+}
+
+// Chain returns a migration that applies several migrations in order, for
+// version bumps that need more than one change
+func Chain(migrations ...Migration) Migration {
+	return func(records []Record) ([]Record, error) {
+		var err error
+		for _, migration := range migrations {
+			if records, err = migration(records); err != nil {
+				return nil, err
+			}
+		}
+		return records, nil
+	}
+}
+
+// migrate upgrades a saved log from version to the manager's version
+func (m *Manager) migrate(log json.RawMessage, version int) (json.RawMessage, error) {
+	if version > m.version {
+		return nil, fmt.Errorf("%w: %d (this is %d)", ErrNewerVersion, version, m.version)
+	}
+	if version == m.version {
+		return log, nil
+	}
+
+	var records []Record
+	if err := json.Unmarshal(log, &records); err != nil {
+		return nil, err
+	}
+	for ; version < m.version; version++ {
+		migration, exists := m.migrations[version]
+		if !exists {
+			return nil, fmt.Errorf("%w: %d to %d", ErrNoMigration, version, version+1)
+		}
+		var err error
+		if records, err = migration(records); err != nil {
+			return nil, fmt.Errorf("migrating from version %d: %w", version, err)
+		}
+	}
+	return json.Marshal(records)
+}
@@ -0,0 +1,81 @@
+package saves_test
+
+import (
+	"encoding/json"
+	"errors"
+	"testing"
+
+	"github.com/cumulusrpg/atmos/saves"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// legacySave is a slot written by version 0, when gold was "coins" with an "amt" field
+const legacySave = `{"name":"old","saved_at":"2023-05-01T10:00:00Z","events":2,
+	"log":[{"type":"coins_found","data":{"amt":4}},{"type":"coins_found","data":{"amt":6}}]}`
+
+// renameAmount moves the old amt field to amount
+func renameAmount(data json.RawMessage) (json.RawMessage, error) {
+	var old struct {
+		Amt int `json:"amt"`
+	}
+	if err := json.Unmarshal(data, &old); err != nil {
+		return nil, err
+	}
+	return json.Marshal(GoldFoundEvent{Amount: old.Amt})
+}
+
+// TestLoadMigratesOldSaves verifies migrations run in order for each version bump
+func TestLoadMigratesOldSaves(t *testing.T) {
+	store := saves.NewMemoryStore()
+	require.NoError(t, store.Write("old", []byte(legacySave)))
+
+	engine := newGame()
+	manager := saves.New(engine, store, saves.WithVersion(2),
+		saves.WithMigration(0, saves.RenameEvent("coins_found", "gold_found")),
+		saves.WithMigration(1, saves.Upcast("gold_found", renameAmount)))
+
+	info, err := manager.Load("old")
+	require.NoError(t, err)
+	assert.Equal(t, 0, info.Version)
+	assert.Equal(t, Purse{Gold: 10}, engine.GetState("purse"))
+
+	info, err = manager.Save("old")
+	require.NoError(t, err)
+	assert.Equal(t, 2, info.Version, "Resaving upgrades the slot")
+	_, err = manager.Load("old")
+	require.NoError(t, err)
+	assert.Equal(t, Purse{Gold: 10}, engine.GetState("purse"))
+}
+
+// TestLoadRejectsUnmigratableSaves verifies gaps and newer saves are reported
+func TestLoadRejectsUnmigratableSaves(t *testing.T) {
+	store := saves.NewMemoryStore()
+	require.NoError(t, store.Write("old", []byte(legacySave)))
+
+	engine := newGame()
+	_, err := saves.New(engine, store, saves.WithVersion(2),
+		saves.WithMigration(0, saves.RenameEvent("coins_found", "gold_found"))).Load("old")
+	assert.True(t, errors.Is(err, saves.ErrNoMigration))
+	assert.Contains(t, err.Error(), "1 to 2")
+
+	_, err = saves.New(engine, store, saves.WithVersion(3)).Save("new")
+	require.NoError(t, err)
+	_, err = saves.New(engine, store, saves.WithVersion(2)).Load("new")
+	assert.True(t, errors.Is(err, saves.ErrNewerVersion))
+}
+
+// TestChainAppliesMigrationsInOrder verifies one version bump can combine changes
+func TestChainAppliesMigrationsInOrder(t *testing.T) {
+	migration := saves.Chain(
+		saves.RenameEvent("coins_found", "gold_found"),
+		saves.Upcast("gold_found", renameAmount))
+
+	records, err := migration([]saves.Record{{Type: "coins_found", Data: json.RawMessage(`{"amt":3}`)}})
+	require.NoError(t, err)
+	assert.Equal(t, "gold_found", records[0].Type)
+	assert.JSONEq(t, `{"amount":3}`, string(records[0].Data))
+
+	_, err = migration([]saves.Record{{Type: "coins_found", Data: json.RawMessage(`"bad"`)}})
+	assert.Error(t, err)
+}
@@ -0,0 +1,199 @@
+// Package saves manages named save slots for an engine, so games don't
+// hand-roll slot handling on top of MarshalEvents:
+//
+//	store, _ := saves.NewDirStore(filepath.Join(configDir, "saves"))
+//	manager := saves.New(engine, store)
+//	manager.Save("autosave")
+//	manager.Load("before-boss")
+//
+// A save holds the engine's event log plus any state snapshots its
+// repository keeps (see atmos.Engine.SetSnapshot). Saves are stamped with
+// the game's schema version; older saves are migrated as they load (see
+// WithVersion and WithMigration).
+package saves
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"sort"
+	"time"
+
+	"github.com/cumulusrpg/atmos"
+)
+
+// Info describes a save slot
+type Info struct {
+	Name    string    `json:"name"`
+	SavedAt time.Time `json:"saved_at"`
+	Events  int       `json:"events"`            // length of the saved log
+	Version int       `json:"version,omitempty"` // game schema version the save was written with
+}
+
+// file is the encoded form of a save
+type file struct {
+	Info
+	Log       json.RawMessage            `json:"log"`
+	Snapshots map[string]json.RawMessage `json:"snapshots,omitempty"`
+}
+
+// Manager saves and loads an engine's log in named slots
+type Manager struct {
+	engine     *atmos.Engine
+	store      Store
+	now        func() time.Time
+	version    int
+	migrations map[int]Migration
+}
+
+// Option configures manager construction
+type Option func(*Manager)
+
+// WithClock sets the time source for save timestamps (default time.Now)
+func WithClock(now func() time.Time) Option {
+	return func(m *Manager) {
+		m.now = now
+	}
+}
+
+// WithVersion sets the game schema version written into saves (default 0)
+func WithVersion(version int) Option {
+	return func(m *Manager) {
+		m.version = version
+	}
+}
+
+// WithMigration registers the migration that upgrades saves written at
+// version from to version from+1
+func WithMigration(from int, migration Migration) Option {
+	return func(m *Manager) {
+		m.migrations[from] = migration
+	}
+}
+
+// New creates a save manager for an engine backed by a store
+func New(engine *atmos.Engine, store Store, opts ...Option) *Manager {
+	m := &Manager{
+		engine:     engine,
+		store:      store,
+		now:        time.Now,
+		migrations: make(map[int]Migration),
+	}
+
+	// Apply options
+	for _, opt := range opts {
+		opt(m)
+	}
+
+	return m
+}
+
+// Save writes the engine's current log and snapshots to a slot, replacing
+// any existing save with that name
+func (m *Manager) Save(name string) (Info, error) {
+	events := m.engine.GetEvents()
+	log, err := m.engine.MarshalLog()
+	if err != nil {
+		return Info{}, err
+	}
+
+	save := file{
+		Info: Info{Name: name, SavedAt: m.now().UTC(), Events: len(events), Version: m.version},
+		Log:  log,
+	}
+	for _, state := range m.engine.StateNames() {
+		if snapshot, exists := m.engine.GetSnapshot(state); exists {
+			if save.Snapshots == nil {
+				save.Snapshots = make(map[string]json.RawMessage)
+			}
+			save.Snapshots[state] = snapshot
+		}
+	}
+
+	data, err := json.Marshal(save)
+	if err != nil {
+		return Info{}, err
+	}
+	if err := m.store.Write(name, data); err != nil {
+		return Info{}, err
+	}
+	return save.Info, nil
+}
+
+// Load replaces the engine's log and snapshots with a saved slot,
+// migrating it first if it was written by an older version
+func (m *Manager) Load(name string) (Info, error) {
+	save, err := m.read(name)
+	if err != nil {
+		return Info{}, err
+	}
+
+	log, err := m.migrate(save.Log, save.Version)
+	if err != nil {
+		return Info{}, fmt.Errorf("save %q: %w", name, err)
+	}
+	events, err := m.engine.UnmarshalEvents(log)
+	if err != nil {
+		return Info{}, fmt.Errorf("save %q: %w", name, err)
+	}
+
+	for _, state := range m.engine.StateNames() {
+		if snapshot, exists := save.Snapshots[state]; exists {
+			err = m.engine.SetSnapshot(state, snapshot)
+		} else if m.engine.HasSnapshot(state) {
+			err = m.engine.ClearSnapshot(state)
+		}
+		if err != nil {
+			return Info{}, fmt.Errorf("save %q: %w", name, err)
+		}
+	}
+
+	if err := m.engine.SetEventsE(events); err != nil {
+		return Info{}, fmt.Errorf("save %q: %w", name, err)
+	}
+	return save.Info, nil
+}
+
+// List returns every save, most recent first
+func (m *Manager) List() ([]Info, error) {
+	names, err := m.store.Names()
+	if err != nil {
+		return nil, err
+	}
+
+	infos := make([]Info, 0, len(names))
+	for _, name := range names {
+		save, err := m.read(name)
+		if errors.Is(err, ErrNotFound) {
+			continue // Deleted since Names
+		}
+		if err != nil {
+			return nil, err
+		}
+		infos = append(infos, save.Info)
+	}
+
+	sort.SliceStable(infos, func(i, j int) bool {
+		return infos[i].SavedAt.After(infos[j].SavedAt)
+	})
+	return infos, nil
+}
+
+// Delete removes a save
+func (m *Manager) Delete(name string) error {
+	return m.store.Remove(name)
+}
+
+// read decodes a save from the store
+func (m *Manager) read(name string) (file, error) {
+	data, err := m.store.Read(name)
+	if err != nil {
+		return file{}, err
+	}
+	var save file
+	if err := json.Unmarshal(data, &save); err != nil {
+		return file{}, fmt.Errorf("save %q: %w", name, err)
+	}
+	save.Name = name
+	return save, nil
+}
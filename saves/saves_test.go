@@ -0,0 +1,134 @@
+package saves_test
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/cumulusrpg/atmos"
+	"github.com/cumulusrpg/atmos/repository"
+	"github.com/cumulusrpg/atmos/saves"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// GoldFoundEvent adds gold to the purse
+type GoldFoundEvent struct {
+	Amount int `json:"amount"`
+}
+
+func (e GoldFoundEvent) Type() string { return "gold_found" }
+
+// Purse is the player's wallet
+type Purse struct {
+	Gold int `json:"gold"`
+}
+
+// newGame creates an engine whose purse can be seeded from snapshots
+func newGame() *atmos.Engine {
+	engine := atmos.NewEngine(atmos.WithRepository(repository.NewInMemorySnapshot()))
+	engine.RegisterEventTypes(GoldFoundEvent{})
+	engine.RegisterState("purse", Purse{})
+	engine.When("gold_found").
+		Updates("purse", func(e *atmos.Engine, state interface{}, event atmos.Event) interface{} {
+			purse := state.(Purse)
+			switch found := event.(type) {
+			case GoldFoundEvent:
+				purse.Gold += found.Amount
+			case *GoldFoundEvent:
+				purse.Gold += found.Amount
+			}
+			return purse
+		})
+	return engine
+}
+
+// clock returns a time source that advances a minute per call
+func clock() func() time.Time {
+	now := time.Date(2024, 1, 1, 12, 0, 0, 0, time.UTC)
+	return func() time.Time {
+		now = now.Add(time.Minute)
+		return now
+	}
+}
+
+// TestSaveAndLoad verifies a slot restores the log it was saved with
+func TestSaveAndLoad(t *testing.T) {
+	engine := newGame()
+	manager := saves.New(engine, saves.NewMemoryStore(), saves.WithClock(clock()))
+
+	engine.Emit(GoldFoundEvent{Amount: 10})
+	info, err := manager.Save("slot1")
+	require.NoError(t, err)
+	assert.Equal(t, "slot1", info.Name)
+	assert.Equal(t, 1, info.Events)
+
+	engine.Emit(GoldFoundEvent{Amount: 5})
+	assert.Equal(t, Purse{Gold: 15}, engine.GetState("purse"))
+
+	_, err = manager.Load("slot1")
+	require.NoError(t, err)
+	assert.Equal(t, Purse{Gold: 10}, engine.GetState("purse"))
+	assert.Len(t, engine.GetEvents(), 1)
+
+	_, err = manager.Load("missing")
+	assert.True(t, errors.Is(err, saves.ErrNotFound))
+}
+
+// TestSavesCarrySnapshots verifies snapshots are saved and restored with the log
+func TestSavesCarrySnapshots(t *testing.T) {
+	engine := newGame()
+	manager := saves.New(engine, saves.NewMemoryStore())
+
+	require.NoError(t, engine.SetSnapshot("purse", Purse{Gold: 100}))
+	engine.Emit(GoldFoundEvent{Amount: 1})
+	_, err := manager.Save("seeded")
+	require.NoError(t, err)
+
+	require.NoError(t, engine.ClearSnapshot("purse"))
+	_, err = manager.Load("seeded")
+	require.NoError(t, err)
+	assert.Equal(t, Purse{Gold: 101}, engine.GetState("purse"))
+	assert.True(t, engine.HasSnapshot("purse"))
+}
+
+// TestLoadClearsStaleSnapshots verifies loading a save without snapshots drops the engine's
+func TestLoadClearsStaleSnapshots(t *testing.T) {
+	engine := newGame()
+	manager := saves.New(engine, saves.NewMemoryStore())
+	engine.Emit(GoldFoundEvent{Amount: 3})
+	_, err := manager.Save("plain")
+	require.NoError(t, err)
+
+	require.NoError(t, engine.SetSnapshot("purse", Purse{Gold: 50}))
+	_, err = manager.Load("plain")
+	require.NoError(t, err)
+	assert.False(t, engine.HasSnapshot("purse"))
+	assert.Equal(t, Purse{Gold: 3}, engine.GetState("purse"))
+}
+
+// TestListAndDelete verifies slots are listed newest first and can be deleted
+func TestListAndDelete(t *testing.T) {
+	engine := newGame()
+	manager := saves.New(engine, saves.NewMemoryStore(), saves.WithClock(clock()))
+
+	_, err := manager.Save("b")
+	require.NoError(t, err)
+	engine.Emit(GoldFoundEvent{Amount: 1})
+	_, err = manager.Save("a")
+	require.NoError(t, err)
+
+	infos, err := manager.List()
+	require.NoError(t, err)
+	require.Len(t, infos, 2)
+	assert.Equal(t, "a", infos[0].Name)
+	assert.Equal(t, 1, infos[0].Events)
+	assert.Equal(t, "b", infos[1].Name)
+	assert.True(t, infos[0].SavedAt.After(infos[1].SavedAt))
+
+	require.NoError(t, manager.Delete("a"))
+	assert.True(t, errors.Is(manager.Delete("a"), saves.ErrNotFound))
+	infos, err = manager.List()
+	require.NoError(t, err)
+	assert.Len(t, infos, 1)
+}
@@ -0,0 +1,177 @@
+package saves
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+)
+
+// ErrNotFound is returned when reading or removing a save that doesn't exist
+var ErrNotFound = errors.New("save not found")
+
+// Store holds encoded saves by name
+type Store interface {
+	// Write creates or replaces a save
+	Write(name string, data []byte) error
+
+	// Read returns a save, or ErrNotFound
+	Read(name string) ([]byte, error)
+
+	// Names returns the names of all saves
+	Names() ([]string, error)
+
+	// Remove deletes a save, or returns ErrNotFound
+	Remove(name string) error
+}
+
+// MemoryStore keeps saves in memory, for tests and ephemeral sessions
+type MemoryStore struct {
+	mu    sync.Mutex
+	saves map[string][]byte
+}
+
+// NewMemoryStore creates an empty in-memory store
+func NewMemoryStore() *MemoryStore {
+	return &MemoryStore{saves: make(map[string][]byte)}
+}
+
+// Write implements Store
+func (s *MemoryStore) Write(name string, data []byte) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.saves[name] = append([]byte(nil), data...)
+	return nil
+}
+
+// Read implements Store
+func (s *MemoryStore) Read(name string) ([]byte, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	data, exists := s.saves[name]
+	if !exists {
+		return nil, ErrNotFound
+	}
+	return append([]byte(nil), data...), nil
+}
+
+// Names implements Store
+func (s *MemoryStore) Names() ([]string, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	names := make([]string, 0, len(s.saves))
+	for name := range s.saves {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names, nil
+}
+
+// Remove implements Store
+func (s *MemoryStore) Remove(name string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if _, exists := s.saves[name]; !exists {
+		return ErrNotFound
+	}
+	delete(s.saves, name)
+	return nil
+}
+
+// DirStore keeps each save as a <name>.save file in a directory. Writes go
+// through a temporary file and a rename, so a crash mid-save never leaves a
+// truncated slot behind.
+type DirStore struct {
+	dir string
+}
+
+// saveExt is the file extension of saves in a DirStore
+const saveExt = ".save"
+
+// NewDirStore creates a store in dir, creating the directory if needed
+func NewDirStore(dir string) (*DirStore, error) {
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return nil, err
+	}
+	return &DirStore{dir: dir}, nil
+}
+
+// path returns the file for a save, rejecting names that would escape the directory
+func (s *DirStore) path(name string) (string, error) {
+	if name == "" || name != filepath.Base(name) || strings.HasPrefix(name, ".") {
+		return "", errors.New("invalid save name: " + name)
+	}
+	return filepath.Join(s.dir, name+saveExt), nil
+}
+
+// Write implements Store
+func (s *DirStore) Write(name string, data []byte) error {
+	path, err := s.path(name)
+	if err != nil {
+		return err
+	}
+
+	tmp, err := os.CreateTemp(s.dir, "."+name+"-*")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(tmp.Name()) // No-op once renamed
+
+	if _, err := tmp.Write(data); err != nil {
+		tmp.Close()
+		return err
+	}
+	if err := tmp.Sync(); err != nil {
+		tmp.Close()
+		return err
+	}
+	if err := tmp.Close(); err != nil {
+		return err
+	}
+	return os.Rename(tmp.Name(), path)
+}
+
+// Read implements Store
+func (s *DirStore) Read(name string) ([]byte, error) {
+	path, err := s.path(name)
+	if err != nil {
+		return nil, err
+	}
+	data, err := os.ReadFile(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return nil, ErrNotFound
+	}
+	return data, err
+}
+
+// Names implements Store
+func (s *DirStore) Names() ([]string, error) {
+	entries, err := os.ReadDir(s.dir)
+	if err != nil {
+		return nil, err
+	}
+	var names []string
+	for _, entry := range entries {
+		name := entry.Name()
+		if entry.IsDir() || strings.HasPrefix(name, ".") || !strings.HasSuffix(name, saveExt) {
+			continue
+		}
+		names = append(names, strings.TrimSuffix(name, saveExt))
+	}
+	return names, nil
+}
+
+// Remove implements Store
+func (s *DirStore) Remove(name string) error {
+	path, err := s.path(name)
+	if err != nil {
+		return err
+	}
+	err = os.Remove(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return ErrNotFound
+	}
+	return err
+}
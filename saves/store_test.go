@@ -0,0 +1,64 @@
+package saves_test
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/cumulusrpg/atmos/saves"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// TestDirStore verifies saves persist as files across store instances
+func TestDirStore(t *testing.T) {
+	dir := filepath.Join(t.TempDir(), "saves")
+	store, err := saves.NewDirStore(dir)
+	require.NoError(t, err)
+
+	require.NoError(t, store.Write("slot1", []byte("one")))
+	require.NoError(t, store.Write("slot2", []byte("two")))
+	require.NoError(t, store.Write("slot1", []byte("uno")))
+
+	reopened, err := saves.NewDirStore(dir)
+	require.NoError(t, err)
+	data, err := reopened.Read("slot1")
+	require.NoError(t, err)
+	assert.Equal(t, "uno", string(data))
+
+	names, err := reopened.Names()
+	require.NoError(t, err)
+	assert.Equal(t, []string{"slot1", "slot2"}, names)
+
+	entries, err := os.ReadDir(dir)
+	require.NoError(t, err)
+	assert.Len(t, entries, 2, "No temporary files are left behind")
+
+	require.NoError(t, reopened.Remove("slot2"))
+	_, err = reopened.Read("slot2")
+	assert.True(t, errors.Is(err, saves.ErrNotFound))
+	assert.True(t, errors.Is(reopened.Remove("slot2"), saves.ErrNotFound))
+}
+
+// TestDirStoreRejectsPaths verifies save names can't escape the directory
+func TestDirStoreRejectsPaths(t *testing.T) {
+	store, err := saves.NewDirStore(t.TempDir())
+	require.NoError(t, err)
+
+	for _, name := range []string{"", "../escape", "a/b", ".hidden"} {
+		assert.Error(t, store.Write(name, []byte("x")), name)
+	}
+}
+
+// TestMemoryStoreCopiesData verifies callers can't mutate stored saves
+func TestMemoryStoreCopiesData(t *testing.T) {
+	store := saves.NewMemoryStore()
+	data := []byte("save")
+	require.NoError(t, store.Write("slot", data))
+	data[0] = 'X'
+
+	stored, err := store.Read("slot")
+	require.NoError(t, err)
+	assert.Equal(t, "save", string(stored))
+}
@@ -0,0 +1,246 @@
+// Package scheduler emits periodic tick events (a day passing every game
+// hour, upkeep every round of real time) as real events in the log:
+//
+//	schedule, err := scheduler.New(
+//		scheduler.Every("day_passed", time.Hour),
+//		scheduler.Every("upkeep", 10*time.Minute),
+//		scheduler.WithClock(gameClock),
+//	)
+//	if err != nil { ... }
+//	schedule.Install(engine)
+//	schedule.Start(engine)
+//	go schedule.Run(ctx, engine, lock, time.Second)
+//
+//	engine.When("day_passed").Updates("calendar", AdvanceDay)
+//
+// Start records the anchor time, and the Nth tick of a schedule is always
+// due at anchor + N*interval; its Count and At are part of the event, so
+// ticks don't depend on when Tick happens to run. A validator admits only
+// the next tick of each schedule once it is due by the clock, so after a
+// restart missed ticks are caught up in order and none is ever repeated,
+// and replaying the log reproduces exactly the same ticks.
+package scheduler
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/cumulusrpg/atmos"
+	"github.com/cumulusrpg/atmos/types"
+)
+
+// StartedEventType is the type of StartedEvent
+const StartedEventType = "scheduler_started"
+
+// StartedEvent anchors every schedule's ticks
+type StartedEvent struct {
+	At time.Time `json:"at"`
+}
+
+// Type implements atmos.Event
+func (e StartedEvent) Type() string { return StartedEventType }
+
+// TickEvent is one tick of a schedule; its type is the schedule's name
+type TickEvent struct {
+	Name  string    `json:"name"`
+	Count int       `json:"count"` // 1 for the first tick
+	At    time.Time `json:"at"`    // when the tick was due, not when it was emitted
+}
+
+// Type implements atmos.Event
+func (e TickEvent) Type() string { return e.Name }
+
+// State records the anchor and the ticks emitted so far. Each tick copies
+// Counts, so a State read earlier doesn't change under its reader.
+type State struct {
+	Started time.Time      `json:"started"`
+	Counts  map[string]int `json:"counts"` // schedule name -> ticks emitted
+}
+
+// Scheduler emits tick events on an engine
+type Scheduler struct {
+	name      string
+	now       func() time.Time
+	intervals map[string]time.Duration // schedule name -> interval
+}
+
+// Option configures scheduler construction
+type Option func(*Scheduler)
+
+// Every adds a schedule emitting TickEvents of type name every interval,
+// which must be positive
+func Every(name string, interval time.Duration) Option {
+	return func(s *Scheduler) {
+		s.intervals[name] = interval
+	}
+}
+
+// WithClock sets the time source (for tests, simulations, and game time)
+func WithClock(now func() time.Time) Option {
+	return func(s *Scheduler) {
+		s.now = now
+	}
+}
+
+// WithStateName sets the name of the scheduler state (default "scheduler")
+func WithStateName(name string) Option {
+	return func(s *Scheduler) {
+		s.name = name
+	}
+}
+
+// New creates a scheduler, or returns atmos.ErrInvalidInterval if a
+// schedule's interval isn't positive
+func New(opts ...Option) (*Scheduler, error) {
+	s := &Scheduler{
+		name:      "scheduler",
+		now:       time.Now,
+		intervals: make(map[string]time.Duration),
+	}
+
+	// Apply options
+	for _, opt := range opts {
+		opt(s)
+	}
+
+	for _, name := range s.names() {
+		if s.intervals[name] <= 0 {
+			return nil, fmt.Errorf("%w: %s every %s", atmos.ErrInvalidInterval, name, s.intervals[name])
+		}
+	}
+	return s, nil
+}
+
+// Install registers the scheduler's events, state, and validator
+func (s *Scheduler) Install(engine *atmos.Engine) {
+	engine.RegisterState(s.name, State{Counts: map[string]int{}})
+	engine.When(StartedEventType, func() atmos.Event { return &StartedEvent{} }).
+		Requires(&tickValidator{scheduler: s}).
+		Updates(s.name, s.reduce)
+
+	for _, name := range s.names() {
+		engine.When(name, func() atmos.Event { return &TickEvent{Name: name} }).
+			Requires(&tickValidator{scheduler: s}).
+			Updates(s.name, s.reduce)
+	}
+}
+
+// Start anchors the schedules at the current time; it is rejected once started
+func (s *Scheduler) Start(engine *atmos.Engine) bool {
+	return engine.Emit(StartedEvent{At: s.now()})
+}
+
+// State returns the anchor and tick counts
+func (s *Scheduler) State(engine *atmos.Engine) State {
+	return engine.GetState(s.name).(State)
+}
+
+// Next returns the next tick due, whether or not it is due yet
+func (s *Scheduler) Next(engine *atmos.Engine) (TickEvent, bool) {
+	state := s.State(engine)
+	if state.Started.IsZero() || len(s.intervals) == 0 {
+		return TickEvent{}, false
+	}
+
+	var next TickEvent
+	for _, name := range s.names() {
+		candidate := s.tick(state, name)
+		if next.Name == "" || candidate.At.Before(next.At) {
+			next = candidate
+		}
+	}
+	return next, true
+}
+
+// Tick emits every tick that is due, in time order (schedules due at the
+// same instant in name order), returning the ticks emitted
+func (s *Scheduler) Tick(engine *atmos.Engine) []TickEvent {
+	now := s.now()
+	var emitted []TickEvent
+	for {
+		next, ok := s.Next(engine)
+		if !ok || next.At.After(now) || !engine.Emit(next) {
+			return emitted
+		}
+		emitted = append(emitted, next)
+	}
+}
+
+// Run calls Tick every interval until ctx is done, holding locker around
+// each tick so the engine can be shared with request handlers
+func (s *Scheduler) Run(ctx context.Context, engine *atmos.Engine, locker sync.Locker, interval time.Duration) error {
+	return atmos.RunTicks(ctx, locker, interval, func() { s.Tick(engine) })
+}
+
+// tick returns a schedule's next tick given the state
+func (s *Scheduler) tick(state State, name string) TickEvent {
+	count := state.Counts[name] + 1
+	return TickEvent{Name: name, Count: count, At: state.Started.Add(time.Duration(count) * s.intervals[name])}
+}
+
+// names returns the schedule names, sorted
+func (s *Scheduler) names() []string {
+	names := make([]string, 0, len(s.intervals))
+	for name := range s.intervals {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// reduce records the anchor and counts ticks
+func (s *Scheduler) reduce(engine *atmos.Engine, state interface{}, event atmos.Event) interface{} {
+	current := state.(State)
+	next := State{Started: current.Started, Counts: make(map[string]int, len(current.Counts)+1)}
+	for name, count := range current.Counts {
+		next.Counts[name] = count
+	}
+
+	switch e := atmos.Deref(event).(type) {
+	case StartedEvent:
+		next.Started = e.At
+	case TickEvent:
+		next.Counts[e.Name] = e.Count
+	}
+	return next
+}
+
+// tickValidator admits one start, and only the next tick of each schedule
+// once it is due
+type tickValidator struct {
+	scheduler *Scheduler
+}
+
+// Validate implements atmos.EventValidator
+func (v *tickValidator) Validate(engine types.Engine, event atmos.Event) bool {
+	return v.Check(engine, event) == nil
+}
+
+// Check implements atmos.ReasonedValidator
+func (v *tickValidator) Check(engine types.Engine, event atmos.Event) error {
+	s := v.scheduler
+	state := s.State(engine.(*atmos.Engine))
+
+	switch e := atmos.Deref(event).(type) {
+	case StartedEvent:
+		if !state.Started.IsZero() {
+			return fmt.Errorf("scheduler already started at %s", state.Started.Format(time.RFC3339))
+		}
+	case TickEvent:
+		if state.Started.IsZero() {
+			return fmt.Errorf("scheduler has not started")
+		}
+		expected := s.tick(state, e.Name)
+		if e.Count != expected.Count || !e.At.Equal(expected.At) {
+			return fmt.Errorf("%s tick %d at %s is not the next tick (%d at %s)", e.Name,
+				e.Count, e.At.Format(time.RFC3339), expected.Count, expected.At.Format(time.RFC3339))
+		}
+		if e.At.After(s.now()) {
+			return fmt.Errorf("%s tick %d is not due until %s", e.Name, e.Count, e.At.Format(time.RFC3339))
+		}
+	}
+	return nil
+}
@@ -0,0 +1,115 @@
+package scheduler_test
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/cumulusrpg/atmos"
+	"github.com/cumulusrpg/atmos/scheduler"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+var noon = time.Date(2024, 1, 1, 12, 0, 0, 0, time.UTC)
+
+// newCalendar ticks days every hour and upkeep every 40 minutes
+func newCalendar(t *testing.T, now *time.Time) (*atmos.Engine, *scheduler.Scheduler) {
+	schedule, err := scheduler.New(
+		scheduler.Every("day_passed", time.Hour),
+		scheduler.Every("upkeep", 40*time.Minute),
+		scheduler.WithClock(func() time.Time { return *now }),
+	)
+	require.NoError(t, err)
+	engine := atmos.NewEngine()
+	schedule.Install(engine)
+	engine.RegisterState("days", 0)
+	engine.When("day_passed").Updates("days", func(e *atmos.Engine, state interface{}, event atmos.Event) interface{} {
+		return state.(int) + 1
+	})
+	return engine, schedule
+}
+
+// ticked renders ticks as "name#count"
+func ticked(ticks []scheduler.TickEvent) []string {
+	var names []string
+	for _, tick := range ticks {
+		names = append(names, fmt.Sprintf("%s#%d", tick.Name, tick.Count))
+	}
+	return names
+}
+
+// TestTicksEmittedWhenDue verifies ticks are emitted in time order, once, and catch up after a gap
+func TestTicksEmittedWhenDue(t *testing.T) {
+	now := noon
+	engine, schedule := newCalendar(t, &now)
+
+	assert.Empty(t, schedule.Tick(engine), "Nothing ticks before Start")
+	require.True(t, schedule.Start(engine))
+	assert.False(t, schedule.Start(engine), "Already started")
+
+	now = noon.Add(59 * time.Minute)
+	assert.Equal(t, []string{"upkeep#1"}, ticked(schedule.Tick(engine)))
+	assert.Empty(t, schedule.Tick(engine))
+
+	now = noon.Add(3 * time.Hour)
+	assert.Equal(t, []string{"day_passed#1", "upkeep#2", "day_passed#2", "upkeep#3", "upkeep#4", "day_passed#3"},
+		ticked(schedule.Tick(engine)))
+	assert.Equal(t, 3, engine.GetState("days"))
+
+	next, ok := schedule.Next(engine)
+	assert.True(t, ok)
+	assert.Equal(t, scheduler.TickEvent{Name: "upkeep", Count: 5, At: noon.Add(200 * time.Minute)}, next)
+}
+
+// TestTicksValidated verifies hand-made ticks must be the next one and due
+func TestTicksValidated(t *testing.T) {
+	now := noon
+	engine, schedule := newCalendar(t, &now)
+	require.True(t, schedule.Start(engine))
+
+	result := engine.EmitWithResult(scheduler.TickEvent{Name: "day_passed", Count: 1, At: noon.Add(time.Hour)})
+	assert.False(t, result.Accepted)
+	assert.Equal(t, "day_passed tick 1 is not due until 2024-01-01T13:00:00Z", result.Reason)
+
+	now = noon.Add(2 * time.Hour)
+	assert.False(t, engine.Emit(scheduler.TickEvent{Name: "day_passed", Count: 2, At: noon.Add(2 * time.Hour)}), "Skips a tick")
+	assert.True(t, engine.Emit(scheduler.TickEvent{Name: "day_passed", Count: 1, At: noon.Add(time.Hour)}))
+}
+
+// TestTicksSurviveReload verifies a restored engine continues from the logged ticks
+func TestTicksSurviveReload(t *testing.T) {
+	now := noon
+	engine, schedule := newCalendar(t, &now)
+	require.True(t, schedule.Start(engine))
+	now = noon.Add(90 * time.Minute)
+	schedule.Tick(engine)
+	data, err := engine.MarshalEvents(engine.GetEvents())
+	require.NoError(t, err)
+
+	restored, restoredSchedule := newCalendar(t, &now)
+	events, err := restored.UnmarshalEvents(data)
+	require.NoError(t, err)
+	require.NoError(t, restored.Replay(events), "Replaying reproduces the same ticks")
+
+	assert.Equal(t, 1, restored.GetState("days"))
+	assert.Empty(t, restoredSchedule.Tick(restored), "Ticks already in the log are not repeated")
+	now = noon.Add(2 * time.Hour)
+	assert.Equal(t, []string{"day_passed#2", "upkeep#3"}, ticked(restoredSchedule.Tick(restored)))
+}
+
+// TestIntervalsMustBePositive verifies schedules that would never advance are refused
+func TestIntervalsMustBePositive(t *testing.T) {
+	_, err := scheduler.New(scheduler.Every("day_passed", time.Hour), scheduler.Every("upkeep", 0))
+	assert.ErrorIs(t, err, atmos.ErrInvalidInterval)
+	assert.EqualError(t, err, "interval must be positive: upkeep every 0s")
+
+	_, err = scheduler.New(scheduler.Every("day_passed", -time.Hour))
+	assert.ErrorIs(t, err, atmos.ErrInvalidInterval)
+
+	schedule, err := scheduler.New()
+	require.NoError(t, err)
+	assert.ErrorIs(t, schedule.Run(context.Background(), atmos.NewEngine(), &sync.Mutex{}, 0), atmos.ErrInvalidInterval)
+}
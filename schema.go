@@ -0,0 +1,145 @@
+package atmos
+
+import (
+	"encoding"
+	"encoding/json"
+	"reflect"
+	"strings"
+	"time"
+)
+
+// Schema is a JSON Schema document
+type Schema map[string]interface{}
+
+// schemaDialect is the JSON Schema version produced by ExportEventSchemas
+const schemaDialect = "https://json-schema.org/draft/2020-12/schema"
+
+var (
+	timeType          = reflect.TypeOf(time.Time{})
+	jsonMarshalerType = reflect.TypeOf((*json.Marshaler)(nil)).Elem()
+	textMarshalerType = reflect.TypeOf((*encoding.TextMarshaler)(nil)).Elem()
+)
+
+// ExportEventSchemas returns a JSON Schema for the data payload of every event
+// type with a registered factory, keyed by event type. Schemas are derived by
+// reflection and follow encoding/json conventions (json tags, omitempty,
+// embedded structs), so front ends can validate payloads and generate types
+// from the engine's own definitions.
+func (e *Engine) ExportEventSchemas() map[string]Schema {
+	schemas := make(map[string]Schema, len(e.eventFactories))
+	for eventType, factory := range e.eventFactories {
+		schema := valueSchema(reflect.TypeOf(factory()), map[reflect.Type]bool{})
+		schema["$schema"] = schemaDialect
+		schema["title"] = eventType
+		schemas[eventType] = schema
+	}
+	return schemas
+}
+
+// schemaFor builds the schema of a Go type, allowing null for the types
+// encoding/json writes as null when nil (pointers, slices and maps); seen
+// guards against recursive types
+func schemaFor(t reflect.Type, seen map[reflect.Type]bool) Schema {
+	schema := valueSchema(t, seen)
+	if name, ok := schema["type"].(string); ok && nullable(t) {
+		schema["type"] = []string{name, "null"}
+	}
+	return schema
+}
+
+// nullable reports whether encoding/json writes nil values of a type as null
+func nullable(t reflect.Type) bool {
+	switch t.Kind() {
+	case reflect.Ptr, reflect.Slice, reflect.Map:
+		return true
+	}
+	return false
+}
+
+// valueSchema builds the schema of a Go type's non-null values
+func valueSchema(t reflect.Type, seen map[reflect.Type]bool) Schema {
+	for t.Kind() == reflect.Ptr {
+		t = t.Elem()
+	}
+
+	switch {
+	case t == timeType:
+		return Schema{"type": "string", "format": "date-time"}
+	case t.Implements(jsonMarshalerType) || reflect.PointerTo(t).Implements(jsonMarshalerType):
+		return Schema{} // Custom JSON: any value
+	case t.Implements(textMarshalerType) || reflect.PointerTo(t).Implements(textMarshalerType):
+		return Schema{"type": "string"}
+	}
+
+	switch t.Kind() {
+	case reflect.Bool:
+		return Schema{"type": "boolean"}
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return Schema{"type": "integer"}
+	case reflect.Float32, reflect.Float64:
+		return Schema{"type": "number"}
+	case reflect.String:
+		return Schema{"type": "string"}
+	case reflect.Slice, reflect.Array:
+		if t.Elem().Kind() == reflect.Uint8 && t.Kind() == reflect.Slice {
+			return Schema{"type": "string", "contentEncoding": "base64"}
+		}
+		return Schema{"type": "array", "items": schemaFor(t.Elem(), seen)}
+	case reflect.Map:
+		return Schema{"type": "object", "additionalProperties": schemaFor(t.Elem(), seen)}
+	case reflect.Struct:
+		if seen[t] {
+			return Schema{"type": "object"} // Recursive type
+		}
+		seen[t] = true
+		defer delete(seen, t)
+
+		properties := Schema{}
+		var required []string
+		addStructFields(t, seen, properties, &required)
+
+		schema := Schema{"type": "object", "properties": properties}
+		if len(required) > 0 {
+			schema["required"] = required
+		}
+		return schema
+	default:
+		return Schema{} // interface{} and anything else: any value
+	}
+}
+
+// addStructFields adds a struct's JSON fields, flattening embedded structs.
+// Fields that may be null or omitted aren't required.
+func addStructFields(t reflect.Type, seen map[reflect.Type]bool, properties Schema, required *[]string) {
+	for i := 0; i < t.NumField(); i++ {
+		field := t.Field(i)
+		tag := field.Tag.Get("json")
+		if tag == "-" {
+			continue
+		}
+		name, options, _ := strings.Cut(tag, ",")
+
+		if field.Anonymous && name == "" {
+			embedded := field.Type
+			if embedded.Kind() == reflect.Ptr {
+				embedded = embedded.Elem()
+			}
+			if embedded.Kind() == reflect.Struct {
+				addStructFields(embedded, seen, properties, required)
+				continue
+			}
+		}
+		if !field.IsExported() {
+			continue
+		}
+
+		if name == "" {
+			name = field.Name
+		}
+		properties[name] = schemaFor(field.Type, seen)
+		if !strings.Contains(options, "omitempty") && !nullable(field.Type) {
+			*required = append(*required, name)
+		}
+	}
+}
@@ -0,0 +1,54 @@
+package atmos
+
+import (
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+type TradeOfferedEvent struct {
+	AutoEvent[TradeOfferedEvent]
+	From     string         `json:"from"`
+	To       string         `json:"to,omitempty"`
+	Offer    map[string]int `json:"offer"`
+	Expires  time.Time      `json:"expires"`
+	Notes    []string       `json:"notes,omitempty"`
+	Internal string         `json:"-"`
+	Counter  *TradeOffer    `json:"counter,omitempty"`
+	Priority float64
+}
+
+type TradeOffer struct {
+	Counter *TradeOffer `json:"counter"`
+}
+
+// TestExportEventSchemas verifies schemas follow encoding/json conventions
+func TestExportEventSchemas(t *testing.T) {
+	engine := NewEngine()
+	engine.RegisterEventTypes(&TradeOfferedEvent{}, &OrderPlacedEvent{})
+
+	schemas := engine.ExportEventSchemas()
+	assert.Len(t, schemas, 2)
+
+	data, err := json.Marshal(schemas["trade_offered"])
+	assert.NoError(t, err)
+	assert.JSONEq(t, `{
+		"$schema": "https://json-schema.org/draft/2020-12/schema",
+		"title": "trade_offered",
+		"type": "object",
+		"properties": {
+			"from": {"type": "string"},
+			"to": {"type": "string"},
+			"offer": {"type": ["object", "null"], "additionalProperties": {"type": "integer"}},
+			"expires": {"type": "string", "format": "date-time"},
+			"notes": {"type": ["array", "null"], "items": {"type": "string"}},
+			"counter": {"type": ["object", "null"], "properties": {"counter": {"type": ["object", "null"]}}},
+			"Priority": {"type": "number"}
+		},
+		"required": ["from", "expires", "Priority"]
+	}`, string(data))
+
+	assert.Equal(t, Schema{"type": "number"}, schemas["order_placed"]["properties"].(Schema)["Amount"])
+}
@@ -0,0 +1,264 @@
+// Package scoring keeps a running score from events, adds end-of-game
+// scoring rules, and ranks players with tie-breakers:
+//
+//	score := scoring.New(
+//		scoring.OnEvent("trick_won", func(event atmos.Event) map[string]int {
+//			return map[string]int{event.(TrickWonEvent).Player: 1}
+//		}),
+//		scoring.AtEnd("longest road", LongestRoadBonus),
+//		scoring.WithTieBreakers(scoring.Lower(CardsInHand)),
+//	)
+//	score.Install(engine)
+//	...
+//	score.Finish(engine) // emits a GameScoredEvent with final standings
+//
+// End-of-game rules run once, when Finish is called, and their results are
+// recorded in the GameScoredEvent so replays never re-evaluate them.
+package scoring
+
+import (
+	"errors"
+	"maps"
+	"slices"
+	"sort"
+
+	"github.com/cumulusrpg/atmos"
+	"github.com/cumulusrpg/atmos/types"
+)
+
+// ScoredEventType is the type of GameScoredEvent
+const ScoredEventType = "game_scored"
+
+// EventRule awards points (per player, possibly negative) for an event
+type EventRule func(event atmos.Event) map[string]int
+
+// EndRule awards points (per player) from the final state of the game
+type EndRule func(engine *atmos.Engine) map[string]int
+
+// TieBreaker gives each player a secondary key; among tied players the
+// higher key ranks first
+type TieBreaker func(engine *atmos.Engine, player string) int
+
+// Lower reverses a tie-breaker so the lower key ranks first (e.g. fewest turns)
+func Lower(tieBreaker TieBreaker) TieBreaker {
+	return func(engine *atmos.Engine, player string) int {
+		return -tieBreaker(engine, player)
+	}
+}
+
+// Standing is a player's place in the ranking
+type Standing struct {
+	Player string `json:"player"`
+	Score  int    `json:"score"`
+	Rank   int    `json:"rank"` // 1 for the winner; players still tied after every tie-breaker share a rank
+}
+
+// GameScoredEvent records the final scoring of a game
+type GameScoredEvent struct {
+	Bonuses   map[string]map[string]int `json:"bonuses"` // End rule name -> player -> points
+	Standings []Standing                `json:"standings"`
+}
+
+// Type implements atmos.Event
+func (e GameScoredEvent) Type() string { return ScoredEventType }
+
+// Scores is the score state. Awards copy Points rather than add to it.
+type Scores struct {
+	Points map[string]int   `json:"points"`
+	Final  *GameScoredEvent `json:"final,omitempty"` // Set once the game is scored
+}
+
+// endRule is a named end-of-game rule
+type endRule struct {
+	name string
+	rule EndRule
+}
+
+// Scoring keeps score on an engine
+type Scoring struct {
+	name        string
+	eventRules  map[string][]EventRule
+	endRules    []endRule
+	tieBreakers []TieBreaker
+}
+
+// Option configures scoring construction
+type Option func(*Scoring)
+
+// OnEvent awards points whenever an event of the given type commits
+func OnEvent(eventType string, rule EventRule) Option {
+	return func(s *Scoring) {
+		s.eventRules[eventType] = append(s.eventRules[eventType], rule)
+	}
+}
+
+// AtEnd adds a named end-of-game rule, evaluated by Finish
+func AtEnd(name string, rule EndRule) Option {
+	return func(s *Scoring) {
+		s.endRules = append(s.endRules, endRule{name: name, rule: rule})
+	}
+}
+
+// WithTieBreakers sets the tie-breakers applied, in order, to tied players
+func WithTieBreakers(tieBreakers ...TieBreaker) Option {
+	return func(s *Scoring) {
+		s.tieBreakers = tieBreakers
+	}
+}
+
+// WithStateName sets the name of the score state (default "score")
+func WithStateName(name string) Option {
+	return func(s *Scoring) {
+		s.name = name
+	}
+}
+
+// New creates scoring rules
+func New(opts ...Option) *Scoring {
+	s := &Scoring{
+		name:       "score",
+		eventRules: make(map[string][]EventRule),
+	}
+
+	// Apply options
+	for _, opt := range opts {
+		opt(s)
+	}
+
+	return s
+}
+
+// Install registers the score state, its reducers, and the scored event
+func (s *Scoring) Install(engine *atmos.Engine) {
+	engine.RegisterState(s.name, Scores{Points: map[string]int{}})
+
+	for _, eventType := range slices.Sorted(maps.Keys(s.eventRules)) {
+		engine.When(eventType).Updates(s.name, s.reduce)
+	}
+	engine.When(ScoredEventType, func() atmos.Event { return &GameScoredEvent{} }).
+		Requires(&notScoredValidator{scoring: s}).
+		Updates(s.name, s.reduce)
+}
+
+// Scores returns the score state
+func (s *Scoring) Scores(engine *atmos.Engine) Scores {
+	return engine.GetState(s.name).(Scores)
+}
+
+// Score returns a player's points so far (or final score, once scored)
+func (s *Scoring) Score(engine *atmos.Engine, player string) int {
+	return s.Scores(engine).Points[player]
+}
+
+// Standings ranks players by their current points and the tie-breakers
+func (s *Scoring) Standings(engine *atmos.Engine) []Standing {
+	return s.rank(engine, s.Scores(engine).Points)
+}
+
+// Finish evaluates the end-of-game rules and emits a GameScoredEvent with
+// the final standings
+func (s *Scoring) Finish(engine *atmos.Engine) bool {
+	points := copyPoints(s.Scores(engine).Points)
+	bonuses := make(map[string]map[string]int, len(s.endRules))
+	for _, end := range s.endRules {
+		awarded := end.rule(engine)
+		bonuses[end.name] = awarded
+		for player, bonus := range awarded {
+			points[player] += bonus
+		}
+	}
+	return engine.Emit(GameScoredEvent{Bonuses: bonuses, Standings: s.rank(engine, points)})
+}
+
+// rank orders players by points, then tie-breakers, assigning shared ranks to exact ties
+func (s *Scoring) rank(engine *atmos.Engine, points map[string]int) []Standing {
+	players := slices.Sorted(maps.Keys(points))
+	keys := make(map[string][]int, len(players))
+	for _, player := range players {
+		key := []int{points[player]}
+		for _, tieBreaker := range s.tieBreakers {
+			key = append(key, tieBreaker(engine, player))
+		}
+		keys[player] = key
+	}
+
+	// Stable sort keeps name order among exact ties
+	sort.SliceStable(players, func(i, j int) bool {
+		return compare(keys[players[i]], keys[players[j]]) > 0
+	})
+
+	standings := make([]Standing, len(players))
+	for i, player := range players {
+		rank := i + 1
+		if i > 0 && compare(keys[player], keys[players[i-1]]) == 0 {
+			rank = standings[i-1].Rank
+		}
+		standings[i] = Standing{Player: player, Score: points[player], Rank: rank}
+	}
+	return standings
+}
+
+// compare orders two keys lexicographically
+func compare(a, b []int) int {
+	for i := range a {
+		if a[i] != b[i] {
+			if a[i] > b[i] {
+				return 1
+			}
+			return -1
+		}
+	}
+	return 0
+}
+
+// reduce applies event rules, or the final scoring
+func (s *Scoring) reduce(engine *atmos.Engine, state interface{}, event atmos.Event) interface{} {
+	scores := state.(Scores)
+
+	if event.Type() == ScoredEventType {
+		final := atmos.Deref(event).(GameScoredEvent)
+		points := make(map[string]int, len(final.Standings))
+		for _, standing := range final.Standings {
+			points[standing.Player] = standing.Score
+		}
+		return Scores{Points: points, Final: &final}
+	}
+
+	if scores.Final != nil {
+		return scores // Points are frozen once the game is scored
+	}
+	points := copyPoints(scores.Points)
+	for _, rule := range s.eventRules[event.Type()] {
+		for player, awarded := range rule(event) {
+			points[player] += awarded
+		}
+	}
+	return Scores{Points: points}
+}
+
+// notScoredValidator rejects scoring a game twice
+type notScoredValidator struct {
+	scoring *Scoring
+}
+
+// Validate implements atmos.EventValidator
+func (v *notScoredValidator) Validate(engine types.Engine, event atmos.Event) bool {
+	return v.Check(engine, event) == nil
+}
+
+// Check implements atmos.ReasonedValidator
+func (v *notScoredValidator) Check(engine types.Engine, event atmos.Event) error {
+	if v.scoring.Scores(engine.(*atmos.Engine)).Final != nil {
+		return errors.New("the game has already been scored")
+	}
+	return nil
+}
+
+// copyPoints copies a points map
+func copyPoints(points map[string]int) map[string]int {
+	next := make(map[string]int, len(points))
+	for player, p := range points {
+		next[player] = p
+	}
+	return next
+}
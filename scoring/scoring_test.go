@@ -0,0 +1,89 @@
+package scoring_test
+
+import (
+	"testing"
+
+	"github.com/cumulusrpg/atmos"
+	"github.com/cumulusrpg/atmos/scoring"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type TrickWonEvent struct {
+	Player string
+	Points int
+}
+
+func (e TrickWonEvent) Type() string { return "trick_won" }
+
+// newGame scores tricks, gives carol a 2 point bonus at the end, and breaks
+// ties by fewest cards left (alice 3, bob 1, carol 1, dave 1)
+func newGame() (*atmos.Engine, *scoring.Scoring) {
+	cardsLeft := map[string]int{"alice": 3, "bob": 1, "carol": 1, "dave": 1}
+	score := scoring.New(
+		scoring.OnEvent("trick_won", func(event atmos.Event) map[string]int {
+			won := event.(TrickWonEvent)
+			return map[string]int{won.Player: won.Points}
+		}),
+		scoring.AtEnd("most hearts", func(engine *atmos.Engine) map[string]int {
+			return map[string]int{"carol": 2}
+		}),
+		scoring.WithTieBreakers(scoring.Lower(func(engine *atmos.Engine, player string) int {
+			return cardsLeft[player]
+		})),
+	)
+	engine := atmos.NewEngine()
+	score.Install(engine)
+	return engine, score
+}
+
+// TestScoringRanksWithTieBreakers verifies running scores, end rules, and tie-breaking
+func TestScoringRanksWithTieBreakers(t *testing.T) {
+	engine, score := newGame()
+	engine.Emit(TrickWonEvent{Player: "alice", Points: 5})
+	engine.Emit(TrickWonEvent{Player: "bob", Points: 5})
+	engine.Emit(TrickWonEvent{Player: "carol", Points: 3})
+	engine.Emit(TrickWonEvent{Player: "dave", Points: 5})
+
+	assert.Equal(t, 5, score.Score(engine, "alice"))
+	assert.Equal(t, []scoring.Standing{
+		{Player: "bob", Score: 5, Rank: 1},
+		{Player: "dave", Score: 5, Rank: 1}, // Tied after every tie-breaker
+		{Player: "alice", Score: 5, Rank: 3},
+		{Player: "carol", Score: 3, Rank: 4},
+	}, score.Standings(engine))
+
+	require.True(t, score.Finish(engine))
+	final := score.Scores(engine).Final
+	require.NotNil(t, final)
+	assert.Equal(t, map[string]map[string]int{"most hearts": {"carol": 2}}, final.Bonuses)
+	assert.Equal(t, []scoring.Standing{
+		{Player: "bob", Score: 5, Rank: 1},
+		{Player: "carol", Score: 5, Rank: 1},
+		{Player: "dave", Score: 5, Rank: 1},
+		{Player: "alice", Score: 5, Rank: 4},
+	}, final.Standings)
+
+	assert.False(t, score.Finish(engine), "A game is scored once")
+	engine.Emit(TrickWonEvent{Player: "alice", Points: 10})
+	assert.Equal(t, 5, score.Score(engine, "alice"), "Scores are frozen after the final scoring")
+}
+
+// TestScoringReplaysRecordedResult verifies replays use the recorded final scoring
+func TestScoringReplaysRecordedResult(t *testing.T) {
+	engine, score := newGame()
+	engine.Emit(TrickWonEvent{Player: "alice", Points: 1})
+	score.Finish(engine)
+
+	data, err := engine.MarshalEvents(engine.GetEvents())
+	require.NoError(t, err)
+
+	replayed := atmos.NewEngine()
+	scoring.New().Install(replayed) // No end rules: results come from the log
+	replayed.RegisterEventType("trick_won", func() atmos.Event { return &TrickWonEvent{} })
+	events, err := replayed.UnmarshalEvents(data)
+	require.NoError(t, err)
+	replayed.SetEvents(events)
+
+	assert.Equal(t, score.Scores(engine), score.Scores(replayed))
+}
@@ -0,0 +1,100 @@
+package atmos
+
+import "strings"
+
+// selector derives a value from other states or selectors
+type selector struct {
+	deps    []string
+	compute func(states ...interface{}) interface{}
+}
+
+// selection is a memoized selector result
+type selection struct {
+	revision uint64
+	value    interface{}
+}
+
+// RegisterSelector registers a value derived from several states, such as
+// the legal moves given the board and the current turn:
+//
+//	engine.RegisterSelector("legal_moves", []string{"board", "turn"}, func(states ...interface{}) interface{} {
+//		return legalMoves(states[0].(Board), states[1].(string))
+//	})
+//
+// Dependencies are passed in the order listed and may name other selectors,
+// but not the selector itself, directly or indirectly: registering a cycle
+// panics with ErrMisconfigured.
+// Results are memoized until the engine's log, snapshots or registrations
+// change, so repeated queries between events cost nothing. Changes made to
+// the repository directly, bypassing the engine, are not noticed.
+func (e *Engine) RegisterSelector(name string, deps []string, compute func(states ...interface{}) interface{}) {
+	if cycle := e.selectorCycle(name, deps); cycle != nil {
+		e.misconfigured("selector %q depends on itself (%s)", name, strings.Join(cycle, " -> "))
+	}
+	e.mutableRegistrations().selectors[name] = selector{
+		deps:    append([]string(nil), deps...),
+		compute: compute,
+	}
+}
+
+// selectorCycle returns the path by which a selector with deps would depend
+// on itself, or nil
+func (e *Engine) selectorCycle(name string, deps []string) []string {
+	visited := make(map[string]bool)
+	var walk func(path, deps []string) []string
+	walk = func(path, deps []string) []string {
+		for _, dep := range deps {
+			if dep == name {
+				return append(path, dep)
+			}
+			sel, isSelector := e.selectors[dep]
+			if !isSelector || visited[dep] {
+				continue
+			}
+			visited[dep] = true
+			if cycle := walk(append(path, dep), sel.deps); cycle != nil {
+				return cycle
+			}
+		}
+		return nil
+	}
+	return walk([]string{name}, deps)
+}
+
+// Select returns a selector's value, computing it only if the engine has
+// changed since it was last selected. Returns nil for unknown selectors.
+func (e *Engine) Select(name string) interface{} {
+	sel, exists := e.selectors[name]
+	if !exists {
+		return nil
+	}
+	e.absorbExternal()
+	if memo, cached := e.selections[name]; cached && memo.revision == e.revision {
+		return memo.value
+	}
+
+	// Project the state dependencies in one traversal
+	var stateDeps []string
+	for _, dep := range sel.deps {
+		if _, isSelector := e.selectors[dep]; !isSelector {
+			stateDeps = append(stateDeps, dep)
+		}
+	}
+	states := e.GetStates(stateDeps...)
+
+	values := make([]interface{}, len(sel.deps))
+	for i, dep := range sel.deps {
+		if _, isSelector := e.selectors[dep]; isSelector {
+			values[i] = e.Select(dep)
+		} else {
+			values[i] = states[dep]
+		}
+	}
+
+	value := sel.compute(values...)
+	if e.selections == nil {
+		e.selections = make(map[string]selection)
+	}
+	e.selections[name] = selection{revision: e.revision, value: value}
+	return value
+}
@@ -0,0 +1,92 @@
+package atmos
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// newSelectorEngine derives each player's lead from the round wins and the balance
+func newSelectorEngine(computed *int) *Engine {
+	engine := newRoundEngine()
+	engine.RegisterState("balance", TokenBalance{Tokens: 3})
+	engine.When("tokens_spent").Updates("balance", func(e *Engine, state interface{}, event Event) interface{} {
+		return TokenBalance{Tokens: state.(TokenBalance).Tokens - event.(TokensSpentEvent).Amount}
+	})
+	engine.RegisterSelector("score", []string{"wins", "balance"}, func(states ...interface{}) interface{} {
+		*computed++
+		return states[0].(map[string]int)["alice"]*10 + states[1].(TokenBalance).Tokens
+	})
+	return engine
+}
+
+// TestSelectMemoizesUntilTheLogChanges verifies a selector is recomputed only after new events
+func TestSelectMemoizesUntilTheLogChanges(t *testing.T) {
+	computed := 0
+	engine := newSelectorEngine(&computed)
+	engine.Emit(RoundPlayedEvent{Winner: "alice"})
+
+	assert.Equal(t, 13, engine.Select("score"))
+	assert.Equal(t, 13, engine.Select("score"))
+	assert.Equal(t, 1, computed)
+
+	engine.Emit(TokensSpentEvent{Amount: 1})
+	assert.Equal(t, 12, engine.Select("score"))
+	assert.Equal(t, 2, computed)
+
+	engine.SetEvents(nil)
+	assert.Equal(t, 3, engine.Select("score"))
+	assert.Equal(t, 3, computed)
+
+	require.True(t, engine.EmitAll(RoundPlayedEvent{Winner: "alice"}, RoundPlayedEvent{Winner: "alice"}).Accepted)
+	assert.Equal(t, 23, engine.Select("score"))
+	assert.Equal(t, 4, computed)
+}
+
+// TestSelectorsCompose verifies selectors may depend on other selectors
+func TestSelectorsCompose(t *testing.T) {
+	computed := 0
+	engine := newSelectorEngine(&computed)
+	engine.RegisterSelector("leading", []string{"score", "wins"}, func(states ...interface{}) interface{} {
+		return states[0].(int) > 10 && states[1].(map[string]int)["bob"] == 0
+	})
+
+	assert.Equal(t, false, engine.Select("leading"))
+	engine.Emit(RoundPlayedEvent{Winner: "alice"})
+	assert.Equal(t, true, engine.Select("leading"))
+	assert.Equal(t, true, engine.Select("leading"))
+	assert.Equal(t, 2, computed)
+}
+
+// TestSelectUnknown verifies unknown selectors select nothing
+func TestSelectUnknown(t *testing.T) {
+	assert.Nil(t, NewEngine().Select("missing"))
+}
+
+// TestSelectAfterRegistrationChange verifies changing a reducer invalidates memoized results
+func TestSelectAfterRegistrationChange(t *testing.T) {
+	computed := 0
+	engine := newSelectorEngine(&computed)
+	engine.Emit(TokensSpentEvent{Amount: 1})
+	assert.Equal(t, 2, engine.Select("score"))
+
+	engine.UseReducerMiddleware("balance", OnlyIf(func(*Engine, interface{}, Event) bool { return false }))
+	assert.Equal(t, 3, engine.Select("score"))
+}
+
+// TestSelectorCyclesAreRefused verifies a selector can't depend on itself, however indirectly
+func TestSelectorCyclesAreRefused(t *testing.T) {
+	engine := NewEngine()
+	first := func(states ...interface{}) interface{} { return states[0] }
+	engine.RegisterSelector("a", []string{"b"}, first)
+	engine.RegisterSelector("b", []string{"c"}, first)
+
+	assert.PanicsWithError(t, `misconfigured registration: selector "c" depends on itself (c -> a -> b -> c)`, func() {
+		engine.RegisterSelector("c", []string{"a"}, first)
+	})
+	assert.Panics(t, func() { engine.RegisterSelector("d", []string{"d"}, first) })
+
+	engine.RegisterSelector("c", []string{"balance"}, first)
+	assert.Nil(t, engine.Select("a"), "A chain ending in a state is fine")
+}
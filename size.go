@@ -0,0 +1,238 @@
+package atmos
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"reflect"
+	"unicode/utf8"
+
+	"github.com/cumulusrpg/atmos/types"
+)
+
+// ErrPayloadTooLarge is returned for events whose JSON payload is over the
+// limit (see WithMaxPayloadSize)
+var ErrPayloadTooLarge = errors.New("payload too large")
+
+// PayloadSizeValidator is the RejectedBy of emits rejected for their size
+const PayloadSizeValidator = "payload size"
+
+// OversizedPolicy decides what happens to an emitted event whose payload is
+// over the limit (see WithMaxPayloadSize)
+type OversizedPolicy struct {
+	truncate []string        // JSON names of the string fields to cut
+	blobs    types.BlobStore // where payloads are externalized
+}
+
+// RejectOversized rejects oversized events (the default)
+func RejectOversized() OversizedPolicy {
+	return OversizedPolicy{}
+}
+
+// TruncateOversized cuts the named string fields (by JSON name), in order,
+// until the payload fits. Events that still don't fit are rejected. The
+// truncated event is decoded afresh, so unexported fields are lost.
+func TruncateOversized(fields ...string) OversizedPolicy {
+	return OversizedPolicy{truncate: fields}
+}
+
+// ExternalizeOversized accepts oversized events whole, and whenever one is
+// serialized (MarshalEvents, Export, autosave...) puts its payload in store
+// and writes only the key to the log. Decoding fetches the payload back.
+// Puts repeat for every serialization, so store should dedupe by content
+// (as repository.InMemoryBlobs does).
+func ExternalizeOversized(store types.BlobStore) OversizedPolicy {
+	return OversizedPolicy{blobs: store}
+}
+
+// WithMaxPayloadSize limits events' JSON payloads to limit bytes as the log
+// stores them (encrypted fields encrypted), protecting the repository from
+// pathological inputs. Larger emitted events are
+// handled by policy: rejected by PayloadSizeValidator, truncated, or
+// externalized. Larger stored records are refused when decoded, with
+// ErrPayloadTooLarge (UnmarshalEvents skips or quarantines them).
+func WithMaxPayloadSize(limit int, policy OversizedPolicy) EngineOption {
+	return func(e *Engine) {
+		e.payloadLimit = limit
+		e.oversized = policy
+	}
+}
+
+// blobRef is what the log stores in place of an externalized payload
+type blobRef struct {
+	Blob string `json:"$blob"`
+}
+
+// limitPayload applies the oversized policy to an emitted event, returning
+// the event to commit. The payload is measured as the log stores it (see
+// storedPayload), so every accepted event decodes again under the limit.
+func (e *Engine) limitPayload(event Event) (Event, error) {
+	if e.oversized.blobs != nil {
+		return event, nil // eventData externalizes what doesn't fit
+	}
+	data, err := e.storedPayload(event)
+	if err != nil || len(data) <= e.payloadLimit {
+		return event, err
+	}
+	if len(e.oversized.truncate) > 0 {
+		truncated, ok, err := e.truncate(event, len(data))
+		if err != nil || ok {
+			return truncated, err
+		}
+	}
+	return nil, e.tooLarge(len(data))
+}
+
+// storedPayload returns an event's payload as the log stores it, with
+// tagged fields encrypted and inline blobs as references (see pendingBlobs)
+func (e *Engine) storedPayload(event Event) ([]byte, error) {
+	data, err := e.encryptData(e.pendingBlobs(event))
+	if err != nil {
+		return nil, err
+	}
+	return json.Marshal(data)
+}
+
+// truncate cuts the policy's fields until the stored payload, size bytes
+// as emitted, fits. Fields are cut in plaintext, so the plaintext target is
+// offset by how much storing changes the size (encryption, blob
+// references), and lowered by any overshoot before cutting again.
+func (e *Engine) truncate(event Event, size int) (Event, bool, error) {
+	plaintext, err := json.Marshal(event)
+	if err != nil {
+		return nil, false, err
+	}
+	for target := e.payloadLimit + len(plaintext) - size; target > 0; {
+		truncated, ok := truncateFields(event, plaintext, target, e.oversized.truncate)
+		if !ok {
+			return nil, false, nil
+		}
+		data, err := e.storedPayload(truncated)
+		if err != nil {
+			return nil, false, err
+		}
+		if len(data) <= e.payloadLimit {
+			return truncated, true, nil
+		}
+		target -= len(data) - e.payloadLimit
+	}
+	return nil, false, nil
+}
+
+// tooLarge describes a payload over the limit
+func (e *Engine) tooLarge(size int) error {
+	return fmt.Errorf("%w: %d bytes, over the limit of %d", ErrPayloadTooLarge, size, e.payloadLimit)
+}
+
+// truncateFields cuts string fields of an event's payload until it fits in
+// limit bytes, returning a copy of the event with the cut payload
+func truncateFields(event Event, data []byte, limit int, fields []string) (Event, bool) {
+	var object map[string]json.RawMessage
+	if json.Unmarshal(data, &object) != nil {
+		return nil, false
+	}
+
+	size := len(data)
+	for _, name := range fields {
+		var s string
+		if size <= limit {
+			break
+		}
+		if json.Unmarshal(object[name], &s) != nil {
+			continue // Absent, or not a string
+		}
+		// Each byte cut shortens the encoding by at least a byte
+		keep := len(s) - (size - limit)
+		if keep < 0 {
+			keep = 0
+		}
+		for keep > 0 && !utf8.RuneStart(s[keep]) {
+			keep--
+		}
+		object[name], _ = json.Marshal(s[:keep])
+		data, _ = json.Marshal(object)
+		size = len(data)
+	}
+	if size > limit {
+		return nil, false
+	}
+
+	t := reflect.TypeOf(event)
+	pointer := t.Kind() == reflect.Ptr
+	if pointer {
+		t = t.Elem()
+	}
+	value := reflect.New(t)
+	if json.Unmarshal(data, value.Interface()) != nil {
+		return nil, false
+	}
+	if pointer {
+		return value.Interface().(Event), true
+	}
+	return value.Elem().Interface().(Event), true
+}
+
+// eventData returns an event's payload for serialization, with tagged
+// fields encrypted (see encryptData) and, if oversized and the policy says
+// so, moved to blob storage
+func (e *Engine) eventData(event Event) (interface{}, error) {
+	data, err := e.encryptData(event)
+	if err != nil || e.oversized.blobs == nil || e.payloadLimit == 0 {
+		return data, err
+	}
+
+	encoded, err := json.Marshal(data)
+	if err != nil {
+		return nil, err
+	}
+	if len(encoded) <= e.payloadLimit {
+		return data, nil
+	}
+	key, err := e.oversized.blobs.Put(encoded)
+	if err != nil {
+		return nil, fmt.Errorf("externalizing %s: %w", event.Type(), err)
+	}
+	return blobRef{Blob: key}, nil
+}
+
+// storedData reverses eventData for a payload about to be decoded into
+// event, refusing records over the limit
+func (e *Engine) storedData(event Event, data json.RawMessage) (json.RawMessage, error) {
+	key, externalized := e.blobKey(data)
+	if !externalized && e.payloadLimit > 0 && len(data) > e.payloadLimit {
+		// Measured as written, so indentation added since doesn't count
+		var compact bytes.Buffer
+		if json.Compact(&compact, data) != nil || compact.Len() > e.payloadLimit {
+			return nil, e.tooLarge(len(data))
+		}
+	}
+	if externalized {
+		blob, err := e.oversized.blobs.Get(key)
+		if err != nil {
+			return nil, fmt.Errorf("fetching externalized payload: %w", err)
+		}
+		data = blob
+	}
+	return e.decryptData(event, data)
+}
+
+// maxBlobRef bounds the size of a reference to an externalized payload,
+// which may be larger than the payload limit
+const maxBlobRef = 512
+
+// blobKey returns the key of an externalized payload's reference
+func (e *Engine) blobKey(data json.RawMessage) (string, bool) {
+	if e.oversized.blobs == nil || len(data) > maxBlobRef || !bytes.Contains(data, []byte(`"$blob"`)) {
+		return "", false
+	}
+	var object map[string]json.RawMessage
+	if json.Unmarshal(data, &object) != nil || len(object) != 1 {
+		return "", false
+	}
+	var key string
+	if json.Unmarshal(object["$blob"], &key) != nil {
+		return "", false
+	}
+	return key, true
+}
@@ -0,0 +1,188 @@
+package atmos
+
+import (
+	"bytes"
+	"encoding/json"
+	"strings"
+	"testing"
+
+	"github.com/cumulusrpg/atmos/repository"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type PostedEvent struct {
+	Author string `json:"author"`
+	Title  string `json:"title"`
+	Body   string `json:"body"`
+}
+
+func (e PostedEvent) Type() string { return "posted" }
+
+// TestMaxPayloadSizeRejectsOversizedEvents verifies the default policy rejects before any validator runs
+func TestMaxPayloadSizeRejectsOversizedEvents(t *testing.T) {
+	engine := NewEngine(WithMaxPayloadSize(64, RejectOversized()))
+	engine.RegisterValidator("posted", rejectEverything{})
+
+	result := engine.EmitWithResult(PostedEvent{Author: "ann", Body: strings.Repeat("x", 100)})
+	assert.Equal(t, PayloadSizeValidator, result.RejectedBy)
+	assert.Equal(t, "payload too large: 137 bytes, over the limit of 64", result.Reason)
+	assert.Empty(t, engine.GetEvents())
+
+	result = engine.EmitWithResult(PostedEvent{Author: "ann", Body: "short"})
+	assert.Equal(t, "atmos.rejectEverything", result.RejectedBy, "Events that fit go on to validation")
+}
+
+// TestMaxPayloadSizeTruncatesFields verifies designated fields are cut in order until the event fits
+func TestMaxPayloadSizeTruncatesFields(t *testing.T) {
+	engine := NewEngine(WithMaxPayloadSize(64, TruncateOversized("body", "title")))
+
+	require.True(t, engine.Emit(PostedEvent{Author: "ann", Title: "hello", Body: strings.Repeat("é", 40)}))
+	posted := engine.GetEvents()[0].(PostedEvent)
+	assert.Equal(t, "hello", posted.Title, "Later fields are only cut if needed")
+	assert.Equal(t, strings.Repeat("é", 11), posted.Body, "Cut on a character boundary")
+	data, _ := engine.MarshalEvent(posted)
+	assert.Contains(t, string(data), `"author":"ann"`)
+
+	require.True(t, engine.Emit(&PostedEvent{Author: "bob", Title: strings.Repeat("t", 60), Body: strings.Repeat("b", 60)}))
+	pointer := engine.GetEvents()[1].(*PostedEvent)
+	assert.Equal(t, "", pointer.Body)
+	assert.Equal(t, strings.Repeat("t", 27), pointer.Title)
+
+	result := engine.EmitWithResult(PostedEvent{Author: strings.Repeat("a", 100)})
+	assert.Equal(t, PayloadSizeValidator, result.RejectedBy, "Still too large once the fields are empty")
+}
+
+// TestMaxPayloadSizeExternalizesPayloads verifies oversized payloads are stored as blobs and fetched back on decode
+func TestMaxPayloadSizeExternalizesPayloads(t *testing.T) {
+	blobs := repository.NewInMemoryBlobs()
+	engine := NewEngine(WithMaxPayloadSize(64, ExternalizeOversized(blobs)))
+	engine.RegisterEventTypes(PostedEvent{})
+
+	large := PostedEvent{Author: "ann", Body: strings.Repeat("x", 100)}
+	require.True(t, engine.Emit(large))
+	require.True(t, engine.Emit(PostedEvent{Author: "bob", Body: "short"}))
+	assert.Equal(t, large, engine.GetEvents()[0], "Kept whole in the engine")
+
+	data, err := engine.MarshalEvents(engine.GetEvents())
+	require.NoError(t, err)
+	assert.NotContains(t, string(data), "xxx")
+	assert.Contains(t, string(data), `"$blob"`)
+	assert.Contains(t, string(data), `"author":"bob"`, "Small payloads stay inline")
+	assert.Equal(t, 1, blobs.Len())
+
+	events, err := engine.UnmarshalEvents(data)
+	require.NoError(t, err)
+	require.Len(t, events, 2)
+	assert.Equal(t, &large, events[0])
+
+	single, err := engine.MarshalEvent(large)
+	require.NoError(t, err)
+	event, err := engine.UnmarshalEvent(single)
+	require.NoError(t, err)
+	assert.Equal(t, &large, event)
+}
+
+// TestMaxPayloadSizeRefusesOversizedRecords verifies stored records over the limit aren't decoded
+func TestMaxPayloadSizeRefusesOversizedRecords(t *testing.T) {
+	writer := NewEngine()
+	data, err := writer.MarshalEvents([]Event{
+		PostedEvent{Author: "ann", Body: strings.Repeat("x", 100)},
+		PostedEvent{Author: "bob"},
+	})
+	require.NoError(t, err)
+
+	engine := NewEngine(WithMaxPayloadSize(64, RejectOversized()), WithQuarantine())
+	engine.RegisterEventTypes(PostedEvent{})
+	events, err := engine.UnmarshalEvents(data)
+	require.NoError(t, err)
+	assert.Equal(t, []Event{&PostedEvent{Author: "bob"}}, events)
+	engine.SetEvents(events)
+	require.Len(t, engine.Quarantined(), 1)
+	assert.ErrorIs(t, engine.Quarantined()[0].Err, ErrPayloadTooLarge)
+
+	single, err := writer.MarshalEvent(PostedEvent{Body: strings.Repeat("x", 100)})
+	require.NoError(t, err)
+	_, err = engine.UnmarshalEvent(single)
+	assert.ErrorIs(t, err, ErrPayloadTooLarge)
+}
+
+// TestMaxPayloadSizeAppliesToBatches verifies EmitAll applies the policy to every event
+func TestMaxPayloadSizeAppliesToBatches(t *testing.T) {
+	engine := NewEngine(WithMaxPayloadSize(64, RejectOversized()))
+
+	result := engine.EmitAll(PostedEvent{Author: "ann"}, PostedEvent{Body: strings.Repeat("x", 100)})
+	assert.Equal(t, PayloadSizeValidator, result.RejectedBy)
+	assert.True(t, strings.HasPrefix(result.Reason, "event 1 (posted): payload too large"))
+	assert.Empty(t, engine.GetEvents())
+
+	truncating := NewEngine(WithMaxPayloadSize(64, TruncateOversized("body")))
+	events := []Event{PostedEvent{Body: strings.Repeat("x", 100)}}
+	require.True(t, truncating.EmitAll(events...).Accepted)
+	assert.Len(t, truncating.GetEvents()[0].(PostedEvent).Body, 30)
+	assert.Len(t, events[0].(PostedEvent).Body, 100, "The caller's slice is left alone")
+}
+
+// TestMaxPayloadSizeMeasuresStoredPayloads verifies encrypted events are measured as stored, so accepted events decode again
+func TestMaxPayloadSizeMeasuresStoredPayloads(t *testing.T) {
+	keys, err := NewAESKeyService(bytes.Repeat([]byte{7}, 32))
+	require.NoError(t, err)
+	engine := NewEngine(WithFieldEncryption(keys), WithMaxPayloadSize(192, RejectOversized()))
+	engine.RegisterEventTypes(ChatSentEvent{})
+
+	chat := ChatSentEvent{Player: "ann", Message: strings.Repeat("x", 60)}
+	plaintext, _ := json.Marshal(chat)
+	require.Less(t, len(plaintext), 192)
+	result := engine.EmitWithResult(chat)
+	assert.Equal(t, PayloadSizeValidator, result.RejectedBy, "Too large once encrypted")
+
+	chat.Message = "gg"
+	require.True(t, engine.Emit(chat))
+	data, err := engine.MarshalEvents(engine.GetEvents())
+	require.NoError(t, err)
+	events, err := engine.UnmarshalEvents(data)
+	require.NoError(t, err)
+	assert.Equal(t, []Event{&chat}, events)
+
+	truncating := NewEngine(WithFieldEncryption(keys), WithMaxPayloadSize(192, TruncateOversized("message")))
+	truncating.RegisterEventTypes(ChatSentEvent{})
+	require.True(t, truncating.Emit(ChatSentEvent{Player: "ann", Message: strings.Repeat("x", 60)}))
+	data, err = truncating.MarshalEvents(truncating.GetEvents())
+	require.NoError(t, err)
+	events, err = truncating.UnmarshalEvents(data)
+	require.NoError(t, err)
+	require.Len(t, events, 1, "Cut until the encrypted payload fits")
+	assert.Less(t, len(events[0].(*ChatSentEvent).Message), 60)
+}
+
+// TestMaxPayloadSizeIgnoresIndentation verifies whitespace added to stored records doesn't count against the limit
+func TestMaxPayloadSizeIgnoresIndentation(t *testing.T) {
+	engine := NewEngine(WithMaxPayloadSize(64, RejectOversized()))
+	engine.RegisterEventTypes(PostedEvent{})
+	require.True(t, engine.Emit(PostedEvent{Author: "ann", Body: strings.Repeat("x", 20)}))
+
+	data, err := engine.MarshalEvents(engine.GetEvents())
+	require.NoError(t, err)
+	var indented bytes.Buffer
+	require.NoError(t, json.Indent(&indented, data, "", "    "))
+	events, err := engine.UnmarshalEvents(indented.Bytes())
+	require.NoError(t, err)
+	assert.Len(t, events, 1)
+}
+
+type unencodableEvent struct {
+	Ch chan int
+}
+
+func (e unencodableEvent) Type() string { return "unencodable" }
+
+// TestMaxPayloadSizeReportsMarshalErrors verifies events that can't be encoded fail instead of passing unmeasured
+func TestMaxPayloadSizeReportsMarshalErrors(t *testing.T) {
+	engine := NewEngine(WithMaxPayloadSize(64, RejectOversized()))
+
+	result := engine.EmitWithResult(unencodableEvent{Ch: make(chan int)})
+	assert.Error(t, result.Err)
+	assert.Empty(t, result.RejectedBy)
+	assert.Error(t, engine.EmitAll(unencodableEvent{Ch: make(chan int)}).Err)
+	assert.Empty(t, engine.GetEvents())
+}
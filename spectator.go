@@ -0,0 +1,164 @@
+package atmos
+
+import (
+	"sync"
+	"time"
+
+	"github.com/cumulusrpg/atmos/types"
+)
+
+// Spectator is a read-only, optionally delayed and masked view of an engine
+// for observers and stream overlays. It has no way to emit, and only sees
+// events once they are older than its delay.
+//
+//	spectator := engine.Spectate(DelayEvents(5), DelayTime(30*time.Second))
+//	board := spectator.State("board")
+//
+// Like the engine itself, a spectator must not be read while the engine is
+// emitting on another goroutine. Close a spectator made with DelayTime when
+// done with it, to unregister its clock.
+type Spectator struct {
+	engine      *Engine
+	viewer      string
+	delayEvents int
+	delay       time.Duration
+	now         func() time.Time
+
+	mu        sync.Mutex
+	base      int             // events already in the log when spectating began (treated as old)
+	committed []time.Time     // commit times of later events
+	clock     *spectatorClock // registered with DelayTime
+
+	past         *Engine // the engine as of the visible events, reused until either changes
+	pastRevision uint64
+	pastVisible  int
+}
+
+// SpectatorOption configures a spectator
+type SpectatorOption func(*Spectator)
+
+// DelayEvents hides the most recent n events
+func DelayEvents(n int) SpectatorOption {
+	return func(s *Spectator) {
+		s.delayEvents = n
+	}
+}
+
+// DelayTime hides events committed less than d ago
+func DelayTime(d time.Duration) SpectatorOption {
+	return func(s *Spectator) {
+		s.delay = d
+	}
+}
+
+// SpectateAs sets the viewer whose masks apply (default "", no seat)
+func SpectateAs(viewer string) SpectatorOption {
+	return func(s *Spectator) {
+		s.viewer = viewer
+	}
+}
+
+// WithSpectatorClock sets the time source for DelayTime (for tests)
+func WithSpectatorClock(now func() time.Time) SpectatorOption {
+	return func(s *Spectator) {
+		s.now = now
+	}
+}
+
+// Spectate creates a spectator. With DelayTime, it registers a listener to
+// timestamp commits; events committed before it was created count as old.
+func (e *Engine) Spectate(opts ...SpectatorOption) *Spectator {
+	s := &Spectator{
+		engine: e,
+		now:    time.Now,
+		base:   len(e.GetEvents()),
+	}
+
+	// Apply options
+	for _, opt := range opts {
+		opt(s)
+	}
+
+	if s.delay > 0 {
+		s.clock = &spectatorClock{spectator: s}
+		e.ThenAll(s.clock)
+	}
+	return s
+}
+
+// Close unregisters the spectator's clock, if it has one. The spectator
+// can still be read, but stops seeing events committed after Close.
+func (s *Spectator) Close() {
+	if s.clock != nil {
+		s.engine.RemoveListener(AnyEvent, s.clock)
+		s.clock = nil
+	}
+}
+
+// Visible returns how many events (from the start of the log) the spectator may see
+func (s *Spectator) Visible() int {
+	visible := len(s.engine.GetEvents()) - s.delayEvents
+
+	if s.delay > 0 {
+		s.mu.Lock()
+		cutoff := s.now().Add(-s.delay)
+		old := s.base
+		for _, at := range s.committed {
+			if at.After(cutoff) {
+				break
+			}
+			old++
+		}
+		s.mu.Unlock()
+		if old < visible {
+			visible = old
+		}
+	}
+
+	if visible < 0 {
+		return 0
+	}
+	return visible
+}
+
+// Events returns the visible events, masked for the spectator's viewer
+func (s *Spectator) Events() []Event {
+	events := s.engine.GetEvents()[:s.Visible()]
+	var visible []Event
+	for _, event := range events {
+		if masked, ok := s.engine.EventFor(s.viewer, event); ok {
+			visible = append(visible, masked)
+		}
+	}
+	return visible
+}
+
+// State returns a state projected from the visible events, masked for the
+// spectator's viewer. The projection is kept until the engine or the
+// visible window changes.
+func (s *Spectator) State(name string) interface{} {
+	events := s.engine.GetEvents()
+	visible := s.Visible()
+	if visible == len(events) {
+		return s.engine.GetStateFor(s.viewer, name)
+	}
+
+	if s.past == nil || s.pastRevision != s.engine.revision || s.pastVisible != visible {
+		s.past = s.engine.forkWith(events[:visible])
+		s.pastRevision, s.pastVisible = s.engine.revision, visible
+	}
+	return s.past.GetStateFor(s.viewer, name)
+}
+
+// spectatorClock timestamps commits for DelayTime
+type spectatorClock struct {
+	spectator *Spectator
+}
+
+// Handle implements EventListener
+func (c *spectatorClock) Handle(engine types.Engine, event Event) {
+	s := c.spectator
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.committed = append(s.committed, s.now())
+}
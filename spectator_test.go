@@ -0,0 +1,101 @@
+package atmos
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// newTallyEngine counts card_drawn events, hiding drawn cards from non-owners
+func newTallyEngine() *Engine {
+	engine := NewEngine()
+	engine.RegisterState("draws", 0)
+	engine.When("card_drawn").
+		Masked(RedactFields(drawingPlayer, "Card")).
+		Updates("draws", func(e *Engine, state interface{}, event Event) interface{} {
+			return state.(int) + 1
+		})
+	return engine
+}
+
+// TestSpectatorDelaysByEvents verifies the newest events are hidden and data is masked
+func TestSpectatorDelaysByEvents(t *testing.T) {
+	engine := newTallyEngine()
+	spectator := engine.Spectate(DelayEvents(2))
+
+	engine.Emit(CardDrawnEvent{Player: "alice", Card: "ace"})
+	assert.Equal(t, 0, spectator.Visible())
+	assert.Equal(t, 0, spectator.State("draws"))
+
+	engine.Emit(CardDrawnEvent{Player: "bob", Card: "king"})
+	engine.Emit(CardDrawnEvent{Player: "alice", Card: "queen"})
+	assert.Equal(t, 1, spectator.Visible())
+	assert.Equal(t, 1, spectator.State("draws"))
+	assert.Equal(t, []Event{CardDrawnEvent{Player: "alice"}}, spectator.Events())
+	assert.Equal(t, 3, engine.GetState("draws"))
+
+	seated := engine.Spectate(SpectateAs("alice"))
+	assert.Equal(t, "queen", seated.Events()[2].(CardDrawnEvent).Card, "A viewer sees their own cards")
+}
+
+// TestSpectatorDelaysByTime verifies events appear once they are old enough
+func TestSpectatorDelaysByTime(t *testing.T) {
+	now := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	engine := newTallyEngine()
+	engine.Emit(CardDrawnEvent{Player: "alice"})
+
+	spectator := engine.Spectate(DelayTime(30*time.Second), WithSpectatorClock(func() time.Time { return now }))
+	assert.Equal(t, 1, spectator.Visible(), "Events from before spectating began are old")
+
+	engine.Emit(CardDrawnEvent{Player: "bob"})
+	now = now.Add(10 * time.Second)
+	engine.Emit(CardDrawnEvent{Player: "carol"})
+	assert.Equal(t, 1, spectator.Visible())
+
+	now = now.Add(25 * time.Second)
+	assert.Equal(t, 2, spectator.Visible())
+	assert.Equal(t, 2, spectator.State("draws"))
+
+	now = now.Add(time.Hour)
+	assert.Equal(t, 3, spectator.Visible())
+}
+
+// TestSpectatorsAreNotDuplicates verifies identical spectators each get their own clock
+func TestSpectatorsAreNotDuplicates(t *testing.T) {
+	engine := NewEngine(WithDuplicatePolicy(DuplicatesError))
+	engine.Spectate(DelayTime(time.Second))
+	assert.NotPanics(t, func() { engine.Spectate(DelayTime(time.Second)) })
+}
+
+// TestSpectatorCachesPastProjection verifies reads reuse the projection until the engine or window changes
+func TestSpectatorCachesPastProjection(t *testing.T) {
+	engine := newTallyEngine()
+	spectator := engine.Spectate(DelayEvents(1))
+	engine.Emit(CardDrawnEvent{Player: "alice"})
+	engine.Emit(CardDrawnEvent{Player: "bob"})
+
+	assert.Equal(t, 1, spectator.State("draws"))
+	past := spectator.past
+	assert.Equal(t, 1, spectator.State("draws"))
+	assert.Same(t, past, spectator.past, "Nothing changed, so nothing was forked")
+
+	engine.Emit(CardDrawnEvent{Player: "carol"})
+	assert.Equal(t, 2, spectator.State("draws"))
+	assert.NotSame(t, past, spectator.past)
+}
+
+// TestSpectatorCloseUnregistersClock verifies closed spectators stop timestamping commits
+func TestSpectatorCloseUnregistersClock(t *testing.T) {
+	engine := newTallyEngine()
+	spectator := engine.Spectate(DelayTime(time.Second))
+	assert.Len(t, engine.listeners[AnyEvent], 1)
+
+	spectator.Close()
+	spectator.Close()
+	assert.Empty(t, engine.listeners[AnyEvent])
+	engine.Emit(CardDrawnEvent{Player: "alice"})
+	assert.Empty(t, spectator.committed)
+
+	assert.False(t, engine.RemoveListener(AnyEvent, &spectatorClock{}), "Only the registered value matches")
+}
@@ -0,0 +1,60 @@
+package atmos
+
+import (
+	"time"
+
+	"github.com/cumulusrpg/atmos/types"
+)
+
+// Stats summarizes the event log, for dashboards and debugging
+type Stats struct {
+	Events    int             `json:"events"`
+	ByType    map[string]int  `json:"by_type"`
+	First     time.Time       `json:"first"`     // When the first event was committed; zero if unknown
+	Last      time.Time       `json:"last"`      // When the last event was committed; zero if unknown
+	LogBytes  int64           `json:"log_bytes"` // Stored size of the log; -1 if the repository doesn't report it
+	Snapshots map[string]bool `json:"snapshots"` // Per registered state, whether it has a snapshot (see SetSnapshot)
+}
+
+// Stats summarizes the event log in one pass. Commit times are known when
+// the repository stores event IDs generated as ULIDs (see WithEventIDs);
+// the log size when it implements types.SizedRepository.
+func (e *Engine) Stats() Stats {
+	stats := Stats{
+		ByType:    make(map[string]int),
+		LogBytes:  -1,
+		Snapshots: make(map[string]bool),
+	}
+
+	if identified, ok := e.repository.(types.IdentifiedRepository); ok {
+		envelopes := identified.Envelopes(e)
+		for _, envelope := range envelopes {
+			stats.count(envelope.Event)
+		}
+		if len(envelopes) > 0 {
+			stats.First, _ = ULIDTime(envelopes[0].ID)
+			stats.Last, _ = ULIDTime(envelopes[len(envelopes)-1].ID)
+		}
+	} else {
+		e.eachEvent(func(event Event) bool {
+			stats.count(event)
+			return true
+		})
+	}
+
+	if sized, ok := e.repository.(types.SizedRepository); ok {
+		if size, err := sized.LogSize(); err == nil {
+			stats.LogBytes = size
+		}
+	}
+	for _, state := range e.StateNames() {
+		stats.Snapshots[state] = e.HasSnapshot(state)
+	}
+	return stats
+}
+
+// count adds an event to the totals
+func (s *Stats) count(event Event) {
+	s.Events++
+	s.ByType[event.Type()]++
+}
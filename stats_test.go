@@ -0,0 +1,53 @@
+package atmos
+
+import (
+	"testing"
+	"time"
+
+	"github.com/cumulusrpg/atmos/repository"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// sizedRepository reports a fixed log size
+type sizedRepository struct {
+	*repository.InMemorySnapshot
+}
+
+func (sizedRepository) LogSize() (int64, error) { return 2048, nil }
+
+// TestStatsSummarizesLog verifies counts, sizes, and snapshot presence
+func TestStatsSummarizesLog(t *testing.T) {
+	engine := newTokenEngine(WithRepository(sizedRepository{repository.NewInMemorySnapshot()}))
+	engine.RegisterState("pot", 0)
+	engine.Emit(TokensSpentEvent{Amount: 1})
+	engine.Emit(TokensSpentEvent{Amount: 1})
+	engine.Emit(TestEvent{Name: "a"})
+	require.NoError(t, engine.SetSnapshot("pot", 5))
+
+	assert.Equal(t, Stats{
+		Events:    3,
+		ByType:    map[string]int{"tokens_spent": 2, "test_event": 1},
+		LogBytes:  2048,
+		Snapshots: map[string]bool{"balance": false, "pot": true},
+	}, engine.Stats())
+}
+
+// TestStatsCommitTimes verifies first and last commit times are read from ULID event IDs
+func TestStatsCommitTimes(t *testing.T) {
+	clock := time.UnixMilli(1700000000000)
+	engine := newTokenEngine(
+		WithRepository(repository.NewIdentified()),
+		WithEventIDs(NewULIDGenerator(func() time.Time { return clock }, nil)),
+	)
+	assert.Equal(t, Stats{ByType: map[string]int{}, LogBytes: -1, Snapshots: map[string]bool{"balance": false}}, engine.Stats())
+
+	engine.Emit(TokensSpentEvent{Amount: 1})
+	clock = clock.Add(time.Minute)
+	engine.Emit(TokensSpentEvent{Amount: 1})
+
+	stats := engine.Stats()
+	assert.Equal(t, 2, stats.Events)
+	assert.True(t, time.UnixMilli(1700000000000).Equal(stats.First))
+	assert.True(t, clock.Equal(stats.Last))
+}
@@ -0,0 +1,68 @@
+package atmos
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/cumulusrpg/atmos/types"
+)
+
+// ErrStorageFull is reported by repositories with no room for a write
+var ErrStorageFull = types.ErrStorageFull
+
+// ErrConflict is reported by repositories when another writer changed the
+// log first
+var ErrConflict = types.ErrConflict
+
+// RepositoryError is a failure to store events, as opposed to a rejection
+// (see EmitResult.StorageFailed). Test the cause with errors.Is, e.g.
+// against ErrStorageFull or ErrConflict.
+type RepositoryError struct {
+	Op  string // "add" for Emit, "commit" for EmitAll, "set" for SetEventsE
+	Err error
+}
+
+// Error implements error, with the repository's message unchanged
+func (e *RepositoryError) Error() string {
+	return e.Err.Error()
+}
+
+// Unwrap returns the repository's error
+func (e *RepositoryError) Unwrap() error {
+	return e.Err
+}
+
+// StorageFailed reports whether the event passed validation but the
+// repository failed to store it (Err is then a *RepositoryError)
+func (r EmitResult) StorageFailed() bool {
+	var repositoryErr *RepositoryError
+	return errors.As(r.Err, &repositoryErr)
+}
+
+// SetEventsE replaces the log like SetEvents, but returns failures instead
+// of panicking, for logs from untrusted sources: every nil event (joined,
+// leaving the log untouched), or the repository's *RepositoryError
+func (e *Engine) SetEventsE(events []Event) error {
+	var errs []error
+	for i, event := range events {
+		if event == nil {
+			errs = append(errs, fmt.Errorf("event %d is nil", i))
+		}
+	}
+	if len(errs) > 0 {
+		return errors.Join(errs...)
+	}
+
+	var err error
+	if identified, ok := e.repository.(types.IdentifiedRepository); ok {
+		err = identified.SetEnvelopes(e, e.identify(identified.Envelopes(e), events))
+	} else {
+		err = e.repository.SetAll(e, events)
+	}
+	if err != nil {
+		return &RepositoryError{Op: "set", Err: err}
+	}
+	e.adoptQuarantine(events)
+	e.loaded(len(events))
+	return nil
+}
@@ -0,0 +1,86 @@
+package atmos
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/cumulusrpg/atmos/types"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// quotaRepository runs out of room after a number of events
+type quotaRepository struct {
+	events []Event
+	quota  int
+}
+
+func (r *quotaRepository) Add(engine types.Engine, event types.Event) error {
+	if len(r.events) >= r.quota {
+		return fmt.Errorf("quota of %d events: %w", r.quota, ErrStorageFull)
+	}
+	r.events = append(r.events, event)
+	return nil
+}
+
+func (r *quotaRepository) GetAll(engine types.Engine) []types.Event {
+	return append([]types.Event{}, r.events...)
+}
+
+func (r *quotaRepository) SetAll(engine types.Engine, events []types.Event) error {
+	if len(events) > r.quota {
+		return ErrStorageFull
+	}
+	r.events = append([]types.Event{}, events...)
+	return nil
+}
+
+// TestEmitSurfacesRepositoryErrors verifies storage failures are told apart from rejections and from each other
+func TestEmitSurfacesRepositoryErrors(t *testing.T) {
+	engine := NewEngine(WithRepository(&quotaRepository{quota: 1}))
+	engine.RegisterValidator("test_event", rejectNamed("banned"))
+
+	assert.False(t, engine.EmitWithResult(TestEvent{Name: "banned"}).StorageFailed(), "Rejections aren't storage failures")
+	require.True(t, engine.Emit(TestEvent{Name: "first"}))
+
+	result := engine.EmitWithResult(TestEvent{Name: "second"})
+	assert.True(t, result.StorageFailed())
+	assert.ErrorIs(t, result.Err, ErrStorageFull)
+	assert.NotErrorIs(t, result.Err, ErrConflict)
+	var repositoryErr *RepositoryError
+	require.ErrorAs(t, result.Err, &repositoryErr)
+	assert.Equal(t, "add", repositoryErr.Op)
+	assert.Equal(t, "quota of 1 events: storage full", result.Err.Error())
+
+	result = engine.EmitAll(TestEvent{Name: "third"})
+	assert.True(t, result.StorageFailed())
+	assert.ErrorIs(t, result.Err, ErrStorageFull)
+
+	assert.False(t, NewEngine(WithReadOnly()).EmitWithResult(TestEvent{}).StorageFailed(), "Engine errors aren't storage failures")
+}
+
+// rejectNamed rejects test events with a name
+type rejectNamed string
+
+func (r rejectNamed) Validate(engine types.Engine, event Event) bool {
+	return event.(TestEvent).Name != string(r)
+}
+
+// TestSetEventsEReportsFailures verifies SetEventsE returns errors where SetEvents panics
+func TestSetEventsEReportsFailures(t *testing.T) {
+	engine := NewEngine(WithRepository(&quotaRepository{quota: 2}))
+
+	err := engine.SetEventsE([]Event{nil, TestEvent{Name: "ok"}, nil})
+	assert.EqualError(t, err, "event 0 is nil\nevent 2 is nil")
+	assert.Empty(t, engine.GetEvents(), "Nothing is set")
+
+	err = engine.SetEventsE([]Event{TestEvent{}, TestEvent{}, TestEvent{}})
+	assert.ErrorIs(t, err, ErrStorageFull)
+	var repositoryErr *RepositoryError
+	require.ErrorAs(t, err, &repositoryErr)
+	assert.Equal(t, "set", repositoryErr.Op)
+	assert.Panics(t, func() { engine.SetEvents([]Event{TestEvent{}, TestEvent{}, TestEvent{}}) })
+
+	require.NoError(t, engine.SetEventsE([]Event{TestEvent{Name: "a"}, TestEvent{Name: "b"}}))
+	assert.Len(t, engine.GetEvents(), 2)
+}
@@ -0,0 +1,85 @@
+package atmos
+
+import (
+	"errors"
+	"fmt"
+)
+
+// WithStrictEvents makes Emit fail with ErrUnknownEventType for event types
+// nothing is registered for (no validator, hook, listener, reducer, or
+// factory), so typos in event type strings are caught instead of silently
+// bypassing every validator. Wildcard (AnyEvent) registrations don't count.
+func WithStrictEvents() EngineOption {
+	return func(e *Engine) {
+		e.strictEvents = true
+	}
+}
+
+// WithStrictEventsPanic is WithStrictEvents for tests: Emit panics at the
+// offending event instead of returning an error
+func WithStrictEventsPanic() EngineOption {
+	return func(e *Engine) {
+		e.strictEvents = true
+		e.strictPanic = true
+	}
+}
+
+// IsRegistered reports whether anything is registered for an event type
+func (e *Engine) IsRegistered(eventType string) bool {
+	if len(e.validators[eventType]) > 0 || len(e.exceptions[eventType]) > 0 ||
+		len(e.beforeHooks[eventType]) > 0 || len(e.listeners[eventType]) > 0 ||
+		len(e.advisories[eventType]) > 0 {
+		return true
+	}
+	if _, exists := e.eventFactories[eventType]; exists {
+		return true
+	}
+	for _, registry := range e.states {
+		if _, exists := registry.Reducers[eventType]; exists {
+			return true
+		}
+	}
+	return false
+}
+
+// checkRegistered enforces strict mode for an event about to be emitted
+func (e *Engine) checkRegistered(event Event) error {
+	if e.IsRegistered(event.Type()) {
+		return nil
+	}
+	err := fmt.Errorf("%w %q: nothing is registered for it", ErrUnknownEventType, event.Type())
+	if e.strictPanic {
+		panic(err)
+	}
+	return err
+}
+
+// ErrMisconfigured is raised (as a panic) by engines with strict registration
+var ErrMisconfigured = errors.New("misconfigured registration")
+
+// WithStrictRegistration makes wiring mistakes panic with ErrMisconfigured at
+// setup time instead of being silently ignored: Updates() on a state that
+// isn't registered yet, an event type registered with factories for two
+// different Go types, and Except() for a validator that isn't registered for
+// the event type (exceptions must follow their validator's registration)
+func WithStrictRegistration() EngineOption {
+	return func(e *Engine) {
+		e.strictRegistration = true
+	}
+}
+
+// hasValidator reports whether a validator applies to an event type,
+// including global validators
+func (e *Engine) hasValidator(eventType string, validator EventValidator) bool {
+	for _, registered := range e.validatorsFor(eventType) {
+		if registered == validator {
+			return true
+		}
+	}
+	return false
+}
+
+// misconfigured panics with a strict registration error
+func (e *Engine) misconfigured(format string, args ...interface{}) {
+	panic(fmt.Errorf("%w: %s", ErrMisconfigured, fmt.Sprintf(format, args...)))
+}
@@ -0,0 +1,78 @@
+package atmos
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+type TypoEvent struct{}
+
+func (e TypoEvent) Type() string { return "mvoe" }
+
+// TestStrictEventsRejectsUnregisteredTypes verifies typos fail instead of bypassing validators
+func TestStrictEventsRejectsUnregisteredTypes(t *testing.T) {
+	engine := NewEngine(WithStrictEvents())
+	engine.When(AnyEvent).Then(&typeRecorder{})
+	engine.When("move").Requires(&PolicyValidator{Name: "anyone", Policy: ActorIn("alice")})
+	engine.RegisterState("passes", 0)
+	engine.When("pass").Updates("passes", func(e *Engine, state interface{}, event Event) interface{} {
+		return state.(int) + 1
+	})
+
+	assert.True(t, engine.EmitAs("alice", MoveEvent{Player: "alice"}))
+	assert.True(t, engine.Emit(PassEvent{}), "A reducer counts as a registration")
+
+	result := engine.EmitWithResult(TypoEvent{})
+	assert.False(t, result.Accepted)
+	assert.ErrorIs(t, result.Err, ErrUnknownEventType)
+	assert.Contains(t, result.Err.Error(), `"mvoe"`)
+	assert.Len(t, engine.GetEvents(), 2)
+
+	assert.True(t, NewEngine().Emit(TypoEvent{}), "Engines are lenient by default")
+}
+
+// TestStrictEventsPanic verifies the test-oriented variant panics at the offending emit
+func TestStrictEventsPanic(t *testing.T) {
+	engine := NewEngine(WithStrictEventsPanic())
+	assert.PanicsWithError(t, `unknown event type "mvoe": nothing is registered for it`, func() {
+		engine.Emit(TypoEvent{})
+	})
+}
+
+// TestStrictRegistrationCatchesMisconfigurations verifies wiring mistakes panic at setup
+func TestStrictRegistrationCatchesMisconfigurations(t *testing.T) {
+	engine := NewEngine(WithStrictRegistration())
+	count := func(e *Engine, state interface{}, event Event) interface{} { return state.(int) + 1 }
+
+	assert.PanicsWithError(t, `misconfigured registration: reducer for pass updates state "passes", which is not registered`, func() {
+		engine.When("pass").Updates("passes", count)
+	})
+	engine.RegisterState("passes", 0)
+	assert.NotPanics(t, func() { engine.When("pass").Updates("passes", count) })
+
+	engine.RegisterEventTypes(&MoveEvent{})
+	assert.NotPanics(t, func() { engine.RegisterEventTypes(MoveEvent{}) }, "The same type again is harmless")
+	assert.PanicsWithError(t, "misconfigured registration: event type move is registered as both *atmos.MoveEvent and atmos.TypoEvent", func() {
+		engine.RegisterEventType("move", func() Event { return TypoEvent{} })
+	})
+
+	anyone := &PolicyValidator{Name: "anyone", Policy: ActorIn("alice")}
+	never := func(e *Engine, event Event) bool { return false }
+	assert.PanicsWithError(t, `misconfigured registration: exception "never" for move skips policy: anyone, which is not registered for it`, func() {
+		engine.When("move").Except(anyone, never, "never")
+	})
+	assert.NotPanics(t, func() { engine.When("move").Requires(anyone).Except(anyone, never, "never") })
+}
+
+// TestRegistrationIsLenientByDefault verifies misconfigurations are ignored without the option
+func TestRegistrationIsLenientByDefault(t *testing.T) {
+	engine := NewEngine()
+	assert.NotPanics(t, func() {
+		engine.When("pass").Updates("passes", func(e *Engine, state interface{}, event Event) interface{} { return state })
+		engine.RegisterEventTypes(MoveEvent{})
+		engine.RegisterEventType("move", func() Event { return TypoEvent{} })
+		engine.When("move").Except(&PolicyValidator{Name: "anyone"}, nil, "never")
+	})
+	assert.Nil(t, engine.GetState("passes"))
+}
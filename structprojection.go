@@ -0,0 +1,133 @@
+package atmos
+
+import (
+	"fmt"
+	"reflect"
+	"sort"
+	"strings"
+)
+
+var eventInterface = reflect.TypeOf((*Event)(nil)).Elem()
+
+// RegisterProjection registers a state whose reducers are the Apply methods
+// of its struct type, one per event type:
+//
+//	type Board struct{ Moves int }
+//
+//	func (b *Board) ApplyMoveMade(e MoveMadeEvent) { b.Moves++ }
+//	func (b Board) ApplyGameReset(e GameResetEvent) Board { return Board{} }
+//
+//	engine.RegisterProjection("board", Board{})
+//
+// Each method whose name starts with "Apply" and takes a single event
+// becomes the reducer for that event's Type(), whether the log holds the
+// event as a value or a pointer. Pointer-receiver methods update a copy of
+// the state, so they must replace maps and slices rather than modify them;
+// value-receiver methods return the new state. The state is stored as a
+// struct value, even when initial is a pointer.
+func (e *Engine) RegisterProjection(name string, initial interface{}) error {
+	value := reflect.ValueOf(initial)
+	if value.Kind() == reflect.Ptr {
+		value = value.Elem()
+	}
+	if value.Kind() != reflect.Struct {
+		return fmt.Errorf("projection %q: %T is not a struct", name, initial)
+	}
+	structType := value.Type()
+
+	reducers := make(map[string]StateReducer)
+	var problems []string
+	for _, methodType := range []reflect.Type{structType, reflect.PointerTo(structType)} {
+		for i := 0; i < methodType.NumMethod(); i++ {
+			method := methodType.Method(i)
+			if !strings.HasPrefix(method.Name, "Apply") {
+				continue
+			}
+			if _, valueMethod := structType.MethodByName(method.Name); valueMethod && methodType != structType {
+				continue // Value methods also appear in the pointer method set
+			}
+			reducer, handles, err := projectionReducer(structType, method)
+			if err != nil {
+				problems = append(problems, err.Error())
+				continue
+			}
+			if _, exists := reducers[handles]; exists {
+				problems = append(problems, fmt.Sprintf("%s handles %s twice", method.Name, handles))
+				continue
+			}
+			reducers[handles] = reducer
+		}
+	}
+	if len(problems) > 0 {
+		sort.Strings(problems)
+		return fmt.Errorf("projection %q: %s", name, strings.Join(problems, "; "))
+	}
+	if len(reducers) == 0 {
+		return fmt.Errorf("projection %q: %s has no Apply methods", name, structType.Name())
+	}
+
+	e.RegisterState(name, value.Interface())
+	for handles, reducer := range reducers {
+		e.When(handles).Updates(name, reducer)
+	}
+	return nil
+}
+
+// projectionReducer adapts an Apply method to a StateReducer, returning the
+// event type it handles
+func projectionReducer(structType reflect.Type, method reflect.Method) (StateReducer, string, error) {
+	fn := method.Type // receiver is the first parameter
+	if fn.NumIn() != 2 || !fn.In(1).Implements(eventInterface) {
+		return nil, "", fmt.Errorf("%s must take a single event", method.Name)
+	}
+	pointerReceiver := fn.In(0).Kind() == reflect.Ptr
+	switch {
+	case pointerReceiver && fn.NumOut() != 0:
+		return nil, "", fmt.Errorf("%s has a pointer receiver and must not return anything", method.Name)
+	case !pointerReceiver && (fn.NumOut() != 1 || fn.Out(0) != structType):
+		return nil, "", fmt.Errorf("%s has a value receiver and must return %s", method.Name, structType.Name())
+	}
+
+	param := fn.In(1)
+	handles := zeroEvent(param).Type()
+
+	reducer := func(engine *Engine, state interface{}, event Event) interface{} {
+		arg, ok := eventArgument(reflect.ValueOf(event), param)
+		if !ok {
+			return state // A different Go type sharing the event type name
+		}
+
+		if pointerReceiver {
+			copied := reflect.New(structType)
+			copied.Elem().Set(reflect.ValueOf(state))
+			method.Func.Call([]reflect.Value{copied, arg})
+			return copied.Elem().Interface()
+		}
+		return method.Func.Call([]reflect.Value{reflect.ValueOf(state), arg})[0].Interface()
+	}
+	return reducer, handles, nil
+}
+
+// zeroEvent returns an empty event of a type (a new pointer for pointer types)
+func zeroEvent(t reflect.Type) Event {
+	if t.Kind() == reflect.Ptr {
+		return reflect.New(t.Elem()).Interface().(Event)
+	}
+	return reflect.Zero(t).Interface().(Event)
+}
+
+// eventArgument converts an event to the parameter type, dereferencing or
+// taking the address of a copy as needed
+func eventArgument(event reflect.Value, param reflect.Type) (reflect.Value, bool) {
+	switch {
+	case event.Type() == param:
+		return event, true
+	case event.Kind() == reflect.Ptr && event.Type().Elem() == param:
+		return event.Elem(), true
+	case param.Kind() == reflect.Ptr && param.Elem() == event.Type():
+		copied := reflect.New(event.Type())
+		copied.Elem().Set(event)
+		return copied, true
+	}
+	return reflect.Value{}, false
+}
@@ -0,0 +1,73 @@
+package atmos
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// Scoreboard is a struct projection over rounds and resets
+type Scoreboard struct {
+	Rounds int
+	Wins   map[string]int
+}
+
+func (s *Scoreboard) ApplyRoundPlayed(e RoundPlayedEvent) {
+	wins := map[string]int{}
+	for player, n := range s.Wins {
+		wins[player] = n
+	}
+	wins[e.Winner]++
+	s.Rounds++
+	s.Wins = wins
+}
+
+func (s Scoreboard) ApplyTokensSpent(e *TokensSpentEvent) Scoreboard {
+	s.Rounds += e.Amount * 100
+	return s
+}
+
+// String isn't an Apply method, so it is ignored
+func (s Scoreboard) String() string { return "scoreboard" }
+
+// TestStructProjectionDispatchesByEvent verifies Apply methods become reducers
+func TestStructProjectionDispatchesByEvent(t *testing.T) {
+	engine := NewEngine()
+	require.NoError(t, engine.RegisterProjection("scoreboard", &Scoreboard{Wins: map[string]int{}}))
+	engine.RegisterEventTypes(RoundPlayedEvent{})
+
+	engine.Emit(RoundPlayedEvent{Winner: "alice"})
+	engine.Emit(RoundPlayedEvent{Winner: "alice"})
+	engine.Emit(TokensSpentEvent{Amount: 1})
+	restored, err := engine.UnmarshalEvent([]byte(`{"type":"round_played","data":{"winner":"bob"}}`))
+	require.NoError(t, err)
+	engine.Emit(restored)
+
+	assert.Equal(t, Scoreboard{Rounds: 103, Wins: map[string]int{"alice": 2, "bob": 1}}, engine.GetState("scoreboard"))
+	assert.Equal(t, Scoreboard{Wins: map[string]int{}}, engine.states["scoreboard"].InitialState,
+		"The initial state is never mutated")
+}
+
+// BadProjection has Apply methods with the wrong shapes
+type BadProjection struct{}
+
+func (b *BadProjection) ApplyNothing()                                  {}
+func (b *BadProjection) ApplyRoundPlayed(e RoundPlayedEvent) int        { return 0 }
+func (b BadProjection) ApplyTokensSpent(e TokensSpentEvent)             {}
+func (b *BadProjection) ApplyTwoEvents(e RoundPlayedEvent, f TestEvent) {}
+
+// TestStructProjectionRejectsBadMethods verifies malformed projections are reported, not registered
+func TestStructProjectionRejectsBadMethods(t *testing.T) {
+	engine := NewEngine()
+
+	err := engine.RegisterProjection("bad", BadProjection{})
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "ApplyNothing must take a single event")
+	assert.Contains(t, err.Error(), "ApplyRoundPlayed has a pointer receiver and must not return anything")
+	assert.Contains(t, err.Error(), "ApplyTokensSpent has a value receiver and must return BadProjection")
+	assert.Nil(t, engine.GetState("bad"))
+
+	assert.ErrorContains(t, engine.RegisterProjection("empty", struct{}{}), "no Apply methods")
+	assert.ErrorContains(t, engine.RegisterProjection("number", 5), "not a struct")
+}
@@ -0,0 +1,33 @@
+package atmos
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"time"
+)
+
+// ErrInvalidInterval is returned for periods that aren't positive
+var ErrInvalidInterval = errors.New("interval must be positive")
+
+// RunTicks calls tick every interval until ctx is done, holding locker
+// around each call so the engine can be shared with request handlers. It
+// backs the Run loops of clock-driven modules such as timers and scheduler.
+func RunTicks(ctx context.Context, locker sync.Locker, interval time.Duration, tick func()) error {
+	if interval <= 0 {
+		return ErrInvalidInterval
+	}
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-ticker.C:
+			locker.Lock()
+			tick()
+			locker.Unlock()
+		}
+	}
+}
@@ -0,0 +1,281 @@
+// Package timers adds countdowns (turn timers, auction clocks) whose whole
+// lifecycle is recorded as events, so time-limited turns survive restarts
+// and replay deterministically:
+//
+//	clock := timers.New()
+//	clock.Install(engine)
+//	clock.Start(engine, "turn:alice", "turn", 30*time.Second)
+//	go clock.Run(ctx, engine, lock, time.Second)
+//
+//	engine.When(timers.ExpiredEventType).Then(Do(&ForfeitTurn{}))
+//
+// Deadlines are absolute, and expiries are only ever produced by Tick (at
+// most once per timer, with the clock checked by a validator), so replaying
+// the log restores exactly the timers that were still pending — cancelled
+// and expired timers never fire again.
+package timers
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/cumulusrpg/atmos"
+	"github.com/cumulusrpg/atmos/types"
+)
+
+// Event types
+const (
+	StartedEventType   = "timer_started"
+	CancelledEventType = "timer_cancelled"
+	ExpiredEventType   = "timer_expired"
+)
+
+// Timer is a pending countdown
+type Timer struct {
+	ID       string    `json:"id"`
+	Kind     string    `json:"kind,omitempty"` // Free-form label for listeners, e.g. "turn"
+	Deadline time.Time `json:"deadline"`
+	Timers   string    `json:"timers,omitempty"` // The timer set's state name ("" for the default set)
+}
+
+// StartedEvent starts a timer
+type StartedEvent struct {
+	Timer
+}
+
+// Type implements atmos.Event
+func (e StartedEvent) Type() string { return StartedEventType }
+
+// CancelledEvent stops a pending timer
+type CancelledEvent struct {
+	ID     string `json:"id"`
+	Timers string `json:"timers,omitempty"`
+}
+
+// Type implements atmos.Event
+func (e CancelledEvent) Type() string { return CancelledEventType }
+
+// ExpiredEvent records that a timer reached its deadline
+type ExpiredEvent struct {
+	Timer
+}
+
+// Type implements atmos.Event
+func (e ExpiredEvent) Type() string { return ExpiredEventType }
+
+// Pending is the state of the pending timers, by ID. Starting, cancelling or
+// expiring a timer builds a new map, leaving earlier ones as they were.
+type Pending map[string]Timer
+
+// Timers installs countdowns on an engine
+type Timers struct {
+	name string
+	now  func() time.Time
+}
+
+// Option configures timers construction
+type Option func(*Timers)
+
+// WithClock sets the time source (for tests and simulations)
+func WithClock(now func() time.Time) Option {
+	return func(t *Timers) {
+		t.now = now
+	}
+}
+
+// WithStateName sets the name of the pending timers state (default "timers").
+// The timers' events carry this name, so each set only sees its own.
+func WithStateName(name string) Option {
+	return func(t *Timers) {
+		t.name = name
+	}
+}
+
+// New creates timers
+func New(opts ...Option) *Timers {
+	t := &Timers{
+		name: "timers",
+		now:  time.Now,
+	}
+
+	// Apply options
+	for _, opt := range opts {
+		opt(t)
+	}
+
+	return t
+}
+
+// Install registers the timer event types, the pending timers state, and validators
+func (t *Timers) Install(engine *atmos.Engine) {
+	engine.RegisterState(t.name, Pending{})
+
+	factories := []func() atmos.Event{
+		func() atmos.Event { return &StartedEvent{} },
+		func() atmos.Event { return &CancelledEvent{} },
+		func() atmos.Event { return &ExpiredEvent{} },
+	}
+	for _, factory := range factories {
+		engine.When(factory().Type(), factory).
+			Requires(&timerValidator{timers: t}).
+			Updates(t.name, t.reduce)
+	}
+}
+
+// Start emits a StartedEvent for a timer expiring after d
+func (t *Timers) Start(engine *atmos.Engine, id, kind string, d time.Duration) bool {
+	return engine.Emit(StartedEvent{Timer{ID: id, Kind: kind, Deadline: t.now().Add(d), Timers: t.set()}})
+}
+
+// Cancel emits a CancelledEvent for a pending timer
+func (t *Timers) Cancel(engine *atmos.Engine, id string) bool {
+	return engine.Emit(CancelledEvent{ID: id, Timers: t.set()})
+}
+
+// Pending returns the pending timers
+func (t *Timers) Pending(engine *atmos.Engine) Pending {
+	return engine.GetState(t.name).(Pending)
+}
+
+// Remaining returns the time left on a pending timer
+func (t *Timers) Remaining(engine *atmos.Engine, id string) (time.Duration, bool) {
+	timer, exists := t.Pending(engine)[id]
+	if !exists {
+		return 0, false
+	}
+	if remaining := timer.Deadline.Sub(t.now()); remaining > 0 {
+		return remaining, true
+	}
+	return 0, true
+}
+
+// Next returns the earliest pending deadline
+func (t *Timers) Next(engine *atmos.Engine) (time.Time, bool) {
+	due := t.sorted(t.Pending(engine))
+	if len(due) == 0 {
+		return time.Time{}, false
+	}
+	return due[0].Deadline, true
+}
+
+// Tick emits an ExpiredEvent for every pending timer whose deadline has
+// passed, earliest first, returning the IDs of the timers that expired
+func (t *Timers) Tick(engine *atmos.Engine) []string {
+	now := t.now()
+	var expired []string
+	for _, timer := range t.sorted(t.Pending(engine)) {
+		if timer.Deadline.After(now) {
+			break
+		}
+		if engine.Emit(ExpiredEvent{timer}) {
+			expired = append(expired, timer.ID)
+		}
+	}
+	return expired
+}
+
+// Run calls Tick every interval until ctx is done, holding locker around
+// each tick so the engine can be shared with request handlers
+func (t *Timers) Run(ctx context.Context, engine *atmos.Engine, locker sync.Locker, interval time.Duration) error {
+	return atmos.RunTicks(ctx, locker, interval, func() { t.Tick(engine) })
+}
+
+// set returns the name events of this set carry in their Timers field
+func (t *Timers) set() string {
+	if t.name == "timers" {
+		return ""
+	}
+	return t.name
+}
+
+// owns reports whether an event's Timers field refers to this set
+func (t *Timers) owns(event atmos.Event) bool {
+	switch e := atmos.Deref(event).(type) {
+	case StartedEvent:
+		return e.Timers == t.set()
+	case CancelledEvent:
+		return e.Timers == t.set()
+	case ExpiredEvent:
+		return e.Timers == t.set()
+	}
+	return false
+}
+
+// sorted orders timers by deadline, then ID
+func (t *Timers) sorted(pending Pending) []Timer {
+	timers := make([]Timer, 0, len(pending))
+	for _, timer := range pending {
+		timers = append(timers, timer)
+	}
+	sort.Slice(timers, func(i, j int) bool {
+		if !timers[i].Deadline.Equal(timers[j].Deadline) {
+			return timers[i].Deadline.Before(timers[j].Deadline)
+		}
+		return timers[i].ID < timers[j].ID
+	})
+	return timers
+}
+
+// reduce adds started timers and removes cancelled and expired ones
+func (t *Timers) reduce(engine *atmos.Engine, state interface{}, event atmos.Event) interface{} {
+	pending := state.(Pending)
+	if !t.owns(event) {
+		return pending
+	}
+	next := make(Pending, len(pending)+1)
+	for id, timer := range pending {
+		next[id] = timer
+	}
+
+	switch e := atmos.Deref(event).(type) {
+	case StartedEvent:
+		next[e.ID] = e.Timer
+	case CancelledEvent:
+		delete(next, e.ID)
+	case ExpiredEvent:
+		delete(next, e.ID)
+	}
+	return next
+}
+
+// timerValidator rejects restarting pending timers, touching unknown ones,
+// and expiring timers before their deadline
+type timerValidator struct {
+	timers *Timers
+}
+
+// Validate implements atmos.EventValidator
+func (v *timerValidator) Validate(engine types.Engine, event atmos.Event) bool {
+	return v.Check(engine, event) == nil
+}
+
+// Check implements atmos.ReasonedValidator
+func (v *timerValidator) Check(engine types.Engine, event atmos.Event) error {
+	if !v.timers.owns(event) {
+		return nil
+	}
+	pending := v.timers.Pending(engine.(*atmos.Engine))
+
+	switch e := atmos.Deref(event).(type) {
+	case StartedEvent:
+		if _, exists := pending[e.ID]; exists {
+			return fmt.Errorf("timer %q is already running", e.ID)
+		}
+	case CancelledEvent:
+		if _, exists := pending[e.ID]; !exists {
+			return fmt.Errorf("timer %q is not running", e.ID)
+		}
+	case ExpiredEvent:
+		timer, exists := pending[e.ID]
+		if !exists {
+			return fmt.Errorf("timer %q is not running", e.ID)
+		}
+		if timer.Deadline.After(v.timers.now()) {
+			return fmt.Errorf("timer %q has not reached its deadline", e.ID)
+		}
+	}
+	return nil
+}
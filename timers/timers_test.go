@@ -0,0 +1,143 @@
+package timers_test
+
+import (
+	"context"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/cumulusrpg/atmos"
+	"github.com/cumulusrpg/atmos/timers"
+	"github.com/cumulusrpg/atmos/types"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// expiryRecorder records the IDs of expired timers
+type expiryRecorder struct {
+	mu  sync.Mutex
+	ids []string
+}
+
+func (r *expiryRecorder) Handle(engine types.Engine, event atmos.Event) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.ids = append(r.ids, event.(timers.ExpiredEvent).ID)
+}
+
+func (r *expiryRecorder) IDs() []string {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return append([]string(nil), r.ids...)
+}
+
+// TestTimersExpireOnTick verifies timers expire in deadline order, once, and can be cancelled
+func TestTimersExpireOnTick(t *testing.T) {
+	now := time.Date(2024, 1, 1, 12, 0, 0, 0, time.UTC)
+	clock := timers.New(timers.WithClock(func() time.Time { return now }))
+	recorder := &expiryRecorder{}
+	engine := atmos.NewEngine()
+	clock.Install(engine)
+	engine.When(timers.ExpiredEventType).Then(recorder)
+
+	assert.True(t, clock.Start(engine, "turn:alice", "turn", 30*time.Second))
+	assert.True(t, clock.Start(engine, "auction", "auction", 10*time.Second))
+	assert.True(t, clock.Start(engine, "turn:bob", "turn", 20*time.Second))
+	assert.False(t, clock.Start(engine, "auction", "auction", time.Minute), "Already running")
+	assert.True(t, clock.Cancel(engine, "turn:bob"))
+
+	next, _ := clock.Next(engine)
+	assert.Equal(t, now.Add(10*time.Second), next)
+	assert.Empty(t, clock.Tick(engine))
+	assert.False(t, engine.Emit(timers.ExpiredEvent{Timer: timers.Timer{ID: "auction"}}), "Too early")
+
+	now = now.Add(25 * time.Second)
+	remaining, _ := clock.Remaining(engine, "turn:alice")
+	assert.Equal(t, 5*time.Second, remaining)
+
+	now = now.Add(time.Hour)
+	assert.Equal(t, []string{"auction", "turn:alice"}, clock.Tick(engine))
+	assert.Empty(t, clock.Tick(engine), "Timers expire once")
+	assert.Equal(t, []string{"auction", "turn:alice"}, recorder.IDs())
+	assert.Empty(t, clock.Pending(engine))
+}
+
+// TestTimersReplayRestoresPending verifies replays restore only pending timers, with their deadlines
+func TestTimersReplayRestoresPending(t *testing.T) {
+	now := time.Date(2024, 1, 1, 12, 0, 0, 0, time.UTC)
+	clock := timers.New(timers.WithClock(func() time.Time { return now }))
+	engine := atmos.NewEngine()
+	clock.Install(engine)
+	clock.Start(engine, "a", "turn", time.Second)
+	clock.Start(engine, "b", "turn", time.Minute)
+	clock.Start(engine, "c", "turn", time.Minute)
+	clock.Cancel(engine, "c")
+	now = now.Add(2 * time.Second)
+	clock.Tick(engine)
+
+	data, err := engine.MarshalEvents(engine.GetEvents())
+	require.NoError(t, err)
+
+	recorder := &expiryRecorder{}
+	restarted := atmos.NewEngine()
+	clock.Install(restarted)
+	restarted.When(timers.ExpiredEventType).Then(recorder)
+	events, err := restarted.UnmarshalEvents(data)
+	require.NoError(t, err)
+	restarted.SetEvents(events)
+
+	pending := clock.Pending(restarted)
+	assert.Len(t, pending, 1)
+	assert.True(t, pending["b"].Deadline.Equal(time.Date(2024, 1, 1, 12, 1, 0, 0, time.UTC)))
+
+	now = now.Add(time.Hour)
+	assert.Equal(t, []string{"b"}, clock.Tick(restarted), "Expired and cancelled timers don't fire again")
+}
+
+// TestTimersRun verifies the background loop ticks until cancelled
+func TestTimersRun(t *testing.T) {
+	clock := timers.New()
+	recorder := &expiryRecorder{}
+	engine := atmos.NewEngine()
+	clock.Install(engine)
+	engine.When(timers.ExpiredEventType).Then(recorder)
+
+	var lock sync.Mutex
+	lock.Lock()
+	clock.Start(engine, "soon", "turn", time.Millisecond)
+	lock.Unlock()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan error)
+	go func() { done <- clock.Run(ctx, engine, &lock, time.Millisecond) }()
+
+	assert.Eventually(t, func() bool { return len(recorder.IDs()) == 1 }, time.Second, time.Millisecond)
+	cancel()
+	assert.ErrorIs(t, <-done, context.Canceled)
+}
+
+// TestTimersSeparateSets verifies two timer sets on one engine only see their own events
+func TestTimersSeparateSets(t *testing.T) {
+	now := time.Date(2024, 1, 1, 12, 0, 0, 0, time.UTC)
+	clock := func() time.Time { return now }
+	turns := timers.New(timers.WithClock(clock))
+	auctions := timers.New(timers.WithClock(clock), timers.WithStateName("auctions"))
+	engine := atmos.NewEngine()
+	turns.Install(engine)
+	auctions.Install(engine)
+
+	require.True(t, turns.Start(engine, "lot:1", "turn", 10*time.Second))
+	require.True(t, auctions.Start(engine, "lot:1", "auction", time.Minute), "The same ID in another set")
+	assert.Len(t, turns.Pending(engine), 1)
+	assert.Len(t, auctions.Pending(engine), 1)
+
+	now = now.Add(30 * time.Second)
+	assert.Equal(t, []string{"lot:1"}, turns.Tick(engine))
+	assert.Empty(t, auctions.Tick(engine), "Still running")
+	assert.Empty(t, turns.Pending(engine))
+	assert.Contains(t, auctions.Pending(engine), "lot:1")
+
+	require.True(t, auctions.Cancel(engine, "lot:1"))
+	assert.Empty(t, auctions.Pending(engine))
+	assert.False(t, turns.Cancel(engine, "lot:1"), "Already expired in its own set")
+}
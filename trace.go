@@ -0,0 +1,200 @@
+package atmos
+
+import (
+	"encoding/json"
+	"io"
+	"runtime/pprof"
+	"strings"
+	"sync"
+	"time"
+)
+
+// Pipeline stages recorded by tracing (see Span)
+const (
+	StageEmit       = "emit"       // a whole Emit or EmitAll, including derived emits
+	StageValidator  = "validator"  // one validator, or a batch's validation
+	StageHook       = "hook"       // one before hook
+	StageRepository = "repository" // committing to the repository
+	StageListener   = "listener"   // one listener
+)
+
+// Span is one timed step of the emit pipeline
+type Span struct {
+	Stage     string        // One of the Stage constants
+	Name      string        // Event type for emits ("EmitAll" for batches), otherwise the validator, hook, or listener
+	EventType string        // Event being emitted (comma-separated for batches)
+	Start     time.Duration // Since the trace started
+	Duration  time.Duration
+	Depth     int // Nesting: 0 for an emit called from outside the engine
+}
+
+// Trace records emit pipeline timings, to see where a slow turn spends its
+// time. Record with WithTracing, then load WriteChromeTrace's output in
+// chrome://tracing or https://ui.perfetto.dev. Safe to read while the
+// engine records.
+type Trace struct {
+	mu    sync.Mutex
+	start time.Time
+	spans []Span
+	depth int
+	reset int // Reset count, so spans ending after a reset are dropped
+}
+
+// NewTrace creates an empty trace starting now
+func NewTrace() *Trace {
+	return &Trace{start: time.Now()}
+}
+
+// Spans returns the recorded spans in the order they started
+func (t *Trace) Spans() []Span {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	return append([]Span(nil), t.spans...)
+}
+
+// Reset discards the recorded spans
+func (t *Trace) Reset() {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	t.start = time.Now()
+	t.spans = nil
+	t.reset++
+}
+
+// begin records the start of a span, returning the function that ends it
+func (t *Trace) begin(stage, name, eventType string) func() {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	i, reset := len(t.spans), t.reset
+	t.spans = append(t.spans, Span{
+		Stage:     stage,
+		Name:      name,
+		EventType: eventType,
+		Start:     time.Since(t.start),
+		Depth:     t.depth,
+	})
+	t.depth++
+	return func() {
+		t.mu.Lock()
+		defer t.mu.Unlock()
+		t.depth--
+		if reset == t.reset {
+			t.spans[i].Duration = time.Since(t.start) - t.spans[i].Start
+		}
+	}
+}
+
+// chromeEvent is a complete event in the Chrome trace event format
+type chromeEvent struct {
+	Name      string            `json:"name"`
+	Category  string            `json:"cat"`
+	Phase     string            `json:"ph"`
+	Timestamp float64           `json:"ts"`  // Microseconds
+	Duration  float64           `json:"dur"` // Microseconds
+	PID       int               `json:"pid"`
+	TID       int               `json:"tid"`
+	Args      map[string]string `json:"args,omitempty"`
+}
+
+// WriteChromeTrace writes the spans as Chrome trace event JSON, which
+// trace viewers show as a flame chart
+func (t *Trace) WriteChromeTrace(w io.Writer) error {
+	spans := t.Spans()
+	events := make([]chromeEvent, len(spans))
+	for i, span := range spans {
+		events[i] = chromeEvent{
+			Name:      span.Name,
+			Category:  span.Stage,
+			Phase:     "X",
+			Timestamp: float64(span.Start) / float64(time.Microsecond),
+			Duration:  float64(span.Duration) / float64(time.Microsecond),
+			PID:       1,
+			TID:       1,
+		}
+		if span.Stage != StageEmit {
+			events[i].Args = map[string]string{"event": span.EventType}
+		}
+	}
+	return json.NewEncoder(w).Encode(struct {
+		TraceEvents []chromeEvent `json:"traceEvents"`
+	}{events})
+}
+
+// WithTracing records the timings of every emit's validators, before
+// hooks, repository commit, and listeners into a trace
+func WithTracing(trace *Trace) EngineOption {
+	return func(e *Engine) {
+		e.trace = trace
+	}
+}
+
+// WithProfilerLabels labels CPU profile samples taken during emits with
+// the pipeline step (pprof labels atmos.event, atmos.stage, and
+// atmos.step), so `go tool pprof -tagfocus` can isolate a slow listener.
+// Labels set on the EmitCtx context (see pprof.Do) are kept.
+func WithProfilerLabels() EngineOption {
+	return func(e *Engine) {
+		e.profilerLabels = true
+	}
+}
+
+// untraced ends a step that isn't being traced
+func untraced() {}
+
+// begin starts a traced step of the emit pipeline, returning the function
+// that ends it. The step is its name, or the validator or hook or listener,
+// whose name is only worked out when tracing.
+func (e *Engine) begin(stage string, step interface{}, eventType string) func() {
+	if e.trace == nil && !e.profilerLabels {
+		return untraced
+	}
+
+	name, named := step.(string)
+	if !named && stage == StageValidator {
+		name = validatorName(step.(EventValidator))
+	} else if !named {
+		name = listenerName(step.(EventListener))
+	}
+
+	endSpan := untraced
+	if e.trace != nil {
+		endSpan = e.trace.begin(stage, name, eventType)
+	}
+	if !e.profilerLabels {
+		return endSpan
+	}
+
+	previous := e.labels
+	base := previous
+	if base == nil {
+		base = e.Context()
+	}
+	e.labels = pprof.WithLabels(base, pprof.Labels("atmos.event", eventType, "atmos.stage", stage, "atmos.step", name))
+	pprof.SetGoroutineLabels(e.labels)
+	return func() {
+		endSpan()
+		e.labels = previous
+		if previous == nil {
+			previous = e.Context()
+		}
+		pprof.SetGoroutineLabels(previous)
+	}
+}
+
+// handle runs a listener for a committed event, traced
+func (e *Engine) handle(listener EventListener, event Event) {
+	defer e.begin(StageListener, listener, event.Type())()
+	listener.Handle(e, event)
+}
+
+// batchTypes names the event types of a batch for its spans, when tracing
+func (e *Engine) batchTypes(events []Event) string {
+	if e.trace == nil && !e.profilerLabels {
+		return ""
+	}
+	names := make([]string, len(events))
+	for i, event := range events {
+		names[i] = event.Type()
+	}
+	return strings.Join(names, ",")
+}
@@ -0,0 +1,107 @@
+package atmos
+
+import (
+	"bytes"
+	"encoding/json"
+	"runtime/pprof"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// spanSteps lists spans as stage, name, and depth
+func spanSteps(trace *Trace) [][3]interface{} {
+	var steps [][3]interface{}
+	for _, span := range trace.Spans() {
+		steps = append(steps, [3]interface{}{span.Stage, span.Name, span.Depth})
+	}
+	return steps
+}
+
+// TestTracingRecordsPipeline verifies each step of an emit and its derived emits is timed, nested
+func TestTracingRecordsPipeline(t *testing.T) {
+	trace := NewTrace()
+	var results []EmitResult
+	engine := newFanOutEngine(&results, WithTracing(trace))
+	engine.When("test_event").Requires(Policy("open"))
+	engine.RegisterPolicy("open", NewTypedValidator(TypedValidatorFunc[TestEvent](func(e *Engine, event TestEvent) bool { return true })))
+
+	require.True(t, engine.Emit(TestEvent{Name: "b1"}))
+	listener := listenerName(engine.listeners["test_event"][0])
+	assert.Equal(t, [][3]interface{}{
+		{StageEmit, "test_event", 0},
+		{StageValidator, "policy open", 1},
+		{StageRepository, "add", 1},
+		{StageListener, listener, 1},
+		{StageEmit, "test_event", 2},
+		{StageValidator, "policy open", 3},
+		{StageRepository, "add", 3},
+		{StageListener, listener, 3},
+	}, spanSteps(trace))
+	spans := trace.Spans()
+	for i, span := range spans {
+		assert.Equal(t, "test_event", span.EventType)
+		assert.LessOrEqual(t, span.Start+span.Duration, spans[0].Start+spans[0].Duration, "Within the outer emit")
+		if i > 0 {
+			assert.GreaterOrEqual(t, span.Start, spans[i-1].Start)
+		}
+	}
+
+	trace.Reset()
+	assert.Empty(t, trace.Spans())
+}
+
+// TestTracingBatches verifies EmitAll is traced as one emit
+func TestTracingBatches(t *testing.T) {
+	trace := NewTrace()
+	engine := newBatchEngine(WithTracing(trace))
+	engine.When("tokens_spent").Before(&typeRecorder{})
+
+	require.True(t, engine.EmitAll(TokensSpentEvent{Amount: 1}, TokensSpentEvent{Amount: 1}).Accepted)
+	assert.Equal(t, [][3]interface{}{
+		{StageEmit, "EmitAll", 0},
+		{StageValidator, "EmitAll", 1},
+		{StageHook, "*atmos.typeRecorder", 1},
+		{StageHook, "*atmos.typeRecorder", 1},
+		{StageRepository, "commit", 1},
+	}, spanSteps(trace))
+	assert.Equal(t, "tokens_spent,tokens_spent", trace.Spans()[0].EventType)
+}
+
+// TestWriteChromeTrace verifies the Chrome trace event format
+func TestWriteChromeTrace(t *testing.T) {
+	trace := NewTrace()
+	engine := newBatchEngine(WithTracing(trace))
+	engine.Emit(TokensSpentEvent{Amount: 1})
+
+	var buf bytes.Buffer
+	require.NoError(t, trace.WriteChromeTrace(&buf))
+	var decoded struct {
+		TraceEvents []map[string]interface{} `json:"traceEvents"`
+	}
+	require.NoError(t, json.Unmarshal(buf.Bytes(), &decoded))
+	require.Len(t, decoded.TraceEvents, 3)
+	assert.Equal(t, "tokens_spent", decoded.TraceEvents[0]["name"])
+	assert.Equal(t, "emit", decoded.TraceEvents[0]["cat"])
+	assert.Equal(t, "X", decoded.TraceEvents[0]["ph"])
+	assert.Equal(t, map[string]interface{}{"event": "tokens_spent"}, decoded.TraceEvents[1]["args"])
+}
+
+// TestProfilerLabels verifies listeners run under pprof labels naming the step
+func TestProfilerLabels(t *testing.T) {
+	engine := NewEngine(WithProfilerLabels())
+	var labels map[string]string
+	engine.When("test_event").Then(NewTypedListener(TypedListenerFunc[TestEvent](func(e *Engine, event TestEvent) {
+		labels = map[string]string{}
+		pprof.ForLabels(e.labels, func(key, value string) bool {
+			labels[key] = value
+			return true
+		})
+	})))
+
+	require.True(t, engine.Emit(TestEvent{Name: "a"}))
+	assert.Equal(t, "test_event", labels["atmos.event"])
+	assert.Equal(t, StageListener, labels["atmos.stage"])
+	assert.Nil(t, engine.labels, "Restored after the emit")
+}
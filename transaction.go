@@ -0,0 +1,19 @@
+package atmos
+
+// transaction starts collecting the events an emit commits, including those
+// derived by its hooks and listeners. The returned function, deferred by
+// the emit, reports them in the result if it was accepted.
+func (e *Engine) transaction(result *EmitResult) func() {
+	start := len(e.committed)
+	e.transactions++
+	return func() {
+		e.transactions--
+		if result.Accepted {
+			result.Events = append([]Event(nil), e.committed[start:]...)
+			result.Position -= start // Emits record it as an index in e.committed
+		}
+		if e.transactions == 0 {
+			e.committed = nil
+		}
+	}
+}
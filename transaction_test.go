@@ -0,0 +1,70 @@
+package atmos
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// testEventNames lists the names of test events
+func testEventNames(events []Event) []string {
+	var names []string
+	for _, event := range events {
+		names = append(names, event.(TestEvent).Name)
+	}
+	return names
+}
+
+// TestEmitResultListsDerivedEvents verifies each result lists what its call committed, in log order
+func TestEmitResultListsDerivedEvents(t *testing.T) {
+	var results []EmitResult
+	engine := newFanOutEngine(&results)
+
+	result := engine.EmitWithResult(TestEvent{Name: "a"})
+	require.True(t, result.Accepted)
+	assert.Equal(t, []string{"a", "b1", "c1", "b2", "c2"}, testEventNames(result.Events))
+	assert.Equal(t, []string{"c1"}, testEventNames(results[0].Events))
+	assert.Equal(t, []string{"b1", "c1"}, testEventNames(results[1].Events))
+
+	result = engine.EmitWithResult(TestEvent{Name: "z"})
+	assert.Equal(t, []string{"z"}, testEventNames(result.Events), "Earlier calls aren't included")
+}
+
+// TestEmitResultListsBreadthFirstEvents verifies queued derived events are reported by the outermost emit
+func TestEmitResultListsBreadthFirstEvents(t *testing.T) {
+	var results []EmitResult
+	engine := newFanOutEngine(&results, WithBreadthFirstEmits())
+
+	result := engine.EmitWithResult(TestEvent{Name: "a"})
+	assert.Equal(t, []string{"a", "b1", "b2", "c1", "c2"}, testEventNames(result.Events))
+	assert.True(t, results[0].Queued)
+	assert.Nil(t, results[0].Events)
+}
+
+// TestEmitAllResultListsDerivedEvents verifies batches report the batch, then what it derived
+func TestEmitAllResultListsDerivedEvents(t *testing.T) {
+	var results []EmitResult
+	engine := newFanOutEngine(&results)
+	engine.When("test_event").Before(NewTypedListener(TypedListenerFunc[TestEvent](func(e *Engine, event TestEvent) {
+		if event.Name == "x" {
+			e.Emit(TestEvent{Name: "before x"})
+		}
+	})))
+
+	result := engine.EmitAll(TestEvent{Name: "x"}, TestEvent{Name: "b2"})
+	assert.Equal(t, []string{"before x", "x", "b2", "c2"}, testEventNames(result.Events))
+	assert.Equal(t, 1, result.Position, "The batch starts after its before hook's event")
+
+	result = engine.EmitWithResult(TestEvent{Name: "x"})
+	assert.Equal(t, []string{"before x", "x"}, testEventNames(result.Events))
+	assert.Equal(t, 1, result.Position)
+}
+
+// TestRejectedEmitListsNoEvents verifies rejections report nothing committed
+func TestRejectedEmitListsNoEvents(t *testing.T) {
+	engine := newBatchEngine()
+	result := engine.EmitWithResult(TokensSpentEvent{Amount: 10})
+	assert.False(t, result.Accepted)
+	assert.Nil(t, result.Events)
+}
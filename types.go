@@ -1,6 +1,11 @@
 package atmos
 
-import "github.com/cumulusrpg/atmos/types"
+import (
+	"fmt"
+	"reflect"
+
+	"github.com/cumulusrpg/atmos/types"
+)
 
 // =============================================================================
 // Re-exported types from types package for convenience
@@ -15,15 +20,39 @@ type EventEmitter = types.EventEmitter
 // EventValidator validates whether an event should be committed to the log
 type EventValidator = types.EventValidator
 
+// ReasonedValidator is a validator that explains its rejections
+type ReasonedValidator = types.ReasonedValidator
+
 // EventListener responds to events after they are committed
 type EventListener = types.EventListener
 
 // EventRepository handles event storage and persistence
 type EventRepository = types.EventRepository
 
+// EventRanger visits committed events without copying the log
+type EventRanger = types.EventRanger
+
+// EventIterator walks a sequence of events
+type EventIterator = types.EventIterator
+
+// StreamingRepository loads events lazily in ranges
+type StreamingRepository = types.StreamingRepository
+
+// AggregateEvent belongs to an aggregate identified by ID
+type AggregateEvent = types.AggregateEvent
+
+// IndexedRepository indexes events by type and aggregate
+type IndexedRepository = types.IndexedRepository
+
 // SnapshotRepository handles snapshot storage for state seeding
 type SnapshotRepository = types.SnapshotRepository
 
+// OutboxRepository records committed events awaiting external publication
+type OutboxRepository = types.OutboxRepository
+
+// OutboxEntry is a committed event awaiting publication
+type OutboxEntry = types.OutboxEntry
+
 // =============================================================================
 // Types that remain in main atmos package
 // =============================================================================
@@ -63,6 +92,10 @@ func (w ValidatorWrapper[T]) Validate(engine types.Engine, event Event) bool {
 	return w.validator.ValidateTyped(concreteEngine, typedEvent)
 }
 
+func (w ValidatorWrapper[T]) validatorName() string {
+	return fmt.Sprintf("%T", w.validator)
+}
+
 // ListenerWrapper wraps a typed listener to implement the base interface
 type ListenerWrapper[T Event] struct {
 	listener TypedEventListener[T]
@@ -84,6 +117,20 @@ func NewTypedListener[T Event](listener TypedEventListener[T]) EventListener {
 	return ListenerWrapper[T]{listener: listener}
 }
 
+// Deref returns the event a pointer event points to, when that is an Event
+// itself, so a type switch over value types also matches events decoded as
+// pointers (as UnmarshalEvents produces them)
+func Deref(event Event) Event {
+	value := reflect.ValueOf(event)
+	if value.Kind() != reflect.Ptr || value.IsNil() {
+		return event
+	}
+	if elem, ok := value.Elem().Interface().(Event); ok {
+		return elem
+	}
+	return event
+}
+
 // Context interfaces for explicit dependency injection
 
 // EventLogContext provides access to the event log for validation/projection
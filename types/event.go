@@ -15,6 +15,15 @@ type EventValidator interface {
 	Validate(engine Engine, event Event) bool
 }
 
+// ReasonedValidator is a validator that can explain its rejections (opt-in
+// interface). When present, the engine calls Check instead of Validate and
+// reports the error's message as the rejection reason.
+type ReasonedValidator interface {
+	EventValidator
+	// Check returns nil to approve the event, or an error explaining the rejection
+	Check(engine Engine, event Event) error
+}
+
 // EventListener responds to events after they are committed
 type EventListener interface {
 	Handle(engine Engine, event Event)
@@ -1,5 +1,17 @@
 package types
 
+import "errors"
+
+// Failures repositories report (wrapped, to add detail), so callers can
+// tell them apart with errors.Is
+var (
+	// ErrStorageFull means the backend has no room for the write
+	ErrStorageFull = errors.New("storage full")
+
+	// ErrConflict means another writer changed the log first
+	ErrConflict = errors.New("conflicting write")
+)
+
 // EventRepository handles event storage and persistence
 type EventRepository interface {
 	// Add commits a new event to storage
@@ -12,6 +24,94 @@ type EventRepository interface {
 	SetAll(engine Engine, events []Event) error
 }
 
+// EventRanger visits committed events in order without copying the log
+// (opt-in interface). The engine prefers it over GetAll when replaying, so
+// repositories that can iterate in place avoid a full copy per GetState.
+type EventRanger interface {
+	// Range calls fn for each event in commit order, stopping early if fn returns false.
+	// fn may read from the repository but must not write to it.
+	Range(engine Engine, fn func(event Event) bool)
+}
+
+// EventIterator walks a sequence of events:
+//
+//	for it.Next() {
+//		use(it.Event())
+//	}
+//	if err := it.Err(); err != nil { ... }
+//	it.Close()
+type EventIterator interface {
+	// Next advances to the next event, returning false when done or on error
+	Next() bool
+
+	// Event returns the current event
+	Event() Event
+
+	// Err returns the error that stopped iteration, if any
+	Err() error
+
+	// Close releases resources held by the iterator
+	Close() error
+}
+
+// StreamingRepository loads events lazily in ranges (opt-in interface), so
+// disk- or database-backed stores can replay long logs without holding them in
+// memory. The engine replays such repositories in chunks.
+type StreamingRepository interface {
+	// Stream iterates events with positions in [from, to); to < 0 means the end of the log
+	Stream(from, to int) EventIterator
+}
+
+// AggregateEvent is implemented by events that belong to an aggregate (a
+// game, a player, an order...) so indexed repositories can find them by ID
+type AggregateEvent interface {
+	Event
+	AggregateID() string
+}
+
+// IndexedRepository maintains indexes by event type and by aggregate at Add
+// time (opt-in interface), so replays that only need a few event types don't
+// scan unrelated events. The engine uses it to replay only the event types a
+// state has reducers for.
+type IndexedRepository interface {
+	// EventsOfTypes returns the events of the given types in commit order
+	EventsOfTypes(engine Engine, eventTypes ...string) []Event
+
+	// EventsForAggregate returns the AggregateEvents with the given ID in commit order
+	EventsForAggregate(engine Engine, aggregateID string) []Event
+}
+
+// Tx is a batch of appends that becomes visible all at once on Commit
+type Tx interface {
+	// Append adds an event to the batch
+	Append(event Event) error
+
+	// Commit persists every appended event atomically
+	Commit() error
+
+	// Rollback discards the batch
+	Rollback() error
+}
+
+// IdentifiedTx is a Tx that carries event IDs (opt-in interface, for the
+// transactions of IdentifiedRepositories), so batches keep the IDs the
+// engine assigns them
+type IdentifiedTx interface {
+	Tx
+
+	// AppendIdentified adds an event with its ID to the batch
+	AppendIdentified(envelope Envelope) error
+}
+
+// TxRepository persists batches of events atomically (opt-in interface),
+// for backends with transactions (SQL, bbolt). The engine uses it for
+// EmitAll; other repositories get a compensating fallback instead, as do
+// IdentifiedRepositories whose transactions aren't IdentifiedTx.
+type TxRepository interface {
+	// Begin starts a batch
+	Begin(engine Engine) (Tx, error)
+}
+
 // SnapshotRepository handles snapshot storage for state seeding (opt-in interface)
 // Repositories that implement this interface enable snapshot-based state projection.
 // This is useful for E2E testing where you want to seed specific states without
@@ -26,3 +126,77 @@ type SnapshotRepository interface {
 	// ClearSnapshot removes the snapshot for a state
 	ClearSnapshot(stateName string) error
 }
+
+// OutboxEntry is a committed event awaiting publication to an external system
+type OutboxEntry struct {
+	ID       uint64 // Monotonically increasing, in commit order
+	Event    Event  // The committed event
+	Attempts int    // Failed publication attempts so far
+	LastErr  string // Error from the most recent failed attempt
+}
+
+// OutboxRepository records an outbox entry for every added event in the same
+// atomic write as the event itself (opt-in interface). A relay later publishes
+// pending entries and marks them done, so external publishing can't be lost
+// between commit and publish. Events restored via SetAll are not enqueued.
+type OutboxRepository interface {
+	// PendingOutbox returns up to limit unpublished entries in commit order (limit <= 0 means all)
+	PendingOutbox(limit int) ([]OutboxEntry, error)
+
+	// MarkPublished removes an entry from the outbox
+	MarkPublished(id uint64) error
+
+	// MarkFailed records a failed publication attempt for an entry
+	MarkFailed(id uint64, err error) error
+}
+
+// SizedRepository reports how much storage its log takes (opt-in
+// interface), for dashboards (see the engine's Stats)
+type SizedRepository interface {
+	// LogSize returns the size of the stored log in bytes
+	LogSize() (int64, error)
+}
+
+// Envelope is a committed event with the ID the engine assigned to it
+type Envelope struct {
+	ID       string            // Sortable unique ID (a ULID unless the engine was given another generator)
+	Event    Event             // The committed event
+	Metadata map[string]string // Circumstances of the emit (actor, client, origin), if given
+}
+
+// IdentifiedRepository stores the ID assigned to each event at commit
+// (opt-in interface). The engine commits to such repositories with
+// AddIdentified, so events can be referenced, deduplicated and used as sync
+// cursors; events added via Add or SetAll have an empty ID.
+type IdentifiedRepository interface {
+	// AddIdentified commits an event with its ID
+	AddIdentified(engine Engine, envelope Envelope) error
+
+	// Envelopes returns every event with its ID in commit order
+	Envelopes(engine Engine) []Envelope
+
+	// EnvelopeByID returns the event committed with an ID, or false if there is none
+	EnvelopeByID(engine Engine, id string) (Envelope, bool)
+
+	// SetEnvelopes atomically replaces all events, keeping their IDs
+	SetEnvelopes(engine Engine, envelopes []Envelope) error
+}
+
+// ChangeNotifier is implemented by repositories shared between processes or
+// engines (opt-in interface), to tell an engine when another writer commits
+// events, so it can refresh what it has cached
+type ChangeNotifier interface {
+	// WatchChanges calls onChange, possibly from another goroutine, after
+	// other writers commit. The returned function stops the notifications.
+	WatchChanges(onChange func()) (stop func())
+}
+
+// BlobStore keeps large payloads apart from the event log, which stores
+// only their keys (see the engine's WithBlobStore and WithMaxPayloadSize)
+type BlobStore interface {
+	// Put stores a blob, returning the key to get it back with
+	Put(data []byte) (string, error)
+
+	// Get returns a stored blob, or an error if the key is unknown
+	Get(key string) ([]byte, error)
+}
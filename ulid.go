@@ -0,0 +1,98 @@
+package atmos
+
+import (
+	"crypto/rand"
+	"io"
+	"strings"
+	"sync"
+	"time"
+)
+
+// crockford is the ULID alphabet (Crockford's base32)
+const crockford = "0123456789ABCDEFGHJKMNPQRSTVWXYZ"
+
+// NewULIDGenerator returns a generator of ULIDs: 26-character IDs that sort
+// lexicographically by creation time (millisecond precision) and are unique
+// thanks to 80 random bits. IDs from the same generator are strictly
+// increasing, even within a millisecond. The generator is safe for
+// concurrent use; it panics if entropy fails.
+// Pass nil for the defaults (time.Now and crypto/rand).
+func NewULIDGenerator(now func() time.Time, entropy io.Reader) func() string {
+	if now == nil {
+		now = time.Now
+	}
+	if entropy == nil {
+		entropy = rand.Reader
+	}
+
+	var mu sync.Mutex
+	var lastMillis uint64
+	var random [10]byte
+	return func() string {
+		mu.Lock()
+		defer mu.Unlock()
+
+		millis := uint64(now().UnixMilli())
+		if millis <= lastMillis {
+			// Same (or an earlier) millisecond: increment the previous
+			// randomness, moving on to the next millisecond if it wraps
+			millis = lastMillis
+			wrapped := true
+			for i := len(random) - 1; i >= 0 && wrapped; i-- {
+				random[i]++
+				wrapped = random[i] == 0
+			}
+			if wrapped {
+				millis++
+			}
+		} else if _, err := io.ReadFull(entropy, random[:]); err != nil {
+			panic("atmos: reading ULID entropy: " + err.Error())
+		}
+		lastMillis = millis
+		return encodeULID(millis, random)
+	}
+}
+
+// encodeULID renders a 48-bit timestamp and 80 random bits in base32
+func encodeULID(millis uint64, random [10]byte) string {
+	var id [26]byte
+	for i := 9; i >= 0; i-- { // 10 characters of timestamp
+		id[i] = crockford[millis&31]
+		millis >>= 5
+	}
+
+	// 80 bits of randomness as 16 characters, 5 bits at a time
+	var bits uint64
+	var count uint
+	pos := 10
+	for _, b := range random {
+		bits = bits<<8 | uint64(b)
+		count += 8
+		for count >= 5 {
+			count -= 5
+			id[pos] = crockford[(bits>>count)&31]
+			pos++
+		}
+	}
+	return string(id[:])
+}
+
+// ULIDTime returns the creation time encoded in a ULID, or false if id
+// isn't one (see NewULIDGenerator)
+func ULIDTime(id string) (time.Time, bool) {
+	if len(id) != 26 {
+		return time.Time{}, false
+	}
+	var millis uint64
+	for i := 0; i < 10; i++ {
+		digit := strings.IndexByte(crockford, id[i])
+		if digit < 0 {
+			return time.Time{}, false
+		}
+		millis = millis<<5 | uint64(digit)
+	}
+	if millis >= 1<<48 {
+		return time.Time{}, false
+	}
+	return time.UnixMilli(int64(millis)), true
+}
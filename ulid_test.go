@@ -0,0 +1,63 @@
+package atmos
+
+import (
+	"bytes"
+	"sort"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// TestULIDEncoding verifies the timestamp and randomness are laid out as in the ULID spec
+func TestULIDEncoding(t *testing.T) {
+	at := time.UnixMilli(1469918176385)
+	generate := NewULIDGenerator(func() time.Time { return at }, bytes.NewReader(make([]byte, 10)))
+
+	assert.Equal(t, "01ARYZ6S410000000000000000", generate())
+	assert.Equal(t, "01ARYZ6S410000000000000001", generate(), "IDs within a millisecond increment")
+}
+
+// TestULIDsSortByCreation verifies IDs sort in generation order, even if the clock goes backwards
+func TestULIDsSortByCreation(t *testing.T) {
+	clock := time.UnixMilli(1700000000000)
+	ticks := []time.Duration{0, 0, time.Millisecond, -time.Second, time.Hour}
+	i := 0
+	generate := NewULIDGenerator(func() time.Time {
+		clock = clock.Add(ticks[i%len(ticks)])
+		i++
+		return clock
+	}, nil)
+
+	var ids []string
+	for n := 0; n < 20; n++ {
+		ids = append(ids, generate())
+	}
+	assert.True(t, sort.StringsAreSorted(ids))
+	for n := 1; n < len(ids); n++ {
+		assert.NotEqual(t, ids[n-1], ids[n])
+		assert.Len(t, ids[n], 26)
+	}
+}
+
+// TestULIDTime verifies the creation time is read back from IDs
+func TestULIDTime(t *testing.T) {
+	at, ok := ULIDTime("01ARYZ6S410000000000000000")
+	assert.True(t, ok)
+	assert.Equal(t, int64(1469918176385), at.UnixMilli())
+
+	for _, id := range []string{"client-1", "01ARYZ6S4U0000000000000000", "81ARYZ6S410000000000000000"} {
+		_, ok := ULIDTime(id)
+		assert.False(t, ok, id)
+	}
+}
+
+// TestULIDRandomnessOverflow verifies IDs keep increasing when the randomness wraps within a millisecond
+func TestULIDRandomnessOverflow(t *testing.T) {
+	at := time.UnixMilli(1469918176385)
+	generate := NewULIDGenerator(func() time.Time { return at }, bytes.NewReader(bytes.Repeat([]byte{0xFF}, 10)))
+
+	assert.Equal(t, "01ARYZ6S41ZZZZZZZZZZZZZZZZ", generate())
+	assert.Equal(t, "01ARYZ6S420000000000000000", generate(), "Borrowed from the next millisecond")
+	assert.Equal(t, "01ARYZ6S420000000000000001", generate())
+}
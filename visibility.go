@@ -0,0 +1,118 @@
+package atmos
+
+import (
+	"fmt"
+	"reflect"
+)
+
+// StateMask returns what a viewer may see of a state. It must not modify
+// the state it is given; return a redacted copy instead.
+type StateMask func(engine *Engine, viewer string, state interface{}) interface{}
+
+// EventMask returns what a viewer may see of an event: the event itself, a
+// redacted copy, or false to hide it entirely
+type EventMask func(viewer string, event Event) (Event, bool)
+
+// RegisterStateMask registers the visibility rule for a state (see GetStateFor)
+func (e *Engine) RegisterStateMask(stateName string, mask StateMask) {
+	e.mutableRegistrations().stateMasks[stateName] = mask
+}
+
+// RegisterEventMask registers a visibility rule for an event type (AnyEvent
+// for every event); masks run in registration order (see GetEventsFor)
+func (e *Engine) RegisterEventMask(eventType string, mask EventMask) {
+	r := e.mutableRegistrations()
+	r.eventMasks[eventType] = append(r.eventMasks[eventType], mask)
+}
+
+// Masked registers visibility rules for this event (chainable)
+// Usage: When("card_drawn").Masked(RedactFields(DrawingPlayer, "Card"))
+func (r *EventRegistration) Masked(masks ...EventMask) *EventRegistration {
+	for _, mask := range masks {
+		r.engine.RegisterEventMask(r.eventType, mask)
+	}
+	return r
+}
+
+// GetStateFor returns a state as a viewer (player ID, or "" for someone with
+// no seat, such as a spectator) may see it. States without a mask are public.
+func (e *Engine) GetStateFor(viewer, name string) interface{} {
+	state := e.GetState(name)
+	if mask, exists := e.stateMasks[name]; exists {
+		return mask(e, viewer, state)
+	}
+	return state
+}
+
+// EventFor returns an event as a viewer may see it, or false if it is hidden
+func (e *Engine) EventFor(viewer string, event Event) (Event, bool) {
+	for _, eventType := range []string{AnyEvent, event.Type()} {
+		for _, mask := range e.eventMasks[eventType] {
+			var visible bool
+			if event, visible = mask(viewer, event); !visible {
+				return nil, false
+			}
+		}
+	}
+	return event, true
+}
+
+// GetEventsFor returns the event log as a viewer may see it, with hidden
+// events removed and redacted ones replaced
+func (e *Engine) GetEventsFor(viewer string) []Event {
+	var events []Event
+	e.eachEvent(func(event Event) bool {
+		if visible, ok := e.EventFor(viewer, event); ok {
+			events = append(events, visible)
+		}
+		return true
+	})
+	return events
+}
+
+// OwnerOnly hides an event from everyone but its owner
+// Usage: When("card_drawn").Masked(OwnerOnly(func(event Event) string { return event.(CardDrawnEvent).Player }))
+func OwnerOnly(owner func(event Event) string) EventMask {
+	return func(viewer string, event Event) (Event, bool) {
+		return event, viewer != "" && viewer == owner(event)
+	}
+}
+
+// RedactFields shows an event to everyone, but zeroes the named struct
+// fields for anyone but its owner (the event itself is never modified).
+// Usage: When("card_drawn").Masked(RedactFields(DrawingPlayer, "Card"))
+func RedactFields(owner func(event Event) string, fields ...string) EventMask {
+	return func(viewer string, event Event) (Event, bool) {
+		if viewer != "" && viewer == owner(event) {
+			return event, true
+		}
+		return redact(event, fields), true
+	}
+}
+
+// redact returns a copy of a struct event (or pointer to one) with fields zeroed
+func redact(event Event, fields []string) Event {
+	value := reflect.ValueOf(event)
+	pointer := value.Kind() == reflect.Ptr
+	if pointer {
+		value = value.Elem()
+	}
+	if value.Kind() != reflect.Struct {
+		panic(fmt.Sprintf("atmos: cannot redact fields of %T", event))
+	}
+
+	copied := reflect.New(value.Type()).Elem()
+	copied.Set(value)
+	for _, name := range fields {
+		field := copied.FieldByName(name)
+		if !field.IsValid() || !field.CanSet() {
+			panic(fmt.Sprintf("atmos: %T has no exported field %s", event, name))
+		}
+		field.Set(reflect.Zero(field.Type()))
+	}
+
+	if pointer {
+		return copied.Addr().Interface().(Event)
+	}
+	return copied.Interface().(Event)
+}
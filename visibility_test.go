@@ -0,0 +1,89 @@
+package atmos
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+type CardDrawnEvent struct {
+	Player string
+	Card   string
+}
+
+func (e CardDrawnEvent) Type() string { return "card_drawn" }
+
+type PeekEvent struct {
+	Player string
+	Card   string
+}
+
+func (e *PeekEvent) Type() string { return "peek" }
+
+// drawingPlayer is the owner of draw and peek events
+func drawingPlayer(event Event) string {
+	switch e := event.(type) {
+	case CardDrawnEvent:
+		return e.Player
+	case *PeekEvent:
+		return e.Player
+	}
+	return ""
+}
+
+// TestVisibilityMasksStatesAndEvents verifies each viewer sees only their own hidden data
+func TestVisibilityMasksStatesAndEvents(t *testing.T) {
+	engine := NewEngine()
+	engine.RegisterState("hands", map[string][]string{})
+	engine.When("card_drawn").
+		Masked(RedactFields(drawingPlayer, "Card")).
+		Updates("hands", func(e *Engine, state interface{}, event Event) interface{} {
+			drawn := event.(CardDrawnEvent)
+			hands := make(map[string][]string)
+			for player, hand := range state.(map[string][]string) {
+				hands[player] = hand
+			}
+			hands[drawn.Player] = append(append([]string(nil), hands[drawn.Player]...), drawn.Card)
+			return hands
+		})
+	engine.When("peek").Masked(OwnerOnly(drawingPlayer))
+	engine.RegisterStateMask("hands", func(e *Engine, viewer string, state interface{}) interface{} {
+		masked := make(map[string]int)
+		for player, hand := range state.(map[string][]string) {
+			masked[player] = len(hand)
+		}
+		return masked
+	})
+
+	engine.Emit(CardDrawnEvent{Player: "alice", Card: "ace"})
+	engine.Emit(CardDrawnEvent{Player: "bob", Card: "king"})
+	engine.Emit(&PeekEvent{Player: "alice", Card: "queen"})
+
+	assert.Equal(t, map[string]int{"alice": 1, "bob": 1}, engine.GetStateFor("alice", "hands"))
+	assert.Equal(t, map[string][]string{"alice": {"ace"}, "bob": {"king"}}, engine.GetState("hands"), "The real state is untouched")
+
+	assert.Equal(t, []Event{
+		CardDrawnEvent{Player: "alice", Card: "ace"},
+		CardDrawnEvent{Player: "bob"},
+		&PeekEvent{Player: "alice", Card: "queen"},
+	}, engine.GetEventsFor("alice"))
+	assert.Equal(t, []Event{
+		CardDrawnEvent{Player: "alice"},
+		CardDrawnEvent{Player: "bob"},
+	}, engine.GetEventsFor(""), "Spectators see no hidden data")
+	assert.Equal(t, "king", engine.GetEvents()[1].(CardDrawnEvent).Card, "Events in the log are never modified")
+}
+
+// TestVisibilityGlobalMask verifies AnyEvent masks apply to every event
+func TestVisibilityGlobalMask(t *testing.T) {
+	engine := NewEngine()
+	engine.RegisterEventMask(AnyEvent, func(viewer string, event Event) (Event, bool) {
+		return event, viewer != "banned"
+	})
+	engine.Emit(&PeekEvent{Player: "alice"})
+
+	assert.Len(t, engine.GetEventsFor("bob"), 1)
+	assert.Empty(t, engine.GetEventsFor("banned"))
+
+	assert.Panics(t, func() { RedactFields(drawingPlayer, "Missing")("bob", CardDrawnEvent{}) })
+}
@@ -0,0 +1,47 @@
+package atmos
+
+// Warning is raised by an advisory validator that objected to an event
+// without blocking it
+type Warning struct {
+	Validator string   `json:"validator"`         // Name of the advisory validator
+	Reason    string   `json:"reason,omitempty"`  // The validator's reason (ReasonedValidators only)
+	Message   *Message `json:"message,omitempty"` // The reason as a message key and parameters, if given
+}
+
+// RegisterAdvisory registers a non-blocking validator for an event type.
+// When it fails, the event is still committed but carries a Warning,
+// reported in EmitResult.Warnings, Explain, and Warnings() during the emit.
+func (e *Engine) RegisterAdvisory(eventType string, validator EventValidator) {
+	r := e.mutableRegistrations()
+	if !admit(e, "advisory", eventType, r.advisories[eventType], validator, validatorName) {
+		return
+	}
+	r.advisories[eventType] = append(r.advisories[eventType], validator)
+}
+
+// Warns registers advisory validators for soft rules such as deprecated or
+// suspicious-but-legal plays (chainable)
+// Usage: When("card_played").Requires(Valid(&InHand{})).Warns(Because(Valid(&NotDeprecated{}), "card is deprecated"))
+func (r *EventRegistration) Warns(validators ...EventValidator) *EventRegistration {
+	for _, validator := range validators {
+		r.engine.RegisterAdvisory(r.eventType, validator)
+	}
+	return r
+}
+
+// Warnings returns the warnings attached to the event currently being
+// committed, for before hooks and listeners (nil outside of an emit)
+func (e *Engine) Warnings() []Warning {
+	return e.warnings
+}
+
+// advise runs an event's advisory validators, returning their warnings
+func (e *Engine) advise(event Event) []Warning {
+	var warnings []Warning
+	for _, validator := range e.advisories[event.Type()] {
+		if ok, err := e.check(validator, event); !ok {
+			warnings = append(warnings, Warning{Validator: validatorName(validator), Reason: e.reason(err), Message: messageOf(err)})
+		}
+	}
+	return warnings
+}
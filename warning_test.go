@@ -0,0 +1,44 @@
+package atmos
+
+import (
+	"testing"
+
+	"github.com/cumulusrpg/atmos/types"
+	"github.com/stretchr/testify/assert"
+)
+
+// warningRecorder captures the warnings visible to listeners
+type warningRecorder struct {
+	seen [][]Warning
+}
+
+func (l *warningRecorder) Handle(engine types.Engine, event Event) {
+	l.seen = append(l.seen, engine.(*Engine).Warnings())
+}
+
+// TestAdvisoryValidatorsWarnWithoutBlocking verifies warnings travel with the commit
+func TestAdvisoryValidatorsWarnWithoutBlocking(t *testing.T) {
+	recorder := &warningRecorder{}
+	engine := NewEngine()
+	engine.When("order_placed").
+		Warns(Because(Valid(TypedValidatorFunc[OrderPlacedEvent](func(e *Engine, event OrderPlacedEvent) bool {
+			return event.Amount < 1000
+		})), "unusually large order")).
+		Then(recorder)
+
+	result := engine.EmitWithResult(OrderPlacedEvent{OrderID: "ORD-1", Amount: 5000})
+	assert.True(t, result.Accepted)
+	assert.Equal(t, []Warning{{
+		Validator: "atmos.TypedValidatorFunc[github.com/cumulusrpg/atmos.OrderPlacedEvent]",
+		Reason:    "unusually large order",
+	}}, result.Warnings)
+
+	assert.Empty(t, engine.EmitWithResult(OrderPlacedEvent{OrderID: "ORD-2", Amount: 50}).Warnings)
+	assert.Equal(t, [][]Warning{result.Warnings, nil}, recorder.seen)
+	assert.Nil(t, engine.Warnings(), "Warnings are only visible during the emit")
+
+	explanation := engine.Explain(OrderPlacedEvent{Amount: 2000})
+	assert.True(t, explanation.Accepted)
+	assert.Len(t, explanation.Warnings, 1)
+	assert.Contains(t, explanation.String(), "warn atmos.TypedValidatorFunc")
+}
@@ -0,0 +1,288 @@
+// Package webhook delivers committed events to HTTP endpoints. Deliveries
+// are signed with HMAC-SHA256, retried with exponential backoff, and moved
+// to a failure queue once retries are exhausted.
+//
+//	notifier := webhook.NewNotifier([]webhook.Endpoint{
+//		{URL: "https://example.com/turns", Secret: secret, EventTypes: []string{"turn_started"}},
+//	})
+//	defer notifier.Close()
+//	engine.When(atmos.AnyEvent).Then(notifier)
+package webhook
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/cumulusrpg/atmos"
+	"github.com/cumulusrpg/atmos/types"
+)
+
+// Headers set on every delivery
+const (
+	HeaderSignature = "X-Atmos-Signature"  // "sha256=" + hex HMAC of the body (when the endpoint has a secret)
+	HeaderEventType = "X-Atmos-Event-Type" // the event type
+)
+
+// Endpoint is a webhook destination
+type Endpoint struct {
+	URL        string
+	Secret     string   // HMAC key; empty disables signing
+	EventTypes []string // Event types to deliver; empty means all
+}
+
+// accepts reports whether the endpoint subscribes to an event type
+func (ep Endpoint) accepts(eventType string) bool {
+	if len(ep.EventTypes) == 0 {
+		return true
+	}
+	for _, t := range ep.EventTypes {
+		if t == eventType {
+			return true
+		}
+	}
+	return false
+}
+
+// Delivery is one event bound for one endpoint
+type Delivery struct {
+	Endpoint Endpoint
+	Event    atmos.Event
+	Body     []byte
+	Attempts int
+	LastErr  error
+}
+
+// Notifier is an EventListener that delivers events to webhook endpoints in
+// the background, so slow endpoints never block Emit. Call Close to flush.
+type Notifier struct {
+	client      *http.Client
+	endpoints   []Endpoint
+	maxAttempts int
+	backoff     func(attempt int) time.Duration
+	sleep       func(d time.Duration)
+
+	queue     chan *Delivery
+	done      chan struct{}
+	closeOnce sync.Once
+	closing   sync.RWMutex // held for writing while the queue is closed
+	closed    bool
+
+	mu       sync.Mutex
+	failures []*Delivery
+}
+
+// Option configures notifier construction
+type Option func(*Notifier)
+
+// WithHTTPClient sets the HTTP client used for deliveries
+func WithHTTPClient(client *http.Client) Option {
+	return func(n *Notifier) {
+		n.client = client
+	}
+}
+
+// WithMaxAttempts sets how many times a delivery is tried before it fails (default 5)
+func WithMaxAttempts(attempts int) Option {
+	return func(n *Notifier) {
+		n.maxAttempts = attempts
+	}
+}
+
+// WithBackoff sets the delay before retry number attempt (1-based).
+// The default doubles from 500ms.
+func WithBackoff(backoff func(attempt int) time.Duration) Option {
+	return func(n *Notifier) {
+		n.backoff = backoff
+	}
+}
+
+// WithQueueSize sets how many deliveries may be buffered (default 256).
+// When the buffer is full, deliveries go straight to the failure queue.
+func WithQueueSize(size int) Option {
+	return func(n *Notifier) {
+		n.queue = make(chan *Delivery, size)
+	}
+}
+
+// ExponentialBackoff doubles the delay from base on each retry
+func ExponentialBackoff(base time.Duration) func(attempt int) time.Duration {
+	return func(attempt int) time.Duration {
+		return base << (attempt - 1)
+	}
+}
+
+// NewNotifier creates a notifier and starts its delivery worker
+func NewNotifier(endpoints []Endpoint, opts ...Option) *Notifier {
+	n := &Notifier{
+		client:      &http.Client{Timeout: 10 * time.Second},
+		endpoints:   endpoints,
+		maxAttempts: 5,
+		backoff:     ExponentialBackoff(500 * time.Millisecond),
+		sleep:       time.Sleep,
+		queue:       make(chan *Delivery, 256),
+		done:        make(chan struct{}),
+	}
+
+	// Apply options
+	for _, opt := range opts {
+		opt(n)
+	}
+
+	go n.run()
+	return n
+}
+
+// Handle implements atmos.EventListener by queueing deliveries for matching
+// endpoints. Nothing is delivered while the engine is replaying.
+func (n *Notifier) Handle(engine types.Engine, event atmos.Event) {
+	if atmos.IsReplaying(engine) {
+		return
+	}
+	body, err := json.Marshal(atmos.EventWrapper{Type: event.Type(), Data: event})
+
+	for _, endpoint := range n.endpoints {
+		if !endpoint.accepts(event.Type()) {
+			continue
+		}
+
+		delivery := &Delivery{Endpoint: endpoint, Event: event, Body: body}
+		if err != nil {
+			delivery.LastErr = err
+			n.fail(delivery)
+			continue
+		}
+
+		if err := n.enqueue(delivery); err != nil {
+			delivery.LastErr = err
+			n.fail(delivery)
+		}
+	}
+}
+
+// enqueue hands a delivery to the worker without waiting
+func (n *Notifier) enqueue(delivery *Delivery) error {
+	n.closing.RLock()
+	defer n.closing.RUnlock()
+	if n.closed {
+		return ErrClosed
+	}
+	select {
+	case n.queue <- delivery:
+		return nil
+	default:
+		return fmt.Errorf("webhook queue full")
+	}
+}
+
+// ErrClosed is the LastErr of deliveries for events handled after Close
+var ErrClosed = errors.New("webhook notifier closed")
+
+// Close waits for queued deliveries to finish. It is safe to call more than
+// once; events handled afterwards go straight to the failure queue.
+func (n *Notifier) Close() error {
+	n.closeOnce.Do(func() {
+		n.closing.Lock()
+		n.closed = true
+		close(n.queue)
+		n.closing.Unlock()
+	})
+	<-n.done
+	return nil
+}
+
+// Failures returns deliveries that exhausted their retries
+func (n *Notifier) Failures() []*Delivery {
+	n.mu.Lock()
+	defer n.mu.Unlock()
+	return append([]*Delivery{}, n.failures...)
+}
+
+// RetryFailures re-attempts every failed delivery synchronously, keeping
+// the ones that fail again in the failure queue
+func (n *Notifier) RetryFailures(ctx context.Context) {
+	n.mu.Lock()
+	failures := n.failures
+	n.failures = nil
+	n.mu.Unlock()
+
+	for _, delivery := range failures {
+		delivery.Attempts = 0
+		n.deliver(ctx, delivery)
+	}
+}
+
+// run is the background delivery worker
+func (n *Notifier) run() {
+	defer close(n.done)
+	for delivery := range n.queue {
+		n.deliver(context.Background(), delivery)
+	}
+}
+
+// deliver attempts a delivery with retries, recording it as failed if all attempts fail
+func (n *Notifier) deliver(ctx context.Context, delivery *Delivery) {
+	for delivery.Attempts < n.maxAttempts {
+		if delivery.Attempts > 0 {
+			n.sleep(n.backoff(delivery.Attempts))
+		}
+		delivery.Attempts++
+
+		delivery.LastErr = n.post(ctx, delivery)
+		if delivery.LastErr == nil {
+			return
+		}
+	}
+	n.fail(delivery)
+}
+
+// post sends a single signed request
+func (n *Notifier) post(ctx context.Context, delivery *Delivery) error {
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, delivery.Endpoint.URL, bytes.NewReader(delivery.Body))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set(HeaderEventType, delivery.Event.Type())
+	if delivery.Endpoint.Secret != "" {
+		req.Header.Set(HeaderSignature, Sign(delivery.Endpoint.Secret, delivery.Body))
+	}
+
+	resp, err := n.client.Do(req)
+	if err != nil {
+		return err
+	}
+	resp.Body.Close()
+
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return fmt.Errorf("webhook %s returned %s", delivery.Endpoint.URL, resp.Status)
+	}
+	return nil
+}
+
+// fail moves a delivery to the failure queue
+func (n *Notifier) fail(delivery *Delivery) {
+	n.mu.Lock()
+	defer n.mu.Unlock()
+	n.failures = append(n.failures, delivery)
+}
+
+// Sign computes the signature header value for a body
+func Sign(secret string, body []byte) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write(body)
+	return "sha256=" + hex.EncodeToString(mac.Sum(nil))
+}
+
+// Verify checks a signature header value against a body, for receivers
+func Verify(secret string, body []byte, signature string) bool {
+	return hmac.Equal([]byte(Sign(secret, body)), []byte(signature))
+}
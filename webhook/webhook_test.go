@@ -0,0 +1,132 @@
+package webhook_test
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/cumulusrpg/atmos"
+	"github.com/cumulusrpg/atmos/webhook"
+	"github.com/stretchr/testify/assert"
+)
+
+type TurnStartedEvent struct {
+	Player string `json:"player"`
+}
+
+func (e TurnStartedEvent) Type() string { return "turn_started" }
+
+type ChatEvent struct{}
+
+func (e ChatEvent) Type() string { return "chat" }
+
+// TestNotifierDeliversSignedEventsWithRetry verifies filtering, signing, and retries
+func TestNotifierDeliversSignedEventsWithRetry(t *testing.T) {
+	var mu sync.Mutex
+	var bodies []string
+	calls := 0
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		mu.Lock()
+		defer mu.Unlock()
+		calls++
+		if calls == 1 {
+			w.WriteHeader(http.StatusServiceUnavailable)
+			return
+		}
+		body, _ := io.ReadAll(r.Body)
+		assert.True(t, webhook.Verify("s3cret", body, r.Header.Get(webhook.HeaderSignature)))
+		assert.Equal(t, "turn_started", r.Header.Get(webhook.HeaderEventType))
+		bodies = append(bodies, string(body))
+	}))
+	defer server.Close()
+
+	notifier := webhook.NewNotifier(
+		[]webhook.Endpoint{{URL: server.URL, Secret: "s3cret", EventTypes: []string{"turn_started"}}},
+		webhook.WithBackoff(func(int) time.Duration { return 0 }),
+	)
+
+	engine := atmos.NewEngine()
+	engine.When(atmos.AnyEvent).Then(notifier)
+	engine.Emit(ChatEvent{})
+	engine.Emit(TurnStartedEvent{Player: "alice"})
+	assert.NoError(t, notifier.Close())
+
+	assert.Equal(t, 2, calls, "one failure then one success")
+	assert.Equal(t, []string{`{"type":"turn_started","data":{"player":"alice"}}`}, bodies)
+	assert.Empty(t, notifier.Failures())
+}
+
+// TestNotifierFailureQueue verifies exhausted deliveries can be retried later
+func TestNotifierFailureQueue(t *testing.T) {
+	healthy := false
+	received := 0
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if !healthy {
+			w.WriteHeader(http.StatusInternalServerError)
+			return
+		}
+		received++
+	}))
+	defer server.Close()
+
+	notifier := webhook.NewNotifier(
+		[]webhook.Endpoint{{URL: server.URL}},
+		webhook.WithMaxAttempts(2),
+		webhook.WithBackoff(func(int) time.Duration { return 0 }),
+	)
+
+	engine := atmos.NewEngine()
+	engine.When("turn_started").Then(notifier)
+	engine.Emit(TurnStartedEvent{Player: "bob"})
+	assert.NoError(t, notifier.Close())
+
+	failures := notifier.Failures()
+	assert.Len(t, failures, 1)
+	assert.Equal(t, 2, failures[0].Attempts)
+	assert.Contains(t, failures[0].LastErr.Error(), "500")
+
+	healthy = true
+	notifier.RetryFailures(context.Background())
+	assert.Empty(t, notifier.Failures())
+	assert.Equal(t, 1, received)
+}
+
+// TestNotifierSkipsReplays verifies rebuilding an engine doesn't redeliver its log
+func TestNotifierSkipsReplays(t *testing.T) {
+	var mu sync.Mutex
+	calls := 0
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		mu.Lock()
+		defer mu.Unlock()
+		calls++
+	}))
+	defer server.Close()
+
+	notifier := webhook.NewNotifier([]webhook.Endpoint{{URL: server.URL}})
+	engine := atmos.NewEngine()
+	engine.When(atmos.AnyEvent).Then(notifier)
+
+	assert.NoError(t, engine.Replay([]atmos.Event{TurnStartedEvent{Player: "alice"}}))
+	assert.NoError(t, notifier.Close())
+	assert.Equal(t, 0, calls)
+}
+
+// TestNotifierCloseTwice verifies Close can be repeated and later events fail instead of panicking
+func TestNotifierCloseTwice(t *testing.T) {
+	notifier := webhook.NewNotifier([]webhook.Endpoint{{URL: "http://127.0.0.1:0"}})
+	engine := atmos.NewEngine()
+	engine.When(atmos.AnyEvent).Then(notifier)
+
+	assert.NoError(t, notifier.Close())
+	assert.NoError(t, notifier.Close())
+
+	engine.Emit(TurnStartedEvent{Player: "alice"})
+	failures := notifier.Failures()
+	if assert.Len(t, failures, 1) {
+		assert.ErrorIs(t, failures[0].LastErr, webhook.ErrClosed)
+	}
+}